@@ -6808,10 +6808,10 @@ def go_deps():
         name = "com_github_nats_io_nats_go",
         build_file_proto_mode = "disable_global",
         importpath = "github.com/nats-io/nats.go",
-        sha256 = "34a735d158d70685faad1fc3153f08da0ddc21c0ae42f6a0cb09430d638364b2",
-        strip_prefix = "github.com/nats-io/nats.go@v1.9.1",
+        sha256 = "e696cd1b49ef8402e5df41044dbe9ef6facba2110c1fe13e6f22741ad754b8a1",
+        strip_prefix = "github.com/nats-io/nats.go@v1.39.1",
         urls = [
-            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/nats-io/nats.go/com_github_nats_io_nats_go-v1.9.1.zip",
+            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/nats-io/nats.go/com_github_nats_io_nats_go-v1.39.1.zip",
         ],
     )
     go_repository(
@@ -6828,10 +6828,10 @@ def go_deps():
         name = "com_github_nats_io_nkeys",
         build_file_proto_mode = "disable_global",
         importpath = "github.com/nats-io/nkeys",
-        sha256 = "291930a7abcd84edcaffc2cadc75aeb830ebf561144313ead6b8e1fcc03b124f",
-        strip_prefix = "github.com/nats-io/nkeys@v0.1.3",
+        sha256 = "d384190f06e7eda2802d8441f53e36f85e082529bd8bebc7cbc3a6a5d86d29f9",
+        strip_prefix = "github.com/nats-io/nkeys@v0.4.9",
         urls = [
-            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/nats-io/nkeys/com_github_nats_io_nkeys-v0.1.3.zip",
+            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/nats-io/nkeys/com_github_nats_io_nkeys-v0.4.9.zip",
         ],
     )
     go_repository(
@@ -7653,6 +7653,16 @@ def go_deps():
             "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/puzpuzpuz/xsync/v3/com_github_puzpuzpuz_xsync_v3-v3.5.1.zip",
         ],
     )
+    go_repository(
+        name = "com_github_rabbitmq_amqp091_go",
+        build_file_proto_mode = "disable_global",
+        importpath = "github.com/rabbitmq/amqp091-go",
+        sha256 = "5caf2378d1d4a1e4109da5c5e67bcd368127c03bad018cce6756743b52ddd884",
+        strip_prefix = "github.com/rabbitmq/amqp091-go@v1.10.0",
+        urls = [
+            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/rabbitmq/amqp091-go/com_github_rabbitmq_amqp091_go-v1.10.0.zip",
+        ],
+    )
     go_repository(
         name = "com_github_raduberinde_axisds_v2",
         build_file_proto_mode = "disable_global",
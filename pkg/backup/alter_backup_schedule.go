@@ -361,6 +361,8 @@ func processOptions(spec *alterBackupScheduleSpec, s scheduleDetails) error {
 	if err := processOptionsForArgs(opts, incOpts); err != nil {
 		return err
 	}
+	// Deduplication only applies to full backups.
+	incOpts.Deduplicate = nil
 	return nil
 }
 
@@ -402,6 +404,9 @@ func processOptionsForArgs(inOpts tree.BackupOptions, outOpts *tree.BackupOption
 	if inOpts.UpdatesClusterMonitoringMetrics != nil {
 		outOpts.UpdatesClusterMonitoringMetrics = inOpts.UpdatesClusterMonitoringMetrics
 	}
+	if inOpts.Deduplicate != nil {
+		outOpts.Deduplicate = inOpts.Deduplicate
+	}
 	return nil
 }
 
@@ -0,0 +1,661 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/backup/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupinfo"
+	"github.com/cockroachdb/cockroach/pkg/backup/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/backup/backuputils"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/unique"
+	"github.com/cockroachdb/errors"
+)
+
+// A deduplicated full backup writes its data files to a directory shared by
+// the whole collection instead of its own data directory, and before exporting
+// anything it looks for files of the most recent earlier deduplicated full
+// backup in the collection whose spans have not changed since. Such files are
+// referenced from the new manifest by a path relative to the new backup's
+// directory rather than being exported again. Files in the shared directory
+// are deleted once no backup in the collection references them.
+
+var sharedDataGCGracePeriod = settings.RegisterDurationSetting(
+	settings.ApplicationLevel,
+	"bulkio.backup.deduplication.gc_grace_period",
+	"the minimum age of an unreferenced file in the shared data directory of a "+
+		"backup collection before it is deleted",
+	24*time.Hour,
+	settings.NonNegativeDuration,
+)
+
+// reuseCheckConcurrency is the number of files of an earlier backup that are
+// checked for changes concurrently.
+const reuseCheckConcurrency = 32
+
+// sharedDataGCConcurrency is the number of backups whose references to files
+// in the shared data directory are read concurrently.
+const sharedDataGCConcurrency = 16
+
+// sharedDataRefsFileName is the name of the file in the directory of a
+// completed deduplicated full backup that lists the files in the shared data
+// directory its manifest references, one per line. Garbage collection reads it
+// instead of the backup's whole manifest.
+const sharedDataRefsFileName = "BACKUP-SHARED-DATA-REFS"
+
+// inProgressBackupRE matches the checkpoint manifests of full backups in a
+// collection, capturing the backup's directory.
+var inProgressBackupRE = regexp.MustCompile(
+	`^/?([^/]+/[^/]+/[^/]+)/` + backupinfo.BackupProgressDirectory + `/` +
+		backupinfo.BackupManifestCheckpointName)
+
+// backupLockRE matches the lock files of full backups in a collection,
+// capturing the backup's directory and the ID of the job that wrote it.
+var backupLockRE = regexp.MustCompile(
+	`^/?([^/]+/[^/]+/[^/]+)/` + backupinfo.BackupLockFilePrefix + `([0-9]+)$`)
+
+// sharedDataDir returns the directory, relative to the backup at subdir, to
+// which a deduplicated backup writes its data files.
+func sharedDataDir(subdir string) string {
+	return backupRelativePath(subdir, backupbase.SharedDataDirectory)
+}
+
+// backupRelativePath converts a path relative to the root of a collection to
+// one relative to the backup at subdir in that collection.
+func backupRelativePath(subdir string, collectionPath string) string {
+	var depth int
+	for _, part := range strings.Split(path.Clean("/"+subdir), "/") {
+		if part != "" {
+			depth++
+		}
+	}
+	return path.Join(strings.Repeat("../", depth), collectionPath)
+}
+
+// collectionRelativePath converts the path of a file of the backup at subdir
+// to one relative to the root of the collection.
+func collectionRelativePath(subdir string, file string) string {
+	return strings.TrimPrefix(path.Join("/", subdir, file), "/")
+}
+
+// isSharedDataFile returns whether the path, relative to the root of the
+// collection, is in the shared data directory.
+func isSharedDataFile(collectionPath string) bool {
+	return strings.HasPrefix(collectionPath, backupbase.SharedDataDirectory+"/")
+}
+
+// forEachBackupFile calls fn on each file of a backup manifest.
+func forEachBackupFile(
+	ctx context.Context,
+	iterFactory *backupinfo.IterFactory,
+	fn func(*backuppb.BackupManifest_File) error,
+) error {
+	it, err := iterFactory.NewFileIter(ctx)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for ; ; it.Next() {
+		if ok, err := it.Valid(); err != nil {
+			return err
+		} else if !ok {
+			return nil
+		}
+		if err := fn(it.Value()); err != nil {
+			return err
+		}
+	}
+}
+
+// writeSharedDataRefs writes the list of the files in the shared data
+// directory referenced by the manifest of the deduplicated backup at subdir to
+// the backup's directory.
+func writeSharedDataRefs(
+	ctx context.Context,
+	store cloud.ExternalStorage,
+	subdir string,
+	backupManifest *backuppb.BackupManifest,
+) error {
+	seen := make(map[string]struct{})
+	var buf bytes.Buffer
+	for i := range backupManifest.Files {
+		p := collectionRelativePath(subdir, backupManifest.Files[i].Path)
+		if !isSharedDataFile(p) {
+			continue
+		}
+		if _, ok := seen[p]; ok {
+			continue
+		}
+		seen[p] = struct{}{}
+		buf.WriteString(strings.TrimPrefix(p, backupbase.SharedDataDirectory+"/"))
+		buf.WriteByte('\n')
+	}
+	return cloud.WriteFile(ctx, store, sharedDataRefsFileName, &buf)
+}
+
+// isEncryptedBackup returns whether the backup in the store is encrypted.
+func isEncryptedBackup(ctx context.Context, store cloud.ExternalStorage) bool {
+	files, err := backupencryption.GetEncryptionInfoFiles(ctx, store)
+	return err == nil && len(files) > 0
+}
+
+// findPriorDeduplicatedBackup returns the subdirectory of the most recent
+// deduplicated full backup in the collection that completed before the backup
+// described by the manifest, or an empty string if it is not compatible with
+// that backup or there is none.
+func findPriorDeduplicatedBackup(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user username.SQLUsername,
+	mem *mon.BoundAccount,
+	details jobspb.BackupDetails,
+	backupManifest *backuppb.BackupManifest,
+	kmsEnv cloud.KMSEnv,
+) (string, error) {
+	collection, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, details.CollectionURI, user)
+	if err != nil {
+		return "", err
+	}
+	defer collection.Close()
+
+	// There is nothing to reuse if no deduplicated backup has written any files
+	// to the collection yet.
+	var hasSharedFiles bool
+	if err := collection.List(ctx, backupbase.SharedDataDirectory+"/", cloud.ListOptions{},
+		func(string) error {
+			hasSharedFiles = true
+			return cloud.ErrListingDone
+		},
+	); err != nil && !errors.Is(err, cloud.ErrListingDone) {
+		return "", err
+	}
+	if !hasSharedFiles {
+		return "", nil
+	}
+
+	fulls, err := backupdest.ListFullBackupsInCollection(ctx, collection)
+	if err != nil {
+		return "", err
+	}
+	// Full backups are named by their end time, so the most recent sort last.
+	sort.Sort(sort.Reverse(sort.StringSlice(fulls)))
+	own := path.Clean("/" + details.Destination.Subdir)
+	for _, subdir := range fulls {
+		if path.Clean("/"+subdir) == own {
+			continue
+		}
+		uri, err := backuputils.AppendPath(details.CollectionURI, subdir)
+		if err != nil {
+			return "", err
+		}
+		m, ok, err := func() (backuppb.BackupManifest, bool, error) {
+			store, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, uri, user)
+			if err != nil {
+				return backuppb.BackupManifest{}, false, err
+			}
+			defer store.Close()
+			if isEncryptedBackup(ctx, store) {
+				return backuppb.BackupManifest{}, false, nil
+			}
+			m, memSize, err := backupinfo.ReadBackupManifestFromStore(ctx, mem, store, uri, nil, kmsEnv)
+			if err != nil {
+				return backuppb.BackupManifest{}, false, err
+			}
+			mem.Shrink(ctx, memSize)
+			return m, m.Deduplicated, nil
+		}()
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			continue
+		}
+		if !m.EndTime.Less(backupManifest.EndTime) ||
+			m.ClusterID != backupManifest.ClusterID ||
+			m.MVCCFilter != backupManifest.MVCCFilter ||
+			m.ElidedPrefix != backupManifest.ElidedPrefix {
+			return "", nil
+		}
+		return subdir, nil
+	}
+	return "", nil
+}
+
+// reuseUnchangedFiles adds the files of the most recent earlier deduplicated
+// full backup in the collection that are still current as of the end time of
+// the backup to its manifest, and returns the spans they cover. Spans in
+// completedSpans are not considered.
+//
+// A file is reused only if the earlier backup's chain of incremental backups
+// did not touch its span and an incremental export of the span since the end
+// of that chain finds no changes. Since a data file may hold several spans,
+// either all or none of the spans stored in a file are reused.
+func reuseUnchangedFiles(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user username.SQLUsername,
+	details jobspb.BackupDetails,
+	backupManifest *backuppb.BackupManifest,
+	completedSpans []roachpb.Span,
+	kmsEnv cloud.KMSEnv,
+) ([]roachpb.Span, error) {
+	mem := execCfg.RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+
+	prior, err := findPriorDeduplicatedBackup(ctx, execCfg, user, &mem, details, backupManifest, kmsEnv)
+	if err != nil || prior == "" {
+		return nil, err
+	}
+
+	collectionURIs := []string{details.CollectionURI}
+	baseDirs, err := backuputils.AppendPaths(collectionURIs, prior)
+	if err != nil {
+		return nil, err
+	}
+	incDirs, err := backupdest.ResolveIncrementalsBackupLocation(collectionURIs, prior)
+	if err != nil {
+		return nil, err
+	}
+	_, manifests, _, _, err := backupdest.ResolveBackupManifests(
+		ctx, execCfg, &mem, details.CollectionURI, collectionURIs,
+		execCfg.DistSQLSrv.ExternalStorageFromURI, prior, baseDirs, incDirs,
+		hlc.Timestamp{}, nil /* encryption */, kmsEnv, user,
+		false /* includeSkipped */, false, /* includeCompacted */
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the layers that form a continuous chain ending no later than this
+	// backup are of use.
+	chain := manifests[:1]
+	for _, m := range manifests[1:] {
+		if !m.StartTime.Equal(chain[len(chain)-1].EndTime) || backupManifest.EndTime.Less(m.EndTime) {
+			break
+		}
+		chain = append(chain, m)
+	}
+	last := chain[len(chain)-1]
+
+	iterFactories, err := backupinfo.GetBackupManifestIterFactories(
+		ctx, execCfg.DistSQLSrv.ExternalStorage, chain, nil /* encryption */, kmsEnv,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed roachpb.SpanGroup
+	for layer := 1; layer < len(chain); layer++ {
+		changed.Add(chain[layer].IntroducedSpans...)
+		if err := forEachBackupFile(ctx, iterFactories[layer], func(f *backuppb.BackupManifest_File) error {
+			changed.Add(f.Span)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// A span is eligible for reuse if both backups cover it and the chain did
+	// not change it.
+	var eligible roachpb.SpanGroup
+	eligible.Add(backupManifest.Spans...)
+	eligible.Sub(filterSpans(backupManifest.Spans, last.Spans)...)
+	eligible.Sub(changed.Slice()...)
+	eligible.Sub(completedSpans...)
+
+	filesByPath := make(map[string][]backuppb.BackupManifest_File)
+	var paths []string
+	if err := forEachBackupFile(ctx, iterFactories[0], func(f *backuppb.BackupManifest_File) error {
+		p := collectionRelativePath(prior, f.Path)
+		if !isSharedDataFile(p) {
+			return nil
+		}
+		if _, ok := filesByPath[p]; !ok {
+			paths = append(paths, p)
+		}
+		filesByPath[p] = append(filesByPath[p], *f)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	var candidates []string
+	for _, p := range paths {
+		ok := true
+		for _, f := range filesByPath[p] {
+			if !eligible.Encloses(f.Span) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			candidates = append(candidates, p)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	unchanged := make([]bool, len(candidates))
+	todo := make(chan int, len(candidates))
+	for i := range candidates {
+		todo <- i
+	}
+	close(todo)
+	g := ctxgroup.WithContext(ctx)
+	for w := 0; w < min(reuseCheckConcurrency, len(candidates)); w++ {
+		g.GoCtx(func(ctx context.Context) error {
+			for i := range todo {
+				unchanged[i] = true
+				for _, f := range filesByPath[candidates[i]] {
+					ok, err := spanUnchangedSince(ctx, execCfg.DB, f.Span, last.EndTime, backupManifest.EndTime)
+					if err != nil {
+						return err
+					}
+					if !ok {
+						unchanged[i] = false
+						break
+					}
+				}
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var reused []roachpb.Span
+	for i, p := range candidates {
+		if !unchanged[i] {
+			continue
+		}
+		for _, f := range filesByPath[p] {
+			f.Path = backupRelativePath(details.Destination.Subdir, p)
+			f.Reused = true
+			backupManifest.Files = append(backupManifest.Files, f)
+			backupManifest.EntryCounts.Add(f.EntryCounts)
+			reused = append(reused, f.Span)
+		}
+	}
+	log.Dev.Infof(ctx, "reusing %d of %d data files of backup %s", len(reused),
+		len(filesByPath), prior)
+	return reused, nil
+}
+
+// spanUnchangedSince returns whether no key in the span was written or deleted
+// in the time interval (start, end].
+func spanUnchangedSince(
+	ctx context.Context, db *kv.DB, span roachpb.Span, start, end hlc.Timestamp,
+) (bool, error) {
+	for len(span.Key) != 0 {
+		req := &kvpb.ExportRequest{
+			RequestHeader: kvpb.RequestHeaderFromSpan(span),
+			StartTime:     start,
+			MVCCFilter:    kvpb.MVCCFilter_Latest,
+		}
+		// Any exported data means the span changed, so paginate after the first
+		// file and don't wait on intents.
+		header := kvpb.Header{
+			TargetBytes:                 1,
+			Timestamp:                   end,
+			ReturnElasticCPUResumeSpans: true,
+			WaitPolicy:                  lock.WaitPolicy_Error,
+		}
+		admissionHeader := kvpb.AdmissionHeader{
+			Priority:                 int32(admissionpb.BulkNormalPri),
+			CreateTime:               timeutil.Now().UnixNano(),
+			Source:                   kvpb.AdmissionHeader_FROM_SQL,
+			NoMemoryReservedAtSource: true,
+		}
+		rawResp, pErr := kv.SendWrappedWithAdmission(
+			ctx, db.NonTransactionalSender(), header, admissionHeader, req)
+		if pErr != nil {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
+			// An intent or a start time below the GC threshold only mean that the
+			// span cannot be shown to be unchanged; it is exported again instead.
+			log.VEventf(ctx, 1, "unable to check span %s for changes: %v", span, pErr)
+			return false, nil
+		}
+		resp := rawResp.(*kvpb.ExportResponse)
+		if len(resp.Files) > 0 {
+			return false, nil
+		}
+		if resp.ResumeSpan == nil {
+			break
+		}
+		span = *resp.ResumeSpan
+	}
+	return true, nil
+}
+
+// collectSharedDataGarbage deletes the files in the shared data directory of
+// the collection that are not referenced by any backup in it, whether
+// completed or in progress, and are older than the grace period.
+//
+// Completed backups are accounted for by the list of references they write
+// next to their manifest, and running ones by their latest checkpoint, which
+// records the files they reuse before they export anything. A backup that
+// started within the grace period but has not written a checkpoint yet may
+// have picked files to reuse without recording them, so nothing is deleted
+// until it does; the next deduplicated backup in the collection collects the
+// garbage instead.
+func collectSharedDataGarbage(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user username.SQLUsername,
+	collectionURI string,
+	kmsEnv cloud.KMSEnv,
+) error {
+	collection, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, collectionURI, user)
+	if err != nil {
+		return err
+	}
+	defer collection.Close()
+
+	fulls, err := backupdest.ListFullBackupsInCollection(ctx, collection)
+	if err != nil {
+		return err
+	}
+	completed := make(map[string]bool, len(fulls))
+	for _, subdir := range fulls {
+		completed[strings.TrimPrefix(subdir, "/")] = true
+	}
+	inProgress := make(map[string]bool)
+	locked := make(map[string]int64)
+	if err := collection.List(ctx, "", cloud.ListOptions{Delimiter: backupbase.ListingDelimDataSlash},
+		func(p string) error {
+			if m := inProgressBackupRE.FindStringSubmatch(p); m != nil && !completed[m[1]] {
+				inProgress[m[1]] = true
+			} else if m := backupLockRE.FindStringSubmatch(p); m != nil && !completed[m[1]] {
+				if jobID, err := strconv.ParseInt(m[2], 10, 64); err == nil {
+					locked[m[1]] = jobID
+				}
+			}
+			return nil
+		},
+	); err != nil {
+		return err
+	}
+
+	gracePeriod := sharedDataGCGracePeriod.Get(&execCfg.Settings.SV)
+	for subdir, jobID := range locked {
+		if inProgress[subdir] {
+			continue
+		}
+		if timeutil.Since(unique.UniqueIntTime(jobID)) < gracePeriod {
+			log.Dev.Infof(ctx, "not deleting unreferenced backup files: backup %s has not checkpointed yet", subdir)
+			return nil
+		}
+	}
+
+	mem := execCfg.RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+
+	var mu struct {
+		syncutil.Mutex
+		referenced map[string]struct{}
+	}
+	mu.referenced = make(map[string]struct{})
+	addReference := func(ctx context.Context, p string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if _, ok := mu.referenced[p]; ok {
+			return nil
+		}
+		if err := mem.Grow(ctx, int64(len(p))); err != nil {
+			return err
+		}
+		mu.referenced[p] = struct{}{}
+		return nil
+	}
+
+	readRefs := func(ctx context.Context, store cloud.ExternalStorage) error {
+		r, _, err := store.ReadFile(ctx, sharedDataRefsFileName, cloud.ReadOptions{NoFileSize: true})
+		if err != nil {
+			// Only deduplicated backups write the file.
+			if errors.Is(err, cloud.ErrFileDoesNotExist) {
+				return nil
+			}
+			return err
+		}
+		defer r.Close(ctx)
+		buf, err := ioctx.ReadAll(ctx, r)
+		if err != nil {
+			return err
+		}
+		for _, name := range strings.Split(string(buf), "\n") {
+			if name == "" {
+				continue
+			}
+			if err := addReference(ctx, path.Join(backupbase.SharedDataDirectory, name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	readCheckpoint := func(ctx context.Context, store cloud.ExternalStorage, subdir string) error {
+		// Encrypted backups cannot be deduplicated.
+		if isEncryptedBackup(ctx, store) {
+			return nil
+		}
+		checkpointMem := execCfg.RootMemoryMonitor.MakeBoundAccount()
+		defer checkpointMem.Close(ctx)
+		m, _, err := backupinfo.ReadBackupCheckpointManifest(ctx, &checkpointMem, store, nil, kmsEnv)
+		if err != nil {
+			return err
+		}
+		if !m.Deduplicated {
+			return nil
+		}
+		return forEachBackupFile(ctx, backupinfo.NewIterFactory(&m, store, nil, kmsEnv),
+			func(f *backuppb.BackupManifest_File) error {
+				if p := collectionRelativePath(subdir, f.Path); isSharedDataFile(p) {
+					return addReference(ctx, p)
+				}
+				return nil
+			})
+	}
+
+	type backupRefs struct {
+		subdir     string
+		checkpoint bool
+	}
+	todo := make(chan backupRefs, len(completed)+len(inProgress))
+	for subdir := range completed {
+		todo <- backupRefs{subdir: subdir}
+	}
+	for subdir := range inProgress {
+		todo <- backupRefs{subdir: subdir, checkpoint: true}
+	}
+	close(todo)
+	g := ctxgroup.WithContext(ctx)
+	for w := 0; w < min(sharedDataGCConcurrency, len(todo)); w++ {
+		g.GoCtx(func(ctx context.Context) error {
+			for b := range todo {
+				if err := func() error {
+					uri, err := backuputils.AppendPath(collectionURI, b.subdir)
+					if err != nil {
+						return err
+					}
+					store, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, uri, user)
+					if err != nil {
+						return err
+					}
+					defer store.Close()
+					if b.checkpoint {
+						return readCheckpoint(ctx, store, b.subdir)
+					}
+					return readRefs(ctx, store)
+				}(); err != nil {
+					return errors.Wrapf(err, "reading files referenced by backup %s", b.subdir)
+				}
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	var garbage []string
+	if err := collection.List(ctx, backupbase.SharedDataDirectory+"/", cloud.ListOptions{},
+		func(name string) error {
+			name = strings.TrimPrefix(name, "/")
+			id, err := strconv.ParseInt(strings.TrimSuffix(name, ".sst"), 10, 64)
+			if err != nil || !strings.HasSuffix(name, ".sst") {
+				return nil
+			}
+			p := path.Join(backupbase.SharedDataDirectory, name)
+			if _, ok := mu.referenced[p]; ok {
+				return nil
+			}
+			if timeutil.Since(unique.UniqueIntTime(id)) < gracePeriod {
+				return nil
+			}
+			garbage = append(garbage, p)
+			return nil
+		},
+	); err != nil {
+		return err
+	}
+	for _, p := range garbage {
+		if err := collection.Delete(ctx, p); err != nil {
+			return err
+		}
+	}
+	log.Dev.Infof(ctx, "deleted %d unreferenced files from shared data directory of backup collection",
+		len(garbage))
+	return nil
+}
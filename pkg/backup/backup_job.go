@@ -288,6 +288,29 @@ func backup(
 	spans := filterSpans(backupManifest.Spans, completedSpans)
 	introducedSpans := filterSpans(backupManifest.IntroducedSpans, completedIntroducedSpans)
 
+	// A deduplicated backup writes its files to the collection's shared data
+	// directory, and need not export spans whose files in an earlier backup in
+	// the collection are still current.
+	var dataDir string
+	if backupManifest.Deduplicated {
+		dataDir = sharedDataDir(details.Destination.Subdir)
+		reusedSpans, err := reuseUnchangedFiles(
+			ctx, execCtx.ExecCfg(), execCtx.User(), details, backupManifest, completedSpans, &kmsEnv,
+		)
+		if err != nil {
+			// Everything is exported again instead.
+			log.Dev.Warningf(ctx, "unable to reuse files of an earlier backup: %+v", err)
+		}
+		if len(reusedSpans) > 0 {
+			spans = filterSpans(spans, reusedSpans)
+			if err := backupinfo.WriteBackupManifestCheckpoint(
+				ctx, details.URI, encryption, &kmsEnv, backupManifest, execCtx.ExecCfg(), execCtx.User(),
+			); err != nil {
+				return roachpb.RowCount{}, nil, 0, err
+			}
+		}
+	}
+
 	pkIDs := make(map[uint64]bool)
 	for i := range backupManifest.Descriptors {
 		if t, _, _, _, _ := descpb.GetDescriptors(&backupManifest.Descriptors[i]); t != nil {
@@ -353,6 +376,7 @@ func backup(
 		backupManifest.StartTime,
 		backupManifest.EndTime,
 		backupManifest.ElidedPrefix,
+		dataDir,
 	)
 	if err != nil {
 		return roachpb.RowCount{}, nil, 0, err
@@ -542,6 +566,18 @@ func backup(
 	}
 
 	statsTable := getTableStatsForBackup(ctx, execCtx.ExecCfg().InternalDB.Executor(), execCtx.ExecCfg().Settings, backupManifest.Descriptors)
+	if recordFingerprintsEnabled.Get(&execCtx.ExecCfg().Settings.SV) {
+		backupManifest.TableFingerprints = getTableFingerprintsForBackup(
+			ctx, execCtx.ExecCfg().InternalDB.Executor(), backupManifest.Descriptors, backupManifest.EndTime,
+		)
+	}
+	// The list of shared files a deduplicated backup references must be in
+	// place by the time the backup counts as completed, i.e. has a manifest.
+	if backupManifest.Deduplicated {
+		if err := writeSharedDataRefs(ctx, defaultStore, details.Destination.Subdir, backupManifest); err != nil {
+			return roachpb.RowCount{}, trackedProgress, numBackupInstances, err
+		}
+	}
 	if err := backupinfo.WriteBackupMetadata(ctx, execCtx, defaultStore, details, &kmsEnv, backupManifest, statsTable); err != nil {
 		return roachpb.RowCount{}, trackedProgress, numBackupInstances, err
 	}
@@ -856,6 +892,16 @@ func (b *backupResumer) Resume(ctx context.Context, execCtx interface{}) error {
 		}
 	}
 
+	// Now that this backup is recorded in the collection, files in its shared
+	// data directory that no backup references any longer can be deleted.
+	if backupManifest.Deduplicated && details.CollectionURI != "" {
+		if err := collectSharedDataGarbage(
+			ctx, p.ExecCfg(), p.User(), details.CollectionURI, &kmsEnv,
+		); err != nil {
+			log.Dev.Warningf(ctx, "failed to delete unreferenced backup files: %+v", err)
+		}
+	}
+
 	b.backupStats = res
 
 	// Collect telemetry.
@@ -1635,6 +1681,7 @@ func createBackupManifest(
 		StatisticsFilenames: statsFiles,
 		DescriptorCoverage:  coverage,
 		ElidedPrefix:        elide,
+		Deduplicated:        jobDetails.Deduplicate && startTime.IsEmpty(),
 	}
 	if err := checkCoverage(ctx, backupManifest.Spans, append(prevBackups, backupManifest)); err != nil {
 		return backuppb.BackupManifest{}, errors.Wrap(err, "new backup would not cover expected time")
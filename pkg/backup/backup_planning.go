@@ -74,6 +74,7 @@ func resolveOptionsForBackupJobDescription(
 		Detached:                        opts.Detached,
 		ExecutionLocality:               opts.ExecutionLocality,
 		UpdatesClusterMonitoringMetrics: opts.UpdatesClusterMonitoringMetrics,
+		Deduplicate:                     opts.Deduplicate,
 		Strict:                          opts.Strict,
 	}
 
@@ -374,6 +375,7 @@ func backupTypeCheck(
 			backupStmt.Options.CaptureRevisionHistory,
 			backupStmt.Options.IncludeAllSecondaryTenants,
 			backupStmt.Options.UpdatesClusterMonitoringMetrics,
+			backupStmt.Options.Deduplicate,
 		}); err != nil {
 		return false, nil, err
 	}
@@ -499,6 +501,32 @@ func backupPlanHook(
 		}
 	}
 
+	var deduplicate bool
+	if backupStmt.Options.Deduplicate != nil {
+		deduplicate, err = exprEval.Bool(ctx, backupStmt.Options.Deduplicate)
+		if err != nil {
+			return nil, nil, false, err
+		}
+	}
+	if deduplicate {
+		// Files in the shared data directory are referenced by several backups,
+		// each of which would otherwise be able to pick its own encryption key.
+		if encryptionParams.Mode != jobspb.EncryptionMode_None {
+			return nil, nil, false,
+				errors.New("the deduplicate option is not supported with encryption_passphrase or kms")
+		}
+		if len(to) > 1 {
+			return nil, nil, false,
+				errors.New("the deduplicate option is not supported with locality-aware backups")
+		}
+		// Files exported with all revisions may end in the middle of a key's
+		// history, so they cannot be reused independently of their neighbours.
+		if revisionHistory {
+			return nil, nil, false,
+				errors.New("the deduplicate option is not supported with revision_history")
+		}
+	}
+
 	fn := func(ctx context.Context, resultsCh chan<- tree.Datums) error {
 		// TODO(dan): Move this span into sql.
 		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
@@ -588,6 +616,7 @@ func backupPlanHook(
 			ExecutionLocality:               executionLocality,
 			UpdatesClusterMonitoringMetrics: updatesClusterMonitoringMetrics,
 			StrictLocalityFiltering:         backupStmt.Options.Strict,
+			Deduplicate:                     deduplicate,
 		}
 		if backupStmt.CreatedByInfo != nil {
 			initialDetails.ScheduleID = backupStmt.CreatedByInfo.ScheduleID()
@@ -376,6 +376,7 @@ func runBackupProcessor(
 		ProgCh:    progCh,
 		Settings:  &flowCtx.Cfg.Settings.SV,
 		ElideMode: spec.ElidePrefix,
+		DataDir:   spec.DataDir,
 	}
 
 	storage, err := flowCtx.Cfg.ExternalStorage(ctx, dest, cloud.WithClientName("backup"))
@@ -44,6 +44,7 @@ func distBackupPlanSpecs(
 	mvccFilter kvpb.MVCCFilter,
 	startTime, endTime hlc.Timestamp,
 	elide execinfrapb.ElidePrefix,
+	dataDir string,
 ) (map[base.SQLInstanceID]*execinfrapb.BackupDataSpec, error) {
 	var span *tracing.Span
 	ctx, span = tracing.ChildSpan(ctx, "backup.distBackupPlanSpecs")
@@ -109,6 +110,7 @@ func distBackupPlanSpecs(
 			ElidePrefix:            elide,
 			IncludeMVCCValueHeader: true,
 			StrictLocality:         strictLocalityFiltering,
+			DataDir:                dataDir,
 		}
 		sqlInstanceIDToSpec[partition.SQLInstanceID] = spec
 	}
@@ -133,6 +135,7 @@ func distBackupPlanSpecs(
 				UserProto:              user.EncodeProto(),
 				IncludeMVCCValueHeader: true,
 				StrictLocality:         strictLocalityFiltering,
+				DataDir:                dataDir,
 			}
 			sqlInstanceIDToSpec[partition.SQLInstanceID] = spec
 		}
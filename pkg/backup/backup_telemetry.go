@@ -59,6 +59,8 @@ const (
 	telemetryOptionSkipLocalitiesCheck       = "skip_localities_check"
 	telemetryOptionSchemaOnly                = "schema_only"
 	telemetryOptionSkipMissingUDFs           = "skip_missing_udfs"
+	telemetryOptionRowFilter                 = "row_filter"
+	telemetryOptionColumns                   = "columns"
 )
 
 // logBackupTelemetry publishes an eventpb.RecoveryEvent about a manually
@@ -363,6 +365,12 @@ func logRestoreTelemetry(
 	if opts.SchemaOnly {
 		options = append(options, telemetryOptionSchemaOnly)
 	}
+	if opts.RowFilter != nil {
+		options = append(options, telemetryOptionRowFilter)
+	}
+	if opts.Columns != nil {
+		options = append(options, telemetryOptionColumns)
+	}
 	sort.Strings(options)
 
 	event := &eventpb.RecoveryEvent{
@@ -63,6 +63,13 @@ const (
 	// into a single result that can be skipped over quickly.
 	ListingDelimDataSlash = "data/"
 
+	// SharedDataDirectory is the path from the root of the backup collection to
+	// the directory containing the data files of deduplicated full backups,
+	// which may be referenced by more than one backup in the collection. Like
+	// each backup's own data directory it ends in "data", so that listings
+	// using ListingDelimDataSlash skip over its contents.
+	SharedDataDirectory = "shared/data"
+
 	// BackupIndexDirectoryName is the path from the root of the backup collection
 	// to the directory containing the index files for the backup collection.
 	BackupIndexDirectoryPath = backupMetadataDirectory + "/index/"
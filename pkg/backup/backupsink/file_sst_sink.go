@@ -49,6 +49,9 @@ type SSTSinkConf struct {
 	ID        base.SQLInstanceID
 	Settings  *settings.Values
 	ElideMode execinfrapb.ElidePrefix
+	// DataDir, if set, overrides the directory, relative to the destination,
+	// to which data files are written.
+	DataDir string
 }
 
 type FileSSTSink struct {
@@ -315,7 +318,7 @@ func (s *FileSSTSink) Flush(ctx context.Context) error {
 }
 
 func (s *FileSSTSink) open(ctx context.Context) error {
-	s.outName = generateUniqueSSTName(s.conf.ID)
+	s.outName = generateUniqueSSTName(s.conf.DataDir, s.conf.ID)
 	if s.ctx == nil {
 		s.ctx, s.cancel = context.WithCancel(ctx)
 	}
@@ -77,10 +77,13 @@ func adjustFileEndKey(endKey, maxPointKey, maxRangeEnd roachpb.Key) (roachpb.Key
 
 }
 
-func generateUniqueSSTName(nodeID base.SQLInstanceID) string {
+func generateUniqueSSTName(dataDir string, nodeID base.SQLInstanceID) string {
 	// The data/ prefix, including a /, is intended to group SSTs in most of the
 	// common file/bucket browse UIs.
-	return fmt.Sprintf("data/%d.sst",
+	if dataDir == "" {
+		dataDir = "data"
+	}
+	return fmt.Sprintf("%s/%d.sst", dataDir,
 		unique.GenerateUniqueInt(unique.ProcessUniqueID(nodeID)))
 }
 
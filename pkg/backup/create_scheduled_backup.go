@@ -86,6 +86,7 @@ type scheduledBackupSpec struct {
 	includeAllSecondaryTenants *bool
 	execLoc                    *string
 	updatesMetrics             *bool
+	deduplicate                *bool
 }
 
 // TODO(msbutler): move this function into scheduleBase and remove duplicate function in scheduled changefeeds.
@@ -263,6 +264,14 @@ func doCreateBackupSchedules(
 
 	backupNode.Targets = eval.Targets
 
+	// Deduplication only applies to full backups; it is cleared from the
+	// incremental backup statement below.
+	var deduplicate tree.Expr
+	if eval.deduplicate != nil {
+		deduplicate = tree.MakeDBool(tree.DBool(*eval.deduplicate))
+		backupNode.Options.Deduplicate = deduplicate
+	}
+
 	// Run full backup in dry-run mode.  This will do all of the sanity checks
 	// and validation we need to make in order to ensure the schedule is sane.
 	backupEvent, err := dryRunBackup(ctx, p, backupNode)
@@ -338,6 +347,7 @@ func doCreateBackupSchedules(
 		incrementalScheduleDetails.Wait = jobspb.ScheduleDetails_WAIT
 		chainProtectedTimestampRecords = scheduledBackupGCProtectionEnabled.Get(&p.ExecCfg().Settings.SV)
 		backupNode.AppendToLatest = true
+		backupNode.Options.Deduplicate = nil
 
 		// Revision history only applies to incremental backups, not full backups.
 		if eval.captureRevisionHistory != nil {
@@ -367,6 +377,7 @@ func doCreateBackupSchedules(
 	// applies to incremental backups.
 	backupNode.AppendToLatest = false
 	backupNode.Options.CaptureRevisionHistory = nil
+	backupNode.Options.Deduplicate = deduplicate
 	var fullScheduledBackupArgs *backuppb.ScheduledBackupExecutionArgs
 	full, fullScheduledBackupArgs, err := makeBackupSchedule(
 		env, p.User(), scheduleLabel, fullRecurrence, details, unpauseOnSuccessID,
@@ -702,6 +713,14 @@ func makeScheduledBackupSpec(
 		spec.updatesMetrics = &updatesMetrics
 	}
 
+	if schedule.BackupOptions.Deduplicate != nil {
+		deduplicate, err := exprEval.Bool(ctx, schedule.BackupOptions.Deduplicate)
+		if err != nil {
+			return nil, err
+		}
+		spec.deduplicate = &deduplicate
+	}
+
 	return spec, nil
 }
 
@@ -783,6 +802,7 @@ func createBackupScheduleTypeCheck(
 		schedule.BackupOptions.CaptureRevisionHistory,
 		schedule.BackupOptions.IncludeAllSecondaryTenants,
 		schedule.BackupOptions.UpdatesClusterMonitoringMetrics,
+		schedule.BackupOptions.Deduplicate,
 	}
 	if err := exprutil.TypeCheck(
 		ctx, scheduleBackupOp, p.SemaCtx(), stringExprs, bools, stringArrays, opts,
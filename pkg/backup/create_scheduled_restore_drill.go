@@ -0,0 +1,461 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/backup/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs/schedulebase"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/syntheticprivilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+)
+
+const (
+	scheduleRestoreDrillOp = "CREATE SCHEDULE FOR RESTORE DRILL"
+	optSampleSize          = "sample_size"
+)
+
+var restoreDrillOptionExpectValues = map[string]exprutil.KVStringOptValidate{
+	optSampleSize: exprutil.KVStringOptRequireValue,
+}
+
+var scheduledRestoreDrillOptionExpectValues = map[string]exprutil.KVStringOptValidate{
+	optFirstRun:          exprutil.KVStringOptRequireValue,
+	optOnExecFailure:     exprutil.KVStringOptRequireValue,
+	optOnPreviousRunning: exprutil.KVStringOptRequireValue,
+}
+
+var scheduledRestoreDrillHeader = colinfo.ResultColumns{
+	{Name: "schedule_id", Typ: types.Int},
+	{Name: "label", Typ: types.String},
+	{Name: "status", Typ: types.String},
+	{Name: "first_run", Typ: types.TimestampTZ},
+	{Name: "schedule", Typ: types.String},
+	{Name: "restore_drill_stmt", Typ: types.String},
+}
+
+type scheduledRestoreDrillExecutor struct {
+	metrics *jobs.ExecutorMetrics
+}
+
+var _ jobs.ScheduledJobExecutor = (*scheduledRestoreDrillExecutor)(nil)
+
+// ExecuteJob implements jobs.ScheduledJobExecutor interface.
+func (e *scheduledRestoreDrillExecutor) ExecuteJob(
+	ctx context.Context,
+	txn isql.Txn,
+	cfg *scheduledjobs.JobExecutionConfig,
+	env scheduledjobs.JobSchedulerEnv,
+	sj *jobs.ScheduledJob,
+) error {
+	if err := e.executeRestoreDrill(ctx, txn, cfg, sj); err != nil {
+		e.metrics.NumFailed.Inc(1)
+		return err
+	}
+	e.metrics.NumStarted.Inc(1)
+	return nil
+}
+
+// executeRestoreDrill creates the restore drill job of a run of the schedule.
+func (e *scheduledRestoreDrillExecutor) executeRestoreDrill(
+	ctx context.Context, txn isql.Txn, cfg *scheduledjobs.JobExecutionConfig, sj *jobs.ScheduledJob,
+) error {
+	args := &backuppb.ScheduledRestoreDrillExecutionArgs{}
+	if err := pbtypes.UnmarshalAny(sj.ExecutionArgs().Args, args); err != nil {
+		return errors.Wrap(err, "un-marshaling args")
+	}
+	description, err := restoreDrillDescription(args.CollectionURI)
+	if err != nil {
+		return err
+	}
+
+	p, cleanup := cfg.PlanHookMaker(ctx, "invoke-restore-drill", txn.KV(), sj.Owner())
+	defer cleanup()
+	jr := p.(sql.PlanHookState).ExecCfg().JobRegistry
+	record := jobs.Record{
+		Description: description,
+		Username:    sj.Owner(),
+		Details: jobspb.RestoreDrillDetails{
+			CollectionURI: args.CollectionURI,
+			SampleSize:    args.SampleSize,
+		},
+		Progress: jobspb.RestoreDrillProgress{},
+		CreatedBy: &jobs.CreatedByInfo{
+			Name: jobs.CreatedByScheduledJobs,
+			ID:   int64(sj.ScheduleID()),
+		},
+	}
+	jobID := jr.MakeJobID()
+	if _, err := jr.CreateAdoptableJobWithTxn(ctx, record, jobID, txn); err != nil {
+		return err
+	}
+	log.Dev.Infof(ctx, "restore drill job %d scheduled by %d started", jobID, sj.ScheduleID())
+	return nil
+}
+
+// NotifyJobTermination implements jobs.ScheduledJobExecutor interface.
+func (e *scheduledRestoreDrillExecutor) NotifyJobTermination(
+	ctx context.Context,
+	txn isql.Txn,
+	jobID jobspb.JobID,
+	jobState jobs.State,
+	details jobspb.Details,
+	env scheduledjobs.JobSchedulerEnv,
+	schedule *jobs.ScheduledJob,
+) error {
+	if jobState == jobs.StateSucceeded {
+		e.metrics.NumSucceeded.Inc(1)
+		log.Dev.Infof(ctx, "restore drill job %d scheduled by %d succeeded", jobID, schedule.ScheduleID())
+		return nil
+	}
+
+	e.metrics.NumFailed.Inc(1)
+	err := errors.Errorf(
+		"restore drill job %d scheduled by %d failed with status %s",
+		jobID, schedule.ScheduleID(), jobState)
+	log.Dev.Errorf(ctx, "restore drill error: %v", err)
+	jobs.DefaultHandleFailedRun(schedule, "restore drill job %d failed with err=%v", jobID, err)
+	return nil
+}
+
+// Metrics implements jobs.ScheduledJobExecutor interface.
+func (e *scheduledRestoreDrillExecutor) Metrics() metric.Struct {
+	return e.metrics
+}
+
+// GetCreateScheduleStatement implements jobs.ScheduledJobExecutor interface.
+func (e *scheduledRestoreDrillExecutor) GetCreateScheduleStatement(
+	ctx context.Context, txn isql.Txn, env scheduledjobs.JobSchedulerEnv, sj *jobs.ScheduledJob,
+) (string, error) {
+	args := &backuppb.ScheduledRestoreDrillExecutionArgs{}
+	if err := pbtypes.UnmarshalAny(sj.ExecutionArgs().Args, args); err != nil {
+		return "", errors.Wrap(err, "un-marshaling args")
+	}
+
+	wait, err := schedulebase.ParseOnPreviousRunningOption(sj.ScheduleDetails().Wait)
+	if err != nil {
+		return "", err
+	}
+	onError, err := schedulebase.ParseOnErrorOption(sj.ScheduleDetails().OnError)
+	if err != nil {
+		return "", err
+	}
+
+	node, err := makeRestoreDrillNode(args.CollectionURI, args.SampleSize)
+	if err != nil {
+		return "", err
+	}
+	node.ScheduleLabelSpec = tree.LabelSpec{Label: tree.NewDString(sj.ScheduleLabel())}
+	node.Recurrence = tree.NewDString(sj.ScheduleExpr())
+	node.ScheduleOptions = tree.KVOptions{
+		tree.KVOption{
+			Key:   optOnExecFailure,
+			Value: tree.NewDString(onError),
+		},
+		tree.KVOption{
+			Key:   optOnPreviousRunning,
+			Value: tree.NewDString(wait),
+		},
+	}
+	return tree.AsString(node), nil
+}
+
+// makeRestoreDrillNode returns a tree.ScheduledRestoreDrill for a drill of the
+// given collection with sanitized URIs.
+func makeRestoreDrillNode(
+	collectionURIs []string, sampleSize int64,
+) (*tree.ScheduledRestoreDrill, error) {
+	node := &tree.ScheduledRestoreDrill{}
+	for _, uri := range collectionURIs {
+		sanitized, err := cloud.SanitizeExternalStorageURI(uri, nil /* extraParams */)
+		if err != nil {
+			return nil, err
+		}
+		node.From = append(node.From, tree.NewDString(sanitized))
+	}
+	if sampleSize > 0 {
+		node.Options = tree.KVOptions{
+			tree.KVOption{
+				Key:   optSampleSize,
+				Value: tree.NewDString(strconv.FormatInt(sampleSize, 10)),
+			},
+		}
+	}
+	return node, nil
+}
+
+// restoreDrillDescription returns the description of a restore drill job of
+// the given collection.
+func restoreDrillDescription(collectionURIs []string) (string, error) {
+	node, err := makeRestoreDrillNode(collectionURIs, 0 /* sampleSize */)
+	if err != nil {
+		return "", err
+	}
+	f := tree.NewFmtCtx(tree.FmtSimple)
+	f.WriteString("RESTORE DRILL FROM LATEST IN ")
+	f.FormatURIs(node.From)
+	return f.CloseAndGetString(), nil
+}
+
+// scheduledRestoreDrillSpec is a representation of tree.ScheduledRestoreDrill,
+// prepared for evaluation.
+type scheduledRestoreDrillSpec struct {
+	*tree.ScheduledRestoreDrill
+
+	// Schedule specific properties that get evaluated.
+	scheduleLabel *string
+	recurrence    *string
+	scheduleOpts  map[string]string
+
+	// Restore drill specific properties that get evaluated.
+	collectionURIs []string
+	sampleSize     int64
+}
+
+func makeScheduledRestoreDrillSpec(
+	ctx context.Context, p sql.PlanHookState, schedule *tree.ScheduledRestoreDrill,
+) (*scheduledRestoreDrillSpec, error) {
+	exprEval := p.ExprEvaluator(scheduleRestoreDrillOp)
+	spec := &scheduledRestoreDrillSpec{ScheduledRestoreDrill: schedule}
+
+	if schedule.ScheduleLabelSpec.Label != nil {
+		label, err := exprEval.String(ctx, schedule.ScheduleLabelSpec.Label)
+		if err != nil {
+			return nil, err
+		}
+		spec.scheduleLabel = &label
+	}
+
+	if schedule.Recurrence == nil {
+		// Sanity check: recurrence must be specified.
+		return nil, errors.New("RECURRING clause required")
+	}
+	rec, err := exprEval.String(ctx, schedule.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+	spec.recurrence = &rec
+
+	spec.scheduleOpts, err = exprEval.KVOptions(
+		ctx, schedule.ScheduleOptions, scheduledRestoreDrillOptionExpectValues,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	spec.collectionURIs, err = exprEval.StringArray(ctx, tree.Exprs(schedule.From))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to evaluate restore drill collection paths")
+	}
+
+	opts, err := exprEval.KVOptions(ctx, schedule.Options, restoreDrillOptionExpectValues)
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := opts[optSampleSize]; ok {
+		sampleSize, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || sampleSize <= 0 {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"%s must be a positive integer", optSampleSize)
+		}
+		spec.sampleSize = sampleSize
+	}
+	return spec, nil
+}
+
+func doCreateRestoreDrillSchedule(
+	ctx context.Context,
+	p sql.PlanHookState,
+	spec *scheduledRestoreDrillSpec,
+	resultsCh chan<- tree.Datums,
+) error {
+	// A restore drill may restore any table of the backup, so only users who
+	// are allowed to restore the entire backup may schedule one.
+	if err := p.CheckPrivilegeForUser(
+		ctx, syntheticprivilege.GlobalPrivilegeObject, privilege.RESTORE, p.User(),
+	); err != nil {
+		return pgerror.Wrapf(
+			err,
+			pgcode.InsufficientPrivilege,
+			"only users with the admin role or the RESTORE system privilege are allowed to "+
+				"schedule restore drills")
+	}
+	if err := checkRestoreDestinationPrivileges(ctx, p, spec.collectionURIs); err != nil {
+		return err
+	}
+
+	if spec.ScheduleLabelSpec.IfNotExists {
+		exists, err := schedulebase.CheckScheduleAlreadyExists(ctx, p, *spec.scheduleLabel)
+		if err != nil {
+			return err
+		}
+		if exists {
+			p.BufferClientNotice(ctx,
+				pgnotice.Newf("schedule %q already exists, skipping", *spec.scheduleLabel),
+			)
+			return nil
+		}
+	}
+
+	env := jobs.JobSchedulerEnv(p.ExecCfg().JobsKnobs())
+	recurrence, err := schedulebase.ComputeScheduleRecurrence(env.Now(), spec.recurrence)
+	if err != nil {
+		return err
+	}
+
+	var scheduleLabel string
+	if spec.scheduleLabel != nil {
+		scheduleLabel = *spec.scheduleLabel
+	} else {
+		scheduleLabel = fmt.Sprintf("RESTORE DRILL %d", env.Now().Unix())
+	}
+
+	evalCtx := &p.ExtendedEvalContext().Context
+	firstRun, err := scheduleFirstRun(evalCtx, spec.scheduleOpts)
+	if err != nil {
+		return err
+	}
+	details, err := makeScheduleDetails(spec.scheduleOpts, evalCtx.ClusterID, p.ExecCfg().Settings.Version.ActiveVersion(ctx))
+	if err != nil {
+		return err
+	}
+
+	sj := jobs.NewScheduledJob(env)
+	sj.SetScheduleLabel(scheduleLabel)
+	sj.SetOwner(p.User())
+	if err := sj.SetScheduleAndNextRun(recurrence.Cron); err != nil {
+		return err
+	}
+	sj.SetScheduleDetails(details)
+	args := &backuppb.ScheduledRestoreDrillExecutionArgs{
+		CollectionURI: spec.collectionURIs,
+		SampleSize:    spec.sampleSize,
+	}
+	any, err := pbtypes.MarshalAny(args)
+	if err != nil {
+		return err
+	}
+	sj.SetExecutionDetails(
+		tree.ScheduledRestoreDrillExecutor.InternalName(), jobspb.ExecutionArguments{Args: any},
+	)
+	if firstRun != nil {
+		sj.SetNextRun(*firstRun)
+	}
+
+	if err := jobs.ScheduledJobTxn(p.InternalSQLTxn()).Create(ctx, sj); err != nil {
+		return err
+	}
+
+	node, err := makeRestoreDrillNode(spec.collectionURIs, spec.sampleSize)
+	if err != nil {
+		return err
+	}
+	node.ScheduleLabelSpec = tree.LabelSpec{Label: tree.NewDString(sj.ScheduleLabel())}
+	node.Recurrence = tree.NewDString(sj.ScheduleExpr())
+	nextRun, err := tree.MakeDTimestampTZ(sj.NextRun(), time.Microsecond)
+	if err != nil {
+		return err
+	}
+	resultsCh <- tree.Datums{
+		tree.NewDInt(tree.DInt(sj.ScheduleID())),
+		tree.NewDString(sj.ScheduleLabel()),
+		tree.NewDString("ACTIVE"),
+		nextRun,
+		tree.NewDString(sj.ScheduleExpr()),
+		tree.NewDString(tree.AsString(node)),
+	}
+	return nil
+}
+
+func createRestoreDrillScheduleHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, bool, error) {
+	schedule, ok := stmt.(*tree.ScheduledRestoreDrill)
+	if !ok {
+		return nil, nil, false, nil
+	}
+
+	spec, err := makeScheduledRestoreDrillSpec(ctx, p, schedule)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, resultsCh chan<- tree.Datums) error {
+		err := doCreateRestoreDrillSchedule(ctx, p, spec, resultsCh)
+		if err != nil {
+			telemetry.Count("scheduled-restore-drill.create.failed")
+			return err
+		}
+		return nil
+	}
+	return fn, scheduledRestoreDrillHeader, false, nil
+}
+
+func createRestoreDrillScheduleTypeCheck(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (matched bool, header colinfo.ResultColumns, _ error) {
+	schedule, ok := stmt.(*tree.ScheduledRestoreDrill)
+	if !ok {
+		return false, nil, nil
+	}
+	if err := exprutil.TypeCheck(ctx, scheduleRestoreDrillOp, p.SemaCtx(),
+		exprutil.Strings{
+			schedule.ScheduleLabelSpec.Label,
+			schedule.Recurrence,
+		},
+		exprutil.StringArrays{
+			tree.Exprs(schedule.From),
+		},
+		&exprutil.KVOptions{
+			KVOptions:  schedule.Options,
+			Validation: restoreDrillOptionExpectValues,
+		},
+		&exprutil.KVOptions{
+			KVOptions:  schedule.ScheduleOptions,
+			Validation: scheduledRestoreDrillOptionExpectValues,
+		},
+	); err != nil {
+		return false, nil, err
+	}
+	return true, scheduledRestoreDrillHeader, nil
+}
+
+func init() {
+	sql.AddPlanHook(
+		"schedule restore drill", createRestoreDrillScheduleHook, createRestoreDrillScheduleTypeCheck,
+	)
+
+	jobs.RegisterScheduledJobExecutorFactory(
+		tree.ScheduledRestoreDrillExecutor.InternalName(),
+		func() (jobs.ScheduledJobExecutor, error) {
+			m := jobs.MakeExecutorMetrics(tree.ScheduledRestoreDrillExecutor.InternalName())
+			return &scheduledRestoreDrillExecutor{
+				metrics: &m,
+			}, nil
+		})
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package backup
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/backup/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledRestoreDrill(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	th, cleanup := newTestHelper(t)
+	defer cleanup()
+
+	th.sqlDB.Exec(t, `
+SET CLUSTER SETTING bulkio.backup.record_fingerprints.enabled = true;
+CREATE DATABASE db;
+CREATE SCHEMA db.sc;
+CREATE TABLE db.t (k INT PRIMARY KEY, v STRING, INDEX (v));
+INSERT INTO db.t SELECT i, 'v' || i::STRING FROM generate_series(1, 100) AS g(i);
+CREATE TABLE db.sc.u (x INT PRIMARY KEY);
+INSERT INTO db.sc.u VALUES (1), (2), (3);
+CREATE DATABASE other;
+CREATE TABLE other.t (a INT PRIMARY KEY);
+`)
+	th.sqlDB.Exec(t, `BACKUP INTO 'nodelocal://1/drill'`)
+
+	runDrill := func(t *testing.T, schedule string) backuppb.RestoreDrillResult {
+		defer th.clearSchedules(t)
+		var scheduleID jobspb.ScheduleID
+		var unused interface{}
+		th.sqlDB.QueryRow(t, schedule).Scan(&scheduleID, &unused, &unused, &unused, &unused, &unused)
+
+		sj := th.loadSchedule(t, scheduleID)
+		th.env.SetTime(sj.NextRun().Add(time.Second))
+		require.NoError(t, th.executeSchedules())
+		th.waitForSuccessfulScheduledJob(t, scheduleID)
+
+		var value []byte
+		th.sqlDB.QueryRow(t, `
+SELECT value FROM system.job_info WHERE info_key = $1 AND job_id = (
+  SELECT id FROM `+th.env.SystemJobsTableName()+` WHERE created_by_type = $2 AND created_by_id = $3
+)`, restoreDrillResultKey, jobs.CreatedByScheduledJobs, scheduleID).Scan(&value)
+		var result backuppb.RestoreDrillResult
+		require.NoError(t, protoutil.Unmarshal(value, &result))
+
+		// The temporary databases of the drill are dropped.
+		th.sqlDB.CheckQueryResults(t,
+			`SELECT count(*) FROM [SHOW DATABASES] WHERE database_name LIKE 'crdb_restore_drill%'`,
+			[][]string{{"0"}})
+		return result
+	}
+
+	t.Run("all-tables", func(t *testing.T) {
+		result := runDrill(t,
+			`CREATE SCHEDULE 'drill' FOR RESTORE DRILL FROM LATEST IN 'nodelocal://1/drill' RECURRING '@hourly'`)
+		require.Len(t, result.RestoreJobIDs, 2)
+		var names []string
+		for _, tbl := range result.Tables {
+			require.Equal(t, backuppb.RestoreDrillResult_Table_VERIFIED, tbl.Status, tbl.Name)
+			names = append(names, tbl.Name)
+		}
+		require.ElementsMatch(t, []string{"db.public.t", "db.sc.u", "other.public.t"}, names)
+	})
+
+	t.Run("sample", func(t *testing.T) {
+		result := runDrill(t,
+			`CREATE SCHEDULE 'drill' FOR RESTORE DRILL FROM LATEST IN 'nodelocal://1/drill' WITH sample_size = '1' RECURRING '@hourly'`)
+		require.Len(t, result.Tables, 1)
+		require.Equal(t, backuppb.RestoreDrillResult_Table_VERIFIED, result.Tables[0].Status)
+	})
+
+	t.Run("unverified", func(t *testing.T) {
+		th.sqlDB.Exec(t, `SET CLUSTER SETTING bulkio.backup.record_fingerprints.enabled = false`)
+		th.sqlDB.Exec(t, `BACKUP DATABASE other INTO 'nodelocal://1/unverified'`)
+		result := runDrill(t,
+			`CREATE SCHEDULE 'drill' FOR RESTORE DRILL FROM LATEST IN 'nodelocal://1/unverified' RECURRING '@hourly'`)
+		require.Len(t, result.Tables, 1)
+		require.Equal(t, backuppb.RestoreDrillResult_Table_UNVERIFIED, result.Tables[0].Status)
+	})
+
+	t.Run("show-create-schedule", func(t *testing.T) {
+		defer th.clearSchedules(t)
+		var scheduleID jobspb.ScheduleID
+		var unused interface{}
+		th.sqlDB.QueryRow(t, `
+CREATE SCHEDULE 'drill' FOR RESTORE DRILL FROM LATEST IN 'nodelocal://1/drill'
+WITH sample_size = '5' RECURRING '@daily' WITH SCHEDULE OPTIONS on_execution_failure = 'pause'`,
+		).Scan(&scheduleID, &unused, &unused, &unused, &unused, &unused)
+		th.sqlDB.CheckQueryResults(t,
+			fmt.Sprintf(`SELECT create_statement FROM [SHOW CREATE SCHEDULE %d]`, scheduleID),
+			[][]string{{"CREATE SCHEDULE 'drill' FOR RESTORE DRILL FROM LATEST IN 'nodelocal://1/drill' " +
+				"WITH OPTIONS (sample_size = '5') RECURRING '@daily' " +
+				"WITH SCHEDULE OPTIONS on_execution_failure = 'PAUSE', on_previous_running = 'WAIT'"}},
+		)
+	})
+
+	t.Run("invalid-sample-size", func(t *testing.T) {
+		th.sqlDB.ExpectErr(t, "sample_size must be a positive integer",
+			`CREATE SCHEDULE FOR RESTORE DRILL FROM LATEST IN 'nodelocal://1/drill' WITH sample_size = '0' RECURRING '@daily'`)
+	})
+
+	t.Run("requires-restore-privilege", func(t *testing.T) {
+		th.sqlDB.Exec(t, `CREATE USER testuser`)
+		testuser := th.server.SQLConn(t, serverutils.User("testuser"))
+		_, err := testuser.Exec(
+			`CREATE SCHEDULE FOR RESTORE DRILL FROM LATEST IN 'nodelocal://1/drill' RECURRING '@daily'`)
+		require.ErrorContains(t, err, "RESTORE system privilege")
+	})
+}
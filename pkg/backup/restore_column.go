@@ -0,0 +1,424 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/featureflag"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/cockroach/pkg/util/unique"
+	"github.com/cockroachdb/errors"
+)
+
+// RESTORE COLUMN restores the data a column of a table which still exists had
+// in a backup of the table.
+//
+// A column shares its KVs with the other columns of its column family, so its
+// data cannot be ingested from the backup without overwriting the live values
+// of those columns. Instead, the primary key and the column of the table are
+// restored into a temporary database, and the column is copied into the rows
+// of the live table with the same primary key by batches of UPDATE statements.
+// Rows which are not in the backup are left unchanged. The temporary database
+// is dropped once the column is restored, or the statement fails.
+
+// restoreColumnBatchSize is the number of rows of the restored table each
+// UPDATE statement of RESTORE COLUMN copies the column of.
+const restoreColumnBatchSize = 1000
+
+var restoreColumnHeader = colinfo.ResultColumns{
+	{Name: "table_name", Typ: types.String},
+	{Name: "column_name", Typ: types.String},
+	{Name: "rows", Typ: types.Int},
+}
+
+func restoreColumnTypeCheck(
+	ctx context.Context, restoreStmt *tree.Restore, p sql.PlanHookState,
+) (matched bool, header colinfo.ResultColumns, _ error) {
+	if err := exprutil.TypeCheck(
+		ctx, "RESTORE COLUMN", p.SemaCtx(),
+		exprutil.StringArrays{
+			tree.Exprs(restoreStmt.From),
+			tree.Exprs(restoreStmt.Options.DecryptionKMSURI),
+		},
+		exprutil.Strings{
+			restoreStmt.Subdir,
+			restoreStmt.Options.EncryptionPassphrase,
+		},
+	); err != nil {
+		return false, nil, err
+	}
+	return true, restoreColumnHeader, nil
+}
+
+// restoreColumnPlanHook implements PlanHookFn for RESTORE COLUMN.
+func restoreColumnPlanHook(
+	ctx context.Context, restoreStmt *tree.Restore, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, bool, error) {
+	if err := featureflag.CheckEnabled(
+		ctx,
+		p.ExecCfg(),
+		featureRestoreEnabled,
+		"RESTORE",
+	); err != nil {
+		return nil, nil, false, err
+	}
+
+	opts := restoreStmt.Options
+	opts.EncryptionPassphrase, opts.DecryptionKMSURI = nil, nil
+	if !opts.IsDefault() {
+		return nil, nil, false, pgerror.New(pgcode.InvalidParameterValue,
+			"RESTORE COLUMN only supports the encryption_passphrase and kms options")
+	}
+
+	exprEval := p.ExprEvaluator("RESTORE COLUMN")
+	from, err := exprEval.StringArray(ctx, tree.Exprs(restoreStmt.From))
+	if err != nil {
+		return nil, nil, false, err
+	}
+	backupToken, err := exprEval.String(ctx, restoreStmt.Subdir)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	var passphrase string
+	if restoreStmt.Options.EncryptionPassphrase != nil {
+		if passphrase, err = exprEval.String(ctx, restoreStmt.Options.EncryptionPassphrase); err != nil {
+			return nil, nil, false, err
+		}
+	}
+	kms, err := exprEval.StringArray(ctx, tree.Exprs(restoreStmt.Options.DecryptionKMSURI))
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, restoreStmt.StatementTag())
+		defer span.Finish()
+
+		// The column is restored by a RESTORE job and UPDATE statements which
+		// run in transactions of their own.
+		if !p.ExtendedEvalContext().TxnIsSingleStmt {
+			return pgerror.New(pgcode.InvalidTransactionState,
+				"RESTORE COLUMN cannot be used inside a multi-statement transaction")
+		}
+		if err := sql.CheckDestinationPrivileges(ctx, p, from); err != nil {
+			return err
+		}
+
+		tn := restoreStmt.Column.TableName.ToTableName()
+		prefix, table, err := p.ResolveMutableTableDescriptor(ctx, &tn, true /* required */, tree.ResolveRequireTableDesc)
+		if err != nil {
+			return err
+		}
+		tn = tree.MakeTableNameFromPrefix(prefix.NamePrefix(), tree.Name(table.GetName()))
+		if err := p.CheckPrivilege(ctx, table, privilege.UPDATE); err != nil {
+			return err
+		}
+		colName := string(restoreStmt.Column.ColumnName)
+
+		var endTime hlc.Timestamp
+		if restoreStmt.AsOf.Expr != nil {
+			asOf, err := p.EvalAsOfTimestamp(ctx, restoreStmt.AsOf)
+			if err != nil {
+				return err
+			}
+			endTime = asOf.Timestamp
+		}
+		backupTable, err := resolveBackupTable(
+			ctx, p, "RESTORE COLUMN", restoreStmt, from, backupToken, endTime, table.GetID(),
+		)
+		if err != nil {
+			return err
+		}
+		pkCols, err := checkRestoreColumn(table, backupTable.desc, colName)
+		if err != nil {
+			return err
+		}
+
+		// Commit the transaction used to resolve the column, and release the
+		// lease it holds on the table. This is safe because the statement runs
+		// in an implicit transaction.
+		if err := p.Txn().Commit(ctx); err != nil {
+			return err
+		}
+		p.InternalSQLTxn().Descriptors().ReleaseAll(ctx)
+
+		r := columnRestorer{
+			execCfg:    p.ExecCfg(),
+			executor:   p.ExecCfg().InternalDB.Executor(),
+			override:   sessiondata.InternalExecutorOverride{User: p.User()},
+			table:      tn,
+			column:     tree.Name(colName),
+			pkCols:     pkCols,
+			backup:     backupTable,
+			from:       from,
+			passphrase: passphrase,
+			kms:        kms,
+		}
+		rows, err := r.restore(ctx, unique.GenerateUniqueInt(
+			unique.ProcessUniqueID(p.ExtendedEvalContext().NodeID.SQLInstanceID()),
+		))
+		if err != nil {
+			return errors.Wrapf(err, "restoring column %q", colName)
+		}
+		telemetry.Count("restore.column")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case resultsCh <- tree.Datums{
+			tree.NewDString(tn.String()),
+			tree.NewDString(colName),
+			tree.NewDInt(tree.DInt(rows)),
+		}:
+			return nil
+		}
+	}
+	return fn, restoreColumnHeader, false, nil
+}
+
+// checkRestoreColumn checks that the named column of the table in the backup
+// can be restored into the table, and returns the names of the primary key
+// columns of the table.
+func checkRestoreColumn(
+	table, backupTable catalog.TableDescriptor, colName string,
+) (tree.NameList, error) {
+	col := catalog.FindColumnByName(table, colName)
+	if col == nil || !col.Public() {
+		return nil, errors.WithHint(
+			pgerror.Newf(pgcode.UndefinedColumn,
+				"column %q does not exist on table %q", colName, table.GetName()),
+			"add the column back with ALTER TABLE ... ADD COLUMN before restoring its data",
+		)
+	}
+	if col.IsComputed() {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"cannot restore computed column %q", colName)
+	}
+	backupCol := catalog.FindColumnByName(backupTable, colName)
+	if backupCol == nil || !backupCol.Public() {
+		return nil, errors.WithHint(
+			pgerror.Newf(pgcode.UndefinedColumn,
+				"column %q does not exist on table %q in the backup", colName, backupTable.GetName()),
+			"use AS OF SYSTEM TIME to restore the column as of a time before it was dropped",
+		)
+	}
+	if !backupCol.GetType().Identical(col.GetType()) {
+		return nil, pgerror.Newf(pgcode.DatatypeMismatch,
+			"cannot restore column %q: its type is %s in the backup but %s in the table",
+			colName, backupCol.GetType().SQLString(), col.GetType().SQLString())
+	}
+	// The types the restored table references are restored into the temporary
+	// database as new types, so its values cannot be assigned to the column.
+	if col.GetType().UserDefined() {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"cannot restore column %q of user-defined type %s", colName, col.GetType().SQLString())
+	}
+
+	// The rows of the table are matched to those in the backup by their
+	// primary key, so it must be the same in both.
+	pk, backupPK := table.GetPrimaryIndex(), backupTable.GetPrimaryIndex()
+	if pk.NumKeyColumns() != backupPK.NumKeyColumns() {
+		return nil, pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"cannot restore column %q: the primary key of table %q changed since the backup",
+			colName, table.GetName())
+	}
+	pkCols := make(tree.NameList, pk.NumKeyColumns())
+	for i := range pkCols {
+		name := pk.GetKeyColumnName(i)
+		if name == colName {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot restore primary key column %q", colName)
+		}
+		if name != backupPK.GetKeyColumnName(i) {
+			return nil, pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"cannot restore column %q: the primary key of table %q changed since the backup",
+				colName, table.GetName())
+		}
+		pkCols[i] = tree.Name(name)
+	}
+	return pkCols, nil
+}
+
+// columnRestorer restores the data of a column of a table from a backup.
+type columnRestorer struct {
+	execCfg  *sql.ExecutorConfig
+	executor isql.Executor
+	override sessiondata.InternalExecutorOverride
+
+	table  tree.TableName
+	column tree.Name
+	pkCols tree.NameList
+
+	backup     backupTable
+	from       []string
+	passphrase string
+	kms        []string
+}
+
+// restore restores the primary key and the column of the table into a
+// temporary database, copies the column into the table, and returns the
+// number of rows of the table which were updated.
+func (r *columnRestorer) restore(ctx context.Context, id int64) (int, error) {
+	tempDB := tree.Name(fmt.Sprintf("crdb_restore_column_%d", id))
+	defer func() {
+		// The temporary database is dropped even if the statement was canceled.
+		ctx := context.WithoutCancel(ctx)
+		if _, err := r.executor.ExecEx(
+			ctx, "restore-column-drop-database", nil /* txn */, r.override,
+			fmt.Sprintf("DROP DATABASE IF EXISTS %s CASCADE", tempDB.String()),
+		); err != nil {
+			log.Dev.Warningf(ctx, "RESTORE COLUMN could not drop temporary database %s: %v", tempDB, err)
+		}
+	}()
+	if _, err := r.executor.ExecEx(
+		ctx, "restore-column-create-database", nil /* txn */, r.override,
+		fmt.Sprintf("CREATE DATABASE %s", tempDB.String()),
+	); err != nil {
+		return 0, err
+	}
+	// Restore expects the user-defined schema of the restored table to exist in
+	// the target database.
+	if sc := r.backup.name.SchemaName; sc != catconstants.PublicSchemaName {
+		if _, err := r.executor.ExecEx(
+			ctx, "restore-column-create-schema", nil /* txn */, r.override,
+			fmt.Sprintf("CREATE SCHEMA %s.%s", tempDB.String(), sc.String()),
+		); err != nil {
+			return 0, err
+		}
+	}
+
+	args := []interface{}{r.backup.subdir}
+	placeholders := make([]string, 0, len(r.from))
+	for _, uri := range r.from {
+		args = append(args, uri)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+	var asOf string
+	if !r.backup.endTime.IsEmpty() {
+		asOf = fmt.Sprintf(" AS OF SYSTEM TIME '%s'", r.backup.endTime.AsOfSystemTime())
+	}
+	args = append(args, string(tempDB), string(r.column))
+	stmt := fmt.Sprintf(
+		`RESTORE TABLE %s FROM $1 IN (%s)%s WITH into_db = $%d, columns = $%d, detached, `+
+			`skip_missing_foreign_keys, skip_missing_sequences, skip_missing_udfs`,
+		r.backup.name.String(), strings.Join(placeholders, ", "), asOf, len(args)-1, len(args),
+	)
+	if r.passphrase != "" {
+		args = append(args, r.passphrase)
+		stmt += fmt.Sprintf(", encryption_passphrase = $%d", len(args))
+	}
+	if len(r.kms) > 0 {
+		kms := make([]string, len(r.kms))
+		for i, uri := range r.kms {
+			args = append(args, uri)
+			kms[i] = fmt.Sprintf("$%d", len(args))
+		}
+		stmt += fmt.Sprintf(", kms = (%s)", strings.Join(kms, ", "))
+	}
+	row, err := r.executor.QueryRowEx(ctx, "restore-column-restore", nil /* txn */, r.override, stmt, args...)
+	if err != nil {
+		return 0, err
+	}
+	if len(row) == 0 {
+		return 0, errors.AssertionFailedf("restore returned no job ID")
+	}
+	restoreJobID := jobspb.JobID(tree.MustBeDInt(row[0]))
+	if err := r.execCfg.JobRegistry.WaitForJobs(ctx, []jobspb.JobID{restoreJobID}); err != nil {
+		return 0, errors.Wrapf(err, "restore job %d", restoreJobID)
+	}
+
+	restored := tree.MakeTableNameWithSchema(tempDB, r.backup.name.SchemaName, r.backup.name.ObjectName)
+	return r.copyColumn(ctx, &restored)
+}
+
+// copyColumn copies the column from the restored table into the rows of the
+// table with the same primary key, in batches of restoreColumnBatchSize rows
+// of the restored table, and returns the number of rows which were updated.
+// Rows whose value is already the restored one are not updated.
+func (r *columnRestorer) copyColumn(ctx context.Context, restored *tree.TableName) (int, error) {
+	srcPK := make([]string, len(r.pkCols))
+	dstPK := make([]string, len(r.pkCols))
+	for i := range r.pkCols {
+		srcPK[i] = "s." + r.pkCols[i].String()
+		dstPK[i] = "t." + r.pkCols[i].String()
+	}
+	srcKey := strings.Join(srcPK, ", ")
+	dstKey := strings.Join(dstPK, ", ")
+	placeholders := func(first int) string {
+		p := make([]string, len(r.pkCols))
+		for i := range p {
+			p[i] = fmt.Sprintf("$%d", first+i)
+		}
+		return strings.Join(p, ", ")
+	}
+
+	var rows int
+	// lower is the primary key of the last row of the previous batch.
+	var lower tree.Datums
+	for {
+		// upper is the primary key of the last row of the batch, if the batch is
+		// not the last one.
+		cond := "true"
+		if lower != nil {
+			cond = fmt.Sprintf("(%s) > (%s)", srcKey, placeholders(1))
+		}
+		boundArgs := make([]interface{}, len(lower))
+		for i, d := range lower {
+			boundArgs[i] = d
+		}
+		upper, err := r.executor.QueryRowEx(
+			ctx, "restore-column-batch", nil /* txn */, r.override,
+			fmt.Sprintf(`SELECT %s FROM %s AS s WHERE %s ORDER BY %s LIMIT 1 OFFSET %d`,
+				srcKey, restored.String(), cond, srcKey, restoreColumnBatchSize-1),
+			boundArgs...,
+		)
+		if err != nil {
+			return 0, err
+		}
+		updateArgs := boundArgs
+		if upper != nil {
+			cond += fmt.Sprintf(" AND (%s) <= (%s)", srcKey, placeholders(len(updateArgs)+1))
+			for _, d := range upper {
+				updateArgs = append(updateArgs, d)
+			}
+		}
+		n, err := r.executor.ExecEx(
+			ctx, "restore-column-update", nil /* txn */, r.override,
+			fmt.Sprintf(`UPDATE %s AS t SET %s = s.%s FROM %s AS s `+
+				`WHERE (%s) = (%s) AND t.%s IS DISTINCT FROM s.%s AND %s`,
+				r.table.String(), r.column.String(), r.column.String(), restored.String(),
+				dstKey, srcKey, r.column.String(), r.column.String(), cond),
+			updateArgs...,
+		)
+		if err != nil {
+			return 0, err
+		}
+		rows += n
+		if upper == nil {
+			return rows, nil
+		}
+		lower = upper
+	}
+}
@@ -461,6 +461,10 @@ func (rd *restoreDataProcessor) runRestoreWorkers(
 		if err != nil {
 			return errors.Wrap(err, "creating key rewriter from rekeys")
 		}
+		rowFilter, err := makeRestoreRowFilter(ctx, rd.FlowCtx.Codec(), rd.FlowCtx.NewEvalCtx(), rd.spec.RowFilters)
+		if err != nil {
+			return errors.Wrap(err, "creating row filter")
+		}
 
 		for {
 			done, err := func() (done bool, _ error) {
@@ -528,7 +532,7 @@ func (rd *restoreDataProcessor) runRestoreWorkers(
 						return done, errors.Wrap(err, "opening SSTs")
 					}
 
-					ingestSummary, err := rd.processRestoreSpanEntry(ctx, kr, sstIter)
+					ingestSummary, err := rd.processRestoreSpanEntry(ctx, kr, rowFilter, sstIter)
 					if err != nil {
 						return done, errors.Wrap(err, "processing restore span entry")
 					}
@@ -700,8 +704,10 @@ func (rd *restoreDataProcessor) linkFiles(
 
 var backupFileReadError = errors.New("error reading backup file")
 
+// processRestoreSpanEntry ingests the keys of the entry which are matched by
+// the key rewriter and kept by the row filter, if there is one.
 func (rd *restoreDataProcessor) processRestoreSpanEntry(
-	ctx context.Context, kr *KeyRewriter, sst mergedSST,
+	ctx context.Context, kr *KeyRewriter, rowFilter *restoreRowFilter, sst mergedSST,
 ) (kvpb.BulkOpSummary, error) {
 	db := rd.FlowCtx.Cfg.DB
 	var summary kvpb.BulkOpSummary
@@ -830,6 +836,24 @@ func (rd *restoreDataProcessor) processRestoreSpanEntry(
 			}
 			continue
 		}
+		if rowFilter != nil {
+			filtered, keep, err := rowFilter.filter(ctx, key.Key, value)
+			if err != nil {
+				return summary, errors.Wrapf(err, "filtering %s", key.Key)
+			}
+			if !keep {
+				if verbose {
+					log.Dev.Infof(ctx, "filtering out %s %s", key.Key, value.PrettyPrint())
+				}
+				continue
+			}
+			// The value may have been rewritten without some of its columns, so
+			// put it back after the MVCC value header in valueScratch, where the
+			// value already is otherwise.
+			headerLen := len(valueScratch) - len(value.RawBytes)
+			valueScratch = append(valueScratch[:headerLen], filtered.RawBytes...)
+			value.RawBytes = valueScratch[headerLen:]
+		}
 
 		// Rewriting the key means the checksum needs to be updated.
 		value.ClearChecksum()
@@ -0,0 +1,596 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/backup/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/backup/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/backup/backuputils"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/idxtype"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/cockroachdb/errors"
+)
+
+// recordFingerprintsEnabled controls whether backups record the fingerprints
+// of the tables they back up, which restore drills compare restored data to.
+var recordFingerprintsEnabled = settings.RegisterBoolSetting(
+	settings.ApplicationLevel,
+	"bulkio.backup.record_fingerprints.enabled",
+	"if enabled, backups record a fingerprint of each index of the backed up tables "+
+		"as of the backup end time, which restore drills use to verify restored data",
+	false, /* defaultValue */
+)
+
+// restoreDrillResultKey is the job info key under which a restore drill job
+// records its backuppb.RestoreDrillResult.
+const restoreDrillResultKey = "restore_drill_result"
+
+// fingerprintIndex returns the fingerprint of the data in the given index of a
+// table, read as of asOf if it is set. The fingerprint of an empty index is the
+// empty string.
+func fingerprintIndex(
+	ctx context.Context,
+	executor isql.Executor,
+	tableDesc catalog.TableDescriptor,
+	index catalog.Index,
+	asOf hlc.Timestamp,
+) (string, error) {
+	query, err := sql.BuildFingerprintQueryForIndex(tableDesc, index, nil /* ignoredColumns */)
+	if err != nil {
+		return "", err
+	}
+	if asOf.IsSet() {
+		query = query + " AS OF SYSTEM TIME " + asOf.AsOfSystemTime()
+	}
+	row, err := executor.QueryRowEx(
+		ctx, "restore-drill-fingerprint", nil, /* txn */
+		sessiondata.NodeUserSessionDataOverride, query,
+	)
+	if err != nil {
+		return "", err
+	}
+	if len(row) != 1 {
+		return "", errors.AssertionFailedf("unexpected number of columns returned: 1 vs %d", len(row))
+	}
+	if row[0] == tree.DNull {
+		return "", nil
+	}
+	return string(tree.MustBeDString(row[0])), nil
+}
+
+// fingerprintableIndexes returns the indexes of a table that can be
+// fingerprinted. Inverted and vector indexes cannot be scanned on their own.
+func fingerprintableIndexes(tableDesc catalog.TableDescriptor) []catalog.Index {
+	var indexes []catalog.Index
+	for _, index := range tableDesc.ActiveIndexes() {
+		if index.GetType() != idxtype.FORWARD {
+			continue
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes
+}
+
+// getTableFingerprintsForBackup computes the fingerprints of the indexes of the
+// user tables found in descs as of the end time of a backup.
+//
+// Like table statistics, fingerprints are collected on a best-effort basis: a
+// backup does not fail if a table cannot be fingerprinted. A restore drill
+// reports such a table as unverified.
+func getTableFingerprintsForBackup(
+	ctx context.Context, executor isql.Executor, descs []descpb.Descriptor, endTime hlc.Timestamp,
+) []backuppb.BackupManifest_TableFingerprint {
+	var fingerprints []backuppb.BackupManifest_TableFingerprint
+	for i := range descs {
+		tbl, _, _, _, _ := descpb.GetDescriptors(&descs[i])
+		if !isDrillableTable(tbl) {
+			continue
+		}
+		tableDesc := tabledesc.NewBuilder(tbl).BuildImmutableTable()
+		for _, index := range fingerprintableIndexes(tableDesc) {
+			fingerprint, err := fingerprintIndex(ctx, executor, tableDesc, index, endTime)
+			if err != nil {
+				log.Dev.Warningf(
+					ctx, "failed to fingerprint index %d of table: %s, table ID: %d during a backup: %s",
+					index.GetID(), tableDesc.GetName(), tableDesc.GetID(), err,
+				)
+				continue
+			}
+			fingerprints = append(fingerprints, backuppb.BackupManifest_TableFingerprint{
+				TableID:     tableDesc.GetID(),
+				IndexID:     index.GetID(),
+				Fingerprint: fingerprint,
+			})
+		}
+	}
+	return fingerprints
+}
+
+// isDrillableTable returns whether tbl is a public user table, which backups
+// fingerprint and restore drills restore.
+func isDrillableTable(tbl *descpb.TableDescriptor) bool {
+	return tbl != nil && tbl.IsTable() && !tbl.IsVirtualTable() &&
+		tbl.ParentID != keys.SystemDatabaseID && tbl.State == descpb.DescriptorState_PUBLIC
+}
+
+type restoreDrillMetrics struct {
+	TablesVerified   *metric.Counter
+	TablesMismatched *metric.Counter
+	TablesUnverified *metric.Counter
+}
+
+// MetricStruct implements the metric.Struct interface.
+func (restoreDrillMetrics) MetricStruct() {}
+
+func newRestoreDrillMetrics() restoreDrillMetrics {
+	return restoreDrillMetrics{
+		TablesVerified: metric.NewCounter(metric.Metadata{
+			Name:        "restore_drill.tables_verified",
+			Help:        "Number of tables restored by restore drills whose data matched the backup",
+			Measurement: "Tables",
+			Unit:        metric.Unit_COUNT,
+		}),
+		TablesMismatched: metric.NewCounter(metric.Metadata{
+			Name:        "restore_drill.tables_mismatched",
+			Help:        "Number of tables restored by restore drills whose data did not match the backup",
+			Measurement: "Tables",
+			Unit:        metric.Unit_COUNT,
+		}),
+		TablesUnverified: metric.NewCounter(metric.Metadata{
+			Name:        "restore_drill.tables_unverified",
+			Help:        "Number of tables restored by restore drills for which the backup recorded no fingerprints",
+			Measurement: "Tables",
+			Unit:        metric.Unit_COUNT,
+		}),
+	}
+}
+
+// drillTable is a table of the backup that a restore drill restores.
+type drillTable struct {
+	desc       catalog.TableDescriptor
+	dbName     string
+	schemaName string
+}
+
+func (t drillTable) name() *tree.TableName {
+	tn := tree.MakeTableNameWithSchema(
+		tree.Name(t.dbName), tree.Name(t.schemaName), tree.Name(t.desc.GetName()),
+	)
+	return &tn
+}
+
+// pickDrillTables returns the tables in the backup described by manifest that a
+// restore drill restores, grouped by their parent database. If sampleSize is
+// positive, at most sampleSize randomly chosen tables are returned.
+func pickDrillTables(
+	manifest *backuppb.BackupManifest, sampleSize int,
+) map[descpb.ID][]drillTable {
+	dbNames := make(map[descpb.ID]string)
+	schemaNames := make(map[descpb.ID]string)
+	var tables []catalog.TableDescriptor
+	for i := range manifest.Descriptors {
+		tbl, db, _, sc, _ := descpb.GetDescriptors(&manifest.Descriptors[i])
+		switch {
+		case db != nil:
+			dbNames[db.ID] = db.Name
+		case sc != nil:
+			schemaNames[sc.ID] = sc.Name
+		case isDrillableTable(tbl):
+			tables = append(tables, tabledesc.NewBuilder(tbl).BuildImmutableTable())
+		}
+	}
+
+	var picked []drillTable
+	for _, tbl := range tables {
+		dbName, ok := dbNames[tbl.GetParentID()]
+		if !ok {
+			continue
+		}
+		schemaName, ok := schemaNames[tbl.GetParentSchemaID()]
+		if !ok {
+			schemaName = catconstants.PublicSchemaName
+		}
+		picked = append(picked, drillTable{desc: tbl, dbName: dbName, schemaName: schemaName})
+	}
+	sort.Slice(picked, func(i, j int) bool { return picked[i].desc.GetID() < picked[j].desc.GetID() })
+	if sampleSize > 0 && sampleSize < len(picked) {
+		rng, _ := randutil.NewPseudoRand()
+		rng.Shuffle(len(picked), func(i, j int) { picked[i], picked[j] = picked[j], picked[i] })
+		picked = picked[:sampleSize]
+	}
+
+	byDB := make(map[descpb.ID][]drillTable)
+	for _, t := range picked {
+		byDB[t.desc.GetParentID()] = append(byDB[t.desc.GetParentID()], t)
+	}
+	return byDB
+}
+
+// resolveLatestBackupManifest returns the path of the latest backup in the
+// collection and the manifest of the last backup in its chain, which describes
+// the data that a restore of the latest backup restores.
+func resolveLatestBackupManifest(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user username.SQLUsername,
+	collectionURIs []string,
+	kmsEnv cloud.KMSEnv,
+) (string, backuppb.BackupManifest, error) {
+	mkStore := execCfg.DistSQLSrv.ExternalStorageFromURI
+	subdir, err := backupdest.ReadLatestFile(ctx, collectionURIs[0], mkStore, user)
+	if err != nil {
+		return "", backuppb.BackupManifest{}, errors.Wrap(err, "reading LATEST file")
+	}
+	baseDirs, err := backuputils.AppendPaths(collectionURIs, subdir)
+	if err != nil {
+		return "", backuppb.BackupManifest{}, err
+	}
+	incDirs, err := backupdest.ResolveIncrementalsBackupLocation(collectionURIs, subdir)
+	if err != nil {
+		return "", backuppb.BackupManifest{}, err
+	}
+
+	mem := execCfg.RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+	_, manifests, _, _, err := backupdest.ResolveBackupManifests(
+		ctx, execCfg, &mem, collectionURIs[0], collectionURIs, mkStore, subdir,
+		baseDirs, incDirs, hlc.Timestamp{}, nil /* encryption */, kmsEnv, user,
+		false /* includeSkipped */, false, /* includeCompacted */
+	)
+	if err != nil {
+		return "", backuppb.BackupManifest{}, err
+	}
+	if len(manifests) == 0 {
+		return "", backuppb.BackupManifest{}, errors.Newf("no backup found in %s", subdir)
+	}
+	return subdir, manifests[len(manifests)-1], nil
+}
+
+type restoreDrillResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = (*restoreDrillResumer)(nil)
+
+// Resume is part of the jobs.Resumer interface.
+func (r *restoreDrillResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(sql.JobExecContext)
+	execCfg := p.ExecCfg()
+	details := r.job.Details().(jobspb.RestoreDrillDetails)
+	if len(details.CollectionURI) == 0 {
+		return errors.AssertionFailedf("restore drill has no collection")
+	}
+
+	// A previous attempt of this drill may have left its temporary databases
+	// behind.
+	if err := r.cleanup(ctx, execCfg); err != nil {
+		return err
+	}
+
+	kmsEnv := backupencryption.MakeBackupKMSEnv(
+		execCfg.Settings, &execCfg.ExternalIODirConfig, execCfg.InternalDB, p.User(),
+	)
+	backupPath, manifest, err := resolveLatestBackupManifest(
+		ctx, execCfg, p.User(), details.CollectionURI, &kmsEnv,
+	)
+	if err != nil {
+		return err
+	}
+
+	tablesByDB := pickDrillTables(&manifest, int(details.SampleSize))
+	dbIDs := make([]descpb.ID, 0, len(tablesByDB))
+	for id := range tablesByDB {
+		dbIDs = append(dbIDs, id)
+	}
+	sort.Slice(dbIDs, func(i, j int) bool { return dbIDs[i] < dbIDs[j] })
+
+	result := backuppb.RestoreDrillResult{
+		BackupPath:    backupPath,
+		BackupEndTime: manifest.EndTime,
+	}
+	// restored maps the ID of each backed up table to the ID of its restored
+	// copy.
+	restored := make(map[descpb.ID]descpb.ID)
+	for _, dbID := range dbIDs {
+		tables := tablesByDB[dbID]
+		restoreJobID, err := r.restoreTables(ctx, execCfg, details.CollectionURI, dbID, tables)
+		if err != nil {
+			return err
+		}
+		result.RestoreJobIDs = append(result.RestoreJobIDs, restoreJobID)
+		if err := execCfg.JobRegistry.WaitForJobs(ctx, []jobspb.JobID{restoreJobID}); err != nil {
+			return errors.Wrapf(err, "restore job %d", restoreJobID)
+		}
+		restoreJob, err := execCfg.JobRegistry.LoadJob(ctx, restoreJobID)
+		if err != nil {
+			return err
+		}
+		rewrites := restoreJob.Details().(jobspb.RestoreDetails).DescriptorRewrites
+		for _, t := range tables {
+			if rw, ok := rewrites[t.desc.GetID()]; ok {
+				restored[t.desc.GetID()] = rw.ID
+			}
+		}
+	}
+
+	recorded := make(map[descpb.ID]map[descpb.IndexID]string)
+	for _, fp := range manifest.TableFingerprints {
+		if recorded[fp.TableID] == nil {
+			recorded[fp.TableID] = make(map[descpb.IndexID]string)
+		}
+		recorded[fp.TableID][fp.IndexID] = fp.Fingerprint
+	}
+
+	metrics := execCfg.JobRegistry.MetricsStruct().JobSpecificMetrics[jobspb.TypeRestoreDrill].(restoreDrillMetrics)
+	var mismatched int
+	for _, dbID := range dbIDs {
+		for _, t := range tablesByDB[dbID] {
+			res, err := verifyRestoredTable(ctx, execCfg, t, restored, recorded[t.desc.GetID()])
+			if err != nil {
+				return err
+			}
+			switch res.Status {
+			case backuppb.RestoreDrillResult_Table_VERIFIED:
+				metrics.TablesVerified.Inc(1)
+			case backuppb.RestoreDrillResult_Table_MISMATCHED:
+				metrics.TablesMismatched.Inc(1)
+				mismatched++
+			case backuppb.RestoreDrillResult_Table_UNVERIFIED:
+				metrics.TablesUnverified.Inc(1)
+			}
+			result.Tables = append(result.Tables, res)
+		}
+	}
+
+	resultBytes, err := protoutil.Marshal(&result)
+	if err != nil {
+		return err
+	}
+	if err := execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		return jobs.InfoStorageForJob(txn, r.job.ID()).Write(ctx, restoreDrillResultKey, resultBytes)
+	}); err != nil {
+		return err
+	}
+
+	if err := r.cleanup(ctx, execCfg); err != nil {
+		return err
+	}
+	if mismatched > 0 {
+		return jobs.MarkAsPermanentJobError(errors.Newf(
+			"restored data of %d tables does not match backup %s", mismatched, backupPath,
+		))
+	}
+	return nil
+}
+
+// restoreTables restores the given tables of the backed up database with ID
+// dbID into a new temporary database, and returns the ID of the restore job.
+func (r *restoreDrillResumer) restoreTables(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	collectionURIs []string,
+	dbID descpb.ID,
+	tables []drillTable,
+) (jobspb.JobID, error) {
+	tempDB := tree.Name(fmt.Sprintf("crdb_restore_drill_%d_%d", r.job.ID(), dbID))
+	// Record the temporary database before creating it, so that it is dropped
+	// even if the drill fails right after creating it.
+	if err := r.updateProgress(ctx, func(prog *jobspb.RestoreDrillProgress) {
+		prog.TempDatabases = append(prog.TempDatabases, string(tempDB))
+	}); err != nil {
+		return 0, err
+	}
+
+	executor := execCfg.InternalDB.Executor()
+	override := sessiondata.InternalExecutorOverride{User: r.job.Payload().UsernameProto.Decode()}
+	if _, err := executor.ExecEx(
+		ctx, "restore-drill-create-database", nil /* txn */, override,
+		fmt.Sprintf("CREATE DATABASE %s", tempDB.String()),
+	); err != nil {
+		return 0, err
+	}
+	// Restore expects the user-defined schemas of the restored tables to exist
+	// in the target database.
+	created := make(map[string]struct{})
+	for _, t := range tables {
+		if _, ok := created[t.schemaName]; ok || t.schemaName == catconstants.PublicSchemaName {
+			continue
+		}
+		created[t.schemaName] = struct{}{}
+		sc := tree.Name(t.schemaName)
+		if _, err := executor.ExecEx(
+			ctx, "restore-drill-create-schema", nil /* txn */, override,
+			fmt.Sprintf("CREATE SCHEMA %s.%s", tempDB.String(), sc.String()),
+		); err != nil {
+			return 0, err
+		}
+	}
+
+	names := make([]string, len(tables))
+	for i, t := range tables {
+		names[i] = tree.AsString(t.name())
+	}
+	args := make([]interface{}, 0, len(collectionURIs)+1)
+	placeholders := make([]string, 0, len(collectionURIs))
+	for _, uri := range collectionURIs {
+		args = append(args, uri)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+	args = append(args, string(tempDB))
+	stmt := fmt.Sprintf(
+		`RESTORE TABLE %s FROM LATEST IN (%s) WITH into_db = $%d, detached, `+
+			`skip_missing_foreign_keys, skip_missing_sequences, skip_missing_udfs`,
+		strings.Join(names, ", "), strings.Join(placeholders, ", "), len(args),
+	)
+	row, err := executor.QueryRowEx(ctx, "restore-drill-restore", nil /* txn */, override, stmt, args...)
+	if err != nil {
+		return 0, err
+	}
+	if len(row) == 0 {
+		return 0, errors.AssertionFailedf("restore returned no job ID")
+	}
+	restoreJobID := jobspb.JobID(tree.MustBeDInt(row[0]))
+	if err := r.updateProgress(ctx, func(prog *jobspb.RestoreDrillProgress) {
+		prog.RestoreJobIDs = append(prog.RestoreJobIDs, restoreJobID)
+	}); err != nil {
+		return 0, err
+	}
+	return restoreJobID, nil
+}
+
+// verifyRestoredTable compares the fingerprints of the restored copy of a
+// table to the fingerprints recorded when it was backed up.
+func verifyRestoredTable(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	t drillTable,
+	restored map[descpb.ID]descpb.ID,
+	recorded map[descpb.IndexID]string,
+) (backuppb.RestoreDrillResult_Table, error) {
+	res := backuppb.RestoreDrillResult_Table{
+		Name:   tree.AsString(t.name()),
+		Status: backuppb.RestoreDrillResult_Table_UNVERIFIED,
+	}
+	restoredID, ok := restored[t.desc.GetID()]
+	if !ok || len(recorded) == 0 {
+		return res, nil
+	}
+
+	var restoredDesc catalog.TableDescriptor
+	if err := sql.DescsTxn(ctx, execCfg, func(ctx context.Context, txn isql.Txn, col *descs.Collection) error {
+		var err error
+		restoredDesc, err = col.ByIDWithoutLeased(txn.KV()).Get().Table(ctx, restoredID)
+		return err
+	}); err != nil {
+		return res, err
+	}
+
+	// Restore preserves the IDs of the indexes of a table, so the fingerprint of
+	// each index of the restored copy is compared to the one recorded for the
+	// index with the same ID.
+	indexIDs := make([]descpb.IndexID, 0, len(recorded))
+	for id := range recorded {
+		indexIDs = append(indexIDs, id)
+	}
+	sort.Slice(indexIDs, func(i, j int) bool { return indexIDs[i] < indexIDs[j] })
+	for _, indexID := range indexIDs {
+		index, err := catalog.MustFindIndexByID(restoredDesc, indexID)
+		if err != nil {
+			res.MismatchedIndexIDs = append(res.MismatchedIndexIDs, indexID)
+			continue
+		}
+		fingerprint, err := fingerprintIndex(
+			ctx, execCfg.InternalDB.Executor(), restoredDesc, index, hlc.Timestamp{},
+		)
+		if err != nil {
+			return res, errors.Wrapf(err, "fingerprinting restored table %s", res.Name)
+		}
+		if fingerprint != recorded[indexID] {
+			res.MismatchedIndexIDs = append(res.MismatchedIndexIDs, indexID)
+		}
+	}
+	if len(res.MismatchedIndexIDs) > 0 {
+		res.Status = backuppb.RestoreDrillResult_Table_MISMATCHED
+	} else {
+		res.Status = backuppb.RestoreDrillResult_Table_VERIFIED
+	}
+	return res, nil
+}
+
+func (r *restoreDrillResumer) updateProgress(
+	ctx context.Context, fn func(prog *jobspb.RestoreDrillProgress),
+) error {
+	return r.job.NoTxn().Update(ctx, func(txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+		fn(md.Progress.GetRestoreDrill())
+		ju.UpdateProgress(md.Progress)
+		return nil
+	})
+}
+
+// cleanup cancels the restore jobs started by the drill that are still running
+// and drops its temporary databases.
+func (r *restoreDrillResumer) cleanup(ctx context.Context, execCfg *sql.ExecutorConfig) error {
+	prog := r.job.Progress().GetRestoreDrill()
+	if prog == nil || (len(prog.RestoreJobIDs) == 0 && len(prog.TempDatabases) == 0) {
+		return nil
+	}
+	executor := execCfg.InternalDB.Executor()
+	for _, id := range prog.RestoreJobIDs {
+		if _, err := executor.ExecEx(
+			ctx, "restore-drill-cancel-restore", nil, /* txn */
+			sessiondata.NodeUserSessionDataOverride, "CANCEL JOB $1", id,
+		); err != nil {
+			// The restore job may have already finished.
+			log.Dev.Infof(ctx, "could not cancel restore job %d of restore drill: %v", id, err)
+		}
+	}
+	if err := execCfg.JobRegistry.WaitForJobsIgnoringJobErrors(ctx, prog.RestoreJobIDs); err != nil {
+		return err
+	}
+	for _, name := range prog.TempDatabases {
+		db := tree.Name(name)
+		if _, err := executor.ExecEx(
+			ctx, "restore-drill-drop-database", nil, /* txn */
+			sessiondata.NodeUserSessionDataOverride,
+			fmt.Sprintf("DROP DATABASE IF EXISTS %s CASCADE", db.String()),
+		); err != nil {
+			return errors.Wrapf(err, "dropping temporary database %s", name)
+		}
+	}
+	return r.updateProgress(ctx, func(prog *jobspb.RestoreDrillProgress) {
+		prog.RestoreJobIDs = nil
+		prog.TempDatabases = nil
+	})
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface.
+func (r *restoreDrillResumer) OnFailOrCancel(
+	ctx context.Context, execCtx interface{}, _ error,
+) error {
+	return r.cleanup(ctx, execCtx.(sql.JobExecContext).ExecCfg())
+}
+
+// CollectProfile is part of the jobs.Resumer interface.
+func (r *restoreDrillResumer) CollectProfile(_ context.Context, _ interface{}) error {
+	return nil
+}
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeRestoreDrill,
+		func(job *jobs.Job, _ *cluster.Settings) jobs.Resumer {
+			return &restoreDrillResumer{job: job}
+		},
+		jobs.UsesTenantCostControl,
+		jobs.WithJobMetrics(newRestoreDrillMetrics()),
+	)
+}
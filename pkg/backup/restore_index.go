@@ -0,0 +1,412 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package backup
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/backup/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupinfo"
+	"github.com/cockroachdb/cockroach/pkg/backup/backuputils"
+	"github.com/cockroachdb/cockroach/pkg/featureflag"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catformat"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/nstree"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/besteffort"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+// RESTORE INDEX brings back an index which was dropped from a table that
+// still exists, using the definition the index had in a backup of the table.
+//
+// The index is rebuilt from the current rows of the table by a regular index
+// backfill, which keeps the table online, rather than from the index entries in
+// the backup: those entries only match the rows of the table as of the time of
+// the backup, not the rows written since.
+
+var restoreIndexHeader = colinfo.ResultColumns{
+	{Name: "table_name", Typ: types.String},
+	{Name: "index_name", Typ: types.String},
+	{Name: "create_statement", Typ: types.String},
+}
+
+func restoreIndexTypeCheck(
+	ctx context.Context, restoreStmt *tree.Restore, p sql.PlanHookState,
+) (matched bool, header colinfo.ResultColumns, _ error) {
+	if err := exprutil.TypeCheck(
+		ctx, "RESTORE INDEX", p.SemaCtx(),
+		exprutil.StringArrays{
+			tree.Exprs(restoreStmt.From),
+			tree.Exprs(restoreStmt.Options.DecryptionKMSURI),
+		},
+		exprutil.Strings{
+			restoreStmt.Subdir,
+			restoreStmt.Options.EncryptionPassphrase,
+		},
+	); err != nil {
+		return false, nil, err
+	}
+	return true, restoreIndexHeader, nil
+}
+
+// restoreIndexPlanHook implements PlanHookFn for RESTORE INDEX.
+func restoreIndexPlanHook(
+	ctx context.Context, restoreStmt *tree.Restore, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, bool, error) {
+	if err := featureflag.CheckEnabled(
+		ctx,
+		p.ExecCfg(),
+		featureRestoreEnabled,
+		"RESTORE",
+	); err != nil {
+		return nil, nil, false, err
+	}
+
+	opts := restoreStmt.Options
+	opts.EncryptionPassphrase, opts.DecryptionKMSURI = nil, nil
+	if !opts.IsDefault() {
+		return nil, nil, false, pgerror.New(pgcode.InvalidParameterValue,
+			"RESTORE INDEX only supports the encryption_passphrase and kms options")
+	}
+
+	exprEval := p.ExprEvaluator("RESTORE INDEX")
+	from, err := exprEval.StringArray(ctx, tree.Exprs(restoreStmt.From))
+	if err != nil {
+		return nil, nil, false, err
+	}
+	backupToken, err := exprEval.String(ctx, restoreStmt.Subdir)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, restoreStmt.StatementTag())
+		defer span.Finish()
+
+		// The index is built by a schema change which can only start once the
+		// transaction resolving it commits.
+		if !p.ExtendedEvalContext().TxnIsSingleStmt {
+			return pgerror.New(pgcode.InvalidTransactionState,
+				"RESTORE INDEX cannot be used inside a multi-statement transaction")
+		}
+		if err := sql.CheckDestinationPrivileges(ctx, p, from); err != nil {
+			return err
+		}
+
+		tn := restoreStmt.Index.Table
+		prefix, table, err := p.ResolveMutableTableDescriptor(ctx, &tn, true /* required */, tree.ResolveRequireTableDesc)
+		if err != nil {
+			return err
+		}
+		tn = tree.MakeTableNameFromPrefix(prefix.NamePrefix(), tree.Name(table.GetName()))
+		if err := p.CheckPrivilege(ctx, table, privilege.CREATE); err != nil {
+			return err
+		}
+		indexName := string(restoreStmt.Index.Index)
+		if catalog.FindIndexByName(table, indexName) != nil {
+			return pgerror.Newf(pgcode.DuplicateRelation,
+				"index %q already exists on table %q", indexName, table.GetName())
+		}
+
+		var endTime hlc.Timestamp
+		if restoreStmt.AsOf.Expr != nil {
+			asOf, err := p.EvalAsOfTimestamp(ctx, restoreStmt.AsOf)
+			if err != nil {
+				return err
+			}
+			endTime = asOf.Timestamp
+		}
+		backupTable, err := resolveBackupTable(
+			ctx, p, "RESTORE INDEX", restoreStmt, from, backupToken, endTime, table.GetID(),
+		)
+		if err != nil {
+			return err
+		}
+		createStmt, err := restoreIndexCreateStatement(ctx, p, &tn, table, backupTable.desc, indexName)
+		if err != nil {
+			return err
+		}
+
+		// Commit the transaction used to resolve the index, and release the lease
+		// it holds on the table, which would otherwise block the schema change
+		// from publishing new versions of the table. This is safe because the
+		// statement runs in an implicit transaction.
+		if err := p.Txn().Commit(ctx); err != nil {
+			return err
+		}
+		p.InternalSQLTxn().Descriptors().ReleaseAll(ctx)
+
+		if _, err := p.ExecCfg().InternalDB.Executor().ExecEx(
+			ctx, "restore-index", nil, /* txn */
+			sessiondata.InternalExecutorOverride{User: p.User()},
+			createStmt,
+		); err != nil {
+			return errors.Wrapf(err, "restoring index %q", indexName)
+		}
+		telemetry.Count("restore.index")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case resultsCh <- tree.Datums{
+			tree.NewDString(tn.String()),
+			tree.NewDString(indexName),
+			tree.NewDString(createStmt),
+		}:
+			return nil
+		}
+	}
+	return fn, restoreIndexHeader, false, nil
+}
+
+// backupTable is a table in a backup, resolved by RESTORE INDEX or RESTORE
+// COLUMN.
+type backupTable struct {
+	desc catalog.TableDescriptor
+	// name is the name of the table in the backup.
+	name tree.TableName
+	// subdir and endTime identify the backup the table was resolved in.
+	subdir  string
+	endTime hlc.Timestamp
+}
+
+// resolveBackupTable returns the table with the given ID in the backup, as of
+// the given time if it is set. op is the statement resolving it.
+func resolveBackupTable(
+	ctx context.Context,
+	p sql.PlanHookState,
+	op string,
+	restoreStmt *tree.Restore,
+	from []string,
+	backupToken string,
+	endTime hlc.Timestamp,
+	tableID descpb.ID,
+) (backupTable, error) {
+	defaultCollectionURI, _, err := backupdest.GetURIsByLocalityKV(from, "")
+	if err != nil {
+		return backupTable{}, err
+	}
+	subdir, endTime, err := resolveRestoreSubdirAndEndTime(
+		ctx, p, defaultCollectionURI, backupToken, endTime,
+	)
+	if err != nil {
+		return backupTable{}, err
+	}
+	baseDir, err := backuputils.AppendPaths(from, subdir)
+	if err != nil {
+		return backupTable{}, err
+	}
+	incDir, err := backupdest.ResolveIncrementalsBackupLocation(from, subdir)
+	if err != nil {
+		return backupTable{}, err
+	}
+	mkStore := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI
+	baseStores, cleanupFn, err := backupdest.MakeBackupDestinationStores(ctx, p.User(), mkStore, baseDir)
+	if err != nil {
+		return backupTable{}, err
+	}
+	defer besteffort.Cleanup(ctx, "close-base-stores", cleanupFn)
+
+	ioConf := baseStores[0].ExternalIOConf()
+	kmsEnv := backupencryption.MakeBackupKMSEnv(
+		p.ExecCfg().Settings, &ioConf, p.ExecCfg().InternalDB, p.User(),
+	)
+	encryption, err := backupencryption.ResolveEncryptionOptionsFromExpr(
+		ctx, p, p.ExprEvaluator(op), baseStores[0],
+		restoreStmt.Options.EncryptionPassphrase, tree.Exprs(restoreStmt.Options.DecryptionKMSURI),
+	)
+	if err != nil {
+		return backupTable{}, err
+	}
+
+	mem := p.ExecCfg().RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+	_, manifests, _, memReserved, err := backupdest.ResolveBackupManifests(
+		ctx, p.ExecCfg(), &mem, defaultCollectionURI, from, mkStore,
+		subdir, baseDir, incDir, endTime, encryption, &kmsEnv, p.User(),
+		false /* includeSkipped */, restoreCompactedBackups.Get(&p.ExecCfg().Settings.SV),
+	)
+	if err != nil {
+		return backupTable{}, err
+	}
+	defer mem.Shrink(ctx, memReserved)
+
+	if err := checkBackupManifestVersionCompatability(
+		ctx, p.ExecCfg().Settings.Version, manifests, false, /* unsafeRestoreIncompatibleVersion */
+	); err != nil {
+		return backupTable{}, err
+	}
+	// The table is matched to the one in the backup by its ID, which only
+	// identifies the same table in backups of this cluster.
+	if clusterID := p.ExecCfg().NodeInfo.LogicalClusterID(); !manifests[len(manifests)-1].ClusterID.Equal(clusterID) {
+		return backupTable{}, pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s can only restore from backups of this cluster", op)
+	}
+
+	layerToIter, err := backupinfo.GetBackupManifestIterFactories(
+		ctx, p.ExecCfg().DistSQLSrv.ExternalStorage, manifests, encryption, &kmsEnv,
+	)
+	if err != nil {
+		return backupTable{}, err
+	}
+	sqlDescs, _, err := backupinfo.LoadSQLDescsFromBackupsAtTime(ctx, manifests, layerToIter, endTime)
+	if err != nil {
+		return backupTable{}, err
+	}
+	var c nstree.MutableCatalog
+	for _, desc := range sqlDescs {
+		c.UpsertDescriptor(desc)
+	}
+	if err := descs.HydrateCatalog(ctx, c); err != nil {
+		return backupTable{}, err
+	}
+	tbl, ok := c.LookupDescriptor(tableID).(catalog.TableDescriptor)
+	if !ok || !tbl.Public() {
+		return backupTable{}, pgerror.Newf(pgcode.UndefinedTable,
+			"table with ID %d is not in the backup", tableID)
+	}
+	db := c.LookupDescriptor(tbl.GetParentID())
+	if db == nil {
+		return backupTable{}, errors.AssertionFailedf(
+			"database of table %q is not in the backup", tbl.GetName())
+	}
+	// Tables created before user-defined schemas existed are in a public schema
+	// without a descriptor.
+	scName := catconstants.PublicSchemaName
+	if sc := c.LookupDescriptor(tbl.GetParentSchemaID()); sc != nil {
+		scName = sc.GetName()
+	}
+	return backupTable{
+		desc:    tbl,
+		name:    tree.MakeTableNameWithSchema(tree.Name(db.GetName()), tree.Name(scName), tree.Name(tbl.GetName())),
+		subdir:  subdir,
+		endTime: endTime,
+	}, nil
+}
+
+// restoreIndexCreateStatement returns the CREATE INDEX statement which adds
+// the named index of the table in the backup back to the table.
+func restoreIndexCreateStatement(
+	ctx context.Context,
+	p sql.PlanHookState,
+	tn *tree.TableName,
+	table, backupTable catalog.TableDescriptor,
+	indexName string,
+) (string, error) {
+	var idx catalog.Index
+	for _, i := range backupTable.PublicNonPrimaryIndexes() {
+		if i.GetName() == indexName {
+			idx = i
+			break
+		}
+	}
+	if idx == nil {
+		if backupTable.GetPrimaryIndex().GetName() == indexName {
+			return "", pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot restore primary index %q", indexName)
+		}
+		return "", errors.WithHint(
+			pgerror.Newf(pgcode.UndefinedObject,
+				"index %q does not exist on table %q in the backup", indexName, backupTable.GetName()),
+			"use AS OF SYSTEM TIME to restore the index as of a time before it was dropped",
+		)
+	}
+	if part := idx.GetPartitioning(); part.NumColumns() > part.NumImplicitColumns() {
+		return "", pgerror.Newf(pgcode.FeatureNotSupported,
+			"cannot restore partitioned index %q", indexName)
+	}
+
+	// The statement refers to columns by their names in the backup, so each of
+	// them must still be the same column of the table.
+	colIDs, err := restoreIndexReferencedColumns(backupTable, idx)
+	if err != nil {
+		return "", err
+	}
+	for _, id := range colIDs.Ordered() {
+		backupCol := catalog.FindColumnByID(backupTable, id)
+		col := catalog.FindColumnByID(table, id)
+		if col == nil || !col.Public() || col.GetName() != backupCol.GetName() {
+			return "", pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"cannot restore index %q: column %q was dropped or renamed since the backup",
+				indexName, backupCol.GetName())
+		}
+	}
+
+	return catformat.IndexForDisplay(
+		ctx,
+		backupTable,
+		tn,
+		idx,
+		"", /* partition */
+		tree.FmtParsable,
+		&p.ExtendedEvalContext().Context,
+		p.SemaCtx(),
+		p.SessionData(),
+		catformat.IndexDisplayShowCreate,
+	)
+}
+
+// restoreIndexReferencedColumns returns the columns of the table that the
+// definition of the index refers to. The hidden columns which store the
+// expressions of an expression index, or the shard of a hash-sharded index,
+// are created along with the index, so the columns their expressions refer to
+// are returned instead.
+func restoreIndexReferencedColumns(
+	table catalog.TableDescriptor, idx catalog.Index,
+) (catalog.TableColSet, error) {
+	var colIDs catalog.TableColSet
+	addExprColumns := func(expr string) error {
+		e, err := parser.ParseExpr(expr)
+		if err != nil {
+			return err
+		}
+		ids, err := schemaexpr.ExtractColumnIDs(table, e)
+		if err != nil {
+			return err
+		}
+		colIDs.UnionWith(ids)
+		return nil
+	}
+	for i := 0; i < idx.NumKeyColumns(); i++ {
+		col, err := catalog.MustFindColumnByID(table, idx.GetKeyColumnID(i))
+		if err != nil {
+			return catalog.TableColSet{}, err
+		}
+		if col.IsExpressionIndexColumn() ||
+			(idx.IsSharded() && col.GetName() == idx.GetShardColumnName()) {
+			if err := addExprColumns(col.GetComputeExpr()); err != nil {
+				return catalog.TableColSet{}, err
+			}
+			continue
+		}
+		colIDs.Add(col.GetID())
+	}
+	colIDs.UnionWith(idx.CollectSecondaryStoredColumnIDs())
+	if idx.IsPartial() {
+		if err := addExprColumns(idx.GetPredicate()); err != nil {
+			return catalog.TableColSet{}, err
+		}
+	}
+	return colIDs, nil
+}
@@ -60,6 +60,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scbackup"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlclustersettings"
@@ -736,6 +737,7 @@ func restore(
 			exclusiveEndKeys:     true,
 			resumeClusterVersion: resumeClusterVersion,
 			useLink:              useLink,
+			rowFilters:           makeRestoreRowFilterSpecs(details),
 		}
 		return errors.Wrap(distRestore(
 			ctx,
@@ -1095,8 +1097,18 @@ func spansForAllRestoreTableIndexes(
 //     restore with an ImportEpoch is restored with an Offline
 //     table and a revert job is queued that will bring the table
 //     back online.
+//
+//   - An offline table undergoing an IMPORT INTO with mode = 'upsert'
+//     cannot be restored and an error is returned, since the import
+//     overwrote rows whose prior revisions the backup does not contain.
 func checkOfflineDescriptorHandling(desc catalog.Descriptor, onlineRestoreImpl bool) (bool, error) {
 	if backedUpDescriptorWithInProgressImportInto(desc) {
+		if desc.(catalog.TableDescriptor).TableDesc().ImportType == descpb.ImportType_IMPORT_UPSERT {
+			return false, errors.WithHint(
+				errors.Newf("table %s (id %d) in restoring backup has an in-progress upsert import", desc.GetName(), desc.GetID()),
+				"restore from a backup taken before the import started or after it completed",
+			)
+		}
 		if onlineRestoreImpl && !epochBasedInProgressImport(desc) {
 			return false, errors.Newf("table %s (id %d) in restoring backup has an in-progress import, but online restore cannot be run on a table with an in progress import", desc.GetName(), desc.GetID())
 		}
@@ -1362,7 +1374,11 @@ func synthesizeZoneConfigsForPartialRestore(
 //     restore targets. This flow should get executed last and should contain the
 //     bulk of the work, as it is used for job progress tracking.
 func createRestoreFlows(
-	ctx context.Context, r *restoreResumer, backupCodec keys.SQLCodec, sqlDescs []catalog.Descriptor,
+	ctx context.Context,
+	r *restoreResumer,
+	evalCtx *eval.Context,
+	backupCodec keys.SQLCodec,
+	sqlDescs []catalog.Descriptor,
 ) (preRestore restorationData, preValid restorationData, mainRestore restorationData, err error) {
 
 	details := r.job.Details().(jobspb.RestoreDetails)
@@ -1408,6 +1424,10 @@ func createRestoreFlows(
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	postRestoreSpans, err = narrowRestoreSpans(ctx, evalCtx, backupCodec, details, postRestoreSpans)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	var verifySpans []roachpb.Span
 	if details.VerifyData {
 		// verifySpans contains the spans that should be read and checksum'd during a
@@ -2271,7 +2291,9 @@ func (r *restoreResumer) doResume(ctx context.Context, execCtx interface{}) erro
 	if err := createImportingDescriptors(ctx, p, backupDescs, r); err != nil {
 		return err
 	}
-	preData, preValidateData, mainData, err := createRestoreFlows(ctx, r, backupCodec, backupDescs)
+	preData, preValidateData, mainData, err := createRestoreFlows(
+		ctx, r, &p.ExtendedEvalContext().Context, backupCodec, backupDescs,
+	)
 	if err != nil {
 		return err
 	}
@@ -2902,6 +2924,21 @@ func insertStats(
 	if details.StatsInserted {
 		return nil
 	}
+	if len(details.RowFilters) > 0 || len(details.ProjectedTables) > 0 {
+		// The stats in the backup describe all the rows and columns of the table,
+		// rather than the ones which were restored.
+		filtered := make([]*stats.TableStatisticProto, 0, len(latestStats))
+		for _, stat := range latestStats {
+			if _, ok := details.RowFilters[stat.TableID]; ok {
+				continue
+			}
+			if slices.Contains(details.ProjectedTables, stat.TableID) {
+				continue
+			}
+			filtered = append(filtered, stat)
+		}
+		latestStats = filtered
+	}
 	if len(latestStats) == 0 {
 		return nil
 	}
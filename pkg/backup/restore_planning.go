@@ -76,6 +76,8 @@ const (
 	restoreOptSkipLocalitiesCheck       = "skip_localities_check"
 	restoreOptAsTenant                  = "virtual_cluster_name"
 	restoreOptForceTenantID             = "virtual_cluster"
+	restoreOptRowFilter                 = "row_filter"
+	restoreOptColumns                   = "columns"
 )
 
 // testFastRestore is a hook set by backup_test.go to enable OR for all
@@ -1242,6 +1244,8 @@ func resolveOptionsForRestoreJobDescription(
 		ExperimentalCopy:                 opts.ExperimentalCopy,
 		RemoveRegions:                    opts.RemoveRegions,
 		Grants:                           opts.Grants,
+		RowFilter:                        opts.RowFilter,
+		Columns:                          opts.Columns,
 	}
 
 	if opts.EncryptionPassphrase != nil {
@@ -1324,6 +1328,12 @@ func restoreTypeCheck(
 	if !ok {
 		return false, nil, nil
 	}
+	if restoreStmt.Index != nil {
+		return restoreIndexTypeCheck(ctx, restoreStmt, p)
+	}
+	if restoreStmt.Column != nil {
+		return restoreColumnTypeCheck(ctx, restoreStmt, p)
+	}
 	if testFastRestore() && !restoreStmt.Options.ExperimentalCopy && !restoreStmt.Options.ExperimentalOnline {
 		restoreStmt.Options.ExperimentalCopy = true
 	}
@@ -1332,6 +1342,7 @@ func restoreTypeCheck(
 		exprutil.StringArrays{
 			tree.Exprs(restoreStmt.From),
 			tree.Exprs(restoreStmt.Options.DecryptionKMSURI),
+			tree.Exprs(restoreStmt.Options.Columns),
 		},
 		exprutil.Strings{
 			restoreStmt.Subdir,
@@ -1341,6 +1352,7 @@ func restoreTypeCheck(
 			restoreStmt.Options.ForceTenantID,
 			restoreStmt.Options.AsTenant,
 			restoreStmt.Options.ExecutionLocality,
+			restoreStmt.Options.RowFilter,
 		},
 	); err != nil {
 		return false, nil, err
@@ -1371,6 +1383,12 @@ func restorePlanHook(
 	if !ok {
 		return nil, nil, false, nil
 	}
+	if restoreStmt.Index != nil {
+		return restoreIndexPlanHook(ctx, restoreStmt, p)
+	}
+	if restoreStmt.Column != nil {
+		return restoreColumnPlanHook(ctx, restoreStmt, p)
+	}
 	if testFastRestore() && !restoreStmt.Options.ExperimentalCopy && !restoreStmt.Options.ExperimentalOnline {
 		restoreStmt.Options.ExperimentalCopy = true
 	}
@@ -1466,6 +1484,47 @@ func restorePlanHook(
 		}
 	}
 
+	var rowFilter string
+	var columns []string
+	if restoreStmt.Options.RowFilter != nil || restoreStmt.Options.Columns != nil {
+		opt := restoreOptRowFilter
+		if restoreStmt.Options.RowFilter == nil {
+			opt = restoreOptColumns
+		}
+		if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V26_3) {
+			return nil, nil, false, pgerror.Newf(pgcode.FeatureNotSupported,
+				"RESTORE ... WITH %s requires all nodes to be upgraded to %v",
+				opt, clusterversion.V26_3.Version())
+		}
+		if restoreStmt.DescriptorCoverage != tree.RequestedDescriptors ||
+			restoreStmt.Targets.Databases != nil {
+			return nil, nil, false, errors.Newf("%s can only be used with RESTORE TABLE", opt)
+		}
+		if restoreStmt.Options.SchemaOnly {
+			return nil, nil, false, errors.Newf("cannot set %s option with schema_only", opt)
+		}
+		if restoreStmt.Options.OnlineImpl() {
+			return nil, nil, false, errors.Newf("cannot run online restore with %s", opt)
+		}
+	}
+	if restoreStmt.Options.RowFilter != nil {
+		var err error
+		rowFilter, err = exprEval.String(ctx, restoreStmt.Options.RowFilter)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if rowFilter == "" {
+			return nil, nil, false, errors.New("row_filter cannot be empty")
+		}
+	}
+	if restoreStmt.Options.Columns != nil {
+		var err error
+		columns, err = exprEval.StringArray(ctx, tree.Exprs(restoreStmt.Options.Columns))
+		if err != nil {
+			return nil, nil, false, err
+		}
+	}
+
 	var newTenantID *roachpb.TenantID
 	var newTenantName *roachpb.TenantName
 	if restoreStmt.Options.AsTenant != nil || restoreStmt.Options.ForceTenantID != nil {
@@ -1546,7 +1605,7 @@ func restorePlanHook(
 
 		return doRestorePlan(
 			ctx, restoreStmt, exprEval, p, from, intoDB, newDBName, newTenantID,
-			newTenantName, endTime, resultsCh, backupToken, execLocality,
+			newTenantName, endTime, resultsCh, backupToken, execLocality, rowFilter, columns,
 		)
 	}
 
@@ -1787,6 +1846,8 @@ func doRestorePlan(
 	resultsCh chan<- tree.Datums,
 	backupToken string,
 	execLocality roachpb.Locality,
+	rowFilter string,
+	columns []string,
 ) error {
 	if len(from) == 0 {
 		return errors.New("invalid base backup specified")
@@ -2137,6 +2198,32 @@ func doRestorePlan(
 		return errors.Wrapf(err, "function descriptor rewrite failed")
 	}
 
+	var rowFilters map[descpb.ID]string
+	var projectedTables []descpb.ID
+	if rowFilter != "" || columns != nil {
+		opt := restoreOptRowFilter
+		if rowFilter == "" {
+			opt = restoreOptColumns
+		}
+		if len(tables) != 1 {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"%s can only be used to restore a single table, found %d", opt, len(tables))
+		}
+		if rowFilter != "" {
+			expr, err := validateRestoreRowFilter(ctx, p, tables[0], rowFilter)
+			if err != nil {
+				return err
+			}
+			rowFilters = map[descpb.ID]string{tables[0].GetID(): expr}
+		}
+		if columns != nil {
+			if err := projectRestoreTable(tables[0], columns); err != nil {
+				return err
+			}
+			projectedTables = []descpb.ID{tables[0].GetID()}
+		}
+	}
+
 	encodedTables := make([]*descpb.TableDescriptor, len(tables))
 	for i, table := range tables {
 		encodedTables[i] = table.TableDesc()
@@ -2172,6 +2259,8 @@ func doRestorePlan(
 		UnsafeRestoreIncompatibleVersion: restoreStmt.Options.UnsafeRestoreIncompatibleVersion,
 		TempSystemID:                     tempSysDBID,
 		Grants:                           restoreStmt.Options.Grants,
+		RowFilters:                       rowFilters,
+		ProjectedTables:                  projectedTables,
 	}
 
 	jr := jobs.Record{
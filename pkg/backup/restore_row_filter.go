@@ -0,0 +1,725 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/keyside"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/idxtype"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// validateRestoreRowFilter checks that the rows restored into a table can be
+// filtered with the given predicate and returns its serialized form.
+//
+// Every key of a table, be it of its primary index or of a secondary index,
+// encodes the primary key of its row, so restricting the predicate to primary
+// key columns lets the restore data processors evaluate it on each key on its
+// own, without reassembling rows.
+func validateRestoreRowFilter(
+	ctx context.Context, p sql.PlanHookState, table catalog.TableDescriptor, filter string,
+) (string, error) {
+	if table.IsView() || table.IsSequence() {
+		return "", pgerror.Newf(pgcode.WrongObjectType,
+			"row_filter can only be used to restore a table, and %q is not one", table.GetName())
+	}
+	if len(table.AllMutations()) > 0 {
+		return "", pgerror.Newf(pgcode.FeatureNotSupported,
+			"row_filter is not supported for tables with schema changes in progress")
+	}
+	for _, idx := range table.AllIndexes() {
+		if typ := idx.GetType(); typ != idxtype.FORWARD {
+			return "", pgerror.Newf(pgcode.FeatureNotSupported,
+				"row_filter is not supported for tables with %s indexes", strings.ToLower(typ.String()))
+		}
+	}
+
+	e, err := parser.ParseExpr(filter)
+	if err != nil {
+		return "", pgerror.Wrapf(err, pgcode.Syntax, "parsing row_filter")
+	}
+	tn := tree.NewUnqualifiedTableName(tree.Name(table.GetName()))
+	expr, cols, err := schemaexpr.ValidateRowFilter(
+		ctx, table, e, tree.RestoreRowFilterExpr, tn, p.SemaCtx(),
+		p.ExecCfg().Settings.Version.ActiveVersion(ctx),
+	)
+	if err != nil {
+		return "", err
+	}
+	udfIDs, err := schemaexpr.GetUDFIDsFromExprStr(expr)
+	if err != nil {
+		return "", err
+	}
+	if !udfIDs.Empty() {
+		return "", pgerror.New(pgcode.FeatureNotSupported,
+			"row_filter cannot reference user-defined functions")
+	}
+
+	pkCols := table.GetPrimaryIndex().CollectKeyColumnIDs()
+	for _, colID := range cols.Ordered() {
+		col, err := catalog.MustFindColumnByID(table, colID)
+		if err != nil {
+			return "", err
+		}
+		if !pkCols.Contains(colID) {
+			return "", pgerror.Newf(pgcode.FeatureNotSupported,
+				"row_filter can only reference primary key columns, and %q is not one", col.GetName())
+		}
+		if typ := col.GetType(); typ.UserDefined() || colinfo.CanHaveCompositeKeyEncoding(typ) {
+			return "", pgerror.Newf(pgcode.FeatureNotSupported,
+				"row_filter cannot reference column %q of type %s", col.GetName(), typ.SQLString())
+		}
+	}
+	return expr, nil
+}
+
+// projectRestoreTable removes the columns of a table which aren't restored,
+// along with the indexes, column families and constraints which depend on
+// them. The primary key columns of the table are always restored, as are its
+// inaccessible computed columns backing expression and hash-sharded indexes if
+// the columns they are computed from are.
+func projectRestoreTable(table *tabledesc.Mutable, columns []string) error {
+	if table.IsView() || table.IsSequence() {
+		return pgerror.Newf(pgcode.WrongObjectType,
+			"columns can only be used to restore a table, and %q is not one", table.GetName())
+	}
+	if len(table.AllMutations()) > 0 {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"columns is not supported for tables with schema changes in progress")
+	}
+	if len(table.Triggers) > 0 || len(table.Policies) > 0 {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"columns is not supported for tables with triggers or row-level security policies")
+	}
+	if table.HasRowLevelTTL() {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"columns is not supported for tables with row-level TTL")
+	}
+
+	pkCols := table.GetPrimaryIndex().CollectKeyColumnIDs()
+	keep := pkCols.Copy()
+	var requested catalog.TableColSet
+	for _, name := range columns {
+		col := catalog.FindColumnByName(table, name)
+		if col == nil || !col.Public() || col.IsInaccessible() || col.IsSystemColumn() {
+			return pgerror.Newf(pgcode.UndefinedColumn, "column %q does not exist", name)
+		}
+		if requested.Contains(col.GetID()) {
+			return pgerror.Newf(pgcode.DuplicateColumn, "column %q specified more than once", name)
+		}
+		requested.Add(col.GetID())
+		keep.Add(col.GetID())
+	}
+
+	refs := make(map[descpb.ColumnID]catalog.TableColSet)
+	for _, col := range table.PublicColumns() {
+		if !col.IsComputed() {
+			continue
+		}
+		expr, err := parser.ParseExpr(col.GetComputeExpr())
+		if err != nil {
+			return errors.Wrapf(err, "parsing computed column %q", col.GetName())
+		}
+		if refs[col.GetID()], err = schemaexpr.ExtractColumnIDs(table, expr); err != nil {
+			return err
+		}
+		if col.IsInaccessible() {
+			keep.Add(col.GetID())
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, col := range table.PublicColumns() {
+			colRefs, ok := refs[col.GetID()]
+			if !ok || !keep.Contains(col.GetID()) || colRefs.SubsetOf(keep) {
+				continue
+			}
+			if col.IsInaccessible() && !pkCols.Contains(col.GetID()) {
+				keep.Remove(col.GetID())
+				changed = true
+				continue
+			}
+			missing, err := catalog.MustFindColumnByID(table, colRefs.Difference(keep).Ordered()[0])
+			if err != nil {
+				return err
+			}
+			return pgerror.Newf(pgcode.InvalidColumnReference,
+				"column %q is computed from column %q, which is not restored", col.GetName(), missing.GetName())
+		}
+	}
+
+	// Find which indexes and constraints are dropped before removing any column,
+	// as the expressions of their predicates are resolved against the table.
+	keeps := func(cols catalog.TableColSet, predicate string) (bool, error) {
+		if !cols.SubsetOf(keep) {
+			return false, nil
+		}
+		if predicate == "" {
+			return true, nil
+		}
+		expr, err := parser.ParseExpr(predicate)
+		if err != nil {
+			return false, errors.Wrap(err, "parsing predicate")
+		}
+		predCols, err := schemaexpr.ExtractColumnIDs(table, expr)
+		if err != nil {
+			return false, err
+		}
+		return predCols.SubsetOf(keep), nil
+	}
+	indexes := table.Indexes[:0:0]
+	for _, idx := range table.Indexes {
+		cols := catalog.MakeTableColSet(idx.KeyColumnIDs...)
+		cols.UnionWith(catalog.MakeTableColSet(idx.StoreColumnIDs...))
+		ok, err := keeps(cols, idx.Predicate)
+		if err != nil {
+			return err
+		}
+		if ok {
+			indexes = append(indexes, idx)
+		}
+	}
+	uniqueWithoutIndexConstraints := table.UniqueWithoutIndexConstraints[:0:0]
+	for _, c := range table.UniqueWithoutIndexConstraints {
+		ok, err := keeps(catalog.MakeTableColSet(c.ColumnIDs...), c.Predicate)
+		if err != nil {
+			return err
+		}
+		if ok {
+			uniqueWithoutIndexConstraints = append(uniqueWithoutIndexConstraints, c)
+		}
+	}
+	checks := table.Checks[:0:0]
+	for _, c := range table.Checks {
+		if catalog.MakeTableColSet(c.ColumnIDs...).SubsetOf(keep) {
+			checks = append(checks, c)
+		}
+	}
+	outboundFKs := table.OutboundFKs[:0:0]
+	for _, fk := range table.OutboundFKs {
+		if catalog.MakeTableColSet(fk.OriginColumnIDs...).SubsetOf(keep) {
+			outboundFKs = append(outboundFKs, fk)
+		}
+	}
+
+	cols := table.Columns[:0:0]
+	for _, col := range table.Columns {
+		if keep.Contains(col.ID) {
+			cols = append(cols, col)
+		}
+	}
+	families := table.Families[:0:0]
+	for _, family := range table.Families {
+		familyCols, familyColNames := family.ColumnIDs[:0:0], family.ColumnNames[:0:0]
+		for i, id := range family.ColumnIDs {
+			if keep.Contains(id) {
+				familyCols = append(familyCols, id)
+				familyColNames = append(familyColNames, family.ColumnNames[i])
+			}
+		}
+		if len(familyCols) == 0 && family.ID != 0 {
+			continue
+		}
+		family.ColumnIDs, family.ColumnNames = familyCols, familyColNames
+		if !keep.Contains(family.DefaultColumnID) {
+			family.DefaultColumnID = 0
+		}
+		families = append(families, family)
+	}
+	pk := &table.PrimaryIndex
+	storeCols, storeColNames := pk.StoreColumnIDs[:0:0], pk.StoreColumnNames[:0:0]
+	for i, id := range pk.StoreColumnIDs {
+		if keep.Contains(id) {
+			storeCols = append(storeCols, id)
+			storeColNames = append(storeColNames, pk.StoreColumnNames[i])
+		}
+	}
+	pk.StoreColumnIDs, pk.StoreColumnNames = storeCols, storeColNames
+
+	table.Columns = cols
+	table.Families = families
+	table.Indexes = indexes
+	table.UniqueWithoutIndexConstraints = uniqueWithoutIndexConstraints
+	table.Checks = checks
+	table.OutboundFKs = outboundFKs
+	return nil
+}
+
+// makeRestoreRowFilterSpecs returns the row filters of the tables restored by
+// a job, including those of the tables of which only a subset of the columns
+// is restored.
+func makeRestoreRowFilterSpecs(details jobspb.RestoreDetails) []execinfrapb.RestoreRowFilter {
+	if len(details.RowFilters) == 0 && len(details.ProjectedTables) == 0 {
+		return nil
+	}
+	var filters []execinfrapb.RestoreRowFilter
+	for _, table := range details.TableDescs {
+		expr, filtered := details.RowFilters[table.ID]
+		projected := slices.Contains(details.ProjectedTables, table.ID)
+		if filtered || projected {
+			filters = append(filters, execinfrapb.RestoreRowFilter{
+				Table: *table, Expr: expr, Projected: projected,
+			})
+		}
+	}
+	return filters
+}
+
+// maxRestoreRowFilterSpans is the maximum number of primary key prefixes a row
+// filter is narrowed to, past which the whole primary index is read.
+const maxRestoreRowFilterSpans = 10000
+
+// narrowRestoreSpans restricts the spans restored from, which are in the
+// keyspace of the backup, to those which may hold keys restored into the
+// tables with a row filter or a subset of their columns. The indexes missing
+// from the restored descriptors of these tables are skipped, and the spans of
+// their primary index are limited to the primary key prefixes their row filter
+// constrains to, if any.
+func narrowRestoreSpans(
+	ctx context.Context,
+	evalCtx *eval.Context,
+	backupCodec keys.SQLCodec,
+	details jobspb.RestoreDetails,
+	spans []roachpb.Span,
+) ([]roachpb.Span, error) {
+	if len(details.RowFilters) == 0 && len(details.ProjectedTables) == 0 {
+		return spans, nil
+	}
+	newIDToOldID := make(map[descpb.ID]descpb.ID, len(details.DescriptorRewrites))
+	for oldID, rewrite := range details.DescriptorRewrites {
+		newIDToOldID[rewrite.ID] = oldID
+	}
+
+	var tableSpans, narrowed []roachpb.Span
+	for _, desc := range details.TableDescs {
+		expr, filtered := details.RowFilters[desc.ID]
+		if !filtered && !slices.Contains(details.ProjectedTables, desc.ID) {
+			continue
+		}
+		oldID, ok := newIDToOldID[desc.ID]
+		if !ok {
+			return nil, errors.AssertionFailedf("no descriptor rewrite to table %d", desc.ID)
+		}
+		tableSpans = append(tableSpans, backupCodec.TableSpan(uint32(oldID)))
+		table := tabledesc.NewBuilder(desc).BuildImmutableTable()
+		for _, idx := range table.ActiveIndexes() {
+			if idx.Primary() && filtered {
+				pkSpans, err := restoreRowFilterPrimaryKeySpans(ctx, evalCtx, backupCodec, oldID, table, expr)
+				if err != nil {
+					return nil, err
+				}
+				if pkSpans != nil {
+					narrowed = append(narrowed, pkSpans...)
+					continue
+				}
+			}
+			prefix := backupCodec.IndexPrefix(uint32(oldID), uint32(idx.GetID()))
+			narrowed = append(narrowed, roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()})
+		}
+	}
+	for _, sp := range spans {
+		if !slices.ContainsFunc(tableSpans, func(tableSpan roachpb.Span) bool {
+			return tableSpan.Contains(sp)
+		}) {
+			narrowed = append(narrowed, sp)
+		}
+	}
+	slices.SortFunc(narrowed, func(a, b roachpb.Span) int {
+		return a.Key.Compare(b.Key)
+	})
+	return narrowed, nil
+}
+
+// restoreRowFilterPrimaryKeySpans returns the spans, under the given table ID,
+// of the primary key prefixes a row filter constrains the rows of a table to,
+// or nil if it doesn't constrain the first primary key column. Only the
+// conjuncts of the filter which constrain a primary key column to a value, or
+// to a list of values, narrow the spans.
+func restoreRowFilterPrimaryKeySpans(
+	ctx context.Context,
+	evalCtx *eval.Context,
+	codec keys.SQLCodec,
+	tableID descpb.ID,
+	table catalog.TableDescriptor,
+	filter string,
+) ([]roachpb.Span, error) {
+	semaCtx := tree.MakeSemaContext(nil /* resolver */)
+	expr, err := schemaexpr.MakeRowFilterExpr(ctx, table, filter, evalCtx, &semaCtx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "building row filter of table %s", table.GetName())
+	}
+
+	// Collect the values each column is constrained to by the conjuncts of the
+	// filter.
+	cols := table.PublicColumns()
+	constraints := make(map[descpb.ColumnID][]tree.Datum)
+	var collect func(e tree.TypedExpr)
+	collect = func(e tree.TypedExpr) {
+		switch e := e.(type) {
+		case *tree.AndExpr:
+			collect(e.TypedLeft())
+			collect(e.TypedRight())
+		case *tree.ParenExpr:
+			collect(e.TypedInnerExpr())
+		case *tree.ComparisonExpr:
+			v, ok := e.Left.(*tree.IndexedVar)
+			if !ok || v.Idx >= len(cols) {
+				return
+			}
+			col := cols[v.Idx]
+			var vals []tree.Datum
+			switch e.Operator.Symbol {
+			case treecmp.EQ:
+				d, ok := e.Right.(tree.Datum)
+				if !ok {
+					return
+				}
+				vals = []tree.Datum{d}
+			case treecmp.In:
+				t, ok := e.Right.(*tree.DTuple)
+				if !ok {
+					return
+				}
+				vals = t.D
+			default:
+				return
+			}
+			var res []tree.Datum
+			for _, d := range vals {
+				if d == tree.DNull {
+					continue
+				}
+				if d.ResolvedType().Family() != col.GetType().Family() {
+					return
+				}
+				res = append(res, d)
+			}
+			// A column constrained by several conjuncts is constrained to the
+			// values of any one of them, which is enough to narrow the spans.
+			if _, ok := constraints[col.GetID()]; !ok {
+				constraints[col.GetID()] = res
+			}
+		}
+	}
+	collect(expr)
+
+	pk := table.GetPrimaryIndex()
+	prefixes := [][]byte{codec.IndexPrefix(uint32(tableID), uint32(pk.GetID()))}
+	for i := 0; i < pk.NumKeyColumns(); i++ {
+		vals, ok := constraints[pk.GetKeyColumnID(i)]
+		if !ok || len(prefixes)*len(vals) > maxRestoreRowFilterSpans {
+			break
+		}
+		dir, err := catalogkeys.IndexColumnEncodingDirection(pk.GetKeyColumnDirection(i))
+		if err != nil {
+			return nil, err
+		}
+		next := make([][]byte, 0, len(prefixes)*len(vals))
+		for _, prefix := range prefixes {
+			for _, d := range vals {
+				key, err := keyside.Encode(prefix[:len(prefix):len(prefix)], d, dir)
+				if err != nil {
+					return nil, err
+				}
+				next = append(next, key)
+			}
+		}
+		prefixes = next
+	}
+	if _, ok := constraints[pk.GetKeyColumnID(0)]; !ok {
+		return nil, nil
+	}
+
+	spans := make([]roachpb.Span, len(prefixes))
+	for i, prefix := range prefixes {
+		spans[i] = roachpb.Span{Key: prefix, EndKey: roachpb.Key(prefix).PrefixEnd()}
+	}
+	return spans, nil
+}
+
+// restoreRowFilter decides which keys of the tables with a row filter, or of
+// which only a subset of the columns is restored, are restored, and with which
+// values. It is not safe for concurrent use.
+type restoreRowFilter struct {
+	codec   keys.SQLCodec
+	evalCtx *eval.Context
+	tables  map[descpb.ID]*restoreRowFilterTable
+
+	// lastRowPrefix is the prefix, without the column family, of the last key
+	// which was filtered, and lastRowMatched is whether it was restored. All the
+	// keys of a row share their prefix and are adjacent, so this saves decoding
+	// and evaluating the predicate for each of its column families, and lets the
+	// keys of the other families of the rows of unique indexes, which don't
+	// encode the primary key, follow the one of the first family.
+	lastRowPrefix  roachpb.Key
+	lastRowMatched bool
+}
+
+type restoreRowFilterTable struct {
+	// expr is nil if all the rows of the table are restored.
+	expr    tree.TypedExpr
+	ivars   schemaexpr.RowIndexedVarContainer
+	indexes map[descpb.IndexID]restoreRowFilterIndex
+	vals    []rowenc.EncDatum
+	alloc   tree.DatumAlloc
+
+	// projected is set if only the columns and column families of the table
+	// descriptor are restored.
+	projected      bool
+	primaryIndexID descpb.IndexID
+	cols           catalog.TableColSet
+	families       map[descpb.FamilyID]struct{}
+}
+
+type restoreRowFilterIndex struct {
+	// cols are the key columns of the index followed by its key suffix
+	// columns.
+	cols       []fetchpb.IndexFetchSpec_KeyColumn
+	numKeyCols int
+	unique     bool
+}
+
+// makeRestoreRowFilter returns a filter of the keys restored into the tables
+// of the given row filters, or nil if there are none.
+func makeRestoreRowFilter(
+	ctx context.Context,
+	codec keys.SQLCodec,
+	evalCtx *eval.Context,
+	filters []execinfrapb.RestoreRowFilter,
+) (*restoreRowFilter, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+	f := &restoreRowFilter{
+		codec:   codec,
+		evalCtx: evalCtx,
+		tables:  make(map[descpb.ID]*restoreRowFilterTable, len(filters)),
+	}
+	for i := range filters {
+		table := tabledesc.NewBuilder(&filters[i].Table).BuildImmutableTable()
+		t := &restoreRowFilterTable{
+			indexes:        make(map[descpb.IndexID]restoreRowFilterIndex),
+			projected:      filters[i].Projected,
+			primaryIndexID: table.GetPrimaryIndexID(),
+			families:       make(map[descpb.FamilyID]struct{}),
+		}
+		if filters[i].Expr != "" {
+			semaCtx := tree.MakeSemaContext(nil /* resolver */)
+			expr, err := schemaexpr.MakeRowFilterExpr(ctx, table, filters[i].Expr, evalCtx, &semaCtx)
+			if err != nil {
+				return nil, errors.Wrapf(err, "building row filter of table %s", table.GetName())
+			}
+			t.expr = expr
+		}
+		var vals []tree.Datum
+		t.ivars.Cols = table.PublicColumns()
+		for _, col := range table.GetPrimaryIndex().IndexDesc().KeyColumnIDs {
+			t.ivars.Mapping.Set(col, len(vals))
+			vals = append(vals, nil)
+		}
+		t.ivars.CurSourceRow = vals
+		for _, idx := range table.AllIndexes() {
+			cols := table.IndexFetchSpecKeyAndSuffixColumns(idx)
+			t.indexes[idx.GetID()] = restoreRowFilterIndex{
+				cols:       cols,
+				numKeyCols: idx.NumKeyColumns(),
+				unique:     idx.IsUnique(),
+			}
+			if len(cols) > len(t.vals) {
+				t.vals = make([]rowenc.EncDatum, len(cols))
+			}
+		}
+		for _, col := range table.PublicColumns() {
+			t.cols.Add(col.GetID())
+		}
+		for _, family := range table.GetFamilies() {
+			t.families[family.ID] = struct{}{}
+		}
+		f.tables[table.GetID()] = t
+	}
+	return f, nil
+}
+
+// filter returns whether a key, which has already been rewritten, is restored,
+// and the value it is restored with.
+func (f *restoreRowFilter) filter(
+	ctx context.Context, key roachpb.Key, value roachpb.Value,
+) (_ roachpb.Value, keep bool, _ error) {
+	rest, err := f.codec.StripTenantPrefix(key)
+	if err != nil {
+		return value, false, err
+	}
+	rest, tableID, indexID, err := rowenc.DecodePartialTableIDIndexID(rest)
+	if err != nil {
+		return value, false, err
+	}
+	t, ok := f.tables[tableID]
+	if !ok {
+		return value, true, nil
+	}
+	idx, ok := t.indexes[indexID]
+	if !ok {
+		if t.projected {
+			// The index depends on a column which isn't restored.
+			return value, false, nil
+		}
+		return value, false, errors.AssertionFailedf("unknown index %d of table %d", indexID, tableID)
+	}
+	if t.expr != nil {
+		if matched, err := f.matches(ctx, t, idx, key, rest, value); err != nil || !matched {
+			return value, false, err
+		}
+	}
+	if t.projected && indexID == t.primaryIndexID {
+		return t.project(key, value)
+	}
+	return value, true, nil
+}
+
+// matches returns whether the row of a key matches the row filter of its
+// table, given the rest of the key after its index ID.
+func (f *restoreRowFilter) matches(
+	ctx context.Context,
+	t *restoreRowFilterTable,
+	idx restoreRowFilterIndex,
+	key roachpb.Key,
+	rest []byte,
+	value roachpb.Value,
+) (bool, error) {
+	prefixLen, err := keys.GetRowPrefixLength(key)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(key[:prefixLen], f.lastRowPrefix) {
+		return f.lastRowMatched, nil
+	}
+
+	vals := t.vals[:len(idx.cols)]
+	rest, foundNull, err := rowenc.DecodeKeyValsUsingSpec(
+		idx.cols[:idx.numKeyCols], rest, vals[:idx.numKeyCols],
+	)
+	if err != nil {
+		return false, err
+	}
+	if len(idx.cols) > idx.numKeyCols {
+		// The key suffix columns of a unique index are only part of the key if
+		// one of its key columns is NULL, and are otherwise stored in the value
+		// of the first column family.
+		if idx.unique && !foundNull {
+			if familyID, err := keys.DecodeFamilyKey(key); err != nil {
+				return false, err
+			} else if familyID != 0 {
+				return false, errors.AssertionFailedf(
+					"row of key %s does not start with the first column family", key)
+			}
+			if rest, err = value.GetBytes(); err != nil {
+				return false, err
+			}
+		}
+		if _, _, err := rowenc.DecodeKeyValsUsingSpec(
+			idx.cols[idx.numKeyCols:], rest, vals[idx.numKeyCols:],
+		); err != nil {
+			return false, err
+		}
+	}
+
+	for i := range idx.cols {
+		rowIdx, ok := t.ivars.Mapping.Get(idx.cols[i].ColumnID)
+		if !ok {
+			continue
+		}
+		if err := vals[i].EnsureDecoded(idx.cols[i].Type, &t.alloc); err != nil {
+			return false, err
+		}
+		t.ivars.CurSourceRow[rowIdx] = vals[i].Datum
+	}
+	f.evalCtx.PushIVarContainer(&t.ivars)
+	d, err := eval.Expr(ctx, f.evalCtx, t.expr)
+	f.evalCtx.PopIVarContainer()
+	if err != nil {
+		return false, errors.Wrap(err, "evaluating row filter")
+	}
+
+	f.lastRowPrefix = append(f.lastRowPrefix[:0], key[:prefixLen]...)
+	f.lastRowMatched = d == tree.DBoolTrue
+	return f.lastRowMatched, nil
+}
+
+// project returns the value of a primary index key without the columns which
+// aren't restored, and whether the key is restored at all: the keys of the
+// column families which aren't restored are dropped, as are those of the
+// families other than the first left without any column, which are only
+// written for rows with a non-NULL column in them.
+func (t *restoreRowFilterTable) project(
+	key roachpb.Key, value roachpb.Value,
+) (_ roachpb.Value, keep bool, _ error) {
+	familyID, err := keys.DecodeFamilyKey(key)
+	if err != nil {
+		return value, false, err
+	}
+	if _, ok := t.families[descpb.FamilyID(familyID)]; !ok {
+		return value, false, nil
+	}
+	if value.GetTag() != roachpb.ValueType_TUPLE {
+		// The value of a family with a single column isn't a tuple, and the family
+		// is only restored if the column is.
+		return value, true, nil
+	}
+	b, err := value.GetTuple()
+	if err != nil {
+		return value, false, err
+	}
+	var buf []byte
+	var colID, lastID uint32
+	for len(b) > 0 {
+		_, dataOffset, colIDDelta, typ, err := encoding.DecodeValueTag(b)
+		if err != nil {
+			return value, false, err
+		}
+		n, err := encoding.PeekValueLengthWithOffsetsAndType(b, dataOffset, typ)
+		if err != nil {
+			return value, false, err
+		}
+		colID += colIDDelta
+		if t.cols.Contains(descpb.ColumnID(colID)) {
+			buf = encoding.EncodeValueTag(buf, colID-lastID, typ)
+			buf = append(buf, b[dataOffset:n]...)
+			lastID = colID
+		}
+		b = b[n:]
+	}
+	if len(buf) == 0 && familyID != 0 {
+		return value, false, nil
+	}
+	var res roachpb.Value
+	res.SetTuple(buf)
+	res.Timestamp = value.Timestamp
+	return res, true, nil
+}
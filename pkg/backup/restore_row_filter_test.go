@@ -0,0 +1,184 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package backup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func makeRestoreRowFilterTestTable(t *testing.T, schema string) *tabledesc.Mutable {
+	table, err := sql.CreateTestTableDescriptor(
+		context.Background(), 100 /* parentID */, 104 /* id */, schema,
+		catpb.NewBasePrivilegeDescriptor(username.RootUserName()), nil /* txn */, nil, /* collection */
+	)
+	require.NoError(t, err)
+	return table
+}
+
+func TestProjectRestoreTable(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const schema = `CREATE TABLE t (
+		a INT PRIMARY KEY,
+		b INT,
+		c INT,
+		d STRING,
+		e INT AS (b + 1) STORED,
+		INDEX b_idx (b),
+		INDEX c_idx (c) STORING (d),
+		INDEX expr_idx ((c * 2)),
+		CONSTRAINT c_check CHECK (c > 0),
+		FAMILY f0 (a, b),
+		FAMILY f1 (c, d),
+		FAMILY f2 (e)
+	)`
+
+	t.Run("subset", func(t *testing.T) {
+		table := makeRestoreRowFilterTestTable(t, schema)
+		require.NoError(t, projectRestoreTable(table, []string{"b"}))
+
+		var cols, indexes, families []string
+		for _, col := range table.Columns {
+			cols = append(cols, col.Name)
+		}
+		for _, idx := range table.Indexes {
+			indexes = append(indexes, idx.Name)
+		}
+		for _, family := range table.Families {
+			families = append(families, family.Name)
+		}
+		require.Equal(t, []string{"a", "b"}, cols)
+		require.Equal(t, []string{"b_idx"}, indexes)
+		require.Equal(t, []string{"f0"}, families)
+		require.Empty(t, table.Checks)
+		require.Equal(t, []descpb.ColumnID{2}, table.PrimaryIndex.StoreColumnIDs)
+	})
+
+	t.Run("computed", func(t *testing.T) {
+		table := makeRestoreRowFilterTestTable(t, schema)
+		require.NoError(t, projectRestoreTable(table, []string{"b", "e"}))
+		require.Len(t, table.Columns, 3)
+
+		table = makeRestoreRowFilterTestTable(t, schema)
+		require.ErrorContains(t, projectRestoreTable(table, []string{"e"}),
+			`column "e" is computed from column "b", which is not restored`)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		table := makeRestoreRowFilterTestTable(t, schema)
+		require.ErrorContains(t, projectRestoreTable(table, []string{"nope"}),
+			`column "nope" does not exist`)
+		require.ErrorContains(t, projectRestoreTable(table, []string{"b", "b"}),
+			`column "b" specified more than once`)
+	})
+}
+
+func TestRestoreRowFilterPrimaryKeySpans(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	evalCtx := eval.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+	table := makeRestoreRowFilterTestTable(t,
+		`CREATE TABLE t (tenant INT, k INT, v STRING, PRIMARY KEY (tenant, k DESC))`)
+	codec := keys.SystemSQLCodec
+	const oldID = descpb.ID(52)
+
+	prefix := func(tenant int64, k ...int64) roachpb.Span {
+		key := encoding.EncodeVarintAscending(codec.IndexPrefix(uint32(oldID), 1), tenant)
+		for _, k := range k {
+			key = encoding.EncodeVarintDescending(key, k)
+		}
+		return roachpb.Span{Key: key, EndKey: roachpb.Key(key).PrefixEnd()}
+	}
+
+	for _, tc := range []struct {
+		filter string
+		exp    []roachpb.Span
+	}{
+		{filter: "tenant = 1", exp: []roachpb.Span{prefix(1)}},
+		{filter: "tenant IN (2, 1)", exp: []roachpb.Span{prefix(1), prefix(2)}},
+		{filter: "tenant = 1 AND k IN (3, 4)", exp: []roachpb.Span{prefix(1, 3), prefix(1, 4)}},
+		{filter: "tenant = 1 AND k > 3", exp: []roachpb.Span{prefix(1)}},
+		{filter: "tenant = 1 AND k = 3 AND tenant = 1", exp: []roachpb.Span{prefix(1, 3)}},
+		{filter: "k = 3", exp: nil},
+		{filter: "tenant = 1 OR tenant = 2", exp: nil},
+		{filter: "tenant > 1", exp: nil},
+	} {
+		t.Run(tc.filter, func(t *testing.T) {
+			spans, err := restoreRowFilterPrimaryKeySpans(ctx, &evalCtx, codec, oldID, table, tc.filter)
+			require.NoError(t, err)
+			require.ElementsMatch(t, tc.exp, spans)
+		})
+	}
+}
+
+func TestRestoreRowFilterProject(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	evalCtx := eval.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+	table := makeRestoreRowFilterTestTable(t,
+		`CREATE TABLE t (a INT PRIMARY KEY, b INT, c INT, d INT, FAMILY f0 (a, b, c), FAMILY f1 (d))`)
+	require.NoError(t, projectRestoreTable(table, []string{"b"}))
+	codec := keys.SystemSQLCodec
+	f, err := makeRestoreRowFilter(ctx, codec, &evalCtx, []execinfrapb.RestoreRowFilter{
+		{Table: *table.TableDesc(), Projected: true},
+	})
+	require.NoError(t, err)
+
+	rowKey := encoding.EncodeVarintAscending(codec.IndexPrefix(uint32(table.GetID()), 1), 1)
+	tuple := func(b []byte) roachpb.Value {
+		var v roachpb.Value
+		v.SetTuple(b)
+		return v
+	}
+
+	// The columns which aren't restored are removed from the first family.
+	value, keep, err := f.filter(ctx, keys.MakeFamilyKey(rowKey, 0),
+		tuple(encoding.EncodeIntValue(encoding.EncodeIntValue(nil, 2, 7), 1, 8)))
+	require.NoError(t, err)
+	require.True(t, keep)
+	require.Equal(t, tuple(encoding.EncodeIntValue(nil, 2, 7)).RawBytes, value.RawBytes)
+
+	// The first family is restored even if none of its columns are.
+	value, keep, err = f.filter(ctx, keys.MakeFamilyKey(rowKey, 0),
+		tuple(encoding.EncodeIntValue(nil, 3, 8)))
+	require.NoError(t, err)
+	require.True(t, keep)
+	require.Equal(t, tuple(nil).RawBytes, value.RawBytes)
+
+	// The families which aren't restored are dropped.
+	var d roachpb.Value
+	d.SetInt(9)
+	_, keep, err = f.filter(ctx, keys.MakeFamilyKey(rowKey, 1), d)
+	require.NoError(t, err)
+	require.False(t, keep)
+
+	// So are the keys of other indexes.
+	_, keep, err = f.filter(ctx, keys.MakeFamilyKey(
+		encoding.EncodeVarintAscending(codec.IndexPrefix(uint32(table.GetID()), 2), 1), 0), d)
+	require.NoError(t, err)
+	require.False(t, keep)
+}
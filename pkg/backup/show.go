@@ -141,6 +141,9 @@ func showBackupTypeCheck(
 	if backup.Path == nil {
 		return showBackupsInCollectionTypeCheck(ctx, backup, p)
 	}
+	if backup.Details == tree.BackupDiffDetails {
+		return showBackupDiffTypeCheck(ctx, backup, p)
+	}
 	if err := exprutil.TypeCheck(
 		ctx, "SHOW BACKUP", p.SemaCtx(),
 		exprutil.Ints{
@@ -182,6 +185,9 @@ func showBackupPlanHook(
 		}
 		return showBackupsInCollectionPlanHook(ctx, collection, showStmt, p)
 	}
+	if showStmt.Details == tree.BackupDiffDetails {
+		return showBackupDiffPlanHook(ctx, showStmt, p)
+	}
 
 	backupToken, err := exprEval.String(ctx, showStmt.Path)
 	if err != nil {
@@ -691,6 +697,7 @@ func backupShowerHeaders(showSchemas bool, opts tree.ShowBackupOptions) colinfo.
 	}
 	if opts.CheckFiles {
 		baseHeaders = append(baseHeaders, colinfo.ResultColumn{Name: "file_bytes", Typ: types.Int})
+		baseHeaders = append(baseHeaders, colinfo.ResultColumn{Name: "new_file_bytes", Typ: types.Int})
 	}
 	if opts.DebugIDs {
 		baseHeaders = append(
@@ -811,6 +818,7 @@ func backupShowerDefault(
 					dataSizeDatum := tree.DNull
 					rowCountDatum := tree.DNull
 					fileSizeDatum := tree.DNull
+					newFileSizeDatum := tree.DNull
 					regionsDatum := tree.DNull
 
 					descriptorName := desc.GetName()
@@ -855,6 +863,7 @@ func backupShowerDefault(
 						dataSizeDatum = tree.NewDInt(tree.DInt(tableSize.rowCount.DataSize))
 						rowCountDatum = tree.NewDInt(tree.DInt(tableSize.rowCount.Rows))
 						fileSizeDatum = tree.NewDInt(tree.DInt(tableSize.fileSize))
+						newFileSizeDatum = tree.NewDInt(tree.DInt(tableSize.newFileSize))
 
 						// Only resolve the table schemas if running `SHOW BACKUP SCHEMAS`.
 						// In all other cases we discard these results and so it is wasteful
@@ -898,7 +907,7 @@ func backupShowerDefault(
 						row = append(row, tree.NewDString(owner))
 					}
 					if opts.CheckFiles {
-						row = append(row, fileSizeDatum)
+						row = append(row, fileSizeDatum, newFileSizeDatum)
 					}
 					if opts.DebugIDs {
 						// If showing debug IDs, interleave the IDs with the corresponding object names.
@@ -941,7 +950,7 @@ func backupShowerDefault(
 						row = append(row, tree.DNull)
 					}
 					if opts.CheckFiles {
-						row = append(row, tree.DNull)
+						row = append(row, tree.DNull, tree.DNull)
 					}
 					if opts.DebugIDs {
 						// If showing debug IDs, interleave the IDs with the corresponding object names.
@@ -968,6 +977,9 @@ func backupShowerDefault(
 type descriptorSize struct {
 	rowCount roachpb.RowCount
 	fileSize int64
+	// newFileSize is the part of fileSize in files written by the backup itself,
+	// rather than reused from an earlier backup.
+	newFileSize int64
 }
 
 // getLogicalSSTSize gets the total logical bytes stored in each SST. Note that a
@@ -1059,8 +1071,12 @@ func getTableSizes(
 		s := tableSizes[descpb.ID(tableID)]
 		s.rowCount.Add(f.EntryCounts)
 		if len(fileSizes) > 0 {
-			s.fileSize += approximateSpanPhysicalSize(f.EntryCounts.DataSize, logicalSSTSize[f.Path],
+			sz := approximateSpanPhysicalSize(f.EntryCounts.DataSize, logicalSSTSize[f.Path],
 				fileSizes[idx])
+			s.fileSize += sz
+			if !f.Reused {
+				s.newFileSize += sz
+			}
 		}
 		tableSizes[descpb.ID(tableID)] = s
 		idx++
@@ -1279,6 +1295,7 @@ func backupShowerFileSetup() backupShower {
 		{Name: "rows", Typ: types.Int},
 		{Name: "locality", Typ: types.String},
 		{Name: "file_bytes", Typ: types.Int},
+		{Name: "reused", Typ: types.Bool},
 	},
 
 		fn: func(ctx context.Context, info backupInfo) (rows []tree.Datums, err error) {
@@ -1342,6 +1359,7 @@ func backupShowerFileSetup() backupShower {
 						tree.NewDInt(tree.DInt(file.EntryCounts.Rows)),
 						tree.NewDString(locality),
 						tree.NewDInt(tree.DInt(sz)),
+						tree.MakeDBool(tree.DBool(file.Reused)),
 					})
 					idx++
 				}
@@ -0,0 +1,960 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/backup/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupinfo"
+	"github.com/cockroachdb/cockroach/pkg/backup/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupresolver"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupsink"
+	"github.com/cockroachdb/cockroach/pkg/backup/backuputils"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/nstree"
+	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/besteffort"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/parquet"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+// SHOW BACKUP DIFF compares the rows of two backups of the same chain, read
+// straight from the backup files, without restoring them.
+//
+// The backup the diff ends at only needs to be compared to the one it starts
+// from where the incremental layers between them have files: these hold every
+// key which was written, or deleted, in between. Spans which were introduced
+// by one of those layers, e.g. because a table was added to the backup or
+// brought back online, are compared in full.
+
+var showBackupDiffHeader = colinfo.ResultColumns{
+	{Name: "database_name", Typ: types.String},
+	{Name: "parent_schema_name", Typ: types.String},
+	{Name: "object_name", Typ: types.String},
+	{Name: "change", Typ: types.String},
+	{Name: "key", Typ: types.Jsonb},
+	{Name: "before", Typ: types.Jsonb},
+	{Name: "after", Typ: types.Jsonb},
+}
+
+const (
+	backupDiffInsert = "insert"
+	backupDiffUpdate = "update"
+	backupDiffDelete = "delete"
+)
+
+func showBackupDiffTypeCheck(
+	ctx context.Context, backup *tree.ShowBackup, p sql.PlanHookState,
+) (matched bool, header colinfo.ResultColumns, _ error) {
+	if err := exprutil.TypeCheck(
+		ctx, "SHOW BACKUP DIFF", p.SemaCtx(),
+		exprutil.Strings{
+			backup.Path,
+			backup.Diff.To,
+			backup.Diff.File,
+			backup.Options.EncryptionPassphrase,
+		},
+		exprutil.StringArrays{
+			tree.Exprs(backup.InCollection),
+			tree.Exprs(backup.Options.DecryptionKMSURI),
+		},
+	); err != nil {
+		return false, nil, err
+	}
+	if backup.Diff.File != nil {
+		return true, colinfo.ExportColumns, nil
+	}
+	return true, showBackupDiffHeader, nil
+}
+
+// showBackupDiffPlanHook implements PlanHookFn for SHOW BACKUP DIFF.
+func showBackupDiffPlanHook(
+	ctx context.Context, showStmt *tree.ShowBackup, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, bool, error) {
+	opts := showStmt.Options
+	opts.EncryptionPassphrase, opts.DecryptionKMSURI = nil, nil
+	if !opts.IsDefault() {
+		return nil, nil, false, pgerror.New(pgcode.InvalidParameterValue,
+			"SHOW BACKUP DIFF only supports the encryption_passphrase and kms options")
+	}
+	if showStmt.Diff.File != nil && !strings.EqualFold(showStmt.Diff.FileFormat, "PARQUET") {
+		return nil, nil, false, pgerror.Newf(pgcode.FeatureNotSupported,
+			"SHOW BACKUP DIFF can only be written INTO PARQUET, not %s", showStmt.Diff.FileFormat)
+	}
+
+	exprEval := p.ExprEvaluator("SHOW BACKUP DIFF")
+	fromToken, err := exprEval.String(ctx, showStmt.Path)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	toToken, err := exprEval.String(ctx, showStmt.Diff.To)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	collection, err := exprEval.StringArray(ctx, tree.Exprs(showStmt.InCollection))
+	if err != nil {
+		return nil, nil, false, err
+	}
+	var file string
+	header := showBackupDiffHeader
+	if showStmt.Diff.File != nil {
+		if file, err = exprEval.String(ctx, showStmt.Diff.File); err != nil {
+			return nil, nil, false, err
+		}
+		header = colinfo.ExportColumns
+	}
+
+	fn := func(ctx context.Context, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, showStmt.StatementTag())
+		defer span.Finish()
+
+		if err := sql.CheckDestinationPrivileges(ctx, p, collection); err != nil {
+			return err
+		}
+		if file != "" {
+			if err := sql.CheckDestinationPrivileges(ctx, p, []string{file}); err != nil {
+				return err
+			}
+		}
+
+		mem := p.ExecCfg().RootMemoryMonitor.MakeBoundAccount()
+		defer mem.Close(ctx)
+		d, err := resolveBackupDiff(ctx, p, &mem, showStmt, collection, fromToken, toToken)
+		if err != nil {
+			return err
+		}
+		defer d.close(ctx)
+
+		if file == "" {
+			err = d.run(ctx, func(res tree.Datums) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case resultsCh <- res:
+					return nil
+				}
+			})
+		} else {
+			err = d.writeParquet(ctx, file, resultsCh)
+		}
+		if err != nil {
+			return err
+		}
+		telemetry.Count("show-backup.diff")
+		return nil
+	}
+	return fn, header, false, nil
+}
+
+// backupDiff compares the rows of two backups of the same chain.
+type backupDiff struct {
+	p           sql.PlanHookState
+	mem         *mon.BoundAccount
+	memReserved int64
+
+	codec        keys.SQLCodec
+	manifests    []backuppb.BackupManifest
+	defaultURIs  []string
+	localityInfo []jobspb.RestoreDetails_BackupLocalityInfo
+	layerToIter  backupinfo.LayerToBackupManifestFileIterFactory
+	encryption   *kvpb.FileEncryptionOptions
+	stores       map[string]cloud.ExternalStorage
+
+	// fromLayer is the index in manifests of the backup the diff starts from.
+	// The diff ends at the last manifest.
+	fromLayer int
+	from, to  backupDiffSide
+	tables    []descpb.ID
+}
+
+// backupDiffSide holds the descriptors of one of the backups being compared.
+type backupDiffSide struct {
+	layer int
+	descs map[descpb.ID]catalog.Descriptor
+}
+
+// table returns the descriptor of a table in the backup, or nil if the
+// backup doesn't have it.
+func (s backupDiffSide) table(id descpb.ID) catalog.TableDescriptor {
+	if tbl, ok := s.descs[id].(catalog.TableDescriptor); ok && tbl.Public() {
+		return tbl
+	}
+	return nil
+}
+
+// resolveBackupDiff resolves the backup chain the two backups belong to, and
+// the descriptors of the tables to compare.
+func resolveBackupDiff(
+	ctx context.Context,
+	p sql.PlanHookState,
+	mem *mon.BoundAccount,
+	showStmt *tree.ShowBackup,
+	collection []string,
+	fromToken, toToken string,
+) (_ *backupDiff, retErr error) {
+	defaultCollectionURI, _, err := backupdest.GetURIsByLocalityKV(collection, "")
+	if err != nil {
+		return nil, err
+	}
+	resolve := func(token string) (string, hlc.Timestamp, error) {
+		subdir, endTime, err := resolveRestoreSubdirAndEndTime(
+			ctx, p, defaultCollectionURI, token, hlc.Timestamp{},
+		)
+		if err != nil {
+			return "", hlc.Timestamp{}, err
+		}
+		if endTime.IsEmpty() {
+			return "", hlc.Timestamp{}, pgerror.Newf(pgcode.InvalidParameterValue,
+				"SHOW BACKUP DIFF requires the IDs listed by SHOW BACKUPS, not %q", token)
+		}
+		return subdir, endTime, nil
+	}
+	fromSubdir, fromEndTime, err := resolve(fromToken)
+	if err != nil {
+		return nil, err
+	}
+	subdir, toEndTime, err := resolve(toToken)
+	if err != nil {
+		return nil, err
+	}
+	if fromSubdir != subdir {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			"backups %s and %s are not part of the same backup chain", fromToken, toToken)
+	}
+	if !fromEndTime.Less(toEndTime) {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			"backup %s must be older than backup %s", fromToken, toToken)
+	}
+
+	baseDir, err := backuputils.AppendPaths(collection, subdir)
+	if err != nil {
+		return nil, err
+	}
+	incDir, err := backupdest.ResolveIncrementalsBackupLocation(collection, subdir)
+	if err != nil {
+		return nil, err
+	}
+	mkStore := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI
+	baseStores, cleanupFn, err := backupdest.MakeBackupDestinationStores(ctx, p.User(), mkStore, baseDir)
+	if err != nil {
+		return nil, err
+	}
+	defer besteffort.Cleanup(ctx, "close-base-stores", cleanupFn)
+
+	ioConf := baseStores[0].ExternalIOConf()
+	kmsEnv := backupencryption.MakeBackupKMSEnv(
+		p.ExecCfg().Settings, &ioConf, p.ExecCfg().InternalDB, p.User(),
+	)
+	encryption, err := backupencryption.ResolveEncryptionOptionsFromExpr(
+		ctx, p, p.ExprEvaluator("SHOW BACKUP DIFF"), baseStores[0],
+		showStmt.Options.EncryptionPassphrase, tree.Exprs(showStmt.Options.DecryptionKMSURI),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultURIs, manifests, localityInfo, memReserved, err := backupdest.ResolveBackupManifests(
+		ctx, p.ExecCfg(), mem, defaultCollectionURI, collection, mkStore,
+		subdir, baseDir, incDir, toEndTime, encryption, &kmsEnv, p.User(),
+		false /* includeSkipped */, false, /* includeCompacted */
+	)
+	if err != nil {
+		return nil, err
+	}
+	d := &backupDiff{
+		p:            p,
+		mem:          mem,
+		memReserved:  memReserved,
+		manifests:    manifests,
+		defaultURIs:  defaultURIs,
+		localityInfo: localityInfo,
+		stores:       make(map[string]cloud.ExternalStorage),
+		fromLayer:    -1,
+	}
+	defer func() {
+		if retErr != nil {
+			d.close(ctx)
+		}
+	}()
+	for i := range manifests {
+		if manifests[i].EndTime.Equal(fromEndTime) {
+			d.fromLayer = i
+		}
+	}
+	if d.fromLayer < 0 {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			"backup %s is not part of the backup chain of backup %s", fromToken, toToken)
+	}
+
+	if d.codec, err = backupinfo.MakeBackupCodec(manifests); err != nil {
+		return nil, err
+	}
+	if encryption != nil {
+		key, err := backupencryption.GetEncryptionKey(ctx, encryption, &kmsEnv)
+		if err != nil {
+			return nil, err
+		}
+		d.encryption = &kvpb.FileEncryptionOptions{Key: key}
+	}
+	d.layerToIter, err = backupinfo.GetBackupManifestIterFactories(
+		ctx, p.ExecCfg().DistSQLSrv.ExternalStorage, manifests, encryption, &kmsEnv,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if d.from, err = d.loadSide(ctx, d.fromLayer); err != nil {
+		return nil, err
+	}
+	if d.to, err = d.loadSide(ctx, len(manifests)-1); err != nil {
+		return nil, err
+	}
+	if d.tables, err = d.resolveTables(ctx, showStmt.Diff.Table); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// loadSide loads the descriptors of the backup of the given layer.
+func (d *backupDiff) loadSide(ctx context.Context, layer int) (backupDiffSide, error) {
+	sqlDescs, _, err := backupinfo.LoadSQLDescsFromBackupsAtTime(
+		ctx, d.manifests[:layer+1], d.layerToIter, hlc.Timestamp{},
+	)
+	if err != nil {
+		return backupDiffSide{}, err
+	}
+	var c nstree.MutableCatalog
+	for _, desc := range sqlDescs {
+		c.UpsertDescriptor(desc)
+	}
+	if err := descs.HydrateCatalog(ctx, c); err != nil {
+		return backupDiffSide{}, err
+	}
+	side := backupDiffSide{layer: layer, descs: make(map[descpb.ID]catalog.Descriptor)}
+	for _, desc := range c.OrderedDescriptors() {
+		side.descs[desc.GetID()] = desc
+	}
+	return side, nil
+}
+
+// resolveTables returns the IDs of the tables to compare: the given table if
+// there is one, and otherwise every user table in either backup.
+func (d *backupDiff) resolveTables(
+	ctx context.Context, tableName *tree.TableName,
+) ([]descpb.ID, error) {
+	if tableName != nil {
+		var firstErr error
+		for _, side := range []backupDiffSide{d.to, d.from} {
+			sideDescs := make([]catalog.Descriptor, 0, len(side.descs))
+			for _, desc := range side.descs {
+				sideDescs = append(sideDescs, desc)
+			}
+			tn := *tableName
+			matched, err := backupresolver.DescriptorsMatchingTargets(
+				ctx, d.p.CurrentDatabase(), d.p.CurrentSearchPath(), sideDescs,
+				tree.BackupTargetList{Tables: tree.TableAttrs{TablePatterns: tree.TablePatterns{&tn}}},
+				hlc.Timestamp{},
+			)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			tbl := matched.DescsByTablePattern[&tn].(catalog.TableDescriptor)
+			if !isBackupDiffTable(tbl) {
+				return nil, pgerror.Newf(pgcode.WrongObjectType,
+					"SHOW BACKUP DIFF can only compare tables, and %q is not one", tbl.GetName())
+			}
+			return []descpb.ID{tbl.GetID()}, nil
+		}
+		return nil, firstErr
+	}
+
+	seen := make(map[descpb.ID]struct{})
+	var ids []descpb.ID
+	for _, side := range []backupDiffSide{d.from, d.to} {
+		for id := range side.descs {
+			tbl := side.table(id)
+			if _, ok := seen[id]; ok || tbl == nil || !isBackupDiffTable(tbl) ||
+				tbl.GetParentID() == keys.SystemDatabaseID {
+				continue
+			}
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+func isBackupDiffTable(tbl catalog.TableDescriptor) bool {
+	return !tbl.IsView() && !tbl.IsSequence() && !tbl.IsVirtualTable()
+}
+
+func (d *backupDiff) close(ctx context.Context) {
+	for uri, store := range d.stores {
+		if err := store.Close(); err != nil {
+			log.Dev.Warningf(ctx, "failed to close store %s: %+v", uri, err)
+		}
+	}
+	d.mem.Shrink(ctx, d.memReserved)
+}
+
+// run calls fn with a row of showBackupDiffHeader for each row which differs
+// between the two backups.
+func (d *backupDiff) run(ctx context.Context, fn func(tree.Datums) error) error {
+	for _, id := range d.tables {
+		if err := d.diffTable(ctx, id, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *backupDiff) diffTable(
+	ctx context.Context, id descpb.ID, fn func(tree.Datums) error,
+) error {
+	fromTable, toTable := d.from.table(id), d.to.table(id)
+	side, table := d.to, toTable
+	if table == nil {
+		side, table = d.from, fromTable
+	}
+	if fromTable != nil && toTable != nil &&
+		fromTable.GetPrimaryIndexID() != toTable.GetPrimaryIndexID() {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"cannot compare table %q, its primary key changed between the two backups", table.GetName())
+	}
+	dbName, scName := d.names(side, table)
+	prefix := tree.Datums{
+		tree.NewDString(dbName), tree.NewDString(scName), tree.NewDString(table.GetName()),
+	}
+
+	pkSpan := table.PrimaryIndexSpan(d.codec)
+	spans, err := d.changedSpans(ctx, pkSpan, fromTable != nil && toTable != nil)
+	if err != nil {
+		return err
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+
+	fromRows, err := d.openRows(ctx, d.from.layer, fromTable, pkSpan, spans)
+	if err != nil {
+		return err
+	}
+	defer fromRows.close()
+	toRows, err := d.openRows(ctx, d.to.layer, toTable, pkSpan, spans)
+	if err != nil {
+		return err
+	}
+	defer toRows.close()
+
+	emit := func(change string, before, after *backupDiffRows) error {
+		var key json.JSON
+		res := append(prefix[:len(prefix):len(prefix)], tree.NewDString(change), tree.DNull, tree.DNull, tree.DNull)
+		for i, rows := range []*backupDiffRows{before, after} {
+			if rows == nil {
+				continue
+			}
+			k, v, err := rows.decode(ctx)
+			if err != nil {
+				return err
+			}
+			key = k
+			res[len(prefix)+2+i] = tree.NewDJSON(v)
+		}
+		res[len(prefix)+1] = tree.NewDJSON(key)
+		return fn(res)
+	}
+
+	fromOK, err := fromRows.next()
+	if err != nil {
+		return err
+	}
+	toOK, err := toRows.next()
+	if err != nil {
+		return err
+	}
+	for fromOK || toOK {
+		c := 0
+		switch {
+		case !toOK:
+			c = -1
+		case !fromOK:
+			c = 1
+		default:
+			c = fromRows.rowKey.Compare(toRows.rowKey)
+		}
+		switch {
+		case c < 0:
+			err = emit(backupDiffDelete, fromRows, nil)
+		case c > 0:
+			err = emit(backupDiffInsert, nil, toRows)
+		case !sameBackupDiffRow(fromRows.kvs, toRows.kvs):
+			err = emit(backupDiffUpdate, fromRows, toRows)
+		}
+		if err != nil {
+			return err
+		}
+		if c <= 0 {
+			if fromOK, err = fromRows.next(); err != nil {
+				return err
+			}
+		}
+		if c >= 0 {
+			if toOK, err = toRows.next(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// names returns the names of the database and schema of a table.
+func (d *backupDiff) names(side backupDiffSide, table catalog.TableDescriptor) (string, string) {
+	var dbName, scName string
+	if db := side.descs[table.GetParentID()]; db != nil {
+		dbName = db.GetName()
+	}
+	if table.GetParentSchemaID() == keys.PublicSchemaIDForBackup {
+		scName = catconstants.PublicSchemaName
+	} else if sc := side.descs[table.GetParentSchemaID()]; sc != nil {
+		scName = sc.GetName()
+	}
+	return dbName, scName
+}
+
+// changedSpans returns the spans of the primary index of a table in which rows
+// may differ between the two backups.
+func (d *backupDiff) changedSpans(
+	ctx context.Context, pkSpan roachpb.Span, inBoth bool,
+) (roachpb.Spans, error) {
+	if !inBoth {
+		return roachpb.Spans{pkSpan}, nil
+	}
+	var spans roachpb.Spans
+	for layer := d.fromLayer + 1; layer < len(d.manifests); layer++ {
+		if spansOverlap(d.manifests[layer].IntroducedSpans, pkSpan) {
+			return roachpb.Spans{pkSpan}, nil
+		}
+		if err := d.forEachFile(ctx, layer, func(f backuppb.BackupManifest_File) error {
+			if !f.Span.Overlaps(pkSpan) {
+				return nil
+			}
+			// Widen the span to whole rows, so that all the column families of a
+			// row are compared together.
+			span := f.Span
+			if rowKey, err := keys.EnsureSafeSplitKey(span.Key); err == nil {
+				span.Key = rowKey
+			}
+			if rowKey, err := keys.EnsureSafeSplitKey(span.EndKey); err == nil && !rowKey.Equal(span.EndKey) {
+				span.EndKey = rowKey.PrefixEnd()
+			}
+			spans = append(spans, span.Intersect(pkSpan))
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	spans, _ = roachpb.MergeSpans(spans)
+	return spans, nil
+}
+
+func spansOverlap(spans roachpb.Spans, span roachpb.Span) bool {
+	for _, sp := range spans {
+		if sp.Overlaps(span) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *backupDiff) forEachFile(
+	ctx context.Context, layer int, fn func(backuppb.BackupManifest_File) error,
+) error {
+	it, err := d.layerToIter[layer].NewFileIter(ctx)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for ; ; it.Next() {
+		if ok, err := it.Valid(); err != nil {
+			return err
+		} else if !ok {
+			return nil
+		}
+		if err := fn(*it.Value()); err != nil {
+			return err
+		}
+	}
+}
+
+// store returns the store holding a file of a layer.
+func (d *backupDiff) store(
+	ctx context.Context, layer int, f backuppb.BackupManifest_File,
+) (cloud.ExternalStorage, error) {
+	uri := d.defaultURIs[layer]
+	if u, ok := d.localityInfo[layer].URIsByOriginalLocalityKV[f.LocalityKV]; ok {
+		uri = u
+	}
+	if store, ok := d.stores[uri]; ok {
+		return store, nil
+	}
+	store, err := d.p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, uri, d.p.User())
+	if err != nil {
+		return nil, err
+	}
+	d.stores[uri] = store
+	return store, nil
+}
+
+// openRows returns the rows of the given spans of the primary index of a
+// table, as of the backup of the given layer. The table is nil if the backup
+// doesn't have it.
+func (d *backupDiff) openRows(
+	ctx context.Context,
+	layer int,
+	table catalog.TableDescriptor,
+	pkSpan roachpb.Span,
+	spans roachpb.Spans,
+) (*backupDiffRows, error) {
+	r := &backupDiffRows{spans: spans}
+	if table == nil {
+		return r, nil
+	}
+	// Files of layers before the last one which introduced the span don't
+	// hold the data of the table as of this backup.
+	start := 0
+	for l := layer; l > 0; l-- {
+		if spansOverlap(d.manifests[l].IntroducedSpans, pkSpan) {
+			start = l
+			break
+		}
+	}
+	var storeFiles []storage.StoreFile
+	for l := start; l <= layer; l++ {
+		if err := d.forEachFile(ctx, l, func(f backuppb.BackupManifest_File) error {
+			if !spansOverlap(spans, f.Span) {
+				return nil
+			}
+			store, err := d.store(ctx, l, f)
+			if err != nil {
+				return err
+			}
+			storeFiles = append(storeFiles, storage.StoreFile{Store: store, FilePath: f.Path})
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if len(storeFiles) == 0 {
+		return r, nil
+	}
+
+	var err error
+	if r.elidedPrefix, err = backupsink.ElidedPrefix(pkSpan.Key, d.manifests[0].ElidedPrefix); err != nil {
+		return nil, err
+	}
+	asOf := d.manifests[layer].EndTime
+	iter, err := storage.ExternalSSTReader(ctx, storeFiles, d.encryption, storage.IterOptions{
+		RangeKeyMaskingBelow: asOf,
+		KeyTypes:             storage.IterKeyTypePointsAndRanges,
+		LowerBound:           keys.LocalMax,
+		UpperBound:           keys.MaxKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.iter = storage.NewReadAsOfIterator(iter, asOf)
+	if r.decoder, err = makeBackupDiffDecoder(ctx, d.p, d.codec, table); err != nil {
+		r.close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// backupDiffRows iterates over the rows of spans of a primary index in a
+// backup.
+type backupDiffRows struct {
+	iter         *storage.ReadAsOfIterator
+	elidedPrefix []byte
+	spans        roachpb.Spans
+	decoder      *backupDiffDecoder
+
+	// spanIdx is the index of the span iter is positioned in, if positioned is
+	// true.
+	spanIdx    int
+	positioned bool
+
+	// rowKey and kvs are the key prefix and the keys of the current row.
+	rowKey roachpb.Key
+	kvs    []roachpb.KeyValue
+}
+
+func (r *backupDiffRows) close() {
+	if r.iter != nil {
+		r.iter.Close()
+	}
+}
+
+// next moves to the next row, returning false if there are no more rows.
+func (r *backupDiffRows) next() (bool, error) {
+	r.rowKey, r.kvs = nil, nil
+	for {
+		key, ok, err := r.peek()
+		if err != nil || !ok {
+			return len(r.kvs) > 0, err
+		}
+		prefixLen, err := keys.GetRowPrefixLength(key)
+		if err != nil {
+			return false, err
+		}
+		if len(r.kvs) == 0 {
+			r.rowKey = key[:prefixLen]
+		} else if !bytes.Equal(key[:prefixLen], r.rowKey) {
+			return true, nil
+		}
+		v, err := r.iter.UnsafeValue()
+		if err != nil {
+			return false, err
+		}
+		value, err := storage.DecodeValueFromMVCCValue(append([]byte(nil), v...))
+		if err != nil {
+			return false, err
+		}
+		r.kvs = append(r.kvs, roachpb.KeyValue{Key: key, Value: value})
+		r.iter.NextKey()
+	}
+}
+
+// peek returns a copy of the key the iterator is positioned at, seeking to
+// the next span if needed.
+func (r *backupDiffRows) peek() (roachpb.Key, bool, error) {
+	for r.iter != nil && r.spanIdx < len(r.spans) {
+		span := r.spans[r.spanIdx]
+		if !r.positioned {
+			r.iter.SeekGE(storage.MVCCKey{Key: bytes.TrimPrefix(span.Key, r.elidedPrefix)})
+			r.positioned = true
+		}
+		if ok, err := r.iter.Valid(); err != nil {
+			return nil, false, err
+		} else if !ok {
+			return nil, false, nil
+		}
+		key := append(append(roachpb.Key(nil), r.elidedPrefix...), r.iter.UnsafeKey().Key...)
+		if key.Compare(span.EndKey) < 0 {
+			return key, true, nil
+		}
+		r.spanIdx++
+		r.positioned = false
+	}
+	return nil, false, nil
+}
+
+// decode returns the primary key and the columns of the current row.
+func (r *backupDiffRows) decode(ctx context.Context) (key, values json.JSON, _ error) {
+	return r.decoder.decode(ctx, r.kvs)
+}
+
+func sameBackupDiffRow(a, b []roachpb.KeyValue) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Key.Equal(b[i].Key) ||
+			!bytes.Equal(a[i].Value.TagAndDataBytes(), b[i].Value.TagAndDataBytes()) {
+			return false
+		}
+	}
+	return true
+}
+
+// backupDiffDecoder decodes the keys of the rows of a table into JSON.
+type backupDiffDecoder struct {
+	p       sql.PlanHookState
+	fetcher row.Fetcher
+	cols    []catalog.Column
+	isKey   []bool
+}
+
+func makeBackupDiffDecoder(
+	ctx context.Context, p sql.PlanHookState, codec keys.SQLCodec, table catalog.TableDescriptor,
+) (*backupDiffDecoder, error) {
+	d := &backupDiffDecoder{p: p}
+	pkCols := table.GetPrimaryIndex().CollectKeyColumnIDs()
+	var colIDs []descpb.ColumnID
+	for _, col := range table.PublicColumns() {
+		if col.IsVirtual() {
+			continue
+		}
+		d.cols = append(d.cols, col)
+		d.isKey = append(d.isKey, pkCols.Contains(col.GetID()))
+		colIDs = append(colIDs, col.GetID())
+	}
+	var spec fetchpb.IndexFetchSpec
+	if err := rowenc.InitIndexFetchSpec(&spec, codec, table, table.GetPrimaryIndex(), colIDs); err != nil {
+		return nil, err
+	}
+	if err := d.fetcher.Init(ctx, row.FetcherInitArgs{
+		WillUseKVProvider: true,
+		Alloc:             &tree.DatumAlloc{},
+		Spec:              &spec,
+	}); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *backupDiffDecoder) decode(
+	ctx context.Context, kvs []roachpb.KeyValue,
+) (key, values json.JSON, _ error) {
+	if err := d.fetcher.ConsumeKVProvider(ctx, &row.KVProvider{KVs: kvs}); err != nil {
+		return nil, nil, err
+	}
+	datums, _, err := d.fetcher.NextRowDecoded(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if datums == nil {
+		return nil, nil, errors.AssertionFailedf("no row decoded from key %s", kvs[0].Key)
+	}
+	sd := d.p.SessionData()
+	keyBuilder := json.NewObjectBuilder(len(d.cols))
+	rowBuilder := json.NewObjectBuilder(len(d.cols))
+	for i, col := range d.cols {
+		j, err := tree.AsJSON(datums[i], sd.DataConversionConfig, sd.GetLocation())
+		if err != nil {
+			return nil, nil, err
+		}
+		if d.isKey[i] {
+			keyBuilder.Add(col.GetName(), j)
+		}
+		rowBuilder.Add(col.GetName(), j)
+	}
+	return keyBuilder.Build(), rowBuilder.Build(), nil
+}
+
+// backupDiffParquetFlushBytes is the estimated size of the rows buffered by
+// the parquet writer past which they are flushed to the file as a row group.
+const backupDiffParquetFlushBytes = 32 << 20
+
+// writeParquet streams the diff into a parquet file in the given directory,
+// and sends a row of colinfo.ExportColumns describing it. Only the rows of the
+// row group being written are buffered, and their memory is accounted for.
+func (d *backupDiff) writeParquet(
+	ctx context.Context, dest string, resultsCh chan<- tree.Datums,
+) error {
+	names := make([]string, len(showBackupDiffHeader))
+	typs := make([]*types.T, len(showBackupDiffHeader))
+	for i, col := range showBackupDiffHeader {
+		names[i], typs[i] = col.Name, col.Typ
+	}
+	schema, err := parquet.NewSchema(names, typs)
+	if err != nil {
+		return err
+	}
+
+	store, err := d.p.ExecCfg().DistSQLSrv.ExternalStorageFromURI(ctx, dest, d.p.User())
+	if err != nil {
+		return err
+	}
+	defer besteffort.Cleanup(ctx, "close-diff-store", store.Close)
+	filename := fmt.Sprintf("backup-diff-%d-%d.parquet",
+		d.manifests[d.fromLayer].EndTime.WallTime, d.manifests[len(d.manifests)-1].EndTime.WallTime)
+
+	// Canceling the context the file is written with aborts the write, so that
+	// a partial file isn't left behind if the diff fails.
+	writeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	sink, err := store.Writer(writeCtx, filename)
+	if err != nil {
+		return errors.Wrap(err, "opening object for writing")
+	}
+	out := &countingWriteCloser{WriteCloser: sink}
+	w, err := parquet.NewWriter(schema, out)
+	if err != nil {
+		cancel()
+		return errors.CombineErrors(err, sink.Close())
+	}
+
+	acc := d.mem.Monitor().MakeBoundAccount()
+	defer acc.Close(ctx)
+	var rows int64
+	if err := d.run(ctx, func(res tree.Datums) error {
+		if err := w.AddRow(res); err != nil {
+			return err
+		}
+		rows++
+		buffered := w.BufferedBytesEstimate()
+		if buffered >= backupDiffParquetFlushBytes {
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			buffered = 0
+		}
+		return acc.ResizeTo(ctx, buffered)
+	}); err != nil {
+		cancel()
+		return errors.CombineErrors(err, w.Close())
+	}
+	// Closing the parquet writer closes the file.
+	if err := w.Close(); err != nil {
+		return errors.Wrap(err, "closing object")
+	}
+	size := out.n
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case resultsCh <- tree.Datums{
+		tree.NewDString(filename), tree.NewDInt(tree.DInt(rows)), tree.NewDInt(tree.DInt(size)),
+	}:
+		return nil
+	}
+}
+
+// countingWriteCloser counts the bytes written to an io.WriteCloser.
+type countingWriteCloser struct {
+	io.WriteCloser
+	n int64
+}
+
+// Write implements io.Writer.
+func (w *countingWriteCloser) Write(p []byte) (int, error) {
+	n, err := w.WriteCloser.Write(p)
+	w.n += int64(n)
+	return n, err
+}
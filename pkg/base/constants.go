@@ -45,6 +45,10 @@ const (
 	// when the execution trace dumper is enabled.
 	ExecutionTraceDir = "executiontrace_dump"
 
+	// ConsistencyRepairDir is the directory name where the consistency checker
+	// stores the evidence gathered before repairing an inconsistent replica.
+	ConsistencyRepairDir = "consistency_repair"
+
 	// InflightTraceDir is the directory name where the job trace dumper stores traces
 	// when a job opts in to dumping its execution traces.
 	InflightTraceDir = "inflight_trace_dump"
@@ -0,0 +1,244 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package cdceval
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/valueside"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/lib/pq/oid"
+)
+
+// RowFilterForExpression returns a row filter which may be pushed down into
+// the rangefeed registrations of a changefeed with the specified select
+// clause. The returned filter is a conservative approximation of the
+// expression: it contains only the top level conjuncts of the WHERE clause
+// which can be evaluated directly on the encoded column values, and a
+// projection of the columns referenced by the expression, if those can be
+// determined. Rows which pass the filter must still be evaluated by the
+// changefeed. Returns nil if nothing can be pushed down.
+func RowFilterForExpression(
+	ctx context.Context,
+	descr catalog.TableDescriptor,
+	target jobspb.ChangefeedTargetSpecification,
+	sc *tree.SelectClause,
+) (*kvpb.RangeFeedRowFilter, error) {
+	family, err := getTargetFamilyDescriptor(descr, target)
+	if err != nil {
+		return nil, err
+	}
+	inFamily := catalog.MakeTableColSet(family.ColumnIDs...)
+	keyCols := descr.GetPrimaryIndex().CollectKeyColumnIDs()
+
+	filter := &kvpb.RangeFeedRowFilter{
+		TableID:      uint32(descr.GetID()),
+		IndexID:      uint32(descr.GetPrimaryIndexID()),
+		FamilyID:     uint32(family.ID),
+		NextColumnID: uint32(descr.GetNextColumnID()),
+	}
+
+	// valueColumn returns the column with the specified name if its value is
+	// stored in the target family.
+	valueColumn := func(e tree.Expr) catalog.Column {
+		n, ok := e.(*tree.UnresolvedName)
+		if !ok || n.NumParts != 1 || n.Star {
+			return nil
+		}
+		col := catalog.FindColumnByName(descr, n.Parts[0])
+		if col == nil || col.IsVirtual() || keyCols.Contains(col.GetID()) ||
+			!inFamily.Contains(col.GetID()) {
+			return nil
+		}
+		return col
+	}
+
+	if sc.Where != nil {
+		semaCtx := tree.MakeSemaContext(nil /* resolver */)
+		for _, conjunct := range splitConjuncts(sc.Where.Expr, nil) {
+			if clause, ok := pushdownClause(ctx, &semaCtx, conjunct, valueColumn); ok {
+				filter.Clauses = append(filter.Clauses, clause)
+			}
+		}
+	}
+	filter.ProjectionColumnIDs = pushdownProjection(descr, family, sc)
+
+	if len(filter.Clauses) == 0 && len(filter.ProjectionColumnIDs) == 0 {
+		return nil, nil
+	}
+	return filter, nil
+}
+
+// splitConjuncts appends the top level conjuncts of expr to res.
+func splitConjuncts(expr tree.Expr, res []tree.Expr) []tree.Expr {
+	switch e := expr.(type) {
+	case *tree.AndExpr:
+		return splitConjuncts(e.Right, splitConjuncts(e.Left, res))
+	case *tree.ParenExpr:
+		return splitConjuncts(e.Expr, res)
+	default:
+		return append(res, expr)
+	}
+}
+
+// pushdownClause attempts to convert a single conjunct into a row filter
+// clause.
+func pushdownClause(
+	ctx context.Context,
+	semaCtx *tree.SemaContext,
+	expr tree.Expr,
+	valueColumn func(tree.Expr) catalog.Column,
+) (c kvpb.RangeFeedRowFilter_Clause, ok bool) {
+	var col catalog.Column
+	switch e := expr.(type) {
+	case *tree.IsNullExpr:
+		col = valueColumn(e.Expr)
+		c.Op = kvpb.RangeFeedRowFilter_Clause_IS_NULL
+	case *tree.IsNotNullExpr:
+		col = valueColumn(e.Expr)
+		c.Op = kvpb.RangeFeedRowFilter_Clause_IS_NOT_NULL
+	case *tree.ComparisonExpr:
+		var values tree.Exprs
+		switch e.Operator.Symbol {
+		case treecmp.EQ, treecmp.NE:
+			values = tree.Exprs{e.Right}
+		case treecmp.In, treecmp.NotIn:
+			t, isTuple := e.Right.(*tree.Tuple)
+			if !isTuple {
+				return c, false
+			}
+			values = t.Exprs
+		default:
+			return c, false
+		}
+		if e.Operator.Symbol == treecmp.EQ || e.Operator.Symbol == treecmp.In {
+			c.Op = kvpb.RangeFeedRowFilter_Clause_IN
+		} else {
+			c.Op = kvpb.RangeFeedRowFilter_Clause_NOT_IN
+		}
+		col = valueColumn(e.Left)
+		if col == nil || !hasCanonicalValueEncoding(col.GetType()) || len(values) == 0 {
+			return c, false
+		}
+		for _, v := range values {
+			encoded, ok := encodeConstant(ctx, semaCtx, v, col.GetType())
+			if !ok {
+				return c, false
+			}
+			c.Values = append(c.Values, encoded)
+		}
+	default:
+		return c, false
+	}
+	if col == nil {
+		return c, false
+	}
+	c.ColumnID = uint32(col.GetID())
+	return c, true
+}
+
+// hasCanonicalValueEncoding returns true if two values of the specified type
+// are equal if and only if their value encodings are equal.
+func hasCanonicalValueEncoding(t *types.T) bool {
+	switch t.Family() {
+	case types.IntFamily, types.BoolFamily, types.BytesFamily, types.UuidFamily:
+		return true
+	case types.StringFamily:
+		// Exclude types such as CHAR, whose values are padded.
+		return t.Oid() == oid.T_text
+	default:
+		return false
+	}
+}
+
+// encodeConstant returns the value encoding of a constant expression
+// resolved as the specified type.
+func encodeConstant(
+	ctx context.Context, semaCtx *tree.SemaContext, expr tree.Expr, t *types.T,
+) ([]byte, bool) {
+	var d tree.Datum
+	switch e := expr.(type) {
+	case tree.Constant:
+		typed, err := e.ResolveAsType(ctx, semaCtx, t)
+		if err != nil {
+			return nil, false
+		}
+		if d, _ = typed.(tree.Datum); d == nil {
+			return nil, false
+		}
+	case *tree.DBool:
+		d = e
+	default:
+		return nil, false
+	}
+	if d == tree.DNull || !d.ResolvedType().Equivalent(t) {
+		return nil, false
+	}
+	encoded, err := valueside.Encode(nil, valueside.NoColumnID, d)
+	if err != nil {
+		return nil, false
+	}
+	return encoded, true
+}
+
+// pushdownProjection returns the IDs of the columns stored in the target
+// family which must be retained for the expression to be evaluated. Returns
+// nil if all columns must be retained.
+func pushdownProjection(
+	descr catalog.TableDescriptor, family *descpb.ColumnFamilyDescriptor, sc *tree.SelectClause,
+) []uint32 {
+	// Virtual columns are computed from other columns, which we do not track.
+	for _, col := range descr.PublicColumns() {
+		if col.IsVirtual() {
+			return nil
+		}
+	}
+
+	referenced := catalog.MakeTableColSet()
+	allColumns := false
+	if _, err := tree.SimpleStmtVisit(sc, func(expr tree.Expr) (bool, tree.Expr, error) {
+		switch e := expr.(type) {
+		case tree.UnqualifiedStar, *tree.AllColumnsSelector, *tree.TupleStar:
+			allColumns = true
+		case *tree.UnresolvedName:
+			col := catalog.FindColumnByName(descr, e.Parts[0])
+			if e.Star || e.NumParts != 1 || col == nil {
+				// References to cdc_prev, or to anything else we cannot resolve
+				// to a column of the table.
+				allColumns = true
+			} else {
+				referenced.Add(col.GetID())
+			}
+		}
+		return !allColumns, expr, nil
+	}); err != nil || allColumns {
+		return nil
+	}
+
+	// Non-nullable columns are always retained since decoding a value which
+	// is missing them fails.
+	var projection []uint32
+	for _, id := range family.ColumnIDs {
+		col := catalog.FindColumnByID(descr, id)
+		if col == nil {
+			return nil
+		}
+		if referenced.Contains(id) || !col.IsNullable() {
+			projection = append(projection, uint32(id))
+		}
+	}
+	if len(projection) == 0 || len(projection) == len(family.ColumnIDs) {
+		// An empty projection is indistinguishable from no projection.
+		return nil
+	}
+	return projection
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package cdceval
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/valueside"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRowFilterForExpression(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(context.Background())
+	s := srv.ApplicationLayer()
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE TABLE foo (
+  a INT PRIMARY KEY, b INT, c STRING, d UUID, e CHAR(3), f INT NOT NULL DEFAULT 0
+)`)
+	fooDesc := cdctest.GetHydratedTableDescriptor(t, s.ExecutorConfig(), "foo")
+
+	encode := func(d tree.Datum) []byte {
+		b, err := valueside.Encode(nil, valueside.NoColumnID, d)
+		require.NoError(t, err)
+		return b
+	}
+	someUUID := uuid.MakeV4()
+
+	for _, tc := range []struct {
+		name       string
+		stmt       string
+		clauses    []kvpb.RangeFeedRowFilter_Clause
+		projection []uint32
+	}{
+		{
+			name: "star with predicate",
+			stmt: "SELECT * FROM foo WHERE b = 5 AND (c IN ('x', 'y'))",
+			clauses: []kvpb.RangeFeedRowFilter_Clause{
+				{ColumnID: 2, Op: kvpb.RangeFeedRowFilter_Clause_IN,
+					Values: [][]byte{encode(tree.NewDInt(5))}},
+				{ColumnID: 3, Op: kvpb.RangeFeedRowFilter_Clause_IN,
+					Values: [][]byte{encode(tree.NewDString("x")), encode(tree.NewDString("y"))}},
+			},
+		},
+		{
+			name:       "unsupported predicates",
+			stmt:       "SELECT b FROM foo WHERE a = 1 AND e = 'abc' AND b > 3",
+			projection: []uint32{1, 2, 6},
+		},
+		{
+			name: "negations",
+			stmt: "SELECT c FROM foo WHERE c IS NOT NULL AND b != 3",
+			clauses: []kvpb.RangeFeedRowFilter_Clause{
+				{ColumnID: 3, Op: kvpb.RangeFeedRowFilter_Clause_IS_NOT_NULL},
+				{ColumnID: 2, Op: kvpb.RangeFeedRowFilter_Clause_NOT_IN,
+					Values: [][]byte{encode(tree.NewDInt(3))}},
+			},
+			projection: []uint32{2, 3, 6},
+		},
+		{
+			name: "cdc_prev",
+			stmt: "SELECT a, cdc_prev FROM foo WHERE d = '" + someUUID.String() + "'",
+			clauses: []kvpb.RangeFeedRowFilter_Clause{
+				{ColumnID: 4, Op: kvpb.RangeFeedRowFilter_Clause_IN,
+					Values: [][]byte{encode(tree.NewDUuid(tree.DUuid{UUID: someUUID}))}},
+			},
+		},
+		{
+			name: "disjunction",
+			stmt: "SELECT * FROM foo WHERE b = 5 OR c = 'x'",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sc, err := ParseChangefeedExpression(tc.stmt)
+			require.NoError(t, err)
+			filter, err := RowFilterForExpression(context.Background(), fooDesc,
+				jobspb.ChangefeedTargetSpecification{DescID: fooDesc.GetID()}, sc)
+			require.NoError(t, err)
+			if tc.clauses == nil && tc.projection == nil {
+				require.Nil(t, filter)
+				return
+			}
+			require.NotNil(t, filter)
+			require.Equal(t, uint32(fooDesc.GetID()), filter.TableID)
+			require.Equal(t, uint32(fooDesc.GetPrimaryIndexID()), filter.IndexID)
+			require.Equal(t, uint32(fooDesc.GetNextColumnID()), filter.NextColumnID)
+			require.Equal(t, tc.clauses, filter.Clauses)
+			require.Equal(t, tc.projection, filter.ProjectionColumnIDs)
+		})
+	}
+}
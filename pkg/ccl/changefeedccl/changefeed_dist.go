@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/followerreads"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql"
@@ -197,6 +198,26 @@ func fetchSpansForTables(
 		sd, tableDescs[0], initialHighwater, target, sc)
 }
 
+// rowFilterForTables returns the row filter to push down into the rangefeed
+// registrations of a changefeed with a CDC query, or nil if there is none.
+func rowFilterForTables(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	tableDescs []catalog.TableDescriptor,
+	details jobspb.ChangefeedDetails,
+) (*kvpb.RangeFeedRowFilter, error) {
+	if details.Select == "" || len(tableDescs) != 1 ||
+		!changefeedbase.RangefeedPredicatePushdownEnabled.Get(&execCfg.Settings.SV) {
+		return nil, nil
+	}
+	sc, err := cdceval.ParseChangefeedExpression(details.Select)
+	if err != nil {
+		return nil, pgerror.Wrap(err, pgcode.InvalidParameterValue,
+			"could not parse changefeed expression")
+	}
+	return cdceval.RowFilterForExpression(ctx, tableDescs[0], details.TargetSpecifications[0], sc)
+}
+
 // startDistChangefeed plans and runs a distributed changefeed.
 //
 // One or more ChangeAggregator processors watch table data for changes. These
@@ -247,6 +268,10 @@ func startDistChangefeed(
 	if log.ExpensiveLogEnabled(ctx, 2) {
 		log.Changefeed.Infof(ctx, "tracked spans: %s", trackedSpans)
 	}
+	rowFilter, err := rowFilterForTables(ctx, execCfg, tableDescs, details)
+	if err != nil {
+		return flowResult{}, err
+	}
 
 	// Changefeed flows handle transactional consistency themselves.
 	var noTxn *kv.Txn
@@ -260,7 +285,7 @@ func startDistChangefeed(
 	}
 
 	p, planCtx, err := makePlan(execCtx, jobID, details, description, initialHighWater,
-		trackedSpans, spanLevelCheckpoint, resolvedSpans, schemaTS)(ctx, dsp)
+		trackedSpans, spanLevelCheckpoint, resolvedSpans, schemaTS, rowFilter)(ctx, dsp)
 	if err != nil {
 		return flowResult{}, err
 	}
@@ -384,6 +409,7 @@ func makePlan(
 	spanLevelCheckpoint *jobspb.TimestampSpansMap,
 	resolvedSpans []jobspb.ResolvedSpan,
 	schemaTS hlc.Timestamp,
+	rowFilter *kvpb.RangeFeedRowFilter,
 ) func(context.Context, *sql.DistSQLPlanner) (*sql.PhysicalPlan, *sql.PlanningCtx, error) {
 	return func(ctx context.Context, dsp *sql.DistSQLPlanner) (*sql.PhysicalPlan, *sql.PlanningCtx, error) {
 		sv := &execCtx.ExecCfg().Settings.SV
@@ -509,6 +535,7 @@ func makePlan(
 				ResolvedSpans:       resolvedSpans,
 				SchemaTS:            &schemaTS,
 				AggregatorID:        int32(i),
+				RowFilter:           rowFilter,
 			}
 		}
 
@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobfrontier"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -522,6 +523,12 @@ func (ca *changeAggregator) makeKVFeedCfg(
 		return kvfeed.Config{}, err
 	}
 	filters := config.Opts.GetFilters()
+	catchUpMode := kvpb.RangeFeedRequest_ALL_VERSIONS
+	if m, err := config.Opts.GetCatchUpMode(); err != nil {
+		return kvfeed.Config{}, err
+	} else if m == changefeedbase.CatchUpModeLatest {
+		catchUpMode = kvpb.RangeFeedRequest_LATEST
+	}
 	cfg := ca.FlowCtx.Cfg
 
 	initialScanOnly := config.EndTime == initialHighWater
@@ -550,6 +557,16 @@ func (ca *changeAggregator) makeKVFeedCfg(
 		})
 	}
 
+	var initialScanSource kvfeed.InitialScanSource
+	if uri := config.Opts.GetInitialScanSource(); uri != "" {
+		initialScanSource = &backupInitialScanSource{
+			uri:                        uri,
+			user:                       ca.spec.User(),
+			makeExternalStorageFromURI: cfg.ExternalStorageFromURI,
+			mm:                         memMon,
+		}
+	}
+
 	return kvfeed.Config{
 		Writer:               buf,
 		Settings:             cfg.Settings,
@@ -561,6 +578,7 @@ func (ca *changeAggregator) makeKVFeedCfg(
 		Metrics:              &ca.metrics.KVFeedMetrics,
 		MM:                   memMon,
 		InitialHighWater:     initialHighWater,
+		InitialScanSource:    initialScanSource,
 		InitialSpanTimePairs: initialSpanTimePairs,
 		EndTime:              config.EndTime,
 		WithDiff:             filters.WithDiff,
@@ -575,6 +593,8 @@ func (ca *changeAggregator) makeKVFeedCfg(
 		ScopedTimers:         ca.sliMetrics.Timers,
 		MonitoringCfg:        monitoringCfg,
 		ConsumerID:           int64(ca.spec.JobID),
+		RowFilter:            ca.spec.RowFilter,
+		CatchUpMode:          catchUpMode,
 	}, nil
 }
 
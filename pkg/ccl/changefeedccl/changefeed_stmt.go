@@ -12,6 +12,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/backup/backuppb"
 	"github.com/cockroachdb/cockroach/pkg/backup/backupresolver"
 	"github.com/cockroachdb/cockroach/pkg/build"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdceval"
@@ -515,6 +516,13 @@ func changefeedPlanHook(
 				err = errors.WithHint(err,
 					"use a more recent cursor")
 			}
+			if errors.As(err, &e) && opts.GetInitialScanSource() != "" {
+				err = errors.Wrapf(err,
+					"could not create changefeed: the end time of the backup in %s is older than the GC threshold %d",
+					changefeedbase.OptInitialScanSource, e.Threshold.WallTime)
+				err = errors.WithHint(err,
+					"use a more recent backup")
+			}
 		}
 		return err
 	}
@@ -644,6 +652,25 @@ func createChangefeedJobRecord(
 		statementTime = initialHighWater
 	}
 
+	// A changefeed that backfills from a backup starts at the end time of the
+	// backup, so that its rangefeeds pick up exactly where the backup stops.
+	var initialScanSource *backuppb.BackupManifest
+	if uri := opts.GetInitialScanSource(); uri != "" && changefeedStmt.alterChangefeedAsOf.IsEmpty() {
+		if changefeedStmt.Level == tree.ChangefeedLevelDatabase {
+			return nil, changefeedbase.Targets{}, errors.Newf(
+				"%s is not supported for database-level changefeeds", changefeedbase.OptInitialScanSource)
+		}
+		mem := p.ExecCfg().RootMemoryMonitor.MakeBoundAccount()
+		defer mem.Close(ctx)
+		manifest, err := readInitialScanSourceManifest(ctx, &mem, uri, p.User(),
+			p.ExecCfg().DistSQLSrv.ExternalStorageFromURI)
+		if err != nil {
+			return nil, changefeedbase.Targets{}, err
+		}
+		initialScanSource = &manifest
+		statementTime = manifest.EndTime
+	}
+
 	checkPrivs := true
 	if !changefeedStmt.alterChangefeedAsOf.IsEmpty() {
 		statementTime = changefeedStmt.alterChangefeedAsOf
@@ -722,6 +749,20 @@ func createChangefeedJobRecord(
 		return nil, changefeedbase.Targets{}, err
 	}
 
+	if initialScanSource != nil {
+		var targetTables []catalog.TableDescriptor
+		for _, desc := range tableNameToDescriptor {
+			if table, ok := desc.(catalog.TableDescriptor); ok {
+				targetTables = append(targetTables, table)
+			}
+		}
+		if err := validateInitialScanSourceTargets(
+			initialScanSource, p.ExecCfg().Codec, targetTables,
+		); err != nil {
+			return nil, changefeedbase.Targets{}, err
+		}
+	}
+
 	sd := p.SessionData().Clone()
 	// Add non-local session data state (localization, etc).
 	sessiondata.MarshalNonLocal(p.SessionData(), &sd.SessionData)
@@ -843,6 +884,15 @@ func createChangefeedJobRecord(
 		}
 	}
 
+	if changefeedStmt.Select == nil {
+		for _, opt := range []string{changefeedbase.OptTopicExpr, changefeedbase.OptKeyExpr} {
+			if opts.IsSet(opt) {
+				return nil, changefeedbase.Targets{}, errors.Newf(
+					"%s requires a CDC query (CREATE CHANGEFEED ... AS SELECT ...)", opt)
+			}
+		}
+	}
+
 	if changefeedStmt.Select != nil {
 		// Serialize changefeed expression.
 		normalized, withDiff, err := validateAndNormalizeChangefeedExpression(
@@ -851,6 +901,13 @@ func createChangefeedJobRecord(
 		if err != nil {
 			return nil, changefeedbase.Targets{}, err
 		}
+		routingWithDiff, err := validateRoutingExpressions(
+			ctx, p, opts, normalized, tableNameToDescriptor, tableTargets, statementTime,
+		)
+		if err != nil {
+			return nil, changefeedbase.Targets{}, err
+		}
+		withDiff = withDiff || routingWithDiff
 		if withDiff {
 			opts.ForceDiff()
 		} else if opts.IsSet(changefeedbase.OptDiff) {
@@ -1581,6 +1638,37 @@ func validateAndNormalizeChangefeedExpression(
 	return norm, withDiff, nil
 }
 
+// validateRoutingExpressions checks that the topic_expr and key_expr options
+// are valid expressions over the table the CDC query selects from, and returns
+// whether they reference cdc_prev.
+func validateRoutingExpressions(
+	ctx context.Context,
+	execCtx sql.PlanHookState,
+	opts changefeedbase.StatementOptions,
+	normalized *cdceval.NormalizedSelectClause,
+	descriptors map[tree.TablePattern]catalog.Descriptor,
+	targets []jobspb.ChangefeedTargetSpecification,
+	statementTime hlc.Timestamp,
+) (bool, error) {
+	// Normalization may modify the select clause, so the routing expressions
+	// are validated against a copy of the normalized query.
+	sc, err := cdceval.ParseChangefeedExpression(cdceval.AsStringUnredacted(normalized))
+	if err != nil {
+		return false, err
+	}
+	routingSC, err := makeRoutingSelectClause(sc, opts)
+	if err != nil || routingSC == nil {
+		return false, err
+	}
+	_, withDiff, err := validateAndNormalizeChangefeedExpression(
+		ctx, execCtx, opts, routingSC, descriptors, targets, statementTime)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid %s or %s",
+			changefeedbase.OptTopicExpr, changefeedbase.OptKeyExpr)
+	}
+	return withDiff, nil
+}
+
 type changefeedResumer struct {
 	job *jobs.Job
 	sv  *settings.Values
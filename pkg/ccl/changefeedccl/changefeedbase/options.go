@@ -13,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/cloud"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -93,6 +94,18 @@ const (
 	ChangefeedRangeDistributionStrategyNotSpecified ChangefeedRangeDistributionStrategy = ``
 )
 
+// CatchUpMode configures which versions of each row the changefeed emits when
+// it catches up on changes, e.g. after being resumed or when a range moves.
+type CatchUpMode string
+
+const (
+	// CatchUpModeAll emits every version of each row.
+	CatchUpModeAll CatchUpMode = `all`
+	// CatchUpModeLatest emits only the latest version of each row that changed
+	// while the changefeed was catching up, including deletions.
+	CatchUpModeLatest CatchUpMode = `latest`
+)
+
 // Constants for the initial scan types
 const (
 	InitialScan InitialScanType = iota
@@ -140,6 +153,15 @@ const (
 	OptExtraHeaders          = `extra_headers`
 	OptPartitionAlg          = `partition_alg`
 
+	// OptTopicExpr is a SQL expression, evaluated against each row of a CDC
+	// query's table, that names the topic the row is routed to. A NULL result
+	// routes the row to the table's topic. The Kafka and Pub/Sub sinks create
+	// the topics as they are first routed to.
+	OptTopicExpr = `topic_expr`
+	// OptKeyExpr is a SQL expression, evaluated against each row of a CDC
+	// query's table, whose value is used as the message key.
+	OptKeyExpr = `key_expr`
+
 	OptVirtualColumnsOmitted VirtualColumnVisibility = `omitted`
 	OptVirtualColumnsNull    VirtualColumnVisibility = `null`
 
@@ -180,10 +202,19 @@ const (
 
 	OptInitialScanOnly = `initial_scan_only`
 
+	// OptInitialScanSource is the URI of a full backup of the watched tables
+	// that the initial scan reads from instead of the live cluster. The
+	// changefeed starts at the end time of the backup.
+	OptInitialScanSource = `initial_scan_source`
+
 	OptEnrichedProperties = `enriched_properties`
 
 	OptRangeDistributionStrategy = `range_distribution_strategy`
 
+	// OptCatchUpMode controls whether catch-up scans emit every version of a
+	// row or only its latest version. See CatchUpMode.
+	OptCatchUpMode = `catchup_mode`
+
 	OptEnvelopeKeyOnly       EnvelopeType = `key_only`
 	OptEnvelopeRow           EnvelopeType = `row`
 	OptEnvelopeDeprecatedRow EnvelopeType = `deprecated_row`
@@ -217,6 +248,8 @@ const (
 	OptKafkaSinkConfig   = `kafka_sink_config`
 	OptPubsubSinkConfig  = `pubsub_sink_config`
 	OptWebhookSinkConfig = `webhook_sink_config`
+	OptNATSSinkConfig    = `nats_sink_config`
+	OptAMQPSinkConfig    = `amqp_sink_config`
 
 	// OptSink allows users to alter the Sink URI of an existing changefeed.
 	// Note that this option is only allowed for alter changefeed statements.
@@ -241,12 +274,20 @@ const (
 	SinkSchemeCloudStorageHTTPS     = `file-https`
 	SinkSchemeCloudStorageNodelocal = `nodelocal`
 	SinkSchemeCloudStorageS3        = `s3`
+	SinkSchemeCloudStorageSFTP      = `sftp`
+	SinkSchemeCloudStorageWebDAV    = `webdav`
+	SinkSchemeCloudStorageWebDAVS   = `webdavs`
 	SinkSchemeExperimentalSQL       = `experimental-sql`
 	SinkSchemeKafka                 = `kafka`
 	SinkSchemeNull                  = `null`
 	SinkSchemeWebhookHTTP           = `webhook-http`
 	SinkSchemeWebhookHTTPS          = `webhook-https`
 	SinkSchemePulsar                = `pulsar`
+	SinkSchemeNATS                  = `nats`
+	SinkSchemeAMQP                  = `amqp`
+	SinkSchemeAMQPS                 = `amqps`
+	SinkSchemePostgres              = `postgres`
+	SinkSchemePostgresQL            = `postgresql`
 	SinkSchemeExternalConnection    = `external`
 	SinkParamSASLEnabled            = `sasl_enabled`
 	SinkParamSASLHandshake          = `sasl_handshake`
@@ -262,6 +303,12 @@ const (
 	SinkParamSASLAwsRegion          = `sasl_aws_region`
 	SinkParamSASLAwsIAMSessionName  = `sasl_aws_iam_session_name`
 	SinkParamTableNameAttribute     = `with_table_name_attribute`
+	SinkParamNATSStream             = `stream`
+	SinkParamNATSAuthToken          = `auth_token`
+	SinkParamAMQPExchange           = `exchange`
+	SinkParamPostgresSchema         = `schema`
+	SinkParamPostgresTransactional  = `transactional`
+	SinkParamPostgresBatchSize      = `batch_size`
 
 	// These are custom fields required for proprietary oauth. They should not
 	// be documented.
@@ -418,11 +465,14 @@ var ChangefeedOptionExpectValues = map[string]OptionPermittedValues{
 	OptInitialScan:                        enum("yes", "no", "only").orEmptyMeans("yes"),
 	OptNoInitialScan:                      flagOption,
 	OptInitialScanOnly:                    flagOption,
+	OptInitialScanSource:                  stringOption,
 	DeprecatedOptProtectDataFromGCOnPause: flagOption,
 	OptExpirePTSAfter:                     durationOption.thatCanBeZero(),
 	OptKafkaSinkConfig:                    jsonOption,
 	OptPubsubSinkConfig:                   jsonOption,
 	OptWebhookSinkConfig:                  jsonOption,
+	OptNATSSinkConfig:                     jsonOption,
+	OptAMQPSinkConfig:                     jsonOption,
 	OptWebhookAuthHeader:                  stringOption,
 	OptWebhookClientTimeout:               durationOption,
 	OptOnError:                            enum("pause", "fail"),
@@ -436,9 +486,12 @@ var ChangefeedOptionExpectValues = map[string]OptionPermittedValues{
 	OptEncodeJSONValueNullAsObject:        flagOption,
 	OptEnrichedProperties:                 csv(string(EnrichedPropertySource), string(EnrichedPropertySchema)),
 	OptRangeDistributionStrategy:          enum(string(ChangefeedRangeDistributionStrategyDefault), string(ChangefeedRangeDistributionStrategyBalancedSimple)),
+	OptCatchUpMode:                        enum(string(CatchUpModeAll), string(CatchUpModeLatest)),
 	OptHeadersJSONColumnName:              stringOption,
 	OptExtraHeaders:                       jsonOption,
 	OptPartitionAlg:                       enum("fnv-1a", "murmur2"),
+	OptTopicExpr:                          stringOption,
+	OptKeyExpr:                            stringOption,
 }
 
 // CommonOptions is options common to all sinks
@@ -449,18 +502,18 @@ var CommonOptions = makeStringSet(OptCursor, OptEndTime, OptEnvelope,
 	OptMVCCTimestamps, OptDiff, OptSplitColumnFamilies,
 	OptSchemaChangeEvents, OptSchemaChangePolicy,
 	OptOnError,
-	OptInitialScan, OptNoInitialScan, OptInitialScanOnly, OptUnordered, OptCustomKeyColumn,
+	OptInitialScan, OptNoInitialScan, OptInitialScanOnly, OptInitialScanSource, OptUnordered, OptCustomKeyColumn,
 	OptMinCheckpointFrequency, OptMetricsScope, OptVirtualColumns, Topics, OptExpirePTSAfter,
 	OptExecutionLocality, OptLaggingRangesThreshold, OptLaggingRangesPollingInterval,
 	OptIgnoreDisableChangefeedReplication, OptEncodeJSONValueNullAsObject, OptEnrichedProperties,
-	OptRangeDistributionStrategy, OptHibernationPollingFrequency,
+	OptRangeDistributionStrategy, OptHibernationPollingFrequency, OptKeyExpr, OptCatchUpMode,
 )
 
 // SQLValidOptions is options exclusive to SQL sink
 var SQLValidOptions map[string]struct{} = nil
 
 // KafkaValidOptions is options exclusive to Kafka sink
-var KafkaValidOptions = makeStringSet(OptAvroSchemaPrefix, OptConfluentSchemaRegistry, OptKafkaSinkConfig, OptHeadersJSONColumnName, OptExtraHeaders, OptPartitionAlg)
+var KafkaValidOptions = makeStringSet(OptAvroSchemaPrefix, OptConfluentSchemaRegistry, OptKafkaSinkConfig, OptHeadersJSONColumnName, OptExtraHeaders, OptPartitionAlg, OptTopicExpr)
 
 // CloudStorageValidOptions is options exclusive to cloud storage sink
 var CloudStorageValidOptions = makeStringSet(OptCompression)
@@ -469,7 +522,16 @@ var CloudStorageValidOptions = makeStringSet(OptCompression)
 var WebhookValidOptions = makeStringSet(OptWebhookAuthHeader, OptWebhookClientTimeout, OptWebhookSinkConfig, OptCompression, OptExtraHeaders)
 
 // PubsubValidOptions is options exclusive to pubsub sink
-var PubsubValidOptions = makeStringSet(OptPubsubSinkConfig)
+var PubsubValidOptions = makeStringSet(OptPubsubSinkConfig, OptTopicExpr)
+
+// NATSValidOptions is options exclusive to the NATS JetStream sink
+var NATSValidOptions = makeStringSet(OptNATSSinkConfig, OptTopicExpr)
+
+// AMQPValidOptions is options exclusive to the AMQP sink
+var AMQPValidOptions = makeStringSet(OptAMQPSinkConfig, OptTopicExpr)
+
+// PostgresValidOptions is options exclusive to the postgres apply sink
+var PostgresValidOptions map[string]struct{} = nil
 
 // ExternalConnectionValidOptions is options exclusive to the external
 // connection sink.
@@ -477,7 +539,7 @@ var PubsubValidOptions = makeStringSet(OptPubsubSinkConfig)
 // TODO(adityamaru): Some of these options should be supported when creating the
 // external connection rather than when setting up the changefeed. Move them once
 // we support `CREATE EXTERNAL CONNECTION ... WITH <options>`.
-var ExternalConnectionValidOptions = unionStringSets(SQLValidOptions, KafkaValidOptions, CloudStorageValidOptions, WebhookValidOptions, PubsubValidOptions)
+var ExternalConnectionValidOptions = unionStringSets(SQLValidOptions, KafkaValidOptions, CloudStorageValidOptions, WebhookValidOptions, PubsubValidOptions, NATSValidOptions, AMQPValidOptions)
 
 // CaseInsensitiveOpts options which supports case Insensitive value
 var CaseInsensitiveOpts = makeStringSet(OptFormat, OptEnvelope, OptCompression, OptSchemaChangeEvents,
@@ -522,12 +584,17 @@ func RedactUserFromURI(uri string) (string, error) {
 	return u.String(), nil
 }
 
+func redactExternalStorageURI(uri string) (string, error) {
+	return cloud.SanitizeExternalStorageURI(uri, nil /* extraParams */)
+}
+
 // RedactedOptions are options whose values should be replaced with "redacted" in job descriptions and errors.
 var RedactedOptions = map[string]redactionFunc{
 	OptWebhookAuthHeader:       redactSimple,
 	OptExtraHeaders:            redactSimple,
 	SinkParamClientKey:         redactSimple,
 	OptConfluentSchemaRegistry: RedactUserFromURI,
+	OptInitialScanSource:       redactExternalStorageURI,
 }
 
 // NoLongerExperimental aliases options prefixed with experimental that no longer need to be
@@ -560,7 +627,7 @@ var ParquetFormatUnsupportedOptions OptionsSet = makeStringSet(OptTopicInValue)
 // allowed to alter either of these options. We need to support the alteration
 // of these fields.
 var AlterChangefeedUnsupportedOptions OptionsSet = makeStringSet(OptCursor, OptInitialScan,
-	OptNoInitialScan, OptInitialScanOnly, OptEndTime)
+	OptNoInitialScan, OptInitialScanOnly, OptInitialScanSource, OptEndTime)
 
 // AlterChangefeedOptionExpectValues is used to parse alter changefeed options
 // using PlanHookState.TypeAsStringOpts().
@@ -610,11 +677,15 @@ func makeDirectedInvertedIndex(pairs []dependentOption) map[string][]dependentOp
 }
 
 var incompatibleOptionsMap = makeInvertedIndex([]incompatibleOptions{
+	{opt1: OptInitialScanSource, opt2: OptCursor, reason: `the changefeed starts at the end time of the backup`},
 	{opt1: OptUnordered, opt2: OptResolvedTimestamps, reason: `resolved timestamps cannot be guaranteed to be correct in unordered mode`},
+	{opt1: OptKeyExpr, opt2: OptCustomKeyColumn, reason: `both set the message key`},
 })
 
 var dependentOptionsMap = makeDirectedInvertedIndex([]dependentOption{
 	{opt1: OptCustomKeyColumn, opt2: OptUnordered, reason: `using a value other than the primary key as the message key means end-to-end ordering cannot be preserved`},
+	{opt1: OptKeyExpr, opt2: OptUnordered, reason: `using a value other than the primary key as the message key means end-to-end ordering cannot be preserved`},
+	{opt1: OptTopicExpr, opt2: OptUnordered, reason: `the changes to a row may be routed to different topics, so end-to-end ordering cannot be preserved`},
 })
 
 // MakeStatementOptions wraps and canonicalizes the options we get
@@ -698,6 +769,24 @@ func (s StatementOptions) GetCursor() string {
 	return s.m[OptCursor]
 }
 
+// GetInitialScanSource returns the URI of the backup the initial scan reads
+// from, or the empty string if the initial scan reads from the cluster.
+func (s StatementOptions) GetInitialScanSource() string {
+	return s.m[OptInitialScanSource]
+}
+
+// GetTopicExpr returns the expression that routes rows to topics, or the
+// empty string if rows go to their table's topic.
+func (s StatementOptions) GetTopicExpr() string {
+	return s.m[OptTopicExpr]
+}
+
+// GetKeyExpr returns the expression that computes the message key, or the
+// empty string if the key is computed from the primary key.
+func (s StatementOptions) GetKeyExpr() string {
+	return s.m[OptKeyExpr]
+}
+
 // HasEndTime returns true if an end time was provided.
 func (s StatementOptions) HasEndTime() bool {
 	_, ok := s.m[OptEndTime]
@@ -858,6 +947,19 @@ func (s StatementOptions) GetChangefeedRangeDistributionStrategy() (
 	return ChangefeedRangeDistributionStrategy(v), nil
 }
 
+// GetCatchUpMode returns the catch-up mode of the changefeed, which defaults
+// to CatchUpModeAll.
+func (s StatementOptions) GetCatchUpMode() (CatchUpMode, error) {
+	v, err := s.getEnumValue(OptCatchUpMode)
+	if err != nil {
+		return "", err
+	}
+	if v == `` {
+		return CatchUpModeAll, nil
+	}
+	return CatchUpMode(v), nil
+}
+
 // ShouldUseFullStatementTimeName returns true if references to the table should be in db.schema.table
 // format (e.g. in Kafka topics).
 func (s StatementOptions) ShouldUseFullStatementTimeName() bool {
@@ -1169,6 +1271,10 @@ type KafkaSinkOptions struct {
 	// PartitionAlg is the hash function to use for Kafka partitioning.
 	// Valid values are "fnv-1a" (default) and "murmur2".
 	PartitionAlg string
+
+	// CreateTopics is set when rows may be routed to topics that are only
+	// known once the rows are seen, which the sink must then create.
+	CreateTopics bool
 }
 
 func (s StatementOptions) GetKafkaSinkOptions() (KafkaSinkOptions, error) {
@@ -1186,6 +1292,7 @@ func (s StatementOptions) GetKafkaSinkOptions() (KafkaSinkOptions, error) {
 		JSONConfig:   s.getJSONValue(OptKafkaSinkConfig),
 		Headers:      headersMap,
 		PartitionAlg: partitionAlg,
+		CreateTopics: s.IsSet(OptTopicExpr),
 	}
 	return o, nil
 }
@@ -1201,6 +1308,18 @@ func (s StatementOptions) GetPubsubConfigJSON() SinkSpecificJSONConfig {
 	return s.getJSONValue(OptPubsubSinkConfig)
 }
 
+// GetNATSConfigJSON returns arbitrary json to be interpreted
+// by the NATS JetStream sink.
+func (s StatementOptions) GetNATSConfigJSON() SinkSpecificJSONConfig {
+	return s.getJSONValue(OptNATSSinkConfig)
+}
+
+// GetAMQPConfigJSON returns arbitrary json to be interpreted
+// by the AMQP sink.
+func (s StatementOptions) GetAMQPConfigJSON() SinkSpecificJSONConfig {
+	return s.getJSONValue(OptAMQPSinkConfig)
+}
+
 // GetResolvedTimestampInterval gets the best-effort interval at which resolved timestamps
 // should be emitted. Nil or 0 means emit as often as possible. False means do not emit at all.
 // Returns an error for negative or invalid duration value.
@@ -1405,6 +1524,12 @@ func (s StatementOptions) ValidateForCreateChangefeed(isPredicateChangefeed bool
 		}
 		return nil
 	}
+	if s.IsSet(OptKeyExpr) && s.m[OptFormat] != `` && s.m[OptFormat] != string(OptFormatJSON) {
+		return errors.Newf(`%s is only usable with %s=%s`, OptKeyExpr, OptFormat, OptFormatJSON)
+	}
+	if scanType == NoInitialScan && s.IsSet(OptInitialScanSource) {
+		return errors.Newf(`%s requires an initial scan`, OptInitialScanSource)
+	}
 	if scanType == OnlyInitialScan {
 		if err := validateUnsupportedOptions(InitialScanOnlyUnsupportedOptions,
 			fmt.Sprintf("%s='only'", OptInitialScan)); err != nil {
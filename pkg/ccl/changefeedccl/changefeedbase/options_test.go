@@ -37,6 +37,16 @@ func TestOptionsValidations(t *testing.T) {
 		{map[string]string{"initial_scan_only": "", "resolved": ""}, true, "cannot specify both initial_scan='only'"},
 		{map[string]string{"initial_scan_only": "", "resolved": ""}, true, "cannot specify both initial_scan='only'"},
 		{map[string]string{"key_column": "b"}, false, "requires the unordered option"},
+		{map[string]string{"initial_scan_source": "nodelocal://1/b", "initial_scan": "no"}, false, "requires an initial scan"},
+		{map[string]string{"initial_scan_source": "nodelocal://1/b", "cursor": "-1s"}, false, "not usable with cursor"},
+		{map[string]string{"initial_scan_source": "nodelocal://1/b", "initial_scan": "only"}, false, ""},
+		{map[string]string{"key_expr": "b"}, false, "requires the unordered option"},
+		{map[string]string{"topic_expr": "b"}, false, "requires the unordered option"},
+		{map[string]string{"key_expr": "b", "key_column": "b", "unordered": ""}, false, "not usable with key_column"},
+		{map[string]string{"key_expr": "b", "unordered": "", "format": "avro"}, false, "only usable with format=json"},
+		{map[string]string{"key_expr": "b", "topic_expr": "c", "unordered": ""}, false, ""},
+		{map[string]string{"catchup_mode": "latest"}, false, ""},
+		{map[string]string{"catchup_mode": "newest"}, false, "unknown catchup_mode"},
 	}
 
 	for _, test := range tests {
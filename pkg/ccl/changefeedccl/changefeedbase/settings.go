@@ -493,3 +493,26 @@ var FrontierPersistenceInterval = settings.RegisterDurationSettingWithExplicitUn
 	settings.DurationInRange(5*time.Second, 10*time.Minute),
 	settings.WithPublic,
 )
+
+// TopicExprMaxTopics caps the number of distinct topics a changefeed
+// processor may route rows to using the topic_expr option.
+var TopicExprMaxTopics = settings.RegisterIntSetting(
+	settings.ApplicationLevel,
+	"changefeed.topic_expr.max_topics",
+	"the maximum number of distinct topics a changefeed processor may route rows to "+
+		"using topic_expr; the changefeed fails when the limit is exceeded",
+	256,
+	settings.PositiveInt,
+)
+
+// RangefeedPredicatePushdownEnabled controls whether changefeeds with a CDC
+// query push a conservative form of the query's predicate and projection
+// down into their rangefeed registrations, so that the leaseholders filter
+// rows before sending them to the changefeed.
+var RangefeedPredicatePushdownEnabled = settings.RegisterBoolSetting(
+	settings.ApplicationLevel,
+	"changefeed.rangefeed_predicate_pushdown.enabled",
+	"if true, changefeeds with a CDC query filter rows and columns "+
+		"on the leaseholders before they are sent to the changefeed",
+	metamorphic.ConstantWithTestBool("changefeed.rangefeed_predicate_pushdown.enabled", true),
+)
@@ -65,6 +65,7 @@ type kvEventToRowConsumer struct {
 	decoder      cdcevent.Decoder
 	details      ChangefeedConfig
 	evaluator    *cdceval.Evaluator
+	router       *rowRouter
 	encodingOpts changefeedbase.EncodingOptions
 
 	topicDescriptorCache map[TopicIdentifier]TopicDescriptor
@@ -108,6 +109,7 @@ func newEventConsumer(
 	}
 
 	pacerRequestUnit := changefeedbase.EventConsumerPacerRequestSize.Get(&cfg.Settings.SV)
+	routedTopics := makeRoutedTopics(&cfg.Settings.SV)
 	enablePacer := changefeedbase.PerEventElasticCPUControlEnabled.Get(&cfg.Settings.SV)
 
 	makeConsumer := func(s EventSink, frontier frontier) (eventConsumer, error) {
@@ -170,7 +172,7 @@ func newEventConsumer(
 
 		execCfg := cfg.ExecutorConfig.(*sql.ExecutorConfig)
 		return newKVEventToRowConsumer(ctx, execCfg, frontier, cursor, s,
-			encoder, feed, spec, knobs, topicNamer, sliMetrics, pacer)
+			encoder, feed, spec, knobs, topicNamer, routedTopics, sliMetrics, pacer)
 	}
 
 	numWorkers := changefeedbase.EventConsumerWorkers.Get(&cfg.Settings.SV)
@@ -249,6 +251,7 @@ func newKVEventToRowConsumer(
 	spec execinfrapb.ChangeAggregatorSpec,
 	knobs TestingKnobs,
 	topicNamer *TopicNamer,
+	routedTopics *routedTopics,
 	metrics *sliMetrics,
 	pacer *admission.Pacer,
 ) (_ *kvEventToRowConsumer, err error) {
@@ -260,11 +263,16 @@ func newKVEventToRowConsumer(
 	}
 
 	var evaluator *cdceval.Evaluator
+	var router *rowRouter
 	if spec.Select.Expr != "" {
 		evaluator, err = newEvaluator(ctx, cfg, spec, details.Opts.GetFilters().WithDiff)
 		if err != nil {
 			return nil, err
 		}
+		router, err = newRowRouter(ctx, cfg, spec, details.Opts, routedTopics)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	encodingOpts, err := details.Opts.GetEncodingOptions()
@@ -283,6 +291,7 @@ func newKVEventToRowConsumer(
 		topicDescriptorCache: make(map[TopicIdentifier]TopicDescriptor),
 		topicNamer:           topicNamer,
 		evaluator:            evaluator,
+		router:               router,
 		encodingOpts:         encodingOpts,
 		metrics:              metrics,
 		pacer:                pacer,
@@ -407,7 +416,9 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 		return nil
 	}
 
+	var route rowRoute
 	if c.evaluator != nil {
+		sourceRow := updatedRow
 		updatedRow, err = c.evaluator.Eval(ctx, updatedRow, prevRow)
 		if err != nil {
 			return err
@@ -420,15 +431,22 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 			a.Release(ctx)
 			return nil
 		}
+
+		if c.router != nil {
+			if route, err = c.router.route(ctx, sourceRow, prevRow); err != nil {
+				return err
+			}
+		}
 	}
 
-	return c.encodeAndEmit(ctx, updatedRow, prevRow, schemaTimestamp, ev.DetachAlloc())
+	return c.encodeAndEmit(ctx, updatedRow, prevRow, route, schemaTimestamp, ev.DetachAlloc())
 }
 
 func (c *kvEventToRowConsumer) encodeAndEmit(
 	ctx context.Context,
 	updatedRow cdcevent.Row,
 	prevRow cdcevent.Row,
+	route rowRoute,
 	schemaTS hlc.Timestamp,
 	alloc kvevent.Alloc,
 ) error {
@@ -436,6 +454,9 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 	if err != nil {
 		return err
 	}
+	if route.topic != "" {
+		topic = &routedTopic{TopicDescriptor: topic, name: route.topic}
+	}
 
 	// Ensure that r updates are strictly newer than the least resolved timestamp
 	// being tracked by the local span frontier. The poller should not be forwarding
@@ -475,9 +496,12 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 		)
 	}
 	var keyCopy, valueCopy []byte
-	encodedKey, err := c.encoder.EncodeKey(ctx, updatedRow)
-	if err != nil {
-		return err
+	encodedKey := route.key
+	if encodedKey == nil {
+		encodedKey, err = c.encoder.EncodeKey(ctx, updatedRow)
+		if err != nil {
+			return err
+		}
 	}
 	c.scratch, keyCopy = c.scratch.Copy(encodedKey)
 	// TODO(yevgeniy): Some refactoring is needed in the encoder: namely, prevRow
@@ -576,6 +600,9 @@ func (c *kvEventToRowConsumer) Close() error {
 	if c.evaluator != nil {
 		c.evaluator.Close()
 	}
+	if c.router != nil {
+		c.router.Close()
+	}
 	return nil
 }
 
@@ -0,0 +1,290 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/backup/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupinfo"
+	"github.com/cockroachdb/cockroach/pkg/backup/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/backup/backupsink"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvfeed"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+)
+
+// readInitialScanSourceManifest reads the manifest of the backup named by the
+// initial_scan_source option and checks that a changefeed can backfill from
+// it.
+func readInitialScanSourceManifest(
+	ctx context.Context,
+	mem *mon.BoundAccount,
+	uri string,
+	user username.SQLUsername,
+	makeExternalStorageFromURI cloud.ExternalStorageFromURIFactory,
+) (backuppb.BackupManifest, error) {
+	// The changefeed has no options to pass the encryption passphrase or KMS
+	// URIs of the backup with, so encrypted backups are rejected up front
+	// rather than failing to read the manifest.
+	if encrypted, err := isEncryptedInitialScanSource(ctx, uri, user, makeExternalStorageFromURI); err != nil {
+		return backuppb.BackupManifest{}, err
+	} else if encrypted {
+		return backuppb.BackupManifest{}, errors.Newf(
+			"%s does not support encrypted backups", changefeedbase.OptInitialScanSource)
+	}
+	manifest, _, err := backupinfo.ReadBackupManifestFromURI(ctx, mem, uri, user,
+		makeExternalStorageFromURI, nil /* encryption */, nil /* kmsEnv */)
+	if err != nil {
+		return backuppb.BackupManifest{}, errors.Wrapf(err,
+			"reading backup manifest for %s", changefeedbase.OptInitialScanSource)
+	}
+	if !manifest.StartTime.IsEmpty() {
+		return backuppb.BackupManifest{}, errors.Newf(
+			"%s must be a full backup, not an incremental backup", changefeedbase.OptInitialScanSource)
+	}
+	if len(manifest.LocalityKVs) > 0 || len(manifest.PartitionDescriptorFilenames) > 0 {
+		return backuppb.BackupManifest{}, errors.Newf(
+			"%s does not support locality-aware backups", changefeedbase.OptInitialScanSource)
+	}
+	if manifest.EndTime.IsEmpty() {
+		return backuppb.BackupManifest{}, errors.Newf(
+			"backup in %s has no end time", changefeedbase.OptInitialScanSource)
+	}
+	return manifest, nil
+}
+
+// isEncryptedInitialScanSource returns whether the backup at the URI is
+// encrypted.
+func isEncryptedInitialScanSource(
+	ctx context.Context,
+	uri string,
+	user username.SQLUsername,
+	makeExternalStorageFromURI cloud.ExternalStorageFromURIFactory,
+) (bool, error) {
+	store, err := makeExternalStorageFromURI(ctx, uri, user)
+	if err != nil {
+		return false, err
+	}
+	defer store.Close()
+	files, err := backupencryption.GetEncryptionInfoFiles(ctx, store)
+	return err == nil && len(files) > 0, nil
+}
+
+// validateInitialScanSourceTargets checks that the backup contains the data of
+// every target table, as the tables were at the end time of the backup.
+func validateInitialScanSourceTargets(
+	manifest *backuppb.BackupManifest, codec keys.SQLCodec, tables []catalog.TableDescriptor,
+) error {
+	backedUp := make(map[descpb.ID]struct{}, len(manifest.Descriptors))
+	for i := range manifest.Descriptors {
+		id, _, _, _, err := descpb.GetDescriptorMetadata(&manifest.Descriptors[i])
+		if err != nil {
+			return err
+		}
+		backedUp[id] = struct{}{}
+	}
+	var covered roachpb.SpanGroup
+	covered.Add(manifest.Spans...)
+
+	for _, table := range tables {
+		if _, ok := backedUp[table.GetID()]; !ok {
+			return errors.Newf("table %q is not in the backup in %s",
+				table.GetName(), changefeedbase.OptInitialScanSource)
+		}
+		if !covered.Encloses(table.PrimaryIndexSpan(codec)) {
+			return errors.Newf("backup in %s does not contain the rows of table %q",
+				changefeedbase.OptInitialScanSource, table.GetName())
+		}
+	}
+	return nil
+}
+
+// backupInitialScanSource is a kvfeed.InitialScanSource that reads the
+// initial scan of a changefeed from the data files of a full backup instead
+// of scanning the cluster. The scan must be at the end time of the backup.
+type backupInitialScanSource struct {
+	uri                        string
+	user                       username.SQLUsername
+	makeExternalStorageFromURI cloud.ExternalStorageFromURIFactory
+	mm                         *mon.BytesMonitor
+}
+
+var _ kvfeed.InitialScanSource = (*backupInitialScanSource)(nil)
+
+// Scan implements the kvfeed.InitialScanSource interface.
+func (s *backupInitialScanSource) Scan(
+	ctx context.Context,
+	sink kvevent.Writer,
+	spans []roachpb.Span,
+	ts hlc.Timestamp,
+	boundary jobspb.ResolvedSpan_BoundaryType,
+) error {
+	mem := s.mm.MakeBoundAccount()
+	defer mem.Close(ctx)
+	manifest, err := readInitialScanSourceManifest(ctx, &mem, s.uri, s.user, s.makeExternalStorageFromURI)
+	if err != nil {
+		return err
+	}
+	if !ts.Equal(manifest.EndTime) {
+		return errors.Newf("cannot read a scan at %s from %s: the backup ends at %s",
+			ts, changefeedbase.OptInitialScanSource, manifest.EndTime)
+	}
+
+	store, err := s.makeExternalStorageFromURI(ctx, s.uri, s.user)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	files, err := collectBackupFiles(ctx, &mem, &manifest, store, spans)
+	if err != nil {
+		return err
+	}
+	for _, sp := range spans {
+		if err := scanBackupSpan(ctx, sink, &manifest, store, files, sp, ts, boundary); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectBackupFiles returns the data files of the backup which overlap the
+// spans. The retained files are accounted for in the memory account.
+func collectBackupFiles(
+	ctx context.Context,
+	mem *mon.BoundAccount,
+	manifest *backuppb.BackupManifest,
+	store cloud.ExternalStorage,
+	spans []roachpb.Span,
+) ([]backuppb.BackupManifest_File, error) {
+	spans, _ = roachpb.MergeSpans(append([]roachpb.Span(nil), spans...))
+	overlaps := func(sp roachpb.Span) bool {
+		// The first span which ends after the start of sp is the only one which
+		// can overlap it, since the merged spans are sorted and disjoint.
+		i := sort.Search(len(spans), func(i int) bool {
+			return spans[i].EndKey.Compare(sp.Key) > 0
+		})
+		return i < len(spans) && spans[i].Overlaps(sp)
+	}
+
+	it, err := backupinfo.NewIterFactory(manifest, store, nil /* encryption */, nil /* kmsEnv */).
+		NewFileIter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	var files []backuppb.BackupManifest_File
+	for ; ; it.Next() {
+		if ok, err := it.Valid(); err != nil {
+			return nil, err
+		} else if !ok {
+			break
+		}
+		f := it.Value()
+		if !overlaps(f.Span) {
+			continue
+		}
+		if err := mem.Grow(ctx, int64(f.Size())); err != nil {
+			return nil, errors.Wrap(err, "retaining backup file metadata")
+		}
+		files = append(files, *f)
+	}
+	return files, nil
+}
+
+// scanBackupSpan writes every live KV in the span as of the end time of the
+// backup to the sink, followed by a resolved event for the span.
+func scanBackupSpan(
+	ctx context.Context,
+	sink kvevent.Writer,
+	manifest *backuppb.BackupManifest,
+	store cloud.ExternalStorage,
+	files []backuppb.BackupManifest_File,
+	sp roachpb.Span,
+	ts hlc.Timestamp,
+	boundary jobspb.ResolvedSpan_BoundaryType,
+) error {
+	var storeFiles []storage.StoreFile
+	for _, f := range files {
+		if f.Span.Overlaps(sp) {
+			storeFiles = append(storeFiles, storage.StoreFile{Store: store, FilePath: f.Path})
+		}
+	}
+	if log.V(2) {
+		log.Changefeed.Infof(ctx, "scanning %s at %s from %d backup files", sp, ts, len(storeFiles))
+	}
+
+	if len(storeFiles) > 0 {
+		if err := func() error {
+			iter, err := storage.ExternalSSTReader(ctx, storeFiles, nil /* encryption */, storage.IterOptions{
+				RangeKeyMaskingBelow: manifest.EndTime,
+				KeyTypes:             storage.IterKeyTypePointsAndRanges,
+				LowerBound:           keys.LocalMax,
+				UpperBound:           keys.MaxKey,
+			})
+			if err != nil {
+				return err
+			}
+			readAsOf := storage.NewReadAsOfIterator(iter, manifest.EndTime)
+			defer readAsOf.Close()
+
+			// Keys in the backup files may be stored without their tenant or
+			// table prefix, which has to be added back.
+			elidedPrefix, err := backupsink.ElidedPrefix(sp.Key, manifest.ElidedPrefix)
+			if err != nil {
+				return err
+			}
+			start := storage.MVCCKey{Key: bytes.TrimPrefix(sp.Key, elidedPrefix)}
+			for readAsOf.SeekGE(start); ; readAsOf.NextKey() {
+				if ok, err := readAsOf.Valid(); err != nil {
+					return errors.Wrapf(err, "reading backup files for %s", sp)
+				} else if !ok {
+					break
+				}
+				key := readAsOf.UnsafeKey()
+				keyBytes := make(roachpb.Key, 0, len(elidedPrefix)+len(key.Key))
+				keyBytes = append(append(keyBytes, elidedPrefix...), key.Key...)
+				if keyBytes.Compare(sp.EndKey) >= 0 {
+					break
+				}
+				v, err := readAsOf.UnsafeValue()
+				if err != nil {
+					return err
+				}
+				value, err := storage.DecodeValueFromMVCCValue(v)
+				if err != nil {
+					return errors.Wrapf(err, "decoding backup value for %s", keyBytes)
+				}
+				valBytes := append([]byte(nil), value.RawBytes...)
+				if err := sink.Add(ctx, kvevent.NewBackfillKVEvent(
+					keyBytes, key.Timestamp, valBytes, false /* withDiff */, ts,
+				)); err != nil {
+					return errors.Wrapf(err, `buffering changes for %s`, sp)
+				}
+			}
+			return nil
+		}(); err != nil {
+			return err
+		}
+	}
+	return sink.Add(ctx, kvevent.NewBackfillResolvedEvent(sp, ts, boundary))
+}
@@ -0,0 +1,234 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedInitialScanSource(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'backed up'), (2, 'backed up')`)
+		sqlDB.Exec(t, `BACKUP TABLE foo INTO 'nodelocal://1/foo'`)
+		var path string
+		sqlDB.QueryRow(t, `SELECT path FROM [SHOW BACKUPS IN 'nodelocal://1/foo']`).Scan(&path)
+		backupURI := `nodelocal://1/foo` + path
+
+		// Changes made after the backup are picked up by the rangefeed.
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, 'after')`)
+		sqlDB.Exec(t, `UPDATE foo SET b = 'after' WHERE a = 1`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 2`)
+
+		t.Run("backfill", func(t *testing.T) {
+			// The initial scan is read from the backup, so the changefeed must
+			// not scan the table in the cluster.
+			var clusterScans int32
+			knobs := s.TestingKnobs.
+				DistSQL.(*execinfra.TestingKnobs).
+				Changefeed.(*TestingKnobs)
+			knobs.FeedKnobs.BeforeScanRequest = func(_ *kv.Batch) error {
+				atomic.AddInt32(&clusterScans, 1)
+				return nil
+			}
+			defer func() { knobs.FeedKnobs.BeforeScanRequest = nil }()
+
+			foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH initial_scan_source = $1`, backupURI)
+			defer closeFeed(t, foo)
+			assertPayloads(t, foo, []string{
+				`foo: [1]->{"after": {"a": 1, "b": "backed up"}}`,
+				`foo: [2]->{"after": {"a": 2, "b": "backed up"}}`,
+				`foo: [3]->{"after": {"a": 3, "b": "after"}}`,
+				`foo: [1]->{"after": {"a": 1, "b": "after"}}`,
+				`foo: [2]->{"after": null}`,
+			})
+			require.Zero(t, atomic.LoadInt32(&clusterScans))
+		})
+
+		t.Run("table not in backup", func(t *testing.T) {
+			expectErrCreatingFeed(t, f,
+				`CREATE CHANGEFEED FOR foo, bar WITH initial_scan_source = '`+backupURI+`'`,
+				`table "bar" is not in the backup in initial_scan_source`)
+		})
+
+		t.Run("missing backup", func(t *testing.T) {
+			expectErrCreatingFeed(t, f,
+				`CREATE CHANGEFEED FOR foo WITH initial_scan_source = 'nodelocal://1/missing'`,
+				`reading backup manifest for initial_scan_source`)
+		})
+
+		t.Run("encrypted backup", func(t *testing.T) {
+			sqlDB.Exec(t, `BACKUP TABLE foo INTO 'nodelocal://1/encrypted' WITH encryption_passphrase = 'abc'`)
+			var path string
+			sqlDB.QueryRow(t, `SELECT path FROM [SHOW BACKUPS IN 'nodelocal://1/encrypted']`).Scan(&path)
+			expectErrCreatingFeed(t, f,
+				`CREATE CHANGEFEED FOR foo WITH initial_scan_source = 'nodelocal://1/encrypted`+path+`'`,
+				`initial_scan_source does not support encrypted backups`)
+		})
+	}
+
+	cdcTest(t, testFn)
+}
+
+// recordingKVEventWriter is a kvevent.Writer that records the events added to
+// it.
+type recordingKVEventWriter struct {
+	events []kvevent.Event
+}
+
+var _ kvevent.Writer = (*recordingKVEventWriter)(nil)
+
+func (w *recordingKVEventWriter) Add(ctx context.Context, ev kvevent.Event) error {
+	w.events = append(w.events, ev)
+	return nil
+}
+
+func (w *recordingKVEventWriter) Drain(ctx context.Context) error {
+	return nil
+}
+
+func (w *recordingKVEventWriter) CloseWithReason(ctx context.Context, reason error) error {
+	return nil
+}
+
+// TestBackupInitialScanSourceScan checks the events the backup initial scan
+// source emits: every KV in the backup as of its end time, with its original
+// MVCC timestamp, followed by a resolved event for the span.
+func TestBackupInitialScanSourceScan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		ctx := context.Background()
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'one'), (2, 'two'), (3, 'three')`)
+
+		execCfg := s.Server.ExecutorConfig().(sql.ExecutorConfig)
+		fooDesc := cdctest.GetHydratedTableDescriptor(t, s.Server.ExecutorConfig(), "foo")
+		fooSpan := fooDesc.PrimaryIndexSpan(s.Codec)
+
+		// The table is not written to before the backup, so its contents now
+		// are its contents as of the end time of the backup.
+		want, err := s.Server.DB().Scan(ctx, fooSpan.Key, fooSpan.EndKey, 0 /* maxRows */)
+		require.NoError(t, err)
+		require.Len(t, want, 3)
+
+		sqlDB.Exec(t, `BACKUP TABLE foo INTO 'nodelocal://1/foo'`)
+		var path string
+		sqlDB.QueryRow(t, `SELECT path FROM [SHOW BACKUPS IN 'nodelocal://1/foo']`).Scan(&path)
+		backupURI := `nodelocal://1/foo` + path
+
+		// Changes made after the backup must not be emitted by the scan.
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (4, 'four')`)
+		sqlDB.Exec(t, `UPDATE foo SET b = 'uno' WHERE a = 1`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 2`)
+
+		mm := mon.NewUnlimitedMonitor(ctx, mon.Options{
+			Name:     mon.MakeName("test"),
+			Settings: execCfg.Settings,
+		})
+		defer mm.Stop(ctx)
+		src := &backupInitialScanSource{
+			uri:                        backupURI,
+			user:                       username.RootUserName(),
+			makeExternalStorageFromURI: execCfg.DistSQLSrv.ExternalStorageFromURI,
+			mm:                         mm,
+		}
+		mem := mm.MakeBoundAccount()
+		defer mem.Close(ctx)
+		manifest, err := readInitialScanSourceManifest(ctx, &mem, src.uri, src.user,
+			src.makeExternalStorageFromURI)
+		require.NoError(t, err)
+		endTime := manifest.EndTime
+
+		t.Run("emits the backed up rows", func(t *testing.T) {
+			var w recordingKVEventWriter
+			require.NoError(t, src.Scan(ctx, &w, []roachpb.Span{fooSpan}, endTime,
+				jobspb.ResolvedSpan_BACKFILL))
+			require.Len(t, w.events, len(want)+1)
+
+			for i, kv := range want {
+				ev := w.events[i]
+				require.Equal(t, kvevent.TypeKV, ev.Type())
+				got := ev.KV()
+				require.Equal(t, kv.Key, got.Key)
+				require.Equal(t, kv.Value.Timestamp, got.Value.Timestamp)
+				require.Equal(t, kv.Value.TagAndDataBytes(), got.Value.TagAndDataBytes())
+				require.Equal(t, endTime, ev.BackfillTimestamp())
+			}
+
+			resolved := w.events[len(want)]
+			require.Equal(t, kvevent.TypeResolved, resolved.Type())
+			require.Equal(t, fooSpan, resolved.Resolved().Span)
+			require.Equal(t, endTime, resolved.Resolved().Timestamp)
+			require.Equal(t, jobspb.ResolvedSpan_BACKFILL, resolved.Resolved().BoundaryType)
+		})
+
+		t.Run("span outside the table", func(t *testing.T) {
+			// A span the backup has no files for only gets a resolved event.
+			other := roachpb.Span{Key: fooSpan.EndKey, EndKey: fooSpan.EndKey.PrefixEnd()}
+			var w recordingKVEventWriter
+			require.NoError(t, src.Scan(ctx, &w, []roachpb.Span{other}, endTime,
+				jobspb.ResolvedSpan_BACKFILL))
+			require.Len(t, w.events, 1)
+			require.Equal(t, kvevent.TypeResolved, w.events[0].Type())
+		})
+
+		t.Run("collects the files of the spans", func(t *testing.T) {
+			store, err := src.makeExternalStorageFromURI(ctx, src.uri, src.user)
+			require.NoError(t, err)
+			defer store.Close()
+			other := roachpb.Span{Key: fooSpan.EndKey, EndKey: fooSpan.EndKey.PrefixEnd()}
+
+			mem := mm.MakeBoundAccount()
+			defer mem.Close(ctx)
+			files, err := collectBackupFiles(ctx, &mem, &manifest, store, []roachpb.Span{other})
+			require.NoError(t, err)
+			require.Empty(t, files)
+			require.Zero(t, mem.Used())
+
+			files, err = collectBackupFiles(ctx, &mem, &manifest, store, []roachpb.Span{other, fooSpan})
+			require.NoError(t, err)
+			require.NotEmpty(t, files)
+			for _, f := range files {
+				require.True(t, f.Span.Overlaps(fooSpan))
+			}
+			require.Positive(t, mem.Used())
+		})
+
+		t.Run("timestamp other than the backup end time", func(t *testing.T) {
+			var w recordingKVEventWriter
+			err := src.Scan(ctx, &w, []roachpb.Span{fooSpan}, endTime.Next(),
+				jobspb.ResolvedSpan_BACKFILL)
+			require.ErrorContains(t, err, "the backup ends at")
+			require.Empty(t, w.events)
+		})
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("enterprise"))
+}
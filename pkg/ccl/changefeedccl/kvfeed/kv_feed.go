@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
@@ -68,6 +69,11 @@ type Config struct {
 	// be produced. For initial scans, this is the scan time.
 	InitialHighWater hlc.Timestamp
 
+	// InitialScanSource, if set, produces the initial scan instead of the
+	// live cluster. Scans triggered by schema changes always read from the
+	// cluster.
+	InitialScanSource InitialScanSource
+
 	// InitialSpanTimePairs contains pairs of spans and their initial resolved
 	// timestamps. The timestamps could be lower than InitialHighWater if the
 	// initial scan has not yet been completed.
@@ -88,6 +94,17 @@ type Config struct {
 	// during catchup scans.
 	WithBulkDelivery bool
 
+	// RowFilter, if set, is propagated via the RangefeedRequest to the
+	// rangefeed server, which uses it to drop rows that do not match the
+	// changefeed's predicate and to project away unused columns before
+	// sending events.
+	RowFilter *kvpb.RangeFeedRowFilter
+
+	// CatchUpMode is propagated via the RangefeedRequest to the rangefeed
+	// server, and controls whether catch-up scans emit every version of each
+	// key or only the latest one.
+	CatchUpMode kvpb.RangeFeedRequest_CatchUpMode
+
 	// WithFrontierQuantize specifies the resolved timestamp quantization
 	// granularity. If non-zero, resolved timestamps from rangefeed checkpoint
 	// events will be rounded down to the nearest multiple of the quantization
@@ -160,6 +177,11 @@ func Run(ctx context.Context, cfg Config) (retErr error) {
 		cfg.SchemaFeed,
 		sc, pff, bf, cfg.Targets, cfg.ScopedTimers, cfg.Knobs)
 	f.onBackfillCallback = cfg.MonitoringCfg.OnBackfillCallback
+	f.rowFilter = cfg.RowFilter
+	f.catchUpMode = cfg.CatchUpMode
+	if cfg.InitialScanSource != nil {
+		f.initialScanner = initialScanSourceScanner{src: cfg.InitialScanSource}
+	}
 
 	g.GoCtx(cfg.SchemaFeed.Run)
 	g.GoCtx(f.run)
@@ -232,6 +254,8 @@ type kvFeed struct {
 
 	onBackfillCallback func() func()
 	rangeObserver      kvcoord.RangeObserver
+	rowFilter          *kvpb.RangeFeedRowFilter
+	catchUpMode        kvpb.RangeFeedRequest_CatchUpMode
 	schemaChangeEvents changefeedbase.SchemaChangeEventClass
 	schemaChangePolicy changefeedbase.SchemaChangePolicy
 
@@ -244,6 +268,9 @@ type kvFeed struct {
 	scanner       kvScanner
 	physicalFeed  physicalFeedFactory
 	knobs         TestingKnobs
+
+	// initialScanner, if set, is used instead of scanner for the initial scan.
+	initialScanner kvScanner
 }
 
 // TODO(yevgeniy): This method is a kitchen sink. Refactor.
@@ -530,7 +557,11 @@ func (f *kvFeed) scanIfShould(
 	if initialScanOnly {
 		boundaryType = jobspb.ResolvedSpan_EXIT
 	}
-	if err := f.scanner.Scan(ctx, f.writer, scanConfig{
+	scanner := f.scanner
+	if isInitialScan && f.initialScanner != nil {
+		scanner = f.initialScanner
+	}
+	if err := scanner.Scan(ctx, f.writer, scanConfig{
 		Spans:     spansToBackfill.Slice(),
 		Timestamp: scanTime,
 		WithDiff:  !isInitialScan && f.withDiff,
@@ -584,6 +615,8 @@ func (f *kvFeed) runUntilTableEvent(ctx context.Context, resumeFrontier span.Fro
 		Knobs:                f.knobs,
 		Timers:               f.timers,
 		RangeObserver:        f.rangeObserver,
+		RowFilter:            f.rowFilter,
+		CatchUpMode:          f.catchUpMode,
 	}
 
 	// The following two synchronous calls works as follows:
@@ -37,6 +37,8 @@ type rangeFeedConfig struct {
 	WithBulkDelivery     bool
 	ConsumerID           int64
 	RangeObserver        kvcoord.RangeObserver
+	RowFilter            *kvpb.RangeFeedRowFilter
+	CatchUpMode          kvpb.RangeFeedRequest_CatchUpMode
 	Knobs                TestingKnobs
 	Timers               *timers.ScopedTimers
 }
@@ -111,6 +113,12 @@ func (p rangefeedFactory) Run(ctx context.Context, sink kvevent.Writer, cfg rang
 	if cfg.RangeObserver != nil {
 		rfOpts = append(rfOpts, kvcoord.WithRangeObserver(cfg.RangeObserver))
 	}
+	if cfg.RowFilter != nil {
+		rfOpts = append(rfOpts, kvcoord.WithRowFilter(cfg.RowFilter))
+	}
+	if cfg.CatchUpMode != kvpb.RangeFeedRequest_ALL_VERSIONS {
+		rfOpts = append(rfOpts, kvcoord.WithCatchUpMode(cfg.CatchUpMode))
+	}
 	if cfg.ConsumerID != 0 {
 		rfOpts = append(rfOpts, kvcoord.WithConsumerID(cfg.ConsumerID))
 	}
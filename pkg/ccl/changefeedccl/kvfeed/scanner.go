@@ -49,6 +49,35 @@ type kvScanner interface {
 	Scan(ctx context.Context, sink kvevent.Writer, cfg scanConfig) error
 }
 
+// InitialScanSource produces the initial scan of a changefeed from somewhere
+// other than the live cluster, such as a backup.
+type InitialScanSource interface {
+	// Scan writes every KV in the spans as of the timestamp to the sink as
+	// backfill events, followed by a resolved event with the boundary type for
+	// each span it has finished.
+	Scan(
+		ctx context.Context,
+		sink kvevent.Writer,
+		spans []roachpb.Span,
+		ts hlc.Timestamp,
+		boundary jobspb.ResolvedSpan_BoundaryType,
+	) error
+}
+
+// initialScanSourceScanner adapts an InitialScanSource to a kvScanner.
+type initialScanSourceScanner struct {
+	src InitialScanSource
+}
+
+var _ kvScanner = initialScanSourceScanner{}
+
+// Scan implements the kvScanner interface.
+func (s initialScanSourceScanner) Scan(
+	ctx context.Context, sink kvevent.Writer, cfg scanConfig,
+) error {
+	return s.src.Scan(ctx, sink, cfg.Spans, cfg.Timestamp, cfg.Boundary)
+}
+
 type scanRequestScanner struct {
 	settings                *cluster.Settings
 	db                      *kv.DB
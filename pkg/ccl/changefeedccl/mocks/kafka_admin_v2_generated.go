@@ -35,6 +35,21 @@ func (m *MockKafkaAdminClientV2) EXPECT() *MockKafkaAdminClientV2MockRecorder {
 	return m.recorder
 }
 
+// CreateTopic mocks base method.
+func (m *MockKafkaAdminClientV2) CreateTopic(arg0 context.Context, arg1 int32, arg2 int16, arg3 map[string]*string, arg4 string) (kadm.CreateTopicResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTopic", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(kadm.CreateTopicResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTopic indicates an expected call of CreateTopic.
+func (mr *MockKafkaAdminClientV2MockRecorder) CreateTopic(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTopic", reflect.TypeOf((*MockKafkaAdminClientV2)(nil).CreateTopic), arg0, arg1, arg2, arg3, arg4)
+}
+
 // ListTopics mocks base method.
 func (m *MockKafkaAdminClientV2) ListTopics(arg0 context.Context, arg1 ...string) (kadm.TopicDetails, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,185 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdceval"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// Names of the columns the routing expressions are projected as.
+const (
+	routedTopicColumn = `topic_expr`
+	routedKeyColumn   = `key_expr`
+)
+
+// makeRoutingSelectClause returns a select clause that projects the topic_expr
+// and key_expr options over the rows of the table the CDC query selects from.
+// It returns nil if neither option is set.
+func makeRoutingSelectClause(
+	sc *tree.SelectClause, opts changefeedbase.StatementOptions,
+) (*tree.SelectClause, error) {
+	var exprs tree.SelectExprs
+	for _, e := range []struct{ opt, col, expr string }{
+		{opt: changefeedbase.OptTopicExpr, col: routedTopicColumn, expr: opts.GetTopicExpr()},
+		{opt: changefeedbase.OptKeyExpr, col: routedKeyColumn, expr: opts.GetKeyExpr()},
+	} {
+		if e.expr == "" {
+			continue
+		}
+		expr, err := parser.ParseExpr(e.expr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing %s", e.opt)
+		}
+		exprs = append(exprs, tree.SelectExpr{Expr: expr, As: tree.UnrestrictedName(e.col)})
+	}
+	if len(exprs) == 0 {
+		return nil, nil
+	}
+	return &tree.SelectClause{Exprs: exprs, From: sc.From}, nil
+}
+
+// newRowRouter returns a router for the rows of the aggregator's CDC query, or
+// nil if the changefeed does not use topic_expr or key_expr.
+func newRowRouter(
+	ctx context.Context,
+	cfg *sql.ExecutorConfig,
+	spec execinfrapb.ChangeAggregatorSpec,
+	opts changefeedbase.StatementOptions,
+	topics *routedTopics,
+) (*rowRouter, error) {
+	sc, err := cdceval.ParseChangefeedExpression(spec.Select.Expr)
+	if err != nil {
+		return nil, err
+	}
+	routingSC, err := makeRoutingSelectClause(sc, opts)
+	if err != nil || routingSC == nil {
+		return nil, err
+	}
+	sd := sql.NewInternalSessionData(ctx, cfg.Settings, "changefeed-router")
+	if spec.Feed.SessionData != nil {
+		sd.SessionData = *spec.Feed.SessionData
+	}
+	return &rowRouter{
+		evaluator: cdceval.NewEvaluator(routingSC, cfg, spec.User(), sd,
+			spec.Feed.StatementTime, opts.GetFilters().WithDiff),
+		topics: topics,
+	}, nil
+}
+
+// rowRoute is where a row is routed to. An empty topic means the row goes
+// to its table's topic and a nil key means the key is encoded as usual.
+type rowRoute struct {
+	topic string
+	key   []byte
+}
+
+// rowRouter evaluates the topic_expr and key_expr options for the rows of a
+// CDC query.
+type rowRouter struct {
+	evaluator *cdceval.Evaluator
+	topics    *routedTopics
+	buf       bytes.Buffer
+}
+
+// route evaluates the routing expressions against the row as it was read from
+// the table, before the CDC query's projection.
+func (r *rowRouter) route(
+	ctx context.Context, updatedRow cdcevent.Row, prevRow cdcevent.Row,
+) (rowRoute, error) {
+	routing, err := r.evaluator.Eval(ctx, updatedRow, prevRow)
+	if err != nil {
+		return rowRoute{}, err
+	}
+	var route rowRoute
+	if err := routing.ForEachColumn().Datum(func(d tree.Datum, col cdcevent.ResultColumn) error {
+		switch col.Name {
+		case routedTopicColumn:
+			if d == tree.DNull {
+				return nil
+			}
+			s, ok := tree.AsDString(d)
+			if !ok {
+				return changefeedbase.WithTerminalError(errors.Newf(
+					"%s must evaluate to a string, got %s", changefeedbase.OptTopicExpr, d.ResolvedType()))
+			}
+			if s == "" {
+				return changefeedbase.WithTerminalError(errors.Newf(
+					"%s evaluated to an empty topic name", changefeedbase.OptTopicExpr))
+			}
+			if err := r.topics.add(string(s)); err != nil {
+				return err
+			}
+			route.topic = string(s)
+		case routedKeyColumn:
+			// The key is encoded like a single key_column would be.
+			j, err := tree.AsJSON(d, sessiondatapb.DataConversionConfig{}, time.UTC)
+			if err != nil {
+				return err
+			}
+			b := json.NewArrayBuilder(1)
+			b.Add(j)
+			r.buf.Reset()
+			b.Build().Format(&r.buf)
+			route.key = r.buf.Bytes()
+		}
+		return nil
+	}); err != nil {
+		return rowRoute{}, err
+	}
+	return route, nil
+}
+
+// Close releases the resources of the router.
+func (r *rowRouter) Close() {
+	r.evaluator.Close()
+}
+
+// routedTopics tracks the topics a changefeed processor has routed rows to,
+// and enforces changefeed.topic_expr.max_topics. It is shared by all of the
+// processor's event consumers.
+type routedTopics struct {
+	sv *settings.Values
+	mu struct {
+		syncutil.Mutex
+		names map[string]struct{}
+	}
+}
+
+func makeRoutedTopics(sv *settings.Values) *routedTopics {
+	t := &routedTopics{sv: sv}
+	t.mu.names = make(map[string]struct{})
+	return t
+}
+
+func (t *routedTopics) add(name string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.mu.names[name]; ok {
+		return nil
+	}
+	if limit := changefeedbase.TopicExprMaxTopics.Get(t.sv); int64(len(t.mu.names)) >= limit {
+		return changefeedbase.WithTerminalError(errors.WithHintf(errors.Newf(
+			"%s routed rows to more than %d topics", changefeedbase.OptTopicExpr, limit),
+			"raise the %s cluster setting", changefeedbase.TopicExprMaxTopics.Name()))
+	}
+	t.mu.names[name] = struct{}{}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedTopicAndKeyExpr(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `SET CLUSTER SETTING changefeed.new_kafka_sink.enabled = true`)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'dog', 'cat'), (1, 'cow', NULL)`)
+
+		t.Run("topic and key", func(t *testing.T) {
+			foo := feed(t, f, `CREATE CHANGEFEED WITH topic_expr='c', key_expr='b', unordered AS SELECT a FROM foo`,
+				optOutOfMetamorphicEnrichedEnvelope{reason: "custom key not supported in test framework"})
+			defer closeFeed(t, foo)
+			// A NULL topic routes the row to the table's topic.
+			assertPayloads(t, foo, []string{
+				`cat: ["dog"]->{"a": 0}`,
+				`foo: ["cow"]->{"a": 1}`,
+			})
+		})
+
+		t.Run("expressions use columns outside the projection", func(t *testing.T) {
+			foo := feed(t, f, `CREATE CHANGEFEED WITH key_expr='lower(b) || c', unordered AS SELECT a FROM foo WHERE a = 0`,
+				optOutOfMetamorphicEnrichedEnvelope{reason: "custom key not supported in test framework"})
+			defer closeFeed(t, foo)
+			assertPayloads(t, foo, []string{
+				`foo: ["dogcat"]->{"a": 0}`,
+			})
+		})
+
+		t.Run("requires a cdc query", func(t *testing.T) {
+			expectErrCreatingFeed(t, f, `CREATE CHANGEFEED FOR foo WITH topic_expr='c', unordered`,
+				`topic_expr requires a CDC query`)
+		})
+
+		t.Run("invalid expression", func(t *testing.T) {
+			expectErrCreatingFeed(t, f,
+				`CREATE CHANGEFEED WITH topic_expr='no_such_column', unordered AS SELECT a FROM foo`,
+				`invalid topic_expr or key_expr`)
+		})
+
+		t.Run("v1 kafka sink", func(t *testing.T) {
+			sqlDB.Exec(t, `SET CLUSTER SETTING changefeed.new_kafka_sink.enabled = false`)
+			defer sqlDB.Exec(t, `SET CLUSTER SETTING changefeed.new_kafka_sink.enabled = true`)
+			expectErrCreatingFeed(t, f,
+				`CREATE CHANGEFEED WITH topic_expr='c', unordered AS SELECT a FROM foo`,
+				`topic_expr is not supported for the v1 kafka sink`)
+		})
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestChangefeedTopicExprMaxTopics(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `SET CLUSTER SETTING changefeed.new_kafka_sink.enabled = true`)
+		sqlDB.Exec(t, `SET CLUSTER SETTING changefeed.topic_expr.max_topics = 2`)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'one'), (1, 'two'), (2, 'three')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED WITH topic_expr='b', unordered AS SELECT a FROM foo`)
+		defer closeFeed(t, foo)
+		requireTerminalErrorSoon(context.Background(), t, foo,
+			regexp.MustCompile(`topic_expr routed rows to more than 2 topics`))
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"), withAllowChangefeedErr("expects error"))
+}
+
+func TestRoutedTopics(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	st := cluster.MakeTestingClusterSettings()
+	changefeedbase.TopicExprMaxTopics.Override(context.Background(), &st.SV, 2)
+	topics := makeRoutedTopics(&st.SV)
+
+	require.NoError(t, topics.add("a"))
+	require.NoError(t, topics.add("b"))
+	// Topics that were already routed to do not count against the limit.
+	require.NoError(t, topics.add("a"))
+	require.ErrorContains(t, topics.add("c"), "routed rows to more than 2 topics")
+}
@@ -58,6 +58,9 @@ const (
 	sinkTypeCloudstorage
 	sinkTypeSQL
 	sinkTypePulsar
+	sinkTypeNATS
+	sinkTypeAMQP
+	sinkTypePostgres
 )
 
 func (st sinkType) String() string {
@@ -78,6 +81,12 @@ func (st sinkType) String() string {
 		return `sql`
 	case sinkTypePulsar:
 		return `pulsar`
+	case sinkTypeNATS:
+		return `nats`
+	case sinkTypeAMQP:
+		return `amqp`
+	case sinkTypePostgres:
+		return `postgres`
 	default:
 		return `unknown`
 	}
@@ -303,6 +312,18 @@ func getSink(
 				opts.IsSet(changefeedbase.OptUnordered), numSinkIOWorkers(serverCfg),
 				newCPUPacerFactory(ctx, serverCfg), timeutil.DefaultTimeSource{},
 				metricsBuilder, serverCfg.Settings, testingKnobs)
+		case isNATSSink(u):
+			return validateOptionsAndMakeSink(changefeedbase.NATSValidOptions, func() (Sink, error) {
+				return makeNATSSink(ctx, &changefeedbase.SinkURL{URL: u}, encodingOpts, opts.GetNATSConfigJSON(),
+					targets, numSinkIOWorkers(serverCfg), newCPUPacerFactory(ctx, serverCfg),
+					timeutil.DefaultTimeSource{}, metricsBuilder, serverCfg.Settings, natsSinkKnobs{})
+			})
+		case isAMQPSink(u):
+			return validateOptionsAndMakeSink(changefeedbase.AMQPValidOptions, func() (Sink, error) {
+				return makeAMQPSink(ctx, &changefeedbase.SinkURL{URL: u}, encodingOpts, opts.GetAMQPConfigJSON(),
+					targets, numSinkIOWorkers(serverCfg), newCPUPacerFactory(ctx, serverCfg),
+					timeutil.DefaultTimeSource{}, metricsBuilder, serverCfg.Settings, amqpSinkKnobs{})
+			})
 		case isCloudStorageSink(u):
 			return validateOptionsAndMakeSink(changefeedbase.CloudStorageValidOptions, func() (Sink, error) {
 				var testingKnobs *TestingKnobs
@@ -320,6 +341,15 @@ func getSink(
 					timestampOracle, serverCfg.ExternalStorageFromURI, user, metricsBuilder, testingKnobs,
 				)
 			})
+		case isPostgresSink(u):
+			return validateOptionsAndMakeSink(changefeedbase.PostgresValidOptions, func() (Sink, error) {
+				schemaChangeOpts, err := opts.GetSchemaChangeHandlingOptions()
+				if err != nil {
+					return nil, err
+				}
+				return makePostgresSink(&changefeedbase.SinkURL{URL: u}, encodingOpts, schemaChangeOpts,
+					targets, metricsBuilder)
+			})
 		case u.Scheme == changefeedbase.SinkSchemeExperimentalSQL:
 			return validateOptionsAndMakeSink(changefeedbase.SQLValidOptions, func() (Sink, error) {
 				return makeSQLSink(&changefeedbase.SinkURL{URL: u}, sqlSinkTableName, targets, metricsBuilder)
@@ -0,0 +1,387 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// AMQPConfirmation is a handle on the broker's confirmation of a single
+// publish, as returned by amqp091.Channel.PublishWithDeferredConfirmWithContext.
+type AMQPConfirmation interface {
+	// WaitContext implements the amqp091.DeferredConfirmation interface.
+	WaitContext(ctx context.Context) (bool, error)
+}
+
+// AMQPChannel extracts the operations the AMQP sink performs on an AMQP
+// connection and its channel in publisher confirm mode.
+type AMQPChannel interface {
+	// Publish publishes a message and returns a handle on its confirmation.
+	Publish(ctx context.Context, exchange, key string, msg amqp.Publishing) (AMQPConfirmation, error)
+	// CheckExchange returns an error if the named exchange does not exist.
+	CheckExchange(name string) error
+	// Close closes the channel and the underlying connection.
+	Close() error
+}
+
+type amqpSinkKnobs struct {
+	// OverrideChannel, if set, is used instead of connecting to a broker.
+	OverrideChannel func() AMQPChannel
+}
+
+func isAMQPSink(u *url.URL) bool {
+	switch u.Scheme {
+	case changefeedbase.SinkSchemeAMQP, changefeedbase.SinkSchemeAMQPS:
+		return true
+	default:
+		return false
+	}
+}
+
+// amqpSinkJSONConfig holds the AMQP-specific fields of amqp_sink_config, in
+// addition to the common Flush and Retry fields.
+type amqpSinkJSONConfig struct {
+	// RoutingKeys maps a topic name, as generated by the TopicNamer, to the
+	// routing key that messages for that topic are published with. Topics
+	// that are not in the map use the topic name as the routing key.
+	RoutingKeys map[string]string `json:",omitempty"`
+}
+
+// amqpChannel is the AMQPChannel implementation backed by a real broker
+// connection.
+type amqpChannel struct {
+	conn *amqp.Connection
+	mu   struct {
+		syncutil.Mutex
+		ch *amqp.Channel
+	}
+}
+
+var _ AMQPChannel = (*amqpChannel)(nil)
+
+func dialAMQPChannel(u *url.URL, cfg amqp.Config) (*amqpChannel, error) {
+	conn, err := amqp.DialConfig(u.String(), cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "connecting to amqp broker")
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		_ = conn.Close()
+		return nil, errors.Wrap(err, "opening amqp channel")
+	}
+	if err := ch.Confirm(false /* noWait */); err != nil {
+		_ = conn.Close()
+		return nil, errors.Wrap(err, "enabling publisher confirms")
+	}
+	c := &amqpChannel{conn: conn}
+	c.mu.ch = ch
+	return c, nil
+}
+
+// Publish implements the AMQPChannel interface.
+func (c *amqpChannel) Publish(
+	ctx context.Context, exchange, key string, msg amqp.Publishing,
+) (AMQPConfirmation, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// A channel is closed by the broker after a channel-level error, so reopen
+	// it to let the batching sink's retries make progress.
+	if c.mu.ch.IsClosed() {
+		ch, err := c.conn.Channel()
+		if err != nil {
+			return nil, errors.Wrap(err, "reopening amqp channel")
+		}
+		if err := ch.Confirm(false /* noWait */); err != nil {
+			return nil, errors.Wrap(err, "enabling publisher confirms")
+		}
+		c.mu.ch = ch
+	}
+	confirm, err := c.mu.ch.PublishWithDeferredConfirmWithContext(
+		ctx, exchange, key, false /* mandatory */, false /* immediate */, msg)
+	if err != nil {
+		return nil, err
+	}
+	return confirm, nil
+}
+
+// CheckExchange implements the AMQPChannel interface.
+func (c *amqpChannel) CheckExchange(name string) error {
+	// A failed passive declaration closes the channel it was issued on, so use
+	// a throwaway channel rather than the one used for publishing.
+	ch, err := c.conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = ch.Close() }()
+	return ch.ExchangeDeclarePassive(name, "" /* kind */, false, false, false, false, nil)
+}
+
+// Close implements the AMQPChannel interface.
+func (c *amqpChannel) Close() error {
+	return c.conn.Close()
+}
+
+type amqpSinkClient struct {
+	ch          AMQPChannel
+	format      changefeedbase.FormatType
+	batchCfg    sinkBatchConfig
+	exchange    string
+	routingKeys map[string]string
+}
+
+var _ SinkClient = (*amqpSinkClient)(nil)
+var _ SinkPayload = ([]amqpMessage)(nil)
+
+// amqpMessage is a publishing along with the routing key it is sent with.
+type amqpMessage struct {
+	key string
+	msg amqp.Publishing
+}
+
+func makeAMQPSinkClient(
+	u *changefeedbase.SinkURL,
+	encodingOpts changefeedbase.EncodingOptions,
+	batchCfg sinkBatchConfig,
+	routingKeys map[string]string,
+	m metricsRecorder,
+	knobs amqpSinkKnobs,
+) (*amqpSinkClient, error) {
+	if err := validateBrokerSinkEncoding(encodingOpts); err != nil {
+		return nil, err
+	}
+
+	sc := &amqpSinkClient{
+		format:      encodingOpts.Format,
+		batchCfg:    batchCfg,
+		exchange:    u.ConsumeParam(changefeedbase.SinkParamAMQPExchange),
+		routingKeys: routingKeys,
+	}
+
+	tlsCfg, err := consumeTLSConfig(u, u.Scheme == changefeedbase.SinkSchemeAMQPS)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil && u.Scheme != changefeedbase.SinkSchemeAMQPS {
+		return nil, errors.Errorf(`%s=true requires the %s scheme`,
+			changefeedbase.SinkParamTLSEnabled, changefeedbase.SinkSchemeAMQPS)
+	}
+
+	if unknownParams := u.RemainingQueryParams(); len(unknownParams) > 0 {
+		return nil, errors.Errorf(
+			`unknown amqp sink query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+
+	if knobs.OverrideChannel != nil {
+		sc.ch = knobs.OverrideChannel()
+		return sc, nil
+	}
+
+	dialer := m.netMetrics().WrapDialer(&net.Dialer{Timeout: 30 * time.Second}, "amqp")
+	cfg := amqp.Config{
+		TLSClientConfig: tlsCfg,
+		Dial:            dialer.Dial,
+		Properties:      amqp.Table{"product": "cockroachdb-changefeed"},
+	}
+	if tlsCfg != nil && tlsCfg.ServerName == "" {
+		tlsCfg.ServerName = u.Hostname()
+	}
+	// The sink-specific parameters have been consumed above. The user info
+	// and path (the vhost) are interpreted by the client.
+	brokerURL := &url.URL{Scheme: u.Scheme, User: u.User, Host: u.Host, Path: u.Path}
+	sc.ch, err = dialAMQPChannel(brokerURL, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+// routingKey returns the routing key that messages for the given topic are
+// published with.
+func (sc *amqpSinkClient) routingKey(topic string) string {
+	if k, ok := sc.routingKeys[topic]; ok {
+		return k
+	}
+	return topic
+}
+
+// CheckConnection implements the SinkClient interface.
+func (sc *amqpSinkClient) CheckConnection(ctx context.Context) error {
+	if sc.exchange == "" {
+		// The default exchange always exists.
+		return nil
+	}
+	return errors.Wrapf(sc.ch.CheckExchange(sc.exchange), "checking amqp exchange %s", sc.exchange)
+}
+
+// Flush implements the SinkClient interface. It publishes every message in
+// the batch and then waits for the broker to confirm each of them.
+func (sc *amqpSinkClient) Flush(ctx context.Context, payload SinkPayload) error {
+	msgs := payload.([]amqpMessage)
+	confirms := make([]AMQPConfirmation, 0, len(msgs))
+	for _, m := range msgs {
+		c, err := sc.ch.Publish(ctx, sc.exchange, m.key, m.msg)
+		if err != nil {
+			return errors.Wrapf(err, "publishing with routing key %s", m.key)
+		}
+		confirms = append(confirms, c)
+	}
+	for i, c := range confirms {
+		acked, err := c.WaitContext(ctx)
+		if err != nil {
+			return err
+		}
+		if !acked {
+			return errors.Newf("amqp broker rejected message with routing key %s", msgs[i].key)
+		}
+	}
+	return nil
+}
+
+// FlushResolvedPayload implements the SinkClient interface.
+func (sc *amqpSinkClient) FlushResolvedPayload(
+	ctx context.Context,
+	body []byte,
+	forEachTopic func(func(topic string) error) error,
+	retryOpts retry.Options,
+) error {
+	return forEachTopic(func(topic string) error {
+		pl := []amqpMessage{{key: sc.routingKey(topic), msg: sc.publishing(nil, body)}}
+		return retry.WithMaxAttempts(ctx, retryOpts, retryOpts.MaxRetries+1, func() error {
+			return sc.Flush(ctx, pl)
+		})
+	})
+}
+
+func (sc *amqpSinkClient) publishing(key, value []byte) amqp.Publishing {
+	msg := amqp.Publishing{
+		DeliveryMode: amqp.Persistent,
+		Body:         value,
+	}
+	switch sc.format {
+	case changefeedbase.OptFormatJSON:
+		msg.ContentType = applicationTypeJSON
+	case changefeedbase.OptFormatCSV:
+		msg.ContentType = applicationTypeCSV
+	}
+	if len(key) > 0 {
+		msg.Headers = amqp.Table{changefeedKeyHeader: string(key)}
+	}
+	return msg
+}
+
+// Close implements the SinkClient interface.
+func (sc *amqpSinkClient) Close() error {
+	return sc.ch.Close()
+}
+
+// MakeBatchBuffer implements the SinkClient interface.
+func (sc *amqpSinkClient) MakeBatchBuffer(topic string) BatchBuffer {
+	return &amqpBuffer{
+		sc:       sc,
+		key:      sc.routingKey(topic),
+		messages: make([]amqpMessage, 0, sc.batchCfg.Messages),
+	}
+}
+
+type amqpBuffer struct {
+	sc       *amqpSinkClient
+	key      string
+	messages []amqpMessage
+	numBytes int
+}
+
+var _ BatchBuffer = (*amqpBuffer)(nil)
+
+// Append implements the BatchBuffer interface.
+func (ab *amqpBuffer) Append(ctx context.Context, key []byte, value []byte, _ attributes) {
+	ab.messages = append(ab.messages, amqpMessage{key: ab.key, msg: ab.sc.publishing(key, value)})
+	ab.numBytes += len(key) + len(value)
+}
+
+// ShouldFlush implements the BatchBuffer interface.
+func (ab *amqpBuffer) ShouldFlush() bool {
+	return shouldFlushBatch(ab.numBytes, len(ab.messages), ab.sc.batchCfg)
+}
+
+// Close implements the BatchBuffer interface.
+func (ab *amqpBuffer) Close() (SinkPayload, error) {
+	return ab.messages, nil
+}
+
+func makeAMQPSink(
+	ctx context.Context,
+	u *changefeedbase.SinkURL,
+	encodingOpts changefeedbase.EncodingOptions,
+	jsonConfig changefeedbase.SinkSpecificJSONConfig,
+	targets changefeedbase.Targets,
+	parallelism int,
+	pacerFactory func() *admission.Pacer,
+	source timeutil.TimeSource,
+	mb metricsRecorderBuilder,
+	settings *cluster.Settings,
+	knobs amqpSinkKnobs,
+) (Sink, error) {
+	m := mb(requiresResourceAccounting)
+
+	batchCfg, retryOpts, err := getSinkConfigFromJson(jsonConfig, sinkJSONConfig{
+		Flush: sinkBatchConfig{
+			Frequency: jsonDuration(10 * time.Millisecond),
+			Messages:  1000,
+			Bytes:     1 << 20,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var amqpCfg amqpSinkJSONConfig
+	if jsonConfig != `` {
+		if err := json.Unmarshal([]byte(jsonConfig), &amqpCfg); err != nil {
+			return nil, errors.Wrapf(err, "error unmarshalling json")
+		}
+	}
+
+	topicNamer, err := MakeTopicNamer(
+		targets,
+		WithPrefix(u.ConsumeParam(changefeedbase.SinkParamTopicPrefix)),
+		WithSingleName(u.ConsumeParam(changefeedbase.SinkParamTopicName)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	sinkClient, err := makeAMQPSinkClient(u, encodingOpts, batchCfg, amqpCfg.RoutingKeys, m, knobs)
+	if err != nil {
+		return nil, err
+	}
+
+	return makeBatchingSink(
+		ctx,
+		sinkTypeAMQP,
+		sinkClient,
+		time.Duration(batchCfg.Frequency),
+		retryOpts,
+		parallelism,
+		topicNamer,
+		pacerFactory,
+		source,
+		m,
+		settings,
+	), nil
+}
@@ -99,11 +99,17 @@ var supportedExternalConnectionTypes = map[string]connectionpb.ConnectionProvide
 	changefeedbase.DeprecatedSinkSchemeHTTPS:       connectionpb.ConnectionProvider_https,
 	changefeedbase.SinkSchemeCloudStorageNodelocal: connectionpb.ConnectionProvider_nodelocal,
 	changefeedbase.SinkSchemeCloudStorageS3:        connectionpb.ConnectionProvider_s3,
+	changefeedbase.SinkSchemeCloudStorageSFTP:      connectionpb.ConnectionProvider_sftp,
+	changefeedbase.SinkSchemeCloudStorageWebDAV:    connectionpb.ConnectionProvider_webdav,
+	changefeedbase.SinkSchemeCloudStorageWebDAVS:   connectionpb.ConnectionProvider_webdav,
 	changefeedbase.SinkSchemeKafka:                 connectionpb.ConnectionProvider_kafka,
 	changefeedbase.SinkSchemeWebhookHTTP:           connectionpb.ConnectionProvider_webhookhttp,
 	changefeedbase.SinkSchemeWebhookHTTPS:          connectionpb.ConnectionProvider_webhookhttps,
 	changefeedbase.SinkSchemeConfluentKafka:        connectionpb.ConnectionProvider_kafka,
 	changefeedbase.SinkSchemeAzureKafka:            connectionpb.ConnectionProvider_kafka,
+	changefeedbase.SinkSchemeNATS:                  connectionpb.ConnectionProvider_nats,
+	changefeedbase.SinkSchemeAMQP:                  connectionpb.ConnectionProvider_amqp,
+	changefeedbase.SinkSchemeAMQPS:                 connectionpb.ConnectionProvider_amqp,
 	// TODO (zinger): Not including SinkSchemeExperimentalSQL for now because A: it's undocumented
 	// and B, in tests it leaks a *gosql.DB and I can't figure out why.
 }
@@ -131,6 +137,7 @@ func init() {
 		changefeedbase.SinkParamConfluentAPISecret,
 		changefeedbase.SinkParamAzureAccessKey,
 		changefeedbase.SinkParamAzureAccessKeyCamel,
+		changefeedbase.SinkParamNATSAuthToken,
 	))
 }
 
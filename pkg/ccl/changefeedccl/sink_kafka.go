@@ -1242,6 +1242,11 @@ func makeKafkaSink(
 		return nil, errors.Newf("headers are not supported for the v1 kafka sink;"+
 			" use the v2 sink instead via the `%s` cluster setting", KafkaV2Enabled.Name())
 	}
+	if sinkOpts.CreateTopics {
+		return nil, errors.Newf("%s is not supported for the v1 kafka sink;"+
+			" use the v2 sink instead via the `%s` cluster setting",
+			changefeedbase.OptTopicExpr, KafkaV2Enabled.Name())
+	}
 
 	m := mb(requiresResourceAccounting)
 	config, err := buildKafkaConfig(ctx, u, jsonStr, m.getKafkaThrottlingMetrics(settings), m.netMetrics())
@@ -49,6 +49,15 @@ type kafkaSinkClientV2 struct {
 	topicsForConnectionCheck []string
 	constHeaders             []kgo.RecordHeader
 
+	// createTopics is set when rows are routed to topics by topic_expr, which
+	// are created on demand rather than relying on the brokers to
+	// auto-create them.
+	createTopics bool
+	createdMu    struct {
+		syncutil.Mutex
+		topics map[string]struct{}
+	}
+
 	// we need to fetch and keep track of this ourselves since kgo doesnt expose metadata to us
 	metadataMu struct {
 		syncutil.Mutex
@@ -71,6 +80,7 @@ func newKafkaSinkClientV2(
 	topicsForConnectionCheck []string,
 	constHeaders map[string][]byte,
 	partitionAlg string,
+	createTopics bool,
 ) (*kafkaSinkClientV2, error) {
 	bootstrapBrokers := strings.Split(bootstrapAddrsStr, `,`)
 
@@ -136,8 +146,10 @@ func newKafkaSinkClientV2(
 		recordResize:             recordResize,
 		topicsForConnectionCheck: topicsForConnectionCheck,
 		constHeaders:             constHeadersKgo,
+		createTopics:             createTopics,
 	}
 	c.metadataMu.allTopicPartitions = make(map[string][]int32)
+	c.createdMu.topics = make(map[string]struct{})
 
 	return c, nil
 }
@@ -151,6 +163,9 @@ func (k *kafkaSinkClientV2) Close() error {
 // Flush implements SinkClient. Does not retry -- retries will be handled either by kafka or ParallelIO.
 func (k *kafkaSinkClientV2) Flush(ctx context.Context, payload SinkPayload) (retErr error) {
 	msgs := payload.([]*kgo.Record)
+	if err := k.maybeCreateTopics(ctx, msgs); err != nil {
+		return err
+	}
 
 	var flushMsgs func(msgs []*kgo.Record) error
 	flushMsgs = func(msgs []*kgo.Record) error {
@@ -195,6 +210,31 @@ func (k *kafkaSinkClientV2) Flush(ctx context.Context, payload SinkPayload) (ret
 	return flushMsgs(msgs)
 }
 
+// maybeCreateTopics creates the topics of the messages that have not been
+// produced to before, if the client creates topics on demand. The topics are
+// created with the brokers' default partition count and replication factor.
+func (k *kafkaSinkClientV2) maybeCreateTopics(ctx context.Context, msgs []*kgo.Record) error {
+	if !k.createTopics {
+		return nil
+	}
+	k.createdMu.Lock()
+	defer k.createdMu.Unlock()
+	for _, msg := range msgs {
+		if _, ok := k.createdMu.topics[msg.Topic]; ok {
+			continue
+		}
+		resp, err := k.adminClient.CreateTopic(ctx, -1 /* partitions */, -1 /* replicationFactor */, nil /* configs */, msg.Topic)
+		if err == nil {
+			err = resp.Err
+		}
+		if err != nil && !errors.Is(err, kerr.TopicAlreadyExists) {
+			return errors.Wrapf(err, "creating kafka topic %s", redact.SafeString(msg.Topic))
+		}
+		k.createdMu.topics[msg.Topic] = struct{}{}
+	}
+	return nil
+}
+
 // FlushResolvedPayload implements SinkClient.
 func (k *kafkaSinkClientV2) FlushResolvedPayload(
 	ctx context.Context,
@@ -304,9 +344,12 @@ type KafkaClientV2 interface {
 
 // KafkaAdminClientV2 is a small interface restricting the functionality in
 // *kadm.Client. It's used to list topics so we can iterate over all partitions
-// to flush resolved messages.
+// to flush resolved messages, and to create the topics rows are routed to.
 type KafkaAdminClientV2 interface {
 	ListTopics(ctx context.Context, topics ...string) (kadm.TopicDetails, error)
+	CreateTopic(
+		ctx context.Context, partitions int32, replicationFactor int16, configs map[string]*string, topic string,
+	) (kadm.CreateTopicResponse, error)
 }
 
 type kafkaSinkV2Knobs struct {
@@ -412,7 +455,7 @@ func makeKafkaSinkV2(
 	}
 
 	topicsForConnectionCheck := topicNamer.DisplayNamesSlice()
-	client, err := newKafkaSinkClientV2(ctx, clientOpts, batchCfg, u.Host, settings, knobs, mb, topicsForConnectionCheck, sinkOpts.Headers, sinkOpts.PartitionAlg)
+	client, err := newKafkaSinkClientV2(ctx, clientOpts, batchCfg, u.Host, settings, knobs, mb, topicsForConnectionCheck, sinkOpts.Headers, sinkOpts.PartitionAlg, sinkOpts.CreateTopics)
 	if err != nil {
 		return nil, err
 	}
@@ -110,6 +110,43 @@ func TestKafkaSinkClientV2_Basic(t *testing.T) {
 	require.NoError(t, fx.sink.Flush(fx.ctx, payload))
 }
 
+func TestKafkaSinkClientV2_CreateTopics(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	fx := newKafkaSinkV2Fx(t, withCreateTopics())
+	defer fx.close()
+
+	makePayload := func(topic string) SinkPayload {
+		buf := fx.sink.MakeBatchBuffer(topic)
+		buf.Append(context.Background(), []byte("k"), []byte("v"), attributes{})
+		payload, err := buf.Close()
+		require.NoError(t, err)
+		return payload
+	}
+
+	// Each topic is created before it is first produced to. A topic that
+	// already exists is not an error.
+	fx.ac.EXPECT().CreateTopic(fx.ctx, int32(-1), int16(-1), gomock.Nil(), "orders_eu").Times(1).
+		Return(kadm.CreateTopicResponse{Topic: "orders_eu"}, nil)
+	fx.ac.EXPECT().CreateTopic(fx.ctx, int32(-1), int16(-1), gomock.Nil(), "orders_us").Times(1).
+		Return(kadm.CreateTopicResponse{Topic: "orders_us", Err: kerr.TopicAlreadyExists}, nil)
+	fx.kc.EXPECT().ProduceSync(fx.ctx, gomock.Any()).Times(3).Return(nil)
+	for _, topic := range []string{"orders_eu", "orders_us", "orders_eu"} {
+		require.NoError(t, fx.sink.Flush(fx.ctx, makePayload(topic)))
+	}
+
+	// A topic that cannot be created fails the flush, and creating it is
+	// retried on the next one.
+	fx.ac.EXPECT().CreateTopic(fx.ctx, int32(-1), int16(-1), gomock.Nil(), "orders_ap").Times(1).
+		Return(kadm.CreateTopicResponse{Topic: "orders_ap", Err: kerr.TopicAuthorizationFailed}, nil)
+	require.ErrorIs(t, fx.sink.Flush(fx.ctx, makePayload("orders_ap")), kerr.TopicAuthorizationFailed)
+	fx.ac.EXPECT().CreateTopic(fx.ctx, int32(-1), int16(-1), gomock.Nil(), "orders_ap").Times(1).
+		Return(kadm.CreateTopicResponse{Topic: "orders_ap"}, nil)
+	fx.kc.EXPECT().ProduceSync(fx.ctx, gomock.Any()).Times(1).Return(nil)
+	require.NoError(t, fx.sink.Flush(fx.ctx, makePayload("orders_ap")))
+}
+
 func TestKafkaSinkClientV2_Resize(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -662,6 +699,7 @@ type kafkaSinkV2Fx struct {
 	additionalKOpts     []kgo.Opt
 	createClientErrorCb func(error)
 	uri                 string
+	createTopics        bool
 
 	sink *kafkaSinkClientV2
 	bs   *batchingSink
@@ -717,6 +755,12 @@ func withKOptsClient(kOpts []kgo.Opt) fxOpt {
 	}
 }
 
+func withCreateTopics() fxOpt {
+	return func(fx *kafkaSinkV2Fx) {
+		fx.createTopics = true
+	}
+}
+
 func withCreateClientErrorCb(cb func(error)) fxOpt {
 	return func(fx *kafkaSinkV2Fx) {
 		fx.createClientErrorCb = cb
@@ -761,7 +805,7 @@ func newKafkaSinkV2Fx(t *testing.T, opts ...fxOpt) *kafkaSinkV2Fx {
 	var err error
 	fx.sink, err = newKafkaSinkClientV2(ctx, fx.additionalKOpts,
 		fx.batchConfig, uri, settings, knobs, nilMetricsRecorderBuilder,
-		nil, nil, "" /* partitionAlg */)
+		nil, nil, "" /* partitionAlg */, fx.createTopics)
 	if err != nil && fx.createClientErrorCb != nil {
 		fx.createClientErrorCb(err)
 		return fx
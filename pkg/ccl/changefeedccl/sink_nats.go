@@ -0,0 +1,373 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// changefeedKeyHeader is the message header in which the message brokers
+// that support headers (NATS, AMQP) receive the encoded row key.
+const changefeedKeyHeader = `Changefeed-Key`
+
+// NATSJetStreamClient extracts the relevant methods from jetstream.JetStream.
+type NATSJetStreamClient interface {
+	// PublishMsgAsync implements the jetstream.JetStream interface.
+	PublishMsgAsync(msg *nats.Msg, opts ...jetstream.PublishOpt) (jetstream.PubAckFuture, error)
+	// StreamNameBySubject implements the jetstream.JetStream interface.
+	StreamNameBySubject(ctx context.Context, subject string) (string, error)
+}
+
+type natsSinkKnobs struct {
+	// OverrideClient, if set, is used instead of connecting to a NATS server.
+	OverrideClient func() NATSJetStreamClient
+}
+
+func isNATSSink(u *url.URL) bool {
+	return u.Scheme == changefeedbase.SinkSchemeNATS
+}
+
+// natsSinkJSONConfig holds the NATS-specific fields of nats_sink_config, in
+// addition to the common Flush and Retry fields.
+type natsSinkJSONConfig struct {
+	// Subjects maps a topic name, as generated by the TopicNamer, to the
+	// subject that messages for that topic are published to. Topics that are
+	// not in the map are published to a subject of the same name.
+	Subjects map[string]string `json:",omitempty"`
+}
+
+type natsSinkClient struct {
+	conn     *nats.Conn
+	js       NATSJetStreamClient
+	format   changefeedbase.FormatType
+	batchCfg sinkBatchConfig
+
+	// stream, if set, is the JetStream stream every subject must be bound to.
+	// It is passed along with every publish so that the server rejects
+	// messages that would otherwise land in a different stream.
+	stream   string
+	subjects map[string]string
+
+	topicsForConnectionCheck []string
+}
+
+var _ SinkClient = (*natsSinkClient)(nil)
+var _ SinkPayload = ([]*nats.Msg)(nil)
+
+func makeNATSSinkClient(
+	ctx context.Context,
+	u *changefeedbase.SinkURL,
+	encodingOpts changefeedbase.EncodingOptions,
+	batchCfg sinkBatchConfig,
+	subjects map[string]string,
+	topicsForConnectionCheck []string,
+	m metricsRecorder,
+	knobs natsSinkKnobs,
+) (*natsSinkClient, error) {
+	if err := validateBrokerSinkEncoding(encodingOpts); err != nil {
+		return nil, err
+	}
+
+	sc := &natsSinkClient{
+		format:                   encodingOpts.Format,
+		batchCfg:                 batchCfg,
+		stream:                   u.ConsumeParam(changefeedbase.SinkParamNATSStream),
+		subjects:                 subjects,
+		topicsForConnectionCheck: topicsForConnectionCheck,
+	}
+
+	opts := []nats.Option{
+		nats.Name(`cockroachdb-changefeed`),
+		nats.Timeout(10 * time.Second),
+		// The batching sink retries failed flushes itself, and buffering
+		// publishes inside the client while disconnected would only hide
+		// errors from it.
+		nats.ReconnectBufSize(-1),
+	}
+	tlsCfg, err := consumeTLSConfig(u, false /* impliedTLS */)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		opts = append(opts, nats.Secure(tlsCfg))
+	}
+	if token := u.ConsumeParam(changefeedbase.SinkParamNATSAuthToken); token != "" {
+		if u.User != nil {
+			return nil, errors.Errorf(`%s cannot be combined with a username in the sink URI`,
+				changefeedbase.SinkParamNATSAuthToken)
+		}
+		opts = append(opts, nats.Token(token))
+	}
+	if u.User != nil {
+		password, _ := u.User.Password()
+		opts = append(opts, nats.UserInfo(u.User.Username(), password))
+	}
+
+	if unknownParams := u.RemainingQueryParams(); len(unknownParams) > 0 {
+		return nil, errors.Errorf(
+			`unknown nats sink query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+
+	if knobs.OverrideClient != nil {
+		sc.js = knobs.OverrideClient()
+		return sc, nil
+	}
+
+	dialer := m.netMetrics().WrapDialer(&net.Dialer{Timeout: 10 * time.Second}, "nats")
+	opts = append(opts, nats.SetCustomDialer(dialer))
+
+	// The credentials and sink-specific parameters have been consumed above,
+	// so connect using only the scheme and server addresses.
+	serverURL := url.URL{Scheme: u.Scheme, Host: u.Host}
+	sc.conn, err = nats.Connect(serverURL.String(), opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "connecting to nats server")
+	}
+	sc.js, err = jetstream.New(sc.conn)
+	if err != nil {
+		sc.conn.Close()
+		return nil, errors.Wrap(err, "creating jetstream context")
+	}
+	return sc, nil
+}
+
+// subject returns the NATS subject that messages for the given topic are
+// published to.
+func (sc *natsSinkClient) subject(topic string) string {
+	if s, ok := sc.subjects[topic]; ok {
+		return s
+	}
+	return topic
+}
+
+func (sc *natsSinkClient) publishOpts() []jetstream.PublishOpt {
+	if sc.stream == "" {
+		return nil
+	}
+	return []jetstream.PublishOpt{jetstream.WithExpectStream(sc.stream)}
+}
+
+// CheckConnection implements the SinkClient interface. It verifies that every
+// subject the changefeed publishes to is captured by a JetStream stream, since
+// publishes to a subject without a stream are never acknowledged.
+func (sc *natsSinkClient) CheckConnection(ctx context.Context) error {
+	if sc.conn != nil && !sc.conn.IsConnected() {
+		return errors.Newf("nats connection is %s", sc.conn.Status())
+	}
+	for _, topic := range sc.topicsForConnectionCheck {
+		if strings.Contains(topic, familyPlaceholder) {
+			// The family-specific subjects aren't known until rows arrive.
+			continue
+		}
+		subject := sc.subject(topic)
+		stream, err := sc.js.StreamNameBySubject(ctx, subject)
+		if err != nil {
+			return errors.Wrapf(err, "looking up jetstream stream for subject %s", subject)
+		}
+		if sc.stream != "" && stream != sc.stream {
+			return errors.Newf("subject %s is bound to stream %s, not %s", subject, stream, sc.stream)
+		}
+	}
+	return nil
+}
+
+// Flush implements the SinkClient interface. It publishes every message in
+// the batch asynchronously and then waits for the server to acknowledge each
+// of them.
+func (sc *natsSinkClient) Flush(ctx context.Context, payload SinkPayload) error {
+	msgs := payload.([]*nats.Msg)
+	futures := make([]jetstream.PubAckFuture, 0, len(msgs))
+	for _, msg := range msgs {
+		f, err := sc.js.PublishMsgAsync(msg, sc.publishOpts()...)
+		if err != nil {
+			return errors.Wrapf(err, "publishing to subject %s", msg.Subject)
+		}
+		futures = append(futures, f)
+	}
+	for _, f := range futures {
+		select {
+		case <-f.Ok():
+		case err := <-f.Err():
+			return errors.Wrapf(err, "nats publish to subject %s was not acknowledged", f.Msg().Subject)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// FlushResolvedPayload implements the SinkClient interface.
+func (sc *natsSinkClient) FlushResolvedPayload(
+	ctx context.Context,
+	body []byte,
+	forEachTopic func(func(topic string) error) error,
+	retryOpts retry.Options,
+) error {
+	return forEachTopic(func(topic string) error {
+		msg := &nats.Msg{Subject: sc.subject(topic), Data: body}
+		return retry.WithMaxAttempts(ctx, retryOpts, retryOpts.MaxRetries+1, func() error {
+			return sc.Flush(ctx, []*nats.Msg{msg})
+		})
+	})
+}
+
+// Close implements the SinkClient interface.
+func (sc *natsSinkClient) Close() error {
+	if sc.conn != nil {
+		sc.conn.Close()
+	}
+	return nil
+}
+
+// MakeBatchBuffer implements the SinkClient interface.
+func (sc *natsSinkClient) MakeBatchBuffer(topic string) BatchBuffer {
+	return &natsBuffer{
+		sc:       sc,
+		subject:  sc.subject(topic),
+		messages: make([]*nats.Msg, 0, sc.batchCfg.Messages),
+	}
+}
+
+type natsBuffer struct {
+	sc       *natsSinkClient
+	subject  string
+	messages []*nats.Msg
+	numBytes int
+}
+
+var _ BatchBuffer = (*natsBuffer)(nil)
+
+// Append implements the BatchBuffer interface.
+func (nb *natsBuffer) Append(ctx context.Context, key []byte, value []byte, _ attributes) {
+	msg := &nats.Msg{Subject: nb.subject, Data: value}
+	if len(key) > 0 {
+		msg.Header = nats.Header{}
+		msg.Header.Set(changefeedKeyHeader, string(key))
+	}
+	nb.messages = append(nb.messages, msg)
+	nb.numBytes += len(key) + len(value)
+}
+
+// ShouldFlush implements the BatchBuffer interface.
+func (nb *natsBuffer) ShouldFlush() bool {
+	return shouldFlushBatch(nb.numBytes, len(nb.messages), nb.sc.batchCfg)
+}
+
+// Close implements the BatchBuffer interface.
+func (nb *natsBuffer) Close() (SinkPayload, error) {
+	return nb.messages, nil
+}
+
+// validateBrokerSinkEncoding checks that the encoding options are supported by
+// the sinks that publish one message per row to a message broker without a
+// schema registry.
+func validateBrokerSinkEncoding(encodingOpts changefeedbase.EncodingOptions) error {
+	switch encodingOpts.Format {
+	case changefeedbase.OptFormatJSON, changefeedbase.OptFormatCSV:
+	default:
+		return errors.Errorf(`this sink is incompatible with %s=%s`,
+			changefeedbase.OptFormat, encodingOpts.Format)
+	}
+
+	switch encodingOpts.Envelope {
+	case changefeedbase.OptEnvelopeWrapped, changefeedbase.OptEnvelopeBare:
+	default:
+		return errors.Errorf(`this sink is incompatible with %s=%s`,
+			changefeedbase.OptEnvelope, encodingOpts.Envelope)
+	}
+	return nil
+}
+
+// natsSubjectSanitizer replaces the characters that are not allowed in a NATS
+// subject token. Dots are kept since they separate the tokens of a subject,
+// which lets fully qualified table names map onto subject hierarchies.
+func natsSubjectSanitizer(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\r', '\n', '*', '>':
+			return '_'
+		default:
+			return r
+		}
+	}, s)
+}
+
+func makeNATSSink(
+	ctx context.Context,
+	u *changefeedbase.SinkURL,
+	encodingOpts changefeedbase.EncodingOptions,
+	jsonConfig changefeedbase.SinkSpecificJSONConfig,
+	targets changefeedbase.Targets,
+	parallelism int,
+	pacerFactory func() *admission.Pacer,
+	source timeutil.TimeSource,
+	mb metricsRecorderBuilder,
+	settings *cluster.Settings,
+	knobs natsSinkKnobs,
+) (Sink, error) {
+	m := mb(requiresResourceAccounting)
+
+	batchCfg, retryOpts, err := getSinkConfigFromJson(jsonConfig, sinkJSONConfig{
+		Flush: sinkBatchConfig{
+			Frequency: jsonDuration(10 * time.Millisecond),
+			Messages:  1000,
+			Bytes:     1 << 20,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var natsCfg natsSinkJSONConfig
+	if jsonConfig != `` {
+		if err := json.Unmarshal([]byte(jsonConfig), &natsCfg); err != nil {
+			return nil, errors.Wrapf(err, "error unmarshalling json")
+		}
+	}
+
+	topicNamer, err := MakeTopicNamer(
+		targets,
+		WithPrefix(u.ConsumeParam(changefeedbase.SinkParamTopicPrefix)),
+		WithSingleName(u.ConsumeParam(changefeedbase.SinkParamTopicName)),
+		WithSanitizeFn(natsSubjectSanitizer),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	sinkClient, err := makeNATSSinkClient(ctx, u, encodingOpts, batchCfg, natsCfg.Subjects,
+		topicNamer.DisplayNamesSlice(), m, knobs)
+	if err != nil {
+		return nil, err
+	}
+
+	return makeBatchingSink(
+		ctx,
+		sinkTypeNATS,
+		sinkClient,
+		time.Duration(batchCfg.Frequency),
+		retryOpts,
+		parallelism,
+		topicNamer,
+		pacerFactory,
+		source,
+		m,
+		settings,
+	), nil
+}
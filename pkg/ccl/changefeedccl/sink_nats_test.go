@@ -0,0 +1,132 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/errors"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNATSJetStream is a NATSJetStreamClient that records published messages
+// and acknowledges or fails them immediately.
+type fakeNATSJetStream struct {
+	published []*nats.Msg
+	// streams maps a subject to the stream that captures it.
+	streams map[string]string
+	// failPublish, if set, fails the acknowledgement of every publish.
+	failPublish error
+}
+
+var _ NATSJetStreamClient = (*fakeNATSJetStream)(nil)
+
+type fakePubAckFuture struct {
+	msg *nats.Msg
+	ok  chan *jetstream.PubAck
+	err chan error
+}
+
+func (f *fakePubAckFuture) Ok() <-chan *jetstream.PubAck { return f.ok }
+func (f *fakePubAckFuture) Err() <-chan error            { return f.err }
+func (f *fakePubAckFuture) Msg() *nats.Msg               { return f.msg }
+
+// PublishMsgAsync implements the NATSJetStreamClient interface.
+func (f *fakeNATSJetStream) PublishMsgAsync(
+	msg *nats.Msg, _ ...jetstream.PublishOpt,
+) (jetstream.PubAckFuture, error) {
+	f.published = append(f.published, msg)
+	fut := &fakePubAckFuture{msg: msg, ok: make(chan *jetstream.PubAck, 1), err: make(chan error, 1)}
+	if f.failPublish != nil {
+		fut.err <- f.failPublish
+	} else {
+		fut.ok <- &jetstream.PubAck{Stream: f.streams[msg.Subject]}
+	}
+	return fut, nil
+}
+
+// StreamNameBySubject implements the NATSJetStreamClient interface.
+func (f *fakeNATSJetStream) StreamNameBySubject(_ context.Context, subject string) (string, error) {
+	if s, ok := f.streams[subject]; ok {
+		return s, nil
+	}
+	return "", jetstream.ErrStreamNotFound
+}
+
+func makeTestNATSSinkClient(
+	t *testing.T, uri string, js *fakeNATSJetStream, subjects map[string]string, topics ...string,
+) (*natsSinkClient, error) {
+	u, err := url.Parse(uri)
+	require.NoError(t, err)
+	return makeNATSSinkClient(context.Background(), &changefeedbase.SinkURL{URL: u},
+		changefeedbase.EncodingOptions{
+			Format:   changefeedbase.OptFormatJSON,
+			Envelope: changefeedbase.OptEnvelopeWrapped,
+		},
+		sinkBatchConfig{}, subjects, topics, nilMetricsRecorderBuilder(false),
+		natsSinkKnobs{OverrideClient: func() NATSJetStreamClient { return js }})
+}
+
+func TestNATSSinkClient(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	js := &fakeNATSJetStream{streams: map[string]string{"cdc.foo": "CDC"}}
+	sc, err := makeTestNATSSinkClient(t, "nats://localhost:4222?stream=CDC", js,
+		map[string]string{"foo": "cdc.foo"}, "foo")
+	require.NoError(t, err)
+	require.NoError(t, sc.CheckConnection(ctx))
+
+	buf := sc.MakeBatchBuffer("foo")
+	buf.Append(ctx, []byte(`[1]`), []byte(`{"after": {"a": 1}}`), attributes{})
+	buf.Append(ctx, []byte(`[2]`), []byte(`{"after": {"a": 2}}`), attributes{})
+	payload, err := buf.Close()
+	require.NoError(t, err)
+	require.NoError(t, sc.Flush(ctx, payload))
+
+	require.Len(t, js.published, 2)
+	for i, msg := range js.published {
+		require.Equal(t, "cdc.foo", msg.Subject)
+		require.Equal(t, []string{`[1]`, `[2]`}[i], msg.Header.Get(changefeedKeyHeader))
+	}
+
+	js.failPublish = errors.New("no responders")
+	require.ErrorContains(t, sc.Flush(ctx, payload), "was not acknowledged")
+	require.ErrorContains(t, sc.FlushResolvedPayload(ctx, []byte(`{"resolved": "1"}`),
+		func(f func(topic string) error) error { return f("foo") }, retry.Options{}),
+		"no responders")
+}
+
+func TestNATSSinkClientCheckConnection(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	js := &fakeNATSJetStream{streams: map[string]string{"foo": "OTHER"}}
+
+	sc, err := makeTestNATSSinkClient(t, "nats://localhost:4222", js, nil, "foo", "bar")
+	require.NoError(t, err)
+	require.ErrorContains(t, sc.CheckConnection(ctx), "subject bar")
+
+	sc, err = makeTestNATSSinkClient(t, "nats://localhost:4222?stream=CDC", js, nil, "foo")
+	require.NoError(t, err)
+	require.ErrorContains(t, sc.CheckConnection(ctx), "bound to stream OTHER, not CDC")
+
+	_, err = makeTestNATSSinkClient(t, "nats://localhost:4222?bogus=1", js, nil)
+	require.ErrorContains(t, err, "unknown nats sink query parameters: bogus")
+
+	_, err = makeTestNATSSinkClient(t, "nats://u:p@localhost:4222?auth_token=x", js, nil)
+	require.ErrorContains(t, err, "cannot be combined with a username")
+}
@@ -0,0 +1,688 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	gosql "database/sql"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq"
+)
+
+const (
+	// postgresSinkDefaultBatchSize is the default maximum number of rows
+	// written by a single UPSERT or DELETE statement.
+	postgresSinkDefaultBatchSize = 1000
+	// postgresMaxPlaceholders is the maximum number of placeholders a single
+	// statement may use in the PostgreSQL wire protocol.
+	postgresMaxPlaceholders = 65535
+)
+
+// postgresConnParams are the query parameters of a postgres:// sink URI that
+// are interpreted by the driver rather than the sink.
+var postgresConnParams = []string{
+	`application_name`,
+	`connect_timeout`,
+	`options`,
+	`sslcert`,
+	`sslkey`,
+	`sslmode`,
+	`sslrootcert`,
+}
+
+func isPostgresSink(u *url.URL) bool {
+	switch u.Scheme {
+	case changefeedbase.SinkSchemePostgres, changefeedbase.SinkSchemePostgresQL:
+		return true
+	default:
+		return false
+	}
+}
+
+// postgresSink applies changefeed events to tables in a remote PostgreSQL or
+// CockroachDB database. Every topic is mapped to a table of the same name,
+// which must already exist with a primary key matching the one of the watched
+// table. Upserts are written with INSERT ... ON CONFLICT DO UPDATE and deletes
+// with DELETE, so replaying events after a changefeed restart is idempotent.
+//
+// Events are buffered until the next Flush, which the changefeed issues
+// before every resolved timestamp. Only the latest version of each row is
+// written. With transactional=true in the sink URI, everything buffered since
+// the previous flush is applied in a single transaction, so that readers of
+// the destination only ever observe states as of a resolved timestamp.
+type postgresSink struct {
+	uri    string
+	db     *gosql.DB
+	schema string
+
+	topicNamer    *TopicNamer
+	transactional bool
+	batchSize     int
+	policy        changefeedbase.SchemaChangePolicy
+
+	// tables caches the destination table schemas, keyed by topic.
+	tables map[string]*postgresTable
+
+	// pending holds, for every topic, the latest buffered event of each key.
+	pending    map[string]map[string]postgresRow
+	numPending int
+	alloc      kvevent.Alloc
+
+	metrics metricsRecorder
+}
+
+var _ Sink = (*postgresSink)(nil)
+
+// postgresRow is a decoded changefeed event.
+type postgresRow struct {
+	key []interface{}
+	// after is the new value of the row, or nil if the row was deleted.
+	after map[string]interface{}
+	mvcc  hlc.Timestamp
+}
+
+// postgresColumn describes a column of a destination table.
+type postgresColumn struct {
+	name string
+	// dataType and udtName are the data_type and udt_name columns of
+	// information_schema.columns.
+	dataType string
+	udtName  string
+}
+
+// postgresTable describes a destination table.
+type postgresTable struct {
+	schema, name string
+	columns      map[string]postgresColumn
+	primaryKey   []string
+}
+
+func makePostgresSink(
+	u *changefeedbase.SinkURL,
+	encodingOpts changefeedbase.EncodingOptions,
+	schemaChangeOpts changefeedbase.SchemaChangeHandlingOptions,
+	targets changefeedbase.Targets,
+	mb metricsRecorderBuilder,
+) (Sink, error) {
+	if encodingOpts.Format != changefeedbase.OptFormatJSON {
+		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+			changefeedbase.OptFormat, encodingOpts.Format)
+	}
+	if encodingOpts.Envelope != changefeedbase.OptEnvelopeWrapped {
+		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+			changefeedbase.OptEnvelope, encodingOpts.Envelope)
+	}
+
+	if u.Path == `` || u.Path == `/` {
+		return nil, errors.Errorf(`must specify database`)
+	}
+
+	s := &postgresSink{
+		schema:  `public`,
+		policy:  schemaChangeOpts.Policy,
+		tables:  make(map[string]*postgresTable),
+		pending: make(map[string]map[string]postgresRow),
+		metrics: mb(requiresResourceAccounting),
+	}
+	if schema := u.ConsumeParam(changefeedbase.SinkParamPostgresSchema); schema != `` {
+		s.schema = schema
+	}
+	if _, err := u.ConsumeBool(changefeedbase.SinkParamPostgresTransactional, &s.transactional); err != nil {
+		return nil, err
+	}
+	s.batchSize = postgresSinkDefaultBatchSize
+	if v := u.ConsumeParam(changefeedbase.SinkParamPostgresBatchSize); v != `` {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return nil, errors.Errorf(`param %s must be a positive integer: %q`,
+				changefeedbase.SinkParamPostgresBatchSize, v)
+		}
+		s.batchSize = n
+	}
+
+	topicNamer, err := MakeTopicNamer(
+		targets,
+		WithPrefix(u.ConsumeParam(changefeedbase.SinkParamTopicPrefix)),
+		WithSingleName(u.ConsumeParam(changefeedbase.SinkParamTopicName)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	s.topicNamer = topicNamer
+
+	// Everything left apart from the driver's own parameters is unknown.
+	s.uri = u.String()
+	for _, p := range postgresConnParams {
+		u.ConsumeParam(p)
+	}
+	if unknownParams := u.RemainingQueryParams(); len(unknownParams) > 0 {
+		return nil, errors.Errorf(
+			`unknown postgres sink query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+	return s, nil
+}
+
+func (s *postgresSink) getConcreteType() sinkType {
+	return sinkTypePostgres
+}
+
+// Dial implements the Sink interface.
+func (s *postgresSink) Dial() error {
+	connector, err := pq.NewConnector(s.uri)
+	if err != nil {
+		return err
+	}
+	s.metrics.netMetrics().WrapPqDialer(connector, "postgres")
+	db := gosql.OpenDB(connector)
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return errors.Wrap(err, "connecting to postgres sink")
+	}
+	s.db = db
+	return nil
+}
+
+// EmitRow implements the Sink interface.
+func (s *postgresSink) EmitRow(
+	ctx context.Context,
+	topicDescr TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+	_headers rowHeaders,
+) error {
+	defer s.metrics.recordOneMessage()(mvcc, len(key)+len(value), sinkDoesNotCompress)
+
+	topic, err := s.topicNamer.Name(topicDescr)
+	if err != nil {
+		return err
+	}
+	row, err := decodePostgresRow(key, value)
+	if err != nil {
+		return err
+	}
+	row.mvcc = mvcc
+
+	rows, ok := s.pending[topic]
+	if !ok {
+		rows = make(map[string]postgresRow)
+		s.pending[topic] = rows
+	}
+	// Events for a key may be redelivered out of order, so keep whichever
+	// version is the newest.
+	if prev, ok := rows[string(key)]; !ok || !mvcc.Less(prev.mvcc) {
+		if !ok {
+			s.numPending++
+		}
+		rows[string(key)] = row
+	}
+	s.alloc.Merge(&alloc)
+
+	if !s.transactional && s.numPending >= s.batchSize {
+		return s.Flush(ctx)
+	}
+	return nil
+}
+
+// decodePostgresRow decodes the key and value of a changefeed event encoded
+// with format=json and envelope=wrapped.
+func decodePostgresRow(key, value []byte) (postgresRow, error) {
+	var row postgresRow
+	if err := decodeJSONUseNumber(key, &row.key); err != nil {
+		return row, errors.Wrap(err, "decoding key")
+	}
+	var envelope map[string]json.RawMessage
+	if err := decodeJSONUseNumber(value, &envelope); err != nil {
+		return row, errors.Wrap(err, "decoding value")
+	}
+	after, ok := envelope[`after`]
+	if !ok {
+		return row, errors.AssertionFailedf("value has no after field")
+	}
+	if err := decodeJSONUseNumber(after, &row.after); err != nil {
+		return row, errors.Wrap(err, "decoding value")
+	}
+	return row, nil
+}
+
+func decodeJSONUseNumber(data []byte, dest interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(dest)
+}
+
+// EmitResolvedTimestamp implements the Sink interface. Every event preceding
+// the resolved timestamp was applied by the preceding Flush, so there is
+// nothing to write.
+func (s *postgresSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	defer s.metrics.recordResolvedCallback()()
+	return nil
+}
+
+// Topics gives the names of all topics that have been initialized
+// and will receive resolved timestamps.
+func (s *postgresSink) Topics() []string {
+	return s.topicNamer.DisplayNamesSlice()
+}
+
+// Flush implements the Sink interface.
+func (s *postgresSink) Flush(ctx context.Context) error {
+	defer s.metrics.recordFlushRequestCallback()()
+
+	if s.numPending == 0 {
+		return nil
+	}
+	if err := s.applyPending(ctx); err != nil {
+		return err
+	}
+	s.pending = make(map[string]map[string]postgresRow)
+	s.numPending = 0
+	s.alloc.Release(ctx)
+	return nil
+}
+
+// postgresExecer is implemented by both *gosql.DB and *gosql.Tx.
+type postgresExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (gosql.Result, error)
+}
+
+func (s *postgresSink) applyPending(ctx context.Context) error {
+	topics := make([]string, 0, len(s.pending))
+	for topic := range s.pending {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+
+	if !s.transactional {
+		for _, topic := range topics {
+			if err := s.applyTopic(ctx, s.db, topic); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil /* opts */)
+	if err != nil {
+		return err
+	}
+	for _, topic := range topics {
+		if err := s.applyTopic(ctx, tx, topic); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// applyTopic writes the buffered events of a topic to its destination table.
+func (s *postgresSink) applyTopic(ctx context.Context, ex postgresExecer, topic string) error {
+	t, err := s.table(ctx, topic, false /* refresh */)
+	if err != nil {
+		return err
+	}
+
+	var deletes [][]interface{}
+	// upserts groups the upserted rows by the list of columns they set, which
+	// only differs between rows around a schema change.
+	upserts := make(map[string][]postgresRow)
+	var upsertCols [][]string
+	for _, row := range s.pending[topic] {
+		if len(row.key) != len(t.primaryKey) {
+			return errors.Errorf(`key %v of topic %s does not match the primary key (%s) of %s`,
+				row.key, topic, strings.Join(t.primaryKey, ", "), t.qualifiedName())
+		}
+		if row.after == nil {
+			deletes = append(deletes, row.key)
+			continue
+		}
+		cols, err := s.resolveColumns(ctx, topic, &t, row.after)
+		if err != nil {
+			return err
+		}
+		sig := strings.Join(cols, ",")
+		if _, ok := upserts[sig]; !ok {
+			upsertCols = append(upsertCols, cols)
+		}
+		upserts[sig] = append(upserts[sig], row)
+	}
+
+	if err := s.applyDeletes(ctx, ex, t, deletes); err != nil {
+		return err
+	}
+	for _, cols := range upsertCols {
+		if err := s.applyUpserts(ctx, ex, t, cols, upserts[strings.Join(cols, ",")]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveColumns returns the sorted names of the columns in after that are
+// written to the destination table. A column that is missing from the
+// destination is an error under schema_change_policy=stop and is skipped
+// otherwise.
+func (s *postgresSink) resolveColumns(
+	ctx context.Context, topic string, t **postgresTable, after map[string]interface{},
+) ([]string, error) {
+	cols := make([]string, 0, len(after))
+	refreshed := false
+	for name := range after {
+		if _, ok := (*t).columns[name]; !ok && !refreshed {
+			// The destination may have been altered since its schema was read.
+			refreshed = true
+			var err error
+			if *t, err = s.table(ctx, topic, true /* refresh */); err != nil {
+				return nil, err
+			}
+		}
+		if _, ok := (*t).columns[name]; !ok {
+			if s.policy == changefeedbase.OptSchemaChangePolicyStop {
+				return nil, errors.Errorf(`column %s does not exist in destination table %s`,
+					name, (*t).qualifiedName())
+			}
+			continue
+		}
+		cols = append(cols, name)
+	}
+	sort.Strings(cols)
+	return cols, nil
+}
+
+func (s *postgresSink) applyDeletes(
+	ctx context.Context, ex postgresExecer, t *postgresTable, keys [][]interface{},
+) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	pkCols := make([]postgresColumn, len(t.primaryKey))
+	quotedPK := make([]string, len(t.primaryKey))
+	for i, name := range t.primaryKey {
+		pkCols[i] = t.columns[name]
+		quotedPK[i] = pq.QuoteIdentifier(name)
+	}
+
+	for _, chunk := range chunkPostgresRows(len(keys), len(pkCols), s.batchSize) {
+		var stmt strings.Builder
+		fmt.Fprintf(&stmt, `DELETE FROM %s WHERE (%s) IN (`,
+			t.qualifiedName(), strings.Join(quotedPK, ", "))
+		args := make([]interface{}, 0, (chunk[1]-chunk[0])*len(pkCols))
+		for i, key := range keys[chunk[0]:chunk[1]] {
+			if i > 0 {
+				stmt.WriteString(`, `)
+			}
+			stmt.WriteString(`(`)
+			for j, col := range pkCols {
+				if j > 0 {
+					stmt.WriteString(`, `)
+				}
+				arg, err := col.arg(key[j])
+				if err != nil {
+					return err
+				}
+				args = append(args, arg)
+				stmt.WriteString(col.placeholder(len(args)))
+			}
+			stmt.WriteString(`)`)
+		}
+		stmt.WriteString(`)`)
+		if _, err := ex.ExecContext(ctx, stmt.String(), args...); err != nil {
+			return errors.Wrapf(err, "deleting from %s", t.qualifiedName())
+		}
+	}
+	return nil
+}
+
+func (s *postgresSink) applyUpserts(
+	ctx context.Context, ex postgresExecer, t *postgresTable, colNames []string, rows []postgresRow,
+) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	cols := make([]postgresColumn, len(colNames))
+	quoted := make([]string, len(colNames))
+	isPK := make(map[string]bool, len(t.primaryKey))
+	for _, name := range t.primaryKey {
+		isPK[name] = true
+	}
+	var updates []string
+	for i, name := range colNames {
+		cols[i] = t.columns[name]
+		quoted[i] = pq.QuoteIdentifier(name)
+		if !isPK[name] {
+			updates = append(updates, fmt.Sprintf(`%[1]s = excluded.%[1]s`, quoted[i]))
+		}
+	}
+	quotedPK := make([]string, len(t.primaryKey))
+	for i, name := range t.primaryKey {
+		quotedPK[i] = pq.QuoteIdentifier(name)
+	}
+	onConflict := `DO NOTHING`
+	if len(updates) > 0 {
+		onConflict = `DO UPDATE SET ` + strings.Join(updates, `, `)
+	}
+
+	for _, chunk := range chunkPostgresRows(len(rows), len(cols), s.batchSize) {
+		var stmt strings.Builder
+		fmt.Fprintf(&stmt, `INSERT INTO %s (%s) VALUES `, t.qualifiedName(), strings.Join(quoted, `, `))
+		args := make([]interface{}, 0, (chunk[1]-chunk[0])*len(cols))
+		for i, row := range rows[chunk[0]:chunk[1]] {
+			if i > 0 {
+				stmt.WriteString(`, `)
+			}
+			stmt.WriteString(`(`)
+			for j, col := range cols {
+				if j > 0 {
+					stmt.WriteString(`, `)
+				}
+				arg, err := col.arg(row.after[col.name])
+				if err != nil {
+					return errors.Wrapf(err, "column %s", col.name)
+				}
+				args = append(args, arg)
+				stmt.WriteString(col.placeholder(len(args)))
+			}
+			stmt.WriteString(`)`)
+		}
+		fmt.Fprintf(&stmt, ` ON CONFLICT (%s) %s`, strings.Join(quotedPK, `, `), onConflict)
+		if _, err := ex.ExecContext(ctx, stmt.String(), args...); err != nil {
+			return errors.Wrapf(err, "upserting into %s", t.qualifiedName())
+		}
+	}
+	return nil
+}
+
+// chunkPostgresRows splits n rows of width placeholders each into [start, end)
+// ranges of at most batchSize rows that fit in a single statement.
+func chunkPostgresRows(n, width, batchSize int) [][2]int {
+	perChunk := batchSize
+	if width > 0 && perChunk*width > postgresMaxPlaceholders {
+		perChunk = postgresMaxPlaceholders / width
+	}
+	var chunks [][2]int
+	for start := 0; start < n; start += perChunk {
+		end := start + perChunk
+		if end > n {
+			end = n
+		}
+		chunks = append(chunks, [2]int{start, end})
+	}
+	return chunks
+}
+
+// table returns the schema of the destination table of a topic, reading it
+// from the destination if it isn't cached or refresh is set.
+func (s *postgresSink) table(
+	ctx context.Context, topic string, refresh bool,
+) (*postgresTable, error) {
+	if t, ok := s.tables[topic]; ok && !refresh {
+		return t, nil
+	}
+
+	t := &postgresTable{schema: s.schema, name: topic, columns: make(map[string]postgresColumn)}
+	// Topics named after fully qualified tables carry the schema.
+	if parts := strings.Split(topic, "."); len(parts) > 1 {
+		t.schema, t.name = parts[len(parts)-2], parts[len(parts)-1]
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+SELECT column_name, data_type, udt_name
+  FROM information_schema.columns
+ WHERE table_schema = $1 AND table_name = $2`, t.schema, t.name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading columns of %s", t.qualifiedName())
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var c postgresColumn
+		if err := rows.Scan(&c.name, &c.dataType, &c.udtName); err != nil {
+			return nil, err
+		}
+		t.columns[c.name] = c
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(t.columns) == 0 {
+		return nil, errors.Errorf(`destination table %s does not exist`, t.qualifiedName())
+	}
+
+	pkRows, err := s.db.QueryContext(ctx, `
+SELECT kcu.column_name
+  FROM information_schema.table_constraints AS tc
+  JOIN information_schema.key_column_usage AS kcu
+    ON kcu.constraint_schema = tc.constraint_schema
+   AND kcu.constraint_name = tc.constraint_name
+   AND kcu.table_name = tc.table_name
+ WHERE tc.constraint_type = 'PRIMARY KEY'
+   AND tc.table_schema = $1 AND tc.table_name = $2
+ ORDER BY kcu.ordinal_position`, t.schema, t.name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading primary key of %s", t.qualifiedName())
+	}
+	defer pkRows.Close()
+	for pkRows.Next() {
+		var name string
+		if err := pkRows.Scan(&name); err != nil {
+			return nil, err
+		}
+		t.primaryKey = append(t.primaryKey, name)
+	}
+	if err := pkRows.Err(); err != nil {
+		return nil, err
+	}
+	if len(t.primaryKey) == 0 {
+		return nil, errors.Errorf(`destination table %s has no primary key`, t.qualifiedName())
+	}
+
+	s.tables[topic] = t
+	return t, nil
+}
+
+func (t *postgresTable) qualifiedName() string {
+	return pq.QuoteIdentifier(t.schema) + "." + pq.QuoteIdentifier(t.name)
+}
+
+// placeholder returns the expression for the i-th placeholder that writes a
+// value to the column.
+func (c postgresColumn) placeholder(i int) string {
+	switch c.udtName {
+	case `geometry`:
+		return fmt.Sprintf(`ST_GeomFromGeoJSON($%d)`, i)
+	case `geography`:
+		return fmt.Sprintf(`ST_GeomFromGeoJSON($%d)::geography`, i)
+	default:
+		return fmt.Sprintf(`$%d`, i)
+	}
+}
+
+// arg converts a JSON-decoded value into the statement argument written to
+// the column. Scalars are sent as text and parsed by the destination
+// according to the column type, which accepts the formats the JSON encoder
+// produces (e.g. "\x" hex for bytes). Arrays are sent as array literals when
+// the column is an array and, like objects, as JSON text otherwise.
+func (c postgresColumn) arg(v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return t, nil
+	case json.Number:
+		return t.String(), nil
+	case bool:
+		return t, nil
+	case []interface{}:
+		if c.dataType == `ARRAY` {
+			return postgresArrayLiteral(t)
+		}
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// postgresArrayLiteral formats a JSON array as a PostgreSQL array literal.
+func postgresArrayLiteral(elems []interface{}) (string, error) {
+	var buf strings.Builder
+	buf.WriteByte('{')
+	for i, e := range elems {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		var s string
+		switch t := e.(type) {
+		case nil:
+			buf.WriteString(`NULL`)
+			continue
+		case string:
+			s = t
+		case json.Number:
+			s = t.String()
+		case bool:
+			s = strconv.FormatBool(t)
+		default:
+			b, err := json.Marshal(t)
+			if err != nil {
+				return "", err
+			}
+			s = string(b)
+		}
+		buf.WriteByte('"')
+		for _, r := range s {
+			if r == '"' || r == '\\' {
+				buf.WriteByte('\\')
+			}
+			buf.WriteRune(r)
+		}
+		buf.WriteByte('"')
+	}
+	buf.WriteByte('}')
+	return buf.String(), nil
+}
+
+// Close implements the Sink interface.
+func (s *postgresSink) Close() error {
+	s.alloc.Release(context.Background())
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
@@ -0,0 +1,222 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package changefeedccl
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/testutils/pgurlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	topic := func(name string) *tableDescriptorTopic {
+		id, _ := strconv.ParseUint(name, 36, 64)
+		td := tabledesc.NewBuilder(&descpb.TableDescriptor{Name: name, ID: descpb.ID(id)}).BuildImmutableTable()
+		spec := changefeedbase.Target{
+			Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+			DescID:            td.GetID(),
+			StatementTimeName: changefeedbase.StatementTimeName(name),
+		}
+		return &tableDescriptorTopic{Metadata: makeMetadata(td), spec: spec}
+	}
+
+	ctx := context.Background()
+	s, sqlDBRaw, _ := serverutils.StartServer(t, base.TestServerArgs{
+		DefaultTestTenant: base.TestIsForStuffThatShouldWorkWithSharedProcessModeButDoesntYet(
+			base.TestTenantProbabilistic, 112863,
+		),
+		UseDatabase: "d",
+	})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(sqlDBRaw)
+	sqlDB.Exec(t, `CREATE DATABASE d`)
+	sqlDB.Exec(t, `CREATE TABLE foo (a INT, b STRING, c INT[], PRIMARY KEY (a, b))`)
+	sqlDB.Exec(t, `CREATE TABLE bar (k STRING PRIMARY KEY, v JSONB)`)
+
+	pgURL, cleanup := pgurlutils.PGUrl(t, s.ApplicationLayer().AdvSQLAddr(), t.Name(), url.User(username.RootUser))
+	defer cleanup()
+	pgURL.Path = `d`
+
+	fooTopic, barTopic := topic(`foo`), topic(`bar`)
+	targets := changefeedbase.Targets{}
+	targets.Add(fooTopic.GetTargetSpecification())
+	targets.Add(barTopic.GetTargetSpecification())
+
+	makeSink := func(t *testing.T, params url.Values, policy changefeedbase.SchemaChangePolicy) Sink {
+		u := pgURL
+		q := u.Query()
+		for k, v := range params {
+			q[k] = v
+		}
+		u.RawQuery = q.Encode()
+		sink, err := makePostgresSink(&changefeedbase.SinkURL{URL: &u},
+			changefeedbase.EncodingOptions{
+				Format:   changefeedbase.OptFormatJSON,
+				Envelope: changefeedbase.OptEnvelopeWrapped,
+			},
+			changefeedbase.SchemaChangeHandlingOptions{Policy: policy},
+			targets, nilMetricsRecorderBuilder)
+		require.NoError(t, err)
+		require.NoError(t, sink.Dial())
+		return sink
+	}
+	ts := func(i int64) hlc.Timestamp { return hlc.Timestamp{WallTime: i} }
+
+	t.Run("apply", func(t *testing.T) {
+		sink := makeSink(t, nil, changefeedbase.OptSchemaChangePolicyBackfill)
+		defer func() { require.NoError(t, sink.Close()) }()
+
+		require.NoError(t, sink.Flush(ctx))
+		require.NoError(t, sink.EmitRow(ctx, fooTopic, []byte(`[1, "x"]`),
+			[]byte(`{"after": {"a": 1, "b": "x", "c": [1, null, 3]}}`), zeroTS, ts(1), zeroAlloc, nil))
+		require.NoError(t, sink.EmitRow(ctx, fooTopic, []byte(`[2, "y"]`),
+			[]byte(`{"after": {"a": 2, "b": "y", "c": null}}`), zeroTS, ts(1), zeroAlloc, nil))
+		require.NoError(t, sink.EmitRow(ctx, barTopic, []byte(`["k"]`),
+			[]byte(`{"after": {"k": "k", "v": {"n": [1, 2]}}}`), zeroTS, ts(1), zeroAlloc, nil))
+
+		// Nothing is written until Flush is called.
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM foo`, [][]string{{`0`}})
+		require.NoError(t, sink.Flush(ctx))
+		sqlDB.CheckQueryResults(t, `SELECT a, b, c FROM foo ORDER BY a`, [][]string{
+			{`1`, `x`, `{1,NULL,3}`},
+			{`2`, `y`, `NULL`},
+		})
+		sqlDB.CheckQueryResults(t, `SELECT k, v FROM bar`, [][]string{{`k`, `{"n": [1, 2]}`}})
+
+		// Only the newest version of a key is applied, regardless of the order
+		// in which the versions arrive, and deletes are applied.
+		require.NoError(t, sink.EmitRow(ctx, fooTopic, []byte(`[1, "x"]`),
+			[]byte(`{"after": {"a": 1, "b": "x", "c": [3]}}`), zeroTS, ts(3), zeroAlloc, nil))
+		require.NoError(t, sink.EmitRow(ctx, fooTopic, []byte(`[1, "x"]`),
+			[]byte(`{"after": {"a": 1, "b": "x", "c": [2]}}`), zeroTS, ts(2), zeroAlloc, nil))
+		require.NoError(t, sink.EmitRow(ctx, fooTopic, []byte(`[2, "y"]`),
+			[]byte(`{"after": null}`), zeroTS, ts(2), zeroAlloc, nil))
+		require.NoError(t, sink.Flush(ctx))
+		sqlDB.CheckQueryResults(t, `SELECT a, b, c FROM foo ORDER BY a`, [][]string{
+			{`1`, `x`, `{3}`},
+		})
+
+		// Replaying events is idempotent.
+		require.NoError(t, sink.EmitRow(ctx, fooTopic, []byte(`[1, "x"]`),
+			[]byte(`{"after": {"a": 1, "b": "x", "c": [3]}}`), zeroTS, ts(3), zeroAlloc, nil))
+		require.NoError(t, sink.EmitRow(ctx, fooTopic, []byte(`[2, "y"]`),
+			[]byte(`{"after": null}`), zeroTS, ts(2), zeroAlloc, nil))
+		require.NoError(t, sink.Flush(ctx))
+		sqlDB.CheckQueryResults(t, `SELECT a, b, c FROM foo ORDER BY a`, [][]string{
+			{`1`, `x`, `{3}`},
+		})
+	})
+
+	t.Run("transactional", func(t *testing.T) {
+		sink := makeSink(t, url.Values{`transactional`: {`true`}}, changefeedbase.OptSchemaChangePolicyBackfill)
+		defer func() { require.NoError(t, sink.Close()) }()
+
+		// A failing write rolls back the whole flush.
+		require.NoError(t, sink.EmitRow(ctx, barTopic, []byte(`["t"]`),
+			[]byte(`{"after": {"k": "t", "v": null}}`), zeroTS, ts(4), zeroAlloc, nil))
+		require.NoError(t, sink.EmitRow(ctx, fooTopic, []byte(`[3, "z"]`),
+			[]byte(`{"after": {"a": 3, "b": "z", "c": ["not an int"]}}`), zeroTS, ts(4), zeroAlloc, nil))
+		require.Error(t, sink.Flush(ctx))
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM bar WHERE k = 't'`, [][]string{{`0`}})
+	})
+
+	t.Run("schema_change_policy", func(t *testing.T) {
+		row := []byte(`{"after": {"k": "new", "v": null, "extra": 1}}`)
+
+		stop := makeSink(t, nil, changefeedbase.OptSchemaChangePolicyStop)
+		defer func() { require.NoError(t, stop.Close()) }()
+		require.NoError(t, stop.EmitRow(ctx, barTopic, []byte(`["new"]`), row, zeroTS, ts(5), zeroAlloc, nil))
+		require.ErrorContains(t, stop.Flush(ctx), `column extra does not exist in destination table "public"."bar"`)
+
+		backfill := makeSink(t, nil, changefeedbase.OptSchemaChangePolicyBackfill)
+		defer func() { require.NoError(t, backfill.Close()) }()
+		require.NoError(t, backfill.EmitRow(ctx, barTopic, []byte(`["new"]`), row, zeroTS, ts(5), zeroAlloc, nil))
+		require.NoError(t, backfill.Flush(ctx))
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM bar WHERE k = 'new'`, [][]string{{`1`}})
+
+		// Columns added to the destination after its schema was read are
+		// picked up.
+		sqlDB.Exec(t, `ALTER TABLE bar ADD COLUMN extra INT`)
+		require.NoError(t, backfill.EmitRow(ctx, barTopic, []byte(`["new"]`), row, zeroTS, ts(6), zeroAlloc, nil))
+		require.NoError(t, backfill.Flush(ctx))
+		sqlDB.CheckQueryResults(t, `SELECT extra FROM bar WHERE k = 'new'`, [][]string{{`1`}})
+	})
+}
+
+func TestPostgresSinkParams(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	encodingOpts := changefeedbase.EncodingOptions{
+		Format:   changefeedbase.OptFormatJSON,
+		Envelope: changefeedbase.OptEnvelopeWrapped,
+	}
+	for _, tc := range []struct {
+		uri  string
+		opts changefeedbase.EncodingOptions
+		err  string
+	}{
+		{uri: `postgres://host/`, opts: encodingOpts, err: `must specify database`},
+		{uri: `postgres://host/d?bogus=1`, opts: encodingOpts, err: `unknown postgres sink query parameters: bogus`},
+		{uri: `postgres://host/d?batch_size=0`, opts: encodingOpts, err: `batch_size must be a positive integer`},
+		{uri: `postgres://host/d`, opts: changefeedbase.EncodingOptions{
+			Format: changefeedbase.OptFormatAvro, Envelope: changefeedbase.OptEnvelopeWrapped,
+		}, err: `incompatible with format=avro`},
+		{uri: `postgresql://host/d?sslmode=disable&transactional=true&schema=s`, opts: encodingOpts},
+	} {
+		t.Run(tc.uri, func(t *testing.T) {
+			u, err := url.Parse(tc.uri)
+			require.NoError(t, err)
+			_, err = makePostgresSink(&changefeedbase.SinkURL{URL: u}, tc.opts,
+				changefeedbase.SchemaChangeHandlingOptions{}, changefeedbase.Targets{}, nilMetricsRecorderBuilder)
+			if tc.err == `` {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestPostgresArrayLiteral(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var elems []interface{}
+	require.NoError(t, decodeJSONUseNumber(
+		[]byte(`["a", "b\"c", "d\\e", null, 1.50, true, {"x": 1}]`), &elems))
+	lit, err := postgresArrayLiteral(elems)
+	require.NoError(t, err)
+	require.Equal(t, `{"a","b\"c","d\\e",NULL,"1.50","true","{\"x\":1}"}`, lit)
+
+	// Arrays destined for non-array columns are written as JSON.
+	arg, err := postgresColumn{dataType: `jsonb`}.arg(elems[:1])
+	require.NoError(t, err)
+	require.Equal(t, `["a"]`, arg)
+
+	n := json.Number(`12345678901234567890`)
+	arg, err = postgresColumn{dataType: `numeric`}.arg(n)
+	require.NoError(t, err)
+	require.Equal(t, `12345678901234567890`, arg)
+}
@@ -1954,6 +1954,10 @@ func (s *fakeKafkaSinkV2) Dial() error {
 		}
 		return td, nil
 	}).AnyTimes()
+	s.adminClient.EXPECT().CreateTopic(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ int32, _ int16, _ map[string]*string, topic string) (kadm.CreateTopicResponse, error) {
+			return kadm.CreateTopicResponse{Topic: topic}, nil
+		}).AnyTimes()
 	kc.adminClient = s.adminClient
 
 	return bs.Dial()
@@ -55,3 +55,60 @@ func newClientFromTLSKeyPair(caCert, clientCert, clientKey []byte) (*httputil.Cl
 
 	return client, nil
 }
+
+// consumeTLSConfig consumes the TLS query parameters (tls_enabled,
+// insecure_tls_skip_verify, ca_cert, client_cert and client_key) from the sink
+// URL and builds the corresponding tls.Config. It returns a nil config if TLS
+// was neither enabled in the URL nor implied by the scheme.
+func consumeTLSConfig(u *changefeedbase.SinkURL, impliedTLS bool) (*tls.Config, error) {
+	var tlsEnabled, tlsSkipVerify bool
+	var caCert, clientCert, clientKey []byte
+	if _, err := u.ConsumeBool(changefeedbase.SinkParamTLSEnabled, &tlsEnabled); err != nil {
+		return nil, err
+	}
+	if _, err := u.ConsumeBool(changefeedbase.SinkParamSkipTLSVerify, &tlsSkipVerify); err != nil {
+		return nil, err
+	}
+	if err := u.DecodeBase64(changefeedbase.SinkParamCACert, &caCert); err != nil {
+		return nil, err
+	}
+	if err := u.DecodeBase64(changefeedbase.SinkParamClientCert, &clientCert); err != nil {
+		return nil, err
+	}
+	if err := u.DecodeBase64(changefeedbase.SinkParamClientKey, &clientKey); err != nil {
+		return nil, err
+	}
+
+	if !tlsEnabled && !impliedTLS {
+		if caCert != nil {
+			return nil, errors.Errorf(`%s requires %s=true`, changefeedbase.SinkParamCACert, changefeedbase.SinkParamTLSEnabled)
+		}
+		if clientCert != nil {
+			return nil, errors.Errorf(`%s requires %s=true`, changefeedbase.SinkParamClientCert, changefeedbase.SinkParamTLSEnabled)
+		}
+		return nil, nil
+	}
+
+	tlsCfg := &tls.Config{InsecureSkipVerify: tlsSkipVerify}
+	if caCert != nil {
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return nil, errors.Errorf("failed to parse certificate data:%s", string(caCert))
+		}
+		tlsCfg.RootCAs = caCertPool
+	}
+
+	if clientCert != nil && clientKey == nil {
+		return nil, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamClientCert, changefeedbase.SinkParamClientKey)
+	} else if clientKey != nil && clientCert == nil {
+		return nil, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamClientKey, changefeedbase.SinkParamClientCert)
+	}
+	if clientCert != nil {
+		cert, err := tls.X509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, `invalid client certificate data provided`)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	return tlsCfg, nil
+}
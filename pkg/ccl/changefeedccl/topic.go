@@ -57,6 +57,9 @@ type TopicNamer struct {
 	// They do not contain placeholder strings.
 	FullNames map[TopicIdentifier]string
 
+	// routedNames caches the full names of topics chosen by topic_expr.
+	routedNames map[string]string
+
 	sliceCache []string
 }
 
@@ -118,6 +121,7 @@ func MakeTopicNamer(targets changefeedbase.Targets, opts ...TopicNameOption) (*T
 		join:         '.',
 		DisplayNames: make(map[changefeedbase.Target]string, targets.Size),
 		FullNames:    make(map[TopicIdentifier]string),
+		routedNames:  make(map[string]string),
 	}
 	for _, opt := range opts {
 		opt.set(tn)
@@ -139,6 +143,9 @@ const familyPlaceholder = "{family}"
 
 // Name generates (with caching) a sink's topic identifier string.
 func (tn *TopicNamer) Name(td TopicDescriptor) (string, error) {
+	if rt, ok := td.(*routedTopic); ok {
+		return tn.routedName(rt.name)
+	}
 	if name, ok := tn.FullNames[td.GetTopicIdentifier()]; ok {
 		return name, nil
 	}
@@ -147,6 +154,21 @@ func (tn *TopicNamer) Name(td TopicDescriptor) (string, error) {
 	return name, err
 }
 
+// routedName generates (with caching) the topic identifier string for a row
+// routed by topic_expr. Prefixes and sanitization apply as usual.
+func (tn *TopicNamer) routedName(name string) (string, error) {
+	if tn.singleName != "" {
+		return "", changefeedbase.WithTerminalError(errors.Newf(
+			"%s cannot be used with a sink that emits to a single topic", changefeedbase.OptTopicExpr))
+	}
+	if fullName, ok := tn.routedNames[name]; ok {
+		return fullName, nil
+	}
+	fullName := tn.nameFromComponents(changefeedbase.StatementTimeName(name))
+	tn.routedNames[name] = fullName
+	return fullName, nil
+}
+
 // DisplayNamesSlice gives all topics that are going to be emitted to,
 // suitable for displaying to the user on feed creation.
 func (tn *TopicNamer) DisplayNamesSlice() []string {
@@ -300,6 +322,16 @@ func (cft *columnFamilyTopic) GetTableName() string {
 
 var _ TopicDescriptor = &columnFamilyTopic{}
 
+// routedTopic is the topic of a row routed by the topic_expr option. It is
+// named by the result of the expression rather than by its target, and
+// otherwise describes the row's table like the topic it wraps.
+type routedTopic struct {
+	TopicDescriptor
+	name string
+}
+
+var _ TopicDescriptor = &routedTopic{}
+
 type noTopic struct{}
 
 var noStatementTimeName changefeedbase.StatementTimeName = ""
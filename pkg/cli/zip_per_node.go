@@ -119,6 +119,11 @@ func (zc *debugZipContext) collectPerNodeData(
 		return err
 	}
 
+	err = zc.getConsistencyRepairBundles(ctx, nodePrinter, id, prefix)
+	if err != nil {
+		return err
+	}
+
 	err = zc.getLogFiles(ctx, nodePrinter, id, prefix)
 	if err != nil {
 		return err
@@ -279,6 +284,9 @@ func (zc *debugZipContext) collectFileList(
 	case serverpb.FileType_EXECUTIONTRACE:
 		fileKind = "execution trace"
 		prefix = prefix + "/executiontraces"
+	case serverpb.FileType_CONSISTENCY_REPAIR:
+		fileKind = "consistency repair bundle"
+		prefix = prefix + "/consistencyrepair"
 	default:
 		return errors.AssertionFailedf("unknown file type: %v", fileType)
 	}
@@ -517,6 +525,31 @@ func (zc *debugZipContext) getLogFiles(
 	return nil
 }
 
+// getConsistencyRepairBundles retrieves the evidence bundles written by the
+// consistency checker when it repaired a replica on the node. Such bundles are
+// rare, so unlike profiles nothing is reported unless the node has some.
+func (zc *debugZipContext) getConsistencyRepairBundles(
+	ctx context.Context, nodePrinter *zipReporter, id string, prefix string,
+) error {
+	var files *serverpb.GetFilesResponse
+	if err := timeutil.RunWithTimeout(ctx, "listing consistency repair bundles", zc.timeout,
+		func(ctx context.Context) error {
+			var err error
+			files, err = zc.status.GetFiles(ctx, &serverpb.GetFilesRequest{
+				NodeId:   id,
+				Type:     serverpb.FileType_CONSISTENCY_REPAIR,
+				Patterns: zipCtx.files.retrievalPatterns(),
+				ListOnly: true,
+			})
+			return err
+		}); err != nil || len(files.Files) == 0 {
+		// Nodes without a configured bundle directory return an error, which is
+		// not worth reporting.
+		return nil //nolint:returnerrcheck
+	}
+	return zc.collectFileList(ctx, nodePrinter, id, prefix, serverpb.FileType_CONSISTENCY_REPAIR)
+}
+
 func (zc *debugZipContext) getProfiles(
 	ctx context.Context, nodePrinter *zipReporter, id string, prefix string,
 ) error {
@@ -0,0 +1,545 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package cloud
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	crypto_rand "crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"net/url"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// The files of an ExternalStorage whose URI has one of the
+// COCKROACH_ENCRYPTION_* parameters are encrypted by the node before they are
+// written, and decrypted after they are read, so that callers which are
+// unaware of encryption, such as EXPORT, changefeeds, IMPORT or RESTORE, can
+// use them as is.
+//
+// An encrypted file starts with a header, made of a preamble, a version, the
+// mode of encryption and the material its key is made from (the salt the key
+// is derived from the passphrase with, or the key encrypted by the KMS),
+// followed by an IV. The plaintext follows, split in chunks which are sealed
+// on their own with AES-GCM, using the IV incremented by the index of the
+// chunk, so that a file can be read starting at any offset by only reading
+// the chunks which cover it. The last chunk is always shorter than the others,
+// and may be empty, so that truncation at a chunk boundary is detected.
+
+var encryptedStoragePreamble = []byte("crdbenc")
+
+const (
+	encryptedStorageVersion = 1
+
+	encryptedStorageModePassphrase = 1
+	encryptedStorageModeKMS        = 2
+
+	encryptedStorageChunkSize = 64 << 10
+	encryptedStorageTagSize   = 16
+	encryptedStorageNonceSize = 12
+	encryptedStorageSaltSize  = 16
+	encryptedStorageKeySize   = 32
+
+	// encryptedStorageMaxKeyMaterialSize bounds the size of the header, so that
+	// it can be read with a single request of a known length.
+	encryptedStorageMaxKeyMaterialSize = 1 << 10
+	// encryptedStorageFixedHeaderSize is the size of the preamble, the version,
+	// the mode and the length of the key material.
+	encryptedStorageFixedHeaderSize = 7 + 1 + 1 + 2
+	encryptedStorageMaxHeaderSize   = encryptedStorageFixedHeaderSize +
+		encryptedStorageMaxKeyMaterialSize + encryptedStorageNonceSize
+)
+
+// setEncryptionFromURI sets the client-side encryption of a storage from the
+// parameters of its URI.
+func setEncryptionFromURI(uri *url.URL, conf *cloudpb.ExternalStorage) error {
+	params := uri.Query()
+	passphrase, kmsURI := params.Get(EncryptionPassphraseParam), params.Get(EncryptionKMSParam)
+	if passphrase == "" && kmsURI == "" {
+		return nil
+	}
+	if passphrase != "" && kmsURI != "" {
+		return errors.Newf("cannot specify both %s and %s", EncryptionPassphraseParam, EncryptionKMSParam)
+	}
+	if conf.Provider == cloudpb.ExternalStorageProvider_external {
+		return errors.Newf("%s and %s cannot be set on an external connection URI; "+
+			"set them on the URI the connection was created with instead",
+			EncryptionPassphraseParam, EncryptionKMSParam)
+	}
+	if kmsURI != "" {
+		kmsURL, err := url.ParseRequestURI(kmsURI)
+		if err != nil {
+			return errors.Wrapf(err, "parsing %s", EncryptionKMSParam)
+		}
+		if kmsURL.Scheme == "external" {
+			return errors.Newf("%s cannot refer to an external connection", EncryptionKMSParam)
+		}
+	}
+	conf.ClientSideEncryption = &cloudpb.ExternalStorage_ClientSideEncryption{
+		Passphrase: passphrase,
+		KMSURI:     kmsURI,
+	}
+	return nil
+}
+
+// encryptedStorageKMSEnv is the environment the KMS of an encrypted storage is
+// used in. The KMS can't refer to an external connection, so it doesn't
+// depend on the user the storage is used by.
+type encryptedStorageKMSEnv struct {
+	settings *cluster.Settings
+	conf     base.ExternalIODirConfig
+	db       isql.DB
+}
+
+var _ KMSEnv = &encryptedStorageKMSEnv{}
+
+// ClusterSettings implements the KMSEnv interface.
+func (e *encryptedStorageKMSEnv) ClusterSettings() *cluster.Settings {
+	return e.settings
+}
+
+// KMSConfig implements the KMSEnv interface.
+func (e *encryptedStorageKMSEnv) KMSConfig() *base.ExternalIODirConfig {
+	return &e.conf
+}
+
+// DBHandle implements the KMSEnv interface.
+func (e *encryptedStorageKMSEnv) DBHandle() isql.DB {
+	return e.db
+}
+
+// User implements the KMSEnv interface.
+func (e *encryptedStorageKMSEnv) User() username.SQLUsername {
+	return username.NodeUserName()
+}
+
+// encryptedStorage wraps an ExternalStorage to encrypt the files written to
+// it, and decrypt the files read from it.
+type encryptedStorage struct {
+	ExternalStorage
+
+	conf   cloudpb.ExternalStorage_ClientSideEncryption
+	kmsEnv KMSEnv
+
+	mu struct {
+		syncutil.Mutex
+		// writeMaterial and writeKey are the key material and the key of the
+		// files written through this storage, generated with the first one.
+		writeMaterial []byte
+		writeKey      []byte
+		// keys caches the keys made from the key material of the files.
+		keys map[string][]byte
+	}
+}
+
+var _ ExternalStorage = &encryptedStorage{}
+
+func newEncryptedStorage(
+	es ExternalStorage, conf cloudpb.ExternalStorage_ClientSideEncryption, kmsEnv KMSEnv,
+) *encryptedStorage {
+	s := &encryptedStorage{ExternalStorage: es, conf: conf, kmsEnv: kmsEnv}
+	s.mu.keys = make(map[string][]byte)
+	return s
+}
+
+// Conf implements the ExternalStorage interface. The encryption is part of
+// the configuration, so that the storages made from it, e.g. by the
+// processors of a job, encrypt their files too.
+func (s *encryptedStorage) Conf() cloudpb.ExternalStorage {
+	conf := s.ExternalStorage.Conf()
+	encryption := s.conf
+	conf.ClientSideEncryption = &encryption
+	return conf
+}
+
+func (s *encryptedStorage) mode() byte {
+	if s.conf.KMSURI != "" {
+		return encryptedStorageModeKMS
+	}
+	return encryptedStorageModePassphrase
+}
+
+func deriveEncryptedStorageKey(passphrase string, salt []byte) []byte {
+	return pbkdf2.Key([]byte(passphrase), salt, 64000, encryptedStorageKeySize, sha256.New)
+}
+
+// writeKey returns the key material and the key to write files with.
+func (s *encryptedStorage) writeKey(ctx context.Context) (material, key []byte, _ error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.writeKey != nil {
+		return s.mu.writeMaterial, s.mu.writeKey, nil
+	}
+	switch s.mode() {
+	case encryptedStorageModePassphrase:
+		material = make([]byte, encryptedStorageSaltSize)
+		if _, err := crypto_rand.Read(material); err != nil {
+			return nil, nil, err
+		}
+		key = deriveEncryptedStorageKey(s.conf.Passphrase, material)
+	case encryptedStorageModeKMS:
+		key = make([]byte, encryptedStorageKeySize)
+		if _, err := crypto_rand.Read(key); err != nil {
+			return nil, nil, err
+		}
+		kms, err := KMSFromURI(ctx, s.conf.KMSURI, s.kmsEnv)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer func() { _ = kms.Close() }()
+		if material, err = kms.Encrypt(ctx, key); err != nil {
+			return nil, nil, errors.Wrap(err, "encrypting data key")
+		}
+		if len(material) > encryptedStorageMaxKeyMaterialSize {
+			return nil, nil, errors.Newf("data key encrypted by KMS is too large: %d bytes", len(material))
+		}
+	}
+	s.mu.writeMaterial, s.mu.writeKey = material, key
+	s.mu.keys[string(material)] = key
+	return material, key, nil
+}
+
+// readKey returns the key of a file with the given header.
+func (s *encryptedStorage) readKey(ctx context.Context, h encryptedFileHeader) ([]byte, error) {
+	if h.mode != s.mode() {
+		if h.mode == encryptedStorageModeKMS {
+			return nil, errors.Newf("file is encrypted with a KMS, but %s is set", EncryptionPassphraseParam)
+		}
+		return nil, errors.Newf("file is encrypted with a passphrase, but %s is set", EncryptionKMSParam)
+	}
+	s.mu.Lock()
+	key, ok := s.mu.keys[string(h.material)]
+	s.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+	switch h.mode {
+	case encryptedStorageModePassphrase:
+		key = deriveEncryptedStorageKey(s.conf.Passphrase, h.material)
+	case encryptedStorageModeKMS:
+		kms, err := KMSFromURI(ctx, s.conf.KMSURI, s.kmsEnv)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = kms.Close() }()
+		if key, err = kms.Decrypt(ctx, h.material); err != nil {
+			return nil, errors.Wrap(err, "decrypting data key")
+		}
+	}
+	s.mu.Lock()
+	s.mu.keys[string(h.material)] = key
+	s.mu.Unlock()
+	return key, nil
+}
+
+// encryptedFileHeader is the header of an encrypted file.
+type encryptedFileHeader struct {
+	mode     byte
+	material []byte
+	iv       []byte
+	// size is the size of the header, i.e. the offset of the first chunk.
+	size int64
+}
+
+func readEncryptedFileHeader(ctx context.Context, r ioctx.ReaderCtx) (encryptedFileHeader, error) {
+	rd := ioctx.ReaderCtxAdapter(ctx, r)
+	fixed := make([]byte, encryptedStorageFixedHeaderSize)
+	if _, err := io.ReadFull(rd, fixed); err != nil || !bytes.HasPrefix(fixed, encryptedStoragePreamble) {
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return encryptedFileHeader{}, err
+		}
+		return encryptedFileHeader{}, errors.New("file does not appear to be encrypted")
+	}
+	if v := fixed[len(encryptedStoragePreamble)]; v != encryptedStorageVersion {
+		return encryptedFileHeader{}, errors.Newf("unexpected encrypted file version %d", v)
+	}
+	h := encryptedFileHeader{mode: fixed[len(encryptedStoragePreamble)+1]}
+	if h.mode != encryptedStorageModePassphrase && h.mode != encryptedStorageModeKMS {
+		return encryptedFileHeader{}, errors.Newf("unexpected encrypted file mode %d", h.mode)
+	}
+	materialLen := int(binary.BigEndian.Uint16(fixed[len(fixed)-2:]))
+	if materialLen > encryptedStorageMaxKeyMaterialSize {
+		return encryptedFileHeader{}, errors.Newf("invalid encrypted file key material size %d", materialLen)
+	}
+	rest := make([]byte, materialLen+encryptedStorageNonceSize)
+	if _, err := io.ReadFull(rd, rest); err != nil {
+		return encryptedFileHeader{}, errors.Wrap(err, "invalid encrypted file header")
+	}
+	h.material, h.iv = rest[:materialLen], rest[materialLen:]
+	h.size = int64(len(fixed) + len(rest))
+	return h, nil
+}
+
+func (h encryptedFileHeader) encode() []byte {
+	buf := make([]byte, 0, encryptedStorageFixedHeaderSize+len(h.material)+len(h.iv))
+	buf = append(buf, encryptedStoragePreamble...)
+	buf = append(buf, encryptedStorageVersion, h.mode)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(h.material)))
+	buf = append(buf, h.material...)
+	return append(buf, h.iv...)
+}
+
+// plaintextSize returns the size of the plaintext of an encrypted file.
+func (h encryptedFileHeader) plaintextSize(fileSize int64) int64 {
+	size := fileSize - h.size
+	chunks := size/(encryptedStorageChunkSize+encryptedStorageTagSize) + 1
+	return size - chunks*encryptedStorageTagSize
+}
+
+// chunkIV returns the IV of a chunk of a file.
+func chunkIV(iv []byte, chunk int64) []byte {
+	res := append([]byte(nil), iv...)
+	binary.BigEndian.PutUint64(res[4:], binary.BigEndian.Uint64(res[4:])+uint64(chunk))
+	return res
+}
+
+func encryptedStorageAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// ciphertextLength returns the length of the ciphertext of the chunks which
+// cover length bytes of plaintext starting at offsetInChunk of the first one.
+func ciphertextLength(offsetInChunk, length int64) int64 {
+	chunks := (offsetInChunk+length)/encryptedStorageChunkSize + 1
+	return chunks * (encryptedStorageChunkSize + encryptedStorageTagSize)
+}
+
+// ReadFile implements the ExternalStorage interface.
+func (s *encryptedStorage) ReadFile(
+	ctx context.Context, basename string, opts ReadOptions,
+) (_ ioctx.ReadCloserCtx, fileSize int64, retErr error) {
+	if opts.Offset < 0 {
+		return nil, 0, errors.Newf("invalid offset %d", opts.Offset)
+	}
+	chunk := opts.Offset / encryptedStorageChunkSize
+	offsetInChunk := opts.Offset % encryptedStorageChunkSize
+
+	innerOpts := ReadOptions{NoFileSize: opts.NoFileSize}
+	if chunk > 0 {
+		// Only the header is read from the start of the file.
+		innerOpts.LengthHint = encryptedStorageMaxHeaderSize
+	} else if opts.LengthHint > 0 {
+		innerOpts.LengthHint = encryptedStorageMaxHeaderSize + ciphertextLength(offsetInChunk, opts.LengthHint)
+	}
+	r, size, err := s.ExternalStorage.ReadFile(ctx, basename, innerOpts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		if retErr != nil && r != nil {
+			retErr = errors.CombineErrors(retErr, r.Close(ctx))
+		}
+	}()
+	h, err := readEncryptedFileHeader(ctx, r)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "reading encrypted file %s", basename)
+	}
+	key, err := s.readKey(ctx, h)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "reading encrypted file %s", basename)
+	}
+	gcm, err := encryptedStorageAEAD(key)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !opts.NoFileSize {
+		fileSize = h.plaintextSize(size)
+	}
+
+	if chunk > 0 {
+		if err := r.Close(ctx); err != nil {
+			r = nil
+			return nil, 0, err
+		}
+		r = nil
+		innerOpts = ReadOptions{
+			Offset:     h.size + chunk*(encryptedStorageChunkSize+encryptedStorageTagSize),
+			NoFileSize: true,
+		}
+		if opts.LengthHint > 0 {
+			innerOpts.LengthHint = ciphertextLength(offsetInChunk, opts.LengthHint)
+		}
+		if r, _, err = s.ExternalStorage.ReadFile(ctx, basename, innerOpts); err != nil {
+			return nil, 0, err
+		}
+	}
+	return &decryptingReader{
+		r:     r,
+		gcm:   gcm,
+		iv:    h.iv,
+		chunk: chunk,
+		skip:  int(offsetInChunk),
+		buf:   make([]byte, encryptedStorageChunkSize+encryptedStorageTagSize),
+	}, fileSize, nil
+}
+
+// decryptingReader decrypts the chunks of an encrypted file.
+type decryptingReader struct {
+	r   ioctx.ReadCloserCtx
+	gcm cipher.AEAD
+	iv  []byte
+
+	// chunk is the index of the next chunk to read, and skip is the number of
+	// bytes of its plaintext to skip.
+	chunk int64
+	skip  int
+	// buf holds the ciphertext of the last chunk read, and then its plaintext,
+	// of which plaintext is what is left to return.
+	buf       []byte
+	plaintext []byte
+	// done is set once the last chunk of the file was read.
+	done bool
+}
+
+var _ ioctx.ReadCloserCtx = &decryptingReader{}
+
+// Read implements the ioctx.ReaderCtx interface.
+func (d *decryptingReader) Read(ctx context.Context, p []byte) (int, error) {
+	for len(d.plaintext) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.fill(ctx); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.plaintext)
+	d.plaintext = d.plaintext[n:]
+	return n, nil
+}
+
+// fill reads and decrypts the next chunk.
+func (d *decryptingReader) fill(ctx context.Context) error {
+	n, err := io.ReadFull(ioctx.ReaderCtxAdapter(ctx, d.r), d.buf[:cap(d.buf)])
+	switch {
+	case errors.Is(err, io.EOF):
+		// Every file ends with a chunk shorter than the others.
+		return errors.New("encrypted file is truncated")
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		d.done = true
+	case err != nil:
+		return err
+	}
+	plaintext, err := d.gcm.Open(d.buf[:0], chunkIV(d.iv, d.chunk), d.buf[:n], nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to decrypt, maybe incorrect passphrase or KMS")
+	}
+	d.chunk++
+	if d.skip > len(plaintext) {
+		d.skip = len(plaintext)
+	}
+	d.plaintext, d.skip = plaintext[d.skip:], 0
+	return nil
+}
+
+// Close implements the ioctx.ReadCloserCtx interface.
+func (d *decryptingReader) Close(ctx context.Context) error {
+	return d.r.Close(ctx)
+}
+
+// Size implements the ExternalStorage interface.
+func (s *encryptedStorage) Size(ctx context.Context, basename string) (int64, error) {
+	r, size, err := s.ReadFile(ctx, basename, ReadOptions{LengthHint: 1})
+	if err != nil {
+		return 0, err
+	}
+	return size, r.Close(ctx)
+}
+
+// Writer implements the ExternalStorage interface.
+func (s *encryptedStorage) Writer(ctx context.Context, basename string) (io.WriteCloser, error) {
+	material, key, err := s.writeKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := encryptedStorageAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	h := encryptedFileHeader{
+		mode:     s.mode(),
+		material: material,
+		iv:       make([]byte, encryptedStorageNonceSize),
+	}
+	if _, err := crypto_rand.Read(h.iv); err != nil {
+		return nil, err
+	}
+	w, err := s.ExternalStorage.Writer(ctx, basename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(h.encode()); err != nil {
+		return nil, errors.CombineErrors(err, w.Close())
+	}
+	return &encryptingWriter{
+		w:   w,
+		gcm: gcm,
+		iv:  h.iv,
+		buf: make([]byte, 0, encryptedStorageChunkSize+encryptedStorageTagSize),
+	}, nil
+}
+
+// encryptingWriter encrypts the chunks of a file.
+type encryptingWriter struct {
+	w     io.WriteCloser
+	gcm   cipher.AEAD
+	iv    []byte
+	chunk int64
+	// buf holds the plaintext of the chunk being written.
+	buf []byte
+}
+
+// Write implements the io.Writer interface.
+func (e *encryptingWriter) Write(p []byte) (int, error) {
+	var wrote int
+	for wrote < len(p) {
+		n := copy(e.buf[len(e.buf):encryptedStorageChunkSize], p[wrote:])
+		e.buf = e.buf[:len(e.buf)+n]
+		wrote += n
+		if len(e.buf) == encryptedStorageChunkSize {
+			if err := e.flush(); err != nil {
+				return wrote, err
+			}
+		}
+	}
+	return wrote, nil
+}
+
+func (e *encryptingWriter) flush() error {
+	sealed := e.gcm.Seal(e.buf[:0], chunkIV(e.iv, e.chunk), e.buf, nil)
+	if _, err := e.w.Write(sealed); err != nil {
+		return err
+	}
+	e.chunk++
+	e.buf = e.buf[:0]
+	return nil
+}
+
+// Close implements the io.Closer interface. It writes the last chunk, which
+// may be empty, of the file.
+func (e *encryptingWriter) Close() error {
+	if err := e.flush(); err != nil {
+		return errors.CombineErrors(err, e.w.Close())
+	}
+	return e.w.Close()
+}
@@ -0,0 +1,180 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package cloud
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudpb"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/stretchr/testify/require"
+)
+
+// memStorage is an in-memory ExternalStorage which only supports reading and
+// writing files.
+type memStorage struct {
+	ExternalStorage
+	files map[string][]byte
+}
+
+func (m *memStorage) ReadFile(
+	ctx context.Context, basename string, opts ReadOptions,
+) (ioctx.ReadCloserCtx, int64, error) {
+	data, ok := m.files[basename]
+	if !ok {
+		return nil, 0, ErrFileDoesNotExist
+	}
+	if opts.Offset > int64(len(data)) {
+		return nil, 0, fmt.Errorf("offset %d past the end of %s", opts.Offset, basename)
+	}
+	return ioctx.NopCloser(ioctx.ReaderAdapter(bytes.NewReader(data[opts.Offset:]))), int64(len(data)), nil
+}
+
+type memStorageWriter struct {
+	bytes.Buffer
+	m    *memStorage
+	name string
+}
+
+func (w *memStorageWriter) Close() error {
+	w.m.files[w.name] = w.Bytes()
+	return nil
+}
+
+func (m *memStorage) Writer(ctx context.Context, basename string) (io.WriteCloser, error) {
+	return &memStorageWriter{m: m, name: basename}, nil
+}
+
+func TestEncryptedStorage(t *testing.T) {
+	ctx := context.Background()
+	mem := &memStorage{files: make(map[string][]byte)}
+	conf := cloudpb.ExternalStorage_ClientSideEncryption{Passphrase: "hunter2"}
+	s := newEncryptedStorage(mem, conf, nil /* kmsEnv */)
+
+	rng := rand.New(rand.NewSource(1))
+	for _, size := range []int{
+		0, 1, encryptedStorageChunkSize - 1, encryptedStorageChunkSize,
+		encryptedStorageChunkSize + 1, 3*encryptedStorageChunkSize + 5,
+	} {
+		t.Run(fmt.Sprintf("size=%d", size), func(t *testing.T) {
+			name := fmt.Sprintf("file-%d", size)
+			data := make([]byte, size)
+			rng.Read(data)
+
+			w, err := s.Writer(ctx, name)
+			require.NoError(t, err)
+			// Write in pieces which don't line up with the chunks.
+			for rest := data; len(rest) > 0; {
+				n := min(len(rest), 1+rng.Intn(2*encryptedStorageChunkSize))
+				_, err := w.Write(rest[:n])
+				require.NoError(t, err)
+				rest = rest[n:]
+			}
+			require.NoError(t, w.Close())
+
+			fileSize, err := s.Size(ctx, name)
+			require.NoError(t, err)
+			require.Equal(t, int64(size), fileSize)
+
+			for _, offset := range []int{
+				0, 1, size / 2, encryptedStorageChunkSize - 1, encryptedStorageChunkSize,
+				2*encryptedStorageChunkSize + 3, size - 1, size,
+			} {
+				if offset < 0 || offset > size {
+					continue
+				}
+				for _, hint := range []int64{0, 1, encryptedStorageChunkSize} {
+					r, fileSize, err := s.ReadFile(ctx, name, ReadOptions{Offset: int64(offset), LengthHint: hint})
+					require.NoError(t, err)
+					require.Equal(t, int64(size), fileSize)
+					got, err := ioctx.ReadAll(ctx, r)
+					require.NoError(t, err)
+					require.NoError(t, r.Close(ctx))
+					require.Equal(t, data[offset:], got, "offset %d", offset)
+				}
+			}
+		})
+	}
+
+	t.Run("truncated", func(t *testing.T) {
+		w, err := s.Writer(ctx, "truncated")
+		require.NoError(t, err)
+		_, err = w.Write(make([]byte, 2*encryptedStorageChunkSize))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		data := mem.files["truncated"]
+		for _, n := range []int{
+			len(data) - 1,
+			len(data) - encryptedStorageTagSize,
+			len(data) - encryptedStorageChunkSize - encryptedStorageTagSize,
+		} {
+			mem.files["truncated"] = data[:n]
+			r, _, err := s.ReadFile(ctx, "truncated", ReadOptions{})
+			require.NoError(t, err)
+			_, err = ioctx.ReadAll(ctx, r)
+			require.Error(t, err)
+		}
+	})
+
+	t.Run("wrong-passphrase", func(t *testing.T) {
+		wrong := newEncryptedStorage(mem, cloudpb.ExternalStorage_ClientSideEncryption{Passphrase: "hunter3"}, nil)
+		r, _, err := wrong.ReadFile(ctx, "file-1", ReadOptions{})
+		require.NoError(t, err)
+		_, err = ioctx.ReadAll(ctx, r)
+		require.ErrorContains(t, err, "failed to decrypt")
+	})
+
+	t.Run("not-encrypted", func(t *testing.T) {
+		mem.files["plain"] = []byte("hello world")
+		_, _, err := s.ReadFile(ctx, "plain", ReadOptions{})
+		require.ErrorContains(t, err, "does not appear to be encrypted")
+	})
+}
+
+func TestEncryptionFromURI(t *testing.T) {
+	for _, tc := range []struct {
+		uri      string
+		expected *cloudpb.ExternalStorage_ClientSideEncryption
+		err      string
+	}{
+		{uri: "nodelocal://1/foo"},
+		{
+			uri:      "nodelocal://1/foo?COCKROACH_ENCRYPTION_PASSPHRASE=abc",
+			expected: &cloudpb.ExternalStorage_ClientSideEncryption{Passphrase: "abc"},
+		},
+		{
+			uri:      "s3://bucket/foo?COCKROACH_ENCRYPTION_KMS=" + url.QueryEscape("aws:///key?REGION=us-east-1"),
+			expected: &cloudpb.ExternalStorage_ClientSideEncryption{KMSURI: "aws:///key?REGION=us-east-1"},
+		},
+		{
+			uri: "nodelocal://1/foo?COCKROACH_ENCRYPTION_PASSPHRASE=abc&COCKROACH_ENCRYPTION_KMS=aws:///key",
+			err: "cannot specify both",
+		},
+		{
+			uri: "nodelocal://1/foo?COCKROACH_ENCRYPTION_KMS=external://kms",
+			err: "cannot refer to an external connection",
+		},
+	} {
+		t.Run(tc.uri, func(t *testing.T) {
+			uri, err := url.Parse(tc.uri)
+			require.NoError(t, err)
+			var conf cloudpb.ExternalStorage
+			err = setEncryptionFromURI(uri, &conf)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, conf.ClientSideEncryption)
+		})
+	}
+}
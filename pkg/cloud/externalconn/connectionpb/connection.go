@@ -11,12 +11,14 @@ import "github.com/cockroachdb/errors"
 func (d *ConnectionDetails) Type() ConnectionType {
 	switch d.Provider {
 	case ConnectionProvider_nodelocal, ConnectionProvider_s3, ConnectionProvider_userfile,
-		ConnectionProvider_gs, ConnectionProvider_azure_storage:
+		ConnectionProvider_gs, ConnectionProvider_azure_storage, ConnectionProvider_sftp,
+		ConnectionProvider_webdav:
 		return TypeStorage
 	case ConnectionProvider_gcp_kms, ConnectionProvider_aws_kms, ConnectionProvider_azure_kms:
 		return TypeKMS
 	case ConnectionProvider_kafka, ConnectionProvider_http, ConnectionProvider_https,
-		ConnectionProvider_webhookhttp, ConnectionProvider_webhookhttps, ConnectionProvider_gcpubsub:
+		ConnectionProvider_webhookhttp, ConnectionProvider_webhookhttps, ConnectionProvider_gcpubsub,
+		ConnectionProvider_nats, ConnectionProvider_amqp:
 		// Changefeed sink providers are TypeStorage for now because they overlap with backup storage providers.
 		return TypeStorage
 	case ConnectionProvider_sql:
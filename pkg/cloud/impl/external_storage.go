@@ -19,5 +19,7 @@ import (
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/httpsink"
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/nodelocal"
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/nullsink"
+	_ "github.com/cockroachdb/cockroach/pkg/cloud/sftp"
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/userfile"
+	_ "github.com/cockroachdb/cockroach/pkg/cloud/webdav"
 )
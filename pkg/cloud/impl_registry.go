@@ -33,7 +33,10 @@ import (
 // redactedQueryParams is the set of query parameter names registered by the
 // external storage providers that should be redacted from external storage URIs
 // whenever they are displayed to a user.
-var redactedQueryParams = map[string]struct{}{}
+var redactedQueryParams = map[string]struct{}{
+	EncryptionPassphraseParam: {},
+	EncryptionKMSParam:        {},
+}
 
 // confParsers maps URI schemes to a ExternalStorageURIParser for that scheme.
 var confParsers = map[string]ExternalStorageURIParser{}
@@ -189,7 +192,14 @@ func ExternalStorageConfFromURI(
 		return cloudpb.ExternalStorage{}, err
 	}
 	if fn, ok := confParsers[uri.Scheme]; ok {
-		return fn(ExternalStorageURIContext{CurrentUser: user}, uri)
+		conf, err := fn(ExternalStorageURIContext{CurrentUser: user}, uri)
+		if err != nil {
+			return cloudpb.ExternalStorage{}, err
+		}
+		if err := setEncryptionFromURI(uri, &conf); err != nil {
+			return cloudpb.ExternalStorage{}, err
+		}
+		return conf, nil
 	}
 	// TODO(adityamaru): Link dedicated ExternalStorage scheme docs once ready.
 	return cloudpb.ExternalStorage{}, errors.Errorf("unsupported storage scheme: %q - refer to docs to find supported"+
@@ -263,7 +273,7 @@ func MakeExternalStorage(
 		DB:                db,
 	}
 
-	return makeExternalStorage[ExternalStorageContext](ctx, dest, conf, limiters, metrics, settings, args, getImpl, opts...)
+	return makeExternalStorage[ExternalStorageContext](ctx, dest, conf, limiters, metrics, settings, db, args, getImpl, opts...)
 }
 
 // EarlyBootExternalStorageConfFromURI generates an
@@ -277,7 +287,14 @@ func EarlyBootExternalStorageConfFromURI(path string) (cloudpb.ExternalStorage,
 		return cloudpb.ExternalStorage{}, err
 	}
 	if fn, ok := earlyBootConfParsers[uri.Scheme]; ok {
-		return fn(uri)
+		conf, err := fn(uri)
+		if err != nil {
+			return cloudpb.ExternalStorage{}, err
+		}
+		if err := setEncryptionFromURI(uri, &conf); err != nil {
+			return cloudpb.ExternalStorage{}, err
+		}
+		return conf, nil
 	}
 	return cloudpb.ExternalStorage{}, errors.Errorf("unsupported storage scheme: %q - refer to docs to find supported storage schemes",
 		uri.Scheme)
@@ -325,7 +342,7 @@ func MakeEarlyBootExternalStorage(
 		Limiters: limiters,
 	}
 
-	return makeExternalStorage[EarlyBootExternalStorageContext](ctx, dest, conf, limiters, metrics, settings, args, getImpl, opts...)
+	return makeExternalStorage[EarlyBootExternalStorageContext](ctx, dest, conf, limiters, metrics, settings, nil /* db */, args, getImpl, opts...)
 }
 
 type rwLimiter struct {
@@ -521,6 +538,7 @@ func makeExternalStorage[T interface {
 	limiters Limiters,
 	metrics metric.Struct,
 	settings *cluster.Settings,
+	db isql.DB,
 	args T,
 	getImpl func(cloudpb.ExternalStorageProvider) (func(context.Context, T, cloudpb.ExternalStorage) (ExternalStorage, error), bool),
 	opts ...ExternalStorageOption,
@@ -566,13 +584,18 @@ func makeExternalStorage[T interface {
 			}
 		}
 
-		return &esWrapper{
+		e = &esWrapper{
 			ExternalStorage: e,
 			lim:             limiters[dest.Provider],
 			ioRecorder:      options.ioAccountingInterceptor,
 			metrics:         cloudMetrics,
 			httpTracer:      httpTracer,
-		}, nil
+		}
+		if dest.ClientSideEncryption != nil {
+			kmsEnv := &encryptedStorageKMSEnv{settings: settings, conf: conf, db: db}
+			e = newEncryptedStorage(e, *dest.ClientSideEncryption, kmsEnv)
+		}
+		return e, nil
 	}
 
 	return nil, errors.Errorf("unsupported external destination type: %s", dest.Provider.String())
@@ -0,0 +1,446 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package sftp
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudpb"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/errors/oserror"
+	"github.com/cockroachdb/redact"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// PrivateKeyParam is the query parameter for the base64-encoded PEM private
+	// key to authenticate with, as an alternative or in addition to the password
+	// in the user info of the URI.
+	PrivateKeyParam = "SFTP_PRIVATE_KEY"
+	// PrivateKeyPassphraseParam is the query parameter for the passphrase the
+	// private key is encrypted with, if any.
+	PrivateKeyPassphraseParam = "SFTP_PRIVATE_KEY_PASSPHRASE"
+	// HostKeyParam is the query parameter for the public key the server must
+	// present, either in the authorized_keys format, e.g. as printed by
+	// ssh-keyscan, or as its SHA256 fingerprint, e.g. "SHA256:...".
+	HostKeyParam = "SFTP_HOST_KEY"
+
+	scheme = "sftp"
+
+	defaultPort = "22"
+)
+
+func parseSFTPURL(uri *url.URL) (cloudpb.ExternalStorage, error) {
+	sftpURL := cloud.ConsumeURL{URL: uri}
+	conf := cloudpb.ExternalStorage{}
+	conf.Provider = cloudpb.ExternalStorageProvider_sftp
+	conf.URI = uri.String()
+	conf.SFTPConfig = &cloudpb.ExternalStorage_SFTP{
+		Host:                 uri.Host,
+		Prefix:               uri.Path,
+		PrivateKeyPassphrase: sftpURL.ConsumeParam(PrivateKeyPassphraseParam),
+		HostKey:              sftpURL.ConsumeParam(HostKeyParam),
+	}
+	if uri.User != nil {
+		conf.SFTPConfig.User = uri.User.Username()
+		conf.SFTPConfig.Password, _ = uri.User.Password()
+	}
+	if encoded := sftpURL.ConsumeParam(PrivateKeyParam); encoded != "" {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return conf, errors.Wrapf(err, "decoding value of %s", PrivateKeyParam)
+		}
+		conf.SFTPConfig.PrivateKey = string(key)
+	}
+
+	// Validate that all the passed in parameters are supported.
+	if unknownParams := sftpURL.RemainingQueryParams(); len(unknownParams) > 0 {
+		return cloudpb.ExternalStorage{}, errors.Errorf(
+			`unknown sftp query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+
+	if conf.SFTPConfig.Host == "" {
+		return conf, errors.New("sftp uri missing host")
+	}
+	if conf.SFTPConfig.User == "" {
+		return conf, errors.New("sftp uri missing user")
+	}
+	if conf.SFTPConfig.Password == "" && conf.SFTPConfig.PrivateKey == "" {
+		return conf, errors.Errorf("sftp uri requires a password or the %q parameter", PrivateKeyParam)
+	}
+	if conf.SFTPConfig.HostKey == "" {
+		return conf, errors.Errorf("sftp uri missing %q parameter", HostKeyParam)
+	}
+	return conf, nil
+}
+
+type sftpStorage struct {
+	conf     *cloudpb.ExternalStorage_SFTP
+	ioConf   base.ExternalIODirConfig
+	settings *cluster.Settings
+	prefix   string
+	uri      string // original URI used to construct this storage
+
+	addr      string
+	sshConfig *ssh.ClientConfig
+
+	mu struct {
+		syncutil.Mutex
+		conn   *ssh.Client
+		client *sftp.Client
+		// lost is closed when the connection of the client is lost.
+		lost chan struct{}
+	}
+}
+
+var _ cloud.ExternalStorage = &sftpStorage{}
+
+func makeSFTPStorage(
+	_ context.Context, args cloud.EarlyBootExternalStorageContext, dest cloudpb.ExternalStorage,
+) (cloud.ExternalStorage, error) {
+	telemetry.Count("external-io.sftp")
+	conf := dest.SFTPConfig
+	if conf == nil {
+		return nil, errors.Errorf("sftp storage requested but info missing")
+	}
+
+	sshConfig := &ssh.ClientConfig{User: conf.User}
+	if conf.Password != "" {
+		sshConfig.Auth = append(sshConfig.Auth, ssh.Password(conf.Password))
+	}
+	if conf.PrivateKey != "" {
+		var signer ssh.Signer
+		var err error
+		if conf.PrivateKeyPassphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(conf.PrivateKey), []byte(conf.PrivateKeyPassphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey([]byte(conf.PrivateKey))
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "sftp: parsing private key")
+		}
+		// Try the key before the password, which servers may limit the attempts
+		// of.
+		sshConfig.Auth = append([]ssh.AuthMethod{ssh.PublicKeys(signer)}, sshConfig.Auth...)
+	}
+	if err := setHostKeyCallback(sshConfig, conf.HostKey); err != nil {
+		return nil, err
+	}
+
+	addr := conf.Host
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, defaultPort)
+	}
+	return &sftpStorage{
+		conf:      conf,
+		ioConf:    args.IOConf,
+		settings:  args.Settings,
+		prefix:    conf.Prefix,
+		uri:       dest.URI,
+		addr:      addr,
+		sshConfig: sshConfig,
+	}, nil
+}
+
+// setHostKeyCallback sets up the verification of the key of the server
+// against the pinned one.
+func setHostKeyCallback(config *ssh.ClientConfig, hostKey string) error {
+	if strings.HasPrefix(hostKey, "SHA256:") {
+		config.HostKeyCallback = func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			if fingerprint := ssh.FingerprintSHA256(key); fingerprint != hostKey {
+				return errors.Newf("sftp: host key mismatch: server presented %s", fingerprint)
+			}
+			return nil
+		}
+		return nil
+	}
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(hostKey))
+	if err != nil {
+		return errors.Wrapf(err, "sftp: parsing %s", HostKeyParam)
+	}
+	config.HostKeyCallback = ssh.FixedHostKey(key)
+	// Have the server present the pinned key, if it has several.
+	if key.Type() == ssh.KeyAlgoRSA {
+		config.HostKeyAlgorithms = []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+	} else {
+		config.HostKeyAlgorithms = []string{key.Type()}
+	}
+	return nil
+}
+
+// client returns the client of the storage, connecting to the server if it
+// isn't connected yet or if its connection was lost.
+func (s *sftpStorage) client(ctx context.Context) (*sftp.Client, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.client != nil {
+		select {
+		case <-s.mu.lost:
+			_ = s.mu.conn.Close()
+			s.mu.conn, s.mu.client, s.mu.lost = nil, nil, nil
+		default:
+			return s.mu.client, nil
+		}
+	}
+	conn, client, err := s.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lost := make(chan struct{})
+	go func() {
+		// Wait returns once the connection is closed, be it by Close or by a
+		// failure.
+		_ = client.Wait()
+		close(lost)
+	}()
+	s.mu.conn, s.mu.client, s.mu.lost = conn, client, lost
+	return client, nil
+}
+
+func (s *sftpStorage) dial(ctx context.Context) (_ *ssh.Client, _ *sftp.Client, retErr error) {
+	timeout := cloud.Timeout.Get(&s.settings.SV)
+	var d net.Dialer
+	netConn, err := d.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "sftp: dialing %s", s.addr)
+	}
+	// Neither the handshake nor the negotiation of the SFTP session observe the
+	// context.
+	if err := netConn.SetDeadline(timeutil.Now().Add(timeout)); err != nil {
+		_ = netConn.Close()
+		return nil, nil, err
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(netConn, s.addr, s.sshConfig)
+	if err != nil {
+		_ = netConn.Close()
+		return nil, nil, errors.Wrapf(err, "sftp: connecting to %s", s.addr)
+	}
+	conn := ssh.NewClient(sshConn, chans, reqs)
+	defer func() {
+		if retErr != nil {
+			_ = conn.Close()
+		}
+	}()
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "sftp: starting sftp session")
+	}
+	if err := netConn.SetDeadline(time.Time{}); err != nil {
+		_ = client.Close()
+		return nil, nil, err
+	}
+	return conn, client, nil
+}
+
+func (s *sftpStorage) Conf() cloudpb.ExternalStorage {
+	return cloudpb.ExternalStorage{
+		Provider:   cloudpb.ExternalStorageProvider_sftp,
+		SFTPConfig: s.conf,
+		URI:        s.uri,
+	}
+}
+
+func (s *sftpStorage) ExternalIOConf() base.ExternalIODirConfig {
+	return s.ioConf
+}
+
+func (s *sftpStorage) RequiresExternalIOAccounting() bool { return true }
+
+func (s *sftpStorage) Settings() *cluster.Settings {
+	return s.settings
+}
+
+func (s *sftpStorage) ReadFile(
+	ctx context.Context, basename string, opts cloud.ReadOptions,
+) (_ ioctx.ReadCloserCtx, fileSize int64, retErr error) {
+	c, err := s.client(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	name := path.Join(s.prefix, basename)
+	f, err := c.Open(name)
+	if err != nil {
+		if oserror.IsNotExist(err) {
+			return nil, 0, cloud.WrapErrFileDoesNotExist(err, "sftp file does not exist")
+		}
+		return nil, 0, errors.Wrapf(err, "opening %s", name)
+	}
+	defer func() {
+		if retErr != nil {
+			retErr = errors.CombineErrors(retErr, f.Close())
+		}
+	}()
+	if !opts.NoFileSize {
+		info, err := f.Stat()
+		if err != nil {
+			return nil, 0, err
+		}
+		fileSize = info.Size()
+	}
+	if opts.Offset != 0 {
+		if _, err := f.Seek(opts.Offset, io.SeekStart); err != nil {
+			return nil, 0, err
+		}
+	}
+	if opts.LengthHint > 0 {
+		return ioctx.ReadCloserAdapter(struct {
+			io.Reader
+			io.Closer
+		}{io.LimitReader(f, opts.LengthHint), f}), fileSize, nil
+	}
+	return ioctx.ReadCloserAdapter(f), fileSize, nil
+}
+
+func (s *sftpStorage) Writer(ctx context.Context, basename string) (io.WriteCloser, error) {
+	c, err := s.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name := path.Join(s.prefix, basename)
+	return cloud.BackgroundPipe(ctx, func(ctx context.Context, r io.Reader) error {
+		if err := c.MkdirAll(path.Dir(name)); err != nil {
+			return errors.Wrapf(err, "creating directory of %s", name)
+		}
+		f, err := c.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+		if err != nil {
+			return errors.Wrapf(err, "opening %s", name)
+		}
+		_, err = f.ReadFrom(r)
+		return errors.CombineErrors(err, f.Close())
+	}), nil
+}
+
+func (s *sftpStorage) List(
+	ctx context.Context, prefix string, opts cloud.ListOptions, fn cloud.ListingFn,
+) error {
+	c, err := s.client(ctx)
+	if err != nil {
+		return err
+	}
+	dest := cloud.JoinPathPreservingTrailingSlash(s.prefix, prefix)
+	afterKey := opts.CanonicalAfterKey(s.prefix)
+
+	// Unless it ends with a slash, the prefix matches the start of the names of
+	// files and directories in its parent directory.
+	walkRoot := dest
+	if !strings.HasSuffix(dest, "/") {
+		walkRoot = path.Dir(dest)
+	}
+	var res []string
+	for walker := c.Walk(walkRoot); walker.Step(); {
+		if err := walker.Err(); err != nil {
+			if oserror.IsNotExist(err) && walker.Path() == walkRoot {
+				return nil
+			}
+			return errors.Wrap(err, "unable to list files")
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if p := walker.Path(); !walker.Stat().IsDir() && strings.HasPrefix(p, dest) {
+			res = append(res, p)
+		}
+	}
+
+	// Sort results so that we can group as we go.
+	sort.Strings(res)
+	var prevPrefix string
+	for _, f := range res {
+		f = strings.TrimPrefix(f, dest)
+		if opts.Delimiter != "" {
+			if i := strings.Index(f, opts.Delimiter); i >= 0 {
+				f = f[:i+len(opts.Delimiter)]
+			}
+			if f == prevPrefix {
+				continue
+			}
+			prevPrefix = f
+		}
+
+		// afterKey is a full key so we must compare against the full file key.
+		if dest+f <= afterKey {
+			continue
+		}
+
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sftpStorage) Delete(ctx context.Context, basename string) error {
+	return timeutil.RunWithTimeout(ctx, redact.Sprintf("delete sftp file %s", basename),
+		cloud.Timeout.Get(&s.settings.SV), func(ctx context.Context) error {
+			c, err := s.client(ctx)
+			if err != nil {
+				return err
+			}
+			if err := c.Remove(path.Join(s.prefix, basename)); err != nil && !oserror.IsNotExist(err) {
+				return errors.Wrap(err, "delete file")
+			}
+			return nil
+		})
+}
+
+func (s *sftpStorage) Size(ctx context.Context, basename string) (int64, error) {
+	var size int64
+	err := timeutil.RunWithTimeout(ctx, redact.Sprintf("size sftp file %s", basename),
+		cloud.Timeout.Get(&s.settings.SV), func(ctx context.Context) error {
+			c, err := s.client(ctx)
+			if err != nil {
+				return err
+			}
+			info, err := c.Stat(path.Join(s.prefix, basename))
+			if err != nil {
+				if oserror.IsNotExist(err) {
+					return cloud.WrapErrFileDoesNotExist(err, "sftp file does not exist")
+				}
+				return err
+			}
+			size = info.Size()
+			return nil
+		})
+	return size, err
+}
+
+func (s *sftpStorage) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.conn == nil {
+		return nil
+	}
+	err := errors.CombineErrors(s.mu.client.Close(), s.mu.conn.Close())
+	s.mu.conn, s.mu.client, s.mu.lost = nil, nil, nil
+	return err
+}
+
+func init() {
+	cloud.RegisterExternalStorageProvider(cloudpb.ExternalStorageProvider_sftp,
+		cloud.RegisteredProvider{
+			EarlyBootParseFn:     parseSFTPURL,
+			EarlyBootConstructFn: makeSFTPStorage,
+			RedactedParams:       cloud.RedactedParams(PrivateKeyParam, PrivateKeyPassphraseParam),
+			Schemes:              []string{scheme},
+		})
+}
@@ -27,6 +27,12 @@ const (
 	// LocalityURLParam is the parameter name used when specifying a locality tag
 	// in a locality aware backup/restore.
 	LocalityURLParam = "COCKROACH_LOCALITY"
+	// EncryptionPassphraseParam is the parameter name used to have the files of
+	// any External Storage encrypted with a key derived from a passphrase.
+	EncryptionPassphraseParam = "COCKROACH_ENCRYPTION_PASSPHRASE"
+	// EncryptionKMSParam is the parameter name used to have the files of any
+	// External Storage encrypted with a key protected by a KMS.
+	EncryptionKMSParam = "COCKROACH_ENCRYPTION_KMS"
 
 	redactionMarker = "redacted"
 )
@@ -215,6 +221,12 @@ func (u *ConsumeURL) RemainingQueryParams() (res []string) {
 		if p == LocalityURLParam {
 			continue
 		}
+		// Similarly, the client-side encryption parameters are supported for all
+		// External Storage implementations, which are wrapped by the encryption
+		// once they are created.
+		if p == EncryptionPassphraseParam || p == EncryptionKMSParam {
+			continue
+		}
 		res = append(res, p)
 	}
 	return
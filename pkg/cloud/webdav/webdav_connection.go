@@ -0,0 +1,37 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package webdav
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud/externalconn"
+	"github.com/cockroachdb/cockroach/pkg/cloud/externalconn/connectionpb"
+	"github.com/cockroachdb/cockroach/pkg/cloud/externalconn/utils"
+	"github.com/cockroachdb/errors"
+)
+
+func validateWebDAVConnectionURI(
+	ctx context.Context, env externalconn.ExternalConnEnv, uri string,
+) error {
+	if err := utils.CheckExternalStorageConnection(ctx, env, uri); err != nil {
+		return errors.Wrap(err, "failed to create webdav external connection")
+	}
+
+	return nil
+}
+
+func init() {
+	for _, s := range []string{scheme, secureScheme} {
+		externalconn.RegisterConnectionDetailsFromURIFactory(
+			s,
+			connectionpb.ConnectionProvider_webdav,
+			externalconn.SimpleURIFactory,
+		)
+
+		externalconn.RegisterDefaultValidation(s, validateWebDAVConnectionURI)
+	}
+}
@@ -0,0 +1,458 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package webdav
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudpb"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/redact"
+)
+
+const (
+	// scheme is the scheme of storages on WebDAV servers reached over HTTP, and
+	// secureScheme the one of those reached over HTTPS.
+	scheme       = "webdav"
+	secureScheme = "webdavs"
+)
+
+func parseWebDAVURL(uri *url.URL) (cloudpb.ExternalStorage, error) {
+	conf := cloudpb.ExternalStorage{}
+	conf.Provider = cloudpb.ExternalStorageProvider_webdav
+	conf.URI = uri.String()
+
+	// Validate that all the passed in parameters are supported.
+	webdavURL := cloud.ConsumeURL{URL: uri}
+	if unknownParams := webdavURL.RemainingQueryParams(); len(unknownParams) > 0 {
+		return cloudpb.ExternalStorage{}, errors.Errorf(
+			`unknown webdav query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+	if uri.Host == "" {
+		return conf, errors.New("webdav uri missing host")
+	}
+
+	base := url.URL{Scheme: "http", Host: uri.Host, Path: uri.Path}
+	if uri.Scheme == secureScheme {
+		base.Scheme = "https"
+	}
+	conf.WebDAVConfig = &cloudpb.ExternalStorage_WebDAV{BaseURI: base.String()}
+	if uri.User != nil {
+		conf.WebDAVConfig.User = uri.User.Username()
+		conf.WebDAVConfig.Password, _ = uri.User.Password()
+	}
+	return conf, nil
+}
+
+type webdavStorage struct {
+	conf     *cloudpb.ExternalStorage_WebDAV
+	base     *url.URL
+	client   *http.Client
+	ioConf   base.ExternalIODirConfig
+	settings *cluster.Settings
+	uri      string // original URI used to construct this storage
+
+	mu struct {
+		syncutil.Mutex
+		// collections are the paths of the collections known to exist, which
+		// files can be written to.
+		collections map[string]struct{}
+	}
+}
+
+var _ cloud.ExternalStorage = &webdavStorage{}
+
+func makeWebDAVStorage(
+	_ context.Context, args cloud.EarlyBootExternalStorageContext, dest cloudpb.ExternalStorage,
+) (cloud.ExternalStorage, error) {
+	telemetry.Count("external-io.webdav")
+	if args.IOConf.DisableHTTP {
+		return nil, errors.New("external http access disabled")
+	}
+	conf := dest.WebDAVConfig
+	if conf == nil {
+		return nil, errors.Errorf("webdav storage requested but info missing")
+	}
+	base, err := url.Parse(conf.BaseURI)
+	if err != nil {
+		return nil, err
+	}
+	client, err := cloud.MakeHTTPClient(args.Settings, args.MetricsRecorder,
+		cloud.HTTPClientConfig{
+			Bucket:         base.Host,
+			Client:         args.ExternalStorageOptions().ClientName,
+			Cloud:          "webdav",
+			HttpMiddleware: args.HttpMiddleware,
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "webdav: unable to create transport")
+	}
+	s := &webdavStorage{
+		conf:     conf,
+		base:     base,
+		client:   client,
+		ioConf:   args.IOConf,
+		settings: args.Settings,
+		uri:      dest.URI,
+	}
+	s.mu.collections = make(map[string]struct{})
+	return s, nil
+}
+
+func (s *webdavStorage) Conf() cloudpb.ExternalStorage {
+	return cloudpb.ExternalStorage{
+		Provider:     cloudpb.ExternalStorageProvider_webdav,
+		WebDAVConfig: s.conf,
+		URI:          s.uri,
+	}
+}
+
+func (s *webdavStorage) ExternalIOConf() base.ExternalIODirConfig {
+	return s.ioConf
+}
+
+func (s *webdavStorage) RequiresExternalIOAccounting() bool { return true }
+
+func (s *webdavStorage) Settings() *cluster.Settings {
+	return s.settings
+}
+
+// resolve returns the path of a file of the storage on the server.
+func (s *webdavStorage) resolve(basename string) string {
+	return path.Join(s.base.Path, basename)
+}
+
+// req sends a request for the given path. Responses with an unsuccessful
+// status are returned as errors.
+func (s *webdavStorage) req(
+	ctx context.Context, method, p string, body io.Reader, headers map[string]string,
+) (*http.Response, error) {
+	dest := *s.base
+	dest.Path = p
+	req, err := http.NewRequestWithContext(ctx, method, dest.String(), body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error constructing request %s %q", method, dest.String())
+	}
+	if s.conf.User != "" {
+		req.SetBasicAuth(s.conf.User, s.conf.Password)
+	}
+	for key, val := range headers {
+		req.Header.Set(key, val)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+	_ = resp.Body.Close()
+	err = &webdavStatusError{method: method, status: resp.StatusCode,
+		msg: fmt.Sprintf("%s %q", resp.Status, msg)}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, cloud.WrapErrFileDoesNotExist(err, "webdav file does not exist")
+	}
+	return nil, err
+}
+
+// reqNoBody is like req but it closes the response body.
+func (s *webdavStorage) reqNoBody(
+	ctx context.Context, method, p string, body io.Reader, headers map[string]string,
+) error {
+	resp, err := s.req(ctx, method, p, body, headers)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+type webdavStatusError struct {
+	method string
+	status int
+	msg    string
+}
+
+func (e *webdavStatusError) Error() string {
+	return fmt.Sprintf("error response from server to %s: %s", e.method, e.msg)
+}
+
+func hasStatus(err error, status int) bool {
+	var statusErr *webdavStatusError
+	return errors.As(err, &statusErr) && statusErr.status == status
+}
+
+// openStreamAt sends a GET for the file at the given path, starting at pos,
+// and returns the response and the size of the file.
+func (s *webdavStorage) openStreamAt(
+	ctx context.Context, p string, pos int64,
+) (*http.Response, int64, error) {
+	var headers map[string]string
+	if pos > 0 {
+		headers = map[string]string{"Range": fmt.Sprintf("bytes=%d-", pos)}
+	}
+	resp, err := s.req(ctx, "GET", p, nil, headers)
+	if err != nil {
+		return nil, 0, err
+	}
+	if pos == 0 {
+		return resp, resp.ContentLength, nil
+	}
+	size, err := cloud.CheckHTTPContentRangeHeader(resp.Header.Get("Content-Range"), pos)
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, 0, err
+	}
+	return resp, size, nil
+}
+
+func (s *webdavStorage) ReadFile(
+	ctx context.Context, basename string, opts cloud.ReadOptions,
+) (_ ioctx.ReadCloserCtx, fileSize int64, _ error) {
+	p := s.resolve(basename)
+	stream, size, err := s.openStreamAt(ctx, p, opts.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	opener := func(ctx context.Context, pos int64) (io.ReadCloser, int64, error) {
+		resp, _, err := s.openStreamAt(ctx, p, pos)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp.Body, size, nil
+	}
+	return cloud.NewResumingReader(ctx, opener, stream.Body, opts.Offset, size, basename,
+		cloud.ResumingReaderRetryOnErrFnForSettings(ctx, s.settings), nil), size, nil
+}
+
+func (s *webdavStorage) Writer(ctx context.Context, basename string) (io.WriteCloser, error) {
+	p := s.resolve(basename)
+	return cloud.BackgroundPipe(ctx, func(ctx context.Context, r io.Reader) error {
+		if err := s.makeCollection(ctx, path.Dir(p)); err != nil {
+			return err
+		}
+		return s.reqNoBody(ctx, "PUT", p, r, nil)
+	}), nil
+}
+
+// makeCollection creates a collection and its missing parents, since files
+// can only be written in existing collections.
+func (s *webdavStorage) makeCollection(ctx context.Context, p string) error {
+	if p == "/" || p == "." {
+		return nil
+	}
+	s.mu.Lock()
+	_, ok := s.mu.collections[p]
+	s.mu.Unlock()
+	if ok {
+		return nil
+	}
+
+	if _, err := s.propfind(ctx, p, "0"); err != nil {
+		if !errors.Is(err, cloud.ErrFileDoesNotExist) {
+			return err
+		}
+		if err := s.makeCollection(ctx, path.Dir(p)); err != nil {
+			return err
+		}
+		// The collection may have been created concurrently, in which case the
+		// server responds with 405 Method Not Allowed.
+		if err := s.reqNoBody(ctx, "MKCOL", p+"/", nil, nil); err != nil && !hasStatus(err, http.StatusMethodNotAllowed) {
+			return errors.Wrapf(err, "creating collection %s", p)
+		}
+	}
+	s.mu.Lock()
+	s.mu.collections[p] = struct{}{}
+	s.mu.Unlock()
+	return nil
+}
+
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?>` +
+	`<D:propfind xmlns:D="DAV:"><D:prop><D:resourcetype/></D:prop></D:propfind>`
+
+type webdavMultistatus struct {
+	Responses []struct {
+		Href      string `xml:"DAV: href"`
+		Propstats []struct {
+			Prop struct {
+				ResourceType struct {
+					Collection *struct{} `xml:"DAV: collection"`
+				} `xml:"DAV: resourcetype"`
+			} `xml:"DAV: prop"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+type webdavEntry struct {
+	path       string
+	collection bool
+}
+
+// propfind returns the entries of the resource at a path, and of its members
+// if depth is "1".
+func (s *webdavStorage) propfind(ctx context.Context, p, depth string) ([]webdavEntry, error) {
+	resp, err := s.req(ctx, "PROPFIND", p, strings.NewReader(propfindBody), map[string]string{
+		"Depth":        depth,
+		"Content-Type": `application/xml; charset="utf-8"`,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var ms webdavMultistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, errors.Wrap(err, "decoding PROPFIND response")
+	}
+	entries := make([]webdavEntry, 0, len(ms.Responses))
+	for _, r := range ms.Responses {
+		href, err := url.Parse(r.Href)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing href %q", r.Href)
+		}
+		e := webdavEntry{path: path.Clean(href.Path)}
+		for _, ps := range r.Propstats {
+			if ps.Prop.ResourceType.Collection != nil {
+				e.collection = true
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// walk calls fn with the path of every file under a collection.
+func (s *webdavStorage) walk(ctx context.Context, dir string, fn func(string) error) error {
+	entries, err := s.propfind(ctx, dir+"/", "1")
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.path == dir {
+			continue
+		}
+		if e.collection {
+			if err := s.walk(ctx, e.path, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := fn(e.path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *webdavStorage) List(
+	ctx context.Context, prefix string, opts cloud.ListOptions, fn cloud.ListingFn,
+) error {
+	dest := cloud.JoinPathPreservingTrailingSlash(s.base.Path, prefix)
+	afterKey := opts.CanonicalAfterKey(s.base.Path)
+
+	// Unless it ends with a slash, the prefix matches the start of the names of
+	// the members of its parent collection.
+	walkRoot := path.Clean(dest)
+	if !strings.HasSuffix(dest, "/") {
+		walkRoot = path.Dir(dest)
+	}
+	var res []string
+	if err := s.walk(ctx, walkRoot, func(p string) error {
+		if strings.HasPrefix(p, dest) {
+			res = append(res, p)
+		}
+		return nil
+	}); err != nil {
+		if errors.Is(err, cloud.ErrFileDoesNotExist) {
+			return nil
+		}
+		return errors.Wrap(err, "unable to list files")
+	}
+
+	// Sort results so that we can group as we go.
+	sort.Strings(res)
+	var prevPrefix string
+	for _, f := range res {
+		f = strings.TrimPrefix(f, dest)
+		if opts.Delimiter != "" {
+			if i := strings.Index(f, opts.Delimiter); i >= 0 {
+				f = f[:i+len(opts.Delimiter)]
+			}
+			if f == prevPrefix {
+				continue
+			}
+			prevPrefix = f
+		}
+
+		// afterKey is a full key so we must compare against the full file key.
+		if dest+f <= afterKey {
+			continue
+		}
+
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *webdavStorage) Delete(ctx context.Context, basename string) error {
+	return timeutil.RunWithTimeout(ctx, redact.Sprintf("DELETE %s", basename),
+		cloud.Timeout.Get(&s.settings.SV), func(ctx context.Context) error {
+			err := s.reqNoBody(ctx, "DELETE", s.resolve(basename), nil, nil)
+			if errors.Is(err, cloud.ErrFileDoesNotExist) {
+				return nil
+			}
+			return err
+		})
+}
+
+func (s *webdavStorage) Size(ctx context.Context, basename string) (int64, error) {
+	var size int64
+	if err := timeutil.RunWithTimeout(ctx, redact.Sprintf("HEAD %s", basename),
+		cloud.Timeout.Get(&s.settings.SV), func(ctx context.Context) error {
+			resp, err := s.req(ctx, "HEAD", s.resolve(basename), nil, nil)
+			if err != nil {
+				return err
+			}
+			size = resp.ContentLength
+			return resp.Body.Close()
+		}); err != nil {
+		return 0, err
+	}
+	if size < 0 {
+		return 0, errors.Errorf("bad ContentLength: %d", size)
+	}
+	return size, nil
+}
+
+func (s *webdavStorage) Close() error {
+	return nil
+}
+
+func init() {
+	cloud.RegisterExternalStorageProvider(cloudpb.ExternalStorageProvider_webdav,
+		cloud.RegisteredProvider{
+			EarlyBootParseFn:     parseWebDAVURL,
+			EarlyBootConstructFn: makeWebDAVStorage,
+			RedactedParams:       cloud.RedactedParams(),
+			Schemes:              []string{scheme, secureScheme},
+		})
+}
@@ -853,6 +853,17 @@ var specs = []stmtSpec{
 			"opt_with_options":      "( | 'WITH' changefeed_option ( ',' changefeed_option )* )"},
 		unlink: []string{"schedule_label", "schedule_option", "changefeed_sink", "changefeed_option", "crontab"},
 	},
+	{
+		name:   "create_schedule_for_restore_drill_stmt",
+		inline: []string{"string_or_placeholder_opt_list", "string_or_placeholder_list", "opt_with_schedule_options", "cron_expr"},
+		replace: map[string]string{
+			"string_or_placeholder": "collectionURI",
+			"sconst_or_placeholder": "crontab",
+			"schedule_label_spec":   "( 'IF NOT EXISTS' | )  schedule_label",
+			"kv_option_list":        "schedule_option",
+			"opt_with_options":      "( | 'WITH' restore_drill_option ( ',' restore_drill_option )* )"},
+		unlink: []string{"schedule_label", "collectionURI", "restore_drill_option", "crontab", "schedule_option"},
+	},
 	{
 		name:    "create_schema_stmt",
 		inline:  []string{"qualifiable_schema_name", "opt_schema_name", "opt_name"},
@@ -22,6 +22,9 @@ func _() {
 	_ = x[Constraints-7]
 	_ = x[VoterConstraints-8]
 	_ = x[LeasePreferences-9]
+	_ = x[NumWitnesses-10]
+	_ = x[StorageCompression-11]
+	_ = x[StorageValueSeparationMinSize-12]
 }
 
 func (i Field) String() string {
@@ -44,6 +47,12 @@ func (i Field) String() string {
 		return "voter_constraints"
 	case LeasePreferences:
 		return "lease_preferences"
+	case NumWitnesses:
+		return "num_witnesses"
+	case StorageCompression:
+		return "storage.compression"
+	case StorageValueSeparationMinSize:
+		return "storage.value_separation_min_size"
 	default:
 		return "Field(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
@@ -348,6 +348,10 @@ func (z *ZoneConfig) ValidateTandemFields() error {
 		return fmt.Errorf("when voter_constraints are set, num_voters must be set as well")
 	}
 
+	if z.NumWitnesses != nil && *z.NumWitnesses > 0 && !numVotersExplicit {
+		return fmt.Errorf("when num_witnesses is set, num_voters must be set as well")
+	}
+
 	if (z.RangeMinBytes != nil || z.RangeMaxBytes != nil) &&
 		(z.RangeMinBytes == nil || z.RangeMaxBytes == nil) {
 		return fmt.Errorf("range_min_bytes and range_max_bytes must be set together")
@@ -406,18 +410,37 @@ func (z *ZoneConfig) Validate() error {
 		}
 	}
 
+	var numWitnesses int32
+	if z.NumWitnesses != nil {
+		if *z.NumWitnesses < 0 {
+			return fmt.Errorf("num_witnesses cannot be negative")
+		}
+		numWitnesses = *z.NumWitnesses
+	}
+
 	var numVotersExplicit bool
 	if z.NumVoters != nil {
 		numVotersExplicit = true
 		switch {
 		case *z.NumVoters <= 0:
 			return fmt.Errorf("at least one voting replica is required")
-		case *z.NumVoters == 2:
+		case *z.NumVoters+numWitnesses == 2:
 			return fmt.Errorf("at least 3 voting replicas are required for multi-replica configurations")
 		}
-		if z.NumReplicas != nil && *z.NumVoters > *z.NumReplicas {
+		if z.NumReplicas != nil && *z.NumVoters+numWitnesses > *z.NumReplicas {
+			if numWitnesses > 0 {
+				return fmt.Errorf("num_voters plus num_witnesses cannot be greater than num_replicas")
+			}
 			return fmt.Errorf("num_voters cannot be greater than num_replicas")
 		}
+		// Every raft quorum must contain at least one replica holding data, or
+		// a write could be acknowledged by witnesses alone and then lost.
+		if quorum := (*z.NumVoters+numWitnesses)/2 + 1; numWitnesses >= quorum {
+			return fmt.Errorf("num_witnesses must be less than a quorum of the voting replicas " +
+				"(num_voters + num_witnesses)")
+		}
+	} else if numWitnesses > 0 && z.NumReplicas != nil && numWitnesses >= *z.NumReplicas {
+		return fmt.Errorf("num_witnesses must be less than num_replicas")
 	}
 
 	if z.RangeMaxBytes != nil && *z.RangeMaxBytes < minRangeMaxBytes {
@@ -439,6 +462,14 @@ func (z *ZoneConfig) Validate() error {
 			*z.RangeMinBytes, *z.RangeMaxBytes)
 	}
 
+	if z.StorageCompression != nil && !slices.Contains(roachpb.StorageCompressions, *z.StorageCompression) {
+		return fmt.Errorf("storage.compression must be one of %s",
+			strings.Join(roachpb.StorageCompressions, ", "))
+	}
+	if z.StorageValueSeparationMinSize != nil && *z.StorageValueSeparationMinSize < 0 {
+		return fmt.Errorf("storage.value_separation_min_size cannot be negative")
+	}
+
 	// Reserve the value 0 to potentially have some special meaning in the future,
 	// such as to disable GC.
 	if z.GC != nil && z.GC.TTLSeconds < 1 {
@@ -592,11 +623,26 @@ func (z *ZoneConfig) InheritFromParent(parent *ZoneConfig) {
 			z.NumVoters = proto.Int32(*parent.NumVoters)
 		}
 	}
+	if z.NumWitnesses == nil {
+		if parent.NumWitnesses != nil {
+			z.NumWitnesses = proto.Int32(*parent.NumWitnesses)
+		}
+	}
 	if z.GlobalReads == nil {
 		if parent.GlobalReads != nil {
 			z.GlobalReads = proto.Bool(*parent.GlobalReads)
 		}
 	}
+	if z.StorageCompression == nil {
+		if parent.StorageCompression != nil {
+			z.StorageCompression = proto.String(*parent.StorageCompression)
+		}
+	}
+	if z.StorageValueSeparationMinSize == nil {
+		if parent.StorageValueSeparationMinSize != nil {
+			z.StorageValueSeparationMinSize = proto.Int64(*parent.StorageValueSeparationMinSize)
+		}
+	}
 	if z.RangeMinBytes == nil {
 		if parent.RangeMinBytes != nil {
 			z.RangeMinBytes = proto.Int64(*parent.RangeMinBytes)
@@ -641,6 +687,11 @@ func (z *ZoneConfig) CopyFromZone(other ZoneConfig, fieldList []tree.Name) {
 			if other.NumVoters != nil {
 				z.NumVoters = proto.Int32(*other.NumVoters)
 			}
+		case "num_witnesses":
+			z.NumWitnesses = nil
+			if other.NumWitnesses != nil {
+				z.NumWitnesses = proto.Int32(*other.NumWitnesses)
+			}
 		case "range_min_bytes":
 			z.RangeMinBytes = nil
 			if other.RangeMinBytes != nil {
@@ -656,6 +707,16 @@ func (z *ZoneConfig) CopyFromZone(other ZoneConfig, fieldList []tree.Name) {
 			if other.GlobalReads != nil {
 				z.GlobalReads = proto.Bool(*other.GlobalReads)
 			}
+		case "storage.compression":
+			z.StorageCompression = nil
+			if other.StorageCompression != nil {
+				z.StorageCompression = proto.String(*other.StorageCompression)
+			}
+		case "storage.value_separation_min_size":
+			z.StorageValueSeparationMinSize = nil
+			if other.StorageValueSeparationMinSize != nil {
+				z.StorageValueSeparationMinSize = proto.Int64(*other.StorageValueSeparationMinSize)
+			}
 		case "gc.ttlseconds":
 			z.GC = nil
 			if other.GC != nil {
@@ -1448,6 +1509,15 @@ func (z *ZoneConfig) toSpanConfig() (roachpb.SpanConfig, error) {
 	if z.NumVoters != nil {
 		sc.NumVoters = *z.NumVoters
 	}
+	if z.NumWitnesses != nil {
+		sc.NumWitnesses = *z.NumWitnesses
+	}
+	if z.StorageCompression != nil && *z.StorageCompression != roachpb.StorageCompressionDefault {
+		sc.StorageCompression = *z.StorageCompression
+	}
+	if z.StorageValueSeparationMinSize != nil {
+		sc.StorageValueSeparationMinSize = *z.StorageValueSeparationMinSize
+	}
 
 	toSpanConfigConstraints := func(src []Constraint) ([]roachpb.Constraint, error) {
 		spanConfigConstraints := make([]roachpb.Constraint, len(src))
@@ -287,6 +287,97 @@ func TestZoneConfigValidateNonVoterSpecific(t *testing.T) {
 	}
 }
 
+func TestZoneConfigValidateWitnessSpecific(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		cfg      ZoneConfig
+		expected string
+	}{
+		{
+			cfg: ZoneConfig{
+				NumReplicas:  proto.Int32(3),
+				NumVoters:    proto.Int32(2),
+				NumWitnesses: proto.Int32(-1),
+			},
+			expected: "num_witnesses cannot be negative",
+		},
+		{
+			cfg: ZoneConfig{
+				NumReplicas:  proto.Int32(3),
+				NumVoters:    proto.Int32(1),
+				NumWitnesses: proto.Int32(1),
+			},
+			expected: "at least 3 voting replicas are required for multi-replica configurations",
+		},
+		{
+			cfg: ZoneConfig{
+				NumReplicas:  proto.Int32(3),
+				NumVoters:    proto.Int32(2),
+				NumWitnesses: proto.Int32(2),
+			},
+			expected: "num_voters plus num_witnesses cannot be greater than num_replicas",
+		},
+		{
+			cfg: ZoneConfig{
+				NumReplicas:  proto.Int32(5),
+				NumVoters:    proto.Int32(2),
+				NumWitnesses: proto.Int32(3),
+			},
+			expected: "num_witnesses must be less than a quorum of the voting replicas",
+		},
+		{
+			cfg: ZoneConfig{
+				NumReplicas:   proto.Int32(5),
+				NumVoters:     proto.Int32(4),
+				NumWitnesses:  proto.Int32(1),
+				RangeMaxBytes: DefaultZoneConfig().RangeMaxBytes,
+				GC:            &GCPolicy{TTLSeconds: 1},
+			},
+			expected: "",
+		},
+	}
+
+	for i, c := range testCases {
+		err := c.cfg.Validate()
+		if !testutils.IsError(err, c.expected) {
+			t.Errorf("%d: expected %q, got %v", i, c.expected, err)
+		}
+	}
+}
+
+func TestZoneConfigValidateStoragePolicy(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	valid := func(compression string, valueSeparationMinSize int64) ZoneConfig {
+		return ZoneConfig{
+			NumReplicas:                   proto.Int32(3),
+			RangeMaxBytes:                 DefaultZoneConfig().RangeMaxBytes,
+			GC:                            &GCPolicy{TTLSeconds: 1},
+			StorageCompression:            proto.String(compression),
+			StorageValueSeparationMinSize: proto.Int64(valueSeparationMinSize),
+		}
+	}
+	testCases := []struct {
+		cfg      ZoneConfig
+		expected string
+	}{
+		{cfg: valid("fast", 4096), expected: ""},
+		{cfg: valid("default", 0), expected: ""},
+		{cfg: valid("zstd", 0), expected: ""},
+		{cfg: valid("good", 0), expected: ""},
+		{cfg: valid("lz4", 0), expected: "storage.compression must be one of default, fast, fastest"},
+		{cfg: valid("fast", -1), expected: "storage.value_separation_min_size cannot be negative"},
+	}
+
+	for i, c := range testCases {
+		err := c.cfg.Validate()
+		if !testutils.IsError(err, c.expected) {
+			t.Errorf("%d: expected %q, got %v", i, c.expected, err)
+		}
+	}
+}
+
 func TestZoneConfigValidateTandemFields(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
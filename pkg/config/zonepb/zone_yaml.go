@@ -194,18 +194,21 @@ func (c *ConstraintsList) UnmarshalYAML(unmarshal func(interface{}) error) error
 //
 // TODO(a-robinson,v2.2): Remove the experimental_lease_preferences field.
 type marshalableZoneConfig struct {
-	RangeMinBytes                *int64            `json:"range_min_bytes" yaml:"range_min_bytes"`
-	RangeMaxBytes                *int64            `json:"range_max_bytes" yaml:"range_max_bytes"`
-	GC                           *GCPolicy         `json:"gc"`
-	GlobalReads                  *bool             `json:"global_reads" yaml:"global_reads"`
-	NumReplicas                  *int32            `json:"num_replicas" yaml:"num_replicas"`
-	NumVoters                    *int32            `json:"num_voters" yaml:"num_voters"`
-	Constraints                  ConstraintsList   `json:"constraints" yaml:"constraints,flow"`
-	VoterConstraints             ConstraintsList   `json:"voter_constraints" yaml:"voter_constraints,flow"`
-	LeasePreferences             []LeasePreference `json:"lease_preferences" yaml:"lease_preferences,flow"`
-	ExperimentalLeasePreferences []LeasePreference `json:"experimental_lease_preferences" yaml:"experimental_lease_preferences,flow,omitempty"`
-	Subzones                     []Subzone         `json:"subzones" yaml:"-"`
-	SubzoneSpans                 []SubzoneSpan     `json:"subzone_spans" yaml:"-"`
+	RangeMinBytes                 *int64            `json:"range_min_bytes" yaml:"range_min_bytes"`
+	RangeMaxBytes                 *int64            `json:"range_max_bytes" yaml:"range_max_bytes"`
+	GC                            *GCPolicy         `json:"gc"`
+	GlobalReads                   *bool             `json:"global_reads" yaml:"global_reads"`
+	NumReplicas                   *int32            `json:"num_replicas" yaml:"num_replicas"`
+	NumVoters                     *int32            `json:"num_voters" yaml:"num_voters"`
+	NumWitnesses                  *int32            `json:"num_witnesses,omitempty" yaml:"num_witnesses,omitempty"`
+	StorageCompression            *string           `json:"storage_compression,omitempty" yaml:"storage_compression,omitempty"`
+	StorageValueSeparationMinSize *int64            `json:"storage_value_separation_min_size,omitempty" yaml:"storage_value_separation_min_size,omitempty"`
+	Constraints                   ConstraintsList   `json:"constraints" yaml:"constraints,flow"`
+	VoterConstraints              ConstraintsList   `json:"voter_constraints" yaml:"voter_constraints,flow"`
+	LeasePreferences              []LeasePreference `json:"lease_preferences" yaml:"lease_preferences,flow"`
+	ExperimentalLeasePreferences  []LeasePreference `json:"experimental_lease_preferences" yaml:"experimental_lease_preferences,flow,omitempty"`
+	Subzones                      []Subzone         `json:"subzones" yaml:"-"`
+	SubzoneSpans                  []SubzoneSpan     `json:"subzone_spans" yaml:"-"`
 }
 
 func zoneConfigToMarshalable(c ZoneConfig) marshalableZoneConfig {
@@ -230,6 +233,15 @@ func zoneConfigToMarshalable(c ZoneConfig) marshalableZoneConfig {
 	if c.NumVoters != nil && *c.NumVoters != 0 {
 		m.NumVoters = proto.Int32(*c.NumVoters)
 	}
+	if c.NumWitnesses != nil && *c.NumWitnesses != 0 {
+		m.NumWitnesses = proto.Int32(*c.NumWitnesses)
+	}
+	if c.StorageCompression != nil {
+		m.StorageCompression = proto.String(*c.StorageCompression)
+	}
+	if c.StorageValueSeparationMinSize != nil {
+		m.StorageValueSeparationMinSize = proto.Int64(*c.StorageValueSeparationMinSize)
+	}
 	// NB: In order to preserve round-trippability, we're directly using
 	// `NullVoterConstraintsIsEmpty` as opposed to calling
 	// `c.InheritedVoterConstraints()`. This is copacetic as long as the value is
@@ -270,6 +282,15 @@ func zoneConfigFromMarshalable(m marshalableZoneConfig, c ZoneConfig) ZoneConfig
 	if m.NumVoters != nil {
 		c.NumVoters = proto.Int32(*m.NumVoters)
 	}
+	if m.NumWitnesses != nil {
+		c.NumWitnesses = proto.Int32(*m.NumWitnesses)
+	}
+	if m.StorageCompression != nil {
+		c.StorageCompression = proto.String(*m.StorageCompression)
+	}
+	if m.StorageValueSeparationMinSize != nil {
+		c.StorageValueSeparationMinSize = proto.Int64(*m.StorageValueSeparationMinSize)
+	}
 	c.VoterConstraints = m.VoterConstraints.Constraints
 	c.NullVoterConstraintsIsEmpty = !m.VoterConstraints.Inherited
 	if m.LeasePreferences != nil {
@@ -0,0 +1,344 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package logical
+
+import (
+	"context"
+
+	"github.com/cockroachdb/apd/v3"
+	"github.com/cockroachdb/cockroach/pkg/crosscluster/logical/ldrdecoder"
+	"github.com/cockroachdb/cockroach/pkg/crosscluster/logical/sqlwriter"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/arith"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+)
+
+type conflictPolicyKind int
+
+const (
+	// lastWriteWins applies the replicated change if it is newer than the local
+	// value of the row.
+	lastWriteWins conflictPolicyKind = iota
+	// sourceWins always applies the replicated change.
+	sourceWins
+	// destinationWins drops replicated changes to rows that were last written
+	// by a local transaction.
+	destinationWins
+	// columnMerge applies only the columns the replicated change modified,
+	// keeping the local value of every other column.
+	columnMerge
+	// counterMerge is a columnMerge that applies changes to the counter columns
+	// by adding the replicated delta to the local value.
+	counterMerge
+	// rejectConflicts sends replicated changes that conflict with a local change
+	// to the dead letter queue.
+	rejectConflicts
+)
+
+var conflictPolicyNames = map[string]conflictPolicyKind{
+	"lww":              lastWriteWins,
+	"source_wins":      sourceWins,
+	"destination_wins": destinationWins,
+	"column_merge":     columnMerge,
+	"counter":          counterMerge,
+	"dlq":              rejectConflicts,
+}
+
+// conflictPolicy decides how to apply a replicated change to a row that was
+// changed locally since the change's previous value was written, i.e. a
+// change whose previous value does not match the local row.
+type conflictPolicy struct {
+	kind conflictPolicyKind
+	// counterColumns are the names of the columns of a counter policy.
+	counterColumns []string
+}
+
+// parseConflictPolicy parses a policy given to the CONFLICT_POLICY option. A
+// policy is one of lww, source_wins, destination_wins, column_merge, dlq or
+// counter(col, ...). An empty policy is lww.
+func parseConflictPolicy(s string) (conflictPolicy, error) {
+	if s == "" {
+		return conflictPolicy{kind: lastWriteWins}, nil
+	}
+	invalid := func(cause error) error {
+		return pgerror.Wrapf(cause, pgcode.InvalidParameterValue, "invalid conflict policy %q", s)
+	}
+	expr, err := parser.ParseExpr(s)
+	if err != nil {
+		return conflictPolicy{}, invalid(err)
+	}
+	var name string
+	var args tree.Exprs
+	switch e := expr.(type) {
+	case *tree.UnresolvedName:
+		name = e.String()
+	case *tree.FuncExpr:
+		name = e.Func.String()
+		args = e.Exprs
+	default:
+		return conflictPolicy{}, invalid(errors.New("expected a policy name"))
+	}
+	kind, ok := conflictPolicyNames[name]
+	if !ok {
+		return conflictPolicy{}, invalid(errors.Newf("unknown policy %q", name))
+	}
+	p := conflictPolicy{kind: kind}
+	if kind != counterMerge {
+		if args != nil {
+			return conflictPolicy{}, invalid(errors.Newf("policy %q does not take arguments", name))
+		}
+		return p, nil
+	}
+	if len(args) == 0 {
+		return conflictPolicy{}, invalid(errors.New("counter requires at least one column"))
+	}
+	for _, arg := range args {
+		col, ok := arg.(*tree.UnresolvedName)
+		if !ok || col.NumParts != 1 {
+			return conflictPolicy{}, invalid(errors.Newf("expected a column name, got %s", arg))
+		}
+		p.counterColumns = append(p.counterColumns, col.Parts[0])
+	}
+	return p, nil
+}
+
+// boundConflictPolicy is a conflictPolicy bound to the columns of the
+// destination table it is applied to.
+type boundConflictPolicy struct {
+	kind conflictPolicyKind
+	// isCounter[i] is true if the i'th datum of a decoded row is a counter.
+	isCounter []bool
+}
+
+// bindConflictPolicy checks that the policy can be applied to the table.
+func bindConflictPolicy(
+	p conflictPolicy, table catalog.TableDescriptor,
+) (boundConflictPolicy, error) {
+	bound := boundConflictPolicy{kind: p.kind}
+	if p.kind != counterMerge {
+		return bound, nil
+	}
+	columns := sqlwriter.GetColumnSchema(table)
+	bound.isCounter = make([]bool, len(columns))
+	for _, name := range p.counterColumns {
+		found := false
+		for i, col := range columns {
+			if col.Column.GetName() != name {
+				continue
+			}
+			found = true
+			if col.IsPrimaryKey {
+				return boundConflictPolicy{}, pgerror.Newf(pgcode.InvalidParameterValue,
+					"counter column %q of table %q is part of the primary key", name, table.GetName())
+			}
+			switch col.ColumnType.Family() {
+			case types.IntFamily, types.FloatFamily, types.DecimalFamily:
+			default:
+				return boundConflictPolicy{}, pgerror.Newf(pgcode.InvalidParameterValue,
+					"counter column %q of table %q must be numeric, not %s", name, table.GetName(), col.ColumnType)
+			}
+			bound.isCounter[i] = true
+		}
+		if !found {
+			return boundConflictPolicy{}, pgerror.Newf(pgcode.UndefinedColumn,
+				"counter column %q does not exist in table %q", name, table.GetName())
+		}
+	}
+	return bound, nil
+}
+
+type conflictResolution int
+
+const (
+	// resolveWithLastWriteWins applies the change if it wins last-write-wins.
+	resolveWithLastWriteWins conflictResolution = iota
+	// resolveByApplying applies the resolved change.
+	resolveByApplying
+	// resolveByDropping drops the change.
+	resolveByDropping
+)
+
+// errConflictRejected marks changes rejected by the dlq conflict policy.
+var errConflictRejected = errors.New("conflict rejected by policy")
+
+// isConflictRejection returns true if the error is a change rejected by the
+// dlq conflict policy.
+func isConflictRejection(err error) bool {
+	return errors.Is(err, errConflictRejected)
+}
+
+// resolveConflict decides how to apply a replicated change whose previous
+// value does not match the local row. If the change should be applied, the
+// returned row replaces it.
+func (p *boundConflictPolicy) resolveConflict(
+	ctx context.Context,
+	cmpCtx tree.CompareContext,
+	event ldrdecoder.DecodedRow,
+	local sqlwriter.PriorRow,
+	found bool,
+) (ldrdecoder.DecodedRow, conflictResolution, error) {
+	var localRow tree.Datums
+	if found {
+		localRow = local.Row
+	}
+	resolved := ldrdecoder.DecodedRow{
+		TableID:      event.TableID,
+		IsDelete:     event.IsDelete,
+		Row:          event.Row,
+		RowTimestamp: overridingTimestamp(event.RowTimestamp, local, found),
+		PrevRow:      localRow,
+	}
+
+	switch p.kind {
+	case lastWriteWins:
+		return ldrdecoder.DecodedRow{}, resolveWithLastWriteWins, nil
+	case sourceWins:
+		return resolved, resolveByApplying, nil
+	case destinationWins:
+		if found && local.IsLocal {
+			return ldrdecoder.DecodedRow{}, resolveByDropping, nil
+		}
+		return ldrdecoder.DecodedRow{}, resolveWithLastWriteWins, nil
+	case columnMerge, counterMerge:
+		if event.IsDelete || !found {
+			return ldrdecoder.DecodedRow{}, resolveWithLastWriteWins, nil
+		}
+		merged, err := p.mergeRow(ctx, cmpCtx, event, localRow)
+		if err != nil {
+			return ldrdecoder.DecodedRow{}, 0, err
+		}
+		resolved.Row = merged
+		return resolved, resolveByApplying, nil
+	case rejectConflicts:
+		return ldrdecoder.DecodedRow{}, 0, pgerror.WithCandidateCode(errors.Mark(errors.Newf(
+			"replicated change at %s conflicts with the local value of the row", event.RowTimestamp),
+			errConflictRejected), pgcode.IntegrityConstraintViolation)
+	default:
+		return ldrdecoder.DecodedRow{}, 0, errors.AssertionFailedf("unknown conflict policy %d", p.kind)
+	}
+}
+
+// overridingTimestamp returns the origin timestamp used to write a change
+// that overrides the local value of the row. The timestamp is after the
+// local row's so that the write is not rejected as a last-write-wins loser.
+func overridingTimestamp(ts hlc.Timestamp, local sqlwriter.PriorRow, found bool) hlc.Timestamp {
+	if found && !local.LogicalTimestamp.Less(ts) {
+		return local.LogicalTimestamp.Next()
+	}
+	return ts
+}
+
+// mergeRow returns the local row with the columns modified by the replicated
+// change replaced. A change without a previous value modifies every column.
+// Counter columns are incremented by the replicated delta instead.
+func (p *boundConflictPolicy) mergeRow(
+	ctx context.Context, cmpCtx tree.CompareContext, event ldrdecoder.DecodedRow, localRow tree.Datums,
+) (tree.Datums, error) {
+	merged := make(tree.Datums, len(localRow))
+	copy(merged, localRow)
+	for i := range merged {
+		var prev tree.Datum = tree.DNull
+		if event.PrevRow != nil {
+			prev = event.PrevRow[i]
+		}
+		if p.isCounter != nil && p.isCounter[i] {
+			sum, err := addCounterDelta(localRow[i], event.Row[i], prev)
+			if err != nil {
+				return nil, err
+			}
+			merged[i] = sum
+			continue
+		}
+		if event.PrevRow == nil || !datumsEqual(ctx, cmpCtx, prev, event.Row[i]) {
+			merged[i] = event.Row[i]
+		}
+	}
+	return merged, nil
+}
+
+// datumsEqual returns true if the datums have the same type and value. It
+// is used to find the columns modified by a replicated change, which are
+// decoded with the same types.
+func datumsEqual(ctx context.Context, cmpCtx tree.CompareContext, a, b tree.Datum) bool {
+	if a == tree.DNull || b == tree.DNull {
+		return a == b
+	}
+	if !a.ResolvedType().Identical(b.ResolvedType()) {
+		return false
+	}
+	cmp, err := a.Compare(ctx, cmpCtx, b)
+	return err == nil && cmp == 0
+}
+
+// rowsEqual returns true if both rows are absent or have equal datums.
+func rowsEqual(ctx context.Context, cmpCtx tree.CompareContext, a, b tree.Datums) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !datumsEqual(ctx, cmpCtx, a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// addCounterDelta returns local + (next - prev), treating NULL as zero.
+func addCounterDelta(local, next, prev tree.Datum) (tree.Datum, error) {
+	if next == tree.DNull {
+		// A NULL counter leaves the local value unchanged.
+		return local, nil
+	}
+	switch next.(type) {
+	case *tree.DInt:
+		asInt := func(d tree.Datum) int64 {
+			if i, ok := d.(*tree.DInt); ok {
+				return int64(*i)
+			}
+			return 0
+		}
+		delta, ok := arith.SubWithOverflow(asInt(next), asInt(prev))
+		if !ok {
+			return nil, tree.ErrIntOutOfRange
+		}
+		sum, ok := arith.AddWithOverflow(asInt(local), delta)
+		if !ok {
+			return nil, tree.ErrIntOutOfRange
+		}
+		return tree.NewDInt(tree.DInt(sum)), nil
+	case *tree.DFloat:
+		asFloat := func(d tree.Datum) float64 {
+			if f, ok := d.(*tree.DFloat); ok {
+				return float64(*f)
+			}
+			return 0
+		}
+		return tree.NewDFloat(tree.DFloat(asFloat(local) + asFloat(next) - asFloat(prev))), nil
+	case *tree.DDecimal:
+		asDecimal := func(d tree.Datum) *apd.Decimal {
+			if dec, ok := d.(*tree.DDecimal); ok {
+				return &dec.Decimal
+			}
+			return apd.New(0, 0)
+		}
+		sum := &tree.DDecimal{}
+		if _, err := tree.ExactCtx.Sub(&sum.Decimal, asDecimal(next), asDecimal(prev)); err != nil {
+			return nil, err
+		}
+		if _, err := tree.ExactCtx.Add(&sum.Decimal, &sum.Decimal, asDecimal(local)); err != nil {
+			return nil, err
+		}
+		return sum, nil
+	default:
+		return nil, errors.AssertionFailedf("unexpected counter datum %T", next)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package logical
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConflictPolicy(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		policy   string
+		expected conflictPolicy
+		err      string
+	}{
+		{policy: "", expected: conflictPolicy{kind: lastWriteWins}},
+		{policy: "lww", expected: conflictPolicy{kind: lastWriteWins}},
+		{policy: "source_wins", expected: conflictPolicy{kind: sourceWins}},
+		{policy: "destination_wins", expected: conflictPolicy{kind: destinationWins}},
+		{policy: "column_merge", expected: conflictPolicy{kind: columnMerge}},
+		{policy: "dlq", expected: conflictPolicy{kind: rejectConflicts}},
+		{
+			policy:   "counter(hits, total)",
+			expected: conflictPolicy{kind: counterMerge, counterColumns: []string{"hits", "total"}},
+		},
+		{policy: "counter", err: "counter requires at least one column"},
+		{policy: "counter(t.hits)", err: "expected a column name"},
+		{policy: "dlq(hits)", err: `policy "dlq" does not take arguments`},
+		{policy: "first_write_wins", err: `unknown policy "first_write_wins"`},
+		{policy: "1 + 1", err: "expected a policy name"},
+	} {
+		t.Run(tc.policy, func(t *testing.T) {
+			p, err := parseConflictPolicy(tc.policy)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, p)
+		})
+	}
+}
+
+func TestAddCounterDelta(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	sum, err := addCounterDelta(tree.NewDInt(15), tree.NewDInt(13), tree.NewDInt(10))
+	require.NoError(t, err)
+	require.Equal(t, tree.NewDInt(18), sum)
+
+	// A NULL previous value counts as zero.
+	sum, err = addCounterDelta(tree.NewDInt(15), tree.NewDInt(3), tree.DNull)
+	require.NoError(t, err)
+	require.Equal(t, tree.NewDInt(18), sum)
+
+	// A NULL replicated value leaves the local counter unchanged.
+	sum, err = addCounterDelta(tree.NewDInt(15), tree.DNull, tree.NewDInt(10))
+	require.NoError(t, err)
+	require.Equal(t, tree.NewDInt(15), sum)
+
+	_, err = addCounterDelta(tree.NewDInt(tree.DInt(1<<62)), tree.NewDInt(tree.DInt(1<<62)), tree.DNull)
+	require.ErrorIs(t, err, tree.ErrIntOutOfRange)
+}
@@ -107,6 +107,10 @@ func createLogicalReplicationStreamPlanHook(
 		}
 
 		hasUDF := len(options.userFunctions) > 0 || options.defaultFunction != nil && options.defaultFunction.FunctionId != 0
+		hasConflictPolicy := options.defaultConflictPolicy != "" || len(options.conflictPolicies) > 0
+		if hasUDF && hasConflictPolicy {
+			return pgerror.Newf(pgcode.InvalidParameterValue, "CONFLICT_POLICY cannot be used with user-defined functions")
+		}
 
 		if hasUDF && !crosscluster.LogicalReplicationUDFWriterEnabled.Get(&p.ExecCfg().Settings.SV) {
 			return pgerror.Newf(pgcode.FeatureNotSupported,
@@ -121,15 +125,22 @@ func createLogicalReplicationStreamPlanHook(
 				if hasUDF {
 					return pgerror.Newf(pgcode.InvalidParameterValue, "MODE = 'immediate' cannot be used with user-defined functions")
 				}
+				if hasConflictPolicy {
+					return pgerror.Newf(pgcode.InvalidParameterValue, "MODE = 'immediate' cannot be used with CONFLICT_POLICY")
+				}
 			case "validated":
 				mode = jobspb.LogicalReplicationDetails_Validated
 			case "transactional":
+				if hasConflictPolicy {
+					return pgerror.Newf(pgcode.InvalidParameterValue, "MODE = 'transactional' cannot be used with CONFLICT_POLICY")
+				}
 				mode = jobspb.LogicalReplicationDetails_Transactional
 			default:
 				return pgerror.Newf(pgcode.InvalidParameterValue, "unknown mode %q", m)
 			}
-		} else if hasUDF {
-			// UDFs imply applying changes via SQL, which implies validation.
+		} else if hasUDF || hasConflictPolicy {
+			// UDFs and conflict policies imply applying changes via SQL, which
+			// implies validation.
 			mode = jobspb.LogicalReplicationDetails_Validated
 		}
 
@@ -275,6 +286,12 @@ func createLogicalReplicationStreamPlanHook(
 				repPairs[i].DstFunctionID = uf[name]
 			}
 		}
+		for i, name := range srcTableNames {
+			repPairs[i].ConflictPolicy = options.defaultConflictPolicy
+			if policy, ok := options.conflictPolicies[name]; ok {
+				repPairs[i].ConflictPolicy = policy
+			}
+		}
 		if throwNoTTLWithCDCIgnoreError {
 			return pgerror.Newf(pgcode.InvalidParameterValue, "DISCARD = 'ttl-deletes' specified but no tables have changefeed-excluded TTLs")
 		}
@@ -485,6 +502,19 @@ func doLDRPlan(
 
 		for i := range srcExternalCatalog.Tables {
 			destTableDesc := dstTableDescs[i]
+			if policy := details.ReplicationPairs[i].ConflictPolicy; policy != "" {
+				if writer != sqlclustersettings.LDRWriterTypeCRUD {
+					return pgerror.Newf(pgcode.InvalidParameterValue,
+						"CONFLICT_POLICY is not supported by the %s writer", writer)
+				}
+				parsed, err := parseConflictPolicy(policy)
+				if err != nil {
+					return err
+				}
+				if _, err := bindConflictPolicy(parsed, destTableDesc); err != nil {
+					return err
+				}
+			}
 			if details.Mode == jobspb.LogicalReplicationDetails_Immediate {
 				if len(destTableDesc.OutboundForeignKeys()) > 0 || len(destTableDesc.InboundForeignKeys()) > 0 {
 					return pgerror.Newf(pgcode.InvalidParameterValue, "foreign keys are not supported with MODE = 'immediate'")
@@ -524,6 +554,7 @@ func createLogicalReplicationStreamTypeCheck(
 			stmt.Options.Discard,
 			stmt.Options.BidirectionalURI,
 			stmt.Options.ParentID,
+			stmt.Options.DefaultConflictPolicy,
 		},
 		exprutil.Ints{stmt.Options.ParentID},
 		exprutil.Bools{
@@ -532,6 +563,11 @@ func createLogicalReplicationStreamTypeCheck(
 			stmt.Options.Unidirectional,
 		},
 	}
+	conflictPolicies := make(exprutil.Strings, 0, len(stmt.Options.ConflictPolicies))
+	for _, policy := range stmt.Options.ConflictPolicies {
+		conflictPolicies = append(conflictPolicies, policy)
+	}
+	toTypeCheck = append(toTypeCheck, conflictPolicies)
 	if err := exprutil.TypeCheck(ctx, "LOGICAL REPLICATION STREAM", p.SemaCtx(),
 		toTypeCheck...,
 	); err != nil {
@@ -553,6 +589,10 @@ type resolvedLogicalReplicationOptions struct {
 	metricsLabel     string
 	bidirectionalURI string
 	ParentID         catpb.JobID
+	// defaultConflictPolicy and conflictPolicies, keyed by table name, are
+	// validated but unbound conflict policies.
+	defaultConflictPolicy string
+	conflictPolicies      map[string]string
 }
 
 func evalLogicalReplicationOptions(
@@ -635,6 +675,34 @@ func evalLogicalReplicationOptions(
 		}
 	}
 
+	if options.DefaultConflictPolicy != nil {
+		policy, err := eval.String(ctx, options.DefaultConflictPolicy)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := parseConflictPolicy(policy); err != nil {
+			return nil, err
+		}
+		r.defaultConflictPolicy = policy
+	}
+	if options.ConflictPolicies != nil {
+		r.conflictPolicies = make(map[string]string)
+		for tb, expr := range options.ConflictPolicies {
+			objName, err := tb.ToUnresolvedObjectName(tree.NoAnnotation)
+			if err != nil {
+				return nil, err
+			}
+			policy, err := eval.String(ctx, expr)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := parseConflictPolicy(policy); err != nil {
+				return nil, err
+			}
+			r.conflictPolicies[objName.String()] = policy
+		}
+	}
+
 	if options.Discard != nil {
 		discard, err := eval.String(ctx, options.Discard)
 		if err != nil {
@@ -226,3 +226,32 @@ func TestUDFLogicalReplicationDisabled(t *testing.T) {
 		"CREATE LOGICAL REPLICATION STREAM FROM TABLE test_tab ON $1 INTO TABLE test_tab WITH FUNCTION repl_apply FOR TABLE test_tab",
 		dbAURL.String())
 }
+
+func TestConflictPolicyValidation(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc, s, runnerA, runnerB := setupLogicalTestServer(t, ctx, testClusterBaseClusterArgs, 1)
+	defer tc.Stopper().Stop(ctx)
+
+	runnerA.Exec(t, "CREATE TABLE test_tab(pk INT PRIMARY KEY, v INT, s STRING)")
+	runnerB.Exec(t, "CREATE TABLE test_tab(pk INT PRIMARY KEY, v INT, s STRING)")
+
+	dbAURL := replicationtestutils.GetExternalConnectionURI(t, s, s, serverutils.DBName("a"))
+
+	for _, tc := range []struct {
+		options string
+		err     string
+	}{
+		{options: "CONFLICT_POLICY = 'first_write_wins'", err: `unknown policy "first_write_wins"`},
+		{options: "CONFLICT_POLICY = 'counter(s)'", err: `counter column "s" of table "test_tab" must be numeric`},
+		{options: "CONFLICT_POLICY = 'counter(pk)'", err: `counter column "pk" of table "test_tab" is part of the primary key`},
+		{options: "CONFLICT_POLICY = 'counter(missing)'", err: `counter column "missing" does not exist`},
+		{options: "CONFLICT_POLICY = 'dlq', MODE = 'immediate'", err: "MODE = 'immediate' cannot be used with CONFLICT_POLICY"},
+	} {
+		runnerB.ExpectErr(t, tc.err,
+			"CREATE LOGICAL REPLICATION STREAM FROM TABLE test_tab ON $1 INTO TABLE test_tab WITH "+tc.options,
+			dbAURL.String())
+	}
+}
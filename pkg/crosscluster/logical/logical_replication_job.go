@@ -594,6 +594,10 @@ func (p *logicalReplicationPlanner) generatePlanImpl(
 			if err := tabledesc.CheckLogicalReplicationCompatibility(&srcTableDesc, dstTableDesc.TableDesc(), payload.SkipSchemaCheck || payload.CreateTable, writer == sqlclustersettings.LDRWriterTypeLegacyKV); err != nil {
 				return err
 			}
+			if pair.ConflictPolicy != "" && writer != sqlclustersettings.LDRWriterTypeCRUD {
+				return errors.Newf("conflict policy of table %q is not supported by the %s writer",
+					dstTableDesc.GetName(), writer)
+			}
 
 			var fnOID oid.Oid
 			if pair.DstFunctionID != 0 {
@@ -608,6 +612,7 @@ func (p *logicalReplicationPlanner) generatePlanImpl(
 				DestinationParentSchemaName:   scDesc.GetName(),
 				DestinationTableName:          dstTableDesc.GetName(),
 				DestinationFunctionOID:        uint32(fnOID),
+				ConflictPolicy:                pair.ConflictPolicy,
 			}
 			info.destTableBySrcID[descpb.ID(pair.SrcDescriptorID)] = dstTableMetadata{
 				database: dbDesc.GetName(),
@@ -193,9 +193,14 @@ func newLogicalReplicationWriterProcessor(
 		if err := typedesc.HydrateTypesInDescriptor(ctx, srcDesc, crossClusterResolver); err != nil {
 			return nil, err
 		}
+		policy, err := parseConflictPolicy(md.ConflictPolicy)
+		if err != nil {
+			return nil, err
+		}
 		procConfigByDestTableID[descpb.ID(dstTableID)] = sqlProcessorTableConfig{
-			srcDesc: srcDesc,
-			dstOID:  md.DestinationFunctionOID,
+			srcDesc:        srcDesc,
+			dstOID:         md.DestinationFunctionOID,
+			conflictPolicy: policy,
 		}
 
 		destTableBySrcID[md.SourceDescriptor.GetID()] = dstTableMetadata{
@@ -922,6 +927,7 @@ func (lrw *logicalReplicationWriterProcessor) flushBuffer(
 
 	lrw.metrics.KVUpdateTooOld.Inc(stats.kvWriteTooOld)
 	lrw.metrics.KVValueRefreshes.Inc(stats.kvWriteValueRefreshes)
+	lrw.metrics.Conflicts.Inc(stats.conflicts)
 	lrw.metrics.AppliedRowUpdates.Inc(stats.processed.success)
 	lrw.metrics.DLQedRowUpdates.Inc(stats.processed.dlq)
 	if l := lrw.spec.MetricsLabel; l != "" {
@@ -949,6 +955,7 @@ const (
 	noSpace
 	tooOld
 	errType
+	conflictRejected
 )
 
 func (r retryEligibility) String() string {
@@ -961,6 +968,8 @@ func (r retryEligibility) String() string {
 		return "age limit"
 	case errType:
 		return "not retryable"
+	case conflictRejected:
+		return "conflict policy"
 	}
 	return "unknown"
 }
@@ -1093,6 +1102,12 @@ func (lrw *logicalReplicationWriterProcessor) flushChunk(
 func (lrw *logicalReplicationWriterProcessor) shouldRetryLater(
 	err error, eligibility retryEligibility,
 ) retryEligibility {
+	// Changes rejected by a conflict policy will be rejected again, so they go
+	// straight to the DLQ.
+	if isConflictRejection(err) {
+		return conflictRejected
+	}
+
 	if eligibility != retryAllowed {
 		return eligibility
 	}
@@ -1138,6 +1153,8 @@ func (lrw *logicalReplicationWriterProcessor) maybeDLQ(
 		lrw.metrics.DLQedDueToQueueSpace.Inc(1)
 	case errType:
 		lrw.metrics.DLQedDueToErrType.Inc(1)
+	case conflictRejected:
+		lrw.metrics.DLQedDueToConflict.Inc(1)
 	}
 	return lrw.dlqClient.Log(ctx, lrw.spec.JobID, event, row, applyErr, eligibility)
 }
@@ -1146,12 +1163,14 @@ type batchStats struct {
 	optimisticInsertConflicts int64
 	kvWriteTooOld             int64
 	kvWriteValueRefreshes     int64
+	conflicts                 int64
 }
 
 func (b *batchStats) Add(o batchStats) {
 	b.optimisticInsertConflicts += o.optimisticInsertConflicts
 	b.kvWriteTooOld += o.kvWriteTooOld
 	b.kvWriteValueRefreshes += o.kvWriteValueRefreshes
+	b.conflicts += o.conflicts
 }
 
 type flushStats struct {
@@ -224,6 +224,8 @@ func (q *queryBuffer) ApplierQueryForRow(row cdcevent.Row) (queryBuilder, error)
 type sqlProcessorTableConfig struct {
 	srcDesc catalog.TableDescriptor
 	dstOID  uint32
+	// conflictPolicy is only supported by the crud writer.
+	conflictPolicy conflictPolicy
 }
 
 func makeSQLProcessorFromQuerier(
@@ -154,6 +154,22 @@ var (
 			metric.LabelType, "dlqed_errtype",
 		),
 	}
+	metaDLQedDueToConflict = metric.Metadata{
+		Name:        "logical_replication.events_dlqed_conflict",
+		Help:        "Row update events sent to DLQ because the table's conflict policy rejected them",
+		Measurement: "Failures",
+		Unit:        metric.Unit_COUNT,
+		LabeledName: "logical_replication.events",
+		StaticLabels: metric.MakeLabelPairs(
+			metric.LabelType, "dlqed_conflict",
+		),
+	}
+	metaConflicts = metric.Metadata{
+		Name:        "logical_replication.conflicts",
+		Help:        "Row update events whose previous value did not match the local value of the row",
+		Measurement: "Events",
+		Unit:        metric.Unit_COUNT,
+	}
 
 	// Internal metrics.
 	metaCheckpointEvents = metric.Metadata{
@@ -246,6 +262,8 @@ type Metrics struct {
 	DLQedDueToAge        *metric.Counter
 	DLQedDueToQueueSpace *metric.Counter
 	DLQedDueToErrType    *metric.Counter
+	DLQedDueToConflict   *metric.Counter
+	Conflicts            *metric.Counter
 
 	InitialApplySuccesses *metric.Counter
 	InitialApplyFailures  *metric.Counter
@@ -297,6 +315,8 @@ func MakeMetrics(histogramWindow time.Duration) metric.Struct {
 		DLQedDueToAge:        metric.NewCounter(metaDLQedDueToAge),
 		DLQedDueToQueueSpace: metric.NewCounter(metaDLQedDueToQueueSpace),
 		DLQedDueToErrType:    metric.NewCounter(metaDLQedDueToErrType),
+		DLQedDueToConflict:   metric.NewCounter(metaDLQedDueToConflict),
+		Conflicts:            metric.NewCounter(metaConflicts),
 
 		InitialApplySuccesses: metric.NewCounter(metaInitialApplySuccess),
 		InitialApplyFailures:  metric.NewCounter(metaInitialApplyFailures),
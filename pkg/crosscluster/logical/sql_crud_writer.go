@@ -80,6 +80,8 @@ func newCrudSqlWriter(
 			jobID,
 			cfg.LeaseManager.(*lease.Manager),
 			evalCtx.Settings,
+			procConfigByDestID[dstDescID].conflictPolicy,
+			evalCtx,
 		)
 		if err != nil {
 			return nil, err
@@ -32,6 +32,8 @@ type tableHandler struct {
 	session          isql.Session
 	db               descs.DB
 	tombstoneUpdater *tombstoneUpdater
+	conflictPolicy   boundConflictPolicy
+	cmpCtx           tree.CompareContext
 }
 
 type tableBatchStats struct {
@@ -55,6 +57,12 @@ type tableBatchStats struct {
 	// attempting to write to the KV layer. This case only occurs if there is a
 	// tombstone that is more recent than the replicated action.
 	kvLwwLosers int64
+	// conflicts is the number of rows whose previous value did not match the
+	// local row during the read refresh.
+	conflicts int64
+	// conflictLosers is the number of conflicting rows that were dropped by
+	// the table's conflict policy.
+	conflictLosers int64
 }
 
 func (t *tableBatchStats) Add(o tableBatchStats) {
@@ -65,6 +73,8 @@ func (t *tableBatchStats) Add(o tableBatchStats) {
 	t.tombstoneUpdates += o.tombstoneUpdates
 	t.refreshLwwLosers += o.refreshLwwLosers
 	t.kvLwwLosers += o.kvLwwLosers
+	t.conflicts += o.conflicts
+	t.conflictLosers += o.conflictLosers
 }
 
 func (t *tableBatchStats) AddTo(bs *batchStats) {
@@ -75,6 +85,7 @@ func (t *tableBatchStats) AddTo(bs *batchStats) {
 	if t.refreshedRows != 0 {
 		bs.kvWriteValueRefreshes += 1
 	}
+	bs.conflicts += t.conflicts
 }
 
 // newTableHandler creates a new tableHandler for the given table descriptor ID.
@@ -88,6 +99,8 @@ func newTableHandler(
 	jobID jobspb.JobID,
 	leaseMgr *lease.Manager,
 	settings *cluster.Settings,
+	policy conflictPolicy,
+	cmpCtx tree.CompareContext,
 ) (_ *tableHandler, err error) {
 	var table catalog.TableDescriptor
 	session, err := sqlwriter.NewInternalSession(ctx, db, sd, settings)
@@ -127,6 +140,11 @@ func newTableHandler(
 		return nil, err
 	}
 
+	boundPolicy, err := bindConflictPolicy(policy, table)
+	if err != nil {
+		return nil, err
+	}
+
 	tombstoneUpdater := newTombstoneUpdater(codec, db.KV(), leaseMgr, tableID, sd, settings)
 
 	return &tableHandler{
@@ -135,6 +153,8 @@ func newTableHandler(
 		db:               db,
 		tombstoneUpdater: tombstoneUpdater,
 		session:          session,
+		conflictPolicy:   boundPolicy,
+		cmpCtx:           cmpCtx,
 	}, nil
 }
 
@@ -239,7 +259,8 @@ func (t *tableHandler) attemptBatch(
 
 // refreshPrevRows refreshes the prevRow field for each event in the batch. If
 // any event is known to be a lww loser based on the read, its dropped from the
-// batch.
+// batch. Events whose previous value does not match the local row conflict
+// with a local change and are resolved by the table's conflict policy.
 func (t *tableHandler) refreshPrevRows(
 	ctx context.Context, batch []ldrdecoder.DecodedRow,
 ) ([]ldrdecoder.DecodedRow, tableBatchStats, error) {
@@ -258,8 +279,29 @@ func (t *tableHandler) refreshPrevRows(
 
 	refreshedBatch := make([]ldrdecoder.DecodedRow, 0, len(batch))
 	for i, event := range batch {
+		refreshed, found := refreshedRows[i]
+		var localRow tree.Datums
+		if found {
+			localRow = refreshed.Row
+		}
+		if !rowsEqual(ctx, t.cmpCtx, localRow, event.PrevRow) {
+			stats.conflicts++
+			resolved, resolution, err := t.conflictPolicy.resolveConflict(ctx, t.cmpCtx, event, refreshed, found)
+			if err != nil {
+				return nil, tableBatchStats{}, err
+			}
+			switch resolution {
+			case resolveByApplying:
+				refreshedBatch = append(refreshedBatch, resolved)
+				continue
+			case resolveByDropping:
+				stats.conflictLosers++
+				continue
+			}
+		}
+
 		var prevRow tree.Datums
-		if refreshed, found := refreshedRows[i]; found {
+		if found {
 			if !refreshed.LogicalTimestamp.Less(event.RowTimestamp) {
 				// TODO(jeffswenson): update this logic when its time to handle
 				// ties.
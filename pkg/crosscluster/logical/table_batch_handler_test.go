@@ -29,8 +29,16 @@ import (
 
 func newCrudBatchHandler(
 	t *testing.T, s serverutils.ApplicationLayerInterface, tableName string,
+) (BatchHandler, catalog.TableDescriptor) {
+	return newCrudBatchHandlerWithPolicy(t, s, tableName, "" /* policy */)
+}
+
+func newCrudBatchHandlerWithPolicy(
+	t *testing.T, s serverutils.ApplicationLayerInterface, tableName string, policy string,
 ) (BatchHandler, catalog.TableDescriptor) {
 	ctx := context.Background()
+	conflictPolicy, err := parseConflictPolicy(policy)
+	require.NoError(t, err)
 	desc := cdctest.GetHydratedTableDescriptor(t, s.ExecutorConfig(), tree.Name(tableName))
 	sd := sql.NewInternalSessionData(ctx, s.ClusterSettings(), "" /* opName */)
 
@@ -48,7 +56,8 @@ func newCrudBatchHandler(
 		jobspb.LogicalReplicationDetails_DiscardNothing,
 		map[descpb.ID]sqlProcessorTableConfig{
 			desc.GetID(): {
-				srcDesc: desc,
+				srcDesc:        desc,
+				conflictPolicy: conflictPolicy,
 			},
 		},
 		0, // jobID
@@ -245,3 +254,91 @@ func TestBatchHandlerDuplicateBatchEntries(t *testing.T) {
 		{"2", "newer-update"},
 	})
 }
+
+func TestBatchHandlerConflictPolicy(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+
+	defer srv.Stopper().Stop(ctx)
+	s := srv.ApplicationLayer()
+	runner := sqlutils.MakeSQLRunner(sqlDB)
+
+	row := func(id int, name string, hits int) []tree.Datum {
+		return []tree.Datum{tree.NewDInt(tree.DInt(id)), tree.NewDString(name), tree.NewDInt(tree.DInt(hits))}
+	}
+
+	t.Run("counter", func(t *testing.T) {
+		runner.Exec(t, `CREATE TABLE counters (id INT PRIMARY KEY, name STRING, hits INT)`)
+		handler, desc := newCrudBatchHandlerWithPolicy(t, s, "counters", "counter(hits)")
+		defer handler.Close(ctx)
+		defer handler.ReleaseLeases(ctx)
+		eb := ldrdecoder.NewTestEventBuilder(t, desc.TableDesc())
+
+		// Both clusters started from (1, 'a', 10). The local cluster added 5
+		// hits and the source cluster added 3 hits and renamed the row.
+		runner.Exec(t, `INSERT INTO counters VALUES (1, 'a', 15)`)
+		stats, err := handler.HandleBatch(ctx, []streampb.StreamEvent_KV{
+			eb.UpdateEvent(s.Clock().Now(), row(1, "b", 13), row(1, "a", 10)),
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), stats.conflicts)
+		runner.CheckQueryResults(t, `SELECT id, name, hits FROM counters`, [][]string{
+			{"1", "b", "18"},
+		})
+	})
+
+	t.Run("column merge", func(t *testing.T) {
+		runner.Exec(t, `CREATE TABLE merged (id INT PRIMARY KEY, name STRING, hits INT)`)
+		handler, desc := newCrudBatchHandlerWithPolicy(t, s, "merged", "column_merge")
+		defer handler.Close(ctx)
+		defer handler.ReleaseLeases(ctx)
+		eb := ldrdecoder.NewTestEventBuilder(t, desc.TableDesc())
+
+		// The local change to hits is kept because the source did not change it.
+		runner.Exec(t, `INSERT INTO merged VALUES (1, 'a', 15)`)
+		_, err := handler.HandleBatch(ctx, []streampb.StreamEvent_KV{
+			eb.UpdateEvent(s.Clock().Now(), row(1, "b", 10), row(1, "a", 10)),
+		})
+		require.NoError(t, err)
+		runner.CheckQueryResults(t, `SELECT id, name, hits FROM merged`, [][]string{
+			{"1", "b", "15"},
+		})
+	})
+
+	t.Run("destination wins", func(t *testing.T) {
+		runner.Exec(t, `CREATE TABLE local_wins (id INT PRIMARY KEY, name STRING, hits INT)`)
+		handler, desc := newCrudBatchHandlerWithPolicy(t, s, "local_wins", "destination_wins")
+		defer handler.Close(ctx)
+		defer handler.ReleaseLeases(ctx)
+		eb := ldrdecoder.NewTestEventBuilder(t, desc.TableDesc())
+
+		runner.Exec(t, `INSERT INTO local_wins VALUES (1, 'local', 1)`)
+		_, err := handler.HandleBatch(ctx, []streampb.StreamEvent_KV{
+			eb.UpdateEvent(s.Clock().Now(), row(1, "source", 2), row(1, "a", 1)),
+		})
+		require.NoError(t, err)
+		runner.CheckQueryResults(t, `SELECT id, name, hits FROM local_wins`, [][]string{
+			{"1", "local", "1"},
+		})
+	})
+
+	t.Run("dlq", func(t *testing.T) {
+		runner.Exec(t, `CREATE TABLE rejected (id INT PRIMARY KEY, name STRING, hits INT)`)
+		handler, desc := newCrudBatchHandlerWithPolicy(t, s, "rejected", "dlq")
+		defer handler.Close(ctx)
+		defer handler.ReleaseLeases(ctx)
+		eb := ldrdecoder.NewTestEventBuilder(t, desc.TableDesc())
+
+		runner.Exec(t, `INSERT INTO rejected VALUES (1, 'local', 1)`)
+		_, err := handler.HandleBatch(ctx, []streampb.StreamEvent_KV{
+			eb.UpdateEvent(s.Clock().Now(), row(1, "source", 2), row(1, "a", 1)),
+		})
+		require.True(t, isConflictRejection(err), "%+v", err)
+		runner.CheckQueryResults(t, `SELECT id, name, hits FROM rejected`, [][]string{
+			{"1", "local", "1"},
+		})
+	})
+}
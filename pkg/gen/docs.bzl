@@ -130,6 +130,7 @@ DOCS_SRCS = [
     "//docs/generated/sql/bnf:create_role_stmt.bnf",
     "//docs/generated/sql/bnf:create_schedule_for_backup_stmt.bnf",
     "//docs/generated/sql/bnf:create_schedule_for_changefeed_stmt.bnf",
+    "//docs/generated/sql/bnf:create_schedule_for_restore_drill_stmt.bnf",
     "//docs/generated/sql/bnf:create_schedule_stmt.bnf",
     "//docs/generated/sql/bnf:create_schema_stmt.bnf",
     "//docs/generated/sql/bnf:create_sequence_stmt.bnf",
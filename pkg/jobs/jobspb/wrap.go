@@ -50,6 +50,8 @@ var (
 	_ Details = HotRangesLoggerDetails{}
 	_ Details = InspectDetails{}
 	_ Details = FingerprintDetails{}
+	_ Details = RestoreDrillDetails{}
+	_ Details = ImportDumpDetails{}
 )
 
 // ProgressDetails is a marker interface for job progress details proto structs.
@@ -84,6 +86,8 @@ var (
 	_ ProgressDetails = HotRangesLoggerProgress{}
 	_ ProgressDetails = InspectProgress{}
 	_ ProgressDetails = FingerprintProgress{}
+	_ ProgressDetails = RestoreDrillProgress{}
+	_ ProgressDetails = ImportDumpProgress{}
 )
 
 // Type returns the payload's job type and panics if the type is invalid.
@@ -253,6 +257,10 @@ func DetailsType(d isPayload_Details) (Type, error) {
 		return TypeInspect, nil
 	case *Payload_FingerprintDetails:
 		return TypeFingerprint, nil
+	case *Payload_RestoreDrillDetails:
+		return TypeRestoreDrill, nil
+	case *Payload_ImportDumpDetails:
+		return TypeImportDump, nil
 	default:
 		return TypeUnspecified, errors.Newf("Payload.Type called on a payload with an unknown details type: %T", d)
 	}
@@ -310,6 +318,8 @@ var JobDetailsForEveryJobType = map[Type]Details{
 	TypeHotRangesLogger:              HotRangesLoggerDetails{},
 	TypeInspect:                      InspectDetails{},
 	TypeFingerprint:                  FingerprintDetails{},
+	TypeRestoreDrill:                 RestoreDrillDetails{},
+	TypeImportDump:                   ImportDumpDetails{},
 }
 
 // WrapProgressDetails wraps a ProgressDetails object in the protobuf wrapper
@@ -385,6 +395,10 @@ func WrapProgressDetails(details ProgressDetails) interface {
 		return &Progress_Inspect{Inspect: &d}
 	case FingerprintProgress:
 		return &Progress_Fingerprint{Fingerprint: &d}
+	case RestoreDrillProgress:
+		return &Progress_RestoreDrill{RestoreDrill: &d}
+	case ImportDumpProgress:
+		return &Progress_ImportDump{ImportDump: &d}
 	default:
 		panic(errors.AssertionFailedf("WrapProgressDetails: unknown progress type %T", d))
 	}
@@ -458,6 +472,10 @@ func (p *Payload) UnwrapDetails() Details {
 		return *d.InspectDetails
 	case *Payload_FingerprintDetails:
 		return *d.FingerprintDetails
+	case *Payload_RestoreDrillDetails:
+		return *d.RestoreDrillDetails
+	case *Payload_ImportDumpDetails:
+		return *d.ImportDumpDetails
 	default:
 		return nil
 	}
@@ -531,6 +549,10 @@ func (p *Progress) UnwrapDetails() ProgressDetails {
 		return d.Inspect
 	case *Progress_Fingerprint:
 		return d.Fingerprint
+	case *Progress_RestoreDrill:
+		return *d.RestoreDrill
+	case *Progress_ImportDump:
+		return *d.ImportDump
 	default:
 		return nil
 	}
@@ -628,6 +650,10 @@ func WrapPayloadDetails(details Details) interface {
 		return &Payload_InspectDetails{InspectDetails: &d}
 	case FingerprintDetails:
 		return &Payload_FingerprintDetails{FingerprintDetails: &d}
+	case RestoreDrillDetails:
+		return &Payload_RestoreDrillDetails{RestoreDrillDetails: &d}
+	case ImportDumpDetails:
+		return &Payload_ImportDumpDetails{ImportDumpDetails: &d}
 	default:
 		panic(errors.AssertionFailedf("jobs.WrapPayloadDetails: unknown details type %T", d))
 	}
@@ -663,7 +689,7 @@ const (
 func (Type) SafeValue() {}
 
 // NumJobTypes is the number of jobs types.
-const NumJobTypes = 35
+const NumJobTypes = 37
 
 // ChangefeedDetailsMarshaler allows for dependency injection of
 // cloud.SanitizeExternalStorageURI to avoid the dependency from this
@@ -57,6 +57,9 @@ func setProtectedTSOnJob(details jobspb.Details, u *uuid.UUID) jobspb.Details {
 	case jobspb.InspectDetails:
 		v.ProtectedTimestampRecord = u
 		return v
+	case jobspb.ImportDetails:
+		v.ProtectedTimestampRecord = u
+		return v
 	default:
 		panic(errors.AssertionFailedf("not supported %T", details))
 	}
@@ -72,6 +75,8 @@ func getProtectedTSOnJob(details jobspb.Details) *uuid.UUID {
 		return v.ProtectedTimestampRecord
 	case jobspb.InspectDetails:
 		return v.ProtectedTimestampRecord
+	case jobspb.ImportDetails:
+		return v.ProtectedTimestampRecord
 	default:
 		panic("not supported")
 	}
@@ -135,6 +135,7 @@ func MakeBulkAdder(
 				settings,
 				opts.WriteAtBatchTimestamp,
 				opts.DisallowShadowingBelow,
+				opts.DisallowConflicts,
 				admissionpb.BulkNormalPri,
 				false,
 			),
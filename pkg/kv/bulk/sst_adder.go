@@ -32,6 +32,9 @@ type sstAdder struct {
 	// disallowShadowingBelow is described on kvpb.AddSSTableRequest.
 	disallowShadowingBelow hlc.Timestamp
 
+	// disallowConflicts is described on kvpb.AddSSTableRequest.
+	disallowConflicts bool
+
 	// priority is the admission priority used for AddSSTable
 	// requests.
 	priority admissionpb.WorkPriority
@@ -51,12 +54,14 @@ func newSSTAdder(
 	settings *cluster.Settings,
 	writeAtBatchTS bool,
 	disallowShadowingBelow hlc.Timestamp,
+	disallowConflicts bool,
 	priority admissionpb.WorkPriority,
 	computeStatsDiff bool,
 ) *sstAdder {
 	return &sstAdder{
 		db:                     db,
 		disallowShadowingBelow: disallowShadowingBelow,
+		disallowConflicts:      disallowConflicts,
 		priority:               priority,
 		settings:               settings,
 		writeAtBatchTS:         writeAtBatchTS,
@@ -158,6 +163,7 @@ func (a *sstAdder) AddSSTable(
 					RequestHeader:                          kvpb.RequestHeader{Key: item.start, EndKey: item.end},
 					Data:                                   item.sstBytes,
 					DisallowShadowingBelow:                 a.disallowShadowingBelow,
+					DisallowConflicts:                      a.disallowConflicts,
 					IngestAsWrites:                         ingestAsWriteBatch,
 					ReturnFollowingLikelyNonEmptySpanStart: true,
 					ComputeStatsDiff:                       a.computeStatsDiff,
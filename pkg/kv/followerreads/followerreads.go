@@ -69,13 +69,16 @@ func EvalFollowerReadOffset(st *cluster.Settings) (time.Duration, error) {
 // closedTimestampLikelySufficient determines if a request with a given required
 // frontier timestamp is likely to be below a follower's closed timestamp and
 // serviceable as a follower read were the request to be sent to a follower
-// replica.
+// replica. If waitForClosedTimestamp is set, the follower will wait for its
+// closed timestamp to catch up, so the request is serviceable if its frontier
+// is likely to be closed within the maximum wait.
 func closedTimestampLikelySufficient(
 	ctx context.Context,
 	st *cluster.Settings,
 	clock *hlc.Clock,
 	ctPolicy roachpb.RangeClosedTimestampPolicy,
 	requiredFrontierTS hlc.Timestamp,
+	waitForClosedTimestamp bool,
 ) bool {
 	var offset time.Duration
 	switch ctPolicy {
@@ -86,6 +89,9 @@ func closedTimestampLikelySufficient(
 	default:
 		panic("unknown RangeClosedTimestampPolicy")
 	}
+	if waitForClosedTimestamp {
+		offset += closedts.FollowerReadMaxWait.Get(&st.SV)
+	}
 	expectedClosedTS := clock.Now().Add(offset.Nanoseconds(), 0)
 	return requiredFrontierTS.LessEq(expectedClosedTS)
 }
@@ -100,7 +106,8 @@ func CanSendToFollower(
 	ba *kvpb.BatchRequest,
 ) bool {
 	result := kvpb.BatchCanBeEvaluatedOnFollower(ctx, ba) &&
-		closedTimestampLikelySufficient(ctx, st, clock, ctPolicy, ba.RequiredFrontier()) &&
+		closedTimestampLikelySufficient(ctx, st, clock, ctPolicy, ba.RequiredFrontier(),
+			ba.WaitForClosedTimestamp) &&
 		// NOTE: this call can be expensive, so perform it last. See #62447.
 		checkFollowerReadsEnabled(ctx, st)
 	return result
@@ -157,7 +164,8 @@ func (o *followerReadOracle) useClosestOracle(
 	// BatchRequests in the DistSender. This would hurt performance, but would
 	// not violate correctness.
 	return txn != nil &&
-		closedTimestampLikelySufficient(ctx, o.st, o.clock, ctPolicy, txn.RequiredFrontier()) &&
+		closedTimestampLikelySufficient(ctx, o.st, o.clock, ctPolicy, txn.RequiredFrontier(),
+			txn.WaitForClosedTimestamp()) &&
 		// NOTE: this call can be expensive, so perform it last. See #62447.
 		checkFollowerReadsEnabled(ctx, o.st)
 }
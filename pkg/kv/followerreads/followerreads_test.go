@@ -118,6 +118,10 @@ func TestCanSendToFollower(t *testing.T) {
 		ba.TimestampFromServerClock = (*hlc.ClockTimestamp)(&ts)
 		return ba
 	}
+	withWaitForClosedTimestamp := func(ba *kvpb.BatchRequest) *kvpb.BatchRequest {
+		ba.WaitForClosedTimestamp = true
+		return ba
+	}
 
 	testCases := []struct {
 		name                  string
@@ -172,6 +176,16 @@ func TestCanSendToFollower(t *testing.T) {
 			ba:   withBatchTimestamp(batch(nil, &kvpb.GetRequest{}), future),
 			exp:  false,
 		},
+		{
+			name: "current-time non-txn batch, waiting for closed timestamp",
+			ba:   withWaitForClosedTimestamp(withBatchTimestamp(batch(nil, &kvpb.GetRequest{}), current)),
+			exp:  true,
+		},
+		{
+			name: "future non-txn batch, waiting for closed timestamp",
+			ba:   withWaitForClosedTimestamp(withBatchTimestamp(batch(nil, &kvpb.GetRequest{}), future)),
+			exp:  false,
+		},
 		{
 			name: "future non-txn export batch",
 			ba:   withBatchTimestamp(batch(nil, &kvpb.ExportRequest{}), future),
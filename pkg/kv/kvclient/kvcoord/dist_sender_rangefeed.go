@@ -67,6 +67,8 @@ type rangeFeedConfig struct {
 	withFiltering         bool
 	withMetadata          bool
 	withMatchingOriginIDs []uint32
+	rowFilter             *kvpb.RangeFeedRowFilter
+	catchUpMode           kvpb.RangeFeedRequest_CatchUpMode
 	rangeObserver         RangeObserver
 	consumerID            int64
 	bulkDelivery          bool
@@ -136,6 +138,23 @@ func WithMatchingOriginIDs(originIDs ...uint32) RangeFeedOption {
 	})
 }
 
+// WithRowFilter pushes the provided row predicate and projection down to the
+// rangefeed servers. Filtering is best-effort; the caller must still evaluate
+// the predicate on the events it receives.
+func WithRowFilter(filter *kvpb.RangeFeedRowFilter) RangeFeedOption {
+	return optionFunc(func(c *rangeFeedConfig) {
+		c.rowFilter = filter
+	})
+}
+
+// WithCatchUpMode controls which versions the catch-up scans of the rangefeed
+// emit. See kvpb.RangeFeedRequest_CatchUpMode.
+func WithCatchUpMode(mode kvpb.RangeFeedRequest_CatchUpMode) RangeFeedOption {
+	return optionFunc(func(c *rangeFeedConfig) {
+		c.catchUpMode = mode
+	})
+}
+
 // WithRangeObserver is called when the rangefeed starts with a function that
 // can be used to iterate over all the ranges.
 func WithRangeObserver(observer RangeObserver) RangeFeedOption {
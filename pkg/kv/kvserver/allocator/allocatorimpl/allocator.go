@@ -137,6 +137,11 @@ const (
 	AllocatorReplaceDecommissioningNonVoter
 	AllocatorRemoveDecommissioningVoter
 	AllocatorRemoveDecommissioningNonVoter
+	AllocatorAddWitness
+	AllocatorReplaceDeadWitness
+	AllocatorRemoveDeadWitness
+	AllocatorRemoveWitness
+	AllocatorReplaceDeadVoterNearWitness
 	AllocatorRemoveLearner
 	AllocatorConsiderRebalance
 	AllocatorRangeUnavailable
@@ -184,7 +189,8 @@ func (a AllocatorAction) TargetReplicaType() TargetReplicaType {
 		a == AllocatorReplaceDeadVoter ||
 		a == AllocatorRemoveDeadVoter ||
 		a == AllocatorReplaceDecommissioningVoter ||
-		a == AllocatorRemoveDecommissioningVoter {
+		a == AllocatorRemoveDecommissioningVoter ||
+		a == AllocatorReplaceDeadVoterNearWitness {
 		t = VoterTarget
 	} else if a == AllocatorRemoveNonVoter ||
 		a == AllocatorAddNonVoter ||
@@ -193,6 +199,11 @@ func (a AllocatorAction) TargetReplicaType() TargetReplicaType {
 		a == AllocatorReplaceDecommissioningNonVoter ||
 		a == AllocatorRemoveDecommissioningNonVoter {
 		t = NonVoterTarget
+	} else if a == AllocatorAddWitness ||
+		a == AllocatorReplaceDeadWitness ||
+		a == AllocatorRemoveDeadWitness ||
+		a == AllocatorRemoveWitness {
+		t = WitnessTarget
 	}
 	return t
 }
@@ -203,13 +214,18 @@ func (a AllocatorAction) ReplicaStatus() ReplicaStatus {
 	var s ReplicaStatus
 	if a == AllocatorRemoveVoter ||
 		a == AllocatorRemoveNonVoter ||
+		a == AllocatorRemoveWitness ||
 		a == AllocatorAddVoter ||
-		a == AllocatorAddNonVoter {
+		a == AllocatorAddNonVoter ||
+		a == AllocatorAddWitness {
 		s = Alive
 	} else if a == AllocatorReplaceDeadVoter ||
 		a == AllocatorReplaceDeadNonVoter ||
+		a == AllocatorReplaceDeadWitness ||
 		a == AllocatorRemoveDeadVoter ||
-		a == AllocatorRemoveDeadNonVoter {
+		a == AllocatorRemoveDeadNonVoter ||
+		a == AllocatorRemoveDeadWitness ||
+		a == AllocatorReplaceDeadVoterNearWitness {
 		s = Dead
 	} else if a == AllocatorReplaceDecommissioningVoter ||
 		a == AllocatorReplaceDecommissioningNonVoter ||
@@ -234,6 +250,11 @@ var allocatorActionNames = map[AllocatorAction]string{
 	AllocatorReplaceDecommissioningNonVoter:  "replace decommissioning non-voter",
 	AllocatorRemoveDecommissioningVoter:      "remove decommissioning voter",
 	AllocatorRemoveDecommissioningNonVoter:   "remove decommissioning non-voter",
+	AllocatorAddWitness:                      "add witness",
+	AllocatorReplaceDeadWitness:              "replace dead witness",
+	AllocatorRemoveDeadWitness:               "remove dead witness",
+	AllocatorRemoveWitness:                   "remove witness",
+	AllocatorReplaceDeadVoterNearWitness:     "replace dead voter near witness",
 	AllocatorRemoveLearner:                   "remove learner",
 	AllocatorConsiderRebalance:               "consider rebalance",
 	AllocatorRangeUnavailable:                "range unavailable",
@@ -280,15 +301,17 @@ func (a AllocatorAction) Priority() float64 {
 		return 12001
 	case AllocatorReplaceDeadVoter:
 		return 12000
+	case AllocatorReplaceDeadVoterNearWitness:
+		return 11000
 	case AllocatorAddVoter:
 		return 10000
-	case AllocatorReplaceDecommissioningVoter:
+	case AllocatorReplaceDecommissioningVoter, AllocatorReplaceDeadWitness, AllocatorAddWitness:
 		return 5000
-	case AllocatorRemoveDeadVoter:
+	case AllocatorRemoveDeadVoter, AllocatorRemoveDeadWitness:
 		return 1000
 	case AllocatorRemoveDecommissioningVoter:
 		return 900
-	case AllocatorRemoveVoter:
+	case AllocatorRemoveVoter, AllocatorRemoveWitness:
 		return 800
 	case AllocatorReplaceDeadNonVoter:
 		return 700
@@ -309,8 +332,8 @@ func (a AllocatorAction) Priority() float64 {
 	}
 }
 
-// TargetReplicaType indicates whether the target replica is a voter or
-// non-voter.
+// TargetReplicaType indicates whether the target replica is a voter,
+// non-voter or witness.
 type TargetReplicaType int
 
 const (
@@ -319,6 +342,9 @@ const (
 	VoterTarget
 	// NonVoterTarget represents a non-voting target replica.
 	NonVoterTarget
+	// WitnessTarget represents a witness target replica. Witnesses are placed
+	// like non-voters, but vote in raft and hold no data.
+	WitnessTarget
 )
 
 // ReplicaStatus represents whether a replica is currently alive,
@@ -346,6 +372,8 @@ func (t TargetReplicaType) AddChangeType() roachpb.ReplicaChangeType {
 		return roachpb.ADD_VOTER
 	case NonVoterTarget:
 		return roachpb.ADD_NON_VOTER
+	case WitnessTarget:
+		return roachpb.ADD_WITNESS
 	default:
 		panic(fmt.Sprintf("unknown targetReplicaType %d", t))
 	}
@@ -359,6 +387,8 @@ func (t TargetReplicaType) RemoveChangeType() roachpb.ReplicaChangeType {
 		return roachpb.REMOVE_VOTER
 	case NonVoterTarget:
 		return roachpb.REMOVE_NON_VOTER
+	case WitnessTarget:
+		return roachpb.REMOVE_WITNESS
 	default:
 		panic(fmt.Sprintf("unknown targetReplicaType %d", t))
 	}
@@ -370,6 +400,8 @@ func (t TargetReplicaType) String() string {
 		return "voter"
 	case NonVoterTarget:
 		return "non-voter"
+	case WitnessTarget:
+		return "witness"
 	default:
 		panic(fmt.Sprintf("unknown targetReplicaType %d", t))
 	}
@@ -980,7 +1012,7 @@ func (a *Allocator) ComputeAction(
 	}
 
 	action, priority = a.computeAction(ctx, storePool, conf, desc.Replicas().VoterDescriptors(),
-		desc.Replicas().NonVoterDescriptors())
+		desc.Replicas().NonVoterDescriptors(), desc.Replicas().WitnessDescriptors())
 	// Ensure that priority is never -1. Typically, computeAction return
 	// action.Priority(), but we sometimes modify the priority for specific
 	// actions like AllocatorAddVoter, AllocatorRemoveDeadVoter, and
@@ -1047,6 +1079,7 @@ func (a *Allocator) computeAction(
 	conf *roachpb.SpanConfig,
 	voterReplicas []roachpb.ReplicaDescriptor,
 	nonVoterReplicas []roachpb.ReplicaDescriptor,
+	witnessReplicas []roachpb.ReplicaDescriptor,
 ) (action AllocatorAction, adjustedPriority float64) {
 	// NB: The ordering of the checks in this method is intentional. The order in
 	// which these actions are returned by this method determines the relative
@@ -1055,12 +1088,16 @@ func (a *Allocator) computeAction(
 	// (which influence the replicateQueue's decision of which range it'll pick to
 	// repair/rebalance before the others).
 	//
-	// In broad strokes, we first handle all voting replica-based actions and then
-	// the actions pertaining to non-voting replicas. Within each replica set, we
-	// first handle operations that correspond to repairing/recovering the range.
-	// After that we handle rebalancing related actions, followed by removal
-	// actions.
+	// In broad strokes, we first handle all voting replica-based actions, then
+	// those pertaining to witnesses and finally the actions pertaining to
+	// non-voting replicas. Within each replica set, we first handle operations
+	// that correspond to repairing/recovering the range. After that we handle
+	// rebalancing related actions, followed by removal actions.
+	//
+	// NB: voterReplicas only contains the voters holding data. Witnesses also
+	// vote, so they count towards the quorum of the range.
 	haveVoters := len(voterReplicas)
+	haveWitnesses := len(witnessReplicas)
 	decommissioningVoters := storePool.DecommissioningReplicas(voterReplicas)
 	postDecommissionVoters := haveVoters - len(decommissioningVoters)
 	// Node count including dead nodes but excluding
@@ -1068,7 +1105,7 @@ func (a *Allocator) computeAction(
 	clusterNodes := storePool.ClusterNodeCount()
 	neededVoters := GetNeededVoters(conf.GetNumVoters(), clusterNodes)
 	desiredQuorum := computeQuorum(neededVoters)
-	quorum := computeQuorum(haveVoters)
+	quorum := computeQuorum(haveVoters + haveWitnesses)
 
 	// TODO(aayush): When haveVoters < neededVoters but we don't have quorum to
 	// actually execute the addition of a new replica, we should be returning a
@@ -1092,15 +1129,27 @@ func (a *Allocator) computeAction(
 	// elsewhere (for a regular rebalance or for decommissioning).
 	const includeSuspectAndDrainingStores = true
 	liveVoters, deadVoters := storePool.LiveAndDeadReplicas(voterReplicas, includeSuspectAndDrainingStores)
+	liveWitnesses, deadWitnesses := storePool.LiveAndDeadReplicas(witnessReplicas, includeSuspectAndDrainingStores)
 
-	if len(liveVoters) < quorum {
+	if len(liveVoters)+len(liveWitnesses) < quorum {
 		// Do not take any replacement/removal action if we do not have a quorum of
 		// live voters. If we're correctly assessing the unavailable state of the
 		// range, we also won't be able to add replicas as we try above, but hope
 		// springs eternal.
 		action = AllocatorRangeUnavailable
-		log.KvDistribution.VEventf(ctx, 1, "unable to take action - live voters %v don't meet quorum of %d",
-			liveVoters, quorum)
+		log.KvDistribution.VEventf(ctx, 1, "unable to take action - live voters %v and witnesses %v don't meet quorum of %d",
+			liveVoters, liveWitnesses, quorum)
+		return action, action.Priority()
+	}
+
+	if postDecommissionVoters <= neededVoters && len(deadVoters) > 0 && len(liveWitnesses) > 0 {
+		// A voter holding data is dead, but a live witness remains. In a two
+		// datacenter deployment the witness is in the surviving datacenter, so
+		// replace the dead voter with a new one next to the witness. The witness
+		// stays in place and keeps voting until the new voter has caught up.
+		action = AllocatorReplaceDeadVoterNearWitness
+		log.KvDistribution.VEventf(ctx, 3, "%s - %d dead voters, %d live witnesses priority=%.2f",
+			action, len(deadVoters), len(liveWitnesses), action.Priority())
 		return action, action.Priority()
 	}
 
@@ -1123,6 +1172,25 @@ func (a *Allocator) computeAction(
 		return action, action.Priority()
 	}
 
+	// Witness addition / replacement. Witnesses hold no data, so there is
+	// nothing to drain from one on a decommissioning store: we treat those just
+	// like witnesses on dead stores.
+	neededWitnesses := GetNeededNonVoters(haveVoters, int(conf.GetNumWitnesses()), clusterNodes)
+	unhealthyWitnesses := append(deadWitnesses, storePool.DecommissioningReplicas(witnessReplicas)...)
+	if haveWitnesses < neededWitnesses {
+		action = AllocatorAddWitness
+		log.KvDistribution.VEventf(ctx, 3, "%s - missing witness need=%d, have=%d, priority=%.2f",
+			action, neededWitnesses, haveWitnesses, action.Priority())
+		return action, action.Priority()
+	}
+
+	if haveWitnesses <= neededWitnesses && len(unhealthyWitnesses) > 0 {
+		action = AllocatorReplaceDeadWitness
+		log.KvDistribution.VEventf(ctx, 3, "%s - replacement for %d dead witnesses priority=%.2f",
+			action, len(unhealthyWitnesses), action.Priority())
+		return action, action.Priority()
+	}
+
 	// Voting replica removal actions follow.
 	// TODO(aayush): There's an additional case related to dead voters that we
 	// should handle above. If there are one or more dead replicas, have < need,
@@ -1158,11 +1226,29 @@ func (a *Allocator) computeAction(
 		return action, adjustedPriority
 	}
 
+	// Witness removal.
+	if len(unhealthyWitnesses) > 0 {
+		// The range is over-replicated _and_ has witness(es) on a dead or
+		// decommissioning node. We'll just remove these.
+		action = AllocatorRemoveDeadWitness
+		log.KvDistribution.VEventf(ctx, 3, "%s - dead=%d, live=%d, priority=%.2f",
+			action, len(unhealthyWitnesses), len(liveWitnesses), action.Priority())
+		return action, action.Priority()
+	}
+
+	if haveWitnesses > neededWitnesses {
+		action = AllocatorRemoveWitness
+		log.KvDistribution.VEventf(ctx, 3, "%s - need=%d, have=%d, priority=%.2f", action,
+			neededWitnesses, haveWitnesses, action.Priority())
+		return action, action.Priority()
+	}
+
 	// Non-voting replica actions follow.
 	//
 	// Non-voting replica addition / replacement.
 	haveNonVoters := len(nonVoterReplicas)
-	neededNonVoters := GetNeededNonVoters(haveVoters, int(conf.GetNumNonVoters()), clusterNodes)
+	neededNonVoters := GetNeededNonVoters(
+		haveVoters+haveWitnesses, int(conf.GetNumNonVoters()), clusterNodes)
 	if haveNonVoters < neededNonVoters {
 		action = AllocatorAddNonVoter
 		log.KvDistribution.VEventf(ctx, 3, "%s - missing non-voter need=%d, have=%d, priority=%.2f",
@@ -1464,6 +1550,24 @@ func (a *Allocator) AllocateNonVoter(
 	return a.AllocateTarget(ctx, storePool, conf, existingVoters, existingNonVoters, replacing, replicaStatus, NonVoterTarget)
 }
 
+// AllocateWitness returns a suitable store for a new allocation of a witness
+// replica. Witnesses are subject to the same placement rules as non-voting
+// replicas, and nodes already accommodating _any_ existing replicas are ruled
+// out as targets.
+func (a *Allocator) AllocateWitness(
+	ctx context.Context,
+	storePool storepool.AllocatorStorePool,
+	conf *roachpb.SpanConfig,
+	existingVoters, existingNonVoters, existingWitnesses []roachpb.ReplicaDescriptor,
+	replacing *roachpb.ReplicaDescriptor,
+	replicaStatus ReplicaStatus,
+) (roachpb.ReplicationTarget, string, error) {
+	existing := make([]roachpb.ReplicaDescriptor, 0, len(existingNonVoters)+len(existingWitnesses))
+	existing = append(existing, existingNonVoters...)
+	existing = append(existing, existingWitnesses...)
+	return a.AllocateTarget(ctx, storePool, conf, existingVoters, existing, replacing, replicaStatus, NonVoterTarget)
+}
+
 // AllocateTargetFromList returns a suitable store for a new allocation of a
 // replica of the given type from the set of candidate stores, with the given
 // existing set of voters and non-voters..
@@ -7481,6 +7481,89 @@ func TestAllocatorComputeActionRemoveDead(t *testing.T) {
 	}
 }
 
+func TestAllocatorComputeActionWitness(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	conf := roachpb.SpanConfig{NumReplicas: 3, NumVoters: 2, NumWitnesses: 1}
+	twoVoters := roachpb.RangeDescriptor{
+		InternalReplicas: []roachpb.ReplicaDescriptor{
+			{StoreID: 1, NodeID: 1, ReplicaID: 1},
+			{StoreID: 2, NodeID: 2, ReplicaID: 2},
+		},
+	}
+	withWitness := twoVoters
+	withWitness.InternalReplicas = append(withWitness.InternalReplicas[:2:2], roachpb.ReplicaDescriptor{
+		StoreID: 3, NodeID: 3, ReplicaID: 3, Type: roachpb.WITNESS,
+	})
+	withTwoWitnesses := withWitness
+	withTwoWitnesses.InternalReplicas = append(withTwoWitnesses.InternalReplicas[:3:3], roachpb.ReplicaDescriptor{
+		StoreID: 4, NodeID: 4, ReplicaID: 4, Type: roachpb.WITNESS,
+	})
+
+	testCases := []struct {
+		name           string
+		desc           roachpb.RangeDescriptor
+		live           []roachpb.StoreID
+		dead           []roachpb.StoreID
+		expectedAction AllocatorAction
+	}{
+		{
+			name:           "missing witness",
+			desc:           twoVoters,
+			live:           []roachpb.StoreID{1, 2, 3, 4},
+			expectedAction: AllocatorAddWitness,
+		},
+		{
+			name:           "fully replicated",
+			desc:           withWitness,
+			live:           []roachpb.StoreID{1, 2, 3, 4},
+			expectedAction: AllocatorConsiderRebalance,
+		},
+		{
+			// The witness keeps the range available when a voter dies, and the
+			// dead voter is replaced next to it.
+			name:           "dead voter with live witness",
+			desc:           withWitness,
+			live:           []roachpb.StoreID{1, 3, 4},
+			dead:           []roachpb.StoreID{2},
+			expectedAction: AllocatorReplaceDeadVoterNearWitness,
+		},
+		{
+			name:           "dead witness",
+			desc:           withWitness,
+			live:           []roachpb.StoreID{1, 2, 4},
+			dead:           []roachpb.StoreID{3},
+			expectedAction: AllocatorReplaceDeadWitness,
+		},
+		{
+			name:           "dead voter and witness",
+			desc:           withWitness,
+			live:           []roachpb.StoreID{1, 4},
+			dead:           []roachpb.StoreID{2, 3},
+			expectedAction: AllocatorRangeUnavailable,
+		},
+		{
+			name:           "too many witnesses",
+			desc:           withTwoWitnesses,
+			live:           []roachpb.StoreID{1, 2, 3, 4},
+			expectedAction: AllocatorRemoveWitness,
+		},
+	}
+
+	ctx := context.Background()
+	stopper, _, sp, a, _ := CreateTestAllocator(ctx, 10, false /* deterministic */)
+	defer stopper.Stop(ctx)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockStorePool(sp, tc.live, nil, tc.dead, nil, nil, nil)
+			action, _ := a.ComputeAction(ctx, sp, &conf, &tc.desc)
+			require.Equal(t, tc.expectedAction, action)
+		})
+	}
+}
+
 func TestAllocatorComputeActionWithStorePoolRemoveDead(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -489,6 +489,10 @@ func mapReplicaTypeToVoterOrNonVoter(rType roachpb.ReplicaType) roachpb.ReplicaT
 		return roachpb.VOTER_FULL
 	case roachpb.NON_VOTER, roachpb.LEARNER:
 		return roachpb.NON_VOTER
+	case roachpb.WITNESS:
+		// Witnesses carry no data and can't hold the lease, so for the purposes
+		// of load they look like non-voters.
+		return roachpb.NON_VOTER
 	default:
 		panic(errors.AssertionFailedf("unknown replica type %v", rType))
 	}
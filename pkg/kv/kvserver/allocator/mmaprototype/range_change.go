@@ -213,10 +213,14 @@ func (rc *ExternalRangeChange) ReplicationChanges() kvpb.ReplicationChanges {
 		if c.ChangeType == ChangeReplica || c.ChangeType == RemoveReplica {
 			chg := kvpb.ReplicationChange{Target: c.Target}
 			prevType := mapReplicaTypeToVoterOrNonVoter(c.Prev.ReplicaType.ReplicaType)
-			switch prevType {
-			case roachpb.VOTER_FULL:
+			switch {
+			case c.Prev.ReplicaType.ReplicaType == roachpb.WITNESS:
+				// Witnesses are modeled as non-voters for load purposes, but must
+				// be removed with the dedicated change type.
+				chg.ChangeType = roachpb.REMOVE_WITNESS
+			case prevType == roachpb.VOTER_FULL:
 				chg.ChangeType = roachpb.REMOVE_VOTER
-			case roachpb.NON_VOTER:
+			case prevType == roachpb.NON_VOTER:
 				chg.ChangeType = roachpb.REMOVE_NON_VOTER
 			default:
 				panic(errors.AssertionFailedf("unexpected replica type %s", c.Prev.ReplicaType.ReplicaType))
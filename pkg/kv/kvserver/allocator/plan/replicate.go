@@ -308,6 +308,19 @@ func (rp ReplicaPlanner) PlanOneChange(
 		op, stats, err = rp.removeDead(ctx, repl, deadVoterReplicas, allocatorimpl.VoterTarget)
 	case allocatorimpl.AllocatorRemoveDeadNonVoter:
 		op, stats, err = rp.removeDead(ctx, repl, deadNonVoterReplicas, allocatorimpl.NonVoterTarget)
+
+	// Add, replace or remove witnesses, or replace a dead voter next to one.
+	case allocatorimpl.AllocatorAddWitness, allocatorimpl.AllocatorReplaceDeadWitness:
+		op, stats, err = rp.addOrReplaceWitness(ctx, repl, desc, conf, action, allocatorPrio)
+	case allocatorimpl.AllocatorRemoveDeadWitness:
+		op, stats, err = rp.removeDead(ctx, repl, rp.unhealthyWitnesses(desc), allocatorimpl.WitnessTarget)
+	case allocatorimpl.AllocatorRemoveWitness:
+		op, stats, err = rp.removeWitness(ctx, repl, desc, conf)
+	case allocatorimpl.AllocatorReplaceDeadVoterNearWitness:
+		op, stats, err = rp.replaceDeadVoterNearWitness(
+			ctx, repl, desc, conf, liveVoterReplicas, deadVoterReplicas, liveNonVoterReplicas, allocatorPrio,
+		)
+
 	// Rebalance replicas.
 	//
 	// NB: Rebalacing attempts to balance replica counts among stores of
@@ -679,6 +692,183 @@ func (rp ReplicaPlanner) removeNonVoter(
 	return op, stats, nil
 }
 
+// unhealthyWitnesses returns the witnesses of the range that are on dead or
+// decommissioning stores. Since witnesses hold no data, both are handled the
+// same way.
+func (rp ReplicaPlanner) unhealthyWitnesses(
+	desc *roachpb.RangeDescriptor,
+) []roachpb.ReplicaDescriptor {
+	witnesses := desc.Replicas().WitnessDescriptors()
+	_, dead := rp.storePool.LiveAndDeadReplicas(witnesses, true /* includeSuspectAndDrainingStores */)
+	return append(dead, rp.storePool.DecommissioningReplicas(witnesses)...)
+}
+
+// addOrReplaceWitness adds a witness to the range, or replaces one on a dead or
+// decommissioning store with a new one.
+func (rp ReplicaPlanner) addOrReplaceWitness(
+	ctx context.Context,
+	repl AllocatorReplica,
+	desc *roachpb.RangeDescriptor,
+	conf *roachpb.SpanConfig,
+	action allocatorimpl.AllocatorAction,
+	allocatorPrio float64,
+) (op AllocationOp, stats ReplicateStats, _ error) {
+	existingWitnesses := desc.Replicas().WitnessDescriptors()
+	var replacing *roachpb.ReplicaDescriptor
+	if action == allocatorimpl.AllocatorReplaceDeadWitness {
+		unhealthy := rp.unhealthyWitnesses(desc)
+		if len(unhealthy) == 0 {
+			return nil, stats, errors.AssertionFailedf(
+				"range %s was identified as having dead witnesses, but no dead witnesses were found", repl)
+		}
+		replacing = &unhealthy[0]
+	}
+
+	newWitness, details, err := rp.allocator.AllocateWitness(
+		ctx, rp.storePool, conf, desc.Replicas().VoterDescriptors(),
+		desc.Replicas().NonVoterDescriptors(), existingWitnesses, replacing, action.ReplicaStatus(),
+	)
+	if err != nil {
+		return nil, stats, err
+	}
+
+	stats = stats.trackAddReplicaCount(allocatorimpl.WitnessTarget)
+	ops := kvpb.MakeReplicationChanges(roachpb.ADD_WITNESS, newWitness)
+	if replacing == nil {
+		log.KvDistribution.Infof(ctx, "adding witness %+v: %s",
+			newWitness, rangeRaftProgress(repl.RaftStatus(), existingWitnesses))
+	} else {
+		stats = stats.trackRemoveMetric(allocatorimpl.WitnessTarget, action.ReplicaStatus())
+		log.KvDistribution.Infof(ctx, "replacing witness %s with %+v: %s",
+			replacing, newWitness, rangeRaftProgress(repl.RaftStatus(), existingWitnesses))
+		ops = append(ops,
+			kvpb.MakeReplicationChanges(roachpb.REMOVE_WITNESS, roachpb.ReplicationTarget{
+				StoreID: replacing.StoreID,
+				NodeID:  replacing.NodeID,
+			})...)
+	}
+
+	op = AllocationChangeReplicasOp{
+		LeaseholderStore:  repl.StoreID(),
+		Usage:             repl.RangeUsageInfo(),
+		Chgs:              ops,
+		AllocatorPriority: allocatorPrio,
+		Reason:            kvserverpb.ReasonRangeUnderReplicated,
+		Details:           details,
+	}
+	return op, stats, nil
+}
+
+// removeWitness removes a witness from a range that has more of them than its
+// span config asks for.
+func (rp ReplicaPlanner) removeWitness(
+	ctx context.Context,
+	repl AllocatorReplica,
+	desc *roachpb.RangeDescriptor,
+	conf *roachpb.SpanConfig,
+) (op AllocationOp, stats ReplicateStats, _ error) {
+	existingWitnesses := desc.Replicas().WitnessDescriptors()
+	existingVoters := desc.Replicas().VoterDescriptors()
+	existingNonVoters := append(desc.Replicas().NonVoterDescriptors(), existingWitnesses...)
+	removeWitness, details, err := rp.allocator.RemoveNonVoter(
+		ctx,
+		rp.storePool,
+		conf,
+		existingWitnesses,
+		existingVoters,
+		existingNonVoters,
+		rp.allocator.ScorerOptions(ctx),
+	)
+	if err != nil {
+		return nil, stats, err
+	}
+	stats = stats.trackRemoveMetric(allocatorimpl.WitnessTarget, allocatorimpl.Alive)
+
+	log.KvDistribution.Infof(ctx, "removing witness %+v due to over-replication: %s",
+		removeWitness, rangeRaftProgress(repl.RaftStatus(), existingVoters))
+	op = AllocationChangeReplicasOp{
+		LeaseholderStore:  repl.StoreID(),
+		Usage:             repl.RangeUsageInfo(),
+		Chgs:              kvpb.MakeReplicationChanges(roachpb.REMOVE_WITNESS, removeWitness),
+		AllocatorPriority: 0.0, // unused
+		Reason:            kvserverpb.ReasonRangeOverReplicated,
+		Details:           details,
+	}
+	return op, stats, nil
+}
+
+// replaceDeadVoterNearWitness replaces a dead voter with a new voter in the locality of a
+// live witness. In a two datacenter deployment the witness sits in the
+// surviving datacenter, so this restores a copy of the data there. The witness
+// is left in place and keeps voting: the new voter is added as a learner,
+// caught up through a snapshot and only then swapped for the dead voter in a
+// joint configuration change, so the range never has fewer live voting
+// replicas than it started with.
+func (rp ReplicaPlanner) replaceDeadVoterNearWitness(
+	ctx context.Context,
+	repl AllocatorReplica,
+	desc *roachpb.RangeDescriptor,
+	conf *roachpb.SpanConfig,
+	liveVoters, deadVoters, liveNonVoters []roachpb.ReplicaDescriptor,
+	allocatorPrio float64,
+) (op AllocationOp, stats ReplicateStats, _ error) {
+	liveWitnesses, _ := rp.storePool.LiveAndDeadReplicas(
+		desc.Replicas().WitnessDescriptors(), true, /* includeSuspectAndDrainingStores */
+	)
+	if len(liveWitnesses) == 0 || len(deadVoters) == 0 {
+		return nil, stats, errors.AssertionFailedf(
+			"range %s was identified as needing a witness promotion, but found "+
+				"%d live witnesses and %d dead voters", repl, len(liveWitnesses), len(deadVoters))
+	}
+	witness, deadVoter := liveWitnesses[0], deadVoters[0]
+	witnessLocality := rp.storePool.GetLocalitiesByStore(liveWitnesses)[witness.StoreID]
+
+	// The new voter can't be placed on a store that already has a replica of
+	// the range, which notably includes the witnesses themselves.
+	storeList, _, _ := rp.storePool.GetStoreList(storepool.StoreFilterThrottled)
+	var candidates []roachpb.StoreDescriptor
+	for _, store := range storeList.Stores {
+		if _, ok := desc.GetReplicaDescriptor(store.StoreID); ok {
+			continue
+		}
+		if witnessLocality.NonEmpty() && store.Locality().SharedPrefix(witnessLocality) == 0 {
+			continue
+		}
+		candidates = append(candidates, store)
+	}
+	newVoter, details := rp.allocator.AllocateTargetFromList(
+		ctx, rp.storePool, storepool.MakeStoreList(candidates), conf, liveVoters, liveNonVoters,
+		rp.allocator.ScorerOptions(ctx), rp.allocator.NewGoodCandidateSelector(),
+		false /* allowMultipleReplsPerNode */, allocatorimpl.VoterTarget,
+	)
+	if roachpb.Empty(newVoter) {
+		return nil, stats, errors.Errorf(
+			"no store in the locality of witness %s (%s) can replace dead voter %s",
+			witness, witnessLocality, deadVoter)
+	}
+
+	stats = stats.trackAddReplicaCount(allocatorimpl.VoterTarget)
+	stats = stats.trackRemoveMetric(allocatorimpl.VoterTarget, allocatorimpl.Dead)
+
+	log.KvDistribution.Infof(ctx, "replacing dead voter %+v with %+v next to witness %+v: %s",
+		deadVoter, newVoter, witness, rangeRaftProgress(repl.RaftStatus(), desc.Replicas().VoterDescriptors()))
+	ops := kvpb.MakeReplicationChanges(roachpb.ADD_VOTER, newVoter)
+	ops = append(ops, kvpb.MakeReplicationChanges(roachpb.REMOVE_VOTER, roachpb.ReplicationTarget{
+		NodeID:  deadVoter.NodeID,
+		StoreID: deadVoter.StoreID,
+	})...)
+
+	op = AllocationChangeReplicasOp{
+		LeaseholderStore:  repl.StoreID(),
+		Usage:             repl.RangeUsageInfo(),
+		Chgs:              ops,
+		AllocatorPriority: allocatorPrio,
+		Reason:            kvserverpb.ReasonStoreDead,
+		Details:           details,
+	}
+	return op, stats, nil
+}
+
 func (rp ReplicaPlanner) removeDecommissioning(
 	ctx context.Context,
 	repl AllocatorReplica,
@@ -70,6 +70,8 @@ func (rs ReplicateStats) trackAddReplicaCount(
 		rs.AddVoterReplicaCount++
 	case allocatorimpl.NonVoterTarget:
 		rs.AddNonVoterReplicaCount++
+	case allocatorimpl.WitnessTarget:
+		// Witnesses are only tracked in the aggregate count.
 	default:
 		panic(fmt.Sprintf("unsupported targetReplicaType: %v", targetType))
 	}
@@ -106,6 +108,8 @@ func (rs ReplicateStats) trackRemoveReplicaCount(
 		rs.RemoveVoterReplicaCount++
 	case allocatorimpl.NonVoterTarget:
 		rs.RemoveNonVoterReplicaCount++
+	case allocatorimpl.WitnessTarget:
+		// Witnesses are only tracked in the aggregate count.
 	default:
 		panic(fmt.Sprintf("unsupported targetReplicaType: %v", targetType))
 	}
@@ -123,6 +127,8 @@ func (rs ReplicateStats) trackRemoveDeadReplicaCount(
 		rs.RemoveDeadVoterReplicaCount++
 	case allocatorimpl.NonVoterTarget:
 		rs.RemoveDeadNonVoterReplicaCount++
+	case allocatorimpl.WitnessTarget:
+		// Witnesses are only tracked in the aggregate count.
 	default:
 		panic(fmt.Sprintf("unsupported targetReplicaType: %v", targetType))
 	}
@@ -129,9 +129,10 @@ func (b *appBatch) runPreAddTriggers(ctx context.Context, cmd *raftlog.Replicate
 	return nil
 }
 
-// addWriteBatch adds the command's writes to the batch.
+// addWriteBatch stages the command's WriteBatch in the given batch. On witness
+// replicas, only the writes to the local keyspace are staged.
 func (b *appBatch) addWriteBatch(
-	ctx context.Context, batch storage.Batch, cmd *replicatedCmd,
+	ctx context.Context, batch storage.Batch, cmd *replicatedCmd, witness bool,
 ) error {
 	wb := cmd.Cmd.WriteBatch
 	if wb == nil {
@@ -142,6 +143,12 @@ func (b *appBatch) addWriteBatch(
 	} else {
 		b.numMutations += mutations
 	}
+	if witness {
+		if err := applyWitnessBatchRepr(batch, wb.Data); err != nil {
+			return errors.Wrapf(err, "unable to apply WriteBatch on witness")
+		}
+		return nil
+	}
 	if err := batch.ApplyBatchRepr(wb.Data, false); err != nil {
 		return errors.Wrapf(err, "unable to apply WriteBatch")
 	}
@@ -153,6 +160,9 @@ type postAddEnv struct {
 	eng         storage.Engine // StateEngine
 	sideloaded  logstore.SideloadStorage
 	bulkLimiter *rate.Limiter
+	// witness is set when the replica applying the command is a witness, in
+	// which case SSTables carrying user data are not ingested.
+	witness bool
 }
 
 func (b *appBatch) runPostAddTriggers(
@@ -173,7 +183,7 @@ func (b *appBatch) runPostAddTriggers(
 	// NB: any command which has an AddSSTable is non-trivial and will be
 	// applied in its own batch so it's not possible that any other commands
 	// which precede this command can shadow writes from this SSTable.
-	if res.AddSSTable != nil {
+	if res.AddSSTable != nil && !env.witness {
 		copied := addSSTablePreApply(
 			ctx,
 			env,
@@ -194,7 +204,7 @@ func (b *appBatch) runPostAddTriggers(
 			b.numMutations += int(added)
 		}
 	}
-	if res.LinkExternalSSTable != nil {
+	if res.LinkExternalSSTable != nil && !env.witness {
 		linkExternalSStablePreApply(
 			ctx,
 			env,
@@ -264,14 +264,19 @@ func GC(
 
 	// Check if optional GC hint on the range is expired (e.g. delete operation is
 	// older than GC threshold) and remove it. Otherwise this range could be
-	// unnecessarily GC'd with high priority again.
+	// unnecessarily GC'd with high priority again. A GC threshold bump also
+	// records how far the range's history has been archived.
 	{
 		sl := MakeStateLoader(cArgs.EvalCtx)
 		hint, err := sl.LoadGCHint(ctx, readWriter)
 		if err != nil {
 			return result.Result{}, err
 		}
-		if hint.UpdateAfterGC(gcThreshold) {
+		updated := hint.UpdateAfterGC(gcThreshold)
+		if !args.Threshold.IsEmpty() {
+			updated = hint.SetArchivedThreshold(args.ArchivedThreshold) || updated
+		}
+		if updated {
 			// NB: Replicated.State can already contain GCThreshold from above. Make
 			// sure we don't accidentally remove it.
 			if res.Replicated.State == nil {
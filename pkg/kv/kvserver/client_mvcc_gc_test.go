@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud/nodelocal"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobsprotectedts"
@@ -281,3 +282,77 @@ SELECT count(*)
 		fmt.Sprintf(`SELECT * FROM crdb_internal.scan(crdb_internal.table_span($1)) AS OF SYSTEM TIME '%d'`,
 			protectedTime), tableID)
 }
+
+// TestMVCCGCArchiveReadBelowThreshold verifies that history collected by the
+// MVCC GC queue is archived into kv.gc.archive.uri and that reads below the
+// GC threshold are served from the archive.
+func TestMVCCGCArchiveReadBelowThreshold(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	defer nodelocal.ReplaceNodeLocalForTesting(t.TempDir())()
+
+	ctx := context.Background()
+	var args base.TestServerArgs
+	args.Knobs.SpanConfig = &spanconfig.TestingKnobs{
+		OverrideFallbackConf: func(config roachpb.SpanConfig) roachpb.SpanConfig {
+			overrideCfg := config
+			overrideCfg.GCPolicy.TTLSeconds = 1
+			return overrideCfg
+		},
+	}
+	s := serverutils.StartServerOnly(t, args)
+	defer s.Stopper().Stop(ctx)
+	db := s.DB()
+	sysDB := sqlutils.MakeSQLRunner(s.SystemLayer().SQLConn(t))
+	sysDB.Exec(t, "SET CLUSTER SETTING kv.gc.archive.uri = 'nodelocal://1/archive'")
+
+	key, err := s.ScratchRange()
+	require.NoError(t, err)
+	store, err := s.GetStores().(*kvserver.Stores).GetStore(s.GetFirstStoreID())
+	require.NoError(t, err)
+	repl := store.LookupReplica(roachpb.RKey(key))
+
+	keyA, keyB := key.Next(), key.Next().Next()
+	require.NoError(t, db.Put(ctx, keyA, "a1"))
+	require.NoError(t, db.Put(ctx, keyB, "b1"))
+	readTS := s.Clock().Now()
+	require.NoError(t, db.Put(ctx, keyA, "a2"))
+	_, err = db.Del(ctx, keyB)
+	require.NoError(t, err)
+
+	testutils.SucceedsSoon(t, func() error {
+		if err := store.ManualMVCCGC(repl); err != nil {
+			return err
+		}
+		if thresh := repl.GetGCThreshold(); !readTS.Less(thresh) {
+			return errors.Newf("GC threshold %s not above %s", thresh, readTS)
+		}
+		return nil
+	})
+	require.False(t, repl.GetGCHint().ArchivedThreshold.IsEmpty())
+
+	read := func() (*kv.Batch, error) {
+		b := &kv.Batch{}
+		b.Header.Timestamp = readTS
+		b.Get(keyA)
+		b.Scan(key, key.PrefixEnd())
+		return b, db.Run(ctx, b)
+	}
+	b, err := read()
+	require.NoError(t, err)
+	v, err := b.Results[0].Rows[0].Value.GetBytes()
+	require.NoError(t, err)
+	require.Equal(t, "a1", string(v))
+	var scanned []string
+	for _, row := range b.Results[1].Rows {
+		v, err := row.Value.GetBytes()
+		require.NoError(t, err)
+		scanned = append(scanned, string(v))
+	}
+	require.Equal(t, []string{"a1", "b1"}, scanned)
+
+	// Without the archive, the read is rejected.
+	sysDB.Exec(t, "SET CLUSTER SETTING kv.gc.archive.uri = ''")
+	_, err = read()
+	require.True(t, errors.HasType(err, (*kvpb.BatchTimestampBeforeGCError)(nil)), "%+v", err)
+}
@@ -147,3 +147,17 @@ var ClosedTimestampPropagationSlack = settings.RegisterDurationSetting(
 		"follower_read_timestamp().",
 	time.Second,
 )
+
+// FollowerReadMaxWait bounds how long a follower replica waits for its closed
+// timestamp to advance to the timestamp of a batch which sets
+// WaitForClosedTimestamp before redirecting it to the leaseholder. It also
+// determines how far ahead of the expected closed timestamp such batches may
+// read and still be routed to followers.
+var FollowerReadMaxWait = settings.RegisterDurationSetting(
+	settings.SystemVisible, // needed for routing in the DistSender
+	"kv.closed_timestamp.follower_reads.max_wait",
+	"the maximum amount of time a follower waits for its closed timestamp to "+
+		"catch up to a read at a causality token before redirecting the read to the leaseholder",
+	5*time.Second,
+	settings.NonNegativeDuration,
+)
@@ -50,6 +50,18 @@ var skipConsitencyQueueForExternalBytes = settings.RegisterBoolSetting(
 	true,
 )
 
+// ConsistencyRepairEnabled controls whether the consistency queue repairs
+// replicas which diverge from a quorum of their range instead of terminating
+// the nodes hosting them.
+var ConsistencyRepairEnabled = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"server.consistency_check.repair.enabled",
+	"if enabled, replicas found to be inconsistent with a quorum of their range are "+
+		"checkpointed, removed and replaced from a snapshot of a healthy replica, "+
+		"instead of their nodes being terminated",
+	false,
+)
+
 // consistencyCheckRateBurstFactor we use this to set the burst parameter on the
 // quotapool.RateLimiter. It seems overkill to provide a user setting for this,
 // so we use a factor to scale the burst setting based on the rate defined above.
@@ -448,6 +448,94 @@ func TestCheckConsistencyInconsistent(t *testing.T) {
 	require.NotEmpty(t, b)
 }
 
+// TestCheckConsistencyRepair verifies that with repair enabled, a replica which
+// diverges from the rest of its range is replaced rather than its node being
+// terminated.
+func TestCheckConsistencyRepair(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	// Test expects simple MVCC value encoding.
+	storage.DisableMetamorphicSimpleValueEncoding(t)
+
+	ctx := context.Background()
+	testKnobs := kvserver.StoreTestingKnobs{DisableConsistencyQueue: true}
+	testKnobs.ConsistencyTestingKnobs.OnBadChecksumFatal = func(s roachpb.StoreIdent) {
+		t.Errorf("unexpected termination of %s", s)
+	}
+	// The range has three replicas, so the fourth node is available to host
+	// the replacement of the diverging replica.
+	tc := testcluster.StartTestCluster(t, 4, base.TestClusterArgs{
+		ReplicationMode: base.ReplicationAuto,
+		ServerArgs: base.TestServerArgs{
+			Knobs: base.TestingKnobs{Store: &testKnobs},
+		},
+	})
+	defer tc.Stopper().Stop(ctx)
+	for i := 0; i < tc.NumServers(); i++ {
+		kvserver.ConsistencyRepairEnabled.Override(ctx, &tc.Server(i).ClusterSettings().SV, true)
+	}
+
+	store := tc.GetFirstStoreFromServer(t, 0)
+	for k, v := range map[string]string{"a": "b", "c": "d"} {
+		_, err := kv.SendWrapped(ctx, store.DB().NonTransactionalSender(),
+			putArgs([]byte(k), []byte(v)))
+		require.NoError(t, err.GoError())
+	}
+	runConsistencyCheck := func() *kvpb.CheckConsistencyResponse {
+		req := kvpb.CheckConsistencyRequest{
+			RequestHeader: kvpb.RequestHeader{Key: []byte("a"), EndKey: []byte("z")},
+			Mode:          kvpb.ChecksumMode_CHECK_VIA_QUEUE,
+		}
+		resp, err := kv.SendWrapped(ctx, store.DB().NonTransactionalSender(), &req)
+		require.NoError(t, err.GoError())
+		return resp.(*kvpb.CheckConsistencyResponse)
+	}
+
+	desc := tc.LookupRangeOrFatal(t, roachpb.Key("a"))
+	leaseholder, err := tc.FindRangeLeaseHolder(desc, nil /* hint */)
+	require.NoError(t, err)
+	var s2Replica roachpb.ReplicaDescriptor
+	for _, rDesc := range desc.Replicas().VoterDescriptors() {
+		if rDesc.StoreID != leaseholder.StoreID {
+			s2Replica = rDesc
+			break
+		}
+	}
+	s2Server, err := tc.FindMemberServer(s2Replica.StoreID)
+	require.NoError(t, err)
+	s2, err := s2Server.GetStores().(*kvserver.Stores).GetStore(s2Replica.StoreID)
+	require.NoError(t, err)
+
+	// Put an inconsistent key "e" to s2, and have the other replicas still
+	// agree.
+	var val roachpb.Value
+	val.SetInt(42)
+	_, err = storage.MVCCPut(ctx, s2.StateEngine(),
+		roachpb.Key("e"), tc.Server(0).Clock().Now(), val, storage.MVCCWriteOptions{})
+	require.NoError(t, err)
+
+	resp := runConsistencyCheck()
+	require.Len(t, resp.Result, 1)
+	require.Equal(t, kvpb.CheckConsistencyResponse_RANGE_INCONSISTENT, resp.Result[0].Status)
+
+	// The diverging replica is replaced by a new replica on the store which
+	// did not have one, after which the range is consistent again.
+	testutils.SucceedsSoon(t, func() error {
+		desc := tc.LookupRangeOrFatal(t, roachpb.Key("a"))
+		if _, ok := desc.GetReplicaDescriptor(s2.StoreID()); ok {
+			return errors.Errorf("diverging replica %s not yet removed: %s", s2Replica, desc)
+		}
+		if voters := desc.Replicas().VoterDescriptors(); len(voters) != 3 {
+			return errors.Errorf("diverging replica %s not yet replaced: %s", s2Replica, desc)
+		}
+		if status := runConsistencyCheck().Result[0].Status; status != kvpb.CheckConsistencyResponse_RANGE_CONSISTENT {
+			return errors.Errorf("range is %s", status)
+		}
+		return nil
+	})
+}
+
 // TestConsistencyQueueRecomputeStats is an end-to-end test of the mechanism CockroachDB
 // employs to adjust incorrect MVCCStats ("incorrect" meaning not an inconsistency of
 // these stats between replicas, but a delta between persisted stats and those one
@@ -0,0 +1,710 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package gc
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/storage/fs"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/objstorage/objstorageprovider"
+)
+
+// ArchiveURI is the external storage location into which the MVCC GC queue
+// archives history before collecting it.
+var ArchiveURI = settings.RegisterStringSetting(
+	settings.SystemOnly,
+	"kv.gc.archive.uri",
+	"if set, the MVCC GC queue exports every MVCC version that falls below the GC "+
+		"threshold into SSTs in this external storage location before collecting it, "+
+		"allowing historical reads below the GC threshold to be served from the archive",
+	"",
+	settings.WithReportable(false),
+)
+
+// ArchiveFallbackAfter bounds how long archiving failures can hold up the
+// collection of a range's history.
+var ArchiveFallbackAfter = settings.RegisterDurationSetting(
+	settings.SystemOnly,
+	"kv.gc.archive.fallback_after",
+	"if positive, the MVCC GC queue collects history without archiving it when archiving "+
+		"fails and the range's GC threshold lags the threshold it would advance to by more "+
+		"than this duration; reads below the GC threshold can not be served from the "+
+		"archive for the history collected this way",
+	0,
+	settings.NonNegativeDuration,
+)
+
+// errArchive marks the errors returned by Run when MVCC history could not be
+// archived.
+var errArchive = errors.New("MVCC history archival failed")
+
+// IsArchiveError returns whether err was returned by Run because MVCC history
+// could not be archived.
+func IsArchiveError(err error) bool {
+	return errors.Is(err, errArchive)
+}
+
+// ArchiveSegment identifies an archived SST. The SST contains every point and
+// range key version in Span with a timestamp in (From, To]. Segments of a span
+// form chains: every GC run of a range continues where the previous one left
+// off, so that the segments of a chain taken together contain the complete
+// history of the span between the start and the end of the chain.
+//
+// The first segment of a chain is a base segment, written by the first
+// archiving GC run of a range, the first one after history was collected
+// without being archived, or the first one to find keys in the segment's
+// partition. It additionally contains every version at or below From which was
+// still present, i.e. the versions visible at From that were not collected and
+// archived by earlier runs, so that a chain serves reads at timestamps above
+// its base's From.
+//
+// The SST of a segment may also contain versions above To. They are real
+// versions and are harmless to readers, but the segment does not guarantee
+// that all versions above To are present (see archiveReplicatedKeyRange).
+//
+// The span of a segment never crosses the boundary of an archive partition,
+// i.e. of a table or of the non-table keys of a tenant.
+type ArchiveSegment struct {
+	Span     roachpb.Span
+	From, To hlc.Timestamp
+	Base     bool
+}
+
+// archiveBaseSuffix is appended to the names of base segments.
+const archiveBaseSuffix = ".base"
+
+// archiveSpanSuffix is the suffix of the objects holding the spans of segments
+// whose bounds are too long to be embedded in their names.
+const archiveSpanSuffix = ".span"
+
+// archiveMaxNameKeyLen is the maximum length of a segment bound, relative to
+// the start of its partition, which is embedded in the segment's name. It
+// keeps names well within the length limits of object stores.
+const archiveMaxNameKeyLen = 96
+
+// archiveMaxReadPartitions is the maximum number of archive partitions, i.e.
+// of tables, that a single read against the archive may span.
+const archiveMaxReadPartitions = 64
+
+// archivePartition is a part of the keyspace whose segments are stored under
+// a common prefix: a table, or the non-table keys of a tenant. Readers only
+// list the prefixes of the partitions they read from.
+type archivePartition struct {
+	// prefix is the prefix of the names of the partition's segments.
+	prefix string
+	span   roachpb.Span
+}
+
+// tenantArchivePartition returns the archive partition of the non-table keys
+// of the tenant.
+func tenantArchivePartition(tenID roachpb.TenantID) archivePartition {
+	codec := keys.MakeSQLCodec(tenID)
+	return archivePartition{
+		prefix: fmt.Sprintf("%d/sys/", tenID.ToUint64()),
+		span:   roachpb.Span{Key: codec.TenantPrefix(), EndKey: codec.TablePrefix(0)},
+	}
+}
+
+// tableArchivePartition returns the archive partition of the table.
+func tableArchivePartition(tenID roachpb.TenantID, tableID uint32) archivePartition {
+	return archivePartition{
+		prefix: fmt.Sprintf("%d/%d/", tenID.ToUint64(), tableID),
+		span:   keys.MakeSQLCodec(tenID).TableSpan(tableID),
+	}
+}
+
+// makeArchivePartition returns the archive partition containing the key.
+func makeArchivePartition(key roachpb.Key) (archivePartition, error) {
+	_, tenID, err := keys.DecodeTenantPrefix(key)
+	if err != nil {
+		return archivePartition{}, errors.Wrapf(err, "no archive partition for key %s", key)
+	}
+	if p := tenantArchivePartition(tenID); p.span.ContainsKey(key) {
+		return p, nil
+	}
+	_, tableID, err := keys.MakeSQLCodec(tenID).DecodeTablePrefix(key)
+	if err != nil {
+		return archivePartition{}, errors.Wrapf(err, "no archive partition for key %s", key)
+	}
+	return tableArchivePartition(tenID, tableID), nil
+}
+
+// parseArchivePartition returns the archive partition whose prefix is
+// tenant/table/.
+func parseArchivePartition(tenant, table string) (archivePartition, error) {
+	id, err := strconv.ParseUint(tenant, 10, 64)
+	if err != nil {
+		return archivePartition{}, err
+	}
+	tenID, err := roachpb.MakeTenantID(id)
+	if err != nil {
+		return archivePartition{}, err
+	}
+	if table == "sys" {
+		return tenantArchivePartition(tenID), nil
+	}
+	tableID, err := strconv.ParseUint(table, 10, 32)
+	if err != nil {
+		return archivePartition{}, err
+	}
+	return tableArchivePartition(tenID, uint32(tableID)), nil
+}
+
+// segmentNames returns the name of the object of the segment, which must be
+// contained in the partition. Names start with the prefix of the partition,
+// followed by the time bounds of the segment, so that the segments of a
+// partition sort by time. The key bounds of the segment follow, hex-encoded
+// relative to the start of the partition, with an empty end key denoting the
+// end of the partition. If either of them is longer than archiveMaxNameKeyLen,
+// they are replaced by a digest and the span of the segment is stored in a
+// separate object, whose name is returned as well.
+func (p archivePartition) segmentNames(s ArchiveSegment) (name, spanName string) {
+	start := s.Span.Key[len(p.span.Key):]
+	var end []byte
+	if !s.Span.EndKey.Equal(p.span.EndKey) {
+		end = s.Span.EndKey[len(p.span.Key):]
+	}
+	var bounds string
+	if len(start) <= archiveMaxNameKeyLen && len(end) <= archiveMaxNameKeyLen {
+		bounds = fmt.Sprintf("%x-%x", start, end)
+	} else {
+		h := sha256.New()
+		_, _ = h.Write(s.Span.Key)
+		_, _ = h.Write(s.Span.EndKey)
+		bounds = fmt.Sprintf("h%x", h.Sum(nil)[:16])
+	}
+	stem := fmt.Sprintf("%s%020d.%010d-%020d.%010d-%s", p.prefix,
+		s.From.WallTime, s.From.Logical, s.To.WallTime, s.To.Logical, bounds)
+	if s.Base {
+		stem += archiveBaseSuffix
+	}
+	if bounds[0] == 'h' {
+		spanName = stem + archiveSpanSuffix
+	}
+	return stem + ".sst", spanName
+}
+
+// ParseArchiveSegmentName is the inverse of the naming of segment objects. If
+// the span of the segment is not embedded in its name, the returned segment
+// has an empty span, and the name of the object holding the span is returned.
+func ParseArchiveSegmentName(name string) (_ ArchiveSegment, spanName string, _ error) {
+	var s ArchiveSegment
+	tenant, rest, ok1 := strings.Cut(name, "/")
+	table, rest, ok2 := strings.Cut(rest, "/")
+	if !ok1 || !ok2 || !strings.HasSuffix(rest, ".sst") {
+		return s, "", errors.Newf("invalid archive segment name %q", name)
+	}
+	stem := strings.TrimSuffix(name, ".sst")
+	rest = strings.TrimSuffix(rest, ".sst")
+	if strings.HasSuffix(rest, archiveBaseSuffix) {
+		s.Base = true
+		rest = strings.TrimSuffix(rest, archiveBaseSuffix)
+	}
+	parts := strings.Split(rest, "-")
+	if len(parts) != 3 && len(parts) != 4 {
+		return s, "", errors.Newf("invalid archive segment name %q", name)
+	}
+	if _, err := fmt.Sscanf(parts[0], "%d.%d", &s.From.WallTime, &s.From.Logical); err != nil {
+		return s, "", errors.Wrapf(err, "invalid archive segment name %q", name)
+	}
+	if _, err := fmt.Sscanf(parts[1], "%d.%d", &s.To.WallTime, &s.To.Logical); err != nil {
+		return s, "", errors.Wrapf(err, "invalid archive segment name %q", name)
+	}
+	if len(parts) == 3 {
+		if !strings.HasPrefix(parts[2], "h") {
+			return s, "", errors.Newf("invalid archive segment name %q", name)
+		}
+		return s, stem + archiveSpanSuffix, nil
+	}
+	p, err := parseArchivePartition(tenant, table)
+	if err != nil {
+		return s, "", errors.Wrapf(err, "invalid archive segment name %q", name)
+	}
+	start, err := hex.DecodeString(parts[2])
+	if err != nil {
+		return s, "", errors.Wrapf(err, "invalid archive segment name %q", name)
+	}
+	end, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return s, "", errors.Wrapf(err, "invalid archive segment name %q", name)
+	}
+	s.Span.Key = append(p.span.Key[:len(p.span.Key):len(p.span.Key)], start...)
+	s.Span.EndKey = p.span.EndKey
+	if len(end) > 0 {
+		s.Span.EndKey = append(p.span.Key[:len(p.span.Key):len(p.span.Key)], end...)
+	}
+	return s, "", nil
+}
+
+// Archiver persists the SSTs produced when archiving MVCC history.
+type Archiver interface {
+	// NewSegmentWriter returns a writer for the SST of the specified segment.
+	// The segment must be durable once Close returns successfully, and must
+	// not become visible if ctx is canceled before Close is called.
+	NewSegmentWriter(ctx context.Context, seg ArchiveSegment) (io.WriteCloser, error)
+}
+
+// ArchiveOptions configures archival of MVCC history during a GC run.
+type ArchiveOptions struct {
+	Archiver Archiver
+	Settings *cluster.Settings
+	// PrevThreshold is the GC threshold of the range before this run.
+	PrevThreshold hlc.Timestamp
+	// ArchivedThreshold is the timestamp up to which the history of the range
+	// has been archived, as recorded in its GC hint. If empty, the run starts a
+	// new chain of segments with a base segment.
+	ArchivedThreshold hlc.Timestamp
+}
+
+// canCollectWithoutArchiving returns whether a GC run advancing the threshold
+// to the specified one may collect history without archiving it, because
+// archiving failures have held up collection for longer than allowed by
+// kv.gc.archive.fallback_after.
+func (o ArchiveOptions) canCollectWithoutArchiving(threshold hlc.Timestamp) bool {
+	fallbackAfter := ArchiveFallbackAfter.Get(&o.Settings.SV)
+	if fallbackAfter == 0 || o.PrevThreshold.IsEmpty() {
+		return false
+	}
+	return o.PrevThreshold.Add(fallbackAfter.Nanoseconds(), 0).Less(threshold)
+}
+
+// externalArchiver is an Archiver which writes segments to external storage.
+type externalArchiver struct {
+	es cloud.ExternalStorage
+}
+
+// MakeExternalArchiver returns an Archiver which writes segments to the
+// specified external storage.
+func MakeExternalArchiver(es cloud.ExternalStorage) Archiver {
+	return externalArchiver{es: es}
+}
+
+// NewSegmentWriter implements the Archiver interface.
+func (a externalArchiver) NewSegmentWriter(
+	ctx context.Context, seg ArchiveSegment,
+) (io.WriteCloser, error) {
+	p, err := makeArchivePartition(seg.Span.Key)
+	if err != nil {
+		return nil, err
+	}
+	name, spanName := p.segmentNames(seg)
+	if spanName != "" {
+		// The span is written first, since readers only consider segments whose
+		// SST exists.
+		b, err := protoutil.Marshal(&seg.Span)
+		if err != nil {
+			return nil, err
+		}
+		if err := cloud.WriteFile(ctx, a.es, spanName, bytes.NewReader(b)); err != nil {
+			return nil, err
+		}
+	}
+	return a.es.Writer(ctx, name)
+}
+
+// archiveReplicatedKeyRange exports the point and range key versions in the
+// user key span of the range which the GC run up to threshold may collect and
+// which have not been archived yet into archive segments, one for each archive
+// partition of the range that contains any key. Returns the timestamp up to
+// which the history of the range has been archived, which is to be recorded
+// in the range's GC hint. The segments are durable once the function returns,
+// so it must be called before any of the data they cover is collected.
+//
+// If opts.ArchivedThreshold is set, the segments contain the versions in
+// (opts.ArchivedThreshold, threshold]. Otherwise, they are base segments which
+// contain all versions at or below threshold, and chains only serve reads
+// above opts.PrevThreshold: versions at or below it may have been collected
+// without being archived.
+//
+// Provisional values of intents are not archived, since they may still be
+// committed at a different timestamp or aborted. The segments, and the
+// returned timestamp, therefore stop short of the oldest intent in the range.
+// Versions above it are archived nonetheless since the run may collect them,
+// and the next run archives from that point onwards again, picking up the
+// values of intents which were committed in the meantime.
+func archiveReplicatedKeyRange(
+	ctx context.Context,
+	desc *roachpb.RangeDescriptor,
+	snap storage.Reader,
+	threshold hlc.Timestamp,
+	opts ArchiveOptions,
+) (hlc.Timestamp, error) {
+	span := desc.KeySpan().AsRawSpanWithNoLocals()
+	from, base := opts.ArchivedThreshold, opts.ArchivedThreshold.IsEmpty()
+	if base {
+		from = opts.PrevThreshold
+	}
+	if threshold.LessEq(from) {
+		return opts.ArchivedThreshold, nil
+	}
+	to := threshold
+	minIntentTS, err := minIntentTimestamp(ctx, snap, span)
+	if err != nil {
+		return hlc.Timestamp{}, err
+	}
+	if minIntentTS.IsSet() && minIntentTS.LessEq(threshold) {
+		to = minIntentTS.Prev()
+		to.Forward(from)
+	}
+
+	iter, err := snap.NewMVCCIterator(ctx, storage.MVCCKeyIterKind, storage.IterOptions{
+		LowerBound:   span.Key,
+		UpperBound:   span.EndKey,
+		KeyTypes:     storage.IterKeyTypePointsAndRanges,
+		ReadCategory: fs.MVCCGCReadCategory,
+	})
+	if err != nil {
+		return hlc.Timestamp{}, err
+	}
+	defer iter.Close()
+
+	// A segment is written for every partition which contains any key, even if
+	// none of its versions fall into the window, so that the chains of the
+	// partition are not broken.
+	for key := span.Key; ; {
+		iter.SeekGE(storage.MakeMVCCMetadataKey(key))
+		if ok, err := iter.Valid(); err != nil {
+			return hlc.Timestamp{}, err
+		} else if !ok {
+			break
+		}
+		p, err := makeArchivePartition(iter.UnsafeKey().Key)
+		if err != nil {
+			return hlc.Timestamp{}, err
+		}
+		seg := ArchiveSegment{Span: p.span.Intersect(span), From: from, To: to, Base: base}
+		if !seg.Base {
+			// A partition without versions at or below From had no keys when
+			// the previous run archived the range, e.g. because the table was
+			// created since, since no version can be written at or below the GC
+			// threshold. Any versions visible at From were collected, and hence
+			// archived, by earlier runs, so the segment starts a chain of the
+			// partition that serves reads above From.
+			present, err := hasVersionAtOrBelow(ctx, snap, seg.Span, from)
+			if err != nil {
+				return hlc.Timestamp{}, err
+			}
+			seg.Base = !present
+		}
+		if err := archiveSegment(ctx, snap, seg, threshold, opts); err != nil {
+			return hlc.Timestamp{}, err
+		}
+		key = seg.Span.EndKey
+	}
+	return to, nil
+}
+
+// archiveSegment writes the versions in the segment's span which fall into the
+// segment's window to its SST. The segment is written even if it is empty. It
+// is abandoned by canceling its context if archival fails.
+func archiveSegment(
+	ctx context.Context,
+	snap storage.Reader,
+	seg ArchiveSegment,
+	threshold hlc.Timestamp,
+	opts ArchiveOptions,
+) error {
+	inWindow := func(ts hlc.Timestamp) bool {
+		return (seg.Base || seg.From.Less(ts)) && ts.LessEq(threshold)
+	}
+
+	iter, err := snap.NewMVCCIterator(ctx, storage.MVCCKeyAndIntentsIterKind, storage.IterOptions{
+		LowerBound:   seg.Span.Key,
+		UpperBound:   seg.Span.EndKey,
+		KeyTypes:     storage.IterKeyTypePointsAndRanges,
+		ReadCategory: fs.MVCCGCReadCategory,
+	})
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	w, err := opts.Archiver.NewSegmentWriter(ctx, seg)
+	if err != nil {
+		return err
+	}
+	sst := storage.MakeIngestionSSTWriter(ctx, opts.Settings,
+		objstorageprovider.NewRemoteWritable(w))
+	defer func() {
+		if w != nil {
+			cancel()
+			sst.Close()
+			_ = w.Close()
+		}
+	}()
+
+	var numVersions int
+	var skipProvisional bool
+	for iter.SeekGE(storage.MakeMVCCMetadataKey(seg.Span.Key)); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+		hasPoint, hasRange := iter.HasPointAndRange()
+		if hasRange && iter.RangeKeyChanged() {
+			rangeKeys := iter.RangeKeys().Clone()
+			if seg.Base {
+				rangeKeys.Trim(hlc.MinTimestamp, threshold)
+			} else {
+				rangeKeys.Trim(seg.From.Next(), threshold)
+			}
+			for _, v := range rangeKeys.Versions {
+				if err := sst.PutRawMVCCRangeKey(rangeKeys.AsRangeKey(v), v.Value); err != nil {
+					return err
+				}
+				numVersions++
+			}
+		}
+		if !hasPoint {
+			continue
+		}
+		key := iter.UnsafeKey()
+		if !key.IsValue() {
+			// An intent is immediately followed by its provisional value.
+			skipProvisional = true
+			continue
+		}
+		if skipProvisional {
+			skipProvisional = false
+			continue
+		}
+		if !inWindow(key.Timestamp) {
+			continue
+		}
+		v, err := iter.UnsafeValue()
+		if err != nil {
+			return err
+		}
+		if err := sst.PutRawMVCC(key, v); err != nil {
+			return err
+		}
+		numVersions++
+	}
+	if err := sst.Finish(); err != nil {
+		return err
+	}
+	err = w.Close()
+	w = nil
+	if err != nil {
+		return errors.Wrapf(err, "writing archive segment of %s in (%s, %s]", seg.Span, seg.From, seg.To)
+	}
+	log.Eventf(ctx, "archived %d versions of %s in (%s, %s]", numVersions, seg.Span, seg.From, seg.To)
+	return nil
+}
+
+// hasVersionAtOrBelow returns whether the specified span contains any point or
+// range key version at or below the specified timestamp.
+func hasVersionAtOrBelow(
+	ctx context.Context, reader storage.Reader, span roachpb.Span, ts hlc.Timestamp,
+) (bool, error) {
+	iter, err := reader.NewMVCCIterator(ctx, storage.MVCCKeyIterKind, storage.IterOptions{
+		LowerBound:   span.Key,
+		UpperBound:   span.EndKey,
+		KeyTypes:     storage.IterKeyTypePointsAndRanges,
+		MinTimestamp: hlc.MinTimestamp,
+		MaxTimestamp: ts,
+		ReadCategory: fs.MVCCGCReadCategory,
+	})
+	if err != nil {
+		return false, err
+	}
+	defer iter.Close()
+	// Range keys are not subject to the time bounds of the iterator.
+	for iter.SeekGE(storage.MakeMVCCMetadataKey(span.Key)); ; iter.Next() {
+		if ok, err := iter.Valid(); !ok {
+			return false, err
+		}
+		hasPoint, hasRange := iter.HasPointAndRange()
+		if hasPoint || (hasRange && iter.RangeKeys().Oldest().LessEq(ts)) {
+			return true, nil
+		}
+	}
+}
+
+// minIntentTimestamp returns the timestamp of the oldest intent in the
+// specified span, or an empty timestamp if there are none.
+func minIntentTimestamp(
+	ctx context.Context, reader storage.Reader, span roachpb.Span,
+) (hlc.Timestamp, error) {
+	ltStart, _ := keys.LockTableSingleKey(span.Key, nil)
+	ltEnd, _ := keys.LockTableSingleKey(span.EndKey, nil)
+	iter, err := storage.NewLockTableIterator(ctx, reader, storage.LockTableIteratorOptions{
+		LowerBound:   ltStart,
+		UpperBound:   ltEnd,
+		MatchMinStr:  lock.Intent,
+		ReadCategory: fs.MVCCGCReadCategory,
+	})
+	if err != nil {
+		return hlc.Timestamp{}, err
+	}
+	defer iter.Close()
+
+	var minTS hlc.Timestamp
+	var ok bool
+	for ok, err = iter.SeekEngineKeyGE(storage.EngineKey{Key: ltStart}); ok; ok, err = iter.NextEngineKey() {
+		var meta enginepb.MVCCMetadata
+		if err := iter.ValueProto(&meta); err != nil {
+			return hlc.Timestamp{}, err
+		}
+		if ts := meta.Timestamp.ToTimestamp(); minTS.IsEmpty() || ts.Less(minTS) {
+			minTS = ts
+		}
+	}
+	return minTS, err
+}
+
+// archiveCovers returns whether the specified segments contain the complete
+// history of span as of asOf, i.e. whether every point in the span is covered
+// by a base segment with a From below asOf and a chain of segments extending
+// it up to at least asOf.
+func archiveCovers(segs []ArchiveSegment, span roachpb.Span, asOf hlc.Timestamp) bool {
+	bounds := []roachpb.Key{span.Key, span.EndKey}
+	for _, seg := range segs {
+		for _, k := range []roachpb.Key{seg.Span.Key, seg.Span.EndKey} {
+			if span.ContainsKey(k) {
+				bounds = append(bounds, k)
+			}
+		}
+	}
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i].Compare(bounds[j]) < 0 })
+	for i := 1; i < len(bounds); i++ {
+		sub := roachpb.Span{Key: bounds[i-1], EndKey: bounds[i]}
+		if !sub.Valid() {
+			continue
+		}
+		// Find the most recent point up to which a chain valid at asOf is
+		// complete.
+		var complete hlc.Timestamp
+		for _, seg := range segs {
+			if seg.Base && seg.Span.Contains(sub) && seg.From.Less(asOf) {
+				complete.Forward(seg.To)
+			}
+		}
+		if complete.IsEmpty() {
+			return false
+		}
+		for extended := true; extended && complete.Less(asOf); {
+			extended = false
+			for _, seg := range segs {
+				if !seg.Base && seg.Span.Contains(sub) && seg.From.LessEq(complete) &&
+					complete.Less(seg.To) {
+					complete = seg.To
+					extended = true
+				}
+			}
+		}
+		if complete.Less(asOf) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewArchiveIterator returns a read-only iterator over the state of the
+// specified span as of the specified timestamp, as recorded in the archive.
+// The iterator surfaces the latest live version of each key at or below the
+// timestamp. Returns an error if the archive does not contain the complete
+// history of the span as of the timestamp. Only the segments of the archive
+// partitions overlapping the span are listed, and the span may overlap at most
+// archiveMaxReadPartitions of them.
+func NewArchiveIterator(
+	ctx context.Context, es cloud.ExternalStorage, span roachpb.Span, asOf hlc.Timestamp,
+) (storage.SimpleMVCCIterator, error) {
+	var segs []ArchiveSegment
+	var files []storage.StoreFile
+	for key, n := span.Key, 0; key.Compare(span.EndKey) < 0; n++ {
+		if n == archiveMaxReadPartitions {
+			return nil, errors.Newf("%s spans more than %d tables of the MVCC history archive",
+				span, archiveMaxReadPartitions)
+		}
+		p, err := makeArchivePartition(key)
+		if err != nil {
+			return nil, err
+		}
+		if err := es.List(ctx, p.prefix, cloud.ListOptions{}, func(name string) error {
+			name = p.prefix + strings.TrimPrefix(name, "/")
+			seg, spanName, err := ParseArchiveSegmentName(name)
+			if err != nil {
+				// Ignore the objects holding segment spans and unrelated objects
+				// in the archive location.
+				return nil //nolint:returnerrcheck
+			}
+			if !seg.From.Less(asOf) {
+				return nil
+			}
+			if spanName != "" {
+				if seg.Span, err = readArchiveSegmentSpan(ctx, es, spanName); err != nil {
+					return err
+				}
+			}
+			if seg.Span.Overlaps(span) {
+				segs = append(segs, seg)
+				files = append(files, storage.StoreFile{Store: es, FilePath: name})
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		key = p.span.EndKey
+	}
+	if !archiveCovers(segs, span, asOf) {
+		return nil, errors.Newf("archived history for %s is incomplete as of %s", span, asOf)
+	}
+	iter, err := storage.ExternalSSTReader(ctx, files, nil /* encryption */, storage.IterOptions{
+		KeyTypes:   storage.IterKeyTypePointsAndRanges,
+		LowerBound: span.Key,
+		UpperBound: span.EndKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return storage.NewReadAsOfIterator(iter, asOf), nil
+}
+
+// readArchiveSegmentSpan reads the span of a segment whose bounds are not
+// embedded in its name.
+func readArchiveSegmentSpan(
+	ctx context.Context, es cloud.ExternalStorage, name string,
+) (roachpb.Span, error) {
+	r, _, err := es.ReadFile(ctx, name, cloud.ReadOptions{NoFileSize: true})
+	if err != nil {
+		return roachpb.Span{}, errors.Wrapf(err, "reading span of archive segment %s", name)
+	}
+	defer r.Close(ctx)
+	b, err := ioctx.ReadAll(ctx, r)
+	if err != nil {
+		return roachpb.Span{}, errors.Wrapf(err, "reading span of archive segment %s", name)
+	}
+	var span roachpb.Span
+	if err := protoutil.Unmarshal(b, &span); err != nil {
+		return roachpb.Span{}, errors.Wrapf(err, "reading span of archive segment %s", name)
+	}
+	return span, nil
+}
@@ -0,0 +1,357 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package gc
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudpb"
+	"github.com/cockroachdb/cockroach/pkg/cloud/nodelocal"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveSegmentName(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	table := keys.SystemSQLCodec.TableSpan(42)
+	tenant := keys.MakeSQLCodec(roachpb.MustMakeTenantID(5)).TenantPrefix()
+	for _, seg := range []ArchiveSegment{{
+		Span: roachpb.Span{Key: append(table.Key.Clone(), "a/b-c"...), EndKey: table.EndKey},
+		From: hlc.Timestamp{WallTime: 10, Logical: 1},
+		To:   hlc.Timestamp{WallTime: 1e18},
+	}, {
+		Span: roachpb.Span{Key: tenant, EndKey: append(tenant.Clone(), "z.base"...)},
+		From: hlc.Timestamp{WallTime: 10},
+		To:   hlc.Timestamp{WallTime: 20},
+		Base: true,
+	}} {
+		p, err := makeArchivePartition(seg.Span.Key)
+		require.NoError(t, err)
+		name, spanName := p.segmentNames(seg)
+		require.Empty(t, spanName)
+		parsed, parsedSpanName, err := ParseArchiveSegmentName(name)
+		require.NoError(t, err)
+		require.Empty(t, parsedSpanName)
+		require.Equal(t, seg, parsed)
+	}
+
+	// Long bounds are not embedded in the name.
+	seg := ArchiveSegment{
+		Span: roachpb.Span{Key: table.Key, EndKey: append(table.Key.Clone(), strings.Repeat("x", 200)...)},
+		From: hlc.Timestamp{WallTime: 10},
+		To:   hlc.Timestamp{WallTime: 20},
+	}
+	p, err := makeArchivePartition(seg.Span.Key)
+	require.NoError(t, err)
+	name, spanName := p.segmentNames(seg)
+	require.NotEmpty(t, spanName)
+	require.Less(t, len(name), 128)
+	parsed, parsedSpanName, err := ParseArchiveSegmentName(name)
+	require.NoError(t, err)
+	require.Equal(t, spanName, parsedSpanName)
+	require.Equal(t, ArchiveSegment{From: seg.From, To: seg.To}, parsed)
+
+	_, _, err = ParseArchiveSegmentName("unrelated.sst")
+	require.Error(t, err)
+	_, _, err = ParseArchiveSegmentName(spanName)
+	require.Error(t, err)
+}
+
+func TestArchiveCovers(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	sp := func(start, end string) roachpb.Span {
+		return roachpb.Span{Key: roachpb.Key(start), EndKey: roachpb.Key(end)}
+	}
+	ts := func(wall int64) hlc.Timestamp {
+		return hlc.Timestamp{WallTime: wall}
+	}
+	base := func(span roachpb.Span, from, to int64) ArchiveSegment {
+		return ArchiveSegment{Span: span, From: ts(from), To: ts(to), Base: true}
+	}
+	seg := func(span roachpb.Span, from, to int64) ArchiveSegment {
+		return ArchiveSegment{Span: span, From: ts(from), To: ts(to)}
+	}
+
+	for _, tc := range []struct {
+		name string
+		segs []ArchiveSegment
+		asOf int64
+		exp  bool
+	}{
+		{name: "empty", asOf: 3, exp: false},
+		{name: "base", segs: []ArchiveSegment{base(sp("a", "z"), 1, 5)}, asOf: 3, exp: true},
+		{name: "base upper bound", segs: []ArchiveSegment{base(sp("a", "z"), 1, 5)}, asOf: 5, exp: true},
+		{name: "below base", segs: []ArchiveSegment{base(sp("a", "z"), 1, 5)}, asOf: 1, exp: false},
+		{name: "above base", segs: []ArchiveSegment{base(sp("a", "z"), 1, 5)}, asOf: 6, exp: false},
+		{name: "no base", segs: []ArchiveSegment{seg(sp("a", "z"), 1, 5)}, asOf: 3, exp: false},
+		{name: "chain", segs: []ArchiveSegment{
+			base(sp("a", "z"), 1, 5), seg(sp("a", "z"), 5, 8),
+		}, asOf: 7, exp: true},
+		{name: "gap in chain", segs: []ArchiveSegment{
+			base(sp("a", "z"), 1, 5), seg(sp("a", "z"), 6, 8),
+		}, asOf: 7, exp: false},
+		{name: "below gap in chain", segs: []ArchiveSegment{
+			base(sp("a", "z"), 1, 5), seg(sp("a", "z"), 6, 8),
+		}, asOf: 4, exp: true},
+		{name: "partial span", segs: []ArchiveSegment{base(sp("a", "m"), 1, 5)}, asOf: 3, exp: false},
+		{name: "split", segs: []ArchiveSegment{
+			base(sp("a", "z"), 1, 5), seg(sp("a", "m"), 5, 8), seg(sp("m", "z"), 5, 7),
+		}, asOf: 7, exp: true},
+		{name: "split lagging side", segs: []ArchiveSegment{
+			base(sp("a", "z"), 1, 5), seg(sp("a", "m"), 5, 8), seg(sp("m", "z"), 5, 7),
+		}, asOf: 8, exp: false},
+		// After a merge, the merged range archives from the lower of the
+		// archived thresholds of the two sides.
+		{name: "merge", segs: []ArchiveSegment{
+			base(sp("a", "m"), 1, 5), base(sp("m", "z"), 1, 3), seg(sp("a", "z"), 3, 8),
+		}, asOf: 7, exp: true},
+		{name: "merge from higher threshold", segs: []ArchiveSegment{
+			base(sp("a", "m"), 1, 5), base(sp("m", "z"), 1, 3), seg(sp("a", "z"), 5, 8),
+		}, asOf: 7, exp: false},
+		{name: "new chain", segs: []ArchiveSegment{
+			base(sp("a", "z"), 1, 5), base(sp("a", "z"), 6, 8),
+		}, asOf: 7, exp: true},
+		{name: "between chains", segs: []ArchiveSegment{
+			base(sp("a", "z"), 1, 5), base(sp("a", "z"), 6, 8),
+		}, asOf: 6, exp: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.exp, archiveCovers(tc.segs, sp("c", "x"), ts(tc.asOf)))
+		})
+	}
+}
+
+func TestArchiveReplicatedKeyRange(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	eng := storage.NewDefaultInMemForTesting()
+	defer eng.Close()
+
+	prefix := keys.SystemSQLCodec.TablePrefix(42)
+	key := func(s string) roachpb.Key {
+		return append(prefix[:len(prefix):len(prefix)], s...)
+	}
+	ts := func(wall int64) hlc.Timestamp {
+		return hlc.Timestamp{WallTime: wall}
+	}
+	put := func(k string, wall int64, v string, txn *roachpb.Transaction) {
+		_, err := storage.MVCCPut(ctx, eng, key(k), ts(wall), roachpb.MakeValueFromString(v),
+			storage.MVCCWriteOptions{Txn: txn})
+		require.NoError(t, err)
+	}
+	put("a", 1, "a1", nil)
+	put("a", 3, "a3", nil)
+	put("a", 6, "a6", nil)
+	put("b", 2, "b2", nil)
+	txn := roachpb.MakeTransaction("txn", key("b"), isolation.Serializable,
+		roachpb.NormalUserPriority, ts(3), 1000, 0, 0, false /* omitInRangefeeds */)
+	put("b", 3, "b3", &txn)
+	put("c", 2, "c2", nil)
+	require.NoError(t, eng.PutMVCCRangeKey(storage.MVCCRangeKey{
+		StartKey: key("c"), EndKey: key("d"), Timestamp: ts(4),
+	}, storage.MVCCValue{}))
+
+	desc := &roachpb.RangeDescriptor{
+		RangeID:  1,
+		StartKey: roachpb.RKey(prefix),
+		EndKey:   roachpb.RKey(prefix.PrefixEnd()),
+	}
+	es := nodelocal.TestingMakeNodelocalStorage(t.TempDir(), st, cloudpb.ExternalStorage{})
+	defer es.Close()
+
+	archive := func(prev, archived, threshold hlc.Timestamp) hlc.Timestamp {
+		snap := eng.NewSnapshot()
+		defer snap.Close()
+		res, err := archiveReplicatedKeyRange(ctx, desc, snap, threshold, ArchiveOptions{
+			Archiver:          MakeExternalArchiver(es),
+			Settings:          st,
+			PrevThreshold:     prev,
+			ArchivedThreshold: archived,
+		})
+		require.NoError(t, err)
+		return res
+	}
+	read := func(asOf hlc.Timestamp) map[string]string {
+		iter, err := NewArchiveIterator(ctx, es, desc.KeySpan().AsRawSpanWithNoLocals(), asOf)
+		require.NoError(t, err)
+		defer iter.Close()
+		res := make(map[string]string)
+		for iter.SeekGE(storage.MakeMVCCMetadataKey(prefix)); ; iter.NextKey() {
+			ok, err := iter.Valid()
+			require.NoError(t, err)
+			if !ok {
+				break
+			}
+			raw, err := iter.UnsafeValue()
+			require.NoError(t, err)
+			v, err := storage.DecodeMVCCValue(raw)
+			require.NoError(t, err)
+			b, err := v.Value.GetBytes()
+			require.NoError(t, err)
+			res[string(iter.UnsafeKey().Key[len(prefix):])] = string(b)
+		}
+		return res
+	}
+	numSegments := func() int {
+		var n int
+		require.NoError(t, es.List(ctx, "", cloud.ListOptions{}, func(string) error {
+			n++
+			return nil
+		}))
+		return n
+	}
+
+	// The first run writes a base segment. The intent at ts 3 holds it back,
+	// since it may still commit below the new GC threshold.
+	archived := archive(ts(1), hlc.Timestamp{}, ts(5))
+	require.Equal(t, ts(3).Prev(), archived)
+	require.Equal(t, map[string]string{"a": "a1", "b": "b2", "c": "c2"}, read(ts(2)))
+	_, err := NewArchiveIterator(ctx, es, desc.KeySpan().AsRawSpanWithNoLocals(), ts(1))
+	require.Error(t, err)
+	_, err = NewArchiveIterator(ctx, es, desc.KeySpan().AsRawSpanWithNoLocals(), ts(5))
+	require.Error(t, err)
+
+	// Once the intent is committed, the next run picks up its value.
+	update := roachpb.MakeLockUpdate(&txn, roachpb.Span{Key: key("b")})
+	update.Status = roachpb.COMMITTED
+	_, _, _, _, err = storage.MVCCResolveWriteIntent(ctx, eng, nil, update,
+		storage.MVCCResolveWriteIntentOptions{})
+	require.NoError(t, err)
+	archived = archive(ts(5), archived, ts(7))
+	require.Equal(t, ts(7), archived)
+	require.Equal(t, map[string]string{"a": "a1", "b": "b2", "c": "c2"}, read(ts(2)))
+	require.Equal(t, map[string]string{"a": "a3", "b": "b3"}, read(ts(5)))
+	require.Equal(t, map[string]string{"a": "a6", "b": "b3"}, read(ts(7)))
+	require.Equal(t, 2, numSegments())
+
+	// Nothing is archived if the threshold does not advance.
+	require.Equal(t, ts(7), archive(ts(7), ts(7), ts(7)))
+	require.Equal(t, 2, numSegments())
+}
+
+// TestArchivePartitions verifies that ranges are archived in a segment for
+// every table they contain keys of, and that reads only consult the segments
+// of the tables they read from.
+func TestArchivePartitions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	eng := storage.NewDefaultInMemForTesting()
+	defer eng.Close()
+
+	// The range starts at a key too long to be embedded in segment names.
+	start := append(keys.SystemSQLCodec.TablePrefix(42), strings.Repeat("k", 200)...)
+	desc := &roachpb.RangeDescriptor{
+		RangeID:  1,
+		StartKey: roachpb.RKey(start),
+		EndKey:   roachpb.RKey(keys.SystemSQLCodec.TablePrefix(46)),
+	}
+	ts := func(wall int64) hlc.Timestamp {
+		return hlc.Timestamp{WallTime: wall}
+	}
+	put := func(k roachpb.Key, wall int64, v string) {
+		_, err := storage.MVCCPut(ctx, eng, k, ts(wall), roachpb.MakeValueFromString(v),
+			storage.MVCCWriteOptions{})
+		require.NoError(t, err)
+	}
+	tableSpan := func(id uint32) roachpb.Span {
+		return keys.SystemSQLCodec.TableSpan(id).Intersect(desc.KeySpan().AsRawSpanWithNoLocals())
+	}
+	es := nodelocal.TestingMakeNodelocalStorage(t.TempDir(), st, cloudpb.ExternalStorage{})
+	defer es.Close()
+
+	archive := func(prev, archived, threshold hlc.Timestamp) {
+		snap := eng.NewSnapshot()
+		defer snap.Close()
+		_, err := archiveReplicatedKeyRange(ctx, desc, snap, threshold, ArchiveOptions{
+			Archiver:          MakeExternalArchiver(es),
+			Settings:          st,
+			PrevThreshold:     prev,
+			ArchivedThreshold: archived,
+		})
+		require.NoError(t, err)
+	}
+	read := func(span roachpb.Span, asOf hlc.Timestamp) ([]string, error) {
+		iter, err := NewArchiveIterator(ctx, es, span, asOf)
+		if err != nil {
+			return nil, err
+		}
+		defer iter.Close()
+		var res []string
+		for iter.SeekGE(storage.MakeMVCCMetadataKey(span.Key)); ; iter.NextKey() {
+			ok, err := iter.Valid()
+			require.NoError(t, err)
+			if !ok {
+				break
+			}
+			raw, err := iter.UnsafeValue()
+			require.NoError(t, err)
+			v, err := storage.DecodeMVCCValue(raw)
+			require.NoError(t, err)
+			b, err := v.Value.GetBytes()
+			require.NoError(t, err)
+			res = append(res, string(b))
+		}
+		sort.Strings(res)
+		return res, nil
+	}
+	numObjects := func() int {
+		var n int
+		require.NoError(t, es.List(ctx, "", cloud.ListOptions{}, func(string) error {
+			n++
+			return nil
+		}))
+		return n
+	}
+
+	put(append(start.Clone(), 'a'), 1, "a1")
+	put(append(start.Clone(), 'a'), 3, "a3")
+
+	// Only table 42 has keys, and its segment's span is stored alongside it.
+	archive(ts(1), hlc.Timestamp{}, ts(4))
+	require.Equal(t, 2, numObjects())
+	res, err := read(tableSpan(42), ts(2))
+	require.NoError(t, err)
+	require.Equal(t, []string{"a1"}, res)
+
+	// Table 44 gets keys after the first run. Its first segment starts a chain
+	// above the archived threshold.
+	put(append(keys.SystemSQLCodec.TablePrefix(44), 'b'), 5, "b5")
+	archive(ts(4), ts(4), ts(6))
+	require.Equal(t, 5, numObjects())
+	res, err = read(tableSpan(42), ts(5))
+	require.NoError(t, err)
+	require.Equal(t, []string{"a3"}, res)
+	res, err = read(tableSpan(44), ts(5))
+	require.NoError(t, err)
+	require.Equal(t, []string{"b5"}, res)
+	_, err = read(tableSpan(44), ts(4))
+	require.Error(t, err)
+
+	// Table 43 never had keys, so it has no archived history.
+	_, err = read(tableSpan(43), ts(5))
+	require.Error(t, err)
+	_, err = read(desc.KeySpan().AsRawSpanWithNoLocals(), ts(5))
+	require.Error(t, err)
+}
@@ -207,6 +207,10 @@ func (NoopGCer) GC(
 type Threshold struct {
 	Key hlc.Timestamp
 	Txn hlc.Timestamp
+	// Archived is the timestamp up to which the history of the range has been
+	// archived, or empty if history below Key is collected without having been
+	// archived.
+	Archived hlc.Timestamp
 }
 
 // Info contains statistics and insights from a GC run.
@@ -266,6 +270,9 @@ type Info struct {
 	ClearRangeSpanOperations int
 	// ClearRangeSpanFailures number of ClearRange requests GC failed to perform.
 	ClearRangeSpanFailures int
+	// ArchiveSkipped is set if history was collected without being archived
+	// because archiving kept failing.
+	ArchiveSkipped bool
 }
 
 // SafeFormat implements the redact.SafeFormatter interface.
@@ -302,6 +309,9 @@ func (info Info) SafeFormat(w redact.SafePrinter, _ rune) {
 		w.Printf(", clearRangeSpanOps=%d, clearRangeSpanFailures=%d",
 			info.ClearRangeSpanOperations, info.ClearRangeSpanFailures)
 	}
+	if info.ArchiveSkipped {
+		w.Printf(", archiveSkipped=true")
+	}
 }
 
 // String implements the fmt.Stringer interface.
@@ -346,6 +356,9 @@ type RunOptions struct {
 	// to issuing point delete requests for the oldest batch to free up memory
 	// before resuming further iteration.
 	MaxPendingKeysSize int64
+	// Archive, if set, causes the MVCC history which falls below the new GC
+	// threshold to be archived before anything is collected.
+	Archive *ArchiveOptions
 }
 
 // CleanupIntentsFunc synchronously resolves the supplied intents
@@ -377,20 +390,40 @@ func Run(
 	cleanupTxnIntentsAsyncFn CleanupTxnIntentsAsyncFunc,
 ) (Info, error) {
 
-	txnExp := now.Add(-options.TxnCleanupThreshold.Nanoseconds(), 0)
-	if err := gcer.SetGCThreshold(ctx, Threshold{
-		Key: newThreshold,
-		Txn: txnExp,
-	}); err != nil {
-		return Info{}, errors.Wrap(err, "failed to set GC thresholds")
-	}
-
 	info := Info{
 		GCTTL:     gcTTL,
 		Now:       now,
 		Threshold: newThreshold,
 	}
 
+	// Archive history before the GC threshold is advanced so that reads below
+	// the new threshold can be served from the archive as soon as they are
+	// rejected by the range. If archiving keeps failing, collection eventually
+	// proceeds without it rather than letting garbage accumulate indefinitely.
+	var archived hlc.Timestamp
+	if options.Archive != nil {
+		var err error
+		archived, err = archiveReplicatedKeyRange(ctx, desc, snap, newThreshold, *options.Archive)
+		if err != nil {
+			if !options.Archive.canCollectWithoutArchiving(newThreshold) {
+				return Info{}, errors.Mark(errors.Wrap(err, "failed to archive MVCC history"), errArchive)
+			}
+			log.KvExec.Errorf(ctx, "collecting MVCC history up to %s without archiving it: %v",
+				newThreshold, err)
+			archived = hlc.Timestamp{}
+			info.ArchiveSkipped = true
+		}
+	}
+
+	txnExp := now.Add(-options.TxnCleanupThreshold.Nanoseconds(), 0)
+	if err := gcer.SetGCThreshold(ctx, Threshold{
+		Key:      newThreshold,
+		Txn:      txnExp,
+		Archived: archived,
+	}); err != nil {
+		return Info{}, errors.Wrap(err, "failed to set GC thresholds")
+	}
+
 	// Process all replicated locks first and resolve any that have been around
 	// for longer than the LockAgeThreshold.
 	err := processReplicatedLocks(ctx, desc, snap, now, options.LockAgeThreshold,
@@ -2386,6 +2386,7 @@ func TestInfoSafeFormat(t *testing.T) {
 		AbortSpanGCNum:                5,
 		ClearRangeSpanOperations:      3,
 		ClearRangeSpanFailures:        1,
+		ArchiveSkipped:                true,
 	}
 	require.NoError(t, zerofields.NoZeroField(info),
 		"update test and SafeFormat for the new field")
@@ -448,8 +448,10 @@ func (kvSS *kvBatchSnapshotStrategy) Send(
 			SystemKeys: true,
 			LockTable:  true,
 			// In shared/external mode, the user span come from external SSTs and
-			// are not iterated over here.
-			UserKeys: !(header.SharedReplicate || header.ExternalReplicate),
+			// are not iterated over here. Witnesses don't hold user keys, so the
+			// user span is cleared on the recipient and nothing is sent for it.
+			UserKeys: !(header.SharedReplicate || header.ExternalReplicate) &&
+				!header.RaftMessageRequest.ToReplica.IsWitness(),
 		},
 		ReplicatedByRangeID:   true,
 		UnreplicatedByRangeID: false,
@@ -43,6 +43,13 @@ type BulkAdderOptions struct {
 	// comment on kvpb.AddSSTableRequest for more details.
 	DisallowShadowingBelow hlc.Timestamp
 
+	// DisallowConflicts controls whether the SSTables produced by this adder
+	// are checked for MVCC conflicts with existing keys when they are ingested.
+	// Combined with WriteAtBatchTimestamp, this allows the adder to overwrite
+	// existing keys with MVCC-correct semantics. See the comment on
+	// kvpb.AddSSTableRequest for more details.
+	DisallowConflicts bool
+
 	// BatchTimestamp is the timestamp to use on AddSSTable requests (which can be
 	// different from the timestamp used to construct the adder which is what is
 	// actually applied to each key).
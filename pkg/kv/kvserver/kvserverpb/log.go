@@ -19,4 +19,5 @@ const (
 	ReasonAdminRequest         RangeLogEventReason = "admin request"
 	ReasonAbandonedLearner     RangeLogEventReason = "abandoned learner replica"
 	ReasonUnsafeRecovery       RangeLogEventReason = "unsafe loss of quorum recovery"
+	ReasonConsistencyRepair    RangeLogEventReason = "consistency check repair"
 )
@@ -109,13 +109,13 @@ func (lq *leaseQueue) shouldQueue(
 		return false, 0
 	}
 	desc := repl.Desc()
-	// Only replicas that can hold a lease (IsVoterNewConfig) should be
-	// processed. Without this check, non-voters can reach
+	// Only replicas that can hold a lease (IsVoterNewConfig, and not a
+	// witness) should be processed. Without this check, non-voters can reach
 	// canTransferLeaseFrom (and ShouldPlanChange) when their lease status
 	// evaluates as ERROR — which happens routinely for leader leases
 	// evaluated by a follower once MinExpiration has passed. See #107691.
 	replDesc, ok := desc.GetReplicaDescriptorByID(repl.ReplicaID())
-	if !ok || !replDesc.IsVoterNewConfig() {
+	if !ok || !replDesc.IsVoterNewConfig() || replDesc.IsWitness() {
 		return false, 0
 	}
 	return lq.planner.ShouldPlanChange(ctx, now, repl, desc, &conf, plan.PlannerOptions{
@@ -319,10 +319,10 @@ func (mq *mergeQueue) process(
 	}
 	leftRepls, rightRepls := lhsDesc.Replicas().Descriptors(), rhsDesc.Replicas().Descriptors()
 
-	// Defensive sanity check that the ranges involved only have either VOTER_FULL
-	// and NON_VOTER replicas.
+	// Defensive sanity check that the ranges involved only have VOTER_FULL,
+	// NON_VOTER and WITNESS replicas.
 	for i := range leftRepls {
-		if typ := leftRepls[i].Type; !(typ == roachpb.VOTER_FULL || typ == roachpb.NON_VOTER) {
+		if typ := leftRepls[i].Type; !(typ == roachpb.VOTER_FULL || typ == roachpb.NON_VOTER || typ == roachpb.WITNESS) {
 			return false,
 				errors.AssertionFailedf(
 					`cannot merge because lhs is either in a joint state or has learner replicas: %v`,
@@ -331,6 +331,21 @@ func (mq *mergeQueue) process(
 		}
 	}
 
+	// AdminRelocateRange cannot place witnesses, and a witness on one side of
+	// the merge must sit on the same store as a witness on the other side, since
+	// witnesses hold no user data. Only merge ranges with witnesses that are
+	// already collocated and leave the rest to the replicate queue.
+	lhsWitnesses, rhsWitnesses := lhsDesc.Replicas().WitnessDescriptors(), rhsDesc.Replicas().WitnessDescriptors()
+	if len(lhsWitnesses) > 0 || len(rhsWitnesses) > 0 {
+		if !replicasCollocated(leftRepls, rightRepls) ||
+			!replicasCollocated(lhsWitnesses, rhsWitnesses) ||
+			rhsDesc.Replicas().InAtomicReplicationChange() {
+			log.VEventf(ctx, 2, "skipping merge: witnesses of %s and %s are not collocated",
+				lhsDesc, rhsDesc)
+			return false, nil
+		}
+	}
+
 	// Range merges require that the set of stores that contain a replica for the
 	// RHS range be equal to the set of stores that contain a replica for the LHS
 	// range. The LHS and RHS ranges' leaseholders do not need to be co-located
@@ -383,7 +398,7 @@ func (mq *mergeQueue) process(
 		rightRepls = rhsDesc.Replicas().Descriptors()
 	}
 	for i := range rightRepls {
-		if typ := rightRepls[i].Type; !(typ == roachpb.VOTER_FULL || typ == roachpb.NON_VOTER) {
+		if typ := rightRepls[i].Type; !(typ == roachpb.VOTER_FULL || typ == roachpb.NON_VOTER || typ == roachpb.WITNESS) {
 			log.KvDistribution.Infof(ctx, "RHS Type: %s", typ)
 			return false,
 				errors.AssertionFailedf(
@@ -2466,6 +2466,12 @@ var (
 		Measurement: "Processing Time",
 		Unit:        metric.Unit_NANOSECONDS,
 	}
+	metaConsistencyRepairFailures = metric.Metadata{
+		Name:        "queue.consistency.repair.failure",
+		Help:        "Number of diverging replicas which were removed by consistency check repair but could not be replaced",
+		Measurement: "Replicas",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaReplicaGCQueueSuccesses = metric.Metadata{
 		Name:        "queue.replicagc.process.success",
 		Help:        "Number of replicas successfully processed by the replica GC queue",
@@ -2780,6 +2786,18 @@ var (
 		Measurement: "Requests",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaGCArchiveFailed = metric.Metadata{
+		Name:        "queue.gc.info.archivefailed",
+		Help:        "Number of GC runs which failed because MVCC history could not be archived",
+		Measurement: "Runs",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaGCArchiveSkipped = metric.Metadata{
+		Name:        "queue.gc.info.archiveskipped",
+		Help:        "Number of GC runs which collected MVCC history without archiving it because archiving kept failing",
+		Measurement: "Runs",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaGCEnqueueHighPriority = metric.Metadata{
 		Name:        "queue.gc.info.enqueuehighpriority",
 		Help:        "Number of replicas enqueued for GC with high priority",
@@ -3678,6 +3696,7 @@ type StoreMetrics struct {
 	ConsistencyQueueFailures                  *metric.Counter
 	ConsistencyQueuePending                   *metric.Gauge
 	ConsistencyQueueProcessingNanos           *metric.Counter
+	ConsistencyRepairFailures                 *metric.Counter
 	LeaseQueueSuccesses                       *metric.Counter
 	LeaseQueueFailures                        *metric.Counter
 	LeaseQueuePending                         *metric.Gauge
@@ -3731,6 +3750,8 @@ type StoreMetrics struct {
 	GCTxnIntentsResolveFailed *metric.Counter
 	GCUsedClearRange          *metric.Counter
 	GCFailedClearRange        *metric.Counter
+	GCArchiveFailed           *metric.Counter
+	GCArchiveSkipped          *metric.Counter
 	GCEnqueueHighPriority     *metric.Counter
 
 	// Slow request counts.
@@ -4507,6 +4528,7 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		ConsistencyQueueFailures:                  metric.NewCounter(metaConsistencyQueueFailures),
 		ConsistencyQueuePending:                   metric.NewGauge(metaConsistencyQueuePending),
 		ConsistencyQueueProcessingNanos:           metric.NewCounter(metaConsistencyQueueProcessingNanos),
+		ConsistencyRepairFailures:                 metric.NewCounter(metaConsistencyRepairFailures),
 		LeaseQueueSuccesses:                       metric.NewCounter(metaLeaseQueueSuccesses),
 		LeaseQueueFailures:                        metric.NewCounter(metaLeaseQueueFailures),
 		LeaseQueuePending:                         metric.NewGauge(metaLeaseQueuePending),
@@ -4558,6 +4580,8 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		GCTxnIntentsResolveFailed:    metric.NewCounter(metaGCTxnIntentsResolveFailed),
 		GCUsedClearRange:             metric.NewCounter(metaGCUsedClearRange),
 		GCFailedClearRange:           metric.NewCounter(metaGCFailedClearRange),
+		GCArchiveFailed:              metric.NewCounter(metaGCArchiveFailed),
+		GCArchiveSkipped:             metric.NewCounter(metaGCArchiveSkipped),
 		GCEnqueueHighPriority:        metric.NewCounter(metaGCEnqueueHighPriority),
 
 		// Wedge request counters.
@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/obs/workloadid"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/spanconfig"
@@ -614,6 +615,7 @@ func (r *replicaGCer) send(ctx context.Context, req kvpb.GCRequest) error {
 func (r *replicaGCer) SetGCThreshold(ctx context.Context, thresh gc.Threshold) error {
 	req := r.template()
 	req.Threshold = thresh.Key
+	req.ArchivedThreshold = thresh.Archived
 	return r.send(ctx, req)
 }
 
@@ -713,6 +715,28 @@ func (mgcq *mvccGCQueue) process(
 	txnCleanupThreshold := gc.TxnCleanupThreshold.Get(&repl.store.ClusterSettings().SV)
 	clearRangeMinKeys := gc.ClearRangeMinKeys.Get(&repl.store.ClusterSettings().SV)
 
+	// If an archive is configured, history must be archived before it can be
+	// collected, so failing to open the archive fails the GC run.
+	var archive *gc.ArchiveOptions
+	if uri := gc.ArchiveURI.Get(&repl.store.ClusterSettings().SV); uri != "" {
+		if repl.store.cfg.ExternalStorageFromURI == nil {
+			return false, errors.Newf("%s is set but external storage is unavailable", gc.ArchiveURI.Name())
+		}
+		es, err := repl.store.cfg.ExternalStorageFromURI(ctx, uri, username.NodeUserName())
+		if err != nil {
+			mgcq.store.metrics.GCArchiveFailed.Inc(1)
+			return false, errors.Wrap(err, "opening MVCC history archive")
+		}
+		defer es.Close()
+		hint := repl.GetGCHint()
+		archive = &gc.ArchiveOptions{
+			Archiver:          gc.MakeExternalArchiver(es),
+			Settings:          repl.store.ClusterSettings(),
+			PrevThreshold:     oldThreshold,
+			ArchivedThreshold: hint.ArchivedThreshold,
+		}
+	}
+
 	info, err := gc.Run(ctx, desc, snap, gcTimestamp, newThreshold,
 		gc.RunOptions{
 			LockAgeThreshold:                     lockAgeThreshold,
@@ -722,6 +746,7 @@ func (mgcq *mvccGCQueue) process(
 			MaxTxnsPerIntentCleanupBatch:         intentresolver.MaxTxnsPerIntentCleanupBatch,
 			IntentCleanupBatchTimeout:            mvccGCQueueIntentBatchTimeout,
 			ClearRangeMinKeys:                    clearRangeMinKeys,
+			Archive:                              archive,
 		},
 		conf.TTL(),
 		&replicaGCer{
@@ -766,6 +791,9 @@ func (mgcq *mvccGCQueue) process(
 			return err
 		})
 	if err != nil {
+		if gc.IsArchiveError(err) {
+			mgcq.store.metrics.GCArchiveFailed.Inc(1)
+		}
 		return false, err
 	}
 
@@ -828,6 +856,9 @@ func updateStoreMetricsWithGCInfo(metrics *StoreMetrics, info gc.Info) {
 	metrics.GCResolveTotal.Inc(int64(info.ResolveTotal))
 	metrics.GCUsedClearRange.Inc(int64(info.ClearRangeSpanOperations))
 	metrics.GCFailedClearRange.Inc(int64(info.ClearRangeSpanFailures))
+	if info.ArchiveSkipped {
+		metrics.GCArchiveSkipped.Inc(1)
+	}
 }
 
 func (mgcq *mvccGCQueue) postProcessScheduled(
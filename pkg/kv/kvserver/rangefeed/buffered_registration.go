@@ -78,6 +78,7 @@ func newBufferedRegistration(
 	withDiff bool,
 	withFiltering bool,
 	withOmitRemote bool,
+	rowFilter *RowFilter,
 	bulkDeliverySize int,
 	bufferSz int,
 	blockWhenFull bool,
@@ -93,6 +94,7 @@ func newBufferedRegistration(
 			withDiff,
 			withFiltering,
 			withOmitRemote,
+			rowFilter,
 			bulkDeliverySize,
 			removeRegFromProcessor),
 		metrics:       metrics,
@@ -315,7 +317,7 @@ func (br *bufferedRegistration) maybeRunCatchUpScan(ctx context.Context) error {
 		br.metrics.RangeFeedCatchUpScanNanos.Inc(start.Elapsed().Nanoseconds())
 	}()
 
-	return catchUpSnap.CatchUpScan(ctx, br.stream.SendUnbuffered, br.withDiff, br.withFiltering, br.withOmitRemote, br.bulkDelivery)
+	return catchUpSnap.CatchUpScan(ctx, br.withRowFilter(br.stream.SendUnbuffered), br.withDiff, br.withFiltering, br.withOmitRemote, br.bulkDelivery)
 }
 
 // Wait for this registration to completely process its internal
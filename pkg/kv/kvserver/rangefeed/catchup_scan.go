@@ -78,6 +78,10 @@ type CatchUpSnapshot struct {
 	pacer                *admission.Pacer
 	OnEmit               func(key, endKey roachpb.Key, ts hlc.Timestamp, vh enginepb.MVCCValueHeader)
 	iterRecreateDuration time.Duration
+	// LatestOnly, if set, makes the catch-up scan emit only the newest version
+	// of each key and of each MVCC range tombstone fragment, rather than every
+	// version above startTime. See kvpb.RangeFeedRequest_LATEST.
+	LatestOnly bool
 }
 
 // NewCatchUpSnapshot returns a CatchUpSnapshot for the given Engine over the
@@ -160,6 +164,11 @@ type outputEventFn func(e *kvpb.RangeFeedEvent) error
 // the range key. For example, with a range key [a, z), and iterator bound [d,
 // j), an iterator seeked to d observes range key [d, j), and an iterator
 // seeked to e also observes [d, j).
+//
+// If LatestOnly is set, only the newest version of each key is emitted, and
+// point keys that are shadowed by a newer MVCC range tombstone are omitted
+// entirely since the emitted range tombstone already deletes them. With
+// withDiff, the previous value is the newest version at or below startTime.
 func (i *CatchUpSnapshot) CatchUpScan(
 	ctx context.Context,
 	emitFn outputEventFn,
@@ -222,6 +231,9 @@ func (i *CatchUpSnapshot) CatchUpScan(
 	// can't use NextKey.
 	var lastKey roachpb.Key
 	var meta enginepb.MVCCMetadata
+	// latestSeen is set in LatestOnly mode once the newest version of lastKey
+	// above startTime has been considered.
+	var latestSeen bool
 	iter.SeekGE(storage.MVCCKey{Key: i.span.Key})
 
 	for {
@@ -245,6 +257,7 @@ func (i *CatchUpSnapshot) CatchUpScan(
 				return err
 			}
 			a, lastKey = a.Copy(unsafeKey.Key)
+			latestSeen = false
 			// NB: we only recreate the iterator when we have moved to a new
 			// roachpb.Key. This is because we will need to reposition the new
 			// iterator using a seek, and the seek respects the time bounds.
@@ -334,7 +347,11 @@ func (i *CatchUpSnapshot) CatchUpScan(
 			if hasRange {
 				// Emit events for these MVCC range tombstones, in chronological order.
 				rangeKeys := iter.RangeKeys()
-				for j := rangeKeys.Len() - 1; j >= 0; j-- {
+				oldest := rangeKeys.Len() - 1
+				if i.LatestOnly {
+					oldest = 0
+				}
+				for j := oldest; j >= 0; j-- {
 					var span roachpb.Span
 					a, span.Key = a.Copy(rangeKeys.Bounds.Key)
 					a, span.EndKey = a.Copy(rangeKeys.Bounds.EndKey)
@@ -440,6 +457,29 @@ func (i *CatchUpSnapshot) CatchUpScan(
 			continue
 		}
 
+		if i.LatestOnly && !ignore {
+			if latestSeen {
+				// An older version of a key whose newest version has already been
+				// considered. With withDiff, keep stepping until we reach the
+				// version at or below startTime, which is the previous value.
+				if withDiff {
+					iter.NextIgnoringTime()
+				} else {
+					iter.NextKey()
+				}
+				continue
+			}
+			latestSeen = true
+			if _, hasRange := iter.HasPointAndRange(); hasRange {
+				if ts.Less(iter.RangeKeys().Newest()) {
+					// The key is deleted by a newer MVCC range tombstone, which has
+					// already been emitted.
+					iter.NextKey()
+					continue
+				}
+			}
+		}
+
 		// INVARIANT: !ignore || withDiff
 		//
 		// Cases:
@@ -514,6 +554,8 @@ func (i *CatchUpSnapshot) CatchUpScan(
 				// Need to see the next version even if it is older than the time
 				// bounds.
 				iter.NextIgnoringTime()
+			} else if i.LatestOnly {
+				iter.NextKey()
 			} else {
 				iter.Next()
 			}
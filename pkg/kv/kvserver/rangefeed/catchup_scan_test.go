@@ -326,3 +326,75 @@ func TestCatchupScanSeesOldIntent(t *testing.T) {
 		"e": {},
 	}, keys)
 }
+
+func TestCatchupScanLatestOnly(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	eng := storage.NewDefaultInMemForTesting(storage.If(smallEngineBlocks, storage.BlockSize(1)))
+	defer eng.Close()
+
+	ts := func(wall int64) hlc.Timestamp {
+		return hlc.Timestamp{WallTime: wall}
+	}
+	put := func(k string, wall int64, v string) {
+		_, err := storage.MVCCPut(ctx, eng, roachpb.Key(k), ts(wall),
+			roachpb.MakeValueFromString(v), storage.MVCCWriteOptions{})
+		require.NoError(t, err)
+	}
+	put("a", 1, "a1")
+	put("a", 3, "a3")
+	put("a", 4, "a4")
+	put("b", 1, "b1")
+	_, _, err := storage.MVCCDelete(ctx, eng, roachpb.Key("b"), ts(3), storage.MVCCWriteOptions{})
+	require.NoError(t, err)
+	put("c", 3, "c3")
+	for _, wall := range []int64{4, 5} {
+		require.NoError(t, eng.PutMVCCRangeKey(storage.MVCCRangeKey{
+			StartKey: roachpb.Key("c"), EndKey: roachpb.Key("d"), Timestamp: ts(wall),
+		}, storage.MVCCValue{}))
+	}
+	put("e", 1, "e1")
+
+	testutils.RunTrueAndFalse(t, "withDiff", func(t *testing.T, withDiff bool) {
+		span := roachpb.Span{Key: roachpb.Key("a"), EndKey: roachpb.KeyMax}
+		snap := NewCatchUpSnapshot(eng, span, ts(2), nil, nil, 0)
+		snap.LatestOnly = true
+		defer snap.Close()
+		var events []kvpb.RangeFeedEvent
+		require.NoError(t, snap.CatchUpScan(ctx, func(e *kvpb.RangeFeedEvent) error {
+			events = append(events, *e)
+			return nil
+		}, withDiff, false /* withFiltering */, false /* withOmitRemote */, noBulkDelivery))
+
+		// Only the newest version of a and the deletion of b are emitted, along
+		// with the newest range tombstone over c, which shadows c@3.
+		require.Len(t, events, 3)
+		require.Equal(t, roachpb.Key("a"), events[0].Val.Key)
+		require.Equal(t, ts(4), events[0].Val.Value.Timestamp)
+		require.Equal(t, roachpb.Key("b"), events[1].Val.Key)
+		require.Equal(t, ts(3), events[1].Val.Value.Timestamp)
+		require.False(t, events[1].Val.Value.IsPresent())
+		require.Equal(t, roachpb.Span{Key: roachpb.Key("c"), EndKey: roachpb.Key("d")},
+			events[2].DeleteRange.Span)
+		require.Equal(t, ts(5), events[2].DeleteRange.Timestamp)
+
+		// The previous value is the value as of the start time.
+		prev := func(e kvpb.RangeFeedEvent) string {
+			if !e.Val.PrevValue.IsPresent() {
+				return ""
+			}
+			b, err := e.Val.PrevValue.GetBytes()
+			require.NoError(t, err)
+			return string(b)
+		}
+		if withDiff {
+			require.Equal(t, "a1", prev(events[0]))
+			require.Equal(t, "b1", prev(events[1]))
+		} else {
+			require.Equal(t, "", prev(events[0]))
+			require.Equal(t, "", prev(events[1]))
+		}
+	})
+}
@@ -181,6 +181,9 @@ type Processor interface {
 	// subsequently close it. If method fails, snapshot must be kept intact and
 	// would be closed by caller.
 	//
+	// If rowFilter is non-nil, value events (including those emitted by the
+	// catch-up scan) are evaluated against it before being sent to the stream.
+	//
 	// If the method returns false, the processor will have been stopped, so calling
 	// Stop is not necessary. If the method returns true, it will also return an
 	// updated operation filter that includes the operations required by the new
@@ -195,6 +198,7 @@ type Processor interface {
 		withDiff bool,
 		withFiltering bool,
 		withOmitRemote bool,
+		rowFilter *RowFilter,
 		bulkDeliverySize int,
 		stream Stream,
 	) (bool, Disconnector, *Filter)
@@ -49,6 +49,12 @@ type registration interface {
 	// registration.
 	shouldPublishLogicalOp(hlc.Timestamp, logicalOpMetadata) bool
 
+	// maybeFilterRow applies the registration's row filter, if any, to the
+	// event. It returns false if the event should not be published to this
+	// registration, and otherwise the event that should be published in its
+	// place.
+	maybeFilterRow(event *kvpb.RangeFeedEvent) (*kvpb.RangeFeedEvent, bool)
+
 	// runOutputLoop runs the output loop for the registration. The output loop is
 	// meant to be run in a separate goroutine.
 	runOutputLoop(ctx context.Context, forStacks roachpb.RangeID)
@@ -95,6 +101,7 @@ type baseRegistration struct {
 	withDiff         bool
 	withFiltering    bool
 	withOmitRemote   bool
+	rowFilter        *RowFilter
 	bulkDelivery     int
 	catchUpTimestamp hlc.Timestamp // exclusive
 	// removeRegFromProcessor is called to remove the registration from its
@@ -118,6 +125,7 @@ func newBaseRegistration(
 	withDiff bool,
 	withFiltering bool,
 	withOmitRemote bool,
+	rowFilter *RowFilter,
 	bulkDeliverySize int,
 	removeRegFromProcessor func(registration),
 ) baseRegistration {
@@ -129,6 +137,7 @@ func newBaseRegistration(
 		withDiff:               withDiff,
 		withFiltering:          withFiltering,
 		withOmitRemote:         withOmitRemote,
+		rowFilter:              rowFilter,
 		bulkDelivery:           bulkDeliverySize,
 		removeRegFromProcessor: removeRegFromProcessor,
 	}
@@ -177,6 +186,58 @@ func (r *baseRegistration) shouldPublishLogicalOp(
 	return true
 }
 
+func (r *baseRegistration) maybeFilterRow(
+	event *kvpb.RangeFeedEvent,
+) (*kvpb.RangeFeedEvent, bool) {
+	if r.rowFilter == nil {
+		return event, true
+	}
+	t, ok := event.GetValue().(*kvpb.RangeFeedValue)
+	if !ok {
+		return event, true
+	}
+	filtered, ok := r.rowFilter.filterValue(t)
+	if !ok {
+		return nil, false
+	}
+	if filtered == t {
+		return event, true
+	}
+	// The event may be shared with other registrations, so publish a copy.
+	ret := *event
+	ret.MustSetValue(filtered)
+	return &ret, true
+}
+
+// withRowFilter wraps the provided output function so that the events it is
+// called with are subject to the registration's row filter, if any. It is used
+// for catch-up scans, which send events directly to the stream rather than
+// through the registry.
+func (r *baseRegistration) withRowFilter(fn outputEventFn) outputEventFn {
+	if r.rowFilter == nil {
+		return fn
+	}
+	return func(e *kvpb.RangeFeedEvent) error {
+		if bulk := e.BulkEvents; bulk != nil {
+			events := bulk.Events[:0]
+			for _, ev := range bulk.Events {
+				if ev, ok := r.maybeFilterRow(ev); ok {
+					events = append(events, ev)
+				}
+			}
+			if len(events) == 0 {
+				return nil
+			}
+			bulk.Events = events
+			return fn(e)
+		}
+		if e, ok := r.maybeFilterRow(e); ok {
+			return fn(e)
+		}
+		return nil
+	}
+}
+
 func (r *baseRegistration) shouldUnregister() bool {
 	return r.shouldUnreg.Load()
 }
@@ -407,7 +468,10 @@ func (reg *registry) PublishToOverlapping(
 	}
 
 	reg.forOverlappingRegs(ctx, span, func(r registration) (bool, *kvpb.Error) {
-		if r.shouldPublishLogicalOp(minTS, valueMetadata) {
+		if !r.shouldPublishLogicalOp(minTS, valueMetadata) {
+			return false, nil
+		}
+		if event, ok := r.maybeFilterRow(event); ok {
 			r.publish(ctx, event, alloc)
 		}
 		return false, nil
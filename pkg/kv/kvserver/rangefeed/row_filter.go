@@ -0,0 +1,234 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package rangefeed
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// RowFilter is the compiled form of a kvpb.RangeFeedRowFilter. It evaluates a
+// conjunction of column conditions, and applies a column projection, directly
+// on the tuple-encoded values of a single column family.
+//
+// Row filtering is an optimization: any value the filter is unable to
+// evaluate (e.g. because it was written with a newer table descriptor) is
+// published unmodified, and consumers are expected to evaluate their
+// predicate again.
+type RowFilter struct {
+	tableID      uint32
+	indexID      uint32
+	familyID     uint32
+	nextColumnID uint32
+	clauses      []rowFilterClause
+	// projection is sorted and deduplicated. A nil projection retains all
+	// columns.
+	projection []uint32
+}
+
+type rowFilterClause struct {
+	columnID uint32
+	op       kvpb.RangeFeedRowFilter_Clause_Op
+	values   []encodedColumn
+}
+
+// encodedColumn is a single value-encoded column of a tuple, split into its
+// type and its encoded data following the value tag.
+type encodedColumn struct {
+	id   uint32
+	typ  encoding.Type
+	data []byte
+}
+
+func (c encodedColumn) equal(o encodedColumn) bool {
+	return c.typ == o.typ && bytes.Equal(c.data, o.data)
+}
+
+// NewRowFilter compiles the provided row filter. It returns nil if spec is
+// nil.
+func NewRowFilter(spec *kvpb.RangeFeedRowFilter) (*RowFilter, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	f := &RowFilter{
+		tableID:      spec.TableID,
+		indexID:      spec.IndexID,
+		familyID:     spec.FamilyID,
+		nextColumnID: spec.NextColumnID,
+	}
+	for _, c := range spec.Clauses {
+		clause := rowFilterClause{columnID: c.ColumnID, op: c.Op}
+		switch c.Op {
+		case kvpb.RangeFeedRowFilter_Clause_IN, kvpb.RangeFeedRowFilter_Clause_NOT_IN:
+			if len(c.Values) == 0 {
+				return nil, errors.Errorf("row filter clause on column %d has no values", c.ColumnID)
+			}
+			for _, v := range c.Values {
+				cols, err := decodeTupleColumns(v)
+				if err != nil {
+					return nil, errors.Wrapf(err, "decoding row filter value for column %d", c.ColumnID)
+				}
+				if len(cols) != 1 || cols[0].id != 0 {
+					return nil, errors.Errorf(
+						"row filter value for column %d must be a single datum without a column ID", c.ColumnID)
+				}
+				clause.values = append(clause.values, cols[0])
+			}
+		case kvpb.RangeFeedRowFilter_Clause_IS_NULL, kvpb.RangeFeedRowFilter_Clause_IS_NOT_NULL:
+			if len(c.Values) != 0 {
+				return nil, errors.Errorf("row filter clause %s on column %d must not have values", c.Op, c.ColumnID)
+			}
+		default:
+			return nil, errors.Errorf("unknown row filter clause %s", c.Op)
+		}
+		f.clauses = append(f.clauses, clause)
+	}
+	if len(spec.ProjectionColumnIDs) > 0 {
+		f.projection = append([]uint32(nil), spec.ProjectionColumnIDs...)
+		slices.Sort(f.projection)
+		f.projection = slices.Compact(f.projection)
+	}
+	return f, nil
+}
+
+// filterValue evaluates the filter against a value event. It returns false if
+// the event should not be published. Otherwise, it returns the event to
+// publish, which is either the provided event or, if columns were projected
+// away, a copy of it.
+func (f *RowFilter) filterValue(v *kvpb.RangeFeedValue) (*kvpb.RangeFeedValue, bool) {
+	sqlKey, _, err := keys.DecodeTenantPrefix(v.Key)
+	if err != nil {
+		return v, true
+	}
+	_, tableID, indexID, err := keys.DecodeTableIDIndexID(sqlKey)
+	if err != nil || tableID != f.tableID || indexID != f.indexID {
+		return v, true
+	}
+	familyID, err := keys.DecodeFamilyKey(v.Key)
+	if err != nil {
+		return v, true
+	}
+	if familyID != f.familyID {
+		return nil, false
+	}
+
+	value, ok := f.decodeValue(v.Value)
+	if !ok {
+		// Deletion tombstones, values not encoded as tuples, and values written
+		// by a newer descriptor version are published unfiltered.
+		return v, true
+	}
+	for i := range f.clauses {
+		if !f.clauses[i].matches(value) {
+			return nil, false
+		}
+	}
+
+	if f.projection == nil {
+		return v, true
+	}
+	cpy := *v
+	cpy.Value = f.project(v.Value, value)
+	if prev, ok := f.decodeValue(v.PrevValue); ok {
+		cpy.PrevValue = f.project(v.PrevValue, prev)
+	}
+	return &cpy, true
+}
+
+// decodeValue decodes the columns of a tuple-encoded value. It returns false
+// if the value is not a tuple, cannot be decoded, or contains columns which
+// the filter does not know about.
+func (f *RowFilter) decodeValue(v roachpb.Value) ([]encodedColumn, bool) {
+	if !v.IsPresent() {
+		return nil, false
+	}
+	tuple, err := v.GetTuple()
+	if err != nil {
+		return nil, false
+	}
+	cols, err := decodeTupleColumns(tuple)
+	if err != nil {
+		return nil, false
+	}
+	if n := len(cols); n > 0 && f.nextColumnID != 0 && cols[n-1].id >= f.nextColumnID {
+		return nil, false
+	}
+	return cols, true
+}
+
+// project returns a copy of v retaining only the projected columns.
+func (f *RowFilter) project(v roachpb.Value, cols []encodedColumn) roachpb.Value {
+	var buf []byte
+	var lastID uint32
+	for _, c := range cols {
+		if _, found := slices.BinarySearch(f.projection, c.id); !found {
+			continue
+		}
+		buf = encoding.EncodeValueTag(buf, c.id-lastID, c.typ)
+		buf = append(buf, c.data...)
+		lastID = c.id
+	}
+	var res roachpb.Value
+	res.SetTuple(buf)
+	res.Timestamp = v.Timestamp
+	return res
+}
+
+// matches returns whether the clause is satisfied by the given columns, which
+// must be sorted by column ID. Columns missing from the tuple are NULL.
+func (c *rowFilterClause) matches(cols []encodedColumn) bool {
+	var col encodedColumn
+	isNull := true
+	for _, cur := range cols {
+		if cur.id == c.columnID {
+			col, isNull = cur, cur.typ == encoding.Null
+			break
+		}
+	}
+	switch c.op {
+	case kvpb.RangeFeedRowFilter_Clause_IS_NULL:
+		return isNull
+	case kvpb.RangeFeedRowFilter_Clause_IS_NOT_NULL:
+		return !isNull
+	}
+	if isNull {
+		return false
+	}
+	in := false
+	for _, v := range c.values {
+		if col.equal(v) {
+			in = true
+			break
+		}
+	}
+	return in == (c.op == kvpb.RangeFeedRowFilter_Clause_IN)
+}
+
+// decodeTupleColumns splits tuple-encoded bytes into their columns.
+func decodeTupleColumns(b []byte) ([]encodedColumn, error) {
+	var cols []encodedColumn
+	var colID uint32
+	for len(b) > 0 {
+		_, dataOffset, colIDDelta, typ, err := encoding.DecodeValueTag(b)
+		if err != nil {
+			return nil, err
+		}
+		n, err := encoding.PeekValueLengthWithOffsetsAndType(b, dataOffset, typ)
+		if err != nil {
+			return nil, err
+		}
+		colID += colIDDelta
+		cols = append(cols, encodedColumn{id: colID, typ: typ, data: b[dataOffset:n]})
+		b = b[n:]
+	}
+	return cols, nil
+}
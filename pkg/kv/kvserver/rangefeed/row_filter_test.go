@@ -0,0 +1,227 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package rangefeed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	rowFilterTableID = 104
+	rowFilterIndexID = 1
+)
+
+func rowFilterKey(tableID, indexID uint32, pk int64, familyID uint32) roachpb.Key {
+	k := keys.SystemSQLCodec.IndexPrefix(tableID, indexID)
+	k = encoding.EncodeVarintAscending(k, pk)
+	return keys.MakeFamilyKey(k, familyID)
+}
+
+// rowFilterValue returns a tuple-encoded value with an INT column 2 and a
+// STRING column 3. A nil status omits column 3, which makes it NULL.
+func rowFilterValue(n int64, status *string) roachpb.Value {
+	b := encoding.EncodeIntValue(nil, 2, n)
+	if status != nil {
+		b = encoding.EncodeBytesValue(b, 1, []byte(*status))
+	}
+	var v roachpb.Value
+	v.SetTuple(b)
+	v.Timestamp = hlc.Timestamp{WallTime: 1}
+	return v
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestRowFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	activeOrPending := kvpb.RangeFeedRowFilter_Clause{
+		ColumnID: 3,
+		Op:       kvpb.RangeFeedRowFilter_Clause_IN,
+		Values: [][]byte{
+			encoding.EncodeBytesValue(nil, encoding.NoColumnID, []byte("active")),
+			encoding.EncodeBytesValue(nil, encoding.NoColumnID, []byte("pending")),
+		},
+	}
+	notFive := kvpb.RangeFeedRowFilter_Clause{
+		ColumnID: 2,
+		Op:       kvpb.RangeFeedRowFilter_Clause_NOT_IN,
+		Values:   [][]byte{encoding.EncodeIntValue(nil, encoding.NoColumnID, 5)},
+	}
+	isNull := kvpb.RangeFeedRowFilter_Clause{ColumnID: 3, Op: kvpb.RangeFeedRowFilter_Clause_IS_NULL}
+	spec := func(clauses ...kvpb.RangeFeedRowFilter_Clause) *kvpb.RangeFeedRowFilter {
+		return &kvpb.RangeFeedRowFilter{
+			TableID:      rowFilterTableID,
+			IndexID:      rowFilterIndexID,
+			NextColumnID: 4,
+			Clauses:      clauses,
+		}
+	}
+	key := rowFilterKey(rowFilterTableID, rowFilterIndexID, 1, 0)
+
+	for _, tc := range []struct {
+		name    string
+		spec    *kvpb.RangeFeedRowFilter
+		key     roachpb.Key
+		value   roachpb.Value
+		publish bool
+	}{
+		{"in matches", spec(activeOrPending), key, rowFilterValue(1, strPtr("pending")), true},
+		{"in does not match", spec(activeOrPending), key, rowFilterValue(1, strPtr("done")), false},
+		{"in with null", spec(activeOrPending), key, rowFilterValue(1, nil), false},
+		{"not in matches", spec(notFive), key, rowFilterValue(4, nil), true},
+		{"not in does not match", spec(notFive), key, rowFilterValue(5, nil), false},
+		{"is null", spec(isNull), key, rowFilterValue(1, nil), true},
+		{"is null with value", spec(isNull), key, rowFilterValue(1, strPtr("active")), false},
+		{"conjunction", spec(activeOrPending, notFive), key, rowFilterValue(5, strPtr("active")), false},
+		{"other family", spec(), rowFilterKey(rowFilterTableID, rowFilterIndexID, 1, 1), rowFilterValue(1, nil), false},
+		{"other index", spec(isNull), rowFilterKey(rowFilterTableID, 2, 1, 0), rowFilterValue(1, strPtr("active")), true},
+		{"other table", spec(isNull), rowFilterKey(105, rowFilterIndexID, 1, 0), rowFilterValue(1, strPtr("active")), true},
+		{"tombstone", spec(isNull), key, roachpb.Value{}, true},
+		{"not a tuple", spec(isNull), key, roachpb.MakeValueFromString("active"), true},
+		{
+			// Column 4 was added after the filter was compiled.
+			name: "newer descriptor",
+			spec: spec(isNull),
+			key:  key,
+			value: func() roachpb.Value {
+				b := encoding.EncodeBytesValue(nil, 3, []byte("active"))
+				b = encoding.EncodeIntValue(b, 1, 7)
+				var v roachpb.Value
+				v.SetTuple(b)
+				return v
+			}(),
+			publish: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := NewRowFilter(tc.spec)
+			require.NoError(t, err)
+			in := &kvpb.RangeFeedValue{Key: tc.key, Value: tc.value}
+			out, publish := f.filterValue(in)
+			require.Equal(t, tc.publish, publish)
+			if publish {
+				require.Same(t, in, out)
+			}
+		})
+	}
+
+	t.Run("projection", func(t *testing.T) {
+		s := spec(activeOrPending)
+		s.ProjectionColumnIDs = []uint32{3}
+		f, err := NewRowFilter(s)
+		require.NoError(t, err)
+
+		in := &kvpb.RangeFeedValue{
+			Key:       key,
+			Value:     rowFilterValue(1, strPtr("active")),
+			PrevValue: rowFilterValue(2, strPtr("done")),
+		}
+		out, publish := f.filterValue(in)
+		require.True(t, publish)
+		require.NotSame(t, in, out)
+
+		expected := func(status string) []byte {
+			return encoding.EncodeBytesValue(nil, 3, []byte(status))
+		}
+		tuple, err := out.Value.GetTuple()
+		require.NoError(t, err)
+		require.Equal(t, expected("active"), tuple)
+		require.Equal(t, in.Value.Timestamp, out.Value.Timestamp)
+		tuple, err = out.PrevValue.GetTuple()
+		require.NoError(t, err)
+		require.Equal(t, expected("done"), tuple)
+
+		// The input must not have been modified.
+		tuple, err = in.Value.GetTuple()
+		require.NoError(t, err)
+		require.Equal(t, 2, len(mustDecodeTupleColumns(t, tuple)))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := NewRowFilter(spec(kvpb.RangeFeedRowFilter_Clause{
+			ColumnID: 2, Op: kvpb.RangeFeedRowFilter_Clause_IN,
+		}))
+		require.Error(t, err)
+		_, err = NewRowFilter(spec(kvpb.RangeFeedRowFilter_Clause{
+			ColumnID: 2, Op: kvpb.RangeFeedRowFilter_Clause_IN,
+			Values: [][]byte{encoding.EncodeIntValue(nil, 2, 5)},
+		}))
+		require.Error(t, err)
+	})
+}
+
+func mustDecodeTupleColumns(t *testing.T, b []byte) []encodedColumn {
+	cols, err := decodeTupleColumns(b)
+	require.NoError(t, err)
+	return cols
+}
+
+// TestRegistryWithRowFilter verifies that events published to a registration
+// with a row filter are filtered before they are sent to the stream.
+func TestRegistryWithRowFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	f, err := NewRowFilter(&kvpb.RangeFeedRowFilter{
+		TableID: rowFilterTableID,
+		IndexID: rowFilterIndexID,
+		Clauses: []kvpb.RangeFeedRowFilter_Clause{{
+			ColumnID: 3,
+			Op:       kvpb.RangeFeedRowFilter_Clause_IN,
+			Values:   [][]byte{encoding.EncodeBytesValue(nil, encoding.NoColumnID, []byte("active"))},
+		}},
+	})
+	require.NoError(t, err)
+
+	span := roachpb.Span{
+		Key:    keys.SystemSQLCodec.TablePrefix(rowFilterTableID),
+		EndKey: keys.SystemSQLCodec.TablePrefix(rowFilterTableID + 1),
+	}
+	testutils.RunValues(t, "registration type=", registrationTestTypes, func(t *testing.T, rt registrationType) {
+		ev1, ev2 := new(kvpb.RangeFeedEvent), new(kvpb.RangeFeedEvent)
+		ev1.MustSetValue(&kvpb.RangeFeedValue{
+			Key:   rowFilterKey(rowFilterTableID, rowFilterIndexID, 1, 0),
+			Value: rowFilterValue(1, strPtr("active")),
+		})
+		ev2.MustSetValue(&kvpb.RangeFeedValue{
+			Key:   rowFilterKey(rowFilterTableID, rowFilterIndexID, 2, 0),
+			Value: rowFilterValue(2, strPtr("done")),
+		})
+
+		reg := makeRegistry(NewMetrics())
+		s := newTestStream()
+		sFiltered := newTestStream()
+		r := newTestRegistration(s, withRSpan(span), withRegistrationType(rt))
+		rFiltered := newTestRegistration(sFiltered, withRSpan(span), withRowFilter(f),
+			withRegistrationType(rt))
+
+		go r.runOutputLoop(ctx, 0)
+		go rFiltered.runOutputLoop(ctx, 0)
+		defer r.Disconnect(nil)
+		defer rFiltered.Disconnect(nil)
+
+		reg.Register(ctx, r)
+		reg.Register(ctx, rFiltered)
+
+		reg.PublishToOverlapping(ctx, span, ev1, logicalOpMetadata{}, nil /* alloc */)
+		reg.PublishToOverlapping(ctx, span, ev2, logicalOpMetadata{}, nil /* alloc */)
+
+		require.NoError(t, reg.waitForCaughtUp(ctx, all))
+		require.Equal(t, []*kvpb.RangeFeedEvent{ev1, ev2}, s.GetAndClearEvents())
+		require.Equal(t, []*kvpb.RangeFeedEvent{ev1}, sFiltered.GetAndClearEvents())
+	})
+}
@@ -226,7 +226,7 @@ func TestStreamManagerErrorHandling(t *testing.T) {
 				stream := sm.NewStream(sID, rID)
 				sm.RegisteringStream(sID)
 				registered, d, _ := p.Register(ctx, h.span, hlc.Timestamp{}, nil, /* catchUpSnap */
-					false /* withDiff */, false /* withFiltering */, false /* withOmitRemote */, noBulkDelivery,
+					false /* withDiff */, false /* withFiltering */, false /* withOmitRemote */, nil /* rowFilter */, noBulkDelivery,
 					stream)
 				require.True(t, registered)
 				go p.StopWithErr(disconnectErr)
@@ -241,7 +241,7 @@ func TestStreamManagerErrorHandling(t *testing.T) {
 			defer stopper.Stop(ctx)
 			sm.RegisteringStream(sID)
 			registered, d, _ := p.Register(ctx, h.span, hlc.Timestamp{}, nil, /* catchUpSnap */
-				false /* withDiff */, false /* withFiltering */, false /* withOmitRemote */, noBulkDelivery,
+				false /* withDiff */, false /* withFiltering */, false /* withOmitRemote */, nil /* rowFilter */, noBulkDelivery,
 				stream)
 			require.True(t, registered)
 			sm.AddStream(sID, d)
@@ -257,7 +257,7 @@ func TestStreamManagerErrorHandling(t *testing.T) {
 			defer stopper.Stop(ctx)
 			sm.RegisteringStream(sID)
 			registered, d, _ := p.Register(ctx, h.span, hlc.Timestamp{}, nil, /* catchUpSnap */
-				false /* withDiff */, false /* withFiltering */, false /* withOmitRemote */, noBulkDelivery,
+				false /* withDiff */, false /* withFiltering */, false /* withOmitRemote */, nil /* rowFilter */, noBulkDelivery,
 				stream)
 			require.True(t, registered)
 			sm.AddStream(sID, d)
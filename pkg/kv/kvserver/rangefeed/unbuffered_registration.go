@@ -91,6 +91,7 @@ func newUnbufferedRegistration(
 	withDiff bool,
 	withFiltering bool,
 	withOmitRemote bool,
+	rowFilter *RowFilter,
 	bulkDeliverySize int,
 	bufferSz int,
 	metrics *Metrics,
@@ -105,6 +106,7 @@ func newUnbufferedRegistration(
 			withDiff,
 			withFiltering,
 			withOmitRemote,
+			rowFilter,
 			bulkDeliverySize,
 			removeRegFromProcessor),
 		metrics: metrics,
@@ -257,7 +259,7 @@ func (ubr *unbufferedRegistration) maybeRunCatchUpScan(ctx context.Context) erro
 		catchUpSnap.Close()
 		ubr.metrics.RangeFeedCatchUpScanNanos.Inc(start.Elapsed().Nanoseconds())
 	}()
-	return catchUpSnap.CatchUpScan(ctx, ubr.stream.SendUnbuffered, ubr.withDiff, ubr.withFiltering,
+	return catchUpSnap.CatchUpScan(ctx, ubr.withRowFilter(ubr.stream.SendUnbuffered), ubr.withDiff, ubr.withFiltering,
 		ubr.withOmitRemote, ubr.bulkDelivery)
 }
 
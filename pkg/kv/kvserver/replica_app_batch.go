@@ -142,8 +142,10 @@ func (b *replicaAppBatch) Stage(
 		return nil, err
 	}
 
-	// Stage the command's write batch in the application batch.
-	if err := b.ab.addWriteBatch(ctx, b.batch.State(), cmd); err != nil {
+	// Stage the command's write batch in the application batch. Witness
+	// replicas only retain the range-local portion of it.
+	witness := isWitness(b.state.Desc, b.r.replicaID)
+	if err := b.ab.addWriteBatch(ctx, b.batch.State(), cmd, witness); err != nil {
 		return nil, err
 	}
 
@@ -156,6 +158,7 @@ func (b *replicaAppBatch) Stage(
 		eng:         b.r.store.StateEngine(),
 		sideloaded:  b.r.logStorage.ls.Sideload,
 		bulkLimiter: b.r.store.limiters.BulkIOWriteRate,
+		witness:     witness,
 	}); err != nil {
 		return nil, err
 	}
@@ -719,6 +722,9 @@ func (b *replicaAppBatch) ApplyToStateMachine(ctx context.Context) error {
 	if needsTruncationByLogSize {
 		r.store.raftLogQueue.MaybeAddAsync(ctx, r, r.store.Clock().NowAsClockTimestamp())
 	}
+	if !b.changeRemovesReplica && isWitness(b.state.Desc, r.replicaID) {
+		r.maybeTruncateWitnessLogRaftMuLocked(ctx, b.state.RaftAppliedIndex, b.state.RaftAppliedIndexTerm)
+	}
 
 	b.recordStatsOnCommit()
 
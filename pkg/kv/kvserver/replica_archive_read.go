@@ -0,0 +1,290 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package kvserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/gc"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/errors"
+)
+
+// canEvaluateAgainstArchive returns whether a read-only batch which reads
+// below the range's GC threshold can be evaluated against the MVCC history
+// archive instead (see gc.ArchiveURI). Only non-locking Get and forward Scan
+// requests over global keys are supported, and only if the reads have no
+// uncertainty interval, which is the case for AS OF SYSTEM TIME reads.
+func canEvaluateAgainstArchive(ba *kvpb.BatchRequest) bool {
+	if !ba.IsReadOnly() || ba.TimestampFromServerClock != nil {
+		return false
+	}
+	if ba.Txn != nil && ba.Txn.ReadTimestamp.Less(ba.Txn.GlobalUncertaintyLimit) {
+		return false
+	}
+	for _, ru := range ba.Requests {
+		switch req := ru.GetInner().(type) {
+		case *kvpb.GetRequest:
+			if req.KeyLockingStrength != lock.None || req.ReturnRawMVCCValues {
+				return false
+			}
+		case *kvpb.ScanRequest:
+			if req.KeyLockingStrength != lock.None || req.ReturnRawMVCCValues ||
+				req.ScanFormat == kvpb.COL_BATCH_RESPONSE {
+				return false
+			}
+		default:
+			return false
+		}
+		if keys.IsLocal(ru.GetInner().Header().Key) {
+			return false
+		}
+	}
+	return true
+}
+
+// evaluateReadOnlyBatchAgainstArchive evaluates a batch which reads below the
+// range's GC threshold against the MVCC history archive. The batch must
+// satisfy canEvaluateAgainstArchive. Returns an error if no archive is
+// configured or if it does not contain the complete history of the batch's
+// span as of the batch's timestamp.
+//
+// The archive holds neither intents nor locks, and no write can be evaluated
+// at or below the GC threshold, so there is nothing for such reads to
+// conflict with and the timestamp cache does not need to be updated.
+func (r *Replica) evaluateReadOnlyBatchAgainstArchive(
+	ctx context.Context, ba *kvpb.BatchRequest,
+) (*kvpb.BatchResponse, error) {
+	uri := gc.ArchiveURI.Get(&r.store.ClusterSettings().SV)
+	if uri == "" || r.store.cfg.ExternalStorageFromURI == nil {
+		return nil, errors.New("no MVCC history archive is configured")
+	}
+	es, err := r.store.cfg.ExternalStorageFromURI(ctx, uri, username.NodeUserName())
+	if err != nil {
+		return nil, errors.Wrap(err, "opening MVCC history archive")
+	}
+	defer es.Close()
+	rSpan, err := keys.Range(ba.Requests)
+	if err != nil {
+		return nil, err
+	}
+	iter, err := gc.NewArchiveIterator(ctx, es, rSpan.AsRawSpanWithNoLocals(), ba.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	br := ba.CreateReply()
+	limits := archiveScanLimits{
+		maxKeys:     ba.MaxSpanRequestKeys,
+		targetBytes: ba.TargetBytes,
+		wholeRows:   ba.WholeRowsOfSize > 1,
+		allowEmpty:  ba.AllowEmpty,
+	}
+	for i, ru := range ba.Requests {
+		switch req := ru.GetInner().(type) {
+		case *kvpb.GetRequest:
+			reply := br.Responses[i].GetGet()
+			span := roachpb.Span{Key: req.Key, EndKey: req.Key.Next()}
+			res, err := limits.scan(iter, span)
+			if err != nil {
+				return nil, err
+			}
+			res.fill(&reply.ResponseHeader)
+			if len(res.kvs) > 0 {
+				reply.Value = &res.kvs[0].Value
+			}
+			if reply.ResumeSpan != nil {
+				reply.ResumeSpan.EndKey = nil
+			}
+		case *kvpb.ScanRequest:
+			reply := br.Responses[i].GetScan()
+			res, err := limits.scan(iter, req.Span())
+			if err != nil {
+				return nil, err
+			}
+			res.fill(&reply.ResponseHeader)
+			if req.ScanFormat == kvpb.BATCH_RESPONSE {
+				if repr := res.batchResponse(); len(repr) > 0 {
+					reply.BatchResponses = [][]byte{repr}
+				}
+			} else {
+				reply.Rows = res.kvs
+			}
+		default:
+			return nil, errors.AssertionFailedf("unsupported request %s", req.Method())
+		}
+	}
+	if ba.Txn != nil {
+		br.Txn = ba.Txn.Clone()
+	}
+	br.Timestamp = ba.Timestamp
+	return br, nil
+}
+
+// archiveScanLimits tracks the limits of a batch evaluated against the MVCC
+// history archive across its requests. Like in evaluateBatch, an exhausted
+// limit is represented by -1.
+type archiveScanLimits struct {
+	maxKeys, targetBytes int64
+	wholeRows            bool
+	allowEmpty           bool
+}
+
+// archiveScanResult is the result of a request evaluated against the MVCC
+// history archive.
+type archiveScanResult struct {
+	kvs          []roachpb.KeyValue
+	numBytes     int64
+	resumeKey    roachpb.Key
+	resumeEnd    roachpb.Key
+	resumeReason kvpb.ResumeReason
+}
+
+// archiveKVSize returns the size a key-value pair is accounted for in the
+// batch limits, which matches the size of its BATCH_RESPONSE encoding.
+func archiveKVSize(kv roachpb.KeyValue) int64 {
+	key := storage.MVCCKey{Key: kv.Key, Timestamp: kv.Value.Timestamp}
+	return int64(8 + key.Len() + len(kv.Value.RawBytes))
+}
+
+// scan collects the live key-value pairs in span from the iterator, which
+// surfaces the latest live version of each key as of the batch's timestamp.
+// It stops, setting a resume key, once a limit of the batch is reached. If
+// the batch asks for whole rows, the scan stops at a row boundary.
+func (l *archiveScanLimits) scan(
+	iter storage.SimpleMVCCIterator, span roachpb.Span,
+) (archiveScanResult, error) {
+	res := archiveScanResult{resumeEnd: span.EndKey}
+	if l.maxKeys < 0 || l.targetBytes < 0 {
+		res.resumeKey = span.Key
+		if l.maxKeys < 0 {
+			res.resumeReason = kvpb.RESUME_KEY_LIMIT
+		} else {
+			res.resumeReason = kvpb.RESUME_BYTE_LIMIT
+		}
+		return res, nil
+	}
+	// rowStart is the index of the first key-value pair of the last row in
+	// res.kvs.
+	var rowStart int
+	for iter.SeekGE(storage.MVCCKey{Key: span.Key}); ; iter.NextKey() {
+		if ok, err := iter.Valid(); err != nil {
+			return archiveScanResult{}, err
+		} else if !ok {
+			break
+		}
+		unsafeKey := iter.UnsafeKey()
+		if unsafeKey.Key.Compare(span.EndKey) >= 0 {
+			break
+		}
+		raw, err := iter.UnsafeValue()
+		if err != nil {
+			return archiveScanResult{}, err
+		}
+		v, err := storage.DecodeMVCCValue(raw)
+		if err != nil {
+			return archiveScanResult{}, err
+		}
+		kv := roachpb.KeyValue{
+			Key:   unsafeKey.Key.Clone(),
+			Value: roachpb.Value{RawBytes: bytes.Clone(v.Value.RawBytes), Timestamp: unsafeKey.Timestamp},
+		}
+		newRow := len(res.kvs) == 0 || !l.wholeRows || !sameRow(res.kvs[len(res.kvs)-1].Key, kv.Key)
+		if newRow {
+			rowStart = len(res.kvs)
+		}
+		size := archiveKVSize(kv)
+		var reason kvpb.ResumeReason
+		if l.maxKeys > 0 && int64(len(res.kvs)) >= l.maxKeys {
+			reason = kvpb.RESUME_KEY_LIMIT
+		} else if l.targetBytes > 0 && res.numBytes+size > l.targetBytes &&
+			(len(res.kvs) > 0 || l.allowEmpty) {
+			reason = kvpb.RESUME_BYTE_LIMIT
+		}
+		if reason != 0 {
+			switch {
+			case newRow:
+				res.resumeKey = kv.Key
+			case rowStart > 0 || l.allowEmpty:
+				// Drop the partial row at the end of the result.
+				res.resumeKey = res.kvs[rowStart].Key
+				for _, dropped := range res.kvs[rowStart:] {
+					res.numBytes -= archiveKVSize(dropped)
+				}
+				res.kvs = res.kvs[:rowStart]
+			default:
+				// Complete the first row, even though it exceeds the limit.
+				reason = 0
+			}
+			if reason != 0 {
+				res.resumeReason = reason
+				break
+			}
+		}
+		res.kvs = append(res.kvs, kv)
+		res.numBytes += size
+	}
+	l.consume(res)
+	return res, nil
+}
+
+// consume charges the result of a request to the limits of the batch.
+func (l *archiveScanLimits) consume(res archiveScanResult) {
+	if n := int64(len(res.kvs)); l.maxKeys > 0 && n > 0 {
+		if n < l.maxKeys {
+			l.maxKeys -= n
+		} else {
+			l.maxKeys = -1
+		}
+	}
+	if l.targetBytes > 0 {
+		if res.resumeReason == kvpb.RESUME_BYTE_LIMIT || res.numBytes >= l.targetBytes {
+			l.targetBytes = -1
+		} else {
+			l.targetBytes -= res.numBytes
+		}
+	}
+}
+
+// fill populates the response header of the request from the result.
+func (res archiveScanResult) fill(h *kvpb.ResponseHeader) {
+	h.NumKeys = int64(len(res.kvs))
+	h.NumBytes = res.numBytes
+	if res.resumeKey != nil {
+		h.ResumeSpan = &roachpb.Span{Key: res.resumeKey, EndKey: res.resumeEnd}
+		h.ResumeReason = res.resumeReason
+	}
+}
+
+// batchResponse returns the result in the BATCH_RESPONSE scan format.
+func (res archiveScanResult) batchResponse() []byte {
+	repr := make([]byte, 0, res.numBytes)
+	for _, kv := range res.kvs {
+		key := storage.EncodeMVCCKey(storage.MVCCKey{Key: kv.Key, Timestamp: kv.Value.Timestamp})
+		repr = binary.LittleEndian.AppendUint32(repr, uint32(len(kv.Value.RawBytes)))
+		repr = binary.LittleEndian.AppendUint32(repr, uint32(len(key)))
+		repr = append(repr, key...)
+		repr = append(repr, kv.Value.RawBytes...)
+	}
+	return repr
+}
+
+// sameRow returns whether the two keys belong to the same SQL row.
+func sameRow(a, b roachpb.Key) bool {
+	n, err := keys.GetRowPrefixLength(a)
+	if err != nil || n <= 0 || n >= len(a) {
+		return false
+	}
+	return bytes.HasPrefix(b, a[:n]) && len(b) > n
+}
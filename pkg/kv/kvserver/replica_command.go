@@ -791,17 +791,23 @@ func (r *Replica) AdminMerge(
 		// queues should fix things up quickly).
 		lReplicas, rReplicas := origLeftDesc.Replicas(), rightDesc.Replicas()
 
-		if len(lReplicas.VoterFullAndNonVoterDescriptors()) != len(lReplicas.Descriptors()) {
+		if len(lReplicas.VoterFullNonVoterAndWitnessDescriptors()) != len(lReplicas.Descriptors()) {
 			return errors.Errorf("cannot merge ranges when lhs is in a joint state or has learners: %s",
 				lReplicas)
 		}
-		if len(rReplicas.VoterFullAndNonVoterDescriptors()) != len(rReplicas.Descriptors()) {
+		if len(rReplicas.VoterFullNonVoterAndWitnessDescriptors()) != len(rReplicas.Descriptors()) {
 			return errors.Errorf("cannot merge ranges when rhs is in a joint state or has learners: %s",
 				rReplicas)
 		}
 		if !replicasCollocated(lReplicas.Descriptors(), rReplicas.Descriptors()) {
 			return errors.Errorf("ranges not collocated; %s != %s", lReplicas, rReplicas)
 		}
+		// Witnesses hold no user data, so a store that holds a witness on one
+		// side and a data-bearing replica on the other would end up with only
+		// part of the merged range's data.
+		if !replicasCollocated(lReplicas.WitnessDescriptors(), rReplicas.WitnessDescriptors()) {
+			return errors.Errorf("witnesses not collocated; %s != %s", lReplicas, rReplicas)
+		}
 
 		disableWaitForReplicasInTesting := r.store.TestingKnobs() != nil &&
 			r.store.TestingKnobs().DisableMergeWaitForReplicasInit
@@ -1241,8 +1247,10 @@ func (r *Replica) changeReplicasImpl(
 	// 1. Promotions / demotions / swaps between voters and non-voters
 	// 2. Voter additions
 	// 3. Voter removals
-	// 4. Non-voter additions
-	// 5. Non-voter removals
+	// 4. Witness additions
+	// 5. Witness removals
+	// 6. Non-voter additions
+	// 7. Non-voter removals
 	//
 	// This order is meant to be symmetric with how the allocator prioritizes
 	// these actions. Broadly speaking, we first want to add a missing voter (and
@@ -1317,6 +1325,38 @@ func (r *Replica) changeReplicasImpl(
 		}
 	}
 
+	if adds := targets.WitnessAdditions; len(adds) > 0 {
+		// Witnesses are added directly as voters rather than through the
+		// learner+snapshot+voter cycle: they hold no user data, so catching them up
+		// only takes a snapshot of the range's metadata.
+		for _, target := range adds {
+			iChgs := []internalReplicationChange{{target: target, typ: internalChangeTypeAddWitness}}
+			var err error
+			desc, err = execChangeReplicasTxn(ctx, r.store.cfg.Tracer(), desc, reason, details, iChgs,
+				changeReplicasTxnArgs{
+					db:                                   r.store.DB(),
+					liveAndDeadReplicas:                  r.store.cfg.StorePool.LiveAndDeadReplicas,
+					logChange:                            r.store.logChange,
+					testForceJointConfig:                 r.store.TestingKnobs().ReplicationAlwaysUseJointConfig,
+					testAllowDangerousReplicationChanges: r.store.TestingKnobs().AllowDangerousReplicationChanges,
+				})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if removals := targets.WitnessRemovals; len(removals) > 0 {
+		// Like voters, witnesses are demoted to learners before they are removed.
+		desc, err = r.execReplicationChangesForVoters(ctx, desc, reason, details, nil /* voterAdditions */, removals)
+		if err != nil {
+			if _, err := r.maybeLeaveAtomicChangeReplicas(ctx, r.Desc()); err != nil {
+				return nil, err
+			}
+			return nil, err
+		}
+	}
+
 	if adds := targets.NonVoterAdditions; len(adds) > 0 {
 		// Add all non-voters and send them initial snapshots since some callers of
 		// `AdminChangeReplicas` (notably the mergeQueue, via `AdminRelocateRange`)
@@ -1370,6 +1410,7 @@ type TargetsForReplicationChanges struct {
 	VoterDemotions, NonVoterPromotions  []roachpb.ReplicationTarget
 	VoterAdditions, VoterRemovals       []roachpb.ReplicationTarget
 	NonVoterAdditions, NonVoterRemovals []roachpb.ReplicationTarget
+	WitnessAdditions, WitnessRemovals   []roachpb.ReplicationTarget
 }
 
 // SynthesizeTargetsByChangeType groups replication changes in the
@@ -1396,6 +1437,9 @@ func SynthesizeTargetsByChangeType(
 	result.NonVoterAdditions = subtractTargets(chgs.NonVoterAdditions(), chgs.VoterRemovals())
 	result.NonVoterRemovals = subtractTargets(chgs.NonVoterRemovals(), chgs.VoterAdditions())
 
+	result.WitnessAdditions = chgs.WitnessAdditions()
+	result.WitnessRemovals = chgs.WitnessRemovals()
+
 	return result
 }
 
@@ -1654,6 +1698,11 @@ func validateAdditionsPerStore(
 					return errors.AssertionFailedf(
 						"trying to add a non-voter to a store that already has a %s", t)
 				}
+			case roachpb.WITNESS:
+				// Witnesses hold no data, so they can't be turned into another type
+				// of replica in place.
+				return errors.AssertionFailedf(
+					"trying to add(%+v) to a store that already has a %s", chg, t)
 			default:
 				return errors.AssertionFailedf("store(%d) being added to already contains a"+
 					" replica of an unexpected type: %s", storeID, t)
@@ -1688,6 +1737,11 @@ func validateRemovals(desc *roachpb.RangeDescriptor, chgsByStoreID changesByStor
 					return errors.AssertionFailedf("type of replica being removed (%s) does not match"+
 						" expectation for change: %+v", t, chg)
 				}
+			case roachpb.WITNESS:
+				if chg.ChangeType != roachpb.REMOVE_WITNESS {
+					return errors.AssertionFailedf("type of replica being removed (%s) does not match"+
+						" expectation for change: %+v", t, chg)
+				}
 			default:
 				return errors.AssertionFailedf("unexpected replica type for removal %+v: %s", chg, t)
 			}
@@ -2112,7 +2166,8 @@ func (r *Replica) execReplicationChangesForVoters(
 
 	for _, target := range voterRemovals {
 		typ := internalChangeTypeRemoveLearner
-		if rDesc, ok := desc.GetReplicaDescriptor(target.StoreID); ok && rDesc.Type == roachpb.VOTER_FULL {
+		if rDesc, ok := desc.GetReplicaDescriptor(target.StoreID); ok &&
+			(rDesc.Type == roachpb.VOTER_FULL || rDesc.Type == roachpb.WITNESS) {
 			typ = internalChangeTypeDemoteVoterToLearner
 		}
 		iChgs = append(iChgs, internalReplicationChange{target: target, typ: typ})
@@ -2212,17 +2267,20 @@ const (
 	_ internalChangeType = iota + 1
 	internalChangeTypeAddLearner
 	internalChangeTypeAddNonVoter
+	// internalChangeTypeAddWitness adds a WITNESS, which is a voter from the
+	// start.
+	internalChangeTypeAddWitness
 	// NB: internalChangeTypePromote{Learner,Voter} are quite similar to each
 	// other. We only chose to differentiate them in order to be able to assert on
 	// the type of replica being promoted. See `prepareChangeReplicasTrigger`.
 	internalChangeTypePromoteLearner
 	internalChangeTypePromoteNonVoter
-	// internalChangeTypeDemoteVoterToLearner changes a voter to an ephemeral
-	// learner. This will necessarily go through joint consensus since it requires
-	// two individual changes (only one changes the quorum, so we could allow it
-	// in a simple change too, with some work here and upstream). Demotions are
-	// treated like removals throughout (i.e. they show up in
-	// `ChangeReplicasTrigger.Removed()`, but not in `.Added()`).
+	// internalChangeTypeDemoteVoterToLearner changes a voter (or a witness) to
+	// an ephemeral learner. This will necessarily go through joint consensus
+	// since it requires two individual changes (only one changes the quorum, so
+	// we could allow it in a simple change too, with some work here and
+	// upstream). Demotions are treated like removals throughout (i.e. they show
+	// up in `ChangeReplicasTrigger.Removed()`, but not in `.Added()`).
 	internalChangeTypeDemoteVoterToLearner
 	// internalChangeTypeDemoteVoterToNonVoter demotes a voter to a non-voter.
 	// This, like the demotion to learner, will go through joint consensus.
@@ -2285,6 +2343,13 @@ func prepareChangeReplicasTrigger(
 			case internalChangeTypeAddNonVoter:
 				added = append(added,
 					updatedDesc.AddReplica(chg.target.NodeID, chg.target.StoreID, roachpb.NON_VOTER))
+			case internalChangeTypeAddWitness:
+				if useJoint {
+					return nil, errors.Errorf("witness %v must be added in a simple configuration change",
+						chg.target)
+				}
+				added = append(added,
+					updatedDesc.AddReplica(chg.target.NodeID, chg.target.StoreID, roachpb.WITNESS))
 			case internalChangeTypePromoteLearner:
 				typ := roachpb.VOTER_FULL
 				if useJoint {
@@ -2327,7 +2392,7 @@ func prepareChangeReplicasTrigger(
 					// there's a demotion. This is just a sanity check.
 					return nil, errors.AssertionFailedf("demotions require joint consensus")
 				}
-				if prevTyp := rDesc.Type; prevTyp != roachpb.VOTER_FULL {
+				if prevTyp := rDesc.Type; prevTyp != roachpb.VOTER_FULL && prevTyp != roachpb.WITNESS {
 					return nil, errors.Errorf("cannot transition from %s to VOTER_DEMOTING_LEARNER", prevTyp)
 				}
 				rDesc, _, _ = updatedDesc.SetReplicaType(chg.target.NodeID, chg.target.StoreID, roachpb.VOTER_DEMOTING_LEARNER)
@@ -2752,9 +2817,12 @@ func (r *Replica) getSenderReplicas(
 	}
 
 	// Include voter and non-voter replicas on healthy stores as candidates.
+	// Witnesses are never candidates: they hold no user data, so a snapshot sent
+	// from one would be missing the range's data.
 	nonRecipientReplicas := rangeDesc.Replicas().Filter(
 		func(rDesc roachpb.ReplicaDescriptor) bool {
-			return rDesc.ReplicaID != recipient.ReplicaID && storePool.IsStoreHealthy(rDesc.StoreID)
+			return rDesc.ReplicaID != recipient.ReplicaID && !rDesc.IsWitness() &&
+				storePool.IsStoreHealthy(rDesc.StoreID)
 		},
 	)
 	candidates := nonRecipientReplicas.VoterAndNonVoterDescriptors()
@@ -3307,12 +3375,14 @@ func (r *Replica) followerSendSnapshot(
 	// sstables in shared storage as opposed to streaming their contents. Keys
 	// in higher levels of the LSM are still streamed in the snapshot.
 	nonSystemRange := snap.State.Desc.StartKey.AsRawKey().Compare(keys.TableDataMin) >= 0
-	sharedReplicate := r.store.cfg.SharedStorageEnabled && nonSystemRange
+	// Witnesses don't receive the user keys of the range at all.
+	witness := req.RecipientReplica.IsWitness()
+	sharedReplicate := r.store.cfg.SharedStorageEnabled && nonSystemRange && !witness
 
 	// Use external replication if we aren't using shared
 	// replication, are dealing with a non-system range, are on at
 	// least 24.1, and our store has external files.
-	externalReplicate := !sharedReplicate && nonSystemRange &&
+	externalReplicate := !sharedReplicate && nonSystemRange && !witness &&
 		externalFileSnapshotting.Get(&r.store.ClusterSettings().SV)
 	if externalReplicate {
 		start := snap.State.Desc.StartKey.AsRawKey()
@@ -3808,6 +3878,12 @@ func RelocateOne(
 	transferLeaseToFirstVoter bool,
 	options RelocateOneOptions,
 ) ([]kvpb.ReplicationChange, *roachpb.ReplicationTarget, error) {
+	if len(desc.Replicas().WitnessDescriptors()) > 0 {
+		// Relocation only knows about voter and non-voter targets, and would
+		// otherwise remove the witnesses or move them like data-bearing replicas.
+		return nil, nil, errors.Errorf(
+			`relocating ranges with witness replicas is not supported: %s`, desc)
+	}
 	if repls := desc.Replicas(); len(repls.VoterFullAndNonVoterDescriptors()) != len(repls.Descriptors()) {
 		// The caller removed all the learners and left the joint config, so there
 		// shouldn't be anything but voters and non_voters.
@@ -6,17 +6,21 @@
 package kvserver
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha512"
 	"encoding/binary"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/allocatorimpl"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
@@ -226,13 +230,24 @@ func (r *Replica) checkConsistencyImpl(
 		return resp, nil
 	}
 
+	var terminate []roachpb.ReplicaDescriptor
+	for _, idxs := range shaToIdxs[minoritySHA] {
+		terminate = append(terminate, results[idxs].Replica)
+	}
+	if ConsistencyRepairEnabled.Get(&r.ClusterSettings().SV) {
+		if unrepaired, attempted := r.repairInconsistency(ctx, args, res.Detail, results); attempted {
+			if len(unrepaired) == 0 {
+				return resp, nil
+			}
+			terminate = unrepaired
+		}
+	}
+
 	// No checkpoint was requested, so we want to re-run the check with
 	// checkpoints and termination of suspicious nodes. Note that this recursive
 	// call will be terminated in the `args.Checkpoint` branch above.
 	args.Checkpoint = true
-	for _, idxs := range shaToIdxs[minoritySHA] {
-		args.Terminate = append(args.Terminate, results[idxs].Replica)
-	}
+	args.Terminate = terminate
 	// args.Terminate is a slice of properly redactable values, but
 	// with %v `redact` will not realize that and will redact the
 	// whole thing. Wrap it as a ReplicaSet which is a SafeFormatter
@@ -260,6 +275,174 @@ func (r *Replica) checkConsistencyImpl(
 	return resp, nil
 }
 
+// repairInconsistency attempts to repair the replicas which diverged from the
+// leaseholder in a consistency check run by the queue, as an alternative to
+// terminating their nodes. The repair is only attempted if the leaseholder's
+// replica agrees with a quorum of the range. The evidence is preserved first:
+// all replicas are checkpointed, and the results of the check are written to
+// a bundle which is picked up by debug zip. Each diverging replica is then
+// removed from the range, which quarantines it, and replaced by a replica on
+// another store chosen by the allocator, which is populated from a learner
+// snapshot of a healthy replica.
+//
+// Returns whether the repair was attempted and, if so, the diverging replicas
+// which could not be removed from the range.
+func (r *Replica) repairInconsistency(
+	ctx context.Context,
+	args kvpb.ComputeChecksumRequest,
+	detail string,
+	results []ConsistencyCheckResult,
+) (unrepaired []roachpb.ReplicaDescriptor, attempted bool) {
+	var localChecksum []byte
+	for _, res := range results {
+		if res.Err == nil && res.Replica.ReplicaID == r.replicaID {
+			localChecksum = res.Response.Checksum
+		}
+	}
+	if localChecksum == nil {
+		return nil, false
+	}
+	healthy := make(map[roachpb.ReplicaID]bool, len(results))
+	var diverging []roachpb.ReplicaDescriptor
+	for _, res := range results {
+		if res.Err != nil {
+			continue
+		}
+		if bytes.Equal(res.Response.Checksum, localChecksum) {
+			healthy[res.Replica.ReplicaID] = true
+		} else {
+			diverging = append(diverging, res.Replica)
+		}
+	}
+	if len(diverging) == 0 || !r.Desc().Replicas().CanMakeProgress(
+		func(rDesc roachpb.ReplicaDescriptor) bool { return healthy[rDesc.ReplicaID] },
+	) {
+		return nil, false
+	}
+	{
+		var tmp redact.SafeFormatter = roachpb.MakeReplicaSet(diverging)
+		log.KvExec.Errorf(ctx, "consistency check failed; checkpointing and repairing minority %v", tmp)
+	}
+
+	// Re-run the check with checkpoints, but without terminating anyone. The
+	// recursive call is terminated in the `args.Checkpoint` branch of
+	// checkConsistencyImpl.
+	args.Checkpoint = true
+	args.Terminate = nil
+	if _, pErr := r.checkConsistencyImpl(ctx, args); pErr != nil {
+		log.KvExec.Errorf(ctx, "replica inconsistency detected; checkpointing failed: %s", pErr)
+		return nil, false
+	}
+	bundle := r.writeConsistencyRepairBundle(ctx, detail, diverging)
+
+	for _, rDesc := range diverging {
+		removed, err := r.replaceDivergingReplica(ctx, rDesc, bundle)
+		if err == nil {
+			continue
+		}
+		if !removed {
+			log.KvExec.Errorf(ctx, "unable to repair diverging replica %s: %v", rDesc, err)
+			unrepaired = append(unrepaired, rDesc)
+			continue
+		}
+		// The diverging replica is gone, but the range is now under-replicated.
+		// Hand it to the replicate queue, which retries with backoff.
+		r.store.metrics.ConsistencyRepairFailures.Inc(1)
+		log.KvExec.Errorf(ctx, "removed diverging replica %s, but unable to replace it: %v", rDesc, err)
+		r.store.replicateQueue.MaybeAddAsync(ctx, r, r.store.Clock().NowAsClockTimestamp())
+	}
+	return unrepaired, true
+}
+
+// replaceDivergingReplica removes the specified replica from the range and
+// replaces it with a new replica on a store chosen by the allocator, which
+// never picks the store or node of the diverging replica. The replica is
+// removed first, so that it is quarantined even if no replacement can be
+// added. Returns whether the replica was removed, along with an error if it
+// could not be removed or replaced.
+func (r *Replica) replaceDivergingReplica(
+	ctx context.Context, rDesc roachpb.ReplicaDescriptor, bundle string,
+) (removed bool, _ error) {
+	removeType, targetType := roachpb.REMOVE_VOTER, allocatorimpl.VoterTarget
+	if !rDesc.IsAnyVoter() {
+		removeType, targetType = roachpb.REMOVE_NON_VOTER, allocatorimpl.NonVoterTarget
+	}
+	details := fmt.Sprintf("replica %s diverged from a quorum of the range", rDesc)
+	if bundle != "" {
+		details += fmt.Sprintf("; evidence in %s", bundle)
+	}
+	desc, err := r.changeReplicasImpl(ctx, r.Desc(), kvserverpb.SnapshotRequest_OTHER, 0, /* priority */
+		kvserverpb.ReasonConsistencyRepair, details, kvpb.MakeReplicationChanges(removeType,
+			roachpb.ReplicationTarget{NodeID: rDesc.NodeID, StoreID: rDesc.StoreID}))
+	if err != nil {
+		return false, errors.Wrap(err, "removing replica")
+	}
+
+	conf, err := r.LoadSpanConfig(ctx)
+	if err != nil {
+		return true, err
+	}
+	// The diverging replica is passed to the allocator as an existing replica,
+	// which rules out its node.
+	voters, nonVoters := desc.Replicas().VoterDescriptors(), desc.Replicas().NonVoterDescriptors()
+	if targetType == allocatorimpl.VoterTarget {
+		voters = append(voters, rDesc)
+	} else {
+		nonVoters = append(nonVoters, rDesc)
+	}
+	target, _, err := r.store.allocator.AllocateTarget(ctx, r.store.cfg.StorePool, conf,
+		voters, nonVoters, &rDesc, allocatorimpl.Dead, targetType)
+	if err != nil {
+		return true, errors.Wrap(err, "allocating replacement replica")
+	}
+	chgs := kvpb.MakeReplicationChanges(roachpb.ADD_NON_VOTER, target)
+	if targetType == allocatorimpl.VoterTarget {
+		chgs = kvpb.MakeReplicationChanges(roachpb.ADD_VOTER, target)
+		if _, ok := desc.GetReplicaDescriptor(target.StoreID); ok {
+			// The target already has a non-voter, which is promoted.
+			chgs = kvpb.ReplicationChangesForPromotion(target)
+		}
+	}
+	// A new replica is added as a learner and receives a snapshot from a
+	// healthy replica before it is promoted, if necessary.
+	if _, err := r.changeReplicasImpl(ctx, desc, kvserverpb.SnapshotRequest_OTHER, 0, /* priority */
+		kvserverpb.ReasonConsistencyRepair, details, chgs); err != nil {
+		return true, errors.Wrapf(err, "adding replacement replica on %s", target)
+	}
+	return true, nil
+}
+
+// writeConsistencyRepairBundle writes the results of a failed consistency
+// check to the store's consistency repair directory, and returns the name of
+// the file, or an empty string if none was written.
+func (r *Replica) writeConsistencyRepairBundle(
+	ctx context.Context, detail string, diverging []roachpb.ReplicaDescriptor,
+) string {
+	dir := r.store.cfg.ConsistencyRepairDir
+	if dir == "" {
+		log.KvExec.Warningf(ctx, "no consistency repair directory configured; not writing evidence bundle")
+		return ""
+	}
+	name := fmt.Sprintf("consistency_repair.%s.r%d.txt",
+		timeutil.Now().UTC().Format("2006-01-02T15_04_05.000"), r.RangeID)
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "range: %s\n", r.Desc())
+	fmt.Fprintf(&buf, "leaseholder: n%d,s%d\n", r.store.NodeID(), r.store.StoreID())
+	fmt.Fprintf(&buf, "diverging replicas: %s\n", roachpb.MakeReplicaSet(diverging))
+	fmt.Fprintf(&buf, "checkpoints: %s/checkpoints/r%d_at_* on each store hosting the range\n",
+		r.store.TODOBothEngines().GetAuxiliaryDir(), r.RangeID)
+	fmt.Fprintf(&buf, "\nresults:%s", detail)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.KvExec.Warningf(ctx, "unable to write consistency repair bundle: %v", err)
+		return ""
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(buf.String()), 0644); err != nil {
+		log.KvExec.Warningf(ctx, "unable to write consistency repair bundle: %v", err)
+		return ""
+	}
+	return name
+}
+
 // A ConsistencyCheckResult contains the outcome of a CollectChecksum call.
 type ConsistencyCheckResult struct {
 	Replica  roachpb.ReplicaDescriptor
@@ -302,7 +485,11 @@ func (r *Replica) runConsistencyCheck(
 	}
 	ccRes := res.(*kvpb.ComputeChecksumResponse)
 
-	replicas := r.Desc().Replicas().Descriptors()
+	// Witnesses hold no user data, so their checksums can't be compared
+	// against those of the data-bearing replicas.
+	replicas := r.Desc().Replicas().Filter(func(rDesc roachpb.ReplicaDescriptor) bool {
+		return !rDesc.IsWitness()
+	}).Descriptors()
 	resultCh := make(chan ConsistencyCheckResult, len(replicas))
 	results := make([]ConsistencyCheckResult, 0, len(replicas))
 
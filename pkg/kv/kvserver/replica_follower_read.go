@@ -7,6 +7,7 @@ package kvserver
 
 import (
 	"context"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvbase"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
@@ -14,6 +15,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing/tracingpb"
 	"github.com/cockroachdb/redact"
@@ -80,6 +83,50 @@ func (r *Replica) canServeFollowerRead(
 	return true
 }
 
+// maybeWaitForClosedTimestamp waits for the closed timestamp of the replica to
+// reach the required frontier of a batch which sets WaitForClosedTimestamp, so
+// that the batch can be served as a follower read instead of being redirected
+// to the leaseholder. The wait is bounded by closedts.FollowerReadMaxWait. If
+// the closed timestamp does not catch up in time, the batch proceeds and is
+// redirected as usual.
+func (r *Replica) maybeWaitForClosedTimestamp(ctx context.Context, ba *kvpb.BatchRequest) error {
+	if !ba.WaitForClosedTimestamp || !kvpb.BatchCanBeEvaluatedOnFollower(ctx, ba) ||
+		!closedts.FollowerReadsEnabled.Get(&r.store.cfg.Settings.SV) {
+		return nil
+	}
+	maxWait := closedts.FollowerReadMaxWait.Get(&r.store.cfg.Settings.SV)
+	requiredFrontier := ba.RequiredFrontier()
+	if maxWait == 0 || requiredFrontier.LessEq(r.GetCurrentClosedTimestamp(ctx)) {
+		return nil
+	}
+	if r.OwnsValidLease(ctx, r.Clock().NowAsClockTimestamp()) {
+		// The leaseholder does not need the closed timestamp to serve the read.
+		return nil
+	}
+
+	start := timeutil.Now()
+	opts := retry.Options{
+		InitialBackoff: 5 * time.Millisecond,
+		MaxBackoff:     100 * time.Millisecond,
+		Multiplier:     2,
+		MaxDuration:    maxWait,
+		Closer:         r.store.Stopper().ShouldQuiesce(),
+	}
+	for rt := retry.StartWithCtx(ctx, opts); rt.Next(); {
+		if closed := r.GetCurrentClosedTimestamp(ctx); requiredFrontier.LessEq(closed) {
+			log.Eventf(ctx, "waited %s for closed timestamp to reach %s",
+				timeutil.Since(start), requiredFrontier)
+			return nil
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	log.Eventf(ctx, "gave up waiting for closed timestamp to reach %s after %s",
+		requiredFrontier, timeutil.Since(start))
+	return nil
+}
+
 // getCurrentClosedTimestampRLocked is like GetCurrentClosedTimestamp, except
 // that it requires r.mu to be RLocked. It also optionally takes a hint: if
 // sufficient is not empty, getClosedTimestampRLocked might return a timestamp
@@ -384,6 +384,8 @@ func (r *Replica) initRaftGroupRaftMuLockedReplicaMuLocked() error {
 	if err != nil {
 		return err
 	}
+	// Witnesses vote but hold no user data, so they must never lead the range.
+	rg.SetCampaignDisabled(isWitness(r.shMu.state.Desc, r.replicaID))
 	r.mu.internalRaftGroup = rg
 	r.mu.raftTracer = *rafttrace.NewRaftTracer(ctx, r.Tracer, r.ClusterSettings(), &r.store.concurrentRaftTraces)
 	r.flowControlV2.InitRaftLocked(
@@ -505,6 +507,9 @@ func (r *Replica) setDescLockedRaftMuLocked(ctx context.Context, desc *roachpb.R
 	r.concMgr.OnRangeDescUpdated(desc)
 	r.shMu.state.Desc = desc
 	r.flowControlV2.OnDescChangedLocked(ctx, desc, r.mu.tenantID)
+	if r.mu.internalRaftGroup != nil {
+		r.mu.internalRaftGroup.SetCampaignDisabled(isWitness(desc, r.replicaID))
+	}
 
 	// Give the liveness and meta ranges high priority in the Raft scheduler, to
 	// avoid head-of-line blocking and high scheduling latency.
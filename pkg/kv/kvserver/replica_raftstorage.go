@@ -521,6 +521,10 @@ func (r *Replica) applySnapshotRaftMuLocked(
 					r.store.metrics.RangeSnapshotsAppliedByVoters.Inc(1)
 				case roachpb.NON_VOTER:
 					r.store.metrics.RangeSnapshotsAppliedByNonVoters.Inc(1)
+				case roachpb.WITNESS:
+					// Witness snapshots carry no user data; they are counted with
+					// the voters since witnesses participate in quorum.
+					r.store.metrics.RangeSnapshotsAppliedByVoters.Inc(1)
 				default:
 					log.KvExec.Fatalf(ctx, "unexpected replica type %s while applying snapshot", desc.Type)
 				}
@@ -923,6 +923,13 @@ func (r *Replica) requestLeaseLocked(
 	if err != nil {
 		return r.mu.pendingLeaseRequest.newResolvedHandle(kvpb.NewError(err))
 	}
+	// Witnesses hold no user data, so they can never serve as the leaseholder.
+	// Redirect the client instead of proposing a lease that would be rejected.
+	if repDesc.IsWitness() {
+		return r.mu.pendingLeaseRequest.newResolvedHandle(kvpb.NewError(
+			kvpb.NewNotLeaseHolderError(status.Lease, r.store.StoreID(), r.shMu.state.Desc,
+				"witness replicas cannot acquire the lease")))
+	}
 	return r.mu.pendingLeaseRequest.InitOrJoinRequest(
 		ctx, repDesc, status, r.shMu.state.Desc.StartKey.AsRawKey(),
 		false /* bypassSafetyChecks */, false /* targetHasSendQueue */, limiter)
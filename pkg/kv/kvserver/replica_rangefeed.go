@@ -245,6 +245,11 @@ func (r *Replica) RangeFeed(
 		return nil, errors.Errorf("multiple origin IDs and OriginID != 0 not supported yet")
 	}
 
+	rowFilter, err := rangefeed.NewRowFilter(args.RowFilter)
+	if err != nil {
+		return nil, err
+	}
+
 	// If the RangeFeed is performing a catch-up scan then it will observe all
 	// values above args.Timestamp. If the RangeFeed is requesting previous
 	// values for every update then it will also need to look for the version
@@ -320,6 +325,7 @@ func (r *Replica) RangeFeed(
 		if f := r.store.TestingKnobs().RangefeedValueHeaderFilter; f != nil {
 			catchUpSnap.OnEmit = f
 		}
+		catchUpSnap.LatestOnly = args.CatchUpMode == kvpb.RangeFeedRequest_LATEST
 	}
 
 	bulkDeliverySize := 0
@@ -327,7 +333,8 @@ func (r *Replica) RangeFeed(
 		bulkDeliverySize = int(rangeFeedBulkDeliverySize.Get(&r.store.ClusterSettings().SV))
 	}
 	p, disconnector, err := r.registerWithRangefeedRaftMuLocked(
-		streamCtx, rSpan, args.Timestamp, catchUpSnap, args.WithDiff, args.WithFiltering, omitRemote, bulkDeliverySize, stream,
+		streamCtx, rSpan, args.Timestamp, catchUpSnap, args.WithDiff, args.WithFiltering, omitRemote, rowFilter,
+		bulkDeliverySize, stream,
 	)
 	r.raftMu.Unlock()
 
@@ -435,6 +442,7 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 	withDiff bool,
 	withFiltering bool,
 	withOmitRemote bool,
+	rowFilter *rangefeed.RowFilter,
 	bulkDeliverySize int,
 	stream rangefeed.Stream,
 ) (rangefeed.Processor, rangefeed.Disconnector, error) {
@@ -456,8 +464,8 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 	p := r.rangefeedMu.proc
 
 	if p != nil {
-		reg, disconnector, filter := p.Register(streamCtx, span, startTS, catchUpSnap, withDiff, withFiltering, withOmitRemote, bulkDeliverySize,
-			stream)
+		reg, disconnector, filter := p.Register(streamCtx, span, startTS, catchUpSnap, withDiff, withFiltering, withOmitRemote, rowFilter,
+			bulkDeliverySize, stream)
 		if reg {
 			// Registered successfully with an existing processor.
 			// Update the rangefeed filter to avoid filtering ops
@@ -532,7 +540,7 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 	// this ensures that the only time the registration fails is during
 	// server shutdown.
 	reg, disconnector, filter := p.Register(streamCtx, span, startTS, catchUpSnap, withDiff,
-		withFiltering, withOmitRemote, bulkDeliverySize, stream)
+		withFiltering, withOmitRemote, rowFilter, bulkDeliverySize, stream)
 	if !reg {
 		select {
 		case <-r.store.Stopper().ShouldQuiesce():
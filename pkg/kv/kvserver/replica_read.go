@@ -14,6 +14,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval/result"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/gc"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvadmission"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
@@ -189,6 +190,16 @@ func (r *Replica) executeReadOnlyBatch(
 	}
 
 	if err := r.checkExecutionCanProceedAfterStorageSnapshot(ctx, ba, st); err != nil {
+		// Reads below the GC threshold may be served from the MVCC history
+		// archive, if there is one.
+		if gcErr := (*kvpb.BatchTimestampBeforeGCError)(nil); errors.As(err, &gcErr) &&
+			gc.ArchiveURI.Get(&r.store.ClusterSettings().SV) != "" && canEvaluateAgainstArchive(ba) {
+			br, archiveErr := r.evaluateReadOnlyBatchAgainstArchive(ctx, ba)
+			if archiveErr == nil {
+				return br, g, nil, nil
+			}
+			log.VEventf(ctx, 2, "unable to read below the GC threshold from the archive: %v", archiveErr)
+		}
 		return nil, g, nil, kvpb.NewError(err)
 	}
 	ok, stillNeedsInterleavedIntents, pErr := r.canDropLatchesBeforeEval(ctx, rw, ba, g, st)
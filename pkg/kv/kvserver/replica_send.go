@@ -174,6 +174,9 @@ func (r *Replica) SendWithWriteBytes(
 	if err := r.maybeCommitWaitBeforeCommitTrigger(ctx, ba); err != nil {
 		return nil, nil, kvpb.NewError(err)
 	}
+	if err := r.maybeWaitForClosedTimestamp(ctx, ba); err != nil {
+		return nil, nil, kvpb.NewError(err)
+	}
 
 	// NB: must be performed before collecting request spans.
 	ba, err := maybeStripInFlightWrites(ba)
@@ -0,0 +1,98 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package kvserver
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble"
+)
+
+// isWitness returns whether the replica with the given ID is a witness in the
+// provided range descriptor. Witness replicas vote in raft and persist the
+// log, but hold no user data: they only retain the range-local keyspace
+// (range descriptor, lease, applied state, etc.).
+func isWitness(desc *roachpb.RangeDescriptor, replicaID roachpb.ReplicaID) bool {
+	if desc == nil {
+		return false
+	}
+	repDesc, ok := desc.GetReplicaDescriptorByID(replicaID)
+	return ok && repDesc.IsWitness()
+}
+
+// witnessLogTruncationThreshold is the number of applied entries a witness lets
+// accumulate in its raft log before truncating it.
+const witnessLogTruncationThreshold = 64
+
+// maybeTruncateWitnessLogRaftMuLocked truncates the raft log of a witness
+// replica up to the given applied index, independently of the truncations
+// proposed by the leaseholder. Witnesses never lead the range, so they never
+// send entries to other replicas, and the writes to user keys in the entries
+// were dropped when they were applied: once applied, the entries are of no
+// use. The truncation goes through the loosely coupled truncator, which only
+// enacts it once the applied state is durable.
+func (r *Replica) maybeTruncateWitnessLogRaftMuLocked(
+	ctx context.Context, index kvpb.RaftIndex, term kvpb.RaftTerm,
+) {
+	rt := (*raftTruncatorReplica)(r)
+	if index < rt.getTruncatedState().Index+witnessLogTruncationThreshold {
+		return
+	}
+	// The size of the truncated entries is not known here. Leaving the expected
+	// first index unset marks the log size as untrusted once the truncation is
+	// enacted, which makes the raft log queue recompute it.
+	r.store.raftTruncator.addPendingTruncation(
+		ctx, rt, kvserverpb.RaftTruncatedState{Index: index, Term: term},
+		0 /* raftExpectedFirstIndex */, 0, /* raftLogDelta */
+	)
+}
+
+// applyWitnessBatchRepr stages the portion of a committed WriteBatch that a
+// witness replica retains into the given batch. Only writes to local keys are
+// kept; writes to user keys (and range keys, which only exist in the global
+// keyspace) are dropped.
+func applyWitnessBatchRepr(batch storage.Batch, repr []byte) error {
+	r, err := storage.NewBatchReader(repr)
+	if err != nil {
+		return err
+	}
+	for r.Next() {
+		ek, err := r.EngineKey()
+		if err != nil {
+			return err
+		}
+		if !keys.IsLocal(ek.Key) {
+			continue
+		}
+		switch r.KeyKind() {
+		case pebble.InternalKeyKindSet:
+			err = batch.PutEngineKey(ek, r.Value())
+		case pebble.InternalKeyKindDelete, pebble.InternalKeyKindDeleteSized:
+			err = batch.ClearEngineKey(ek, storage.ClearOptions{})
+		case pebble.InternalKeyKindSingleDelete:
+			err = batch.SingleClearEngineKey(ek)
+		case pebble.InternalKeyKindRangeDelete:
+			var end storage.EngineKey
+			if end, err = r.EngineEndKey(); err == nil {
+				err = batch.ClearRawRange(ek.Key, end.Key, true /* pointKeys */, false /* rangeKeys */)
+			}
+		case pebble.InternalKeyKindRangeKeySet, pebble.InternalKeyKindRangeKeyUnset,
+			pebble.InternalKeyKindRangeKeyDelete:
+		default:
+			err = errors.AssertionFailedf("unexpected batch entry kind %v", r.KeyKind())
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return r.Error()
+}
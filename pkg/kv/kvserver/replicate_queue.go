@@ -516,13 +516,17 @@ func (metrics *ReplicateQueueMetrics) trackSuccessByAllocatorAction(
 	ctx context.Context, action allocatorimpl.AllocatorAction,
 ) {
 	switch action {
-	case allocatorimpl.AllocatorRemoveVoter, allocatorimpl.AllocatorRemoveNonVoter:
+	case allocatorimpl.AllocatorRemoveVoter, allocatorimpl.AllocatorRemoveNonVoter,
+		allocatorimpl.AllocatorRemoveWitness:
 		metrics.RemoveReplicaSuccessCount.Inc(1)
-	case allocatorimpl.AllocatorAddVoter, allocatorimpl.AllocatorAddNonVoter:
+	case allocatorimpl.AllocatorAddVoter, allocatorimpl.AllocatorAddNonVoter,
+		allocatorimpl.AllocatorAddWitness:
 		metrics.AddReplicaSuccessCount.Inc(1)
-	case allocatorimpl.AllocatorReplaceDeadVoter, allocatorimpl.AllocatorReplaceDeadNonVoter:
+	case allocatorimpl.AllocatorReplaceDeadVoter, allocatorimpl.AllocatorReplaceDeadNonVoter,
+		allocatorimpl.AllocatorReplaceDeadWitness, allocatorimpl.AllocatorReplaceDeadVoterNearWitness:
 		metrics.ReplaceDeadReplicaSuccessCount.Inc(1)
-	case allocatorimpl.AllocatorRemoveDeadVoter, allocatorimpl.AllocatorRemoveDeadNonVoter:
+	case allocatorimpl.AllocatorRemoveDeadVoter, allocatorimpl.AllocatorRemoveDeadNonVoter,
+		allocatorimpl.AllocatorRemoveDeadWitness:
 		metrics.RemoveDeadReplicaSuccessCount.Inc(1)
 	case allocatorimpl.AllocatorReplaceDecommissioningVoter, allocatorimpl.AllocatorReplaceDecommissioningNonVoter:
 		metrics.ReplaceDecommissioningReplicaSuccessCount.Inc(1)
@@ -544,13 +548,17 @@ func (metrics *ReplicateQueueMetrics) trackErrorByAllocatorAction(
 	ctx context.Context, action allocatorimpl.AllocatorAction,
 ) {
 	switch action {
-	case allocatorimpl.AllocatorRemoveVoter, allocatorimpl.AllocatorRemoveNonVoter:
+	case allocatorimpl.AllocatorRemoveVoter, allocatorimpl.AllocatorRemoveNonVoter,
+		allocatorimpl.AllocatorRemoveWitness:
 		metrics.RemoveReplicaErrorCount.Inc(1)
-	case allocatorimpl.AllocatorAddVoter, allocatorimpl.AllocatorAddNonVoter:
+	case allocatorimpl.AllocatorAddVoter, allocatorimpl.AllocatorAddNonVoter,
+		allocatorimpl.AllocatorAddWitness:
 		metrics.AddReplicaErrorCount.Inc(1)
-	case allocatorimpl.AllocatorReplaceDeadVoter, allocatorimpl.AllocatorReplaceDeadNonVoter:
+	case allocatorimpl.AllocatorReplaceDeadVoter, allocatorimpl.AllocatorReplaceDeadNonVoter,
+		allocatorimpl.AllocatorReplaceDeadWitness, allocatorimpl.AllocatorReplaceDeadVoterNearWitness:
 		metrics.ReplaceDeadReplicaErrorCount.Inc(1)
-	case allocatorimpl.AllocatorRemoveDeadVoter, allocatorimpl.AllocatorRemoveDeadNonVoter:
+	case allocatorimpl.AllocatorRemoveDeadVoter, allocatorimpl.AllocatorRemoveDeadNonVoter,
+		allocatorimpl.AllocatorRemoveDeadWitness:
 		metrics.RemoveDeadReplicaErrorCount.Inc(1)
 	case allocatorimpl.AllocatorReplaceDecommissioningVoter, allocatorimpl.AllocatorReplaceDecommissioningNonVoter:
 		metrics.ReplaceDecommissioningReplicaErrorCount.Inc(1)
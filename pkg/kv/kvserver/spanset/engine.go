@@ -319,6 +319,11 @@ func (s *spanSetEngine) RegisterLowDiskSpaceCallback(cb func(info pebble.LowDisk
 	s.e.RegisterLowDiskSpaceCallback(cb)
 }
 
+// SetUserKeySpanPolicyFunc implements the storage.EngineWithoutRW interface.
+func (s *spanSetEngine) SetUserKeySpanPolicyFunc(f storage.UserKeySpanPolicyFunc) {
+	s.e.SetUserKeySpanPolicyFunc(f)
+}
+
 // GetPebbleOptions implements the storage.EngineWithoutRW interface.
 func (s *spanSetEngine) GetPebbleOptions() *pebble.Options {
 	return s.e.GetPebbleOptions()
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
@@ -1303,6 +1304,12 @@ type StoreConfig struct {
 	// SharedStorageEnabled stores whether this store is configured with a
 	// shared.Storage instance and can accept shared snapshots.
 	SharedStorageEnabled bool
+	// ExternalStorageFromURI is used to open the external storage into which
+	// the MVCC GC queue archives history, if configured.
+	ExternalStorageFromURI cloud.ExternalStorageFromURIFactory
+	// ConsistencyRepairDir is the directory in which the consistency checker
+	// writes the evidence it gathers before repairing an inconsistent replica.
+	ConsistencyRepairDir string
 
 	// KVAdmissionController is used for admission control.
 	KVAdmissionController kvadmission.Controller
@@ -2437,6 +2444,10 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 		spanconfigstore.FallbackConfigOverride.SetOnChange(&s.ClusterSettings().SV, func(ctx context.Context) {
 			s.applyAllFromSpanConfigStore(ctx)
 		})
+
+		// Let the storage engine apply the storage policies configured through
+		// zone configs when writing sstables.
+		s.StateEngine().SetUserKeySpanPolicyFunc(s.userKeySpanPolicy)
 	}
 
 	// Start Raft processing goroutines.
@@ -2771,6 +2782,32 @@ func (s *Store) applyAllFromSpanConfigStore(ctx context.Context) {
 	})
 }
 
+// userKeySpanPolicy implements storage.UserKeySpanPolicyFunc using the span
+// configs of the store. Since it is called by the storage engine when writing
+// sstables, errors are logged and result in the default policy.
+func (s *Store) userKeySpanPolicy(key roachpb.Key) (roachpb.Span, storage.UserKeySpanPolicy) {
+	span := roachpb.Span{Key: key, EndKey: key.Next()}
+	var policy storage.UserKeySpanPolicy
+	ctx := s.AnnotateCtx(context.Background())
+	if err := s.cfg.SpanConfigSubscriber.ForEachOverlappingSpanConfig(ctx, span,
+		func(sp roachpb.Span, conf roachpb.SpanConfig) error {
+			span = sp
+			policy = storage.MakeUserKeySpanPolicy(&conf)
+			return nil
+		}); err != nil {
+		log.KvExec.Warningf(ctx, "unable to look up storage policy for %s: %v", key, err)
+		return roachpb.Span{Key: key, EndKey: key.Next()}, storage.UserKeySpanPolicy{}
+	}
+	return span, policy
+}
+
+// StoragePolicy returns the storage policy that the store's engine applies to
+// the specified global key, as configured through zone configs.
+func (s *Store) StoragePolicy(key roachpb.Key) storage.UserKeySpanPolicy {
+	_, policy := s.userKeySpanPolicy(key)
+	return policy
+}
+
 // GossipStore broadcasts the store on the gossip network.
 func (s *Store) GossipStore(ctx context.Context, useCached bool) error {
 	return s.storeGossip.GossipStore(ctx, useCached)
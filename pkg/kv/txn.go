@@ -104,6 +104,10 @@ type Txn struct {
 	// represents (statement fingerprint, job, system task).
 	workloadType workloadid.WorkloadType
 
+	// waitForClosedTimestamp, if set, is attached to all requests sent through
+	// this transaction. See SetWaitForClosedTimestamp.
+	waitForClosedTimestamp bool
+
 	// The following fields are not safe for concurrent modification.
 	// They should be set before operating on the transaction.
 
@@ -474,6 +478,21 @@ func (txn *Txn) SetWorkloadInfo(
 	txn.workloadType = workloadType
 }
 
+// SetWaitForClosedTimestamp marks the transaction's reads as eligible to be
+// served by followers which wait for their closed timestamp to catch up to the
+// transaction's timestamp, instead of being redirected to the leaseholder. It
+// is used by historical transactions whose timestamp was forwarded to a
+// causality token, which is likely to be above the closed timestamp.
+func (txn *Txn) SetWaitForClosedTimestamp() {
+	txn.waitForClosedTimestamp = true
+}
+
+// WaitForClosedTimestamp returns whether SetWaitForClosedTimestamp was called
+// on the transaction.
+func (txn *Txn) WaitForClosedTimestamp() bool {
+	return txn.waitForClosedTimestamp
+}
+
 // SetBufferedWritesEnabled toggles whether the writes are buffered on the
 // gateway node until the commit time. Buffered writes cannot be enabled on a
 // txn that performed any requests. When disabling buffered writes, if there are
@@ -1398,6 +1417,10 @@ func (txn *Txn) Send(
 		ba.Header.WorkloadType = txn.workloadType.ToUint32()
 	}
 
+	if txn.waitForClosedTimestamp {
+		ba.Header.WaitForClosedTimestamp = true
+	}
+
 	// Requests with a bounded staleness header should use NegotiateAndSend.
 	if ba.BoundedStaleness != nil {
 		return nil, kvpb.NewError(errors.AssertionFailedf(
@@ -323,6 +323,10 @@ type raft struct {
 	electionTracker      tracker.ElectionTracker
 	fortificationTracker *tracker.FortificationTracker
 	lazyReplication      bool
+	// campaignDisabled is true if the local raft node must never campaign or
+	// become the leader, even though it is a voter. It is set for replicas
+	// that vote but do not hold the state machine, such as witnesses.
+	campaignDisabled bool
 
 	state pb.StateType
 
@@ -2688,8 +2692,12 @@ func (r *raft) restore(s snapshot) bool {
 }
 
 // promotable indicates whether state machine can be promoted to leader,
-// which is true when its own id is in progress list.
+// which is true when its own id is in progress list and campaigning has not
+// been disabled.
 func (r *raft) promotable() bool {
+	if r.campaignDisabled {
+		return false
+	}
 	pr := r.trk.Progress(r.id)
 	return pr != nil && !pr.IsLearner && !r.raftLog.hasNextOrInProgressSnapshot()
 }
@@ -4530,6 +4530,41 @@ func TestLearnerCampaign(t *testing.T) {
 	require.Equal(t, pb.StateFollower, n2.state)
 }
 
+// TestCampaignDisabled verifies that a voter with campaigning disabled neither
+// campaigns nor accepts leadership transfers, but still votes for others. If
+// it is the leader when campaigning is disabled, it steps down.
+func TestCampaignDisabled(t *testing.T) {
+	n1 := newTestRaft(1, 10, 1, newTestMemoryStorage(withPeers(1, 2, 3)))
+	n2 := newTestRaft(2, 10, 1, newTestMemoryStorage(withPeers(1, 2, 3)))
+	n3 := newTestRaft(3, 10, 1, newTestMemoryStorage(withPeers(1, 2, 3)))
+	nt := newNetwork(n1, n2, n3)
+
+	rn2 := &RawNode{raft: n2}
+	rn2.SetCampaignDisabled(true)
+
+	nt.send(pb.Message{From: 2, To: 2, Type: pb.MsgHup})
+	require.Equal(t, pb.StateFollower, n2.state)
+
+	// n2 still votes, so n1 can win the election.
+	nt.send(pb.Message{From: 1, To: 1, Type: pb.MsgHup})
+	require.Equal(t, pb.StateLeader, n1.state)
+	require.Equal(t, pb.PeerID(1), n2.lead)
+
+	// A MsgTimeoutNow is ignored, as it is for learners.
+	nt.send(pb.Message{From: 1, To: 2, Type: pb.MsgTimeoutNow})
+	require.Equal(t, pb.StateFollower, n2.state)
+
+	// Once campaigning is re-enabled, n2 can be elected.
+	rn2.SetCampaignDisabled(false)
+	nt.send(pb.Message{From: 1, To: 2, Type: pb.MsgTimeoutNow})
+	require.Equal(t, pb.StateLeader, n2.state)
+
+	// Disabling campaigning on the leader makes it step down.
+	rn2.SetCampaignDisabled(true)
+	require.Equal(t, pb.StateFollower, n2.state)
+	require.Equal(t, None, n2.lead)
+}
+
 // simulate rolling update a cluster for Pre-Vote. cluster has 3 nodes [n1, n2, n3].
 // n1 is leader with term 2
 // n2 is follower with term 2
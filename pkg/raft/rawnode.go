@@ -152,6 +152,32 @@ func (rn *RawNode) Step(m pb.Message) error {
 	return rn.raft.Step(m)
 }
 
+// SetCampaignDisabled sets whether the local raft node is prevented from
+// campaigning and becoming the leader. A node with campaigning disabled still
+// votes and acknowledges log entries, which lets a replica participate in
+// quorum without being able to serve as the leader.
+//
+// If campaigning is disabled while the node is the leader, it steps down in the
+// same way as a leader that removed itself from the configuration, and the
+// remaining voters elect a new leader.
+func (rn *RawNode) SetCampaignDisabled(disabled bool) {
+	r := rn.raft
+	if r.campaignDisabled == disabled {
+		return
+	}
+	r.campaignDisabled = disabled
+	if !disabled {
+		return
+	}
+	switch r.state {
+	case pb.StateLeader:
+		r.deFortify(r.id, r.Term)
+		r.becomeFollower(r.Term, None)
+	case pb.StateCandidate, pb.StatePreCandidate:
+		r.becomeFollower(r.Term, None)
+	}
+}
+
 // SetLazyReplication enables or disables the lazy MsgApp replication for
 // StateReplicate flows. See Config.LazyReplication which defines the initial
 // value of this setting - the semantics are explained in its comment.
@@ -1769,6 +1769,11 @@ func confChangeImpl(
 			// We're adding a voter, but will transition into a joint config
 			// first.
 			changeType = raftpb.ConfChangeAddNode
+		case WITNESS:
+			// We're adding a witness, which votes like any other voter. Witnesses
+			// are added directly rather than as learners first, since they have
+			// no data to catch up on.
+			changeType = raftpb.ConfChangeAddNode
 		case LEARNER, NON_VOTER:
 			// We're adding a learner or non-voter.
 			// Note that we're guaranteed by virtue of the upstream ChangeReplicas txn
@@ -441,7 +441,7 @@ func (r ReplicaSet) GetReplicaDescriptorByID(id ReplicaID) (repDesc ReplicaDescr
 // ReplicaDescriptors.Filter(ReplicaDescriptor.IsVoterOldConfig).
 func (r ReplicaDescriptor) IsVoterOldConfig() bool {
 	switch r.Type {
-	case VOTER_FULL, VOTER_OUTGOING, VOTER_DEMOTING_NON_VOTER, VOTER_DEMOTING_LEARNER:
+	case VOTER_FULL, VOTER_OUTGOING, VOTER_DEMOTING_NON_VOTER, VOTER_DEMOTING_LEARNER, WITNESS:
 		return true
 	default:
 		return false
@@ -454,7 +454,7 @@ func (r ReplicaDescriptor) IsVoterOldConfig() bool {
 // ReplicaDescriptors.Filter(ReplicaDescriptor.IsVoterOldConfig).
 func (r ReplicaDescriptor) IsVoterNewConfig() bool {
 	switch r.Type {
-	case VOTER_FULL, VOTER_INCOMING:
+	case VOTER_FULL, VOTER_INCOMING, WITNESS:
 		return true
 	default:
 		return false
@@ -466,7 +466,7 @@ func (r ReplicaDescriptor) IsVoterNewConfig() bool {
 // for ReplicaDescriptors.Filter(ReplicaDescriptor.IsVoterOldConfig).
 func (r ReplicaDescriptor) IsAnyVoter() bool {
 	switch r.Type {
-	case VOTER_FULL, VOTER_INCOMING, VOTER_OUTGOING, VOTER_DEMOTING_NON_VOTER, VOTER_DEMOTING_LEARNER:
+	case VOTER_FULL, VOTER_INCOMING, VOTER_OUTGOING, VOTER_DEMOTING_NON_VOTER, VOTER_DEMOTING_LEARNER, WITNESS:
 		return true
 	default:
 		return false
@@ -484,6 +484,12 @@ func (r ReplicaDescriptor) IsNonVoter() bool {
 	}
 }
 
+// IsWitness returns true if the replica is a witness. Can be used as a filter
+// for ReplicaDescriptors.Filter.
+func (r ReplicaDescriptor) IsWitness() bool {
+	return r.Type == WITNESS
+}
+
 // PercentilesFromData derives percentiles from a slice of data points.
 // Sorts the input data if it isn't already sorted.
 func PercentilesFromData(data []float64) Percentiles {
@@ -908,7 +914,8 @@ func (l Locality) AddTier(tier Tier) Locality {
 
 // IsEmpty returns true if hint contains no data.
 func (h *GCHint) IsEmpty() bool {
-	return h.LatestRangeDeleteTimestamp.IsEmpty() && h.GCTimestamp.IsEmpty()
+	return h.LatestRangeDeleteTimestamp.IsEmpty() && h.GCTimestamp.IsEmpty() &&
+		h.ArchivedThreshold.IsEmpty()
 }
 
 // Merge combines GC hints of two adjacent ranges. Updates the receiver to be a
@@ -927,6 +934,12 @@ func (h *GCHint) Merge(rhs *GCHint, leftEmpty, rightEmpty bool) bool {
 	updated := h.ScheduleGCFor(rhs.GCTimestamp)
 	// NB: don't swap the operands, we need the side effect of the method call.
 	updated = h.ScheduleGCFor(rhs.GCTimestampNext) || updated
+	// The merged range has been archived only as far as the side which was
+	// archived less recently. An empty ArchivedThreshold sorts first.
+	if rhs.ArchivedThreshold.Less(h.ArchivedThreshold) {
+		h.ArchivedThreshold = rhs.ArchivedThreshold
+		updated = true
+	}
 
 	// If LHS or RHS has data but no LatestRangeDeleteTimestamp hint, then this
 	// side is not known to be covered by range tombstones. Correspondingly, the
@@ -983,6 +996,17 @@ func (h *GCHint) ScheduleGCFor(ts hlc.Timestamp) bool {
 	return true
 }
 
+// SetArchivedThreshold records the timestamp up to which the MVCC history of
+// the range has been archived. An empty timestamp records that history was
+// collected without being archived. Returns true iff the hint was updated.
+func (h *GCHint) SetArchivedThreshold(ts hlc.Timestamp) bool {
+	if h.ArchivedThreshold.Equal(ts) {
+		return false
+	}
+	h.ArchivedThreshold = ts
+	return true
+}
+
 // UpdateAfterGC updates the GCHint according to the threshold, up to which the
 // data has been garbage collected. Returns true iff the hint has been updated.
 func (h *GCHint) UpdateAfterGC(gcThreshold hlc.Timestamp) bool {
@@ -92,6 +92,14 @@ func predVoterFullOrNonVoter(rDesc ReplicaDescriptor) bool {
 	return predVoterFull(rDesc) || predNonVoter(rDesc)
 }
 
+func predVoterFullNonVoterOrWitness(rDesc ReplicaDescriptor) bool {
+	return predVoterFullOrNonVoter(rDesc) || predWitness(rDesc)
+}
+
+func predWitness(rDesc ReplicaDescriptor) bool {
+	return rDesc.Type == WITNESS
+}
+
 // Voters returns a ReplicaSet of current and future voter replicas in `d`. This
 // means that during an atomic replication change, only the replicas that will
 // be voters once the change completes will be returned; "outgoing" voters will
@@ -221,6 +229,13 @@ func (d ReplicaSet) NonVoterDescriptors() []ReplicaDescriptor {
 	return d.FilterToDescriptors(predNonVoter)
 }
 
+// WitnessDescriptors returns the witness replica descriptors in the set.
+// Witnesses count towards the quorum of the range, but hold no user data and
+// are therefore not included in Voters().
+func (d ReplicaSet) WitnessDescriptors() []ReplicaDescriptor {
+	return d.FilterToDescriptors(predWitness)
+}
+
 // VoterFullAndNonVoterDescriptors returns the descriptors of
 // VOTER_FULL/NON_VOTER replicas in the set. This set will not contain learners
 // or, during an atomic replication change, incoming or outgoing voters.
@@ -230,6 +245,15 @@ func (d ReplicaSet) VoterFullAndNonVoterDescriptors() []ReplicaDescriptor {
 	return d.FilterToDescriptors(predVoterFullOrNonVoter)
 }
 
+// VoterFullNonVoterAndWitnessDescriptors returns the descriptors of
+// VOTER_FULL/NON_VOTER/WITNESS replicas in the set. It is the same as
+// VoterFullAndNonVoterDescriptors, except that it also includes witnesses,
+// which is what range merges need: witnesses are allowed to be part of a
+// merge, but must never be picked as a source of user data.
+func (d ReplicaSet) VoterFullNonVoterAndWitnessDescriptors() []ReplicaDescriptor {
+	return d.FilterToDescriptors(predVoterFullNonVoterOrWitness)
+}
+
 // VoterAndNonVoterDescriptors returns the descriptors of VOTER_FULL,
 // VOTER_INCOMING and NON_VOTER replicas in the set. Notably, this is the set of
 // replicas the DistSender will consider routing follower read requests to.
@@ -309,7 +333,7 @@ func (d ReplicaSet) InAtomicReplicationChange() bool {
 		case VOTER_INCOMING, VOTER_OUTGOING, VOTER_DEMOTING_LEARNER,
 			VOTER_DEMOTING_NON_VOTER:
 			return true
-		case VOTER_FULL, LEARNER, NON_VOTER:
+		case VOTER_FULL, LEARNER, NON_VOTER, WITNESS:
 		default:
 			panic(fmt.Sprintf("unknown replica type %d", rDesc.Type))
 		}
@@ -327,7 +351,7 @@ func (d ReplicaSet) ConfState() raftpb.ConfState {
 	for _, rep := range d.wrapped {
 		id := raftpb.PeerID(rep.ReplicaID)
 		switch rep.Type {
-		case VOTER_FULL:
+		case VOTER_FULL, WITNESS:
 			cs.Voters = append(cs.Voters, id)
 			if joint {
 				cs.VotersOutgoing = append(cs.VotersOutgoing, id)
@@ -435,11 +459,13 @@ func (d ReplicaSet) ReplicationStatus(
 	res.Available = availableIncomingGroup && availableOutgoingGroup
 
 	// Determine over/under-replication of voting replicas. Note that learners
-	// don't matter.
-	underReplicatedOldGroup := len(liveVotersOldGroup) < neededVoters
-	underReplicatedNewGroup := len(liveVotersNewGroup) < neededVoters
-	overReplicatedOldGroup := len(votersOldGroup) > neededVoters
-	overReplicatedNewGroup := len(votersNewGroup) > neededVoters
+	// don't matter, and neither do witnesses, which hold no data.
+	numWitnesses := len(d.FilterToDescriptors(ReplicaDescriptor.IsWitness))
+	numLiveWitnesses := len(d.FilterToDescriptors(isBoth(ReplicaDescriptor.IsWitness, liveFunc)))
+	underReplicatedOldGroup := len(liveVotersOldGroup)-numLiveWitnesses < neededVoters
+	underReplicatedNewGroup := len(liveVotersNewGroup)-numLiveWitnesses < neededVoters
+	overReplicatedOldGroup := len(votersOldGroup)-numWitnesses > neededVoters
+	overReplicatedNewGroup := len(votersNewGroup)-numWitnesses > neededVoters
 	res.UnderReplicated = underReplicatedOldGroup || underReplicatedNewGroup
 	res.OverReplicated = overReplicatedOldGroup || overReplicatedNewGroup
 	if neededNonVoters == -1 {
@@ -493,9 +519,9 @@ func (d ReplicaSet) Subtract(o ReplicaSet) []ReplicaDescriptor {
 // IsAddition returns true if `c` refers to a replica addition operation.
 func (c ReplicaChangeType) IsAddition() bool {
 	switch c {
-	case ADD_NON_VOTER, ADD_VOTER:
+	case ADD_NON_VOTER, ADD_VOTER, ADD_WITNESS:
 		return true
-	case REMOVE_NON_VOTER, REMOVE_VOTER:
+	case REMOVE_NON_VOTER, REMOVE_VOTER, REMOVE_WITNESS:
 		return false
 	default:
 		panic(fmt.Sprintf("unexpected ReplicaChangeType %s", c))
@@ -505,9 +531,9 @@ func (c ReplicaChangeType) IsAddition() bool {
 // IsRemoval returns true if `c` refers a replica removal operation.
 func (c ReplicaChangeType) IsRemoval() bool {
 	switch c {
-	case ADD_NON_VOTER, ADD_VOTER:
+	case ADD_NON_VOTER, ADD_VOTER, ADD_WITNESS:
 		return false
-	case REMOVE_NON_VOTER, REMOVE_VOTER:
+	case REMOVE_NON_VOTER, REMOVE_VOTER, REMOVE_WITNESS:
 		return true
 	default:
 		panic(fmt.Sprintf("unexpected ReplicaChangeType %s", c))
@@ -525,7 +551,8 @@ var ErrReplicaNotFound = errors.New(`lease target replica not found in RangeDesc
 var ErrReplicaCannotHoldLease = errors.New(`lease target replica cannot hold lease`)
 
 // CheckCanReceiveLease checks whether `wouldbeLeaseholder` can receive a lease.
-// Returns an error if the respective replica is not eligible.
+// Returns an error if the respective replica is not eligible. Witnesses are
+// never eligible, since they hold no data to serve.
 //
 // Previously, we were not allowed to enter a joint config where the
 // leaseholder is being removed (i.e., not a full voter). In the new version
@@ -563,6 +590,9 @@ func CheckCanReceiveLease(
 		return errors.AssertionFailedf("node ID mismatch: %d != %d",
 			repDesc.NodeID, wouldbeLeaseholder.NodeID)
 	}
+	if repDesc.IsWitness() {
+		return ErrReplicaCannotHoldLease
+	}
 	if !(repDesc.IsVoterNewConfig() ||
 		(repDesc.IsVoterOldConfig() && replDescs.containsVoterIncoming() && wasLastLeaseholder)) {
 		// We allow a demoting / incoming voter to receive the lease if there's an incoming voter.
@@ -77,6 +77,21 @@ func TestVotersLearnersAll(t *testing.T) {
 	}
 }
 
+// TestWitnessDescriptors verifies that witnesses, which hold no user data, are
+// only returned by the accessors that explicitly include them.
+func TestWitnessDescriptors(t *testing.T) {
+	r := MakeReplicaSet([]ReplicaDescriptor{
+		rd(VOTER_FULL, 1), rd(NON_VOTER, 2), rd(WITNESS, 3),
+	})
+	require.Equal(t, []ReplicaDescriptor{rd(WITNESS, 3)}, r.WitnessDescriptors())
+	require.Equal(t, []ReplicaDescriptor{rd(VOTER_FULL, 1)}, r.VoterDescriptors())
+	require.Equal(t, []ReplicaDescriptor{rd(VOTER_FULL, 1), rd(NON_VOTER, 2)},
+		r.VoterAndNonVoterDescriptors())
+	require.Equal(t, []ReplicaDescriptor{rd(VOTER_FULL, 1), rd(NON_VOTER, 2)},
+		r.VoterFullAndNonVoterDescriptors())
+	require.Equal(t, r.Descriptors(), r.VoterFullNonVoterAndWitnessDescriptors())
+}
+
 func TestReplicaDescriptorsRemove(t *testing.T) {
 	tests := []struct {
 		replicas []ReplicaDescriptor
@@ -177,6 +192,16 @@ func TestReplicaDescriptorsConfState(t *testing.T) {
 			[]ReplicaDescriptor{rd(VOTER_OUTGOING, 1), rd(VOTER_DEMOTING_LEARNER, 2), rd(VOTER_INCOMING, 3), rd(VOTER_INCOMING, 4), rd(LEARNER, 5)},
 			"Voters:[3 4] VotersOutgoing:[1 2] Learners:[5] LearnersNext:[2] AutoLeave:false",
 		},
+		// Witnesses vote in raft, so they're part of the voter set.
+		{
+			[]ReplicaDescriptor{rd(VOTER_FULL, 1), rd(VOTER_FULL, 2), rd(WITNESS, 3)},
+			"Voters:[1 2 3] VotersOutgoing:[] Learners:[] LearnersNext:[] AutoLeave:false",
+		},
+		// Adding a voter while a witness is present: the witness is in both configs.
+		{
+			[]ReplicaDescriptor{rd(VOTER_FULL, 1), rd(WITNESS, 2), rd(VOTER_INCOMING, 3)},
+			"Voters:[1 2 3] VotersOutgoing:[1 2] Learners:[] LearnersNext:[] AutoLeave:false",
+		},
 	}
 
 	for _, test := range tests {
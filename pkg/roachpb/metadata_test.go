@@ -626,6 +626,10 @@ func TestGCHint(t *testing.T) {
 			GCTimestampNext:            maxT,
 		}
 	}
+	archived := func(h GCHint, ts hlc.Timestamp) GCHint {
+		h.ArchivedThreshold = ts
+		return h
+	}
 
 	// checkInvariants verifies that the GCHint is well-formed.
 	checkInvariants := func(t *testing.T, hint GCHint) {
@@ -671,6 +675,7 @@ func TestGCHint(t *testing.T) {
 		hint(ts1, empty, empty),
 		hint(ts1, ts1, ts3),
 		hint(ts2, ts1, ts3),
+		archived(hint(empty, ts1, ts3), ts2),
 	} {
 		t.Run("Merge-with-empty-hint", func(t *testing.T) {
 			for _, lr := range [][]bool{
@@ -723,6 +728,13 @@ func TestGCHint(t *testing.T) {
 			want: hint(ts2, ts1, ts3)},
 		{lhs: hint(ts2, empty, empty), rhs: hint(empty, ts1, ts3), lEmpty: true, rEmpty: true,
 			want: hint(ts2, ts1, ts3)},
+
+		// The merged range is archived as far as the side archived less recently.
+		{lhs: archived(GCHint{}, ts2), rhs: archived(GCHint{}, ts1), want: archived(GCHint{}, ts1)},
+		{lhs: archived(GCHint{}, ts2), rhs: archived(GCHint{}, ts2), want: archived(GCHint{}, ts2)},
+		{lhs: archived(GCHint{}, ts2), rhs: GCHint{}, want: GCHint{}},
+		{lhs: archived(hint(empty, ts1, empty), ts3), rhs: archived(hint(empty, ts2, empty), ts2),
+			want: archived(hint(empty, ts1, ts2), ts2)},
 	} {
 		t.Run("Merge", func(t *testing.T) {
 			checkInvariants(t, tc.lhs)
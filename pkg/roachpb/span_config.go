@@ -58,6 +58,43 @@ func (s *SpanConfig) TTL() time.Duration {
 	return time.Duration(s.GCPolicy.TTLSeconds) * time.Second
 }
 
+// Values of SpanConfig.StorageCompression, as configured through the
+// storage.compression zone config field.
+const (
+	// StorageCompressionDefault uses the store-wide compression settings. It is
+	// represented by the empty string in span configs.
+	StorageCompressionDefault = "default"
+	// StorageCompressionFast prefers a fast compression algorithm over a good
+	// compression ratio.
+	StorageCompressionFast = "fast"
+	// StorageCompressionFastest, StorageCompressionBalanced and
+	// StorageCompressionGood select a compression level, like the values of the
+	// storage.sstable.compression_algorithm cluster setting of the same names.
+	StorageCompressionFastest  = "fastest"
+	StorageCompressionBalanced = "balanced"
+	StorageCompressionGood     = "good"
+	// StorageCompressionSnappy, StorageCompressionMinLZ, StorageCompressionZstd
+	// and StorageCompressionNone select a specific compression algorithm.
+	StorageCompressionSnappy = "snappy"
+	StorageCompressionMinLZ  = "minlz"
+	StorageCompressionZstd   = "zstd"
+	StorageCompressionNone   = "none"
+)
+
+// StorageCompressions are the valid values of the storage.compression zone
+// config field.
+var StorageCompressions = []string{
+	StorageCompressionDefault,
+	StorageCompressionFast,
+	StorageCompressionFastest,
+	StorageCompressionBalanced,
+	StorageCompressionGood,
+	StorageCompressionSnappy,
+	StorageCompressionMinLZ,
+	StorageCompressionZstd,
+	StorageCompressionNone,
+}
+
 // ValidateSystemTargetSpanConfig ensures that only protection policies
 // (GCPolicy.ProtectionPolicies) field is set on the underlying
 // roachpb.SpanConfig.
@@ -83,6 +120,15 @@ func (s *SpanConfig) ValidateSystemTargetSpanConfig() error {
 	if s.NumVoters != 0 {
 		return errors.AssertionFailedf("NumVoters set on system span config")
 	}
+	if s.NumWitnesses != 0 {
+		return errors.AssertionFailedf("NumWitnesses set on system span config")
+	}
+	if s.StorageCompression != "" {
+		return errors.AssertionFailedf("StorageCompression set on system span config")
+	}
+	if s.StorageValueSeparationMinSize != 0 {
+		return errors.AssertionFailedf("StorageValueSeparationMinSize set on system span config")
+	}
 	if len(s.Constraints) != 0 {
 		return errors.AssertionFailedf("Constraints set on system span config")
 	}
@@ -110,10 +156,16 @@ func (s *SpanConfig) GetNumVoters() int32 {
 	return s.NumReplicas
 }
 
+// GetNumWitnesses returns the number of witness replicas as defined in the
+// span config.
+func (s *SpanConfig) GetNumWitnesses() int32 {
+	return s.NumWitnesses
+}
+
 // GetNumNonVoters returns the number of non-voting replicas as defined in the
 // span config.
 func (s *SpanConfig) GetNumNonVoters() int32 {
-	return s.NumReplicas - s.GetNumVoters()
+	return s.NumReplicas - s.GetNumVoters() - s.GetNumWitnesses()
 }
 
 func (c Constraint) String() string {
@@ -187,6 +187,10 @@ type BaseConfig struct {
 	// ExecutionTraceDirName is the directory name for Go execution traces.
 	ExecutionTraceDirName string
 
+	// ConsistencyRepairDirName is the directory name for the evidence bundles
+	// written when the consistency checker repairs a replica.
+	ConsistencyRepairDirName string
+
 	// InflightTraceDirName is the directory name for job traces.
 	InflightTraceDirName string
 
@@ -1000,6 +1000,8 @@ func NewServer(cfg Config, stopper *stop.Stopper) (serverctl.ServerStartupInterf
 		RangefeedBudgetFactory:       rangeFeedBudgetFactory,
 		RaftEntriesMonitor:           raftEntriesMonitor,
 		SharedStorageEnabled:         cfg.StorageConfig.SharedStorage.URI != "",
+		ExternalStorageFromURI:       externalStorageFromURI,
+		ConsistencyRepairDir:         cfg.ConsistencyRepairDirName,
 		SystemConfigProvider:         systemConfigWatcher,
 		SpanConfigSubscriber:         spanConfig.subscriber,
 		RangeLogWriter:               rangeLogWriter,
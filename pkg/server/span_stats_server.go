@@ -206,6 +206,10 @@ func collectSpanStatsResponses(
 			res.SpanToStats[spanStr].RemoteFileBytes += spanStats.RemoteFileBytes
 			res.SpanToStats[spanStr].ExternalFileBytes += spanStats.ExternalFileBytes
 			res.SpanToStats[spanStr].StoreIDs = util.CombineUnique(res.SpanToStats[spanStr].StoreIDs, spanStats.StoreIDs)
+			if res.SpanToStats[spanStr].StorageCompression == "" {
+				res.SpanToStats[spanStr].StorageCompression = spanStats.StorageCompression
+				res.SpanToStats[spanStr].StorageValueSeparationMinSize = spanStats.StorageValueSeparationMinSize
+			}
 
 			// Logical values: take the values from the first node that responded with
 			// MVCC stats (i.e., non-empty TotalStats) since some nodes may skip MVCC
@@ -263,7 +267,8 @@ func (s *systemStatusServer) statsForSpan(
 		return nil, err
 	}
 
-	// First, get the approximate disk bytes from each store.
+	// First, get the approximate disk bytes from each store, and the storage
+	// policy of the span, which is the same on all stores.
 	err = s.stores.VisitStores(func(store *kvserver.Store) error {
 		approxDiskBytes, remoteBytes, externalBytes, err := store.TODOBothEngines().
 			ApproximateDiskBytes(rSpan.Key.AsRawKey(), rSpan.EndKey.AsRawKey())
@@ -273,6 +278,11 @@ func (s *systemStatusServer) statsForSpan(
 		spanStats.ApproximateDiskBytes += approxDiskBytes
 		spanStats.RemoteFileBytes += remoteBytes
 		spanStats.ExternalFileBytes += externalBytes
+		if spanStats.StorageCompression == "" {
+			policy := store.StoragePolicy(rSpan.Key.AsRawKey())
+			spanStats.StorageCompression = policy.EffectiveCompression(&s.st.SV).String()
+			spanStats.StorageValueSeparationMinSize = policy.EffectiveValueSeparationMinSize(&s.st.SV)
+		}
 		return nil
 	})
 
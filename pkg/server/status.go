@@ -1382,7 +1382,8 @@ func (s *statusServer) GetFiles(
 	cfg := s.sqlServer.cfg
 	return getLocalFiles(
 		req, cfg.HeapProfileDirName, cfg.GoroutineDumpDirName,
-		cfg.CPUProfileDirName, cfg.ExecutionTraceDirName, os.Stat, os.ReadFile,
+		cfg.CPUProfileDirName, cfg.ExecutionTraceDirName, cfg.ConsistencyRepairDirName,
+		os.Stat, os.ReadFile,
 	)
 }
 
@@ -205,6 +205,7 @@ func getLocalFiles(
 	goroutineDumpDirName string,
 	cpuProfileDirName string,
 	executionTraceDirName string,
+	consistencyRepairDirName string,
 	statFileFn func(string) (os.FileInfo, error),
 	readFileFn func(string) ([]byte, error),
 ) (*serverpb.GetFilesResponse, error) {
@@ -220,6 +221,8 @@ func getLocalFiles(
 		dir = cpuProfileDirName
 	case serverpb.FileType_EXECUTIONTRACE:
 		dir = executionTraceDirName
+	case serverpb.FileType_CONSISTENCY_REPAIR:
+		dir = consistencyRepairDirName
 	default:
 		return nil, status.Errorf(codes.InvalidArgument, "unknown file type: %s", req.Type)
 	}
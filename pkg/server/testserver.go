@@ -313,6 +313,9 @@ func makeTestConfigFromParams(params base.TestServerArgs) Config {
 			if cfg.ExecutionTraceDirName == "" {
 				cfg.ExecutionTraceDirName = filepath.Join(storeSpec.Path, "logs", base.ExecutionTraceDir)
 			}
+			if cfg.ConsistencyRepairDirName == "" {
+				cfg.ConsistencyRepairDirName = filepath.Join(storeSpec.Path, "logs", base.ConsistencyRepairDir)
+			}
 		}
 	}
 	cfg.Stores = base.StoreSpecList{Specs: params.StoreSpecs}
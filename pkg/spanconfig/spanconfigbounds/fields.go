@@ -43,21 +43,27 @@ var fields = [config.NumFields]Field{
 	rangeMaxBytes,
 	globalReads,
 	numVoters,
+	numWitnesses,
 	numReplicas,
 	gcTTLSeconds,
 	constraints,
 	voterConstraints,
 	leasePreferences,
+	storageCompression,
+	storageValueSeparationMinSize,
 }
 
 const (
-	rangeMaxBytes    = int64Field(config.RangeMaxBytes)
-	rangeMinBytes    = int64Field(config.RangeMinBytes)
-	globalReads      = boolField(config.GlobalReads)
-	numReplicas      = int32Field(config.NumReplicas)
-	numVoters        = int32Field(config.NumVoters)
-	gcTTLSeconds     = int32Field(config.GCTTL)
-	constraints      = constraintsConjunctionField(config.Constraints)
-	voterConstraints = constraintsConjunctionField(config.VoterConstraints)
-	leasePreferences = leasePreferencesField(config.LeasePreferences)
+	rangeMaxBytes                 = int64Field(config.RangeMaxBytes)
+	rangeMinBytes                 = int64Field(config.RangeMinBytes)
+	globalReads                   = boolField(config.GlobalReads)
+	numReplicas                   = int32Field(config.NumReplicas)
+	numVoters                     = int32Field(config.NumVoters)
+	numWitnesses                  = int32Field(config.NumWitnesses)
+	gcTTLSeconds                  = int32Field(config.GCTTL)
+	constraints                   = constraintsConjunctionField(config.Constraints)
+	voterConstraints              = constraintsConjunctionField(config.VoterConstraints)
+	leasePreferences              = leasePreferencesField(config.LeasePreferences)
+	storageCompression            = stringField(config.StorageCompression)
+	storageValueSeparationMinSize = int64Field(config.StorageValueSeparationMinSize)
 )
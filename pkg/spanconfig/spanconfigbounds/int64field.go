@@ -32,6 +32,8 @@ func (f int64Field) FieldBound(b *Bounds) ValueBounds {
 			return b.RangeMaxBytes
 		case rangeMinBytes:
 			return b.RangeMinBytes
+		case storageValueSeparationMinSize:
+			return nil
 		default:
 			// This is safe because we test that all the fields in the proto have
 			// a corresponding field, and we call this for each of them, and the user
@@ -55,6 +57,8 @@ func (f int64Field) fieldValue(c *roachpb.SpanConfig) *int64 {
 		return &c.RangeMaxBytes
 	case rangeMinBytes:
 		return &c.RangeMinBytes
+	case storageValueSeparationMinSize:
+		return &c.StorageValueSeparationMinSize
 	default:
 		// This is safe because we test that all the fields in the proto have
 		// a corresponding field, and we call this for each of them, and the user
@@ -0,0 +1,45 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package spanconfigbounds
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/redact"
+)
+
+type stringField int
+
+var _ field[string] = stringField(0)
+
+func (f stringField) SafeFormat(s redact.SafePrinter, verb rune) {
+	s.Printf("%s", config.Field(f))
+}
+
+func (f stringField) String() string {
+	return config.Field(f).String()
+}
+
+func (f stringField) FieldBound(b *Bounds) ValueBounds {
+	return unbounded{}
+}
+
+func (f stringField) FieldValue(c *roachpb.SpanConfig) Value {
+	return (*stringValue)(f.fieldValue(c))
+}
+
+func (f stringField) fieldValue(c *roachpb.SpanConfig) *string {
+	switch f {
+	case storageCompression:
+		return &c.StorageCompression
+	default:
+		// This is safe because we test that all the fields in the proto have
+		// a corresponding field, and we call this for each of them, and the user
+		// never provides the input to this function.
+		panic(errors.AssertionFailedf("failed to look up field %s", f))
+	}
+}
@@ -56,6 +56,15 @@ func (l leasePreferencesValue) SafeFormat(s interfaces.SafePrinter, verb rune) {
 	s.Printf("%v", []roachpb.LeasePreference(l))
 }
 
+type stringValue string
+
+func (v stringValue) String() string {
+	return string(v)
+}
+func (v stringValue) SafeFormat(s interfaces.SafePrinter, verb rune) {
+	s.Print(string(v))
+}
+
 type boolValue bool
 
 func (b boolValue) String() string {
@@ -0,0 +1,415 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// Advisory locks are KV locks on keys that hold no data. The keys of the
+// locks of a database are in the span of an index of the database's ID:
+// database IDs are never used as table IDs, so the span is otherwise empty.
+//
+// A transaction-scoped lock is acquired by the SQL transaction, so it is
+// released when the transaction finishes and takes part in deadlock
+// detection like any other lock the transaction holds. A session-scoped lock
+// is acquired by a KV transaction dedicated to the lock, which is rolled back
+// when the session releases the lock or closes. If the node running the
+// session dies, the dedicated transaction stops heartbeating and is aborted
+// by the first waiter that finds it expired. Deadlocks that involve
+// session-scoped locks are not detected; the waiters are subject to
+// lock_timeout.
+//
+// The locks are replicated so that they survive lease transfers.
+const advisoryLockIndexID = 1
+
+// advisoryLockSpan returns the span of the advisory locks of a database.
+func advisoryLockSpan(codec keys.SQLCodec, dbID descpb.ID) roachpb.Span {
+	prefix := codec.IndexPrefix(uint32(dbID), advisoryLockIndexID)
+	return roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()}
+}
+
+// advisoryLockKey returns the key locked by an advisory lock of a database.
+func advisoryLockKey(
+	codec keys.SQLCodec, dbID descpb.ID, key eval.AdvisoryLockKey,
+) roachpb.Key {
+	k := codec.IndexPrefix(uint32(dbID), advisoryLockIndexID)
+	k = encoding.EncodeUvarintAscending(k, uint64(advisoryLockSubID(key)))
+	k = encoding.EncodeUvarintAscending(k, uint64(key.Key1))
+	return encoding.EncodeUvarintAscending(k, uint64(key.Key2))
+}
+
+// advisoryLockSubID returns the objsubid of an advisory lock in pg_locks.
+func advisoryLockSubID(key eval.AdvisoryLockKey) int {
+	if key.TwoPart {
+		return 2
+	}
+	return 1
+}
+
+// decodeAdvisoryLockKey decodes a key returned by advisoryLockKey.
+func decodeAdvisoryLockKey(
+	codec keys.SQLCodec, k roachpb.Key,
+) (descpb.ID, eval.AdvisoryLockKey, error) {
+	rem, dbID, indexID, err := codec.DecodeIndexPrefix(k)
+	if err != nil {
+		return 0, eval.AdvisoryLockKey{}, err
+	}
+	if indexID != advisoryLockIndexID {
+		return 0, eval.AdvisoryLockKey{}, errors.AssertionFailedf("%s is not an advisory lock key", k)
+	}
+	var parts [3]uint64
+	for i := range parts {
+		if rem, parts[i], err = encoding.DecodeUvarintAscending(rem); err != nil {
+			return 0, eval.AdvisoryLockKey{}, err
+		}
+	}
+	return descpb.ID(dbID), eval.AdvisoryLockKey{
+		Key1:    uint32(parts[1]),
+		Key2:    uint32(parts[2]),
+		TwoPart: parts[0] == 2,
+	}, nil
+}
+
+// acquireAdvisoryLock locks the key in txn. It returns false if wait is false
+// and the key is locked by another transaction.
+func acquireAdvisoryLock(
+	ctx context.Context,
+	txn *kv.Txn,
+	key roachpb.Key,
+	shared, wait bool,
+	lockTimeout time.Duration,
+) (bool, error) {
+	b := txn.NewBatch()
+	if shared {
+		b.GetForShare(key, kvpb.GuaranteedDurability)
+	} else {
+		b.GetForUpdate(key, kvpb.GuaranteedDurability)
+	}
+	if wait {
+		b.Header.LockTimeout = lockTimeout
+	} else {
+		b.Header.WaitPolicy = lock.WaitPolicy_Error
+	}
+	if err := txn.Run(ctx, b); err != nil {
+		// A WriteIntentError leaves the transaction usable.
+		var wiErr *kvpb.WriteIntentError
+		if errors.As(err, &wiErr) {
+			if !wait {
+				return false, nil
+			}
+			return false, pgerror.Wrap(err, pgcode.LockNotAvailable, "could not obtain advisory lock")
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// sessionAdvisoryLock is a key locked by the dedicated transaction of a
+// session-scoped advisory lock.
+type sessionAdvisoryLock struct {
+	txn *kv.Txn
+	// exclusive is true if txn holds an exclusive lock on the key. Once
+	// upgraded, the lock stays exclusive until txn is rolled back.
+	exclusive bool
+	// sharedHolds and exclusiveHolds count the times the session acquired the
+	// lock in each mode without releasing it.
+	sharedHolds, exclusiveHolds int
+	// pinned is true if a transaction-scoped lock of the current transaction
+	// was granted because the session holds the lock, which must then be held
+	// until the transaction finishes.
+	pinned bool
+}
+
+func (l *sessionAdvisoryLock) held() bool {
+	return l.sharedHolds > 0 || l.exclusiveHolds > 0 || l.pinned
+}
+
+// advisoryLockManager tracks the advisory locks of a session. As in
+// Postgres, locks held by a session never conflict with each other.
+type advisoryLockManager struct {
+	db *kv.DB
+
+	// mu is held while acquiring locks, so concurrent requests from a single
+	// session are serialized.
+	mu struct {
+		syncutil.Mutex
+		// session contains the session-scoped locks, keyed by lock key.
+		session map[string]*sessionAdvisoryLock
+		// xact contains the keys locked by the current SQL transaction and
+		// whether the lock is exclusive.
+		xact map[string]bool
+	}
+}
+
+func newAdvisoryLockManager(db *kv.DB) *advisoryLockManager {
+	m := &advisoryLockManager{db: db}
+	m.mu.session = make(map[string]*sessionAdvisoryLock)
+	m.mu.xact = make(map[string]bool)
+	return m
+}
+
+// acquire acquires the lock on key for the session. Transaction-scoped locks
+// are acquired by txn.
+func (m *advisoryLockManager) acquire(
+	ctx context.Context,
+	txn *kv.Txn,
+	key roachpb.Key,
+	req eval.AdvisoryLockRequest,
+	lockTimeout time.Duration,
+) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	k := string(key)
+	held := m.mu.session[k]
+	if req.Xact {
+		if held != nil {
+			// The session's transaction for the lock already holds it, so it must
+			// keep holding it until txn finishes.
+			if ok, err := m.strengthenLocked(ctx, held, key, req, lockTimeout); !ok || err != nil {
+				return ok, err
+			}
+			held.pinned = true
+			return true, nil
+		}
+		if exclusive, ok := m.mu.xact[k]; ok && (exclusive || req.Shared) {
+			return true, nil
+		}
+		ok, err := acquireAdvisoryLock(ctx, txn, key, req.Shared, req.Wait, lockTimeout)
+		if !ok || err != nil {
+			return ok, err
+		}
+		m.mu.xact[k] = m.mu.xact[k] || !req.Shared
+		return true, nil
+	}
+
+	if held == nil {
+		if _, ok := m.mu.xact[k]; ok {
+			// The dedicated transaction would wait for txn, which cannot finish
+			// before this statement does.
+			return false, pgerror.New(pgcode.ObjectNotInPrerequisiteState,
+				"cannot acquire a session-level advisory lock that is held by the current transaction")
+		}
+		held = &sessionAdvisoryLock{txn: kv.NewTxn(ctx, m.db, 0 /* gatewayNodeID */)}
+		ok, err := acquireAdvisoryLock(ctx, held.txn, key, req.Shared, req.Wait, lockTimeout)
+		if !ok || err != nil {
+			if rollbackErr := held.txn.Rollback(ctx); rollbackErr != nil {
+				log.Dev.Warningf(ctx, "failed to roll back advisory lock transaction: %v", rollbackErr)
+			}
+			return ok, err
+		}
+		held.exclusive = !req.Shared
+		m.mu.session[k] = held
+	} else if ok, err := m.strengthenLocked(ctx, held, key, req, lockTimeout); !ok || err != nil {
+		return ok, err
+	}
+	if req.Shared {
+		held.sharedHolds++
+	} else {
+		held.exclusiveHolds++
+	}
+	return true, nil
+}
+
+// strengthenLocked upgrades the lock held by the session to an exclusive lock
+// if the request needs one.
+func (m *advisoryLockManager) strengthenLocked(
+	ctx context.Context,
+	held *sessionAdvisoryLock,
+	key roachpb.Key,
+	req eval.AdvisoryLockRequest,
+	lockTimeout time.Duration,
+) (bool, error) {
+	if held.exclusive || req.Shared {
+		return true, nil
+	}
+	ok, err := acquireAdvisoryLock(ctx, held.txn, key, false /* shared */, req.Wait, lockTimeout)
+	if !ok || err != nil {
+		return ok, err
+	}
+	held.exclusive = true
+	return true, nil
+}
+
+// release releases one hold of a session-scoped lock. It returns false if the
+// session does not hold the lock in the given mode.
+func (m *advisoryLockManager) release(ctx context.Context, key roachpb.Key, shared bool) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	held := m.mu.session[string(key)]
+	switch {
+	case held == nil:
+		return false
+	case shared && held.sharedHolds > 0:
+		held.sharedHolds--
+	case !shared && held.exclusiveHolds > 0:
+		held.exclusiveHolds--
+	default:
+		return false
+	}
+	m.maybeRollbackLocked(ctx, string(key), held)
+	return true
+}
+
+// releaseAll releases all session-scoped locks.
+func (m *advisoryLockManager) releaseAll(ctx context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for k, held := range m.mu.session {
+		held.sharedHolds, held.exclusiveHolds = 0, 0
+		m.maybeRollbackLocked(ctx, k, held)
+	}
+}
+
+// finishTxn is called when the SQL transaction finishes, which releases its
+// transaction-scoped locks.
+func (m *advisoryLockManager) finishTxn(ctx context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	clear(m.mu.xact)
+	for k, held := range m.mu.session {
+		held.pinned = false
+		m.maybeRollbackLocked(ctx, k, held)
+	}
+}
+
+// close releases all locks when the session closes.
+func (m *advisoryLockManager) close(ctx context.Context) {
+	m.releaseAll(ctx)
+	m.finishTxn(ctx)
+}
+
+func (m *advisoryLockManager) maybeRollbackLocked(
+	ctx context.Context, k string, held *sessionAdvisoryLock,
+) {
+	if held.held() {
+		return
+	}
+	delete(m.mu.session, k)
+	// If the rollback fails, the lock is released once the transaction
+	// expires.
+	if err := held.txn.Rollback(ctx); err != nil {
+		log.Dev.Warningf(ctx, "failed to roll back advisory lock transaction: %v", err)
+	}
+}
+
+var _ eval.AdvisoryLocker = &planner{}
+
+// AcquireAdvisoryLock is part of the eval.AdvisoryLocker interface.
+func (p *planner) AcquireAdvisoryLock(
+	ctx context.Context, req eval.AdvisoryLockRequest,
+) (bool, error) {
+	key, err := p.advisoryLockKey(ctx, req.Key)
+	if err != nil {
+		return false, err
+	}
+	return p.advisoryLocks.acquire(ctx, p.txn, key, req, p.SessionData().LockTimeout)
+}
+
+// ReleaseAdvisoryLock is part of the eval.AdvisoryLocker interface.
+func (p *planner) ReleaseAdvisoryLock(
+	ctx context.Context, key eval.AdvisoryLockKey, shared bool,
+) (bool, error) {
+	k, err := p.advisoryLockKey(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if !p.advisoryLocks.release(ctx, k, shared) {
+		mode := "ExclusiveLock"
+		if shared {
+			mode = "ShareLock"
+		}
+		p.BufferClientNotice(ctx, pgnotice.NewWithSeverityf("WARNING", "you don't own a lock of type %s", mode))
+		return false, nil
+	}
+	return true, nil
+}
+
+// ReleaseAllAdvisoryLocks is part of the eval.AdvisoryLocker interface.
+func (p *planner) ReleaseAllAdvisoryLocks(ctx context.Context) error {
+	if p.advisoryLocks == nil {
+		return errAdvisoryLocksUnsupported
+	}
+	p.advisoryLocks.releaseAll(ctx)
+	return nil
+}
+
+var errAdvisoryLocksUnsupported = pgerror.New(pgcode.FeatureNotSupported,
+	"advisory locks are only supported in SQL sessions")
+
+// advisoryLockKey returns the key of an advisory lock in the current
+// database.
+func (p *planner) advisoryLockKey(
+	ctx context.Context, key eval.AdvisoryLockKey,
+) (roachpb.Key, error) {
+	if p.advisoryLocks == nil {
+		return nil, errAdvisoryLocksUnsupported
+	}
+	if p.CurrentDatabase() == "" {
+		return nil, pgerror.New(pgcode.UndefinedDatabase,
+			"advisory locks require a current database")
+	}
+	dbDesc, err := p.Descriptors().ByNameWithLeased(p.txn).Get().Database(ctx, p.CurrentDatabase())
+	if err != nil {
+		return nil, err
+	}
+	return advisoryLockKey(p.ExecCfg().Codec, dbDesc.GetID(), key), nil
+}
+
+// forEachAdvisoryLock calls fn for each advisory lock of the database that is
+// held or waited on.
+func forEachAdvisoryLock(
+	ctx context.Context,
+	p *planner,
+	dbID descpb.ID,
+	fn func(key eval.AdvisoryLockKey, lock roachpb.LockStateInfo) error,
+) error {
+	span := advisoryLockSpan(p.ExecCfg().Codec, dbID)
+	for resume := &span; resume != nil; {
+		b := p.Txn().NewBatch()
+		b.AddRawRequest(&kvpb.QueryLocksRequest{
+			RequestHeader:      kvpb.RequestHeaderFromSpan(*resume),
+			IncludeUncontended: true,
+		})
+		b.Header.MaxSpanRequestKeys = int64(rowinfra.ProductionKVBatchSize)
+		if err := p.Txn().Run(ctx, b); err != nil {
+			return err
+		}
+		resp := b.RawResponse().Responses[0].GetQueryLocks()
+		for _, l := range resp.Locks {
+			_, key, err := decodeAdvisoryLockKey(p.ExecCfg().Codec, l.Key)
+			if err != nil {
+				return err
+			}
+			if err := fn(key, l); err != nil {
+				return err
+			}
+		}
+		resume = resp.ResumeSpan
+	}
+	return nil
+}
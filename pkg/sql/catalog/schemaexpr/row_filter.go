@@ -0,0 +1,87 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package schemaexpr
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/parserutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/transform"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// ValidateRowFilter verifies that an expression is a valid filter of the rows
+// of a table. If the expression is valid, it returns the serialized expression
+// with the columns dequalified, along with the IDs of the columns it
+// references.
+//
+// A row filter is valid if all the following are true:
+//
+//   - It results in a boolean.
+//   - It refers only to columns in the table.
+//   - It does not include subqueries.
+//   - It does not include non-immutable, aggregate, window, or set returning
+//     functions.
+func ValidateRowFilter(
+	ctx context.Context,
+	desc catalog.TableDescriptor,
+	e tree.Expr,
+	context tree.SchemaExprContext,
+	tn *tree.TableName,
+	semaCtx *tree.SemaContext,
+	version clusterversion.ClusterVersion,
+) (string, catalog.TableColSet, error) {
+	expr, _, cols, err := DequalifyAndValidateExpr(
+		ctx,
+		desc,
+		e,
+		types.Bool,
+		context,
+		semaCtx,
+		volatility.Immutable,
+		tn,
+		version,
+	)
+	if err != nil {
+		return "", catalog.TableColSet{}, err
+	}
+	return expr, cols, nil
+}
+
+// MakeRowFilterExpr turns a serialized row filter, as returned by
+// ValidateRowFilter, into a TypedExpr. The ordinal of each IndexedVar in the
+// expression is the ordinal of its column in the public columns of the table,
+// so it can be evaluated with a RowIndexedVarContainer.
+func MakeRowFilterExpr(
+	ctx context.Context,
+	table catalog.TableDescriptor,
+	filter string,
+	evalCtx *eval.Context,
+	semaCtx *tree.SemaContext,
+) (tree.TypedExpr, error) {
+	expr, err := parserutils.ParseExpr(filter)
+	if err != nil {
+		return nil, err
+	}
+	tn := tree.NewUnqualifiedTableName(tree.Name(table.GetName()))
+	nr := newNameResolver(table.GetID(), tn, table.PublicColumns())
+	nr.addIVarContainerToSemaCtx(semaCtx)
+	expr, err = nr.resolveNames(expr)
+	if err != nil {
+		return nil, err
+	}
+	typedExpr, err := tree.TypeCheck(ctx, expr, semaCtx, types.Bool)
+	if err != nil {
+		return nil, err
+	}
+	var txCtx transform.ExprTransformContext
+	return txCtx.NormalizeExpr(ctx, evalCtx, typedExpr)
+}
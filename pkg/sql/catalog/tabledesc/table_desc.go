@@ -209,6 +209,13 @@ func (desc *Mutable) OfflineForImport() {
 	desc.ImportEpoch++
 }
 
+// OfflineForUpsertImport sets the descriptor offline in advance of an
+// import that overwrites existing rows.
+func (desc *Mutable) OfflineForUpsertImport() {
+	desc.SetOffline(OfflineReasonImporting)
+	desc.ImportType = descpb.ImportType_IMPORT_UPSERT
+}
+
 // InitializeImport binds the import start time to the table descriptor.
 func (desc *Mutable) InitializeImport(startWallTime int64) error {
 	if desc.ImportStartWallTime != 0 {
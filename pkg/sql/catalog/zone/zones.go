@@ -73,6 +73,25 @@ func init() {
 			RequiredType: types.Int,
 			Setter:       func(c *zonepb.ZoneConfig, d tree.Datum) { c.NumVoters = proto.Int32(int32(tree.MustBeDInt(d))) },
 		},
+		{
+			Field:        config.NumWitnesses,
+			RequiredType: types.Int,
+			Setter:       func(c *zonepb.ZoneConfig, d tree.Datum) { c.NumWitnesses = proto.Int32(int32(tree.MustBeDInt(d))) },
+		},
+		{
+			Field:        config.StorageCompression,
+			RequiredType: types.String,
+			Setter: func(c *zonepb.ZoneConfig, d tree.Datum) {
+				c.StorageCompression = proto.String(string(tree.MustBeDString(d)))
+			},
+		},
+		{
+			Field:        config.StorageValueSeparationMinSize,
+			RequiredType: types.Int,
+			Setter: func(c *zonepb.ZoneConfig, d tree.Datum) {
+				c.StorageValueSeparationMinSize = proto.Int64(int64(tree.MustBeDInt(d)))
+			},
+		},
 		{
 			Field:        config.GCTTL,
 			RequiredType: types.Int,
@@ -1269,6 +1269,7 @@ func (s *Server) newConnExecutor(
 		phaseTimes:                sessionphase.NewTimes(),
 		executorType:              executorType,
 		hasCreatedTemporarySchema: false,
+		advisoryLocks:             newAdvisoryLockManager(s.cfg.DB),
 		stmtDiagnosticsRecorder:   s.cfg.StmtDiagnosticsRecorder,
 		txnDiagnosticsRecorder:    s.cfg.TxnDiagnosticsRecorder,
 		indexUsageStats:           s.indexUsageStats,
@@ -1464,6 +1465,7 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 	}
 
 	ex.resetExtraTxnState(ctx, txnEvent{eventType: txnEvType}, payloadErr)
+	ex.advisoryLocks.close(ctx)
 	if ex.hasCreatedTemporarySchema && !ex.server.cfg.TestingKnobs.DisableTempObjectsCleanupOnSessionExit {
 		err := cleanupSessionTempObjects(
 			ctx,
@@ -1961,6 +1963,10 @@ type connExecutor struct {
 	// temporary schema, which requires special cleanup on close.
 	hasCreatedTemporarySchema bool
 
+	// advisoryLocks tracks the advisory locks held by the session, which are
+	// released on close.
+	advisoryLocks *advisoryLockManager
+
 	// stmtDiagnosticsRecorder is used to track which queries need to have
 	// information collected.
 	stmtDiagnosticsRecorder *stmtdiagnostics.Registry
@@ -2201,6 +2207,7 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent, pay
 	ex.extraTxnState.upgradedToSerializable = false
 	ex.extraTxnState.hasAdminRoleCache = HasAdminRoleCache{}
 	ex.extraTxnState.createdSequences = nil
+	ex.advisoryLocks.finishTxn(ctx)
 
 	if ex.extraTxnState.skipResettingSchemaObjects {
 		if ex.extraTxnState.shouldResetSyntheticDescriptors {
@@ -3917,6 +3924,7 @@ func (ex *connExecutor) initEvalCtx(ctx context.Context, evalCtx *extendedEvalCo
 			Regions:                          p,
 			Gossip:                           p,
 			PreparedStatementState:           &ex.extraTxnState.prepStmtsNamespace,
+			AdvisoryLocker:                   p,
 			SessionDataStack:                 ex.sessionDataStack,
 			ReCache:                          ex.server.reCache,
 			ToCharFormatCache:                ex.server.toCharFormatCache,
@@ -4065,6 +4073,7 @@ func (ex *connExecutor) initPlanner(ctx context.Context, p *planner) {
 	p.noticeSender = nil
 	p.preparedStatements = ex.getPrepStmtsAccessor()
 	p.sqlCursors = ex.getCursorAccessor()
+	p.advisoryLocks = ex.advisoryLocks
 	p.routineMetadataForwarder = nil
 	p.storedProcTxnState = ex.getStoredProcTxnStateAccessor()
 	p.createdSequences = ex.getCreatedSequencesAccessor()
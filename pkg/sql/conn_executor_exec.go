@@ -669,6 +669,7 @@ func (ex *connExecutor) execStmtInOpenState(
 		p.txn.SetWorkloadInfo(
 			uint64(ih.fingerprintId), appNameID, workloadid.WorkloadTypeStatement,
 		)
+		ex.maybeWaitForCausalityToken(p.txn)
 	}
 
 	// Note that here we always unconditionally defer a function that takes care
@@ -1761,6 +1762,7 @@ func (ex *connExecutor) execStmtInOpenStateWithPausablePortal(
 		p.txn.SetWorkloadInfo(
 			uint64(ih.fingerprintId), appNameID2, workloadid.WorkloadTypeStatement,
 		)
+		ex.maybeWaitForCausalityToken(p.txn)
 	}
 
 	if buildutil.CrdbTestBuild {
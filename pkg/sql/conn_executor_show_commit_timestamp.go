@@ -8,6 +8,7 @@ package sql
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -144,3 +145,16 @@ func writeShowCommitTimestampRow(
 	res.SetColumns(ctx, colinfo.ShowCommitTimestampColumns, false /* skipRowDescription */)
 	return res.AddRow(ctx, tree.Datums{eval.TimestampToDecimalDatum(ts)})
 }
+
+// maybeWaitForCausalityToken marks historical transactions of sessions which
+// have a causality token set, i.e. the commit timestamp returned by SHOW COMMIT
+// TIMESTAMP after an earlier write, to wait for the closed timestamps of
+// followers to reach their timestamp. The timestamps of follower reads in such
+// sessions are forwarded to the token (see asof.Eval), which is typically above
+// the closed timestamps of followers, so without waiting they would be served
+// by the leaseholder.
+func (ex *connExecutor) maybeWaitForCausalityToken(txn *kv.Txn) {
+	if ex.state.isHistorical.Load() && !ex.sessionData().CausalityToken.IsEmpty() {
+		txn.SetWaitForClosedTimestamp()
+	}
+}
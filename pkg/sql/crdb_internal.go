@@ -8143,6 +8143,15 @@ func genClusterLocksGenerator(
 					continue
 				}
 				spansToQuery = append(spansToQuery, desc.TableSpan(p.execCfg.Codec))
+			case catalog.DatabaseDescriptor:
+				// Advisory locks are held on keys under the database's ID.
+				if filters.tableName != nil || filters.tableID != nil {
+					continue
+				}
+				if filters.databaseName != nil && *filters.databaseName != desc.GetName() {
+					continue
+				}
+				spansToQuery = append(spansToQuery, advisoryLockSpan(p.execCfg.Codec, desc.GetID()))
 			}
 		}
 
@@ -86,6 +86,7 @@ const (
 	exportSnappyCodec     = "snappy"
 	csvSuffix             = "csv"
 	parquetSuffix         = "parquet"
+	ndjsonSuffix          = "ndjson"
 )
 
 var exportOptionExpectValues = map[string]exprutil.KVStringOptValidate{
@@ -159,7 +160,7 @@ func buildExportPlanningInfo(
 		return nil, errors.Errorf("EXPORT cannot be used inside a multi-statement transaction")
 	}
 
-	if fileSuffix != csvSuffix && fileSuffix != parquetSuffix {
+	if fileSuffix != csvSuffix && fileSuffix != parquetSuffix && fileSuffix != ndjsonSuffix {
 		return nil, errors.Errorf("unsupported export format: %q", fileSuffix)
 	}
 
@@ -242,6 +243,8 @@ func buildExportPlanningInfo(
 		}
 		format.Format = roachpb.IOFileFormat_Parquet
 		format.Parquet = parquetOpts
+	case ndjsonSuffix:
+		format.Format = roachpb.IOFileFormat_NDJSON
 	}
 
 	chunkRows := exportChunkRowsDefault
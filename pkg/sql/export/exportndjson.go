@@ -0,0 +1,322 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra/execopnode"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/unique"
+	"github.com/cockroachdb/errors"
+)
+
+const exportNDJSONFilePatternDefault = exportFilePatternPart + ".ndjson"
+
+// ndjsonExporter buffers the lines of an NDJSON file, optionally compressing
+// them.
+type ndjsonExporter struct {
+	compressor *gzip.Writer
+	buf        *bytes.Buffer
+	// w is the writer of the lines, which is the compressor if there is one.
+	w io.Writer
+	// keys holds the JSON-encoded column names followed by the key separator.
+	// Lines are formatted like JSONB objects are.
+	keys [][]byte
+	line bytes.Buffer
+}
+
+func newNDJSONExporter(sp execinfrapb.ExportSpec) *ndjsonExporter {
+	buf := bytes.NewBuffer([]byte{})
+	e := &ndjsonExporter{buf: buf, w: buf}
+	if sp.Format.Compression == roachpb.IOFileFormat_Gzip {
+		e.compressor = gzip.NewWriter(buf)
+		e.w = e.compressor
+	}
+	e.keys = make([][]byte, len(sp.ColNames))
+	for i, name := range sp.ColNames {
+		var key bytes.Buffer
+		json.FromString(name).Format(&key)
+		key.WriteString(": ")
+		e.keys[i] = key.Bytes()
+	}
+	return e
+}
+
+// Write writes a line holding a JSON object with the values of a row, keyed by
+// the column names in their order.
+func (e *ndjsonExporter) Write(row []json.JSON) error {
+	e.line.Reset()
+	e.line.WriteByte('{')
+	for i, j := range row {
+		if i > 0 {
+			e.line.WriteString(", ")
+		}
+		e.line.Write(e.keys[i])
+		j.Format(&e.line)
+	}
+	e.line.WriteString("}\n")
+	_, err := e.w.Write(e.line.Bytes())
+	return err
+}
+
+// Close closes the compressor writer which appends archive footers.
+func (e *ndjsonExporter) Close() error {
+	if e.compressor != nil {
+		return e.compressor.Close()
+	}
+	return nil
+}
+
+// Flush flushes the compressor writer if initialized.
+func (e *ndjsonExporter) Flush() error {
+	if e.compressor != nil {
+		return e.compressor.Flush()
+	}
+	return nil
+}
+
+// ResetBuffer resets the buffer and compressor state.
+func (e *ndjsonExporter) ResetBuffer() {
+	e.buf.Reset()
+	if e.compressor != nil {
+		e.compressor.Reset(e.buf)
+	}
+}
+
+// Bytes returns the buffered, possibly compressed, content.
+func (e *ndjsonExporter) Bytes() []byte {
+	return e.buf.Bytes()
+}
+
+// Len returns length of the buffer with content.
+func (e *ndjsonExporter) Len() int {
+	return e.buf.Len()
+}
+
+func (e *ndjsonExporter) FileName(spec execinfrapb.ExportSpec, part string) string {
+	pattern := exportNDJSONFilePatternDefault
+	if spec.NamePattern != "" {
+		pattern = spec.NamePattern
+	}
+	fileName := strings.Replace(pattern, exportFilePatternPart, part, -1)
+	if e.compressor != nil {
+		fileName += ".gz"
+	}
+	return fileName
+}
+
+// ndjsonWriter is a processor which exports its input rows as files of
+// newline-delimited JSON, in which each line holds a JSON object with the
+// values of a row keyed by the column names.
+type ndjsonWriter struct {
+	execinfra.ProcessorBase
+
+	spec       execinfrapb.ExportSpec
+	input      execinfra.RowSource
+	inputTypes []*types.T
+	uniqueID   int64
+
+	writer *ndjsonExporter
+
+	runningState exportState
+	done         bool
+	jsonRow      []json.JSON
+	chunk        int
+	rows         int64
+
+	alloc tree.DatumAlloc
+}
+
+var _ execinfra.RowSourcedProcessor = &ndjsonWriter{}
+var _ execopnode.OpNode = &ndjsonWriter{}
+
+func NewNDJSONWriterProcessor(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	processorID int32,
+	spec execinfrapb.ExportSpec,
+	post *execinfrapb.PostProcessSpec,
+	input execinfra.RowSource,
+) (execinfra.Processor, error) {
+	if len(spec.ColNames) != len(input.OutputTypes()) {
+		return nil, errors.AssertionFailedf(
+			"expected %d column names, found %d", len(input.OutputTypes()), len(spec.ColNames))
+	}
+	sp := &ndjsonWriter{
+		spec:       spec,
+		input:      input,
+		inputTypes: input.OutputTypes(),
+		uniqueID:   unique.GenerateUniqueInt(unique.ProcessUniqueID(flowCtx.EvalCtx.NodeID.SQLInstanceID())),
+		writer:     newNDJSONExporter(spec),
+		jsonRow:    make([]json.JSON, len(input.OutputTypes())),
+	}
+	if err := sp.Init(
+		ctx, sp, post, colinfo.ExportColumnTypes, flowCtx, processorID, nil, /* memMonitor */
+		execinfra.ProcStateOpts{
+			InputsToDrain: []execinfra.RowSource{sp.input},
+		},
+	); err != nil {
+		return nil, err
+	}
+	return sp, nil
+}
+
+func (sp *ndjsonWriter) Start(ctx context.Context) {
+	ctx = sp.StartInternal(ctx, "ndjsonWriter")
+	sp.input.Start(ctx)
+	sp.runningState = exportNewChunk
+}
+
+func (sp *ndjsonWriter) Next() (rowenc.EncDatumRow, *execinfrapb.ProducerMetadata) {
+	for sp.State == execinfra.StateRunning {
+		switch sp.runningState {
+		case exportNewChunk:
+			sp.writer.ResetBuffer()
+			sp.rows = 0
+			sp.runningState = exportContinueChunk
+			continue
+
+		case exportContinueChunk:
+			// If the bytes.Buffer sink exceeds the target size of a file, we
+			// flush before exporting any additional rows.
+			if int64(sp.writer.Len()) >= sp.spec.ChunkSize {
+				sp.runningState = exportFlushChunk
+				continue
+			}
+			if sp.spec.ChunkRows > 0 && sp.rows >= sp.spec.ChunkRows {
+				sp.runningState = exportFlushChunk
+				continue
+			}
+			row, meta := sp.input.Next()
+			if meta != nil {
+				return nil, meta
+			}
+			if row == nil {
+				sp.done = true
+				if sp.rows > 0 {
+					sp.runningState = exportFlushChunk
+				} else {
+					sp.runningState = exportDone
+				}
+				continue
+			}
+			sp.rows++
+			for i, ed := range row {
+				if err := ed.EnsureDecoded(sp.inputTypes[i], &sp.alloc); err != nil {
+					sp.MoveToDraining(err)
+					return nil, sp.DrainHelper()
+				}
+				j, err := tree.AsJSON(
+					ed.Datum, sp.FlowCtx.EvalCtx.SessionData().DataConversionConfig, sp.FlowCtx.EvalCtx.GetLocation(),
+				)
+				if err != nil {
+					sp.MoveToDraining(err)
+					return nil, sp.DrainHelper()
+				}
+				sp.jsonRow[i] = j
+			}
+			if err := sp.writer.Write(sp.jsonRow); err != nil {
+				sp.MoveToDraining(err)
+				return nil, sp.DrainHelper()
+			}
+			// continue building the current chunk
+
+		case exportFlushChunk:
+			if err := sp.writer.Flush(); err != nil {
+				sp.MoveToDraining(errors.Wrap(err, "failed to flush ndjson writer"))
+				return nil, sp.DrainHelper()
+			}
+			row, err := sp.exportFile()
+			if err != nil {
+				sp.MoveToDraining(err)
+				return nil, sp.DrainHelper()
+			}
+			sp.runningState = exportNewChunk
+			if outRow := sp.ProcessRowHelper(row); outRow != nil {
+				return outRow, nil
+			}
+			// Either find that we're no longer in StateRunning, or proceed to
+			// the next chunk.
+
+		case exportDone:
+			sp.MoveToDraining(nil /* err */)
+
+		default:
+			log.Dev.Fatalf(sp.Ctx(), "unsupported state: %d", sp.runningState)
+		}
+	}
+
+	return nil, sp.DrainHelper()
+}
+
+func (sp *ndjsonWriter) exportFile() (rowenc.EncDatumRow, error) {
+	conf, err := cloud.ExternalStorageConfFromURI(sp.spec.Destination, sp.spec.User())
+	if err != nil {
+		return nil, err
+	}
+	es, err := sp.FlowCtx.Cfg.ExternalStorage(sp.Ctx(), conf)
+	if err != nil {
+		return nil, err
+	}
+	defer es.Close()
+
+	part := fmt.Sprintf("n%d.%d", sp.uniqueID, sp.chunk)
+	sp.chunk++
+	filename := sp.writer.FileName(sp.spec, part)
+	// Close writer to ensure buffer and any compression footer is flushed.
+	if err := sp.writer.Close(); err != nil {
+		return nil, errors.Wrapf(err, "failed to close exporting writer")
+	}
+
+	size := sp.writer.Len()
+
+	if err := cloud.WriteFile(sp.Ctx(), es, filename, bytes.NewReader(sp.writer.Bytes())); err != nil {
+		return nil, err
+	}
+	return rowenc.EncDatumRow{
+		rowenc.DatumToEncDatumUnsafe(types.String, tree.NewDString(filename)),
+		rowenc.DatumToEncDatumUnsafe(types.Int, tree.NewDInt(tree.DInt(sp.rows))),
+		rowenc.DatumToEncDatumUnsafe(types.Int, tree.NewDInt(tree.DInt(size))),
+	}, nil
+}
+
+func (sp *ndjsonWriter) ConsumerClosed() {
+	if sp.InternalClose() {
+		_ = sp.writer.Close()
+	}
+}
+
+func (sp *ndjsonWriter) ChildCount(verbose bool) int {
+	if _, ok := sp.input.(execopnode.OpNode); ok {
+		return 1
+	}
+	return 0
+}
+
+func (sp *ndjsonWriter) Child(nth int, verbose bool) execopnode.OpNode {
+	if nth == 0 {
+		if n, ok := sp.input.(execopnode.OpNode); ok {
+			return n
+		}
+		panic("input to ndjsonWriter is not an execopnode.OpNode")
+	}
+	panic(errors.AssertionFailedf("invalid index %d", nth))
+}
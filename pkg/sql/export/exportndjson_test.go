@@ -0,0 +1,83 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package export_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+const exportNDJSONFilePattern = "export*-n*.0.ndjson"
+
+func TestExportNDJSON(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	dir, cleanupDir := testutils.TempDir(t)
+	defer cleanupDir()
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(context.Background())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `CREATE TABLE foo (i INT PRIMARY KEY, s STRING, j JSONB, a INT[], d DECIMAL)`)
+	sqlDB.Exec(t, `INSERT INTO foo VALUES
+		(1, 'say "hi"', '{"k": [1, 2]}', ARRAY[1, 2], 1.50),
+		(2, NULL, NULL, NULL, NULL)`)
+	const expected = `{"i": 1, "s": "say \"hi\"", "j": {"k": [1, 2]}, "a": [1, 2], "d": 1.50}
+{"i": 2, "s": null, "j": null, "a": null, "d": null}
+`
+
+	t.Run("plain", func(t *testing.T) {
+		sqlDB.Exec(t, `EXPORT INTO NDJSON 'nodelocal://1/plain' FROM SELECT * FROM foo ORDER BY i`)
+		content := readFileByGlob(t, filepath.Join(dir, "plain", exportNDJSONFilePattern))
+		require.Equal(t, expected, string(content))
+	})
+
+	t.Run("compressed", func(t *testing.T) {
+		sqlDB.Exec(t, `EXPORT INTO NDJSON 'nodelocal://1/compressed' WITH compression = gzip
+			FROM SELECT * FROM foo ORDER BY i`)
+		compressed := readFileByGlob(t, filepath.Join(dir, "compressed", exportNDJSONFilePattern+".gz"))
+		gzipReader, err := gzip.NewReader(bytes.NewReader(compressed))
+		require.NoError(t, err)
+		defer func() { require.NoError(t, gzipReader.Close()) }()
+		content, err := io.ReadAll(gzipReader)
+		require.NoError(t, err)
+		require.Equal(t, expected, string(content))
+	})
+
+	t.Run("chunked", func(t *testing.T) {
+		rows := sqlDB.QueryStr(t, `EXPORT INTO NDJSON 'nodelocal://1/chunked' WITH chunk_rows = 1
+			FROM SELECT * FROM foo ORDER BY i`)
+		require.Len(t, rows, 2)
+		for _, row := range rows {
+			require.Equal(t, "1", row[1])
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		sqlDB.Exec(t, `EXPORT INTO NDJSON 'nodelocal://1/round_trip' FROM SELECT * FROM foo`)
+		sqlDB.Exec(t, `CREATE TABLE bar (LIKE foo INCLUDING ALL)`)
+		sqlDB.Exec(t, `IMPORT INTO bar NDJSON DATA ('nodelocal://1/round_trip/*')`)
+		sqlDB.CheckQueryResults(t, `SELECT * FROM bar ORDER BY i`, sqlDB.QueryStr(t, `SELECT * FROM foo ORDER BY i`))
+	})
+
+	t.Run("header row", func(t *testing.T) {
+		sqlDB.ExpectErr(t, `header row is only supported for csv file format`,
+			`EXPORT INTO NDJSON 'nodelocal://1/header' WITH header_row FROM SELECT * FROM foo`)
+	})
+}
@@ -0,0 +1,860 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package importer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// dumpStatement is a statement of a dump to be executed by IMPORT PGDUMP or
+// IMPORT MYSQLDUMP.
+type dumpStatement struct {
+	// text is the text of the statement in the dump.
+	text string
+	// sql is the SQL to execute for the statement, if it is not its text,
+	// which is the case for statements translated from MySQL.
+	sql string
+	// ast is the parsed statement, if it could be parsed.
+	ast tree.Statement
+	// parseErr is the error encountered parsing or translating the statement.
+	parseErr error
+	// modified is set if ast was modified and needs to be executed in place of
+	// the statement's text.
+	modified bool
+	// dropped is set if the statement was folded into another one.
+	dropped bool
+	// data is set for a statement which loads the data of a table.
+	data *dumpTableData
+}
+
+// dumpTableData describes the data of a table in a dump.
+type dumpTableData struct {
+	// table is the table the data is imported into.
+	table tree.TableName
+	// name is the name of the table in the dump, which is passed to the
+	// reader of the data.
+	name string
+	// cols are the columns of the data, or nil if the data has all the visible
+	// columns of the table.
+	cols tree.NameList
+	// offset is the offset in the uncompressed dump from which the reader of
+	// the data can start, or zero if it needs to read the dump from its start.
+	offset int64
+}
+
+// execSQL returns the SQL to execute for the statement.
+func (s *dumpStatement) execSQL() string {
+	if s.modified {
+		return tree.AsStringWithFlags(s.ast, tree.FmtParsable)
+	}
+	if s.sql != "" {
+		return s.sql
+	}
+	return s.text
+}
+
+// dumpDataConcurrency is the number of tables whose data IMPORT PGDUMP and
+// IMPORT MYSQLDUMP import concurrently, each with an IMPORT INTO job.
+const dumpDataConcurrency = 4
+
+// importDumpSkippedInfoKeyPrefix is the prefix of the info keys under which
+// an IMPORT PGDUMP or IMPORT MYSQLDUMP job stores the statements it skipped,
+// followed by the index of the statement in the dump.
+const importDumpSkippedInfoKeyPrefix = "~import-dump/skipped/"
+
+// importDumpBundle plans IMPORT PGDUMP, which restores a dump produced by
+// pg_dump in the plain or custom format into the current database, and
+// IMPORT MYSQLDUMP, which does the same for a dump produced by mysqldump. The
+// dump is restored by an IMPORT_DUMP job; see importDumpResumer.
+func importDumpBundle(
+	ctx context.Context,
+	p sql.PlanHookState,
+	importStmt *tree.Import,
+	opts map[string]string,
+	files []string,
+	isDetached bool,
+	resultsCh chan<- tree.Datums,
+) error {
+	switch importStmt.FileFormat {
+	case "PGDUMP", "MYSQLDUMP":
+	default:
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"IMPORT %s is not supported; use IMPORT INTO", importStmt.FileFormat)
+	}
+	if err := validateDumpFormatOptions(importStmt.FileFormat, opts, dumpBundleAllowedOptions); err != nil {
+		return err
+	}
+	if len(files) != 1 {
+		return errors.Errorf("IMPORT %s requires exactly one file, found %d", importStmt.FileFormat, len(files))
+	}
+	maxRowSize, err := parseMaxRowSize(opts)
+	if err != nil {
+		return err
+	}
+	compression, err := parseCompressionOption(opts)
+	if err != nil {
+		return err
+	}
+	logURI, logIgnored := opts[pgDumpLogIgnoredStatements]
+	if logIgnored {
+		if err := sql.CheckDestinationPrivileges(ctx, p, []string{logURI}); err != nil {
+			return err
+		}
+	}
+	jobDesc, err := importJobDescription(ctx, p, importStmt, files, opts)
+	if err != nil {
+		return err
+	}
+	jr := jobs.Record{
+		Description: jobDesc,
+		Username:    p.User(),
+		Details: jobspb.ImportDumpDetails{
+			Format:               importStmt.FileFormat,
+			URI:                  files[0],
+			Compression:          compression,
+			MaxRowSize:           maxRowSize,
+			LogIgnoredStatements: logURI,
+			Database:             p.SessionData().Database,
+			SearchPath:           p.SessionData().SearchPath.GetPathArray(),
+		},
+		Progress: jobspb.ImportDumpProgress{},
+	}
+
+	jobID := p.ExecCfg().JobRegistry.MakeJobID()
+	if isDetached {
+		if _, err := p.ExecCfg().JobRegistry.CreateAdoptableJobWithTxn(
+			ctx, jr, jobID, p.InternalSQLTxn()); err != nil {
+			return err
+		}
+		resultsCh <- tree.Datums{tree.NewDInt(tree.DInt(jobID))}
+		return nil
+	}
+
+	var sj *jobs.StartableJob
+	if err := func() (err error) {
+		defer func() {
+			if err == nil || sj == nil {
+				return
+			}
+			if cleanupErr := sj.CleanupOnRollback(ctx); cleanupErr != nil {
+				log.Dev.Errorf(ctx, "failed to cleanup job: %v", cleanupErr)
+			}
+		}()
+		if err := p.ExecCfg().JobRegistry.CreateStartableJobWithTxn(
+			ctx, &sj, jobID, p.InternalSQLTxn(), jr); err != nil {
+			return err
+		}
+		// As for IMPORT INTO, the transaction is committed so that the job can
+		// be started, which is safe because we're in an implicit transaction.
+		return p.InternalSQLTxn().KV().Commit(ctx)
+	}(); err != nil {
+		return err
+	}
+	// The job creates and modifies descriptors, so release the leases held by
+	// the committed transaction.
+	p.InternalSQLTxn().Descriptors().ReleaseAll(ctx)
+	if err := sj.Start(ctx); err != nil {
+		return err
+	}
+	if err := sj.AwaitCompletion(ctx); err != nil {
+		return err
+	}
+	if err := sj.ReportExecutionResults(ctx, resultsCh); err != nil {
+		return err
+	}
+
+	job, err := p.ExecCfg().JobRegistry.LoadJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	numSkipped := job.Progress().GetImportDump().NumSkipped
+	if numSkipped == 0 {
+		return nil
+	}
+	if !logIgnored {
+		p.BufferClientNotice(ctx, errors.WithHintf(
+			pgnotice.Newf("IMPORT %s skipped %d unsupported statements", importStmt.FileFormat, numSkipped),
+			"use the %s option to record the skipped statements", pgDumpLogIgnoredStatements))
+		return nil
+	}
+	p.BufferClientNotice(ctx, pgnotice.Newf(
+		"IMPORT %s skipped %d unsupported statements, which were written to the %s destination",
+		importStmt.FileFormat, numSkipped, pgDumpLogIgnoredStatements))
+	return nil
+}
+
+// importDumpResumer implements the IMPORT_DUMP job, which executes the
+// statements of a dump in order: the schema objects are created with the
+// declarative schema changer, and the data of each table is ingested by a
+// distributed IMPORT INTO job, which reads the table's data from the offset in
+// the dump recorded while reading the statements. The data of consecutive
+// tables is imported concurrently. Statements which are not supported are
+// skipped rather than failing the import, and are written to the
+// log_ignored_statements destination if one is specified.
+//
+// Each statement is executed in a transaction which also records it in the
+// job's progress, along with the statement dropping the object it created, if
+// any, and each IMPORT INTO job is recorded in the transaction which creates
+// it. A resumed job therefore continues with the first statement it has not
+// executed, and waits for the IMPORT INTO jobs it already started. If the job
+// fails or is canceled, it cancels its IMPORT INTO jobs and drops the objects
+// it created. The objects are visible to other sessions while they are
+// imported, though, and the tables are offline while their data is.
+type importDumpResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = (*importDumpResumer)(nil)
+
+// Resume is part of the jobs.Resumer interface.
+func (r *importDumpResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(sql.JobExecContext)
+	execCfg := p.ExecCfg()
+	details := r.job.Details().(jobspb.ImportDumpDetails)
+	var tableOption string
+	switch details.Format {
+	case "PGDUMP":
+		tableOption = pgDumpTable
+	case "MYSQLDUMP":
+		tableOption = mysqlDumpTable
+	default:
+		return errors.AssertionFailedf("unexpected dump format %q", details.Format)
+	}
+
+	// The statements are read again each time the job is resumed. Reading a
+	// dump is deterministic, so the statement indexes recorded in the
+	// progress remain valid.
+	var stmts []dumpStatement
+	if err := readDumpFile(ctx, execCfg, p.User(), details.URI, details.Compression, func(rd io.Reader) error {
+		if details.Format == "MYSQLDUMP" {
+			items, err := readMysqlDumpItems(rd, int(details.MaxRowSize))
+			if err != nil {
+				return err
+			}
+			stmts = mysqlDumpStatements(items)
+			return nil
+		}
+		items, err := readPgDumpItems(rd, int(details.MaxRowSize))
+		if err != nil {
+			return err
+		}
+		stmts = pgDumpStatements(items)
+		return nil
+	}); err != nil {
+		return err
+	}
+	// The offsets of the data can only be seeked to if the dump is not
+	// compressed; the reader of a compressed dump has to decompress it up to
+	// the offset.
+	decompress := strings.ToLower(guessCompressionFromName(details.URI, details.Compression).String())
+
+	sd := r.sessionData(ctx, execCfg)
+	for i := int(r.job.Progress().GetImportDump().NextStatement); i < len(stmts); {
+		if stmts[i].data == nil {
+			if err := r.executeStatement(ctx, execCfg, sd, stmts, i); err != nil {
+				return err
+			}
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(stmts) && stmts[j].data != nil {
+			j++
+		}
+		if err := r.importData(ctx, execCfg, sd, tableOption, decompress, stmts, i, j); err != nil {
+			return err
+		}
+		i = j
+	}
+	return r.writeSkipped(ctx, execCfg, p.User())
+}
+
+// sessionData returns the session data the statements of the dump are
+// executed with, which has the user, database and search path of the session
+// which started the import.
+func (r *importDumpResumer) sessionData(
+	ctx context.Context, execCfg *sql.ExecutorConfig,
+) *sessiondata.SessionData {
+	details := r.job.Details().(jobspb.ImportDumpDetails)
+	sd := sql.NewInternalSessionData(ctx, execCfg.Settings, "import-dump")
+	sd.UserProto = r.job.Payload().UsernameProto
+	sd.Database = details.Database
+	sd.SearchPath = sessiondata.MakeSearchPath(details.SearchPath)
+	sd.NewSchemaChangerMode = sessiondatapb.UseNewSchemaChangerOn
+	return sd
+}
+
+// executeStatement executes the statement of the dump with index i, other
+// than one loading the data of a table, and records it in the job's progress
+// in the same transaction. Ignored statements are not recorded, since
+// executing them again is harmless.
+func (r *importDumpResumer) executeStatement(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	sd *sessiondata.SessionData,
+	stmts []dumpStatement,
+	i int,
+) error {
+	s := &stmts[i]
+	var reason string
+	switch {
+	case s.dropped:
+		return nil
+	case s.parseErr != nil:
+		reason = s.parseErr.Error()
+	default:
+		switch classifyDumpStatement(s.ast) {
+		case dumpStatementIgnore:
+			return nil
+		case dumpStatementSkip:
+			reason = "unsupported statement"
+		case dumpStatementExecute:
+			err := execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+				if _, err := txn.Exec(ctx, "import-dump-stmt", txn.KV(), s.execSQL()); err != nil {
+					return err
+				}
+				return r.updateProgress(ctx, r.job.WithTxn(txn), func(prog *jobspb.ImportDumpProgress) {
+					prog.NextStatement = int32(i + 1)
+					if drop := dropDumpObject(s.ast); drop != nil {
+						prog.DropStatements = append(prog.DropStatements,
+							tree.AsStringWithFlags(drop, tree.FmtParsable))
+					}
+				})
+			}, isql.WithSessionData(sd))
+			if err == nil {
+				return nil
+			}
+			if !isUnsupportedDumpStatementError(err) {
+				return errors.Wrapf(err, "executing %q", s.text)
+			}
+			reason = err.Error()
+		}
+	}
+	return r.skip(ctx, execCfg, i, s.text, reason, func(prog *jobspb.ImportDumpProgress) {
+		prog.NextStatement = int32(i + 1)
+	})
+}
+
+// skip records that the statement of the dump with index i was skipped, and
+// updates the job's progress with fn in the same transaction.
+func (r *importDumpResumer) skip(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	i int,
+	stmt string,
+	reason string,
+	fn func(prog *jobspb.ImportDumpProgress),
+) error {
+	entry := fmt.Sprintf("-- %s\n%s\n\n", strings.ReplaceAll(reason, "\n", "\n-- "), stmt)
+	return execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		key := fmt.Sprintf("%s%010d", importDumpSkippedInfoKeyPrefix, i)
+		if err := jobs.InfoStorageForJob(txn, r.job.ID()).Write(ctx, key, []byte(entry)); err != nil {
+			return err
+		}
+		return r.updateProgress(ctx, r.job.WithTxn(txn), func(prog *jobspb.ImportDumpProgress) {
+			prog.NumSkipped++
+			fn(prog)
+		})
+	})
+}
+
+// importData imports the data of the tables loaded by the statements of the
+// dump with indexes [start, end), with an IMPORT INTO job per table. At most
+// dumpDataConcurrency jobs run at a time. Jobs which were started by a
+// previous run of the import are waited for rather than started again.
+func (r *importDumpResumer) importData(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	sd *sessiondata.SessionData,
+	tableOption string,
+	decompress string,
+	stmts []dumpStatement,
+	start, end int,
+) error {
+	for batch := start; batch < end; batch += dumpDataConcurrency {
+		if err := execCfg.JobRegistry.CheckPausepoint("import_dump.before_data"); err != nil {
+			return err
+		}
+		batchEnd := min(batch+dumpDataConcurrency, end)
+		for i := batch; i < batchEnd; i++ {
+			if _, ok := r.dataTable(i); ok {
+				continue
+			}
+			if err := r.startImportData(ctx, execCfg, sd, tableOption, decompress, stmts, i); err != nil {
+				return err
+			}
+		}
+		// A failed job does not cancel the others; they are canceled by
+		// OnFailOrCancel.
+		for i := batch; i < batchEnd; i++ {
+			t, _ := r.dataTable(i)
+			if t.Done {
+				continue
+			}
+			if err := execCfg.JobRegistry.WaitForJobs(ctx, []jobspb.JobID{t.JobID}); err != nil {
+				return errors.Wrapf(err, "importing data of %s", &stmts[i].data.table)
+			}
+			if err := r.recordImportData(ctx, execCfg, i, t.JobID); err != nil {
+				return err
+			}
+		}
+	}
+	return r.updateProgress(ctx, r.job.NoTxn(), func(prog *jobspb.ImportDumpProgress) {
+		prog.NextStatement = int32(end)
+	})
+}
+
+// dataTable returns the progress of the import of the data loaded by the
+// statement of the dump with index i, if it was started.
+func (r *importDumpResumer) dataTable(i int) (jobspb.ImportDumpProgress_Table, bool) {
+	for _, t := range r.job.Progress().GetImportDump().Tables {
+		if int(t.StatementIndex) == i {
+			return t, true
+		}
+	}
+	return jobspb.ImportDumpProgress_Table{}, false
+}
+
+// startImportData starts the detached IMPORT INTO job importing the data
+// loaded by the statement of the dump with index i, and records it in the
+// job's progress in the same transaction. The statement is skipped if its
+// table does not exist, which is the case if the statement creating it was
+// skipped.
+func (r *importDumpResumer) startImportData(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	sd *sessiondata.SessionData,
+	tableOption string,
+	decompress string,
+	stmts []dumpStatement,
+	i int,
+) error {
+	details := r.job.Details().(jobspb.ImportDumpDetails)
+	data := stmts[i].data
+	var cols string
+	if len(data.cols) > 0 {
+		cols = fmt.Sprintf(" (%s)", tree.AsString(&data.cols))
+	}
+	stmt := fmt.Sprintf("IMPORT INTO %s%s %s DATA ($1) WITH detached, %s = $2, %s = $3, %s = $4, %s = $5",
+		tree.AsString(&data.table), cols, details.Format,
+		tableOption, optMaxRowSize, importOptionDecompress, dumpOffset)
+	err := execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		row, err := txn.QueryRowEx(ctx, "import-dump-data", txn.KV(),
+			sessiondata.NoSessionDataOverride, stmt, details.URI, data.name,
+			fmt.Sprint(details.MaxRowSize), decompress, fmt.Sprint(data.offset))
+		if err != nil {
+			return err
+		}
+		if len(row) == 0 {
+			return errors.AssertionFailedf("IMPORT INTO returned no job ID")
+		}
+		jobID := jobspb.JobID(tree.MustBeDInt(row[0]))
+		return r.updateProgress(ctx, r.job.WithTxn(txn), func(prog *jobspb.ImportDumpProgress) {
+			prog.Tables = append(prog.Tables, jobspb.ImportDumpProgress_Table{
+				StatementIndex: int32(i), JobID: jobID,
+			})
+		})
+	}, isql.WithSessionData(sd))
+	if err != nil && pgerror.GetPGCode(err) == pgcode.UndefinedTable {
+		return r.skip(ctx, execCfg, i, stmts[i].text, err.Error(), func(prog *jobspb.ImportDumpProgress) {
+			prog.Tables = append(prog.Tables, jobspb.ImportDumpProgress_Table{
+				StatementIndex: int32(i), Done: true,
+			})
+		})
+	}
+	return errors.Wrapf(err, "importing data of %s", &data.table)
+}
+
+// recordImportData records the result of the completed IMPORT INTO job
+// importing the data loaded by the statement of the dump with index i.
+func (r *importDumpResumer) recordImportData(
+	ctx context.Context, execCfg *sql.ExecutorConfig, i int, jobID jobspb.JobID,
+) error {
+	job, err := execCfg.JobRegistry.LoadJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	table := job.Details().(jobspb.ImportDetails).Table
+	pkID := kvpb.BulkOpSummaryID(uint64(table.Desc.ID), uint64(table.Desc.PrimaryIndex.ID))
+	var summary kvpb.BulkOpSummary
+	if prog := job.Progress().GetImport(); prog != nil {
+		summary = prog.Summary
+	}
+	return r.updateProgress(ctx, r.job.NoTxn(), func(prog *jobspb.ImportDumpProgress) {
+		for j := range prog.Tables {
+			t := &prog.Tables[j]
+			if int(t.StatementIndex) != i {
+				continue
+			}
+			t.Done = true
+			t.DataSize = summary.DataSize
+			for id, count := range summary.EntryCounts {
+				if id == pkID {
+					t.Rows += count
+				} else {
+					t.IndexEntries += count
+				}
+			}
+		}
+	})
+}
+
+// writeSkipped writes the statements skipped by the import to the
+// log_ignored_statements destination, if one was specified.
+func (r *importDumpResumer) writeSkipped(
+	ctx context.Context, execCfg *sql.ExecutorConfig, user username.SQLUsername,
+) error {
+	details := r.job.Details().(jobspb.ImportDumpDetails)
+	numSkipped := r.job.Progress().GetImportDump().NumSkipped
+	if numSkipped == 0 {
+		return nil
+	}
+	log.Dev.Infof(ctx, "IMPORT %s skipped %d statements", details.Format, numSkipped)
+	if details.LogIgnoredStatements == "" {
+		return nil
+	}
+	var skipped bytes.Buffer
+	if err := execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		skipped.Reset()
+		return jobs.InfoStorageForJob(txn, r.job.ID()).Iterate(
+			ctx, importDumpSkippedInfoKeyPrefix, func(_ string, value []byte) error {
+				skipped.Write(value)
+				return nil
+			})
+	}); err != nil {
+		return err
+	}
+	es, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, details.LogIgnoredStatements, user)
+	if err != nil {
+		return err
+	}
+	defer es.Close()
+	if err := cloud.WriteFile(ctx, es, "", bytes.NewReader(skipped.Bytes())); err != nil {
+		return errors.Wrap(err, "writing skipped statements")
+	}
+	return nil
+}
+
+func (r *importDumpResumer) updateProgress(
+	ctx context.Context, u jobs.Updater, fn func(prog *jobspb.ImportDumpProgress),
+) error {
+	return u.Update(ctx, func(txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+		fn(md.Progress.GetImportDump())
+		ju.UpdateProgress(md.Progress)
+		return nil
+	})
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface. It cancels the
+// IMPORT INTO jobs started by the import which are still running, and drops
+// the objects the import created in the reverse order of their creation.
+func (r *importDumpResumer) OnFailOrCancel(
+	ctx context.Context, execCtx interface{}, _ error,
+) error {
+	execCfg := execCtx.(sql.JobExecContext).ExecCfg()
+	prog := r.job.Progress().GetImportDump()
+	if prog == nil {
+		return nil
+	}
+	executor := execCfg.InternalDB.Executor()
+	var running []jobspb.JobID
+	for _, t := range prog.Tables {
+		if t.JobID == 0 || t.Done {
+			continue
+		}
+		running = append(running, t.JobID)
+		if _, err := executor.ExecEx(
+			ctx, "import-dump-cancel-data", nil, /* txn */
+			sessiondata.NodeUserSessionDataOverride, "CANCEL JOB $1", t.JobID,
+		); err != nil {
+			// The IMPORT INTO job may have already finished.
+			log.Dev.Infof(ctx, "could not cancel IMPORT INTO job %d: %v", t.JobID, err)
+		}
+	}
+	if err := execCfg.JobRegistry.WaitForJobsIgnoringJobErrors(ctx, running); err != nil {
+		return err
+	}
+	ie := execCfg.InternalDB.Executor(isql.WithSessionData(r.sessionData(ctx, execCfg)))
+	for i := len(prog.DropStatements) - 1; i >= 0; i-- {
+		drop := prog.DropStatements[i]
+		if _, err := ie.Exec(ctx, "import-dump-cleanup", nil /* txn */, drop); err != nil {
+			return errors.Wrapf(err, "dropping the objects created by the import with %q", drop)
+		}
+	}
+	return r.updateProgress(ctx, r.job.NoTxn(), func(prog *jobspb.ImportDumpProgress) {
+		prog.DropStatements = nil
+	})
+}
+
+// ReportResults implements the jobs.JobResultsReporter interface. It reports
+// the results of the IMPORT INTO job of each table whose data was imported.
+func (r *importDumpResumer) ReportResults(ctx context.Context, resultsCh chan<- tree.Datums) error {
+	for _, t := range r.job.Progress().GetImportDump().Tables {
+		if t.JobID == 0 {
+			continue
+		}
+		select {
+		case resultsCh <- tree.Datums{
+			tree.NewDInt(tree.DInt(t.JobID)),
+			tree.NewDString(string(jobs.StateSucceeded)),
+			tree.NewDFloat(tree.DFloat(1.0)),
+			tree.NewDInt(tree.DInt(t.Rows)),
+			tree.NewDInt(tree.DInt(t.IndexEntries)),
+			tree.NewDInt(tree.DInt(t.DataSize)),
+			tree.DNull,
+		}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// CollectProfile is part of the jobs.Resumer interface.
+func (r *importDumpResumer) CollectProfile(_ context.Context, _ interface{}) error {
+	return nil
+}
+
+// readDumpFile reads the dump in the specified file with fn.
+func readDumpFile(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user username.SQLUsername,
+	file string,
+	compression roachpb.IOFileFormat_Compression,
+	fn func(io.Reader) error,
+) error {
+	es, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, file, user)
+	if err != nil {
+		return err
+	}
+	defer es.Close()
+	raw, _, err := es.ReadFile(ctx, "", cloud.ReadOptions{NoFileSize: true})
+	if err != nil {
+		return err
+	}
+	defer raw.Close(ctx)
+	r, err := decompressingReader(ioctx.ReaderCtxAdapter(ctx, raw), file, compression)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return fn(r)
+}
+
+// pgDumpStatements returns the statements IMPORT PGDUMP executes for the
+// items of a dump.
+func pgDumpStatements(items []pgDumpItem) []dumpStatement {
+	stmts := make([]dumpStatement, len(items))
+	for i, item := range items {
+		stmts[i].text = item.stmt
+		if cf := item.copy; cf != nil {
+			stmts[i].data = &dumpTableData{
+				table:  cf.Table,
+				name:   tree.AsString(&cf.Table),
+				cols:   cf.Columns,
+				offset: item.offset,
+			}
+			continue
+		}
+		parsed, err := parser.ParseOne(item.stmt)
+		if err != nil {
+			stmts[i].parseErr = err
+			continue
+		}
+		stmts[i].ast = parsed.AST
+	}
+	foldPgDumpPrimaryKeys(stmts)
+	return stmts
+}
+
+// dropDumpObject returns the statement which drops the object created by a
+// statement of a dump, or nil if the statement does not create an object or
+// may not have, because the object could have existed beforehand. Objects
+// are dropped in the reverse order of their creation, so types and routines,
+// which do not support CASCADE, are dropped after the objects which depend on
+// them.
+func dropDumpObject(stmt tree.Statement) tree.Statement {
+	switch n := stmt.(type) {
+	case *tree.CreateTable:
+		if !n.IfNotExists {
+			return &tree.DropTable{Names: tree.TableNames{n.Table}, IfExists: true, DropBehavior: tree.DropCascade}
+		}
+	case *tree.CreateSequence:
+		if !n.IfNotExists {
+			return &tree.DropSequence{Names: tree.TableNames{n.Name}, IfExists: true, DropBehavior: tree.DropCascade}
+		}
+	case *tree.CreateView:
+		if !n.IfNotExists && !n.Replace {
+			return &tree.DropView{
+				Names: tree.TableNames{n.Name}, IfExists: true, DropBehavior: tree.DropCascade,
+				IsMaterialized: n.Materialized,
+			}
+		}
+	case *tree.CreateType:
+		if !n.IfNotExists {
+			return &tree.DropType{
+				Names: []*tree.UnresolvedObjectName{n.TypeName}, IfExists: true,
+			}
+		}
+	case *tree.CreateSchema:
+		if !n.IfNotExists {
+			return &tree.DropSchema{
+				Names: tree.ObjectNamePrefixList{n.Schema}, IfExists: true, DropBehavior: tree.DropCascade,
+			}
+		}
+	case *tree.CreateRoutine:
+		if !n.Replace {
+			params := make(tree.RoutineParams, len(n.Params))
+			for i, param := range n.Params {
+				params[i] = param
+				params[i].DefaultVal = nil
+			}
+			return &tree.DropRoutine{
+				IfExists: true, Procedure: n.IsProcedure,
+				Routines: tree.RoutineObjs{{FuncName: n.Name, Params: params}},
+			}
+		}
+	}
+	return nil
+}
+
+// foldPgDumpPrimaryKeys moves the primary keys, which pg_dump adds with ALTER
+// TABLE once the data of the tables is restored, into the CREATE TABLE
+// statements of their tables. Otherwise the tables would be created with a
+// hidden rowid primary key, which would have to be replaced once the data is
+// imported.
+func foldPgDumpPrimaryKeys(stmts []dumpStatement) {
+	createTables := make(map[string]*dumpStatement)
+	for i := range stmts {
+		s := &stmts[i]
+		switch n := s.ast.(type) {
+		case *tree.CreateTable:
+			createTables[tree.AsString(&n.Table)] = s
+		case *tree.AlterTable:
+			if len(n.Cmds) != 1 {
+				continue
+			}
+			add, ok := n.Cmds[0].(*tree.AlterTableAddConstraint)
+			if !ok {
+				continue
+			}
+			pk, ok := add.ConstraintDef.(*tree.UniqueConstraintTableDef)
+			if !ok || !pk.PrimaryKey {
+				continue
+			}
+			tn := n.Table.ToTableName()
+			create, ok := createTables[tree.AsString(&tn)]
+			if !ok {
+				continue
+			}
+			ct := create.ast.(*tree.CreateTable)
+			ct.Defs = append(ct.Defs, pk)
+			create.modified = true
+			s.dropped = true
+		}
+	}
+}
+
+type dumpStatementAction int
+
+const (
+	// dumpStatementExecute is the action for statements which are executed.
+	dumpStatementExecute dumpStatementAction = iota
+	// dumpStatementIgnore is the action for statements which configure the
+	// session restoring the dump, and are ignored.
+	dumpStatementIgnore
+	// dumpStatementSkip is the action for unsupported statements, which are
+	// skipped and logged.
+	dumpStatementSkip
+)
+
+// classifyDumpStatement returns the action to take for a statement of a
+// dump. Statements which do not create or alter schema objects, such as
+// those changing ownership or privileges or creating extensions, are
+// skipped.
+func classifyDumpStatement(stmt tree.Statement) dumpStatementAction {
+	switch n := stmt.(type) {
+	case *tree.SetVar:
+		return dumpStatementIgnore
+	case *tree.Select:
+		switch pgDumpFunctionCall(n) {
+		case "set_config":
+			return dumpStatementIgnore
+		case "setval":
+			return dumpStatementExecute
+		}
+		return dumpStatementSkip
+	case *tree.CreateSchema, *tree.CreateType, *tree.CreateSequence, *tree.CreateTable,
+		*tree.CreateIndex, *tree.CreateView, *tree.CreateRoutine,
+		*tree.AlterTable, *tree.AlterSequence,
+		*tree.CommentOnSchema, *tree.CommentOnType, *tree.CommentOnTable, *tree.CommentOnColumn,
+		*tree.CommentOnIndex, *tree.CommentOnConstraint:
+		return dumpStatementExecute
+	default:
+		return dumpStatementSkip
+	}
+}
+
+// pgDumpFunctionCall returns the name of the function called by a statement of
+// the form SELECT pg_catalog.<function>(...), or the empty string.
+func pgDumpFunctionCall(sel *tree.Select) string {
+	clause, ok := sel.Select.(*tree.SelectClause)
+	if !ok || len(clause.Exprs) != 1 || len(clause.From.Tables) != 0 {
+		return ""
+	}
+	fn, ok := clause.Exprs[0].Expr.(*tree.FuncExpr)
+	if !ok {
+		return ""
+	}
+	return strings.TrimPrefix(tree.AsString(&fn.Func), "pg_catalog.")
+}
+
+// isUnsupportedDumpStatementError returns whether an error executing a
+// statement of a dump indicates that the statement, or an object it depends
+// on, is not supported.
+func isUnsupportedDumpStatementError(err error) bool {
+	switch pgerror.GetPGCode(err) {
+	case pgcode.FeatureNotSupported, pgcode.Syntax, pgcode.UndefinedTable,
+		pgcode.UndefinedObject, pgcode.UndefinedFunction:
+		return true
+	}
+	return false
+}
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeImportDump,
+		func(job *jobs.Job, _ *cluster.Settings) jobs.Resumer {
+			return &importDumpResumer{job: job}
+		},
+		jobs.UsesTenantCostControl,
+	)
+}
@@ -625,3 +625,101 @@ func TestImportIntoRowCountCheckAfterIngestRetry(t *testing.T) {
 		})
 	}
 }
+
+// TestImportIntoUpsert verifies that IMPORT INTO with mode = 'upsert'
+// overwrites existing rows, deletes the index entries and column families
+// of the overwritten rows that are no longer valid, and rolls back the rows it
+// overwrote if it fails.
+func TestImportIntoUpsert(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	dir, dirCleanupFn := testutils.TempDir(t)
+	defer dirCleanupFn()
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		ExternalIODir: dir,
+	})
+	defer srv.Stopper().Stop(ctx)
+
+	runner := sqlutils.MakeSQLRunner(db)
+	runner.Exec(t, `
+CREATE TABLE t (
+  k INT PRIMARY KEY,
+  v STRING,
+  w INT,
+  INDEX v_idx (v),
+  FAMILY f1 (k, v),
+  FAMILY f2 (w)
+);
+INSERT INTO t VALUES (1, 'a', 10), (2, 'b', 20), (3, 'c', 30);
+`)
+	runner.Exec(t, `EXPORT INTO CSV 'nodelocal://1/upsert/' FROM VALUES (2, 'B', NULL), (3, 'c', 31), (4, 'd', 40)`)
+
+	// The table is offline while the import is running.
+	var knobInvoked bool
+	registry := srv.ApplicationLayer().JobRegistry().(*jobs.Registry)
+	registry.TestingWrapResumerConstructor(
+		jobspb.TypeImport,
+		func(resumer jobs.Resumer) jobs.Resumer {
+			resumer.(interface {
+				TestingSetBeforeInitialRowCountKnob(fn func() error)
+			}).TestingSetBeforeInitialRowCountKnob(func() error {
+				if !knobInvoked {
+					knobInvoked = true
+					runner.ExpectErr(t, `relation "t" is offline`, `INSERT INTO t VALUES (5, 'e', 50)`)
+				}
+				return nil
+			})
+			return resumer
+		})
+
+	runner.Exec(t, `IMPORT INTO t CSV DATA ('nodelocal://1/upsert/export*-n*.0.csv') WITH mode = 'upsert', nullif = ''`)
+	require.True(t, knobInvoked, "expected knob to be invoked")
+
+	expected := [][]string{
+		{"1", "a", "10"},
+		{"2", "B", "NULL"},
+		{"3", "c", "31"},
+		{"4", "d", "40"},
+	}
+	runner.CheckQueryResults(t, `SELECT k, v, w FROM t ORDER BY k`, expected)
+	// The entry of the overwritten value 'b' is deleted from the secondary
+	// index.
+	runner.CheckQueryResults(t, `SELECT k, v FROM t@v_idx ORDER BY k`, [][]string{
+		{"1", "a"}, {"2", "B"}, {"3", "c"}, {"4", "d"},
+	})
+
+	t.Run("invalid-mode", func(t *testing.T) {
+		runner.ExpectErr(t, `invalid value "merge" for option "mode"`,
+			`IMPORT INTO t CSV DATA ('nodelocal://1/upsert/export*-n*.0.csv') WITH mode = 'merge'`)
+	})
+
+	t.Run("duplicate-key", func(t *testing.T) {
+		runner.Exec(t, `EXPORT INTO CSV 'nodelocal://1/upsert-dup/' FROM VALUES (2, 'x', 1), (6, 'f', 60), (6, 'g', 61)`)
+		runner.ExpectErr(t, `duplicate primary key /Table/\d+/1/6/0 in the input of upsert import`,
+			`IMPORT INTO t CSV DATA ('nodelocal://1/upsert-dup/export*-n*.0.csv') WITH mode = 'upsert'`)
+		// The row overwritten before the failure is rolled back.
+		runner.CheckQueryResults(t, `SELECT k, v, w FROM t ORDER BY k`, expected)
+		runner.CheckQueryResults(t, `SELECT k, v FROM t@v_idx ORDER BY k`, [][]string{
+			{"1", "a"}, {"2", "B"}, {"3", "c"}, {"4", "d"},
+		})
+	})
+
+	t.Run("duplicate-key-across-processors", func(t *testing.T) {
+		runner.Exec(t, `SET CLUSTER SETTING bulkio.import.processors_per_node = 2`)
+		defer runner.Exec(t, `RESET CLUSTER SETTING bulkio.import.processors_per_node`)
+		runner.Exec(t, `EXPORT INTO CSV 'nodelocal://1/upsert-dup-a/' FROM VALUES (3, 'x', 1), (7, 'h', 70)`)
+		runner.Exec(t, `EXPORT INTO CSV 'nodelocal://1/upsert-dup-b/' FROM VALUES (7, 'i', 71)`)
+		runner.ExpectErr(t, `duplicate primary key /Table/\d+/1/7\S* in the input of upsert import`,
+			`IMPORT INTO t CSV DATA ('nodelocal://1/upsert-dup-a/export*-n*.0.csv', 'nodelocal://1/upsert-dup-b/export*-n*.0.csv') WITH mode = 'upsert'`)
+		runner.CheckQueryResults(t, `SELECT k, v, w FROM t ORDER BY k`, expected)
+	})
+
+	t.Run("unique-index", func(t *testing.T) {
+		runner.Exec(t, `CREATE TABLE u (k INT PRIMARY KEY, v STRING UNIQUE, w INT)`)
+		runner.ExpectErr(t, `not supported for table "u" with unique index`,
+			`IMPORT INTO u CSV DATA ('nodelocal://1/upsert/export*-n*.0.csv') WITH mode = 'upsert'`)
+	})
+}
@@ -19,6 +19,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts/ptpb"
+	"github.com/cockroachdb/cockroach/pkg/revert"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
@@ -259,7 +261,9 @@ func (r *importResumer) Resume(ctx context.Context, execCtx interface{}) error {
 		}
 	}
 
-	if details.Table.InitialRowCount == 0 && details.Walltime == 0 {
+	// An upsert import overwrites existing rows, so the initial row count is
+	// neither needed for its rollback nor for validating its result.
+	if !details.Upsert && details.Table.InitialRowCount == 0 && details.Walltime == 0 {
 		// Check if the table being imported into is starting empty, in which case
 		// we can cheaply clear-range instead of DeleteRange to cleanup.
 		//
@@ -342,6 +346,18 @@ func (r *importResumer) Resume(ctx context.Context, execCtx interface{}) error {
 		}
 	}
 
+	// An upsert import shadows the rows it overwrites, so the revisions they
+	// had when the import started must not be garbage collected before the
+	// import either succeeds or is rolled back.
+	if details.Upsert {
+		target := ptpb.MakeSchemaObjectsTarget(descpb.IDs{table.Desc.ID})
+		if _, err := p.ExecCfg().ProtectedTimestampManager.Protect(
+			ctx, r.job, target, hlc.Timestamp{WallTime: details.Walltime},
+		); err != nil {
+			return errors.Wrap(err, "protecting rows overwritten by upsert import")
+		}
+	}
+
 	procsPerNode := int(processorsPerNode.Get(&p.ExecCfg().Settings.SV))
 	initialSplitsPerProc := int(initialSplitsPerProcessor.Get(&p.ExecCfg().Settings.SV))
 
@@ -395,11 +411,28 @@ func (r *importResumer) Resume(ctx context.Context, execCtx interface{}) error {
 		return err
 	}
 
+	// Processors of an upsert import that held the same primary key at the
+	// same time cannot detect it while ingesting, so check for it before the
+	// table is published.
+	if details.Upsert {
+		if err := checkUpsertDuplicates(
+			ctx, p.ExecCfg().DB, p.ExecCfg().Codec, table.Desc, hlc.Timestamp{WallTime: details.Walltime},
+		); err != nil {
+			return err
+		}
+	}
+
 	setPublicTimestamp, err := r.publishTable(ctx, p.ExecCfg(), res)
 	if err != nil {
 		return err
 	}
 
+	if details.Upsert {
+		if err := p.ExecCfg().ProtectedTimestampManager.Unprotect(ctx, r.job); err != nil {
+			log.Dev.Warningf(ctx, "failed to release protected timestamp of import job %d: %v", r.job.ID(), err)
+		}
+	}
+
 	validationMode := importRowCountValidation.Get(&p.ExecCfg().Settings.SV)
 	switch validationMode {
 	case ImportRowCountValidationOff:
@@ -423,6 +456,13 @@ func (r *importResumer) Resume(ctx context.Context, execCtx interface{}) error {
 			}
 			tableName = tblDesc.GetName()
 
+			if details.Upsert {
+				// The number of rows overwritten by an upsert import is unknown, so
+				// the row count of the table cannot be validated.
+				checks, err = inspect.ChecksForTable(ctx, p.ExecCfg(), tblDesc, nil /* expectedRowCount */)
+				return err
+			}
+
 			if creationVersion := r.job.Payload().CreationClusterVersion; !details.Table.WasEmpty && creationVersion.Less(clusterversion.V26_2.Version()) {
 				log.Eventf(ctx, "skipping row count on table %q: the table was not empty and the job was started in an unsupported version", tableName)
 
@@ -588,9 +628,14 @@ func (r *importResumer) prepareTableForIngestion(
 	// Take the table offline for import.
 	// TODO(dt): audit everywhere we get table descs (leases or otherwise) to
 	// ensure that filtering by state handles IMPORTING correctly.
-
-	// Use OfflineForImport which bumps the ImportEpoch.
-	importing.OfflineForImport()
+	if details.Upsert {
+		// An upsert import overwrites existing rows, so it is rolled back by
+		// reverting the table rather than by its ImportEpoch.
+		importing.OfflineForUpsertImport()
+	} else {
+		// Use OfflineForImport which bumps the ImportEpoch.
+		importing.OfflineForImport()
+	}
 
 	// TODO(dt): de-validate all the FKs.
 	if err := descsCol.WriteDesc(
@@ -1002,6 +1047,11 @@ func (r *importResumer) OnFailOrCancel(
 		// If the table was published, there is nothing for us to clean up, the
 		// descriptor is already online.
 		log.Dev.Warningf(ctx, "import job %d failed or canceled after publishing the table, no revert necessary", r.job.ID())
+		if details.Upsert {
+			if err := p.ExecCfg().ProtectedTimestampManager.Unprotect(ctx, r.job); err != nil {
+				log.Dev.Warningf(ctx, "failed to release protected timestamp of import job %d: %v", r.job.ID(), err)
+			}
+		}
 		return nil
 	}
 	if !details.PrepareComplete {
@@ -1047,6 +1097,11 @@ func (r *importResumer) OnFailOrCancel(
 		// as OFFLINE and then choosing a ingestion timestamp. This might happen
 		// while waiting for the descriptor version to propagate across the cluster
 		// for example.
+	case details.Upsert:
+		err := revertUpsertTable(ctx, p, tbl.Desc.ID, details.Walltime)
+		if err != nil {
+			return errors.Wrap(err, "rolling back upsert import")
+		}
 	default:
 		err := revertTable(ctx, cfg, tbl.Desc.ID, details.Walltime)
 		if err != nil {
@@ -1059,6 +1114,12 @@ func (r *importResumer) OnFailOrCancel(
 		return errors.Wrap(err, "bringing table back online after import revert")
 	}
 
+	if details.Upsert {
+		if err := cfg.ProtectedTimestampManager.Unprotect(ctx, r.job); err != nil {
+			log.Dev.Warningf(ctx, "failed to release protected timestamp of import job %d: %v", r.job.ID(), err)
+		}
+	}
+
 	besteffort.Warning(ctx, "import-event", func(ctx context.Context) error {
 		return emitImportJobEvent(ctx, p, jobs.StateFailed, r.job)
 	})
@@ -1179,6 +1240,19 @@ func revertTable(
 	return err
 }
 
+// revertUpsertTable reverts the table of an upsert import to its state when
+// the import started, restoring the rows the import overwrote.
+func revertUpsertTable(
+	ctx context.Context, p sql.JobExecContext, id catid.DescID, writeTime int64,
+) error {
+	execCfg := p.ExecCfg()
+	tableSpan := execCfg.Codec.TableSpan(uint32(id))
+	return revert.RevertSpansFanout(
+		ctx, execCfg.DB, p, []roachpb.Span{tableSpan}, hlc.Timestamp{WallTime: writeTime},
+		false /* ignoreGCThreshold */, sql.RevertTableDefaultBatchSize, nil, /* onCompletedCallback */
+	)
+}
+
 func (r *importResumer) markOnline(
 	ctx context.Context, cfg *sql.ExecutorConfig, id catid.DescID,
 ) error {
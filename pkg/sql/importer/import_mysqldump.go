@@ -0,0 +1,696 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package importer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// mysqlTokens is a cursor over the tokens of a statement.
+type mysqlTokens struct {
+	toks []mysqlToken
+	i    int
+}
+
+// peek returns the next token, which is mysqlTokenEOF past the end.
+func (t *mysqlTokens) peek() mysqlToken {
+	if t.i >= len(t.toks) {
+		return mysqlToken{kind: mysqlTokenEOF}
+	}
+	return t.toks[t.i]
+}
+
+func (t *mysqlTokens) next() mysqlToken {
+	tok := t.peek()
+	if t.i < len(t.toks) {
+		t.i++
+	}
+	return tok
+}
+
+func (t *mysqlTokens) done() bool {
+	return t.i >= len(t.toks)
+}
+
+// acceptWord consumes the next tokens if they are the specified keywords.
+func (t *mysqlTokens) acceptWord(words ...string) bool {
+	for i, w := range words {
+		if t.i+i >= len(t.toks) || !t.toks[t.i+i].isWord(w) {
+			return false
+		}
+	}
+	t.i += len(words)
+	return true
+}
+
+// acceptPunct consumes the next token if it is the specified character.
+func (t *mysqlTokens) acceptPunct(c string) bool {
+	if !t.peek().isPunct(c) {
+		return false
+	}
+	t.i++
+	return true
+}
+
+// ident consumes an identifier.
+func (t *mysqlTokens) ident() (string, error) {
+	tok := t.next()
+	if tok.kind != mysqlTokenIdent && tok.kind != mysqlTokenWord {
+		return "", errors.Errorf("expected an identifier, found %q", tok.val)
+	}
+	return tok.val, nil
+}
+
+// qualifiedName consumes the name of an object, optionally qualified by its
+// database, and returns the name without the database.
+func (t *mysqlTokens) qualifiedName() (string, error) {
+	name, err := t.ident()
+	if err != nil {
+		return "", err
+	}
+	if t.acceptPunct(".") {
+		return t.ident()
+	}
+	return name, nil
+}
+
+// group consumes a parenthesized group of tokens and returns the tokens
+// within the parentheses.
+func (t *mysqlTokens) group() ([]mysqlToken, error) {
+	if !t.acceptPunct("(") {
+		return nil, errors.Errorf("expected \"(\", found %q", t.peek().val)
+	}
+	start := t.i
+	for depth := 1; ; {
+		tok := t.next()
+		switch {
+		case tok.kind == mysqlTokenEOF:
+			return nil, errors.New("unbalanced parentheses")
+		case tok.isPunct("("):
+			depth++
+		case tok.isPunct(")"):
+			if depth--; depth == 0 {
+				return t.toks[start : t.i-1], nil
+			}
+		}
+	}
+}
+
+// splitMysqlList splits tokens on the commas which are not within
+// parentheses.
+func splitMysqlList(toks []mysqlToken) [][]mysqlToken {
+	var res [][]mysqlToken
+	var depth, start int
+	for i, tok := range toks {
+		switch {
+		case tok.isPunct("("):
+			depth++
+		case tok.isPunct(")"):
+			depth--
+		case tok.isPunct(",") && depth == 0:
+			res = append(res, toks[start:i])
+			start = i + 1
+		}
+	}
+	return append(res, toks[start:])
+}
+
+// mysqlExprSQL translates the tokens of a MySQL expression into SQL. The
+// tokens are kept as they are, except for quoting, so the expression only
+// translates if it is also valid in CockroachDB.
+func mysqlExprSQL(toks []mysqlToken) string {
+	var buf strings.Builder
+	for i, tok := range toks {
+		if i > 0 && tok.space {
+			buf.WriteByte(' ')
+		}
+		switch tok.kind {
+		case mysqlTokenIdent:
+			buf.WriteString(tree.NameString(tok.val))
+		case mysqlTokenString:
+			buf.WriteString(lexbase.EscapeSQLString(tok.val))
+		case mysqlTokenHex:
+			fmt.Fprintf(&buf, "x'%x'", tok.val)
+		case mysqlTokenBit:
+			fmt.Fprintf(&buf, "B'%s'", tok.val)
+		default:
+			buf.WriteString(tok.val)
+		}
+	}
+	return buf.String()
+}
+
+// mysqlTypeSQL translates a MySQL column type into a CockroachDB one. args are
+// the tokens of the type's arguments, if any.
+func mysqlTypeSQL(name string, args []mysqlToken, unsigned bool) (string, error) {
+	withArgs := func(typ string) string {
+		if len(args) == 0 {
+			return typ
+		}
+		return fmt.Sprintf("%s(%s)", typ, mysqlExprSQL(args))
+	}
+	switch strings.ToLower(name) {
+	case "bool", "boolean":
+		return "BOOL", nil
+	case "tinyint":
+		return "INT2", nil
+	case "smallint":
+		if unsigned {
+			return "INT4", nil
+		}
+		return "INT2", nil
+	case "mediumint":
+		return "INT4", nil
+	case "int", "integer":
+		if unsigned {
+			return "INT8", nil
+		}
+		return "INT4", nil
+	case "bigint":
+		if unsigned {
+			return "DECIMAL(20)", nil
+		}
+		return "INT8", nil
+	case "serial":
+		return "DECIMAL(20)", nil
+	case "float":
+		return "FLOAT4", nil
+	case "double", "real":
+		return "FLOAT8", nil
+	case "decimal", "numeric", "dec", "fixed":
+		return withArgs("DECIMAL"), nil
+	case "char":
+		return withArgs("CHAR"), nil
+	case "varchar":
+		return withArgs("VARCHAR"), nil
+	case "tinytext", "text", "mediumtext", "longtext", "enum", "set":
+		return "STRING", nil
+	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob":
+		return "BYTES", nil
+	case "date":
+		return "DATE", nil
+	case "time":
+		return withArgs("TIME"), nil
+	case "datetime":
+		return withArgs("TIMESTAMP"), nil
+	case "timestamp":
+		return withArgs("TIMESTAMPTZ"), nil
+	case "year":
+		return "INT2", nil
+	case "bit":
+		return withArgs("VARBIT"), nil
+	case "json":
+		return "JSONB", nil
+	}
+	return "", mysqlUnsupported(fmt.Sprintf("column type %s", name))
+}
+
+// mysqlUnsupported returns the error of a construct of a dump which cannot be
+// translated, for which the statement is skipped.
+func mysqlUnsupported(what string) error {
+	return pgerror.Newf(pgcode.FeatureNotSupported, "%s is not supported", what)
+}
+
+// mysqlCreateTable is the translation of a CREATE TABLE statement.
+type mysqlCreateTable struct {
+	// create holds the statements which create the table.
+	create []string
+	// foreignKeys holds the statements which add the foreign keys of the
+	// table, which are executed once the data of all tables is imported.
+	foreignKeys []string
+	// skipped holds the parts of the statement which were not translated,
+	// along with the reason.
+	skipped [][2]string
+}
+
+// translateMysqlCreateTable translates a CREATE TABLE statement of a dump.
+func translateMysqlCreateTable(t *mysqlTokens) (mysqlCreateTable, error) {
+	var res mysqlCreateTable
+	if !t.acceptWord("CREATE", "TABLE") {
+		return res, errors.AssertionFailedf("expected CREATE TABLE statement")
+	}
+	ifNotExists := t.acceptWord("IF", "NOT", "EXISTS")
+	name, err := t.qualifiedName()
+	if err != nil {
+		return res, err
+	}
+	table := tree.NameString(name)
+	if t.peek().isWord("LIKE") || !t.peek().isPunct("(") {
+		return res, mysqlUnsupported("CREATE TABLE without a column list")
+	}
+	elems, err := t.group()
+	if err != nil {
+		return res, err
+	}
+
+	var defs []string
+	// autoIncrement is the column whose values are generated by a sequence.
+	var autoIncrement string
+	var comments []string
+	for _, elem := range splitMysqlList(elems) {
+		e := &mysqlTokens{toks: elem}
+		var constraint string
+		if e.acceptWord("CONSTRAINT") {
+			if tok := e.peek(); tok.kind == mysqlTokenIdent ||
+				(tok.kind == mysqlTokenWord && !tok.isWord("PRIMARY") && !tok.isWord("UNIQUE") &&
+					!tok.isWord("FOREIGN") && !tok.isWord("CHECK")) {
+				e.next()
+				constraint = "CONSTRAINT " + tree.NameString(tok.val) + " "
+			}
+		}
+		switch {
+		case e.acceptWord("PRIMARY", "KEY"):
+			cols, err := mysqlIndexColumns(e)
+			if err != nil {
+				return res, err
+			}
+			defs = append(defs, fmt.Sprintf("%sPRIMARY KEY (%s)", constraint, cols))
+		case e.peek().isWord("UNIQUE") || e.peek().isWord("KEY") || e.peek().isWord("INDEX"):
+			unique := e.acceptWord("UNIQUE")
+			if !e.acceptWord("KEY") {
+				e.acceptWord("INDEX")
+			}
+			var index string
+			if !e.peek().isPunct("(") && !e.peek().isWord("USING") {
+				if index, err = e.ident(); err != nil {
+					return res, err
+				}
+			}
+			cols, err := mysqlIndexColumns(e)
+			if err != nil {
+				return res, err
+			}
+			def := "INDEX "
+			if unique {
+				def = "UNIQUE INDEX "
+			}
+			if index != "" {
+				def += tree.NameString(index) + " "
+			} else if constraint != "" {
+				def = constraint + "UNIQUE "
+			}
+			defs = append(defs, fmt.Sprintf("%s(%s)", def, cols))
+		case e.peek().isWord("FULLTEXT") || e.peek().isWord("SPATIAL"):
+			res.skipped = append(res.skipped,
+				[2]string{mysqlExprSQL(elem), fmt.Sprintf("%s index is not supported", e.peek().val)})
+		case e.acceptWord("FOREIGN", "KEY"):
+			if !e.peek().isPunct("(") {
+				if _, err := e.ident(); err != nil {
+					return res, err
+				}
+			}
+			cols, err := e.group()
+			if err != nil {
+				return res, err
+			}
+			res.foreignKeys = append(res.foreignKeys, fmt.Sprintf(
+				"ALTER TABLE %s ADD %sFOREIGN KEY (%s) %s",
+				table, constraint, mysqlExprSQL(cols), mysqlExprSQL(e.toks[e.i:])))
+		case e.peek().isWord("CHECK"):
+			check := e.toks[e.i:]
+			if n := len(check); n > 2 && check[n-1].isWord("ENFORCED") {
+				if check[n-2].isWord("NOT") {
+					// MySQL does not enforce the constraint.
+					continue
+				}
+				check = check[:n-1]
+			}
+			defs = append(defs, constraint+mysqlExprSQL(check))
+		default:
+			col, err := e.ident()
+			if err != nil {
+				return res, err
+			}
+			def, comment, auto, err := translateMysqlColumn(col, e)
+			if err != nil {
+				return res, errors.Wrapf(err, "column %s", col)
+			}
+			defs = append(defs, def)
+			if auto {
+				autoIncrement = col
+			}
+			if comment != "" {
+				comments = append(comments, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s",
+					table, tree.NameString(col), lexbase.EscapeSQLString(comment)))
+			}
+		}
+	}
+
+	// Table options, of which only the next AUTO_INCREMENT value and the
+	// comment are used. Partitioning is ignored.
+	start := "1"
+	for !t.done() {
+		switch {
+		case t.acceptWord("AUTO_INCREMENT"):
+			t.acceptPunct("=")
+			start = t.next().val
+		case t.acceptWord("COMMENT"):
+			t.acceptPunct("=")
+			if tok := t.next(); tok.kind == mysqlTokenString && tok.val != "" {
+				comments = append(comments, fmt.Sprintf("COMMENT ON TABLE %s IS %s",
+					table, lexbase.EscapeSQLString(tok.val)))
+			}
+		default:
+			t.next()
+		}
+	}
+
+	var seq string
+	if autoIncrement != "" {
+		seq = tree.NameString(name + "_" + autoIncrement + "_seq")
+		res.create = append(res.create, fmt.Sprintf("CREATE SEQUENCE %s START %s", seq, start))
+		for i := range defs {
+			defs[i] = strings.Replace(defs[i], mysqlAutoIncrementPlaceholder,
+				fmt.Sprintf("DEFAULT nextval(%s)", lexbase.EscapeSQLString(seq)), 1)
+		}
+	}
+	create := "CREATE TABLE "
+	if ifNotExists {
+		create += "IF NOT EXISTS "
+	}
+	res.create = append(res.create, fmt.Sprintf("%s%s (%s)", create, table, strings.Join(defs, ", ")))
+	if autoIncrement != "" {
+		res.create = append(res.create, fmt.Sprintf(
+			"ALTER SEQUENCE %s OWNED BY %s.%s", seq, table, tree.NameString(autoIncrement)))
+	}
+	res.create = append(res.create, comments...)
+	return res, nil
+}
+
+// mysqlAutoIncrementPlaceholder stands for the default expression of an
+// AUTO_INCREMENT column, whose sequence is only known once the options of
+// the table are read.
+const mysqlAutoIncrementPlaceholder = "<auto_increment>"
+
+// mysqlIndexColumns translates the key parts of an index, which may be
+// preceded by the index type, and drops prefix lengths, which are not
+// supported.
+func mysqlIndexColumns(t *mysqlTokens) (string, error) {
+	if t.acceptWord("USING") {
+		t.next()
+	}
+	parts, err := t.group()
+	if err != nil {
+		return "", err
+	}
+	var cols []string
+	for _, part := range splitMysqlList(parts) {
+		p := &mysqlTokens{toks: part}
+		var col string
+		if p.peek().isPunct("(") {
+			expr, err := p.group()
+			if err != nil {
+				return "", err
+			}
+			col = "(" + mysqlExprSQL(expr) + ")"
+		} else {
+			name, err := p.ident()
+			if err != nil {
+				return "", err
+			}
+			col = tree.NameString(name)
+			if p.peek().isPunct("(") {
+				if _, err := p.group(); err != nil {
+					return "", err
+				}
+			}
+		}
+		if p.acceptWord("DESC") {
+			col += " DESC"
+		}
+		cols = append(cols, col)
+	}
+	return strings.Join(cols, ", "), nil
+}
+
+// translateMysqlColumn translates the definition of a column. auto is set if
+// the column is an AUTO_INCREMENT column, whose default expression is left
+// as mysqlAutoIncrementPlaceholder.
+func translateMysqlColumn(
+	col string, t *mysqlTokens,
+) (def string, comment string, auto bool, _ error) {
+	typeName, err := t.ident()
+	if err != nil {
+		return "", "", false, err
+	}
+	var args []mysqlToken
+	if t.peek().isPunct("(") {
+		if args, err = t.group(); err != nil {
+			return "", "", false, err
+		}
+	}
+	var unsigned bool
+	for {
+		if t.acceptWord("UNSIGNED") {
+			unsigned = true
+		} else if !t.acceptWord("SIGNED") && !t.acceptWord("ZEROFILL") {
+			break
+		}
+	}
+	typ, err := mysqlTypeSQL(typeName, args, unsigned)
+	if err != nil {
+		return "", "", false, err
+	}
+	parts := []string{tree.NameString(col), typ}
+	if lower := strings.ToLower(typeName); (lower == "enum" || lower == "set") && len(args) > 0 {
+		if lower == "enum" {
+			parts = append(parts, fmt.Sprintf("CHECK (%s IN (%s))", tree.NameString(col), mysqlExprSQL(args)))
+		}
+	}
+	for !t.done() {
+		switch {
+		case t.acceptWord("NOT", "NULL"):
+			parts = append(parts, "NOT NULL")
+		case t.acceptWord("NULL"):
+			parts = append(parts, "NULL")
+		case t.acceptWord("DEFAULT"):
+			expr, err := mysqlOperand(t)
+			if err != nil {
+				return "", "", false, err
+			}
+			// MySQL's zero dates have no equivalent.
+			if len(expr) == 1 && expr[0].kind == mysqlTokenString &&
+				strings.HasPrefix(expr[0].val, "0000-00-00") {
+				continue
+			}
+			parts = append(parts, "DEFAULT "+mysqlDefaultSQL(expr))
+		case t.acceptWord("ON", "UPDATE"):
+			expr, err := mysqlOperand(t)
+			if err != nil {
+				return "", "", false, err
+			}
+			parts = append(parts, "ON UPDATE "+mysqlDefaultSQL(expr))
+		case t.acceptWord("AUTO_INCREMENT"):
+			auto = true
+			parts = append(parts, mysqlAutoIncrementPlaceholder)
+		case t.acceptWord("PRIMARY", "KEY"), t.acceptWord("KEY"):
+			parts = append(parts, "PRIMARY KEY")
+		case t.acceptWord("UNIQUE"):
+			t.acceptWord("KEY")
+			parts = append(parts, "UNIQUE")
+		case t.acceptWord("COMMENT"):
+			comment = t.next().val
+		case t.acceptWord("CHARACTER", "SET"), t.acceptWord("CHARSET"), t.acceptWord("COLLATE"),
+			t.acceptWord("COLUMN_FORMAT"), t.acceptWord("STORAGE"):
+			t.next()
+		case t.acceptWord("VISIBLE"):
+		case t.acceptWord("INVISIBLE"):
+			parts = append(parts, "NOT VISIBLE")
+		case t.acceptWord("GENERATED", "ALWAYS", "AS"), t.acceptWord("AS"):
+			expr, err := t.group()
+			if err != nil {
+				return "", "", false, err
+			}
+			stored := "VIRTUAL"
+			if t.acceptWord("STORED") {
+				stored = "STORED"
+			} else {
+				t.acceptWord("VIRTUAL")
+			}
+			parts = append(parts, fmt.Sprintf("AS (%s) %s", mysqlExprSQL(expr), stored))
+		case t.peek().isWord("CHECK"):
+			t.next()
+			expr, err := t.group()
+			if err != nil {
+				return "", "", false, err
+			}
+			if t.acceptWord("NOT", "ENFORCED") {
+				// MySQL does not enforce the constraint.
+				continue
+			}
+			t.acceptWord("ENFORCED")
+			parts = append(parts, fmt.Sprintf("CHECK (%s)", mysqlExprSQL(expr)))
+		case t.acceptWord("REFERENCES"):
+			// Inline references are ignored by MySQL.
+			for !t.done() {
+				t.next()
+			}
+		default:
+			return "", "", false, mysqlUnsupported(fmt.Sprintf("column attribute %s", t.peek().val))
+		}
+	}
+	return strings.Join(parts, " "), comment, auto, nil
+}
+
+// mysqlOperand consumes the operand of DEFAULT or ON UPDATE, which is a
+// literal, possibly signed, a function call, or a parenthesized expression.
+func mysqlOperand(t *mysqlTokens) ([]mysqlToken, error) {
+	start := t.i
+	switch tok := t.next(); {
+	case tok.isPunct("-") || tok.isPunct("+"):
+		t.next()
+	case tok.isPunct("("):
+		t.i--
+		if _, err := t.group(); err != nil {
+			return nil, err
+		}
+	case tok.kind == mysqlTokenWord && t.peek().isPunct("("):
+		if _, err := t.group(); err != nil {
+			return nil, err
+		}
+	case tok.kind == mysqlTokenEOF:
+		return nil, errors.New("expected an expression")
+	}
+	return t.toks[start:t.i], nil
+}
+
+// mysqlDefaultSQL translates the operand of DEFAULT or ON UPDATE.
+func mysqlDefaultSQL(expr []mysqlToken) string {
+	if len(expr) > 0 {
+		switch {
+		case expr[0].isWord("CURRENT_TIMESTAMP"), expr[0].isWord("NOW"),
+			expr[0].isWord("LOCALTIME"), expr[0].isWord("LOCALTIMESTAMP"):
+			return "current_timestamp()"
+		}
+	}
+	return mysqlExprSQL(expr)
+}
+
+// mysqlDumpItem is a statement of a dump written by mysqldump, translated for
+// IMPORT MYSQLDUMP.
+type mysqlDumpItem struct {
+	// stmt is the text of the statement in the dump.
+	stmt string
+	// sql is the translated statement, if any.
+	sql string
+	// skipReason is set if the statement is skipped.
+	skipReason string
+	// insert is set for the data of a table, which is imported from the
+	// table's INSERT statements.
+	insert *mysqlInsert
+	// offset is the offset of the first INSERT statement of a table. It is
+	// zero if the INSERT statements of the table are not consecutive.
+	offset int64
+}
+
+// readMysqlDumpItems reads the statements of a dump written by mysqldump and
+// translates them. The tables are created first, then their data is
+// imported, and finally their foreign keys are added, since mysqldump
+// writes the tables in alphabetical order with foreign key checks disabled.
+// Statements which configure the session restoring the dump are ignored.
+func readMysqlDumpItems(r io.Reader, maxSize int) ([]mysqlDumpItem, error) {
+	var create, data, foreignKeys []mysqlDumpItem
+	tableData := make(map[string]int)
+	var lastInsert string
+	s := newMysqlDumpStream(r, maxSize)
+	for {
+		stmt, err := s.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if stmt.insert != nil {
+			if err := s.SkipRows(); err != nil {
+				return nil, errors.Wrapf(err, "reading rows of %q", stmt.text)
+			}
+			table := stmt.insert.table
+			if i, ok := tableData[table]; !ok {
+				tableData[table] = len(data)
+				data = append(data, mysqlDumpItem{stmt: stmt.text, insert: stmt.insert, offset: stmt.offset})
+			} else if lastInsert != table {
+				data[i].offset = 0
+			}
+			lastInsert = table
+			continue
+		}
+		lastInsert = ""
+		t := &mysqlTokens{toks: stmt.tokens}
+		switch {
+		case t.peek().isWord("SET"), t.peek().isWord("LOCK"), t.peek().isWord("UNLOCK"),
+			t.peek().isWord("START"), t.peek().isWord("BEGIN"), t.peek().isWord("COMMIT"),
+			t.acceptWord("DROP", "TABLE"), t.acceptWord("DROP", "VIEW"):
+			// DROP TABLE IF EXISTS precedes the creation of each table, which the
+			// import creates afresh.
+		case t.acceptWord("ALTER", "TABLE"):
+			if _, err := t.qualifiedName(); err == nil &&
+				(t.acceptWord("DISABLE", "KEYS") || t.acceptWord("ENABLE", "KEYS")) {
+				continue
+			}
+			create = append(create, mysqlDumpItem{stmt: stmt.text, skipReason: "unsupported statement"})
+		case t.peek().isWord("CREATE") && len(stmt.tokens) > 1 && stmt.tokens[1].isWord("TABLE"):
+			res, err := translateMysqlCreateTable(t)
+			if err != nil {
+				create = append(create, mysqlDumpItem{stmt: stmt.text, skipReason: err.Error()})
+				continue
+			}
+			for _, sql := range res.create {
+				create = append(create, mysqlDumpItem{stmt: stmt.text, sql: sql})
+			}
+			for _, skipped := range res.skipped {
+				create = append(create, mysqlDumpItem{stmt: skipped[0], skipReason: skipped[1]})
+			}
+			for _, sql := range res.foreignKeys {
+				foreignKeys = append(foreignKeys, mysqlDumpItem{stmt: sql, sql: sql})
+			}
+		default:
+			create = append(create, mysqlDumpItem{stmt: stmt.text, skipReason: "unsupported statement"})
+		}
+	}
+	items := append(create, data...)
+	return append(items, foreignKeys...), nil
+}
+
+// mysqlDumpStatements returns the statements IMPORT MYSQLDUMP executes for
+// the items of a dump.
+func mysqlDumpStatements(items []mysqlDumpItem) []dumpStatement {
+	stmts := make([]dumpStatement, len(items))
+	for i, item := range items {
+		s := &stmts[i]
+		s.text = item.stmt
+		switch {
+		case item.insert != nil:
+			s.data = &dumpTableData{
+				table:  tree.MakeUnqualifiedTableName(tree.Name(item.insert.table)),
+				name:   tree.NameString(item.insert.table),
+				offset: item.offset,
+			}
+			for _, col := range item.insert.cols {
+				s.data.cols = append(s.data.cols, tree.Name(col))
+			}
+		case item.skipReason != "":
+			s.parseErr = errors.New(item.skipReason)
+		default:
+			s.sql = item.sql
+			parsed, err := parser.ParseOne(item.sql)
+			if err != nil {
+				s.parseErr = err
+				continue
+			}
+			s.ast = parsed.AST
+		}
+	}
+	return stmts
+}
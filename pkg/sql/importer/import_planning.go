@@ -34,6 +34,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
@@ -67,10 +68,32 @@ const (
 	importOptionDisableGlobMatch = "disable_glob_matching"
 	importOptionSaveRejected     = "experimental_save_rejected"
 	importOptionDetached         = "detached"
+	importOptionMode             = "mode"
+
+	importModeInsert = "insert"
+	importModeUpsert = "upsert"
 
 	pgCopyDelimiter = "delimiter"
 	pgCopyNull      = "nullif"
 
+	pgDumpTable                = "pgdump_table"
+	pgDumpLogIgnoredStatements = "log_ignored_statements"
+
+	mysqlDumpTable = "mysqldump_table"
+
+	// Offset in an uncompressed dump of the statements holding the data of
+	// the imported table. It is set by IMPORT PGDUMP and IMPORT MYSQLDUMP for
+	// the IMPORT INTO jobs they run, so that each job does not have to read
+	// the dump up to the table's data.
+	dumpOffset = "dump_offset"
+
+	// Paths of the JSON values of columns, as a comma-separated list of
+	// column=path, where a path is a dot-separated list of object keys and
+	// array indexes.
+	ndjsonColumnPaths = "column_paths"
+	// JSONB column populated with the keys which are not mapped to columns.
+	ndjsonCatchAllColumn = "catch_all_column"
+
 	optMaxRowSize = "max_row_size"
 
 	// Turn on strict validation when importing avro or parquet records.
@@ -107,9 +130,18 @@ var importOptionExpectValues = map[string]exprutil.KVStringOptValidate{
 
 	importOptionDisableGlobMatch: exprutil.KVStringOptRequireNoValue,
 	importOptionDetached:         exprutil.KVStringOptRequireNoValue,
+	importOptionMode:             exprutil.KVStringOptRequireValue,
 
 	optMaxRowSize: exprutil.KVStringOptRequireValue,
 
+	pgDumpTable:                exprutil.KVStringOptRequireValue,
+	pgDumpLogIgnoredStatements: exprutil.KVStringOptRequireValue,
+	mysqlDumpTable:             exprutil.KVStringOptRequireValue,
+	dumpOffset:                 exprutil.KVStringOptRequireValue,
+
+	ndjsonColumnPaths:    exprutil.KVStringOptRequireValue,
+	ndjsonCatchAllColumn: exprutil.KVStringOptRequireValue,
+
 	optStrictValidation:    exprutil.KVStringOptRequireNoValue,
 	avroSchema:             exprutil.KVStringOptRequireValue,
 	avroSchemaURI:          exprutil.KVStringOptRequireValue,
@@ -129,6 +161,7 @@ func makeStringSet(opts ...string) map[string]struct{} {
 // Options common to all formats.
 var allowedCommonOptions = makeStringSet(
 	importOptionDecompress, importOptionSaveRejected, importOptionDisableGlobMatch, importOptionDetached,
+	importOptionMode,
 )
 
 // Format specific allowed options.
@@ -148,19 +181,45 @@ var mysqlOutAllowedOptions = makeStringSet(
 
 var pgCopyAllowedOptions = makeStringSet(pgCopyDelimiter, pgCopyNull, optMaxRowSize)
 
+var pgDumpAllowedOptions = makeStringSet(pgDumpTable, optMaxRowSize, dumpOffset)
+
+var mysqlDumpAllowedOptions = makeStringSet(mysqlDumpTable, optMaxRowSize, dumpOffset)
+
+var dumpBundleAllowedOptions = makeStringSet(pgDumpLogIgnoredStatements, optMaxRowSize)
+
+// Common options which the dump formats do not support: the dump readers do
+// not save rejected rows, the single dump file is never a glob pattern, and
+// the data is always inserted into a table taken offline.
+var dumpUnsupportedCommonOptions = []string{
+	importOptionSaveRejected, importOptionDisableGlobMatch, importOptionMode,
+}
+
 var parquetAllowedOptions = makeStringSet(optStrictValidation)
 
+var ndjsonAllowedOptions = makeStringSet(
+	ndjsonColumnPaths, ndjsonCatchAllColumn, optStrictValidation, optMaxRowSize, csvRowLimit,
+)
+
 // DROP is required because the target table needs to be take offline during
 // IMPORT INTO.
 var importIntoRequiredPrivileges = []privilege.Kind{privilege.INSERT, privilege.DROP}
 
+// An IMPORT INTO with mode = 'upsert' also overwrites existing rows, so it
+// additionally requires the privileges of an UPSERT statement.
+var importUpsertRequiredPrivileges = []privilege.Kind{
+	privilege.SELECT, privilege.INSERT, privilege.UPDATE, privilege.DROP,
+}
+
 // File formats supported for IMPORT INTO
 var allowedIntoFormats = map[string]struct{}{
 	"CSV":       {},
 	"AVRO":      {},
 	"DELIMITED": {},
 	"PGCOPY":    {},
+	"PGDUMP":    {},
+	"MYSQLDUMP": {},
 	"PARQUET":   {},
+	"NDJSON":    {},
 }
 
 // featureImportEnabled is used to enable and disable the IMPORT feature.
@@ -185,6 +244,19 @@ func validateFormatOptions(
 	return nil
 }
 
+// validateDumpFormatOptions is like validateFormatOptions, but also rejects
+// the common options which the dump formats do not support.
+func validateDumpFormatOptions(
+	format string, specified map[string]string, formatAllowed map[string]struct{},
+) error {
+	for _, opt := range dumpUnsupportedCommonOptions {
+		if _, ok := specified[opt]; ok {
+			return errors.Errorf("invalid option %q specified for %s import format", opt, format)
+		}
+	}
+	return validateFormatOptions(format, specified, formatAllowed)
+}
+
 func importJobDescription(
 	ctx context.Context,
 	p sql.PlanHookState,
@@ -346,6 +418,10 @@ func importPlanHook(
 	if _, ok := opts[importOptionDetached]; ok {
 		isDetached = true
 	}
+	if _, ok := opts[importOptionMode]; ok && importStmt.Bundle {
+		return nil, nil, false, errors.Errorf(
+			"IMPORT %s does not support the %s option", importStmt.FileFormat, importOptionMode)
+	}
 
 	filenamePatterns, err := exprEval.StringArray(ctx, importStmt.Files)
 	if err != nil {
@@ -420,6 +496,10 @@ func importPlanHook(
 			}
 		}
 
+		if importStmt.Bundle {
+			return importDumpBundle(ctx, p, importStmt, opts, files, isDetached, resultsCh)
+		}
+
 		table := importStmt.Table
 		// TODO: As part of work for #34240, we should be operating on
 		//  UnresolvedObjectNames here, rather than TableNames.
@@ -593,18 +673,43 @@ func importPlanHook(
 			if override, ok := opts[pgCopyNull]; ok {
 				format.PgCopy.Null = override
 			}
-			maxRowSize := int32(defaultScanBuffer)
-			if override, ok := opts[optMaxRowSize]; ok {
-				sz, err := humanizeutil.ParseBytes(override)
-				if err != nil {
-					return err
+			if format.PgCopy.MaxRowSize, err = parseMaxRowSize(opts); err != nil {
+				return err
+			}
+		case "PGDUMP":
+			if err = validateDumpFormatOptions(importStmt.FileFormat, opts, pgDumpAllowedOptions); err != nil {
+				return err
+			}
+			format.Format = roachpb.IOFileFormat_PgDump
+			if override, ok := opts[pgDumpTable]; ok {
+				if _, err := parser.ParseQualifiedTableName(override); err != nil {
+					return pgerror.Wrapf(err, pgcode.Syntax, "invalid %q value", pgDumpTable)
 				}
-				if sz < 1 || sz > math.MaxInt32 {
-					return errors.Errorf("%d out of range: %d", maxRowSize, sz)
+				format.PgDump.Table = override
+			}
+			if format.PgDump.MaxRowSize, err = parseMaxRowSize(opts); err != nil {
+				return err
+			}
+			if format.PgDump.Offset, err = parseDumpOffset(opts); err != nil {
+				return err
+			}
+		case "MYSQLDUMP":
+			if err = validateDumpFormatOptions(importStmt.FileFormat, opts, mysqlDumpAllowedOptions); err != nil {
+				return err
+			}
+			format.Format = roachpb.IOFileFormat_MysqlDump
+			if override, ok := opts[mysqlDumpTable]; ok {
+				if _, err := parser.ParseQualifiedTableName(override); err != nil {
+					return pgerror.Wrapf(err, pgcode.Syntax, "invalid %q value", mysqlDumpTable)
 				}
-				maxRowSize = int32(sz)
+				format.MysqlDump.Table = override
+			}
+			if format.MysqlDump.MaxRowSize, err = parseMaxRowSize(opts); err != nil {
+				return err
+			}
+			if format.MysqlDump.Offset, err = parseDumpOffset(opts); err != nil {
+				return err
 			}
-			format.PgCopy.MaxRowSize = maxRowSize
 		case "AVRO":
 			if err = validateFormatOptions(importStmt.FileFormat, opts, avroAllowedOptions); err != nil {
 				return err
@@ -619,22 +724,23 @@ func importPlanHook(
 			}
 			format.Format = roachpb.IOFileFormat_Parquet
 			_, format.Parquet.StrictMode = opts[optStrictValidation]
+		case "NDJSON":
+			if err = validateFormatOptions(importStmt.FileFormat, opts, ndjsonAllowedOptions); err != nil {
+				return err
+			}
+			format.Format = roachpb.IOFileFormat_NDJSON
+			if err := parseNDJSONOptions(opts, &format.Ndjson); err != nil {
+				return err
+			}
+			if _, ok := opts[importOptionSaveRejected]; ok {
+				format.SaveRejected = true
+			}
 		default:
 			return unimplemented.Newf("import.format", "unsupported import format: %q", importStmt.FileFormat)
 		}
 
-		if override, ok := opts[importOptionDecompress]; ok {
-			found := false
-			for name, value := range roachpb.IOFileFormat_Compression_value {
-				if strings.EqualFold(name, override) {
-					format.Compression = roachpb.IOFileFormat_Compression(value)
-					found = true
-					break
-				}
-			}
-			if !found {
-				return unimplemented.Newf("import.compression", "unsupported compression value: %q", override)
-			}
+		if format.Compression, err = parseCompressionOption(opts); err != nil {
+			return err
 		}
 
 		var tableDetails jobspb.ImportDetails_Table
@@ -654,10 +760,24 @@ func importPlanHook(
 			return err
 		}
 
-		err = ensureRequiredPrivileges(ctx, importIntoRequiredPrivileges, p, found)
+		upsert, err := parseImportMode(opts)
 		if err != nil {
 			return err
 		}
+
+		requiredPrivileges := importIntoRequiredPrivileges
+		if upsert {
+			requiredPrivileges = importUpsertRequiredPrivileges
+		}
+		err = ensureRequiredPrivileges(ctx, requiredPrivileges, p, found)
+		if err != nil {
+			return err
+		}
+		if upsert {
+			if err := checkTableSupportsImportUpsert(found); err != nil {
+				return err
+			}
+		}
 		// Check if the table has any vector indexes
 		for _, idx := range found.NonDropIndexes() {
 			if idx.GetType() == idxtype.VECTOR {
@@ -715,6 +835,12 @@ func importPlanHook(
 			}
 		}
 
+		if format.Format == roachpb.IOFileFormat_NDJSON {
+			if _, err := makeNDJSONColumns(found, importStmt.IntoCols, format.Ndjson); err != nil {
+				return pgerror.WithCandidateCode(err, pgcode.InvalidParameterValue)
+			}
+		}
+
 		{
 			// Resolve the UDTs used by the table being imported into.
 			typeDescs, err := resolveUDTsUsedByImportInto(ctx, p, found)
@@ -775,7 +901,9 @@ func importPlanHook(
 		// transaction here and then in a post-commit hook we should kick of the
 		// StartableJob which we attached to the connExecutor somehow.
 
-		useDistributedMerge := UseDistributedMergeForImport.Get(&p.ExecCfg().Settings.SV)
+		// An upsert import writes every SST at the time it is ingested, which the
+		// distributed merge pipeline does not support.
+		useDistributedMerge := UseDistributedMergeForImport.Get(&p.ExecCfg().Settings.SV) && !upsert
 		if useDistributedMerge && !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V26_2) {
 			return pgerror.New(pgcode.FeatureNotSupported,
 				"distributed merge for IMPORT requires all nodes to be running version 26.2 or later")
@@ -790,6 +918,7 @@ func importPlanHook(
 			Types:                 typeDetails,
 			DatabasePrimaryRegion: databasePrimaryRegion,
 			UseDistributedMerge:   useDistributedMerge,
+			Upsert:                upsert,
 		}
 
 		jr := jobs.Record{
@@ -872,6 +1001,133 @@ func importPlanHook(
 	return fn, jobs.ImportJobExecutionResultHeader, false, nil
 }
 
+// parseMaxRowSize returns the value of the max_row_size option, or the default
+// if it is not specified.
+func parseMaxRowSize(opts map[string]string) (int32, error) {
+	maxRowSize := int32(defaultScanBuffer)
+	if override, ok := opts[optMaxRowSize]; ok {
+		sz, err := humanizeutil.ParseBytes(override)
+		if err != nil {
+			return 0, err
+		}
+		if sz < 1 || sz > math.MaxInt32 {
+			return 0, errors.Errorf("%d out of range: %d", maxRowSize, sz)
+		}
+		maxRowSize = int32(sz)
+	}
+	return maxRowSize, nil
+}
+
+// parseDumpOffset parses the dump_offset option, which defaults to 0.
+func parseDumpOffset(opts map[string]string) (int64, error) {
+	override, ok := opts[dumpOffset]
+	if !ok {
+		return 0, nil
+	}
+	offset, err := strconv.ParseInt(override, 10, 64)
+	if err != nil {
+		return 0, pgerror.Wrapf(err, pgcode.InvalidParameterValue, "invalid %q value", dumpOffset)
+	}
+	if offset < 0 {
+		return 0, pgerror.Newf(pgcode.InvalidParameterValue, "invalid %q value %d", dumpOffset, offset)
+	}
+	return offset, nil
+}
+
+// parseNDJSONOptions parses the options of the NDJSON format.
+func parseNDJSONOptions(opts map[string]string, ndjsonOpts *roachpb.NDJSONOptions) error {
+	if override, ok := opts[ndjsonColumnPaths]; ok {
+		for _, entry := range strings.Split(override, ",") {
+			col, path, ok := strings.Cut(entry, "=")
+			col, path = strings.TrimSpace(col), strings.TrimSpace(path)
+			if !ok || col == "" || path == "" {
+				return pgerror.Newf(pgcode.Syntax,
+					"invalid %s entry %q; expected column=path", ndjsonColumnPaths, entry)
+			}
+			ndjsonOpts.ColumnPaths = append(ndjsonOpts.ColumnPaths, roachpb.NDJSONOptions_ColumnPath{
+				Column: col,
+				Path:   strings.Split(path, "."),
+			})
+		}
+	}
+	ndjsonOpts.CatchAllColumn = opts[ndjsonCatchAllColumn]
+	_, ndjsonOpts.StrictMode = opts[optStrictValidation]
+	var err error
+	if ndjsonOpts.MaxRowSize, err = parseMaxRowSize(opts); err != nil {
+		return err
+	}
+	if override, ok := opts[csvRowLimit]; ok {
+		rowLimit, err := strconv.Atoi(override)
+		if err != nil {
+			return pgerror.Wrapf(err, pgcode.Syntax, "invalid numeric %s value", csvRowLimit)
+		}
+		if rowLimit <= 0 {
+			return pgerror.Newf(pgcode.Syntax, "%s must be > 0", csvRowLimit)
+		}
+		ndjsonOpts.RowLimit = int64(rowLimit)
+	}
+	return nil
+}
+
+// parseImportMode returns whether the mode option requests an upsert import.
+func parseImportMode(opts map[string]string) (bool, error) {
+	mode, ok := opts[importOptionMode]
+	if !ok {
+		return false, nil
+	}
+	switch strings.ToLower(mode) {
+	case importModeInsert:
+		return false, nil
+	case importModeUpsert:
+		return true, nil
+	default:
+		return false, pgerror.Newf(pgcode.InvalidParameterValue,
+			"invalid value %q for option %q: expected %q or %q",
+			mode, importOptionMode, importModeInsert, importModeUpsert)
+	}
+}
+
+// checkTableSupportsImportUpsert returns an error if an upsert import cannot
+// maintain the constraints of the given table. An upsert import only detects
+// conflicts on the primary key, so it cannot enforce any other uniqueness
+// constraint, nor a primary key whose uniqueness is enforced across implicit
+// partitions.
+func checkTableSupportsImportUpsert(desc catalog.TableDescriptor) error {
+	if desc.GetPrimaryIndex().ImplicitPartitioningColumnCount() > 0 {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"IMPORT INTO with %s = '%s' is not supported for table %q with an implicitly partitioned primary key",
+			importOptionMode, importModeUpsert, desc.GetName())
+	}
+	for _, idx := range desc.PublicNonPrimaryIndexes() {
+		if idx.IsUnique() {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"IMPORT INTO with %s = '%s' is not supported for table %q with unique index %q",
+				importOptionMode, importModeUpsert, desc.GetName(), idx.GetName())
+		}
+	}
+	if uwis := desc.EnforcedUniqueConstraintsWithoutIndex(); len(uwis) > 0 {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"IMPORT INTO with %s = '%s' is not supported for table %q with unique constraint %q",
+			importOptionMode, importModeUpsert, desc.GetName(), uwis[0].GetName())
+	}
+	return nil
+}
+
+// parseCompressionOption returns the compression specified by the decompress
+// option, or Auto if it is not specified.
+func parseCompressionOption(opts map[string]string) (roachpb.IOFileFormat_Compression, error) {
+	override, ok := opts[importOptionDecompress]
+	if !ok {
+		return roachpb.IOFileFormat_Auto, nil
+	}
+	for name, value := range roachpb.IOFileFormat_Compression_value {
+		if strings.EqualFold(name, override) {
+			return roachpb.IOFileFormat_Compression(value), nil
+		}
+	}
+	return 0, unimplemented.Newf("import.compression", "unsupported compression value: %q", override)
+}
+
 func parseAvroOptions(
 	ctx context.Context, opts map[string]string, p sql.PlanHookState, format *roachpb.IOFileFormat,
 ) error {
@@ -309,6 +309,12 @@ func makeInputConverter(
 	case roachpb.IOFileFormat_PgCopy:
 		return newPgCopyReader(semaCtx, spec.Format.PgCopy, kvCh, spec.WalltimeNanos,
 			readerParallelism, desc, targetCols, evalCtx, db)
+	case roachpb.IOFileFormat_PgDump:
+		return newPgDumpReader(semaCtx, spec.Format.PgDump, kvCh, spec.WalltimeNanos,
+			readerParallelism, desc, targetCols, evalCtx, db)
+	case roachpb.IOFileFormat_MysqlDump:
+		return newMysqlDumpReader(semaCtx, spec.Format.MysqlDump, kvCh, spec.WalltimeNanos,
+			readerParallelism, desc, targetCols, evalCtx, db)
 	case roachpb.IOFileFormat_Avro:
 		return newAvroInputReader(
 			semaCtx, kvCh, desc, spec.Format.Avro, spec.WalltimeNanos,
@@ -317,6 +323,10 @@ func makeInputConverter(
 		return newParquetInputReader(
 			semaCtx, kvCh, spec.WalltimeNanos, readerParallelism,
 			desc, targetCols, evalCtx, seqChunkProvider, db, spec.Format.Parquet)
+	case roachpb.IOFileFormat_NDJSON:
+		return newNDJSONInputReader(
+			semaCtx, kvCh, spec.Format.Ndjson, spec.WalltimeNanos, readerParallelism,
+			desc, targetCols, evalCtx, seqChunkProvider, db)
 	default:
 		return nil, errors.Errorf(
 			"Requested IMPORT format (%d) not supported by this node", spec.Format.Format)
@@ -329,7 +339,9 @@ var UseDistributedMergeForImport = settings.RegisterBoolSetting(settings.Applica
 	false)
 
 // ingestKvs drains kvs from the channel until it closes, ingesting them using
-// the BulkAdder. It handles the required buffering/sorting/etc.
+// the BulkAdder. It handles the required buffering/sorting/etc. If upserter is
+// non-nil, the KVs overwrite existing rows and the stale KVs of those rows are
+// deleted.
 func ingestKvs(
 	ctx context.Context,
 	flowCtx *execinfra.FlowCtx,
@@ -338,6 +350,7 @@ func ingestKvs(
 	tableName string,
 	progCh chan execinfrapb.RemoteProducerMetadata_BulkProcessorProgress,
 	kvCh <-chan row.KVBatch,
+	upserter *importUpserter,
 ) (*kvpb.BulkOpSummary, error) {
 	ctx, span := tracing.ChildSpan(ctx, "import-ingest-kvs")
 	defer span.Finish()
@@ -384,17 +397,35 @@ func ingestKvs(
 		pkIndexID = table.Desc.PrimaryIndex.ID
 		minBufferSize, maxBufferSize := importBufferConfigSizes(flowCtx.Cfg.Settings, true /* isPKAdder */)
 
+		adderOpts := func(name string) kvserverbase.BulkAdderOptions {
+			opts := kvserverbase.BulkAdderOptions{
+				Name:                     name,
+				DisallowShadowingBelow:   writeTS,
+				SkipDuplicates:           true,
+				MinBufferSize:            minBufferSize,
+				MaxBufferSize:            maxBufferSize,
+				InitialSplitsIfUnordered: int(spec.InitialSplits),
+				WriteAtBatchTimestamp:    true,
+				ImportEpoch:              table.Desc.ImportEpoch,
+			}
+			if spec.Upsert {
+				// An upsert import shadows existing keys of the table, so rather
+				// than rejecting existing keys, its SSTs are checked for MVCC
+				// conflicts at the time they are ingested. Keys it writes more than
+				// once, which DisallowShadowingBelow would reject, are rejected by
+				// the importUpserter and checkUpsertDuplicates instead. Its writes
+				// are rolled back by reverting the table, so they are not tagged
+				// with the import epoch.
+				opts.DisallowShadowingBelow = hlc.Timestamp{}
+				opts.DisallowConflicts = true
+				opts.ImportEpoch = 0
+			}
+			return opts
+		}
+
 		var adder kvserverbase.BulkAdder
-		adder, err = flowCtx.Cfg.BulkAdder(ctx, flowCtx.Cfg.DB.KV(), writeTS, kvserverbase.BulkAdderOptions{
-			Name:                     fmt.Sprintf("%s_indexes", table.Desc.Name),
-			DisallowShadowingBelow:   writeTS,
-			SkipDuplicates:           true,
-			MinBufferSize:            minBufferSize,
-			MaxBufferSize:            maxBufferSize,
-			InitialSplitsIfUnordered: int(spec.InitialSplits),
-			WriteAtBatchTimestamp:    true,
-			ImportEpoch:              table.Desc.ImportEpoch,
-		})
+		adder, err = flowCtx.Cfg.BulkAdder(ctx, flowCtx.Cfg.DB.KV(), writeTS,
+			adderOpts(fmt.Sprintf("%s_indexes", table.Desc.Name)))
 		if err != nil {
 			return nil, err
 		}
@@ -402,16 +433,8 @@ func ingestKvs(
 		defer indexSink.Close(ctx)
 		progressTracker.registerSink(indexSink)
 
-		adder, err = flowCtx.Cfg.BulkAdder(ctx, flowCtx.Cfg.DB.KV(), writeTS, kvserverbase.BulkAdderOptions{
-			Name:                     fmt.Sprintf("%s_rows", table.Desc.Name),
-			DisallowShadowingBelow:   writeTS,
-			SkipDuplicates:           true,
-			MinBufferSize:            minBufferSize,
-			MaxBufferSize:            maxBufferSize,
-			InitialSplitsIfUnordered: int(spec.InitialSplits),
-			WriteAtBatchTimestamp:    true,
-			ImportEpoch:              table.Desc.ImportEpoch,
-		})
+		adder, err = flowCtx.Cfg.BulkAdder(ctx, flowCtx.Cfg.DB.KV(), writeTS,
+			adderOpts(fmt.Sprintf("%s_rows", table.Desc.Name)))
 		if err != nil {
 			return nil, err
 		}
@@ -479,8 +502,8 @@ func ingestKvs(
 		// mentioned above, the KVs sent to the BulkAdder are no longer grouped which
 		// results in flushing a much larger number of small SSTs. This increases the
 		// number of L0 (and total) files, but with a lower memory usage.
-		for kvBatch := range kvCh {
-			for _, kv := range kvBatch.KVs {
+		addKVs := func(kvs []roachpb.KeyValue) error {
+			for _, kv := range kvs {
 				_, _, indexID, indexErr := flowCtx.Codec().DecodeIndexPrefix(kv.Key)
 				if indexErr != nil {
 					return indexErr
@@ -496,7 +519,28 @@ func ingestKvs(
 					return err
 				}
 			}
+			return nil
+		}
+		for kvBatch := range kvCh {
+			if upserter != nil {
+				tombstones, err := upserter.tombstones(ctx, kvBatch.KVs)
+				if err != nil {
+					return err
+				}
+				if err := addKVs(tombstones); err != nil {
+					return err
+				}
+			}
+			if err := addKVs(kvBatch.KVs); err != nil {
+				return err
+			}
 			progressTracker.recordKVBatch(kvBatch)
+			if upserter != nil && upserter.pendingRows() >= upsertMaxPendingRows {
+				if err := flush(); err != nil {
+					return err
+				}
+				upserter.flushed()
+			}
 			if flowCtx.Cfg.TestingKnobs.BulkAdderFlushesEveryBatch {
 				if err := flush(); err != nil {
 					log.Dev.Warningf(ctx, "%v", err)
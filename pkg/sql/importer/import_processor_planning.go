@@ -657,6 +657,7 @@ func makeImportReaderSpecs(
 				InitialSplits:              int32(initialSplitsPerProc),
 				UseDistributedMerge:        details.UseDistributedMerge,
 				DistributedMergeFilePrefix: distributedMergeFilePrefix,
+				Upsert:                     details.Upsert,
 			}
 			inputSpecs = append(inputSpecs, spec)
 		}
@@ -5371,6 +5371,309 @@ func TestImportPgCopy(t *testing.T) {
 	}
 }
 
+func TestImportPgDump(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	skip.UnderRace(t)
+
+	const dump = `--
+-- PostgreSQL database dump
+--
+
+SET statement_timeout = 0;
+SET client_encoding = 'UTF8';
+SELECT pg_catalog.set_config('search_path', '', false);
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm WITH SCHEMA public;
+
+CREATE TABLE public.users (
+    id integer NOT NULL,
+    name text,
+    bio text
+);
+
+ALTER TABLE public.users OWNER TO postgres;
+
+CREATE SEQUENCE public.users_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.users_id_seq OWNED BY public.users.id;
+
+ALTER TABLE ONLY public.users ALTER COLUMN id SET DEFAULT nextval('public.users_id_seq'::regclass);
+
+COPY public.users (id, name, bio) FROM stdin;
+1	alice	likes; semicolons
+2	bob	\N
+\.
+
+
+SELECT pg_catalog.setval('public.users_id_seq', 2, true);
+
+ALTER TABLE ONLY public.users
+    ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+
+CREATE INDEX users_name_idx ON public.users USING btree (name);
+`
+
+	// The data of the second table does not parse, after the first table and
+	// its sequence are created and the first table's data is imported.
+	const badDump = `
+CREATE SEQUENCE public.t1_seq;
+
+CREATE TABLE public.t1 (
+    i integer DEFAULT nextval('public.t1_seq')
+);
+
+COPY public.t1 (i) FROM stdin;
+1
+\.
+
+CREATE TABLE public.t2 (
+    i integer
+);
+
+COPY public.t2 (i) FROM stdin;
+x
+\.
+`
+
+	ctx := context.Background()
+	baseDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "dump.sql"), []byte(dump), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "bad.sql"), []byte(badDump), 0644))
+	archive := makeTestPgArchive(t, 16, pgArchiveCompressionGzip, testPgArchiveEntries)
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "dump.pgdump"), archive, 0644))
+
+	tc := serverutils.StartCluster(t, 1, base.TestClusterArgs{ServerArgs: base.TestServerArgs{
+		ExternalIODir: baseDir,
+	}})
+	defer tc.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(tc.ServerConn(0))
+	sqlDB.Exec(t, `SET CLUSTER SETTING bulkio.import.elastic_control.enabled = false`)
+
+	t.Run("plain", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE DATABASE plain; USE plain`)
+		res := sqlDB.QueryStr(t,
+			`IMPORT PGDUMP 'nodelocal://1/dump.sql' WITH log_ignored_statements = 'nodelocal://1/skipped.log'`)
+		require.Len(t, res, 1)
+		require.Equal(t, "2", res[0][3])
+
+		sqlDB.CheckQueryResults(t, `SELECT * FROM users ORDER BY id`, [][]string{
+			{"1", "alice", "likes; semicolons"},
+			{"2", "bob", "NULL"},
+		})
+		// The primary key is part of the table rather than a hidden rowid.
+		sqlDB.CheckQueryResults(t,
+			`SELECT index_name, column_name FROM [SHOW INDEXES FROM users] WHERE NOT storing AND NOT implicit ORDER BY 1`,
+			[][]string{{"users_name_idx", "name"}, {"users_pkey", "id"}})
+		sqlDB.CheckQueryResults(t, `SELECT nextval('users_id_seq')`, [][]string{{"3"}})
+
+		skipped, err := os.ReadFile(filepath.Join(baseDir, "skipped.log"))
+		require.NoError(t, err)
+		require.Contains(t, string(skipped), "CREATE EXTENSION IF NOT EXISTS pg_trgm")
+		require.Contains(t, string(skipped), "ALTER TABLE public.users OWNER TO postgres;")
+		require.NotContains(t, string(skipped), "SET statement_timeout")
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE DATABASE custom; USE custom`)
+		sqlDB.Exec(t, `IMPORT PGDUMP 'nodelocal://1/dump.pgdump'`)
+		sqlDB.CheckQueryResults(t, `SELECT * FROM a ORDER BY i`, [][]string{{"1", "one"}, {"2", "NULL"}})
+		sqlDB.CheckQueryResults(t, `SELECT i FROM b`, [][]string{{"3"}})
+		sqlDB.CheckQueryResults(t,
+			`SELECT constraint_name FROM [SHOW CONSTRAINTS FROM a] WHERE constraint_type = 'PRIMARY KEY'`,
+			[][]string{{"a_pkey"}})
+	})
+
+	t.Run("into", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE DATABASE into_db; USE into_db`)
+		sqlDB.Exec(t, `CREATE TABLE t (k INT PRIMARY KEY, v STRING)`)
+		sqlDB.Exec(t,
+			`IMPORT INTO t (k, v) PGDUMP DATA ('nodelocal://1/dump.pgdump') WITH pgdump_table = 'public.a'`)
+		sqlDB.CheckQueryResults(t, `SELECT * FROM t ORDER BY k`, [][]string{{"1", "one"}, {"2", "NULL"}})
+		sqlDB.ExpectErr(t, `no COPY data for table public.missing`,
+			`IMPORT INTO t (k, v) PGDUMP DATA ('nodelocal://1/dump.sql') WITH pgdump_table = 'public.missing'`)
+		sqlDB.ExpectErr(t, `invalid option "mode" specified for PGDUMP import format`,
+			`IMPORT INTO t (k, v) PGDUMP DATA ('nodelocal://1/dump.sql') WITH pgdump_table = 'public.users', mode = 'upsert'`)
+	})
+
+	t.Run("atomic", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE DATABASE atomic; USE atomic`)
+		sqlDB.ExpectErr(t, `could not parse "x" as type int`, `IMPORT PGDUMP 'nodelocal://1/bad.sql'`)
+		// The objects created by the import are dropped.
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM [SHOW TABLES]`, [][]string{{"0"}})
+	})
+
+	t.Run("pause", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE DATABASE paused; USE paused`)
+		sqlDB.Exec(t, `SET CLUSTER SETTING jobs.debug.pausepoints = 'import_dump.before_data'`)
+		var jobID jobspb.JobID
+		sqlDB.QueryRow(t, `IMPORT PGDUMP 'nodelocal://1/dump.sql' WITH DETACHED`).Scan(&jobID)
+		jobutils.WaitForJobToPause(t, sqlDB, jobID)
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM users`, [][]string{{"0"}})
+
+		// The resumed job continues with the data of the table it created.
+		sqlDB.Exec(t, `SET CLUSTER SETTING jobs.debug.pausepoints = ''`)
+		sqlDB.Exec(t, `RESUME JOB $1`, jobID)
+		jobutils.WaitForJobToSucceed(t, sqlDB, jobID)
+		sqlDB.CheckQueryResults(t, `SELECT id, name FROM users ORDER BY id`, [][]string{
+			{"1", "alice"},
+			{"2", "bob"},
+		})
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE DATABASE canceled; USE canceled`)
+		sqlDB.Exec(t, `SET CLUSTER SETTING jobs.debug.pausepoints = 'import_dump.before_data'`)
+		defer sqlDB.Exec(t, `SET CLUSTER SETTING jobs.debug.pausepoints = ''`)
+		var jobID jobspb.JobID
+		sqlDB.QueryRow(t, `IMPORT PGDUMP 'nodelocal://1/dump.sql' WITH DETACHED`).Scan(&jobID)
+		jobutils.WaitForJobToPause(t, sqlDB, jobID)
+		sqlDB.Exec(t, `CANCEL JOB $1`, jobID)
+		jobutils.WaitForJobToCancel(t, sqlDB, jobID)
+		// The objects created before the job was paused are dropped.
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM [SHOW TABLES]`, [][]string{{"0"}})
+	})
+
+	sqlDB.ExpectErr(t, `IMPORT PGDUMP does not support the mode option`,
+		`IMPORT PGDUMP 'nodelocal://1/dump.sql' WITH mode = 'upsert'`)
+	for _, opt := range []string{`experimental_save_rejected`, `disable_glob_matching`} {
+		sqlDB.ExpectErr(t, fmt.Sprintf(`invalid option "%s" specified for PGDUMP import format`, opt),
+			fmt.Sprintf(`IMPORT PGDUMP 'nodelocal://1/dump.sql' WITH %s`, opt))
+	}
+}
+
+func TestImportMysqlDump(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	skip.UnderRace(t)
+
+	ctx := context.Background()
+	baseDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "dump.sql"), []byte(testMysqlDump), 0644))
+
+	tc := serverutils.StartCluster(t, 1, base.TestClusterArgs{ServerArgs: base.TestServerArgs{
+		ExternalIODir: baseDir,
+	}})
+	defer tc.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(tc.ServerConn(0))
+	sqlDB.Exec(t, `SET CLUSTER SETTING bulkio.import.elastic_control.enabled = false`)
+
+	t.Run("bundle", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE DATABASE bundle; USE bundle`)
+		sqlDB.Exec(t,
+			`IMPORT MYSQLDUMP 'nodelocal://1/dump.sql' WITH log_ignored_statements = 'nodelocal://1/skipped.log'`)
+
+		sqlDB.CheckQueryResults(t, `SELECT * FROM a ORDER BY id`, [][]string{
+			{"1", "x; y", "NULL"},
+			{"2", "it's", "3"},
+			{"3", "NULL", "NULL"},
+		})
+		sqlDB.CheckQueryResults(t, `SELECT id, encode(v, 'escape') FROM b`, [][]string{{"3", "hi"}})
+		sqlDB.CheckQueryResults(t, `SELECT nextval('a_id_seq')`, [][]string{{"4"}})
+		sqlDB.CheckQueryResults(t,
+			`SELECT constraint_name FROM [SHOW CONSTRAINTS FROM a] WHERE constraint_type = 'FOREIGN KEY'`,
+			[][]string{{"a_fk"}})
+		sqlDB.CheckQueryResults(t,
+			`SELECT comment FROM [SHOW COLUMNS FROM a WITH COMMENT] WHERE column_name = 's'`,
+			[][]string{{"the s"}})
+
+		skipped, err := os.ReadFile(filepath.Join(baseDir, "skipped.log"))
+		require.NoError(t, err)
+		require.Contains(t, string(skipped), "CREATE TRIGGER `t`")
+		require.NotContains(t, string(skipped), "SET NAMES")
+	})
+
+	t.Run("into", func(t *testing.T) {
+		sqlDB.Exec(t, `CREATE DATABASE into_db; USE into_db`)
+		sqlDB.Exec(t, `CREATE TABLE t (k INT PRIMARY KEY, v BYTES)`)
+		sqlDB.Exec(t,
+			`IMPORT INTO t (k, v) MYSQLDUMP DATA ('nodelocal://1/dump.sql') WITH mysqldump_table = 'b'`)
+		sqlDB.CheckQueryResults(t, `SELECT k, encode(v, 'escape') FROM t`, [][]string{{"3", "hi"}})
+		sqlDB.ExpectErr(t, `no INSERT statements for table missing`,
+			`IMPORT INTO t (k, v) MYSQLDUMP DATA ('nodelocal://1/dump.sql') WITH mysqldump_table = 'missing'`)
+	})
+
+	sqlDB.ExpectErr(t, `invalid option "experimental_save_rejected" specified for MYSQLDUMP import format`,
+		`IMPORT MYSQLDUMP 'nodelocal://1/dump.sql' WITH experimental_save_rejected`)
+}
+
+func TestImportIntoNDJSON(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	skip.UnderRace(t)
+
+	const data = `{"id": 1, "user": {"name": "alice", "tags": ["a", "b"]}, "ts": "2024-01-01T00:00:00Z", "extra": true}
+
+{"id": 2, "user": {"name": "bob"}, "other": 5}
+not json
+{"id": 3}
+`
+	ctx := context.Background()
+	baseDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "data.ndjson"), []byte(data), 0644))
+
+	tc := serverutils.StartCluster(t, 1, base.TestClusterArgs{ServerArgs: base.TestServerArgs{
+		ExternalIODir: baseDir,
+	}})
+	defer tc.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(tc.ServerConn(0))
+	sqlDB.Exec(t, `SET CLUSTER SETTING bulkio.import.elastic_control.enabled = false`)
+
+	sqlDB.Exec(t, `CREATE TABLE t (id INT PRIMARY KEY, name STRING, first_tag STRING, ts TIMESTAMPTZ, rest JSONB)`)
+
+	t.Run("catch-all", func(t *testing.T) {
+		defer sqlDB.Exec(t, `TRUNCATE t`)
+		sqlDB.Exec(t, `IMPORT INTO t NDJSON DATA ('nodelocal://1/data.ndjson') WITH
+			column_paths = 'name=user.name, first_tag=user.tags.0',
+			catch_all_column = 'rest',
+			experimental_save_rejected`)
+		sqlDB.CheckQueryResults(t, `SELECT id, name, first_tag, ts::DATE::STRING, rest FROM t ORDER BY id`, [][]string{
+			{"1", "alice", "a", "2024-01-01", `{"extra": true}`},
+			{"2", "bob", "NULL", "NULL", `{"other": 5}`},
+			{"3", "NULL", "NULL", "NULL", "NULL"},
+		})
+		rejected, err := os.ReadFile(filepath.Join(baseDir, "data.ndjson.rejected"))
+		require.NoError(t, err)
+		require.Equal(t, "not json\n", string(rejected))
+	})
+
+	t.Run("target columns", func(t *testing.T) {
+		defer sqlDB.Exec(t, `TRUNCATE t`)
+		sqlDB.Exec(t, `IMPORT INTO t (id, name) NDJSON DATA ('nodelocal://1/data.ndjson') WITH
+			column_paths = 'name=user.name', row_limit = '2'`)
+		sqlDB.CheckQueryResults(t, `SELECT id, name, rest FROM t ORDER BY id`, [][]string{
+			{"1", "alice", "NULL"},
+			{"2", "bob", "NULL"},
+		})
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(baseDir, "strict.ndjson"), []byte(`{"id": 1, "x": 2}`), 0644))
+		sqlDB.ExpectErr(t, `could not find column for key "x"`,
+			`IMPORT INTO t NDJSON DATA ('nodelocal://1/strict.ndjson') WITH strict_validation`)
+		sqlDB.ExpectErr(t, `error parsing row \d+: .*\(row: not json\)`,
+			`IMPORT INTO t NDJSON DATA ('nodelocal://1/data.ndjson') WITH catch_all_column = 'rest'`)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		sqlDB.ExpectErr(t, `catch-all column "name" must be of type JSONB`,
+			`IMPORT INTO t NDJSON DATA ('nodelocal://1/data.ndjson') WITH catch_all_column = 'name'`)
+		sqlDB.ExpectErr(t, `column "missing" with a path is not a target column`,
+			`IMPORT INTO t NDJSON DATA ('nodelocal://1/data.ndjson') WITH column_paths = 'missing=a.b'`)
+		sqlDB.ExpectErr(t, `invalid column_paths entry "name"`,
+			`IMPORT INTO t NDJSON DATA ('nodelocal://1/data.ndjson') WITH column_paths = 'name'`)
+	})
+}
+
 func TestCreateStatsAfterImport(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -5691,6 +5994,8 @@ func TestDisallowsInvalidFormatOptions(t *testing.T) {
 	addOpts(csvAllowedOptions)
 	addOpts(mysqlOutAllowedOptions)
 	addOpts(pgCopyAllowedOptions)
+	addOpts(pgDumpAllowedOptions)
+	addOpts(ndjsonAllowedOptions)
 
 	// Helper to pick num options from the set of allowed and the set
 	// of all other options.  Returns generated options plus a flag indicating
@@ -5735,6 +6040,8 @@ func TestDisallowsInvalidFormatOptions(t *testing.T) {
 		{"csv", csvAllowedOptions},
 		{"mysqouout", mysqlOutAllowedOptions},
 		{"pgcopy", pgCopyAllowedOptions},
+		{"pgdump", pgDumpAllowedOptions},
+		{"ndjson", ndjsonAllowedOptions},
 	}
 
 	for _, tc := range tests {
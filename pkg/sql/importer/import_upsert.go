@@ -0,0 +1,425 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package importer
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// importUpserter computes the deletions that an IMPORT INTO with
+// mode = 'upsert' must ingest alongside the KVs of the rows it imports.
+//
+// An upsert import ingests the KVs of each imported row on top of the
+// existing data, so a row whose primary key already exists is overwritten in
+// place. The KVs of the overwritten row that are not also KVs of the imported
+// row would be left behind: secondary index entries for values that changed,
+// and column families whose columns are all NULL in the imported row. The
+// importUpserter finds those KVs by reading the existing rows and encoding
+// them the same way the imported rows are encoded, and returns a tombstone for
+// each of them.
+//
+// The table is offline while the import runs, so the tombstones are computed
+// from the rows as of the timestamp at which the import started, which does
+// not depend on which imported KVs have been ingested already, by this or any
+// other processor, nor on whether the import was resumed. That only holds if
+// the import writes each row at most once, so a primary key that occurs more
+// than once in the input fails the import, which then reverts the table to
+// its state when the import started. The only exception is a row whose
+// current value is exactly the imported one, which an earlier attempt of the
+// import ingested before it was resumed, which mirrors what
+// DisallowShadowingBelow allows for regular imports.
+//
+// A processor detects a duplicate among the rows it has not flushed yet, and
+// a duplicate that was already ingested when it reads the row. Two processors
+// that both hold the same primary key in their unflushed KVs at the same time
+// do not see each other's row, so checkUpsertDuplicates looks for such rows
+// once all of them have been ingested, before the table is published.
+type importUpserter struct {
+	db         *kv.DB
+	codec      keys.SQLCodec
+	pkIndexID  descpb.IndexID
+	fetchSpec  fetchpb.IndexFetchSpec
+	alloc      tree.DatumAlloc
+	conv       *row.DatumRowConverter
+	convOutput chan row.KVBatch
+
+	// writeTS is the timestamp at which the import started.
+	writeTS hlc.Timestamp
+	// pending holds the row prefixes of the primary keys passed to tombstones
+	// whose KVs may not have been ingested yet.
+	pending map[string]struct{}
+}
+
+// upsertMaxPendingRows is the number of rows after which an upsert import
+// flushes its KVs, so that the rows it holds in memory to detect duplicate
+// primary keys are bounded.
+const upsertMaxPendingRows = 1 << 16
+
+func newImportUpserter(
+	ctx context.Context,
+	semaCtx *tree.SemaContext,
+	evalCtx *eval.Context,
+	desc catalog.TableDescriptor,
+	db *kv.DB,
+	writeTS hlc.Timestamp,
+) (*importUpserter, error) {
+	// The existing rows are re-encoded from all of their stored columns, except
+	// for computed columns which the converter re-computes from the others.
+	var colIDs []descpb.ColumnID
+	var colNames tree.NameList
+	for _, col := range desc.PublicColumns() {
+		if col.IsVirtual() || col.IsComputed() {
+			continue
+		}
+		colIDs = append(colIDs, col.GetID())
+		colNames = append(colNames, col.ColName())
+	}
+
+	u := &importUpserter{
+		db:        db,
+		codec:     evalCtx.Codec,
+		pkIndexID: desc.GetPrimaryIndexID(),
+		// Each converted row is sent as its own batch, which is received right
+		// after the conversion.
+		convOutput: make(chan row.KVBatch, 1),
+		writeTS:    writeTS,
+		pending:    make(map[string]struct{}),
+	}
+	if err := rowenc.InitIndexFetchSpec(
+		&u.fetchSpec, evalCtx.Codec, desc, desc.GetPrimaryIndex(), colIDs,
+	); err != nil {
+		return nil, err
+	}
+	var err error
+	u.conv, err = row.NewDatumRowConverter(
+		ctx, semaCtx, desc, colNames, evalCtx, u.convOutput, nil /* seqChunkProvider */, nil /* metrics */, db,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating converter for existing rows")
+	}
+	return u, nil
+}
+
+// tombstones returns the KVs of the existing rows overwritten by the given
+// KVs that are not overwritten themselves, with empty values. It returns an
+// error if one of the rows was already passed to tombstones since the last
+// call to flushed, or was written since the import started.
+func (u *importUpserter) tombstones(
+	ctx context.Context, kvs []roachpb.KeyValue,
+) ([]roachpb.KeyValue, error) {
+	imported := make(map[string]struct{}, len(kvs))
+	importedRows := make(map[string][]roachpb.KeyValue)
+	var spans roachpb.Spans
+	for _, kv := range kvs {
+		_, _, indexID, err := u.codec.DecodeIndexPrefix(kv.Key)
+		if err != nil {
+			return nil, err
+		}
+		isPK := descpb.IndexID(indexID) == u.pkIndexID
+		if _, ok := imported[string(kv.Key)]; ok && isPK {
+			return nil, duplicateUpsertKeyError(kv.Key)
+		}
+		imported[string(kv.Key)] = struct{}{}
+		if !isPK {
+			continue
+		}
+		n, err := keys.GetRowPrefixLength(kv.Key)
+		if err != nil {
+			return nil, err
+		}
+		prefix := kv.Key[:n]
+		if _, ok := importedRows[string(prefix)]; !ok {
+			if _, ok := u.pending[string(prefix)]; ok {
+				return nil, duplicateUpsertKeyError(prefix)
+			}
+			spans = append(spans, roachpb.Span{Key: prefix, EndKey: prefix.PrefixEnd()})
+		}
+		importedRows[string(prefix)] = append(importedRows[string(prefix)], kv)
+	}
+	if len(spans) == 0 {
+		return nil, nil
+	}
+	for _, sp := range spans {
+		u.pending[string(sp.Key)] = struct{}{}
+	}
+	sort.Sort(spans)
+
+	// Rows that were not written since the import started are the same now as
+	// when it started, so they are read at the current time. The others were
+	// written by an earlier attempt of this import and are read as of its
+	// start.
+	var existing []roachpb.KeyValue
+	var ingested roachpb.Spans
+	if err := u.db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		existing, ingested = existing[:0], ingested[:0]
+		b := txn.NewBatch()
+		for _, sp := range spans {
+			b.Scan(sp.Key, sp.EndKey)
+		}
+		if err := txn.Run(ctx, b); err != nil {
+			return err
+		}
+		var current roachpb.Spans
+		for i, res := range b.Results {
+			written := false
+			for _, kv := range res.Rows {
+				if u.writeTS.Less(kv.Value.Timestamp) {
+					written = true
+					break
+				}
+			}
+			if !written {
+				current = append(current, spans[i])
+				continue
+			}
+			if !sameUpsertRow(res.Rows, importedRows[string(spans[i].Key)]) {
+				return duplicateUpsertKeyError(spans[i].Key)
+			}
+			ingested = append(ingested, spans[i])
+		}
+		return u.fetchRows(ctx, txn, current, &existing)
+	}); err != nil {
+		return nil, errors.Wrap(err, "reading rows overwritten by upsert import")
+	}
+	if len(ingested) > 0 {
+		if err := u.db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+			if err := txn.SetFixedTimestamp(ctx, u.writeTS); err != nil {
+				return err
+			}
+			return u.fetchRows(ctx, txn, ingested, &existing)
+		}); err != nil {
+			return nil, errors.Wrap(err, "reading rows overwritten by upsert import")
+		}
+	}
+
+	var tombstones []roachpb.KeyValue
+	for _, kv := range existing {
+		if _, ok := imported[string(kv.Key)]; !ok {
+			tombstones = append(tombstones, roachpb.KeyValue{Key: kv.Key})
+		}
+	}
+	return tombstones, nil
+}
+
+// pendingRows returns the number of rows passed to tombstones since the last
+// call to flushed.
+func (u *importUpserter) pendingRows() int {
+	return len(u.pending)
+}
+
+// flushed is called once the KVs of the rows passed to tombstones, and their
+// tombstones, have been ingested, after which rows with the same primary keys
+// are detected by reading them.
+func (u *importUpserter) flushed() {
+	clear(u.pending)
+}
+
+// fetchRows appends the KVs of the rows in the spans, re-encoded from their
+// datums, to existing.
+func (u *importUpserter) fetchRows(
+	ctx context.Context, txn *kv.Txn, spans roachpb.Spans, existing *[]roachpb.KeyValue,
+) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	var fetcher row.Fetcher
+	if err := fetcher.Init(ctx, row.FetcherInitArgs{
+		Txn:   txn,
+		Alloc: &u.alloc,
+		Spec:  &u.fetchSpec,
+	}); err != nil {
+		return err
+	}
+	defer fetcher.Close(ctx)
+	if err := fetcher.StartScan(
+		ctx, spans, nil, /* spanIDs */
+		rowinfra.GetDefaultBatchBytesLimit(false /* forceProductionValue */),
+		rowinfra.RowLimit(len(spans)),
+	); err != nil {
+		return err
+	}
+	for {
+		datums, _, err := fetcher.NextRowDecoded(ctx)
+		if err != nil {
+			return err
+		}
+		if datums == nil {
+			return nil
+		}
+		rowKVs, err := u.encodeRow(ctx, datums)
+		if err != nil {
+			return err
+		}
+		*existing = append(*existing, rowKVs...)
+	}
+}
+
+// sameUpsertRow returns whether the primary index KVs of a row read from the
+// table are the imported ones.
+func sameUpsertRow(current []kv.KeyValue, imported []roachpb.KeyValue) bool {
+	if len(current) != len(imported) {
+		return false
+	}
+	values := make(map[string][]byte, len(imported))
+	for i := range imported {
+		values[string(imported[i].Key)] = imported[i].Value.TagAndDataBytes()
+	}
+	for _, kv := range current {
+		v, ok := values[string(kv.Key)]
+		if !ok || !bytes.Equal(v, kv.Value.TagAndDataBytes()) {
+			return false
+		}
+	}
+	return true
+}
+
+func duplicateUpsertKeyError(key roachpb.Key) error {
+	return pgerror.Newf(pgcode.UniqueViolation,
+		"duplicate primary key %s in the input of upsert import", key)
+}
+
+// checkUpsertDuplicates returns an error if a primary index KV of the table
+// was written with two different values since the upsert import started at
+// writeTS. The table is offline while the import runs, so such a KV was
+// written by two processors that each imported a row with its primary key.
+// Rows that were ingested again by a resumed import have the same value.
+func checkUpsertDuplicates(
+	ctx context.Context,
+	db *kv.DB,
+	codec keys.SQLCodec,
+	desc *descpb.TableDescriptor,
+	writeTS hlc.Timestamp,
+) error {
+	span := roachpb.Span{Key: codec.IndexPrefix(uint32(desc.ID), uint32(desc.PrimaryIndex.ID))}
+	span.EndKey = span.Key.PrefixEnd()
+	header := kvpb.Header{Timestamp: db.Clock().Now()}
+
+	// The revisions of a key are never split across responses, since
+	// SplitMidKey is not set, so only the latest revision seen needs to be
+	// kept across them.
+	var lastKey roachpb.Key
+	var lastValue []byte
+	for span.Key != nil {
+		req := &kvpb.ExportRequest{
+			RequestHeader:  kvpb.RequestHeader{Key: span.Key, EndKey: span.EndKey},
+			StartTime:      writeTS,
+			MVCCFilter:     kvpb.MVCCFilter_All,
+			TargetFileSize: upsertDuplicateCheckFileSize,
+		}
+		resp, pErr := kv.SendWrappedWithAdmission(ctx, db.NonTransactionalSender(), header, kvpb.AdmissionHeader{
+			Priority:                 int32(admissionpb.BulkNormalPri),
+			CreateTime:               timeutil.Now().UnixNano(),
+			Source:                   kvpb.AdmissionHeader_FROM_SQL,
+			NoMemoryReservedAtSource: true,
+		}, req)
+		if pErr != nil {
+			return errors.Wrap(pErr.GoError(), "checking upsert import for duplicate primary keys")
+		}
+		exportResp := resp.(*kvpb.ExportResponse)
+		for _, file := range exportResp.Files {
+			if err := func() error {
+				iter, err := storage.NewMemSSTIterator(file.SST, false /* verify */, storage.IterOptions{
+					KeyTypes:   storage.IterKeyTypePointsOnly,
+					LowerBound: file.Span.Key,
+					UpperBound: file.Span.EndKey,
+				})
+				if err != nil {
+					return err
+				}
+				defer iter.Close()
+				for iter.SeekGE(storage.MVCCKey{Key: file.Span.Key}); ; iter.Next() {
+					if ok, err := iter.Valid(); err != nil {
+						return err
+					} else if !ok {
+						return nil
+					}
+					v, err := iter.UnsafeValue()
+					if err != nil {
+						return err
+					}
+					key := iter.UnsafeKey().Key
+					if key.Equal(lastKey) {
+						if !sameUpsertValue(lastValue, v) {
+							n, err := keys.GetRowPrefixLength(key)
+							if err != nil {
+								return err
+							}
+							return duplicateUpsertKeyError(key[:n])
+						}
+						continue
+					}
+					lastKey = append(lastKey[:0], key...)
+					lastValue = append(lastValue[:0], v...)
+				}
+			}(); err != nil {
+				return err
+			}
+		}
+		span.Key = nil
+		if exportResp.ResumeSpan != nil {
+			span.Key = exportResp.ResumeSpan.Key
+		}
+	}
+	return nil
+}
+
+// upsertDuplicateCheckFileSize is the size of the SSTs returned by each
+// ExportRequest sent by checkUpsertDuplicates.
+const upsertDuplicateCheckFileSize = 16 << 20
+
+// sameUpsertValue returns whether two encoded revisions of a KV, either of
+// which may be a tombstone, have the same value.
+func sameUpsertValue(a, b []byte) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) == len(b)
+	}
+	return bytes.Equal(
+		roachpb.Value{RawBytes: a}.TagAndDataBytes(), roachpb.Value{RawBytes: b}.TagAndDataBytes(),
+	)
+}
+
+// encodeRow returns the KVs of the row with the given datums, ordered as the
+// columns in the fetch spec.
+func (u *importUpserter) encodeRow(
+	ctx context.Context, datums tree.Datums,
+) ([]roachpb.KeyValue, error) {
+	copy(u.conv.Datums, datums)
+	if err := u.conv.Row(ctx, 0 /* sourceID */, 0 /* rowIndex */); err != nil {
+		return nil, err
+	}
+	// Row only sends the batch once it is full, so send the rest of it now.
+	if err := u.conv.SendBatch(ctx); err != nil {
+		return nil, err
+	}
+	select {
+	case batch := <-u.convOutput:
+		return batch.KVs, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
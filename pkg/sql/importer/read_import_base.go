@@ -39,6 +39,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding/csv"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
@@ -93,6 +94,16 @@ func runImport(
 		return nil, err
 	}
 
+	var upserter *importUpserter
+	if spec.Upsert {
+		desc := tabledesc.NewBuilder(table.Desc).BuildImmutableTable()
+		upserter, err = newImportUpserter(ctx, &semaCtx, evalCtx, desc, flowCtx.Cfg.DB.KV(),
+			hlc.Timestamp{WallTime: spec.WalltimeNanos})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// This group holds the go routines that are responsible for producing KV
 	// batches and ingesting produced KVs.
 	group := ctxgroup.WithContext(ctx)
@@ -123,7 +134,7 @@ func runImport(
 	// at the end is one row containing an encoded BulkOpSummary.
 	var summary *kvpb.BulkOpSummary
 	group.GoCtx(func(ctx context.Context) error {
-		summary, err = ingestKvs(ctx, flowCtx, spec, processorID, table.Desc.Name, progCh, kvCh)
+		summary, err = ingestKvs(ctx, flowCtx, spec, processorID, table.Desc.Name, progCh, kvCh, upserter)
 		return err
 	})
 
@@ -148,7 +159,9 @@ func runImport(
 
 // makeFileReader creates a fileReader for the given format, applying
 // decompression for formats that need it (CSV, Avro, etc.) or providing
-// seekable access for formats that require it (Parquet).
+// seekable access for formats that require it (Parquet). Uncompressed dumps
+// are also given seekable access, so that the data of a table can be read
+// without reading the dump from its start.
 func makeFileReader(
 	ctx context.Context,
 	format roachpb.IOFileFormat,
@@ -161,30 +174,40 @@ func makeFileReader(
 	var randomReader ioctx.ReaderAtSeekerCloser
 	var counter *byteCounter
 
-	switch format.Format {
-	case roachpb.IOFileFormat_Parquet:
+	// This works with any cloud storage that supports offset reads.
+	openAt := func(ctx context.Context, offset int64, endHint int64) (ioctx.ReadCloserCtx, error) {
+		opts := cloud.ReadOptions{
+			Offset: offset,
+		}
+		// Set LengthHint if endHint is provided and valid.
+		if endHint > offset {
+			opts.LengthHint = endHint - offset
+		}
+		r, _, err := storage.ReadFile(ctx, "", opts)
+		return r, err
+	}
+
+	switch {
+	case format.Format == roachpb.IOFileFormat_Parquet:
 		// Parquet needs seekable, uncompressed access
 		// (compression is handled internally by Parquet)
 		if storage == nil {
 			// This shouldn't really happen, makeExternalStorage would have returned an error.
 			return nil, nil, errors.AssertionFailedf("storage must be non-nil for Parquet format")
 		}
-		// This works with any cloud storage that supports offset reads.
-		openAt := func(ctx context.Context, offset int64, endHint int64) (ioctx.ReadCloserCtx, error) {
-			opts := cloud.ReadOptions{
-				Offset: offset,
-			}
-			// Set LengthHint if endHint is provided and valid.
-			if endHint > offset {
-				opts.LengthHint = endHint - offset
-			}
-			r, _, err := storage.ReadFile(ctx, "", opts)
-			return r, err
-		}
 		randomReader = ioctx.NewRandomAccessReader(ctx, dataFileSize, openAt)
 		readCloser = randomReader
 		// counter = nil, since it is not very useful for random access files;
 		// we track progress on the rows read within a parquet file.
+	case (format.Format == roachpb.IOFileFormat_PgDump || format.Format == roachpb.IOFileFormat_MysqlDump) &&
+		storage != nil && dataFileSize > 0 &&
+		guessCompressionFromName(dataFile, format.Compression) == roachpb.IOFileFormat_None:
+		randomReader = ioctx.NewRandomAccessReader(ctx, dataFileSize, openAt)
+		counter = &byteCounter{r: randomReader}
+		readCloser = struct {
+			io.Reader
+			io.Closer
+		}{counter, randomReader}
 	default:
 		// Default sequential access.
 		source := ioctx.ReaderCtxAdapter(ctx, raw)
@@ -317,7 +340,8 @@ func readInputFiles(
 
 			var rejected chan string
 			if (format.Format == roachpb.IOFileFormat_CSV && format.SaveRejected) ||
-				(format.Format == roachpb.IOFileFormat_MysqlOutfile && format.SaveRejected) {
+				(format.Format == roachpb.IOFileFormat_MysqlOutfile && format.SaveRejected) ||
+				(format.Format == roachpb.IOFileFormat_NDJSON && format.SaveRejected) {
 				rejected = make(chan string)
 			}
 			dataFile := dataFile // copy for safe reference in Go routine
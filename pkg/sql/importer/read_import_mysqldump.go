@@ -0,0 +1,834 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+type mysqlTokenKind int
+
+const (
+	mysqlTokenEOF mysqlTokenKind = iota
+	// mysqlTokenWord is an unquoted identifier or keyword.
+	mysqlTokenWord
+	// mysqlTokenIdent is an identifier quoted with backticks.
+	mysqlTokenIdent
+	// mysqlTokenString is a string literal, whose value is unescaped.
+	mysqlTokenString
+	// mysqlTokenHex is a hexadecimal literal (0x... or X'...'), whose value
+	// is the decoded bytes.
+	mysqlTokenHex
+	// mysqlTokenBit is a bit literal (b'...'), whose value is the digits.
+	mysqlTokenBit
+	mysqlTokenNumber
+	// mysqlTokenPunct is any other character.
+	mysqlTokenPunct
+	// mysqlTokenDelimiter ends a statement.
+	mysqlTokenDelimiter
+)
+
+// mysqlToken is a token of a statement in a dump written by mysqldump.
+type mysqlToken struct {
+	kind mysqlTokenKind
+	val  string
+	// space is set if the token is preceded by whitespace or a comment.
+	space bool
+}
+
+// isWord returns whether the token is the specified unquoted keyword.
+func (t mysqlToken) isWord(word string) bool {
+	return t.kind == mysqlTokenWord && strings.EqualFold(t.val, word)
+}
+
+// isPunct returns whether the token is the specified character.
+func (t mysqlToken) isPunct(c string) bool {
+	return t.kind == mysqlTokenPunct && t.val == c
+}
+
+// mysqlDumpLexer splits a dump written by mysqldump into tokens, following
+// the lexical rules of MySQL in its default SQL mode. The content of
+// version-specific comments (/*!NNNNN ... */), which mysqldump uses to wrap
+// statements and clauses, is tokenized like MySQL executes it.
+type mysqlDumpLexer struct {
+	r       *bufio.Reader
+	maxSize int
+	// pos is the offset in the dump of the next byte to be read.
+	pos int64
+	// tokPos is the offset in the dump of the last token returned by Next.
+	tokPos int64
+	// delimiter ends statements. It is changed by the DELIMITER command of
+	// the mysql client, which mysqldump uses around triggers and routines.
+	delimiter string
+	// inVersionComment is set within a version-specific comment.
+	inVersionComment bool
+	// rec, if set, records the bytes which are read.
+	rec *bytes.Buffer
+	buf []byte
+}
+
+func newMysqlDumpLexer(r io.Reader, maxSize int) *mysqlDumpLexer {
+	return &mysqlDumpLexer{r: bufio.NewReader(r), maxSize: maxSize, delimiter: ";"}
+}
+
+func (l *mysqlDumpLexer) readByte() (byte, error) {
+	b, err := l.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	l.pos++
+	if l.rec != nil {
+		l.rec.WriteByte(b)
+	}
+	return b, nil
+}
+
+// peekByte returns the next byte without consuming it, or 0 at the end of
+// the dump.
+func (l *mysqlDumpLexer) peekByte() byte {
+	b, err := l.r.Peek(1)
+	if err != nil {
+		return 0
+	}
+	return b[0]
+}
+
+// peekIs returns whether the next bytes are s.
+func (l *mysqlDumpLexer) peekIs(s string) bool {
+	b, err := l.r.Peek(len(s))
+	return err == nil && string(b) == s
+}
+
+func (l *mysqlDumpLexer) skip(n int) error {
+	for i := 0; i < n; i++ {
+		if _, err := l.readByte(); err != nil {
+			return unexpectedEOF(err)
+		}
+	}
+	return nil
+}
+
+// appendByte appends a byte to the value of the token being read.
+func (l *mysqlDumpLexer) appendByte(b byte) error {
+	if len(l.buf) >= l.maxSize {
+		return wrapWithLineTooLongHint(errors.Newf("value of more than %d bytes", l.maxSize))
+	}
+	l.buf = append(l.buf, b)
+	return nil
+}
+
+func isMysqlWordByte(b byte) bool {
+	return b == '_' || b == '$' || b >= 0x80 ||
+		(b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+// Next returns the next token, which is mysqlTokenEOF at the end of the dump.
+func (l *mysqlDumpLexer) Next() (mysqlToken, error) {
+	var space bool
+	for {
+		l.tokPos = l.pos
+		b, err := l.readByte()
+		if err == io.EOF {
+			return mysqlToken{kind: mysqlTokenEOF, space: space}, nil
+		} else if err != nil {
+			return mysqlToken{}, err
+		}
+		switch {
+		case b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f':
+			space = true
+		case b == '#' || (b == '-' && l.peekByte() == '-' && l.isCommentDashes()):
+			if err := l.skipLine(); err != nil {
+				return mysqlToken{}, err
+			}
+			space = true
+		case b == '/' && l.peekByte() == '*':
+			if err := l.skip(1); err != nil {
+				return mysqlToken{}, err
+			}
+			if l.peekByte() == '!' {
+				// The optional version number is followed by the content of the
+				// comment.
+				if err := l.skip(1); err != nil {
+					return mysqlToken{}, err
+				}
+				for c := l.peekByte(); c >= '0' && c <= '9'; c = l.peekByte() {
+					if err := l.skip(1); err != nil {
+						return mysqlToken{}, err
+					}
+				}
+				l.inVersionComment = true
+			} else if err := l.skipComment(); err != nil {
+				return mysqlToken{}, err
+			}
+			space = true
+		case b == '*' && l.inVersionComment && l.peekByte() == '/':
+			if err := l.skip(1); err != nil {
+				return mysqlToken{}, err
+			}
+			l.inVersionComment = false
+			space = true
+		case b == l.delimiter[0] && l.peekIs(l.delimiter[1:]):
+			if err := l.skip(len(l.delimiter) - 1); err != nil {
+				return mysqlToken{}, err
+			}
+			return mysqlToken{kind: mysqlTokenDelimiter, val: l.delimiter, space: space}, nil
+		case b == '`':
+			tok, err := l.readQuoted(b, mysqlTokenIdent)
+			tok.space = space
+			return tok, err
+		case b == '\'' || b == '"':
+			tok, err := l.readQuoted(b, mysqlTokenString)
+			tok.space = space
+			return tok, err
+		case b == '0' && (l.peekByte() == 'x' || l.peekByte() == 'X'):
+			if err := l.skip(1); err != nil {
+				return mysqlToken{}, err
+			}
+			tok, err := l.readHex(0 /* quote */)
+			tok.space = space
+			return tok, err
+		case b >= '0' && b <= '9':
+			tok, err := l.readNumber(b)
+			tok.space = space
+			return tok, err
+		case isMysqlWordByte(b):
+			if (b == 'x' || b == 'X') && l.peekByte() == '\'' {
+				if err := l.skip(1); err != nil {
+					return mysqlToken{}, err
+				}
+				tok, err := l.readHex('\'')
+				tok.space = space
+				return tok, err
+			}
+			if (b == 'b' || b == 'B') && l.peekByte() == '\'' {
+				if err := l.skip(1); err != nil {
+					return mysqlToken{}, err
+				}
+				tok, err := l.readQuoted('\'', mysqlTokenBit)
+				tok.space = space
+				return tok, err
+			}
+			l.buf = append(l.buf[:0], b)
+			for isMysqlWordByte(l.peekByte()) {
+				c, err := l.readByte()
+				if err != nil {
+					return mysqlToken{}, err
+				}
+				if err := l.appendByte(c); err != nil {
+					return mysqlToken{}, err
+				}
+			}
+			if b == '_' && l.isIntroducer() {
+				// A character set introducer, such as _binary or _utf8mb4, which
+				// precedes a literal, is dropped.
+				space = true
+				continue
+			}
+			return mysqlToken{kind: mysqlTokenWord, val: string(l.buf), space: space}, nil
+		default:
+			return mysqlToken{kind: mysqlTokenPunct, val: string(b), space: space}, nil
+		}
+	}
+}
+
+// isCommentDashes returns whether a dash which was read, followed by a dash,
+// starts a comment, which requires the second dash to be followed by a
+// whitespace or control character.
+func (l *mysqlDumpLexer) isCommentDashes() bool {
+	b, err := l.r.Peek(2)
+	if err != nil {
+		return len(b) == 1
+	}
+	return b[1] <= ' '
+}
+
+// isIntroducer returns whether the word which was read is followed by a
+// literal.
+func (l *mysqlDumpLexer) isIntroducer() bool {
+	b, _ := l.r.Peek(16)
+	b = bytes.TrimLeft(b, " \t")
+	return len(b) > 0 && (b[0] == '\'' || b[0] == '"' ||
+		bytes.HasPrefix(b, []byte("0x")) || bytes.HasPrefix(b, []byte("X'")) ||
+		bytes.HasPrefix(b, []byte("x'")))
+}
+
+func (l *mysqlDumpLexer) skipLine() error {
+	for {
+		b, err := l.readByte()
+		if err == io.EOF || b == '\n' {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+func (l *mysqlDumpLexer) skipComment() error {
+	for {
+		b, err := l.readByte()
+		if err != nil {
+			return errors.Wrap(unexpectedEOF(err), "unterminated comment")
+		}
+		if b == '*' && l.peekByte() == '/' {
+			return l.skip(1)
+		}
+	}
+}
+
+// readQuoted reads a literal or an identifier quoted with the specified
+// character, which has been read. Backslash escapes are only interpreted in
+// string literals.
+func (l *mysqlDumpLexer) readQuoted(quote byte, kind mysqlTokenKind) (mysqlToken, error) {
+	l.buf = l.buf[:0]
+	for {
+		b, err := l.readByte()
+		if err != nil {
+			return mysqlToken{}, errors.Wrap(unexpectedEOF(err), "unterminated quoted string")
+		}
+		switch {
+		case b == quote:
+			if l.peekByte() != quote {
+				return mysqlToken{kind: kind, val: string(l.buf)}, nil
+			}
+			if err := l.skip(1); err != nil {
+				return mysqlToken{}, err
+			}
+		case b == '\\' && kind == mysqlTokenString:
+			if b, err = l.readByte(); err != nil {
+				return mysqlToken{}, errors.Wrap(unexpectedEOF(err), "unterminated quoted string")
+			}
+			switch b {
+			case '0':
+				b = 0
+			case 'b':
+				b = '\b'
+			case 'n':
+				b = '\n'
+			case 'r':
+				b = '\r'
+			case 't':
+				b = '\t'
+			case 'Z':
+				b = 0x1a
+			case '%', '_':
+				// These escapes are kept for LIKE patterns.
+				if err := l.appendByte('\\'); err != nil {
+					return mysqlToken{}, err
+				}
+			}
+		}
+		if err := l.appendByte(b); err != nil {
+			return mysqlToken{}, err
+		}
+	}
+}
+
+// readHex reads a hexadecimal literal, whose prefix has been read. If quote is
+// non-zero, the digits are followed by it.
+func (l *mysqlDumpLexer) readHex(quote byte) (mysqlToken, error) {
+	l.buf = l.buf[:0]
+	for {
+		b := l.peekByte()
+		if quote != 0 && b == quote {
+			if err := l.skip(1); err != nil {
+				return mysqlToken{}, err
+			}
+			break
+		}
+		if !isMysqlWordByte(b) {
+			if quote != 0 {
+				return mysqlToken{}, errors.New("unterminated hexadecimal literal")
+			}
+			break
+		}
+		if _, err := l.readByte(); err != nil {
+			return mysqlToken{}, err
+		}
+		if err := l.appendByte(b); err != nil {
+			return mysqlToken{}, err
+		}
+	}
+	digits := l.buf
+	if len(digits)%2 != 0 {
+		digits = append([]byte{'0'}, digits...)
+	}
+	val := make([]byte, hex.DecodedLen(len(digits)))
+	if _, err := hex.Decode(val, digits); err != nil {
+		return mysqlToken{}, errors.Wrap(err, "invalid hexadecimal literal")
+	}
+	return mysqlToken{kind: mysqlTokenHex, val: string(val)}, nil
+}
+
+// readNumber reads a numeric literal, whose first digit has been read.
+func (l *mysqlDumpLexer) readNumber(first byte) (mysqlToken, error) {
+	l.buf = append(l.buf[:0], first)
+	for {
+		b := l.peekByte()
+		last := l.buf[len(l.buf)-1]
+		if !isMysqlWordByte(b) && b != '.' &&
+			!((b == '-' || b == '+') && (last == 'e' || last == 'E')) {
+			return mysqlToken{kind: mysqlTokenNumber, val: string(l.buf)}, nil
+		}
+		if _, err := l.readByte(); err != nil {
+			return mysqlToken{}, err
+		}
+		if err := l.appendByte(b); err != nil {
+			return mysqlToken{}, err
+		}
+	}
+}
+
+// readDelimiterCommand reads the argument of a DELIMITER command, which
+// extends to the end of the line.
+func (l *mysqlDumpLexer) readDelimiterCommand() error {
+	var arg []byte
+	for {
+		b, err := l.readByte()
+		if err == io.EOF || b == '\n' {
+			break
+		} else if err != nil {
+			return err
+		}
+		arg = append(arg, b)
+	}
+	delimiter := strings.TrimSpace(string(arg))
+	if delimiter == "" {
+		return errors.New("DELIMITER requires an argument")
+	}
+	l.delimiter = delimiter
+	return nil
+}
+
+// mysqlInsert describes an INSERT statement of a dump.
+type mysqlInsert struct {
+	// table is the name of the table, without its database.
+	table string
+	cols  []string
+}
+
+// mysqlDumpStatement is a statement of a dump written by mysqldump.
+type mysqlDumpStatement struct {
+	// text is the text of the statement. For an INSERT, it only extends up to
+	// the VALUES keyword.
+	text string
+	// tokens are the tokens of the statement, up to the VALUES keyword of an
+	// INSERT.
+	tokens []mysqlToken
+	// offset is the offset of the statement in the dump.
+	offset int64
+	// insert is set for an INSERT or REPLACE statement, whose rows follow.
+	insert *mysqlInsert
+}
+
+// mysqlDumpStream streams the statements of a dump written by mysqldump. The
+// rows of INSERT statements are streamed one at a time, so that the size of
+// a statement is not limited.
+type mysqlDumpStream struct {
+	lex *mysqlDumpLexer
+	// inRows is set while the rows of an INSERT statement returned by Next
+	// have not all been consumed.
+	inRows bool
+	rec    bytes.Buffer
+}
+
+func newMysqlDumpStream(r io.Reader, maxSize int) *mysqlDumpStream {
+	return &mysqlDumpStream{lex: newMysqlDumpLexer(r, maxSize)}
+}
+
+// Next returns the next statement of the dump, or io.EOF if there are none
+// left. If it is an INSERT, its rows must be consumed with NextRow or
+// SkipRows before Next is called again.
+func (s *mysqlDumpStream) Next() (mysqlDumpStatement, error) {
+	if s.inRows {
+		return mysqlDumpStatement{}, errors.AssertionFailedf("INSERT rows were not consumed")
+	}
+	for {
+		s.rec.Reset()
+		s.lex.rec = &s.rec
+		stmt := mysqlDumpStatement{offset: s.lex.pos}
+		// textPos is the offset of the first token of the statement.
+		var textPos int64
+		for {
+			tok, err := s.lex.Next()
+			if err != nil {
+				return mysqlDumpStatement{}, err
+			}
+			if tok.kind == mysqlTokenEOF || tok.kind == mysqlTokenDelimiter {
+				if tok.kind == mysqlTokenEOF && len(stmt.tokens) == 0 {
+					s.lex.rec = nil
+					return mysqlDumpStatement{}, io.EOF
+				}
+				break
+			}
+			if len(stmt.tokens) == 0 && tok.isWord("DELIMITER") {
+				if err := s.lex.readDelimiterCommand(); err != nil {
+					return mysqlDumpStatement{}, err
+				}
+				break
+			}
+			if len(stmt.tokens) == 0 {
+				textPos = s.lex.tokPos
+			}
+			stmt.tokens = append(stmt.tokens, tok)
+			if tok.isWord("VALUES") || tok.isWord("VALUE") {
+				if stmt.insert, err = parseMysqlInsert(stmt.tokens); err != nil {
+					return mysqlDumpStatement{}, err
+				}
+				if stmt.insert != nil {
+					s.inRows = true
+					break
+				}
+			}
+		}
+		if len(stmt.tokens) == 0 {
+			continue
+		}
+		s.lex.rec = nil
+		text := strings.TrimSpace(s.rec.String()[textPos-stmt.offset:])
+		stmt.text = strings.TrimSpace(strings.TrimSuffix(text, s.lex.delimiter))
+		return stmt, nil
+	}
+}
+
+// parseMysqlInsert returns the description of an INSERT statement, whose
+// tokens up to the VALUES keyword are given, or nil if the tokens are not
+// those of an INSERT statement.
+func parseMysqlInsert(tokens []mysqlToken) (*mysqlInsert, error) {
+	t := &mysqlTokens{toks: tokens}
+	if !t.acceptWord("INSERT") && !t.acceptWord("REPLACE") {
+		return nil, nil
+	}
+	t.acceptWord("LOW_PRIORITY")
+	t.acceptWord("DELAYED")
+	t.acceptWord("HIGH_PRIORITY")
+	t.acceptWord("IGNORE")
+	t.acceptWord("INTO")
+	name, err := t.qualifiedName()
+	if err != nil {
+		return nil, err
+	}
+	insert := &mysqlInsert{table: name}
+	if t.acceptPunct("(") {
+		for {
+			col, err := t.ident()
+			if err != nil {
+				return nil, err
+			}
+			insert.cols = append(insert.cols, col)
+			if t.acceptPunct(")") {
+				break
+			}
+			if !t.acceptPunct(",") {
+				return nil, errors.Errorf("unexpected %q in column list of INSERT", t.peek().val)
+			}
+		}
+	}
+	if !t.acceptWord("VALUES") && !t.acceptWord("VALUE") {
+		return nil, errors.Errorf("unexpected %q in INSERT", t.peek().val)
+	}
+	return insert, nil
+}
+
+// NextRow returns the next row of the INSERT statement last returned by Next.
+// It returns false once the rows of the statement have been consumed.
+func (s *mysqlDumpStream) NextRow() (copyData, bool, error) {
+	if !s.inRows {
+		return nil, false, nil
+	}
+	tok, err := s.lex.Next()
+	if err != nil {
+		return nil, false, err
+	}
+	if !tok.isPunct("(") {
+		return nil, false, errors.Errorf("expected a row of values, found %q", tok.val)
+	}
+	var row copyData
+	for {
+		v, err := s.nextValue()
+		if err != nil {
+			return nil, false, err
+		}
+		row = append(row, v)
+		if tok, err = s.lex.Next(); err != nil {
+			return nil, false, err
+		}
+		if tok.isPunct(")") {
+			break
+		}
+		if !tok.isPunct(",") {
+			return nil, false, errors.Errorf("unexpected %q in row of values", tok.val)
+		}
+	}
+	if tok, err = s.lex.Next(); err != nil {
+		return nil, false, err
+	}
+	switch {
+	case tok.isPunct(","):
+	case tok.kind == mysqlTokenDelimiter || tok.kind == mysqlTokenEOF:
+		s.inRows = false
+	case tok.isWord("ON"):
+		return nil, false, errors.New("INSERT ... ON DUPLICATE KEY UPDATE is not supported")
+	default:
+		return nil, false, errors.Errorf("unexpected %q after row of values", tok.val)
+	}
+	return row, true, nil
+}
+
+// nextValue returns the next value of a row, which is nil for NULL.
+func (s *mysqlDumpStream) nextValue() (*string, error) {
+	tok, err := s.lex.Next()
+	if err != nil {
+		return nil, err
+	}
+	var sign string
+	if tok.isPunct("-") || tok.isPunct("+") {
+		sign = tok.val
+		if tok, err = s.lex.Next(); err != nil {
+			return nil, err
+		}
+		if tok.kind != mysqlTokenNumber {
+			return nil, errors.Errorf("unexpected %q after sign", tok.val)
+		}
+	}
+	switch tok.kind {
+	case mysqlTokenWord:
+		switch {
+		case tok.isWord("NULL"):
+			return nil, nil
+		case tok.isWord("TRUE"):
+			v := "1"
+			return &v, nil
+		case tok.isWord("FALSE"):
+			v := "0"
+			return &v, nil
+		}
+	case mysqlTokenNumber, mysqlTokenString, mysqlTokenHex, mysqlTokenBit:
+		v := sign + tok.val
+		return &v, nil
+	}
+	return nil, errors.Errorf("unsupported value %q", tok.val)
+}
+
+// SkipRows skips over the rows of the INSERT statement last returned by Next.
+func (s *mysqlDumpStream) SkipRows() error {
+	for s.inRows {
+		tok, err := s.lex.Next()
+		if err != nil {
+			return err
+		}
+		if tok.kind == mysqlTokenDelimiter || tok.kind == mysqlTokenEOF {
+			s.inRows = false
+		}
+	}
+	return nil
+}
+
+// mysqlDumpReader is an inputConverter which imports the rows of the INSERT
+// statements of a single table from a dump written by mysqldump. The other
+// statements of the dump are ignored.
+type mysqlDumpReader struct {
+	importCtx *parallelImportContext
+	opts      roachpb.MysqlDumpOptions
+	table     string
+}
+
+var _ inputConverter = &mysqlDumpReader{}
+
+func newMysqlDumpReader(
+	semaCtx *tree.SemaContext,
+	opts roachpb.MysqlDumpOptions,
+	kvCh chan row.KVBatch,
+	walltime int64,
+	parallelism int,
+	tableDesc catalog.TableDescriptor,
+	targetCols tree.NameList,
+	evalCtx *eval.Context,
+	db *kv.DB,
+) (*mysqlDumpReader, error) {
+	table := tableDesc.GetName()
+	if opts.Table != "" {
+		tn, err := parser.ParseQualifiedTableName(opts.Table)
+		if err != nil {
+			return nil, err
+		}
+		// mysqldump does not qualify the tables of its INSERT statements.
+		table = string(tn.ObjectName)
+	}
+	return &mysqlDumpReader{
+		importCtx: &parallelImportContext{
+			semaCtx:    semaCtx,
+			walltime:   walltime,
+			numWorkers: parallelism,
+			evalCtx:    evalCtx,
+			tableDesc:  tableDesc,
+			targetCols: targetCols,
+			kvCh:       kvCh,
+			db:         db,
+		},
+		opts:  opts,
+		table: table,
+	}, nil
+}
+
+func (d *mysqlDumpReader) readFiles(
+	ctx context.Context,
+	dataFiles map[int32]string,
+	resumePos map[int32]int64,
+	format roachpb.IOFileFormat,
+	makeExternalStorage cloud.ExternalStorageFactory,
+	user username.SQLUsername,
+) error {
+	return readInputFiles(ctx, dataFiles, resumePos, format, d.readFile, makeExternalStorage, user)
+}
+
+func (d *mysqlDumpReader) readFile(
+	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected chan string,
+) error {
+	if err := seekDumpInput(input, d.opts.Offset); err != nil {
+		return err
+	}
+	producer := &mysqlDumpProducer{
+		input:  input,
+		stream: newMysqlDumpStream(input, int(d.opts.MaxRowSize)),
+		table:  d.table,
+		offset: d.opts.Offset,
+	}
+	fileCtx := &importFileContext{
+		source:   inputIdx,
+		skip:     resumePos,
+		rejected: rejected,
+	}
+	return runParallelImport(ctx, d.importCtx, fileCtx, producer, &mysqlDumpConsumer{})
+}
+
+type mysqlDumpProducer struct {
+	input  *fileReader
+	stream *mysqlDumpStream
+	table  string
+	// offset is the offset of the first INSERT statement of the table, if it
+	// is known. It is only recorded if the INSERT statements of the table are
+	// consecutive, as mysqldump writes them, in which case the rest of the dump
+	// is not read.
+	offset int64
+	// started is set once an INSERT statement of the table has been read.
+	started bool
+	done    bool
+	row     copyData
+	err     error
+}
+
+var _ importRowProducer = &mysqlDumpProducer{}
+
+// Scan implements importRowProducer
+func (p *mysqlDumpProducer) Scan() bool {
+	for !p.done {
+		var ok bool
+		if p.row, ok, p.err = p.stream.NextRow(); p.err != nil {
+			return false
+		} else if ok {
+			return true
+		}
+		stmt, err := p.stream.Next()
+		if err == io.EOF {
+			p.done = true
+			break
+		} else if err != nil {
+			p.err = err
+			return false
+		}
+		if stmt.insert != nil && stmt.insert.table == p.table {
+			p.started = true
+			continue
+		}
+		switch {
+		case p.offset != 0 && p.started:
+			p.done = true
+		case p.offset != 0:
+			p.err = errors.Errorf(
+				"expected an INSERT into table %s at offset %d of dump, found %q",
+				p.table, p.offset, stmt.text)
+			return false
+		case stmt.insert != nil:
+			if p.err = p.stream.SkipRows(); p.err != nil {
+				return false
+			}
+		}
+	}
+	if !p.started {
+		p.err = errors.Errorf("no INSERT statements for table %s in dump", p.table)
+	}
+	return false
+}
+
+// Err implements importRowProducer
+func (p *mysqlDumpProducer) Err() error {
+	return p.err
+}
+
+// Skip implements importRowProducer
+func (p *mysqlDumpProducer) Skip() error {
+	return nil // no-op
+}
+
+// Row implements importRowProducer
+func (p *mysqlDumpProducer) Row() (interface{}, error) {
+	return p.row, p.err
+}
+
+// Progress implements importRowProducer
+func (p *mysqlDumpProducer) Progress() float32 {
+	return p.input.ReadFraction()
+}
+
+type mysqlDumpConsumer struct{}
+
+var _ importRowConsumer = &mysqlDumpConsumer{}
+
+// FillDatums implements importRowConsumer
+func (c *mysqlDumpConsumer) FillDatums(
+	ctx context.Context, row interface{}, rowNum int64, conv *row.DatumRowConverter,
+) error {
+	data := row.(copyData)
+	if len(data) != len(conv.VisibleColTypes) {
+		return newImportRowError(fmt.Errorf(
+			"unexpected number of columns, expected %d values, got %d",
+			len(conv.VisibleColTypes), len(data)), data.String(), rowNum)
+	}
+	for i, s := range data {
+		if s == nil {
+			conv.Datums[i] = tree.DNull
+			continue
+		}
+		var err error
+		conv.Datums[i], err = mysqlStrToDatum(conv.EvalCtx, *s, conv.VisibleColTypes[i])
+		if err != nil {
+			col := conv.VisibleCols[i]
+			return newImportRowError(errors.Wrapf(
+				err,
+				"encountered error when attempting to parse %q as %s",
+				col.GetName(), col.GetType().SQLString(),
+			), data.String(), rowNum)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+var testMysqlDump = strings.Join([]string{
+	"-- MySQL dump 10.13  Distrib 8.0.36, for Linux (x86_64)",
+	"/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;",
+	"/*!40101 SET NAMES utf8mb4 */;",
+	"",
+	"--",
+	"-- Table structure for table `a`",
+	"--",
+	"",
+	"DROP TABLE IF EXISTS `a`;",
+	"CREATE TABLE `a` (",
+	"  `id` int NOT NULL AUTO_INCREMENT,",
+	"  `s` varchar(10) DEFAULT NULL COMMENT 'the s',",
+	"  `b_id` int DEFAULT NULL,",
+	"  PRIMARY KEY (`id`),",
+	"  KEY `a_b` (`b_id`),",
+	"  CONSTRAINT `a_fk` FOREIGN KEY (`b_id`) REFERENCES `b` (`id`),",
+	"  CONSTRAINT `a_chk` CHECK ((`id` > 0))",
+	") ENGINE=InnoDB AUTO_INCREMENT=4 DEFAULT CHARSET=utf8mb4;",
+	"",
+	"LOCK TABLES `a` WRITE;",
+	"/*!40000 ALTER TABLE `a` DISABLE KEYS */;",
+	"INSERT INTO `a` VALUES (1,'x; y',NULL),(2,'it\\'s',3);",
+	"INSERT INTO `a` VALUES (3,NULL,NULL);",
+	"/*!40000 ALTER TABLE `a` ENABLE KEYS */;",
+	"UNLOCK TABLES;",
+	"",
+	"DROP TABLE IF EXISTS `b`;",
+	"CREATE TABLE `b` (",
+	"  `id` int NOT NULL,",
+	"  `v` blob,",
+	"  PRIMARY KEY (`id`)",
+	") ENGINE=InnoDB;",
+	"",
+	"LOCK TABLES `b` WRITE;",
+	"INSERT INTO `b` VALUES (3,_binary 0x6869);",
+	"UNLOCK TABLES;",
+	"",
+	"DELIMITER ;;",
+	"CREATE TRIGGER `t` BEFORE INSERT ON `a` FOR EACH ROW BEGIN SET NEW.s = 'z'; END ;;",
+	"DELIMITER ;",
+	"",
+}, "\n")
+
+// describeMysqlDumpItems returns a description of each item: its SQL, the
+// table of its data, or the reason it is skipped.
+func describeMysqlDumpItems(items []mysqlDumpItem) []string {
+	var res []string
+	for _, item := range items {
+		switch {
+		case item.insert != nil:
+			res = append(res, "data "+item.insert.table)
+		case item.skipReason != "":
+			res = append(res, "skip "+item.stmt+": "+item.skipReason)
+		default:
+			res = append(res, item.sql)
+		}
+	}
+	return res
+}
+
+func TestReadMysqlDumpItems(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	items, err := readMysqlDumpItems(strings.NewReader(testMysqlDump), defaultScanBuffer)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`CREATE SEQUENCE a_id_seq START 4`,
+		`CREATE TABLE a (id INT4 NOT NULL DEFAULT nextval('a_id_seq'), s VARCHAR(10) DEFAULT NULL, ` +
+			`b_id INT4 DEFAULT NULL, PRIMARY KEY (id), INDEX a_b (b_id), CONSTRAINT a_chk CHECK ((id > 0)))`,
+		`ALTER SEQUENCE a_id_seq OWNED BY a.id`,
+		`COMMENT ON COLUMN a.s IS 'the s'`,
+		`CREATE TABLE b (id INT4 NOT NULL, v BYTES, PRIMARY KEY (id))`,
+		"skip CREATE TRIGGER `t` BEFORE INSERT ON `a` FOR EACH ROW BEGIN SET NEW.s = 'z'; END: " +
+			"unsupported statement",
+		`data a`,
+		`data b`,
+		`ALTER TABLE a ADD CONSTRAINT a_fk FOREIGN KEY (b_id) REFERENCES b (id)`,
+	}, describeMysqlDumpItems(items))
+
+	// The data of each table starts at its first INSERT statement.
+	for _, item := range items {
+		if item.insert == nil {
+			continue
+		}
+		require.NotZero(t, item.offset)
+		require.True(t, strings.HasPrefix(
+			strings.TrimSpace(testMysqlDump[item.offset:]), "INSERT INTO `"+item.insert.table+"`"))
+	}
+
+	// The data of a table whose INSERT statements are not consecutive has to
+	// be read from the start of the dump.
+	items, err = readMysqlDumpItems(strings.NewReader(strings.Join([]string{
+		"INSERT INTO `a` VALUES (1);",
+		"INSERT INTO `b` VALUES (2);",
+		"INSERT INTO `a` VALUES (3);",
+	}, "\n")), defaultScanBuffer)
+	require.NoError(t, err)
+	require.Equal(t, []string{"data a", "data b"}, describeMysqlDumpItems(items))
+	require.Zero(t, items[0].offset)
+	require.NotZero(t, items[1].offset)
+}
+
+func TestMysqlDumpStreamRows(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	s := newMysqlDumpStream(strings.NewReader(
+		"/*!40000 ALTER TABLE `t` DISABLE KEYS */;\n"+
+			"INSERT INTO `db`.`t` (`x`, y) VALUES (-1.5e-3,\"a\\\"b\\n\"),(TRUE,b'101'),\n"+
+			"(X'41','it''s'),(NULL,_utf8mb4'#not -- a comment');",
+	), defaultScanBuffer)
+	stmt, err := s.Next()
+	require.NoError(t, err)
+	require.Equal(t, "ALTER TABLE `t` DISABLE KEYS */", stmt.text)
+	require.Nil(t, stmt.insert)
+
+	stmt, err = s.Next()
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO `db`.`t` (`x`, y) VALUES", stmt.text)
+	require.Equal(t, &mysqlInsert{table: "t", cols: []string{"x", "y"}}, stmt.insert)
+	var rows []string
+	for {
+		row, ok, err := s.NextRow()
+		require.NoError(t, err)
+		if !ok {
+			break
+		}
+		rows = append(rows, row.String())
+	}
+	require.Equal(t, []string{
+		"\"-1.5e-3\"\t\"a\\\"b\\n\"",
+		"\"1\"\t\"101\"",
+		"\"A\"\t\"it's\"",
+		"\\N\t\"#not -- a comment\"",
+	}, rows)
+
+	_, err = s.Next()
+	require.ErrorContains(t, err, "EOF")
+}
+
+func TestMysqlDumpProducer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	items, err := readMysqlDumpItems(strings.NewReader(testMysqlDump), defaultScanBuffer)
+	require.NoError(t, err)
+	offsets := make(map[string]int64)
+	for _, item := range items {
+		if item.insert != nil {
+			offsets[item.insert.table] = item.offset
+		}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		table  string
+		offset int64
+		rows   []string
+		err    string
+	}{
+		{
+			name:   "a at offset",
+			table:  "a",
+			offset: offsets["a"],
+			rows:   []string{"\"1\"\t\"x; y\"\t\\N", "\"2\"\t\"it's\"\t\"3\"", "\"3\"\t\\N\t\\N"},
+		},
+		{name: "b at offset", table: "b", offset: offsets["b"], rows: []string{"\"3\"\t\"hi\""}},
+		{name: "b from start", table: "b", rows: []string{"\"3\"\t\"hi\""}},
+		{
+			name:   "wrong offset",
+			table:  "b",
+			offset: offsets["a"],
+			err:    "expected an INSERT into table b at offset",
+		},
+		{name: "missing", table: "c", err: "no INSERT statements for table c in dump"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &mysqlDumpProducer{
+				input:  &fileReader{},
+				stream: newMysqlDumpStream(strings.NewReader(testMysqlDump[tc.offset:]), defaultScanBuffer),
+				table:  tc.table,
+				offset: tc.offset,
+			}
+			var rows []string
+			for p.Scan() {
+				row, err := p.Row()
+				require.NoError(t, err)
+				rows = append(rows, row.(copyData).String())
+			}
+			if tc.err != "" {
+				require.ErrorContains(t, p.Err(), tc.err)
+				return
+			}
+			require.NoError(t, p.Err())
+			require.Equal(t, tc.rows, rows)
+		})
+	}
+}
+
+func TestTranslateMysqlCreateTable(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		name        string
+		stmt        string
+		create      []string
+		foreignKeys []string
+		skipped     [][2]string
+		err         string
+	}{
+		{
+			name: "columns",
+			stmt: "CREATE TABLE `t` (" +
+				"`a` int unsigned NOT NULL, " +
+				"`e` enum('x','y') DEFAULT 'x', " +
+				"`c` int CHECK (`c` > 0) NOT ENFORCED, " +
+				"`d` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP, " +
+				"`g` int GENERATED ALWAYS AS ((`a` + 1)) VIRTUAL, " +
+				"`z` date NOT NULL DEFAULT '0000-00-00', " +
+				"PRIMARY KEY (`a`), " +
+				"UNIQUE KEY `t_d` (`d`), " +
+				"FULLTEXT KEY `ft` (`e`), " +
+				"CONSTRAINT `chk` CHECK ((`a` < 10)) ENFORCED, " +
+				"CONSTRAINT `off` CHECK ((`a` > 1)) /*!80016 NOT ENFORCED */" +
+				") ENGINE=InnoDB COMMENT='tab'",
+			create: []string{
+				"CREATE TABLE t (a INT8 NOT NULL, e STRING CHECK (e IN ('x','y')) DEFAULT 'x', c INT4, " +
+					"d TIMESTAMP DEFAULT current_timestamp() ON UPDATE current_timestamp(), " +
+					"g INT4 AS ((a + 1)) VIRTUAL, z DATE NOT NULL, PRIMARY KEY (a), UNIQUE INDEX t_d (d), " +
+					"CONSTRAINT chk CHECK ((a < 10)))",
+				"COMMENT ON TABLE t IS 'tab'",
+			},
+			skipped: [][2]string{{"FULLTEXT KEY ft (e)", "FULLTEXT index is not supported"}},
+		},
+		{
+			name: "auto increment",
+			stmt: "CREATE TABLE IF NOT EXISTS `db`.`t` (" +
+				"`id` int unsigned NOT NULL AUTO_INCREMENT, KEY (`id`(10) DESC)" +
+				") AUTO_INCREMENT=7",
+			create: []string{
+				"CREATE SEQUENCE t_id_seq START 7",
+				"CREATE TABLE IF NOT EXISTS t (id INT8 NOT NULL DEFAULT nextval('t_id_seq'), INDEX (id DESC))",
+				"ALTER SEQUENCE t_id_seq OWNED BY t.id",
+			},
+		},
+		{
+			name:        "foreign key",
+			stmt:        "CREATE TABLE `c` (`p` int, FOREIGN KEY (`p`) REFERENCES `p` (`id`) ON DELETE CASCADE)",
+			create:      []string{"CREATE TABLE c (p INT4)"},
+			foreignKeys: []string{"ALTER TABLE c ADD FOREIGN KEY (p) REFERENCES p (id) ON DELETE CASCADE"},
+		},
+		{
+			name: "unsupported type",
+			stmt: "CREATE TABLE `t` (`p` point)",
+			err:  "column p: column type point is not supported",
+		},
+		{
+			name: "like",
+			stmt: "CREATE TABLE `t2` LIKE `t`",
+			err:  "CREATE TABLE without a column list is not supported",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stmt, err := newMysqlDumpStream(strings.NewReader(tc.stmt), defaultScanBuffer).Next()
+			require.NoError(t, err)
+			res, err := translateMysqlCreateTable(&mysqlTokens{toks: stmt.tokens})
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.create, res.create)
+			require.Equal(t, tc.foreignKeys, res.foreignKeys)
+			require.Equal(t, tc.skipped, res.skipped)
+		})
+	}
+}
@@ -0,0 +1,286 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/errors"
+)
+
+// ndjsonInputReader imports newline-delimited JSON, in which each line holds
+// a JSON object. The columns are populated by name from the top-level keys of
+// the objects, or from the values at the paths given in the options.
+type ndjsonInputReader struct {
+	importCtx *parallelImportContext
+	opts      roachpb.NDJSONOptions
+	columns   ndjsonColumns
+}
+
+var _ inputConverter = &ndjsonInputReader{}
+
+func newNDJSONInputReader(
+	semaCtx *tree.SemaContext,
+	kvCh chan row.KVBatch,
+	opts roachpb.NDJSONOptions,
+	walltime int64,
+	parallelism int,
+	tableDesc catalog.TableDescriptor,
+	targetCols tree.NameList,
+	evalCtx *eval.Context,
+	seqChunkProvider *row.SeqChunkProvider,
+	db *kv.DB,
+) (*ndjsonInputReader, error) {
+	columns, err := makeNDJSONColumns(tableDesc, targetCols, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &ndjsonInputReader{
+		importCtx: &parallelImportContext{
+			semaCtx:          semaCtx,
+			walltime:         walltime,
+			numWorkers:       parallelism,
+			evalCtx:          evalCtx,
+			tableDesc:        tableDesc,
+			targetCols:       targetCols,
+			kvCh:             kvCh,
+			seqChunkProvider: seqChunkProvider,
+			db:               db,
+		},
+		opts:    opts,
+		columns: columns,
+	}, nil
+}
+
+func (n *ndjsonInputReader) readFiles(
+	ctx context.Context,
+	dataFiles map[int32]string,
+	resumePos map[int32]int64,
+	format roachpb.IOFileFormat,
+	makeExternalStorage cloud.ExternalStorageFactory,
+	user username.SQLUsername,
+) error {
+	return readInputFiles(ctx, dataFiles, resumePos, format, n.readFile, makeExternalStorage, user)
+}
+
+func (n *ndjsonInputReader) readFile(
+	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected chan string,
+) error {
+	s := bufio.NewScanner(input)
+	s.Buffer(nil, int(n.opts.MaxRowSize))
+	producer := &ndjsonRowProducer{
+		scanner:  s,
+		progress: func() float32 { return input.ReadFraction() },
+	}
+	consumer := &ndjsonRowConsumer{
+		opts:    &n.opts,
+		columns: n.columns,
+	}
+	fileCtx := &importFileContext{
+		source:   inputIdx,
+		skip:     resumePos,
+		rejected: rejected,
+		rowLimit: n.opts.RowLimit,
+	}
+	return runParallelImport(ctx, n.importCtx, fileCtx, producer, consumer)
+}
+
+// ndjsonColumns describes how the target columns, in the order of the datums
+// of the row converter, are populated.
+type ndjsonColumns struct {
+	names []string
+	// paths holds the path of the JSON value of each column.
+	paths [][]string
+	// catchAll is the index of the catch-all column, or -1 if there is none.
+	catchAll int
+	// mappedKeys holds the top-level keys which are the first element of the
+	// path of a column.
+	mappedKeys map[string]struct{}
+}
+
+// makeNDJSONColumns resolves the paths of the target columns of the import,
+// or of all visible columns if targetCols is empty.
+func makeNDJSONColumns(
+	tableDesc catalog.TableDescriptor, targetCols tree.NameList, opts roachpb.NDJSONOptions,
+) (ndjsonColumns, error) {
+	var cols []catalog.Column
+	if len(targetCols) == 0 {
+		cols = tableDesc.VisibleColumns()
+	} else {
+		var err error
+		if cols, err = catalog.MustFindPublicColumnsByNameList(tableDesc, targetCols); err != nil {
+			return ndjsonColumns{}, err
+		}
+	}
+	res := ndjsonColumns{
+		names:      make([]string, len(cols)),
+		paths:      make([][]string, len(cols)),
+		catchAll:   -1,
+		mappedKeys: make(map[string]struct{}),
+	}
+	colIdx := make(map[string]int, len(cols))
+	for i, col := range cols {
+		res.names[i] = col.GetName()
+		res.paths[i] = []string{col.GetName()}
+		colIdx[col.GetName()] = i
+	}
+	for _, cp := range opts.ColumnPaths {
+		i, ok := colIdx[cp.Column]
+		if !ok {
+			return ndjsonColumns{}, errors.Errorf("column %q with a path is not a target column", cp.Column)
+		}
+		if len(cp.Path) == 0 {
+			return ndjsonColumns{}, errors.Errorf("empty path for column %q", cp.Column)
+		}
+		res.paths[i] = cp.Path
+	}
+	if opts.CatchAllColumn != "" {
+		i, ok := colIdx[opts.CatchAllColumn]
+		if !ok {
+			return ndjsonColumns{}, errors.Errorf("catch-all column %q is not a target column", opts.CatchAllColumn)
+		}
+		if typ := cols[i].GetType(); typ.Family() != types.JsonFamily {
+			return ndjsonColumns{}, errors.Errorf(
+				"catch-all column %q must be of type JSONB, not %s", opts.CatchAllColumn, typ.SQLString())
+		}
+		res.catchAll = i
+		res.paths[i] = nil
+	}
+	for _, path := range res.paths {
+		if len(path) > 0 {
+			res.mappedKeys[path[0]] = struct{}{}
+		}
+	}
+	return res, nil
+}
+
+// ndjsonRowProducer produces the non-empty lines of the input.
+type ndjsonRowProducer struct {
+	scanner  *bufio.Scanner
+	progress func() float32
+}
+
+var _ importRowProducer = &ndjsonRowProducer{}
+
+// Scan implements the importRowProducer interface.
+func (p *ndjsonRowProducer) Scan() bool {
+	for p.scanner.Scan() {
+		if len(bytes.TrimSpace(p.scanner.Bytes())) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Err implements the importRowProducer interface.
+func (p *ndjsonRowProducer) Err() error {
+	err := p.scanner.Err()
+	if errors.Is(err, bufio.ErrTooLong) {
+		err = wrapWithLineTooLongHint(errors.New("line too long"))
+	}
+	return err
+}
+
+// Skip implements the importRowProducer interface.
+func (p *ndjsonRowProducer) Skip() error {
+	return nil
+}
+
+// Row implements the importRowProducer interface.
+func (p *ndjsonRowProducer) Row() (interface{}, error) {
+	return p.scanner.Text(), nil
+}
+
+// Progress implements the importRowProducer interface.
+func (p *ndjsonRowProducer) Progress() float32 {
+	return p.progress()
+}
+
+// ndjsonRowConsumer converts lines of JSON objects to datums.
+type ndjsonRowConsumer struct {
+	opts    *roachpb.NDJSONOptions
+	columns ndjsonColumns
+}
+
+var _ importRowConsumer = &ndjsonRowConsumer{}
+
+// FillDatums implements the importRowConsumer interface.
+func (c *ndjsonRowConsumer) FillDatums(
+	ctx context.Context, row interface{}, rowNum int64, conv *row.DatumRowConverter,
+) error {
+	line := row.(string)
+	obj, err := json.ParseJSON(line)
+	if err != nil {
+		return newImportRowError(err, line, rowNum)
+	}
+	if obj.Type() != json.ObjectJSONType {
+		return newImportRowError(errors.Errorf("expected a JSON object, found %s", obj.Type()), line, rowNum)
+	}
+	for i, path := range c.columns.paths {
+		if i == c.columns.catchAll {
+			continue
+		}
+		v, err := json.FetchPath(obj, path)
+		if err != nil {
+			return newImportRowError(err, line, rowNum)
+		}
+		if v == nil {
+			conv.Datums[i] = tree.DNull
+			continue
+		}
+		typ := conv.VisibleColTypes[i]
+		if typ.Family() == types.JsonFamily {
+			conv.Datums[i] = tree.NewDJSON(v)
+			continue
+		}
+		if conv.Datums[i], err = eval.PopulateDatumWithJSON(ctx, conv.EvalCtx, v, typ); err != nil {
+			return newImportRowError(
+				errors.Wrapf(err, "parse %q as %s", c.columns.names[i], typ.SQLString()), line, rowNum)
+		}
+	}
+
+	if c.columns.catchAll < 0 && !c.opts.StrictMode {
+		return nil
+	}
+	it, err := obj.ObjectIter()
+	if err != nil {
+		return newImportRowError(err, line, rowNum)
+	}
+	var unmapped *json.ObjectBuilder
+	for it.Next() {
+		if _, ok := c.columns.mappedKeys[it.Key()]; ok {
+			continue
+		}
+		if c.columns.catchAll < 0 {
+			return newImportRowError(errors.Errorf("could not find column for key %q", it.Key()), line, rowNum)
+		}
+		if unmapped == nil {
+			unmapped = json.NewObjectBuilder(0)
+		}
+		unmapped.Add(it.Key(), it.Value())
+	}
+	if c.columns.catchAll >= 0 {
+		// The catch-all column is NULL rather than an empty object if all keys
+		// are mapped to columns.
+		conv.Datums[c.columns.catchAll] = tree.DNull
+		if unmapped != nil {
+			conv.Datums[c.columns.catchAll] = tree.NewDJSON(unmapped.Build())
+		}
+	}
+	return nil
+}
@@ -276,7 +276,7 @@ var _ importRowProducer = &pgCopyProducer{}
 // Scan implements importRowProducer
 func (p *pgCopyProducer) Scan() bool {
 	p.row, p.err = p.copyStream.Next()
-	if p.err == io.EOF {
+	if p.err == io.EOF || errors.Is(p.err, errCopyDone) {
 		p.err = nil
 		return false
 	}
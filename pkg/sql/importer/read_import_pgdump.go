@@ -0,0 +1,437 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// postgreStream streams the statements of a dump in pg_dump's plain format.
+// The data of a COPY FROM STDIN statement follows the statement in the dump,
+// so it switches the stream into line mode until the end of the data.
+type postgreStream struct {
+	s    *bufio.Scanner
+	copy *postgreStreamCopy
+	// pos is the offset in the dump of the end of the last token.
+	pos int64
+	// stmtPos is the offset in the dump of the statement last returned by
+	// Next.
+	stmtPos int64
+}
+
+func newPostgreStream(r io.Reader, maxRowSize int) *postgreStream {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, maxRowSize)
+	p := &postgreStream{s: s}
+	s.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := p.split(data, atEOF)
+		p.pos += int64(advance)
+		return advance, token, err
+	})
+	return p
+}
+
+func (p *postgreStream) split(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if p.copy != nil {
+		return bufio.ScanLines(data, atEOF)
+	}
+	// Comments and psql meta-commands (e.g. \restrict) are returned as tokens of
+	// their own, since the latter cannot be tokenized by the SQL scanner.
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	if bytes.HasPrefix(trimmed, []byte("--")) || bytes.HasPrefix(trimmed, []byte(`\`)) {
+		advance, token, err = bufio.ScanLines(trimmed, atEOF)
+		if advance == 0 {
+			return 0, nil, err
+		}
+		return len(data) - len(trimmed) + advance, token, err
+	}
+	if pos, ok := parser.SplitFirstStatement(string(data)); ok {
+		return pos, data[:pos], nil
+	}
+	if atEOF && len(data) > 0 {
+		// A final statement without a terminating semicolon.
+		return len(data), data, nil
+	}
+	// Request more data.
+	return 0, nil, nil
+}
+
+// Next returns the next statement of the dump, or io.EOF if there are none
+// left. If the statement is a COPY FROM STDIN, it is also returned parsed, and
+// its data must be consumed with CopyData or SkipCopyData before Next is
+// called again.
+func (p *postgreStream) Next() (string, *tree.CopyFrom, error) {
+	if p.copy != nil {
+		return "", nil, errors.AssertionFailedf("COPY data was not consumed")
+	}
+	for {
+		start := p.pos
+		if !p.s.Scan() {
+			break
+		}
+		stmt := strings.TrimSpace(p.s.Text())
+		if stmt == "" || strings.HasPrefix(stmt, "--") || strings.HasPrefix(stmt, `\`) {
+			continue
+		}
+		p.stmtPos = start
+		if len(stmt) < len("COPY") || !strings.EqualFold(stmt[:len("COPY")], "COPY") {
+			return stmt, nil, nil
+		}
+		parsed, err := parser.ParseOne(stmt)
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "parsing %q", stmt)
+		}
+		cf, ok := parsed.AST.(*tree.CopyFrom)
+		if !ok || !cf.Stdin {
+			return stmt, nil, nil
+		}
+		p.copy = newPostgreStreamCopy(p.s, copyDefaultDelimiter, copyDefaultNull)
+		// The data starts on the line following the COPY statement, so the
+		// remainder of the statement's line is expected to be empty.
+		if !p.s.Scan() || len(p.s.Bytes()) != 0 {
+			if err := p.scanErr(); err != nil {
+				return "", nil, err
+			}
+			return "", nil, errors.Errorf("expected a newline after %q", stmt)
+		}
+		return stmt, cf, nil
+	}
+	if err := p.scanErr(); err != nil {
+		return "", nil, err
+	}
+	return "", nil, io.EOF
+}
+
+// CopyData returns the rows of the COPY FROM STDIN statement last returned by
+// Next. The rows end with errCopyDone.
+func (p *postgreStream) CopyData() *postgreStreamCopy {
+	return p.copy
+}
+
+// SkipCopyData skips over the data of the COPY FROM STDIN statement last
+// returned by Next.
+func (p *postgreStream) SkipCopyData() error {
+	for p.s.Scan() {
+		if bytes.Equal(p.s.Bytes(), []byte(`\.`)) {
+			p.copy = nil
+			return nil
+		}
+	}
+	if err := p.scanErr(); err != nil {
+		return err
+	}
+	return errors.New("unexpected end of COPY data")
+}
+
+func (p *postgreStream) scanErr() error {
+	err := p.s.Err()
+	if errors.Is(err, bufio.ErrTooLong) {
+		err = wrapWithLineTooLongHint(errors.New("line too long"))
+	}
+	return err
+}
+
+// pgDumpItem is a statement of a dump. Items are returned in the order in
+// which they need to be executed to restore the dump.
+type pgDumpItem struct {
+	// stmt is the SQL text of the statement.
+	stmt string
+	// copy is set if the statement is a COPY FROM STDIN of table data.
+	copy *tree.CopyFrom
+	// offset is the offset of a COPY statement in a plain-format dump, at
+	// which the reader of its data can start. It is zero for custom archives,
+	// whose readers find the data from the table of contents.
+	offset int64
+}
+
+// pgCustomArchiveMagic is the prefix of archives in pg_dump's custom format.
+const pgCustomArchiveMagic = "PGDMP"
+
+// isPgCustomArchive returns whether the dump read by r is in the custom
+// format rather than the plain one.
+func isPgCustomArchive(r *bufio.Reader) (bool, error) {
+	magic, err := r.Peek(len(pgCustomArchiveMagic))
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	return string(magic) == pgCustomArchiveMagic, nil
+}
+
+// readPgDumpItems reads the statements of a dump in the plain or custom
+// format. The data of the tables is skipped over.
+func readPgDumpItems(r io.Reader, maxRowSize int) ([]pgDumpItem, error) {
+	br := bufio.NewReader(r)
+	custom, err := isPgCustomArchive(br)
+	if err != nil {
+		return nil, err
+	}
+	if custom {
+		archive, err := readPgCustomArchive(br, maxRowSize)
+		if err != nil {
+			return nil, err
+		}
+		var items []pgDumpItem
+		for _, e := range archive.entries {
+			if e.isTableData() {
+				parsed, err := parser.ParseOne(e.copyStmt)
+				if err != nil {
+					return nil, errors.Wrapf(err, "parsing %q", e.copyStmt)
+				}
+				cf, ok := parsed.AST.(*tree.CopyFrom)
+				if !ok {
+					return nil, errors.Errorf("unexpected COPY statement %q", e.copyStmt)
+				}
+				items = append(items, pgDumpItem{stmt: strings.TrimSpace(e.copyStmt), copy: cf})
+				continue
+			}
+			// The definition of an entry may consist of several statements.
+			s := newPostgreStream(strings.NewReader(e.defn), maxRowSize)
+			for {
+				stmt, _, err := s.Next()
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					return nil, err
+				}
+				items = append(items, pgDumpItem{stmt: stmt})
+			}
+		}
+		return items, nil
+	}
+
+	var items []pgDumpItem
+	s := newPostgreStream(br, maxRowSize)
+	for {
+		stmt, cf, err := s.Next()
+		if err == io.EOF {
+			return items, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if cf != nil {
+			if err := s.SkipCopyData(); err != nil {
+				return nil, errors.Wrapf(err, "reading data of %q", stmt)
+			}
+		}
+		items = append(items, pgDumpItem{stmt: stmt, copy: cf, offset: s.stmtPos})
+	}
+}
+
+// pgDumpReader is an inputConverter which imports the COPY data of a single
+// table from a dump in pg_dump's plain or custom format. The other statements
+// of the dump are ignored.
+type pgDumpReader struct {
+	importCtx *parallelImportContext
+	opts      roachpb.PgDumpOptions
+	copyOpts  roachpb.PgCopyOptions
+	table     *tree.TableName
+}
+
+var _ inputConverter = &pgDumpReader{}
+
+func newPgDumpReader(
+	semaCtx *tree.SemaContext,
+	opts roachpb.PgDumpOptions,
+	kvCh chan row.KVBatch,
+	walltime int64,
+	parallelism int,
+	tableDesc catalog.TableDescriptor,
+	targetCols tree.NameList,
+	evalCtx *eval.Context,
+	db *kv.DB,
+) (*pgDumpReader, error) {
+	var table *tree.TableName
+	if opts.Table != "" {
+		var err error
+		if table, err = parser.ParseQualifiedTableName(opts.Table); err != nil {
+			return nil, err
+		}
+	} else {
+		tn := tree.MakeUnqualifiedTableName(tree.Name(tableDesc.GetName()))
+		table = &tn
+	}
+	return &pgDumpReader{
+		importCtx: &parallelImportContext{
+			semaCtx:    semaCtx,
+			walltime:   walltime,
+			numWorkers: parallelism,
+			evalCtx:    evalCtx,
+			tableDesc:  tableDesc,
+			targetCols: targetCols,
+			kvCh:       kvCh,
+			db:         db,
+		},
+		opts: opts,
+		copyOpts: roachpb.PgCopyOptions{
+			Delimiter:  copyDefaultDelimiter,
+			Null:       copyDefaultNull,
+			MaxRowSize: opts.MaxRowSize,
+		},
+		table: table,
+	}, nil
+}
+
+func (d *pgDumpReader) readFiles(
+	ctx context.Context,
+	dataFiles map[int32]string,
+	resumePos map[int32]int64,
+	format roachpb.IOFileFormat,
+	makeExternalStorage cloud.ExternalStorageFactory,
+	user username.SQLUsername,
+) error {
+	return readInputFiles(ctx, dataFiles, resumePos, format, d.readFile, makeExternalStorage, user)
+}
+
+// matches returns whether the named table of the dump is the one whose data
+// is imported.
+func (d *pgDumpReader) matches(tn *tree.TableName) bool {
+	if tn.ObjectName != d.table.ObjectName {
+		return false
+	}
+	return !d.table.ExplicitSchema || tn.SchemaName == d.table.SchemaName
+}
+
+func (d *pgDumpReader) readFile(
+	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected chan string,
+) error {
+	if err := seekDumpInput(input, d.opts.Offset); err != nil {
+		return err
+	}
+	br := bufio.NewReader(input)
+	custom, err := isPgCustomArchive(br)
+	if err != nil {
+		return err
+	}
+	var c *postgreStreamCopy
+	if custom {
+		var data io.ReadCloser
+		if c, data, err = d.customArchiveCopyData(input, br); err != nil {
+			return err
+		}
+		defer data.Close()
+	} else if c, err = d.plainCopyData(br); err != nil {
+		return err
+	}
+
+	producer := &pgCopyProducer{
+		importCtx:  d.importCtx,
+		opts:       &d.copyOpts,
+		input:      input,
+		copyStream: c,
+	}
+
+	consumer := &pgCopyConsumer{
+		opts: &d.copyOpts,
+	}
+
+	fileCtx := &importFileContext{
+		source:   inputIdx,
+		skip:     resumePos,
+		rejected: rejected,
+	}
+
+	return runParallelImport(ctx, d.importCtx, fileCtx, producer, consumer)
+}
+
+// seekDumpInput positions the input of a dump at the specified offset. If the
+// input does not support random access, which is the case for compressed
+// dumps, the bytes before the offset are read and discarded, which still
+// spares parsing them.
+func seekDumpInput(input *fileReader, offset int64) error {
+	if offset == 0 {
+		return nil
+	}
+	if input.Seeker != nil {
+		_, err := input.Seek(offset, io.SeekStart)
+		return err
+	}
+	if _, err := io.CopyN(io.Discard, input, offset); err != nil {
+		return errors.Wrapf(unexpectedEOF(err), "seeking to offset %d of dump", offset)
+	}
+	return nil
+}
+
+// plainCopyData positions a plain-format dump at the COPY data of the table.
+// If the offset of the table's COPY statement is known, the dump must be
+// positioned at it already.
+func (d *pgDumpReader) plainCopyData(r io.Reader) (*postgreStreamCopy, error) {
+	s := newPostgreStream(r, int(d.opts.MaxRowSize))
+	for {
+		stmt, cf, err := s.Next()
+		if err == io.EOF {
+			return nil, errors.Errorf("no COPY data for table %s in dump", d.table)
+		} else if err != nil {
+			return nil, err
+		}
+		if cf != nil && d.matches(&cf.Table) {
+			return s.CopyData(), nil
+		}
+		if d.opts.Offset != 0 {
+			return nil, errors.Errorf(
+				"expected the COPY statement of table %s at offset %d of dump, found %q",
+				d.table, d.opts.Offset, stmt)
+		}
+		if cf == nil {
+			continue
+		}
+		if err := s.SkipCopyData(); err != nil {
+			return nil, errors.Wrapf(err, "reading data of %q", stmt)
+		}
+	}
+}
+
+// customArchiveCopyData returns the COPY data of the table in a custom-format
+// dump, along with the underlying data reader which must be closed. r reads
+// the input, which is read from its start. If the input supports random
+// access and pg_dump recorded the position of the table's data, the data
+// blocks of the other entries are not read.
+func (d *pgDumpReader) customArchiveCopyData(
+	input *fileReader, r *bufio.Reader,
+) (*postgreStreamCopy, io.ReadCloser, error) {
+	archive, err := readPgCustomArchive(r, int(d.opts.MaxRowSize))
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, e := range archive.entries {
+		if !e.isTableData() {
+			continue
+		}
+		tn := tree.MakeTableNameWithSchema("", tree.Name(e.namespace), tree.Name(e.tag))
+		if !d.matches(&tn) {
+			continue
+		}
+		if e.dataOffset != 0 && input.Seeker != nil {
+			if _, err := input.Seek(e.dataOffset, io.SeekStart); err != nil {
+				return nil, nil, err
+			}
+			r.Reset(input)
+		}
+		data, err := archive.openData(e.dumpID)
+		if err != nil {
+			return nil, nil, err
+		}
+		s := bufio.NewScanner(data)
+		s.Split(bufio.ScanLines)
+		s.Buffer(nil, int(d.opts.MaxRowSize))
+		return newPostgreStreamCopy(s, copyDefaultDelimiter, copyDefaultNull), data, nil
+	}
+	return nil, nil, errors.Errorf("no COPY data for table %s in dump", d.table)
+}
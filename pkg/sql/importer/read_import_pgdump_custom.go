@@ -0,0 +1,360 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package importer
+
+import (
+	"bufio"
+	"compress/zlib"
+	"io"
+
+	"github.com/cockroachdb/errors"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+)
+
+// The layout of archives in pg_dump's custom format (pg_dump -Fc) is defined
+// by pg_backup_archiver.c and pg_backup_custom.c in the PostgreSQL sources.
+// An archive consists of a header, a table of contents (TOC) describing every
+// object of the dump along with the SQL which creates it, and the data blocks
+// of the TOC entries which have data.
+
+// pgArchiveVersion returns the archive format version as encoded by
+// MAKE_ARCHIVE_VERSION.
+func pgArchiveVersion(major, minor, rev int) int {
+	return (major*256+minor)*256 + rev
+}
+
+var (
+	// pgArchiveMinVersion is the oldest supported archive version, written by
+	// PostgreSQL 9.0 and later.
+	pgArchiveMinVersion = pgArchiveVersion(1, 12, 0)
+	// pgArchiveVersionTableAM adds the table access method to TOC entries.
+	pgArchiveVersionTableAM = pgArchiveVersion(1, 14, 0)
+	// pgArchiveVersionCompressionAlgorithm replaces the compression level in
+	// the header with a compression algorithm.
+	pgArchiveVersionCompressionAlgorithm = pgArchiveVersion(1, 15, 0)
+	// pgArchiveVersionRelKind adds the relation kind to TOC entries.
+	pgArchiveVersionRelKind = pgArchiveVersion(1, 16, 0)
+	// pgArchiveMaxVersion is the newest supported archive version.
+	pgArchiveMaxVersion = pgArchiveVersionRelKind
+)
+
+const (
+	// pgArchiveFormatCustom identifies the custom format in the header.
+	pgArchiveFormatCustom = 1
+
+	pgArchiveBlockData  = 1
+	pgArchiveBlockBlobs = 3
+
+	pgArchiveOffsetPosSet = 2
+	pgArchiveOffsetNoData = 3
+)
+
+// pgArchiveCompression is the compression algorithm of data blocks.
+type pgArchiveCompression int
+
+const (
+	pgArchiveCompressionNone pgArchiveCompression = iota
+	pgArchiveCompressionGzip
+	pgArchiveCompressionLZ4
+	pgArchiveCompressionZstd
+)
+
+// pgArchiveEntry is an entry in the TOC of an archive.
+type pgArchiveEntry struct {
+	dumpID    int
+	tag       string
+	desc      string
+	namespace string
+	// defn holds the statements which create the object.
+	defn string
+	// copyStmt is the COPY FROM STDIN statement which loads the data of a
+	// table.
+	copyStmt string
+	hasData  bool
+	// dataOffset is the offset of the data block of the entry in the archive,
+	// or zero if pg_dump did not record it, which is the case for archives
+	// written to a pipe.
+	dataOffset int64
+}
+
+// isTableData returns whether the entry is the data of a table.
+func (e *pgArchiveEntry) isTableData() bool {
+	return e.desc == "TABLE DATA" && e.copyStmt != "" && e.hasData
+}
+
+// pgCustomArchive reads an archive in the custom format.
+type pgCustomArchive struct {
+	r           *bufio.Reader
+	maxSize     int
+	version     int
+	intSize     int
+	offSize     int
+	compression pgArchiveCompression
+	entries     []pgArchiveEntry
+	// err is the first error encountered while reading the archive.
+	err error
+}
+
+// readPgCustomArchive reads the header and the TOC of an archive. Strings in
+// the archive larger than maxSize are rejected.
+func readPgCustomArchive(r *bufio.Reader, maxSize int) (*pgCustomArchive, error) {
+	a := &pgCustomArchive{r: r, maxSize: maxSize}
+	if err := a.readHeader(); err != nil {
+		return nil, errors.Wrap(err, "reading pg_dump archive header")
+	}
+	if err := a.readTOC(); err != nil {
+		return nil, errors.Wrap(err, "reading pg_dump archive table of contents")
+	}
+	return a, nil
+}
+
+func (a *pgCustomArchive) readHeader() error {
+	magic := make([]byte, len(pgCustomArchiveMagic))
+	if _, err := io.ReadFull(a.r, magic); err != nil {
+		return err
+	}
+	if string(magic) != pgCustomArchiveMagic {
+		return errors.New("not a pg_dump archive")
+	}
+	major, minor := int(a.readByte()), int(a.readByte())
+	var rev int
+	if major > 1 || (major == 1 && minor > 0) {
+		rev = int(a.readByte())
+	}
+	a.version = pgArchiveVersion(major, minor, rev)
+	if a.err == nil && (a.version < pgArchiveMinVersion || a.version > pgArchiveMaxVersion) {
+		return errors.Errorf("unsupported archive version %d.%d.%d", major, minor, rev)
+	}
+	a.intSize = int(a.readByte())
+	a.offSize = int(a.readByte())
+	if a.err == nil && (a.intSize < 1 || a.intSize > 8 || a.offSize < 1 || a.offSize > 8) {
+		return errors.Errorf("unsupported integer size %d or offset size %d", a.intSize, a.offSize)
+	}
+	if format := a.readByte(); a.err == nil && format != pgArchiveFormatCustom {
+		return errors.Errorf("unsupported archive format %d; only the custom format (-Fc) is supported", format)
+	}
+	if a.version >= pgArchiveVersionCompressionAlgorithm {
+		a.compression = pgArchiveCompression(a.readByte())
+		if a.err == nil && a.compression > pgArchiveCompressionZstd {
+			return errors.Errorf("unsupported compression algorithm %d", a.compression)
+		}
+	} else if level := a.readInt(); level != 0 {
+		// Older versions store a gzip compression level, or -1 for the default
+		// level.
+		a.compression = pgArchiveCompressionGzip
+	}
+	// Skip the creation time, stored as the fields of a struct tm.
+	for i := 0; i < 7; i++ {
+		a.readInt()
+	}
+	a.readStr() // database name
+	a.readStr() // server version
+	a.readStr() // pg_dump version
+	return a.err
+}
+
+func (a *pgCustomArchive) readTOC() error {
+	n := a.readInt()
+	if a.err == nil && n < 0 {
+		return errors.Errorf("invalid number of entries %d", n)
+	}
+	for i := 0; i < n && a.err == nil; i++ {
+		var e pgArchiveEntry
+		e.dumpID = a.readInt()
+		a.readInt() // had dumper
+		a.readStr() // table OID
+		a.readStr() // OID
+		e.tag, _ = a.readStr()
+		e.desc, _ = a.readStr()
+		a.readInt() // section
+		e.defn, _ = a.readStr()
+		a.readStr() // drop statement
+		e.copyStmt, _ = a.readStr()
+		e.namespace, _ = a.readStr()
+		a.readStr() // tablespace
+		if a.version >= pgArchiveVersionTableAM {
+			a.readStr() // table access method
+		}
+		if a.version >= pgArchiveVersionRelKind {
+			a.readInt() // relation kind
+		}
+		a.readStr() // owner
+		a.readStr() // with OIDs
+		// The dependencies of the entry are terminated by a NULL string.
+		for a.err == nil {
+			if _, ok := a.readStr(); !ok {
+				break
+			}
+		}
+		var flag byte
+		flag, e.dataOffset = a.readOffset()
+		e.hasData = flag != pgArchiveOffsetNoData
+		if flag != pgArchiveOffsetPosSet {
+			e.dataOffset = 0
+		}
+		a.entries = append(a.entries, e)
+	}
+	return a.err
+}
+
+func (a *pgCustomArchive) readByte() byte {
+	if a.err != nil {
+		return 0
+	}
+	b, err := a.r.ReadByte()
+	if err != nil {
+		a.err = unexpectedEOF(err)
+	}
+	return b
+}
+
+// readInt reads an integer, stored as a sign byte followed by the magnitude
+// in little-endian order.
+func (a *pgCustomArchive) readInt() int {
+	negative := a.readByte() != 0
+	var res int
+	for i := 0; i < a.intSize; i++ {
+		res |= int(a.readByte()) << (8 * i)
+	}
+	if negative {
+		res = -res
+	}
+	return res
+}
+
+// readStr reads a string, stored as its length followed by its bytes. A
+// negative length denotes a NULL string, for which ok is false.
+func (a *pgCustomArchive) readStr() (_ string, ok bool) {
+	n := a.readInt()
+	if a.err != nil || n < 0 {
+		return "", false
+	}
+	if n > a.maxSize {
+		a.err = wrapWithLineTooLongHint(errors.Newf("string of %d bytes too long", n))
+		return "", false
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(a.r, buf); err != nil {
+		a.err = unexpectedEOF(err)
+		return "", false
+	}
+	return string(buf), true
+}
+
+// readOffset reads the position of the data of an entry, which is stored as a
+// flag byte followed by the offset in little-endian order.
+func (a *pgCustomArchive) readOffset() (flag byte, offset int64) {
+	flag = a.readByte()
+	for i := 0; i < a.offSize; i++ {
+		offset |= int64(a.readByte()) << (8 * i)
+	}
+	return flag, offset
+}
+
+// openData returns a reader for the decompressed data of the entry with the
+// specified dump ID. The data blocks are read in order from the current
+// position of the archive's reader, which is either the end of the TOC or the
+// recorded offset of the entry's data, so openData may only be called once.
+func (a *pgCustomArchive) openData(dumpID int) (io.ReadCloser, error) {
+	for {
+		blockType, err := a.r.ReadByte()
+		if err == io.EOF {
+			return nil, errors.Errorf("no data for entry %d in archive", dumpID)
+		} else if err != nil {
+			return nil, err
+		}
+		id := a.readInt()
+		if a.err != nil {
+			return nil, a.err
+		}
+		switch blockType {
+		case pgArchiveBlockData:
+			if id == dumpID {
+				return a.decompress(&pgArchiveChunkReader{a: a})
+			}
+			if _, err := io.Copy(io.Discard, &pgArchiveChunkReader{a: a}); err != nil {
+				return nil, err
+			}
+		case pgArchiveBlockBlobs:
+			// Large objects are stored as a sequence of OIDs, each followed by
+			// chunks, terminated by a zero OID.
+			for {
+				oid := a.readInt()
+				if a.err != nil {
+					return nil, a.err
+				}
+				if oid == 0 {
+					break
+				}
+				if _, err := io.Copy(io.Discard, &pgArchiveChunkReader{a: a}); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			return nil, errors.Errorf("unexpected block type %d in archive", blockType)
+		}
+	}
+}
+
+func (a *pgCustomArchive) decompress(r io.Reader) (io.ReadCloser, error) {
+	switch a.compression {
+	case pgArchiveCompressionNone:
+		return io.NopCloser(r), nil
+	case pgArchiveCompressionGzip:
+		return zlib.NewReader(r)
+	case pgArchiveCompressionLZ4:
+		return io.NopCloser(lz4.NewReader(r)), nil
+	case pgArchiveCompressionZstd:
+		d, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return d.IOReadCloser(), nil
+	default:
+		return nil, errors.AssertionFailedf("unexpected compression algorithm %d", a.compression)
+	}
+}
+
+// pgArchiveChunkReader reads the data of a block, which is stored as a
+// sequence of chunks, each prefixed by its length, terminated by an empty
+// chunk.
+type pgArchiveChunkReader struct {
+	a         *pgCustomArchive
+	remaining int
+	done      bool
+}
+
+// Read implements the io.Reader interface.
+func (c *pgArchiveChunkReader) Read(p []byte) (int, error) {
+	for c.remaining == 0 {
+		if c.done {
+			return 0, io.EOF
+		}
+		n := c.a.readInt()
+		if c.a.err != nil {
+			return 0, c.a.err
+		}
+		if n < 0 {
+			return 0, errors.Errorf("invalid chunk length %d", n)
+		}
+		c.remaining = n
+		c.done = n == 0
+	}
+	if len(p) > c.remaining {
+		p = p[:c.remaining]
+	}
+	n, err := c.a.r.Read(p)
+	c.remaining -= n
+	return n, unexpectedEOF(err)
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,388 @@
+// Copyright 2025 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+	"github.com/stretchr/testify/require"
+)
+
+const testPgDump = `--
+-- PostgreSQL database dump
+--
+
+\restrict 7sKJ2mQ
+
+SET statement_timeout = 0;
+SELECT pg_catalog.set_config('search_path', '', false);
+
+CREATE FUNCTION public.f() RETURNS integer
+    LANGUAGE sql
+    AS $$SELECT 1; SELECT 2$$;
+
+--
+-- Name: a; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.a (
+    i integer NOT NULL,
+    s text
+);
+
+COPY public.a (i, s) FROM stdin;
+1	one; two
+2	\N
+\.
+
+
+CREATE TABLE public.b (
+    i integer
+);
+
+COPY public.b (i) FROM stdin;
+3
+\.
+
+
+ALTER TABLE ONLY public.a
+    ADD CONSTRAINT a_pkey PRIMARY KEY (i);
+
+\unrestrict 7sKJ2mQ
+`
+
+func TestReadPgDumpItems(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	items, err := readPgDumpItems(strings.NewReader(testPgDump), defaultScanBuffer)
+	require.NoError(t, err)
+	var stmts []string
+	var copies []string
+	for _, item := range items {
+		stmts = append(stmts, item.stmt)
+		if item.copy != nil {
+			copies = append(copies, tree.AsString(&item.copy.Table))
+		}
+	}
+	require.Equal(t, []string{
+		`SET statement_timeout = 0;`,
+		`SELECT pg_catalog.set_config('search_path', '', false);`,
+		"CREATE FUNCTION public.f() RETURNS integer\n    LANGUAGE sql\n    AS $$SELECT 1; SELECT 2$$;",
+		"CREATE TABLE public.a (\n    i integer NOT NULL,\n    s text\n);",
+		`COPY public.a (i, s) FROM stdin;`,
+		"CREATE TABLE public.b (\n    i integer\n);",
+		`COPY public.b (i) FROM stdin;`,
+		"ALTER TABLE ONLY public.a\n    ADD CONSTRAINT a_pkey PRIMARY KEY (i);",
+	}, stmts)
+	require.Equal(t, []string{"public.a", "public.b"}, copies)
+}
+
+func TestPgDumpReaderPlainCopyDataAtOffset(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	items, err := readPgDumpItems(strings.NewReader(testPgDump), defaultScanBuffer)
+	require.NoError(t, err)
+	rows := map[string][]string{
+		"public.a": {"\"1\"\t\"one; two\"", "\"2\"\t\\N"},
+		"public.b": {`"3"`},
+	}
+	var offsets []int64
+	for _, item := range items {
+		if item.copy == nil {
+			continue
+		}
+		require.NotZero(t, item.offset)
+		offsets = append(offsets, item.offset)
+		tn := item.copy.Table
+		d := &pgDumpReader{
+			opts:  roachpb.PgDumpOptions{MaxRowSize: defaultScanBuffer, Offset: item.offset},
+			table: &tn,
+		}
+		c, err := d.plainCopyData(strings.NewReader(testPgDump[item.offset:]))
+		require.NoError(t, err)
+		require.Equal(t, rows[tree.AsString(&tn)], readTestCopyData(t, c))
+	}
+	require.Len(t, offsets, 2)
+
+	// The reader of a table must not scan past a wrong offset.
+	tn, err := parser.ParseQualifiedTableName("public.a")
+	require.NoError(t, err)
+	d := &pgDumpReader{
+		opts:  roachpb.PgDumpOptions{MaxRowSize: defaultScanBuffer, Offset: offsets[1]},
+		table: tn,
+	}
+	_, err = d.plainCopyData(strings.NewReader(testPgDump[offsets[1]:]))
+	require.ErrorContains(t, err, "expected the COPY statement of table public.a")
+}
+
+// readTestCopyData returns the rows of a COPY data stream.
+func readTestCopyData(t *testing.T, c *postgreStreamCopy) []string {
+	var rows []string
+	for {
+		row, err := c.Next()
+		if err == io.EOF || errors.Is(err, errCopyDone) {
+			return rows
+		}
+		require.NoError(t, err)
+		rows = append(rows, row.String())
+	}
+}
+
+func TestPgDumpReaderPlainCopyData(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		table string
+		rows  []string
+		err   string
+	}{
+		{table: "a", rows: []string{"\"1\"\t\"one; two\"", "\"2\"\t\\N"}},
+		{table: "public.b", rows: []string{`"3"`}},
+		{table: "other.b", err: "no COPY data for table other.b"},
+	} {
+		t.Run(tc.table, func(t *testing.T) {
+			tn, err := parser.ParseQualifiedTableName(tc.table)
+			require.NoError(t, err)
+			d := &pgDumpReader{opts: roachpb.PgDumpOptions{MaxRowSize: defaultScanBuffer}, table: tn}
+			c, err := d.plainCopyData(strings.NewReader(testPgDump))
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.rows, readTestCopyData(t, c))
+		})
+	}
+}
+
+// testPgArchiveEntry is an entry of an archive built by makeTestPgArchive.
+type testPgArchiveEntry struct {
+	desc      string
+	namespace string
+	tag       string
+	defn      string
+	copyStmt  string
+	data      string
+}
+
+// makeTestPgArchive builds an archive in pg_dump's custom format with the
+// specified archive version 1.<minor> and compression of data blocks.
+func makeTestPgArchive(
+	t *testing.T, minor int, compression pgArchiveCompression, entries []testPgArchiveEntry,
+) []byte {
+	const intSize, offSize = 4, 8
+	var buf bytes.Buffer
+	writeInt := func(i int) {
+		if i < 0 {
+			buf.WriteByte(1)
+			i = -i
+		} else {
+			buf.WriteByte(0)
+		}
+		for j := 0; j < intSize; j++ {
+			buf.WriteByte(byte(i >> (8 * j)))
+		}
+	}
+	writeStr := func(s string) {
+		writeInt(len(s))
+		buf.WriteString(s)
+	}
+	writeChunks := func(data []byte) {
+		// Use small chunks to exercise reading data across them.
+		for len(data) > 0 {
+			n := min(len(data), 7)
+			writeInt(n)
+			buf.Write(data[:n])
+			data = data[n:]
+		}
+		writeInt(0)
+	}
+	compress := func(data string) []byte {
+		var out bytes.Buffer
+		var w io.WriteCloser
+		switch compression {
+		case pgArchiveCompressionNone:
+			return []byte(data)
+		case pgArchiveCompressionGzip:
+			w = zlib.NewWriter(&out)
+		case pgArchiveCompressionLZ4:
+			w = lz4.NewWriter(&out)
+		case pgArchiveCompressionZstd:
+			var err error
+			w, err = zstd.NewWriter(&out)
+			require.NoError(t, err)
+		}
+		_, err := io.WriteString(w, data)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		return out.Bytes()
+	}
+
+	buf.WriteString(pgCustomArchiveMagic)
+	buf.Write([]byte{1, byte(minor), 0, intSize, offSize, pgArchiveFormatCustom})
+	if pgArchiveVersion(1, minor, 0) >= pgArchiveVersionCompressionAlgorithm {
+		buf.WriteByte(byte(compression))
+	} else if compression == pgArchiveCompressionGzip {
+		writeInt(-1)
+	} else {
+		writeInt(0)
+	}
+	for i := 0; i < 7; i++ {
+		writeInt(0)
+	}
+	writeStr("db")
+	writeStr("16.4")
+	writeStr("16.4")
+
+	writeInt(len(entries))
+	for i, e := range entries {
+		writeInt(i + 1)
+		hasData := e.desc == "TABLE DATA"
+		if hasData {
+			writeInt(1)
+		} else {
+			writeInt(0)
+		}
+		writeStr("1259")
+		writeStr(fmt.Sprint(16384 + i))
+		writeStr(e.tag)
+		writeStr(e.desc)
+		writeInt(2) // section
+		writeStr(e.defn)
+		writeStr("")
+		writeStr(e.copyStmt)
+		writeStr(e.namespace)
+		writeStr("")
+		if pgArchiveVersion(1, minor, 0) >= pgArchiveVersionTableAM {
+			writeStr("heap")
+		}
+		if pgArchiveVersion(1, minor, 0) >= pgArchiveVersionRelKind {
+			writeInt('r')
+		}
+		writeStr("postgres")
+		writeStr("false")
+		if i > 0 {
+			writeStr(fmt.Sprint(i))
+		}
+		writeInt(-1) // end of dependencies
+		if hasData {
+			buf.WriteByte(1) // offset not set
+		} else {
+			buf.WriteByte(pgArchiveOffsetNoData)
+		}
+		buf.Write(make([]byte, offSize))
+	}
+
+	// Large objects are skipped over when reading data.
+	buf.WriteByte(pgArchiveBlockBlobs)
+	writeInt(len(entries) + 1)
+	writeInt(16400)
+	writeChunks(compress("large object"))
+	writeInt(0)
+	for i, e := range entries {
+		if e.desc != "TABLE DATA" {
+			continue
+		}
+		buf.WriteByte(pgArchiveBlockData)
+		writeInt(i + 1)
+		writeChunks(compress(e.data))
+	}
+	return buf.Bytes()
+}
+
+var testPgArchiveEntries = []testPgArchiveEntry{
+	{desc: "ENCODING", tag: "ENCODING", defn: "SET client_encoding = 'UTF8';\n"},
+	{
+		desc: "TABLE", namespace: "public", tag: "a",
+		defn: "CREATE TABLE public.a (\n    i integer NOT NULL,\n    s text\n);\n\n\nALTER TABLE public.a OWNER TO postgres;\n",
+	},
+	{desc: "TABLE", namespace: "public", tag: "b", defn: "CREATE TABLE public.b (\n    i integer\n);\n"},
+	{
+		desc: "TABLE DATA", namespace: "public", tag: "a",
+		copyStmt: "COPY public.a (i, s) FROM stdin;\n", data: "1\tone\n2\t\\N\n",
+	},
+	{
+		desc: "TABLE DATA", namespace: "public", tag: "b",
+		copyStmt: "COPY public.b (i) FROM stdin;\n", data: "3\n",
+	},
+	{
+		desc: "CONSTRAINT", namespace: "public", tag: "a a_pkey",
+		defn: "ALTER TABLE ONLY public.a\n    ADD CONSTRAINT a_pkey PRIMARY KEY (i);\n",
+	},
+}
+
+func TestPgCustomArchive(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		minor       int
+		compression pgArchiveCompression
+	}{
+		{minor: 14, compression: pgArchiveCompressionNone},
+		{minor: 14, compression: pgArchiveCompressionGzip},
+		{minor: 15, compression: pgArchiveCompressionLZ4},
+		{minor: 16, compression: pgArchiveCompressionZstd},
+	} {
+		t.Run(fmt.Sprintf("1.%d/compression=%d", tc.minor, tc.compression), func(t *testing.T) {
+			archive := makeTestPgArchive(t, tc.minor, tc.compression, testPgArchiveEntries)
+
+			items, err := readPgDumpItems(bytes.NewReader(archive), defaultScanBuffer)
+			require.NoError(t, err)
+			var stmts []string
+			for _, item := range items {
+				stmts = append(stmts, item.stmt)
+			}
+			require.Equal(t, []string{
+				`SET client_encoding = 'UTF8';`,
+				"CREATE TABLE public.a (\n    i integer NOT NULL,\n    s text\n);",
+				`ALTER TABLE public.a OWNER TO postgres;`,
+				"CREATE TABLE public.b (\n    i integer\n);",
+				`COPY public.a (i, s) FROM stdin;`,
+				`COPY public.b (i) FROM stdin;`,
+				"ALTER TABLE ONLY public.a\n    ADD CONSTRAINT a_pkey PRIMARY KEY (i);",
+			}, stmts)
+
+			tn, err := parser.ParseQualifiedTableName("public.b")
+			require.NoError(t, err)
+			d := &pgDumpReader{opts: roachpb.PgDumpOptions{MaxRowSize: defaultScanBuffer}, table: tn}
+			c, data, err := d.customArchiveCopyData(bufio.NewReader(bytes.NewReader(archive)))
+			require.NoError(t, err)
+			defer data.Close()
+			require.Equal(t, []string{`"3"`}, readTestCopyData(t, c))
+		})
+	}
+
+	t.Run("truncated", func(t *testing.T) {
+		archive := makeTestPgArchive(t, 16, pgArchiveCompressionNone, testPgArchiveEntries)
+		_, err := readPgDumpItems(bytes.NewReader(archive[:100]), defaultScanBuffer)
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		archive := makeTestPgArchive(t, 16, pgArchiveCompressionNone, testPgArchiveEntries)
+		archive[len(pgCustomArchiveMagic)+1] = 99
+		_, err := readPgDumpItems(bytes.NewReader(archive), defaultScanBuffer)
+		require.ErrorContains(t, err, "unsupported archive version 1.99.0")
+	})
+}
@@ -478,6 +478,7 @@ func init() {
 		&tree.ScheduledChangefeed{},
 		&tree.Import{},
 		&tree.ScheduledBackup{},
+		&tree.ScheduledRestoreDrill{},
 		&tree.CreateTenantFromReplication{},
 		&tree.CreateLogicalReplicationStream{},
 		&tree.CheckExternalConnection{},
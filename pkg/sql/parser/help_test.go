@@ -596,6 +596,8 @@ func TestContextualHelp(t *testing.T) {
 
 		{`RESTORE foo FROM LATEST IN '/bar' ??`, `RESTORE`},
 		{`RESTORE DATABASE ??`, `RESTORE`},
+		{`RESTORE INDEX foo@idx FROM LATEST IN '/bar' ??`, `RESTORE`},
+		{`RESTORE COLUMN foo.c FROM LATEST IN '/bar' ??`, `RESTORE`},
 
 		{`IMPORT INTO ??`, `IMPORT`},
 
@@ -605,6 +607,7 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE SCHEDULE ??`, `CREATE SCHEDULE`},
 		{`CREATE SCHEDULE FOR BACKUP ??`, `CREATE SCHEDULE FOR BACKUP`},
 		{`CREATE SCHEDULE FOR CHANGEFEED ??`, `CREATE SCHEDULE FOR CHANGEFEED`},
+		{`CREATE SCHEDULE FOR RESTORE DRILL ??`, `CREATE SCHEDULE FOR RESTORE DRILL`},
 		{`ALTER BACKUP SCHEDULE ??`, `ALTER BACKUP SCHEDULE`},
 
 		{`CREATE CHANGEFEED FOR foo ??`, `CREATE CHANGEFEED`},
@@ -763,6 +763,9 @@ func (u *sqlSymUnion) showBackupOptions() *tree.ShowBackupOptions {
 func (u *sqlSymUnion) showBackupTimeFilter() *tree.ShowBackupTimeFilter {
   return u.val.(*tree.ShowBackupTimeFilter)
 }
+func (u *sqlSymUnion) showBackupDiff() *tree.ShowBackupDiff {
+  return u.val.(*tree.ShowBackupDiff)
+}
 func (u *sqlSymUnion) checkExternalConnectionOptions() *tree.CheckExternalConnectionOptions {
   return u.val.(*tree.CheckExternalConnectionOptions)
 }
@@ -1015,15 +1018,15 @@ func (u *sqlSymUnion) filterType() tree.FilterType {
 %token <str> CHARACTER CHARACTERISTICS CHECK CHECK_FILES CLOSE
 %token <str> CLUSTER CLUSTERS COALESCE COLLATE COLLATION COLUMN COLUMNS COMMENT COMMENTS COMMIT
 %token <str> COMMITTED COMPACT COMPLETE COMPLETIONS CONCAT CONCURRENTLY CONFIGURATION CONFIGURATIONS CONFIGURE
-%token <str> CONFLICT CONNECTION CONNECTIONS CONSTRAINT CONSTRAINTS CONTAINS CONTROLCHANGEFEED CONTROLJOB
+%token <str> CONFLICT CONFLICT_POLICY CONNECTION CONNECTIONS CONSTRAINT CONSTRAINTS CONTAINS CONTROLCHANGEFEED CONTROLJOB
 %token <str> CONVERSION CONVERT COPY COS_DISTANCE COST COVERING CREATE CREATEDB CREATELOGIN CREATEROLE
 %token <str> CROSS CSV CUBE CURRENT CURRENT_CATALOG CURRENT_DATE CURRENT_SCHEMA
 %token <str> CURRENT_ROLE CURRENT_TIME CURRENT_TIMESTAMP
 %token <str> CURRENT_USER CURSOR CYCLE
 
 %token <str> DATA DATABASE DATABASES DATE DAY DEBUG_IDS DEC DECIMAL DEFAULT DEFAULTS DEFINER
-%token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DELIMITER DEPENDS DESC DESTINATION DETACHED DETAILS
-%token <str> DISABLE DISCARD DISTANCE DISTINCT DO DOMAIN DOUBLE DROP
+%token <str> DEALLOCATE DECLARE DEDUPLICATE DEFERRABLE DEFERRED DELETE DELIMITER DEPENDS DESC DESTINATION DETACHED DETAILS
+%token <str> DIFF DISABLE DISCARD DISTANCE DISTINCT DO DOMAIN DOUBLE DRILL DROP
 
 %token <str> EACH ELSE ENABLE ENCODING ENCRYPTED ENCRYPTION_PASSPHRASE END ENUM ENUMS ERRORS ESCAPE
 %token <str> EXCEPT EXCLUDE EXCLUDING EXPLICIT EXISTS EXECUTE EXECUTION EXPERIMENTAL
@@ -1087,7 +1090,7 @@ func (u *sqlSymUnion) filterType() tree.FilterType {
 %token <str> REGCLASS REGION REGIONAL REGIONS REGNAMESPACE REGPROC REGPROCEDURE REGROLE REGTYPE REINDEX
 %token <str> RELATIVE RELOCATE REMOVE_PATH REMOVE_REGIONS RENAME REPEATABLE REPLACE REPLICATED REPLICATION
 %token <str> RELEASE RESET RESOLVED RESTART RESTORE RESTRICT RESTRICTED RESTRICTIVE RESUME RETENTION RETURNING RETURN RETURNS REVISION REVISION_HISTORY
-%token <str> REVOKE RIGHT ROLE ROLES ROLLBACK ROLLUP ROUTINES ROW ROWS RSHIFT RULE RUN RUNNING
+%token <str> REVOKE RIGHT ROLE ROLES ROLLBACK ROLLUP ROUTINES ROW ROWS ROW_FILTER RSHIFT RULE RUN RUNNING
 
 %token <str> SAVEPOINT SCANS SCATTER SCHEDULE SCHEDULES SCROLL SCHEMA SCHEMA_ONLY SCHEMAS SCRUB
 %token <str> SEARCH SECOND SECONDARY SECURITY SECURITY_INVOKER SELECT SEQUENCE SEQUENCES
@@ -1309,6 +1312,7 @@ func (u *sqlSymUnion) filterType() tree.FilterType {
 %type <tree.Statement> create_index_stmt
 %type <tree.Statement> create_role_stmt
 %type <tree.Statement> create_schedule_for_backup_stmt
+%type <tree.Statement> create_schedule_for_restore_drill_stmt
 %type <tree.Statement> alter_backup_schedule
 %type <tree.Statement> create_schema_stmt
 %type <tree.Statement> create_table_stmt
@@ -1498,6 +1502,7 @@ func (u *sqlSymUnion) filterType() tree.FilterType {
 %type <*tree.ShowJobOptions> show_job_options show_job_options_list
 %type <*tree.ShowBackupOptions> opt_with_show_backup_options show_backup_options show_backup_options_list opt_with_show_backups_options show_backups_options
 %type <*tree.ShowBackupTimeFilter> opt_show_backups_time_filter_clause
+%type <*tree.ShowBackupDiff> opt_show_backup_diff_into_clause
 %type <*tree.CopyOptions> opt_with_copy_options copy_options copy_options_list copy_generic_options copy_generic_options_list
 %type <str> import_format
 %type <str> storage_parameter_key
@@ -3577,6 +3582,7 @@ opt_clear_data:
 //    kms="[kms_provider]://[kms_host]/[master_key_identifier]?[parameters]" : encrypt backups using KMS
 //    detached: execute backup job asynchronously, without waiting for its completion
 //    include_all_virtual_clusters: enable backups of all virtual clusters during a cluster backup
+//    deduplicate: reference unchanged data files of an earlier full backup in the collection
 //
 // %SeeAlso: RESTORE, WEBDOCS/backup.html
 backup_stmt:
@@ -3711,6 +3717,14 @@ backup_options:
   {
     $$.val = &tree.BackupOptions{Strict: true}
   }
+| DEDUPLICATE
+  {
+    $$.val = &tree.BackupOptions{Deduplicate: tree.MakeDBool(true)}
+  }
+| DEDUPLICATE '=' a_expr
+  {
+    $$.val = &tree.BackupOptions{Deduplicate: $3.expr()}
+  }
 
 include_all_clusters:
   INCLUDE_ALL_SECONDARY_TENANTS { /* SKIP DOC */ }
@@ -3806,6 +3820,52 @@ create_schedule_for_backup_stmt:
   }
  | CREATE SCHEDULE schedule_label_spec FOR BACKUP error // SHOW HELP: CREATE SCHEDULE FOR BACKUP
 
+// %Help: CREATE SCHEDULE FOR RESTORE DRILL - verify backups restore periodically
+// %Category: CCL
+// %Text:
+// CREATE SCHEDULE [IF NOT EXISTS]
+// [<description>]
+// FOR RESTORE DRILL FROM LATEST IN <collection...>
+// [WITH <option>[=<value>] [, ...]]
+// RECURRING [crontab]
+// [WITH EXPERIMENTAL SCHEDULE OPTIONS <schedule_option>[= <value>] [, ...] ]
+//
+// Each run of the schedule restores the tables of the latest backup in the
+// collection into temporary databases, compares the fingerprints of the
+// restored tables with those recorded when the backup was taken, and drops the
+// temporary databases.
+//
+// Description:
+//   Optional description (or name) for this schedule
+//
+// WITH <options>:
+//   sample_size=<n>: restore and verify only a random sample of n tables.
+//
+// RECURRING <crontab>:
+//   Schedule specified as a string in crontab format.
+//   All times in UTC.
+//     "@monthly": run monthly, at midnight of the first day of the month
+//   See https://en.wikipedia.org/wiki/Cron
+//
+// SCHEDULE OPTIONS:
+//   first_run, on_execution_failure, on_previous_running: See CREATE SCHEDULE FOR BACKUP.
+//
+// %SeeAlso: RESTORE, CREATE SCHEDULE FOR BACKUP
+create_schedule_for_restore_drill_stmt:
+  CREATE SCHEDULE /*$3=*/schedule_label_spec FOR RESTORE DRILL FROM LATEST IN
+  /*$10=*/string_or_placeholder_opt_list /*$11=*/opt_with_options
+  /*$12=*/cron_expr /*$13=*/opt_with_schedule_options
+  {
+    $$.val = &tree.ScheduledRestoreDrill{
+      ScheduleLabelSpec: *($3.scheduleLabelSpec()),
+      From:              $10.stringOrPlaceholderOptList(),
+      Options:           $11.kvOptions(),
+      Recurrence:        $12.expr(),
+      ScheduleOptions:   $13.kvOptions(),
+    }
+  }
+| CREATE SCHEDULE schedule_label_spec FOR RESTORE DRILL error // SHOW HELP: CREATE SCHEDULE FOR RESTORE DRILL
+
 // %Help: ALTER BACKUP SCHEDULE - alter an existing backup schedule
 // %Category: CCL
 // %Text:
@@ -4102,6 +4162,23 @@ drop_external_connection_stmt:
 // RESTORE SYSTEM USERS FROM <location...>
 //         [ AS OF SYSTEM TIME <expr> ]
 //         [ WITH <option> [= <value>] [, ...] ]
+// or
+// RESTORE INDEX <tablename>@<indexname> FROM <location...>
+//         [ AS OF SYSTEM TIME <expr> ]
+//         [ WITH <option> [= <value>] [, ...] ]
+// or
+// RESTORE COLUMN <tablename>.<columnname> FROM <location...>
+//         [ AS OF SYSTEM TIME <expr> ]
+//         [ WITH <option> [= <value>] [, ...] ]
+//
+// RESTORE INDEX runs CREATE INDEX on the live table with the definition the
+// index has in the backup. It does not read any data from the backup: the
+// index is built from the current rows of the table.
+//
+// RESTORE COLUMN restores the table as of the backup into a temporary
+// database, then copies the column into the rows of the live table with the
+// same primary key, in batches. Rows which are not in the backup are not
+// changed.
 //
 // Targets:
 //    TABLE <pattern> [, ...]
@@ -4122,6 +4199,8 @@ drop_external_connection_stmt:
 //    detached: execute restore job asynchronously, without waiting for its completion
 //    skip_localities_check: ignore difference of zone configuration between restore cluster and backup cluster
 //    new_db_name: renames the restored database. only applies to database restores
+//    row_filter: only restore the rows of a single table matching a predicate on its primary key columns
+//    columns: only restore the given columns, besides its primary key columns, of a single table
 //    include_all_virtual_clusters: enable backups of all virtual clusters during a cluster backup
 // %SeeAlso: BACKUP, WEBDOCS/restore.html
 restore_stmt:
@@ -4170,6 +4249,38 @@ restore_stmt:
       Options: *($9.restoreOptions()),
     }
   }
+| RESTORE INDEX table_name '@' index_name FROM string_or_placeholder IN string_or_placeholder_opt_list opt_as_of_clause opt_with_restore_options
+  {
+    name := $3.unresolvedObjectName().ToTableName()
+    $$.val = &tree.Restore{
+      Index: &tree.TableIndexName{Table: name, Index: tree.UnrestrictedName($5)},
+      Subdir: $7.expr(),
+      From: $9.stringOrPlaceholderOptList(),
+      AsOf: $10.asOfClause(),
+      Options: *($11.restoreOptions()),
+    }
+  }
+| RESTORE COLUMN column_path FROM string_or_placeholder IN string_or_placeholder_opt_list opt_as_of_clause opt_with_restore_options
+  {
+    varName, err := $3.unresolvedName().NormalizeVarName()
+    if err != nil {
+      return setErr(sqllex, err)
+    }
+    columnItem, ok := varName.(*tree.ColumnItem)
+    if !ok || columnItem.TableName == nil {
+      sqllex.Error(fmt.Sprintf("invalid column name: %q", tree.ErrString($3.unresolvedName())))
+      return 1
+    }
+    aIdx := sqllex.(*lexer).NewAnnotation()
+    columnItem.TableName.AnnotatedNode = tree.AnnotatedNode{AnnIdx: aIdx}
+    $$.val = &tree.Restore{
+      Column: columnItem,
+      Subdir: $5.expr(),
+      From: $7.stringOrPlaceholderOptList(),
+      AsOf: $8.asOfClause(),
+      Options: *($9.restoreOptions()),
+    }
+  }
 | RESTORE error // SHOW HELP: RESTORE
 
 string_or_placeholder_opt_list:
@@ -4296,6 +4407,14 @@ restore_options:
   {
     $$.val = &tree.RestoreOptions{Grants: true}
   }
+| ROW_FILTER '=' string_or_placeholder
+  {
+    $$.val = &tree.RestoreOptions{RowFilter: $3.expr()}
+  }
+| COLUMNS '=' string_or_placeholder_opt_list
+  {
+    $$.val = &tree.RestoreOptions{Columns: $3.stringOrPlaceholderOptList()}
+  }
 
 virtual_cluster_opt:
   TENANT  { /* SKIP DOC */ }
@@ -4324,12 +4443,30 @@ alter_unsupported_stmt:
 // %Help: IMPORT - load data from file in a distributed manner
 // %Category: CCL
 // %Text:
+// -- Import both schema and table data:
+// IMPORT <format> <datafile>
+//        [WITH <option> [= <value>] [, ...]]
+//
+// Formats:
+//    PGDUMP
+//    MYSQLDUMP
+//
+// Options:
+//    max_row_size = '...'
+//    log_ignored_statements = '...'
+//    decompress = '...'
+//    detached
+//
 // Use CREATE TABLE followed by IMPORT INTO to create and import into a table
 // from external files that only have table data.
 //
 // %SeeAlso: CREATE TABLE, WEBDOCS/import-into.html
 import_stmt:
- IMPORT INTO table_name '(' insert_column_list ')' import_format DATA '(' string_or_placeholder_list ')' opt_with_options
+ IMPORT import_format string_or_placeholder opt_with_options
+  {
+    $$.val = &tree.Import{Bundle: true, FileFormat: $2, Files: tree.Exprs{$3.expr()}, Options: $4.kvOptions()}
+  }
+| IMPORT INTO table_name '(' insert_column_list ')' import_format DATA '(' string_or_placeholder_list ')' opt_with_options
   {
     name := $3.unresolvedObjectName().ToTableName()
     $$.val = &tree.Import{Table: &name, IntoCols: $5.nameList(), FileFormat: $7, Files: $10.exprs(), Options: $12.kvOptions()}
@@ -4349,6 +4486,7 @@ import_stmt:
 // Formats:
 //    CSV
 //    Parquet
+//    NDJSON
 //
 // Options:
 //    delimiter = '...'   [CSV-specific]
@@ -4888,7 +5026,8 @@ check_stmt:
 //  < CURSOR = start_time > |
 //  < DEFAULT FUNCTION = lww | dlq | udf
 //  < FUNCTION 'udf' FOR TABLE local_name  , ... > |
-//  < DISCARD = 'ttl-deletes' >
+//  < DISCARD = 'ttl-deletes' > |
+//  < CONFLICT_POLICY = 'policy' [FOR TABLE local_name] , ... >
 // ]
 create_logical_replication_stream_stmt:
   CREATE LOGICAL REPLICATION STREAM FROM logical_replication_resources ON string_or_placeholder INTO logical_replication_resources opt_logical_replication_options
@@ -5035,6 +5174,14 @@ logical_replication_options:
   {
     $$.val = &tree.LogicalReplicationOptions{MetricsLabel: $3.expr()}
   }
+| CONFLICT_POLICY '=' string_or_placeholder
+  {
+    $$.val = &tree.LogicalReplicationOptions{DefaultConflictPolicy: $3.expr()}
+  }
+| CONFLICT_POLICY '=' string_or_placeholder FOR_TABLE TABLE db_object_name
+  {
+     $$.val = &tree.LogicalReplicationOptions{ConflictPolicies: map[tree.UnresolvedName]tree.Expr{*$6.unresolvedObjectName().ToUnresolvedName():$3.expr()}}
+  }
 | PARENT '=' string_or_placeholder
   /* SKIP DOC */
   {
@@ -5182,11 +5329,13 @@ source_replication_options:
 // %Category: Group
 // %Text:
 // CREATE SCHEDULE FOR BACKUP,
-// CREATE SCHEDULE FOR CHANGEFEED
+// CREATE SCHEDULE FOR CHANGEFEED,
+// CREATE SCHEDULE FOR RESTORE DRILL
 create_schedule_stmt:
-  create_schedule_for_changefeed_stmt // EXTEND WITH HELP: CREATE SCHEDULE FOR CHANGEFEED
-| create_schedule_for_backup_stmt     // EXTEND WITH HELP: CREATE SCHEDULE FOR BACKUP
-| CREATE SCHEDULE error               // SHOW HELP: CREATE SCHEDULE
+  create_schedule_for_changefeed_stmt    // EXTEND WITH HELP: CREATE SCHEDULE FOR CHANGEFEED
+| create_schedule_for_backup_stmt        // EXTEND WITH HELP: CREATE SCHEDULE FOR BACKUP
+| create_schedule_for_restore_drill_stmt // EXTEND WITH HELP: CREATE SCHEDULE FOR RESTORE DRILL
+| CREATE SCHEDULE error                  // SHOW HELP: CREATE SCHEDULE
 
 // %Help: CREATE EXTENSION - pseudo-statement for PostgreSQL compatibility
 // %Category: Cfg
@@ -9135,7 +9284,9 @@ show_histogram_stmt:
 
 // %Help: SHOW BACKUP - list backup contents
 // %Category: CCL
-// %Text: SHOW BACKUP [SCHEMAS|FILES|RANGES] <location>
+// %Text:
+// SHOW BACKUP [SCHEMAS|FILES|RANGES] <location>
+// SHOW BACKUP DIFF FROM <backup> TO <backup> IN <collection> [FOR TABLE <name>] [INTO PARQUET <destination>]
 // %SeeAlso: WEBDOCS/show-backup.html
 show_backup_stmt:
   SHOW BACKUPS IN string_or_placeholder_opt_list opt_show_backups_time_filter_clause opt_with_show_backups_options
@@ -9165,6 +9316,20 @@ show_backup_stmt:
 			Options: *$6.showBackupOptions(),
 		}
 	}
+| SHOW BACKUP DIFF FROM string_or_placeholder TO string_or_placeholder IN string_or_placeholder_opt_list opt_for_table_clause opt_show_backup_diff_into_clause opt_with_show_backup_options
+	{
+		diff := $11.showBackupDiff()
+		diff.To = $7.expr()
+		diff.Table = $10.tableNamePtr()
+		$$.val = &tree.ShowBackup{
+			From:    true,
+			Details:    tree.BackupDiffDetails,
+			Path:    $5.expr(),
+			InCollection: $9.stringOrPlaceholderOptList(),
+			Options: *$12.showBackupOptions(),
+			Diff: diff,
+		}
+	}
 | SHOW BACKUP string_or_placeholder opt_with_show_backup_options error
 	{
     setErr(sqllex, errors.New("The `SHOW BACKUP` syntax without the `IN` keyword is no longer supported. Please use `SHOW BACKUP FROM <subdirectory> IN <collectionURI>`."))
@@ -9201,6 +9366,16 @@ show_backup_stmt:
 	}
 | SHOW BACKUP error // SHOW HELP: SHOW BACKUP
 
+opt_show_backup_diff_into_clause:
+  INTO import_format string_or_placeholder
+  {
+    $$.val = &tree.ShowBackupDiff{FileFormat: $2, File: $3.expr()}
+  }
+| /* EMPTY */
+  {
+    $$.val = &tree.ShowBackupDiff{}
+  }
+
 show_backup_details:
   /* EMPTY -- default */
   {
@@ -9793,7 +9968,7 @@ show_job_options:
 // %Help: SHOW SCHEDULES - list periodic schedules
 // %Category: Misc
 // %Text:
-// SHOW [RUNNING | PAUSED] SCHEDULES [FOR BACKUP]
+// SHOW [RUNNING | PAUSED] SCHEDULES [FOR BACKUP | FOR CHANGEFEED | FOR RESTORE DRILL]
 // SHOW SCHEDULE <schedule_id>
 // %SeeAlso: PAUSE SCHEDULES, RESUME SCHEDULES, DROP SCHEDULES, EXECUTE SCHEDULES
 show_schedules_stmt:
@@ -9849,6 +10024,10 @@ opt_schedule_executor_type:
 	{
 		$$.val = tree.ScheduledChangefeedExecutor
 	}
+| FOR RESTORE DRILL
+	{
+		$$.val = tree.ScheduledRestoreDrillExecutor
+	}
 
 // %Help: SHOW TRACE - display an execution trace
 // %Category: Misc
@@ -18957,6 +19136,7 @@ unreserved_keyword:
 | COMPLETE
 | COMPLETIONS
 | CONFLICT
+| CONFLICT_POLICY
 | CONFIGURATION
 | CONFIGURATIONS
 | CONFIGURE
@@ -18985,6 +19165,7 @@ unreserved_keyword:
 | DEALLOCATE
 | DEBUG_IDS
 | DECLARE
+| DEDUPLICATE
 | DELETE
 | DEFAULTS
 | DEFERRED
@@ -18994,10 +19175,12 @@ unreserved_keyword:
 | DESTINATION
 | DETACHED
 | DETAILS
+| DIFF
 | DISABLE
 | DISCARD
 | DOMAIN
 | DOUBLE
+| DRILL
 | DROP
 | EACH
 | ENABLE
@@ -19270,6 +19453,7 @@ unreserved_keyword:
 | ROLLUP
 | ROUTINES
 | ROWS
+| ROW_FILTER
 | RULE
 | RUN
 | RUNNING
@@ -19487,6 +19671,7 @@ bare_label_keywords:
 | CONFIGURATIONS
 | CONFIGURE
 | CONFLICT
+| CONFLICT_POLICY
 | CONNECTION
 | CONNECTIONS
 | CONSTRAINT
@@ -19522,6 +19707,7 @@ bare_label_keywords:
 | DEC
 | DECIMAL
 | DECLARE
+| DEDUPLICATE
 | DEFAULT
 | DEFAULTS
 | DEFERRABLE
@@ -19534,12 +19720,14 @@ bare_label_keywords:
 | DESTINATION
 | DETACHED
 | DETAILS
+| DIFF
 | DISABLE
 | DISCARD
 | DISTINCT
 | DO
 | DOMAIN
 | DOUBLE
+| DRILL
 | DROP
 | EACH
 | ELSE
@@ -19866,6 +20054,7 @@ bare_label_keywords:
 | ROUTINES
 | ROW
 | ROWS
+| ROW_FILTER
 | RULE
 | RUN
 | RUNNING
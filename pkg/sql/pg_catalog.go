@@ -17,6 +17,8 @@ import (
 	"unicode"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catenumpb"
@@ -40,6 +42,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/cast"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/idxtype"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/semenumpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -56,6 +59,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
 )
@@ -2477,13 +2481,62 @@ https://www.postgresql.org/docs/9.5/catalog-pg-language.html`,
 }
 
 var pgCatalogLocksTable = virtualSchemaTable{
-	comment: `locks held by active processes (empty - feature does not exist)
+	comment: `advisory locks held or awaited by active transactions
 https://www.postgresql.org/docs/9.6/view-pg-locks.html`,
 	schema: vtable.PGCatalogLocks,
 	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+		lockMode := func(strength lock.Strength) tree.Datum {
+			if strength == lock.Shared {
+				return tree.NewDString("ShareLock")
+			}
+			return tree.NewDString("ExclusiveLock")
+		}
+		return forEachDatabaseDesc(ctx, p, dbContext, true, /* requiresPrivileges */
+			func(ctx context.Context, db catalog.DatabaseDescriptor) error {
+				var prevKey roachpb.Key
+				return forEachAdvisoryLock(ctx, p, db.GetID(), func(key eval.AdvisoryLockKey, l roachpb.LockStateInfo) error {
+					addLockRow := func(txnID uuid.UUID, strength lock.Strength, granted bool) error {
+						return addRow(
+							tree.NewDString("advisory"),     // locktype
+							dbOid(db.GetID()),               // database
+							tree.DNull,                      // relation
+							tree.DNull,                      // page
+							tree.DNull,                      // tuple
+							tree.DNull,                      // virtualxid
+							tree.DNull,                      // transactionid
+							tree.NewDOid(oid.Oid(key.Key1)), // classid
+							tree.NewDOid(oid.Oid(key.Key2)), // objid
+							tree.NewDInt(tree.DInt(advisoryLockSubID(key))), // objsubid
+							tree.NewDString(txnID.String()),                 // virtualtransaction
+							tree.DNull,                                      // pid
+							lockMode(strength),                              // mode
+							tree.MakeDBool(tree.DBool(granted)),             // granted
+							tree.DBoolFalse,                                 // fastpath
+						)
+					}
+					if l.LockHolder != nil {
+						if err := addLockRow(l.LockHolder.ID, l.LockStrength, true /* granted */); err != nil {
+							return err
+						}
+					}
+					// A key locked by several shared holders is returned once per
+					// holder, each time with the same waiters.
+					if l.Key.Equal(prevKey) {
+						return nil
+					}
+					prevKey = l.Key
+					for _, w := range l.Waiters {
+						if w.WaitingTxn == nil {
+							continue
+						}
+						if err := addLockRow(w.WaitingTxn.ID, w.Strength, false /* granted */); err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogMatViewsTable = virtualSchemaTable{
@@ -248,6 +248,12 @@ type planner struct {
 
 	sqlCursors sqlCursors
 
+	// advisoryLocks is the advisory lock manager of the session. Every
+	// connExecutor has one, including those of the internal executor; it is
+	// nil only for planners created outside of a session, such as by
+	// NewInternalPlanner, which do not support advisory locks.
+	advisoryLocks *advisoryLockManager
+
 	// routineMetadataForwarder, if set, is used to propagate ProducerMetadata
 	// out of the routine execution.
 	// TODO(yuzefovich): this is rather ugly, but the routines are expressions,
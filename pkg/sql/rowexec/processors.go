@@ -279,8 +279,11 @@ func NewProcessor(
 			return nil, err
 		}
 
-		if core.Exporter.Format.Format == roachpb.IOFileFormat_Parquet {
+		switch core.Exporter.Format.Format {
+		case roachpb.IOFileFormat_Parquet:
 			return export.NewParquetWriterProcessor(ctx, flowCtx, processorID, *core.Exporter, post, inputs[0])
+		case roachpb.IOFileFormat_NDJSON:
+			return export.NewNDJSONWriterProcessor(ctx, flowCtx, processorID, *core.Exporter, post, inputs[0])
 		}
 		return export.NewCSVWriterProcessor(ctx, flowCtx, processorID, *core.Exporter, post, inputs[0])
 	}
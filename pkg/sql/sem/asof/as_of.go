@@ -157,7 +157,11 @@ func Eval(
 	// All non-function expressions must be const and must TypeCheck into a
 	// string.
 	var te tree.TypedExpr
+	// forwardToCausalityToken is set for expressions which allow data to be
+	// read at a timestamp later than the one they evaluate to.
+	var forwardToCausalityToken bool
 	if asOfFuncExpr, ok := asOf.Expr.(*tree.FuncExpr); ok {
+		forwardToCausalityToken = true
 		switch resolveFuncType(ctx, asOf, semaCtx.SearchPath) {
 		case funcTypeFollowerRead:
 		case funcTypeBoundedStaleness:
@@ -222,6 +226,11 @@ func Eval(
 	if err != nil {
 		return eval.AsOfSystemTime{}, errors.Wrap(err, "AS OF SYSTEM TIME")
 	}
+	// Follower reads and bounded staleness reads must observe the writes which
+	// precede the session's causality token, if any.
+	if sd := evalCtx.SessionData(); forwardToCausalityToken && sd != nil {
+		ret.Timestamp.Forward(sd.CausalityToken)
+	}
 	return ret, nil
 }
 
@@ -1395,7 +1395,7 @@ var builtinOidsArray = []string{
 	1424: `obj_description(object_oid: oid, catalog_name: string) -> string`,
 	1425: `oid(int: int) -> oid`,
 	1426: `shobj_description(object_oid: oid, catalog_name: string) -> string`,
-	1427: `pg_try_advisory_lock(int: int) -> bool`,
+	1427: `pg_try_advisory_lock(key: int) -> bool`,
 	1428: `pg_advisory_unlock(key: int) -> bool`,
 	1429: `pg_client_encoding() -> string`,
 	1430: `pg_function_is_visible(oid: oid) -> bool`,
@@ -2903,6 +2903,21 @@ var builtinOidsArray = []string{
 	2948: `information_schema.crdb_delete_statement_hints(statement_fingerprint: string, database: string) -> int`,
 	2949: `information_schema.crdb_enable_statement_hints(enabled: bool, statement_fingerprint: string, database: string) -> int`,
 	2950: `pg_get_statisticsobjdef(statobj_oid: oid) -> string`,
+	2951: `pg_try_advisory_lock(key1: int4, key2: int4) -> bool`,
+	2952: `pg_advisory_lock(key: int) -> void`,
+	2953: `pg_advisory_lock(key1: int4, key2: int4) -> void`,
+	2954: `pg_advisory_lock_shared(key: int) -> void`,
+	2955: `pg_advisory_lock_shared(key1: int4, key2: int4) -> void`,
+	2956: `pg_try_advisory_lock_shared(key: int) -> bool`,
+	2957: `pg_try_advisory_lock_shared(key1: int4, key2: int4) -> bool`,
+	2958: `pg_advisory_xact_lock(key: int) -> void`,
+	2959: `pg_advisory_xact_lock(key1: int4, key2: int4) -> void`,
+	2960: `pg_advisory_xact_lock_shared(key: int) -> void`,
+	2961: `pg_advisory_xact_lock_shared(key1: int4, key2: int4) -> void`,
+	2962: `pg_try_advisory_xact_lock(key: int) -> bool`,
+	2963: `pg_try_advisory_xact_lock(key1: int4, key2: int4) -> bool`,
+	2964: `pg_try_advisory_xact_lock_shared(key: int) -> bool`,
+	2965: `pg_try_advisory_xact_lock_shared(key1: int4, key2: int4) -> bool`,
 }
 
 var builtinOidsBySignature map[string]oid.Oid
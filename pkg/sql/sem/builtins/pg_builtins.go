@@ -499,6 +499,105 @@ func makePGPrivilegeInquiryDef(
 	)
 }
 
+// advisoryLocker returns the advisory lock manager of the session.
+func advisoryLocker(evalCtx *eval.Context) (eval.AdvisoryLocker, error) {
+	if evalCtx.AdvisoryLocker == nil {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"advisory locks are not supported in this context")
+	}
+	return evalCtx.AdvisoryLocker, nil
+}
+
+// makeAdvisoryLockOverloads returns the overloads of an advisory lock builtin,
+// which identify the lock by a bigint or by two int4s.
+func makeAdvisoryLockOverloads(
+	returnType *types.T,
+	info string,
+	fn func(ctx context.Context, evalCtx *eval.Context, key eval.AdvisoryLockKey) (tree.Datum, error),
+) []tree.Overload {
+	return []tree.Overload{
+		{
+			Types:      tree.ParamTypes{{Name: "key", Typ: types.Int}},
+			ReturnType: tree.FixedReturnType(returnType),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				key := uint64(tree.MustBeDInt(args[0]))
+				return fn(ctx, evalCtx, eval.AdvisoryLockKey{
+					Key1: uint32(key >> 32),
+					Key2: uint32(key),
+				})
+			},
+			Info:       info,
+			Volatility: volatility.Volatile,
+		},
+		{
+			Types:      tree.ParamTypes{{Name: "key1", Typ: types.Int4}, {Name: "key2", Typ: types.Int4}},
+			ReturnType: tree.FixedReturnType(returnType),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return fn(ctx, evalCtx, eval.AdvisoryLockKey{
+					Key1:    uint32(int32(tree.MustBeDInt(args[0]))),
+					Key2:    uint32(int32(tree.MustBeDInt(args[1]))),
+					TwoPart: true,
+				})
+			},
+			Info:       info,
+			Volatility: volatility.Volatile,
+		},
+	}
+}
+
+// makeAdvisoryLockBuiltin returns a builtin that acquires an advisory lock.
+// Builtins that do not wait return whether they acquired the lock.
+func makeAdvisoryLockBuiltin(req eval.AdvisoryLockRequest, info string) builtinDefinition {
+	returnType := types.Bool
+	if req.Wait {
+		returnType = types.Void
+	}
+	return makeBuiltin(
+		tree.FunctionProperties{
+			DistsqlBlocklist: true,
+		},
+		makeAdvisoryLockOverloads(returnType, info,
+			func(ctx context.Context, evalCtx *eval.Context, key eval.AdvisoryLockKey) (tree.Datum, error) {
+				locker, err := advisoryLocker(evalCtx)
+				if err != nil {
+					return nil, err
+				}
+				req := req
+				req.Key = key
+				ok, err := locker.AcquireAdvisoryLock(ctx, req)
+				if err != nil {
+					return nil, err
+				}
+				if req.Wait {
+					return tree.DVoidDatum, nil
+				}
+				return tree.MakeDBool(tree.DBool(ok)), nil
+			})...,
+	)
+}
+
+// makeAdvisoryUnlockBuiltin returns a builtin that releases a session-level
+// advisory lock.
+func makeAdvisoryUnlockBuiltin(shared bool, info string) builtinDefinition {
+	return makeBuiltin(
+		tree.FunctionProperties{
+			DistsqlBlocklist: true,
+		},
+		makeAdvisoryLockOverloads(types.Bool, info,
+			func(ctx context.Context, evalCtx *eval.Context, key eval.AdvisoryLockKey) (tree.Datum, error) {
+				locker, err := advisoryLocker(evalCtx)
+				if err != nil {
+					return nil, err
+				}
+				ok, err := locker.ReleaseAdvisoryLock(ctx, key, shared)
+				if err != nil {
+					return nil, err
+				}
+				return tree.MakeDBool(tree.DBool(ok)), nil
+			})...,
+	)
+}
+
 // getNameForArg determines the object name for the specified argument, which
 // should be either an unwrapped STRING or an OID. If the object is not found,
 // the returned string will be empty.
@@ -1309,68 +1408,80 @@ FROM defaults_parsed
 		},
 	),
 
-	"pg_try_advisory_lock": makeBuiltin(defProps(),
-		tree.Overload{
-			Types:      tree.ParamTypes{{Name: "int", Typ: types.Int}},
-			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ context.Context, _ *eval.Context, _ tree.Datums) (tree.Datum, error) {
-				return tree.DBoolTrue, nil
-			},
-			Info:       notUsableInfo,
-			Volatility: volatility.Volatile,
-		},
+	"pg_advisory_lock": makeAdvisoryLockBuiltin(
+		eval.AdvisoryLockRequest{Wait: true},
+		"Acquires an exclusive session-level advisory lock, waiting for it if necessary.",
 	),
 
-	"pg_advisory_unlock": makeBuiltin(defProps(),
-		tree.Overload{
-			Types:      tree.ParamTypes{{Name: "key", Typ: types.Int}},
-			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ context.Context, _ *eval.Context, _ tree.Datums) (tree.Datum, error) {
-				return tree.DBoolTrue, nil
-			},
-			Info:       notUsableInfo,
-			Volatility: volatility.Volatile,
-		},
-		tree.Overload{
-			Types:      tree.ParamTypes{{Name: "key1", Typ: types.Int4}, {Name: "key2", Typ: types.Int4}},
-			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ context.Context, _ *eval.Context, _ tree.Datums) (tree.Datum, error) {
-				return tree.DBoolTrue, nil
-			},
-			Info:       notUsableInfo,
-			Volatility: volatility.Volatile,
-		},
+	"pg_advisory_lock_shared": makeAdvisoryLockBuiltin(
+		eval.AdvisoryLockRequest{Shared: true, Wait: true},
+		"Acquires a shared session-level advisory lock, waiting for it if necessary.",
 	),
 
-	"pg_advisory_unlock_shared": makeBuiltin(defProps(),
-		tree.Overload{
-			Types:      tree.ParamTypes{{Name: "key", Typ: types.Int}},
-			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ context.Context, _ *eval.Context, _ tree.Datums) (tree.Datum, error) {
-				return tree.DBoolTrue, nil
-			},
-			Info:       notUsableInfo,
-			Volatility: volatility.Volatile,
-		},
-		tree.Overload{
-			Types:      tree.ParamTypes{{Name: "key1", Typ: types.Int4}, {Name: "key2", Typ: types.Int4}},
-			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ context.Context, _ *eval.Context, _ tree.Datums) (tree.Datum, error) {
-				return tree.DBoolTrue, nil
-			},
-			Info:       notUsableInfo,
-			Volatility: volatility.Volatile,
-		},
+	"pg_try_advisory_lock": makeAdvisoryLockBuiltin(
+		eval.AdvisoryLockRequest{},
+		"Acquires an exclusive session-level advisory lock if it is available. "+
+			"Returns true if the lock was acquired.",
+	),
+
+	"pg_try_advisory_lock_shared": makeAdvisoryLockBuiltin(
+		eval.AdvisoryLockRequest{Shared: true},
+		"Acquires a shared session-level advisory lock if it is available. "+
+			"Returns true if the lock was acquired.",
+	),
+
+	"pg_advisory_xact_lock": makeAdvisoryLockBuiltin(
+		eval.AdvisoryLockRequest{Xact: true, Wait: true},
+		"Acquires an exclusive transaction-level advisory lock, waiting for it if necessary.",
+	),
+
+	"pg_advisory_xact_lock_shared": makeAdvisoryLockBuiltin(
+		eval.AdvisoryLockRequest{Shared: true, Xact: true, Wait: true},
+		"Acquires a shared transaction-level advisory lock, waiting for it if necessary.",
+	),
+
+	"pg_try_advisory_xact_lock": makeAdvisoryLockBuiltin(
+		eval.AdvisoryLockRequest{Xact: true},
+		"Acquires an exclusive transaction-level advisory lock if it is available. "+
+			"Returns true if the lock was acquired.",
+	),
+
+	"pg_try_advisory_xact_lock_shared": makeAdvisoryLockBuiltin(
+		eval.AdvisoryLockRequest{Shared: true, Xact: true},
+		"Acquires a shared transaction-level advisory lock if it is available. "+
+			"Returns true if the lock was acquired.",
+	),
+
+	"pg_advisory_unlock": makeAdvisoryUnlockBuiltin(
+		false, /* shared */
+		"Releases an exclusive session-level advisory lock. "+
+			"Returns false if the session did not hold the lock.",
+	),
+
+	"pg_advisory_unlock_shared": makeAdvisoryUnlockBuiltin(
+		true, /* shared */
+		"Releases a shared session-level advisory lock. "+
+			"Returns false if the session did not hold the lock.",
 	),
 
-	"pg_advisory_unlock_all": makeBuiltin(defProps(),
+	"pg_advisory_unlock_all": makeBuiltin(
+		tree.FunctionProperties{
+			DistsqlBlocklist: true,
+		},
 		tree.Overload{
 			Types:      tree.ParamTypes{},
 			ReturnType: tree.FixedReturnType(types.Void),
-			Fn: func(_ context.Context, _ *eval.Context, _ tree.Datums) (tree.Datum, error) {
+			Fn: func(ctx context.Context, evalCtx *eval.Context, _ tree.Datums) (tree.Datum, error) {
+				locker, err := advisoryLocker(evalCtx)
+				if err != nil {
+					return nil, err
+				}
+				if err := locker.ReleaseAllAdvisoryLocks(ctx); err != nil {
+					return nil, err
+				}
 				return tree.DVoidDatum, nil
 			},
-			Info:       notUsableInfo,
+			Info:       "Releases all session-level advisory locks held by the current session.",
 			Volatility: volatility.Volatile,
 		},
 	),
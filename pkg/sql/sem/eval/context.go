@@ -194,6 +194,8 @@ type Context struct {
 
 	PreparedStatementState PreparedStatementState
 
+	AdvisoryLocker AdvisoryLocker
+
 	// The transaction in which the statement is executing.
 	Txn *kv.Txn
 
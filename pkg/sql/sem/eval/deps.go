@@ -640,6 +640,48 @@ type PreparedStatementState interface {
 	HasPortal(s string) bool
 }
 
+// AdvisoryLockKey identifies an advisory lock. As in Postgres, locks taken
+// with a single bigint key and locks taken with two int4 keys are in separate
+// key spaces.
+type AdvisoryLockKey struct {
+	// Key1 and Key2 are the high and low halves of a bigint key, or the two
+	// int4 keys.
+	Key1, Key2 uint32
+	// TwoPart is true if the lock was taken with two int4 keys.
+	TwoPart bool
+}
+
+// AdvisoryLockRequest is a request to acquire an advisory lock.
+type AdvisoryLockRequest struct {
+	Key AdvisoryLockKey
+	// Shared is true for a shared lock and false for an exclusive lock.
+	Shared bool
+	// Xact is true if the lock is released when the transaction finishes
+	// instead of when the session releases it.
+	Xact bool
+	// Wait is false if the request should fail instead of waiting for a
+	// conflicting lock to be released.
+	Wait bool
+}
+
+// AdvisoryLocker acquires and releases the advisory locks of a session. It is
+// only available on the gateway node.
+type AdvisoryLocker interface {
+	// AcquireAdvisoryLock acquires an advisory lock in the current database.
+	// It returns false if the request does not wait and the lock is held by
+	// another session.
+	AcquireAdvisoryLock(ctx context.Context, req AdvisoryLockRequest) (bool, error)
+
+	// ReleaseAdvisoryLock releases one hold of a session-level advisory lock
+	// in the current database. It returns false if the session does not hold
+	// the lock.
+	ReleaseAdvisoryLock(ctx context.Context, key AdvisoryLockKey, shared bool) (bool, error)
+
+	// ReleaseAllAdvisoryLocks releases all session-level advisory locks held
+	// by the session.
+	ReleaseAllAdvisoryLocks(ctx context.Context) error
+}
+
 // ClientNoticeSender is a limited interface to send notices to the
 // client.
 //
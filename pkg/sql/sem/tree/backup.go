@@ -42,6 +42,7 @@ type BackupOptions struct {
 	EncryptionKMSURI                StringOrPlaceholderOptList
 	ExecutionLocality               Expr
 	UpdatesClusterMonitoringMetrics Expr
+	Deduplicate                     Expr
 	Strict                          bool
 }
 
@@ -129,6 +130,8 @@ type RestoreOptions struct {
 	ExperimentalCopy                 bool
 	RemoveRegions                    bool
 	Grants                           bool
+	RowFilter                        Expr
+	Columns                          StringOrPlaceholderOptList
 }
 
 func (opts *RestoreOptions) OnlineImpl() bool {
@@ -142,6 +145,14 @@ type Restore struct {
 	Targets            BackupTargetList
 	DescriptorCoverage DescriptorCoverage
 
+	// Index is set for RESTORE INDEX, which restores a single index of a table
+	// which still exists, instead of Targets.
+	Index *TableIndexName
+
+	// Column is set for RESTORE COLUMN, which restores the data of a single
+	// column of a table which still exists, instead of Targets.
+	Column *ColumnItem
+
 	// From contains the URIs for the backup we seek to restore.
 	//   - len(From) > 1 implies the backups are locality aware
 	//   - From[0] must be the default locality.
@@ -162,7 +173,15 @@ var _ Statement = &Restore{}
 // Format implements the NodeFormatter interface.
 func (node *Restore) Format(ctx *FmtCtx) {
 	ctx.WriteString("RESTORE ")
-	if node.DescriptorCoverage == RequestedDescriptors {
+	if node.Index != nil {
+		ctx.WriteString("INDEX ")
+		ctx.FormatNode(node.Index)
+		ctx.WriteString(" ")
+	} else if node.Column != nil {
+		ctx.WriteString("COLUMN ")
+		ctx.FormatNode(node.Column)
+		ctx.WriteString(" ")
+	} else if node.DescriptorCoverage == RequestedDescriptors {
 		ctx.FormatNode(&node.Targets)
 		ctx.WriteString(" ")
 	}
@@ -298,6 +317,12 @@ func (o *BackupOptions) Format(ctx *FmtCtx) {
 		ctx.WriteString("updates_cluster_monitoring_metrics = ")
 		ctx.FormatNode(o.UpdatesClusterMonitoringMetrics)
 	}
+
+	if o.Deduplicate != nil {
+		maybeAddSep()
+		ctx.WriteString("deduplicate = ")
+		ctx.FormatNode(o.Deduplicate)
+	}
 	if o.Strict {
 		maybeAddSep()
 		ctx.WriteString("strict storage locality")
@@ -356,6 +381,14 @@ func (o *BackupOptions) CombineWith(other *BackupOptions) error {
 	} else {
 		o.UpdatesClusterMonitoringMetrics = other.UpdatesClusterMonitoringMetrics
 	}
+
+	if o.Deduplicate != nil {
+		if other.Deduplicate != nil {
+			return errors.New("deduplicate option specified multiple times")
+		}
+	} else {
+		o.Deduplicate = other.Deduplicate
+	}
 	if o.Strict {
 		if other.Strict {
 			return errors.New("strict storage locality option specified multiple times")
@@ -376,6 +409,7 @@ func (o BackupOptions) IsDefault() bool {
 		o.ExecutionLocality == options.ExecutionLocality &&
 		o.IncludeAllSecondaryTenants == options.IncludeAllSecondaryTenants &&
 		o.UpdatesClusterMonitoringMetrics == options.UpdatesClusterMonitoringMetrics &&
+		o.Deduplicate == options.Deduplicate &&
 		o.Strict == options.Strict
 }
 
@@ -502,6 +536,18 @@ func (o *RestoreOptions) Format(ctx *FmtCtx) {
 		maybeAddSep()
 		ctx.WriteString("grants")
 	}
+
+	if o.RowFilter != nil {
+		maybeAddSep()
+		ctx.WriteString("row_filter = ")
+		ctx.FormatNode(o.RowFilter)
+	}
+
+	if o.Columns != nil {
+		maybeAddSep()
+		ctx.WriteString("columns = ")
+		o.Columns.Format(ctx)
+	}
 }
 
 // CombineWith merges other backup options into this backup options struct.
@@ -661,6 +707,18 @@ func (o *RestoreOptions) CombineWith(other *RestoreOptions) error {
 		o.Grants = other.Grants
 	}
 
+	if o.RowFilter == nil {
+		o.RowFilter = other.RowFilter
+	} else if other.RowFilter != nil {
+		return errors.New("row_filter specified multiple times")
+	}
+
+	if o.Columns == nil {
+		o.Columns = other.Columns
+	} else if other.Columns != nil {
+		return errors.New("columns specified multiple times")
+	}
+
 	return nil
 }
 
@@ -687,7 +745,9 @@ func (o RestoreOptions) IsDefault() bool {
 		o.ExperimentalOnline == options.ExperimentalOnline &&
 		o.ExperimentalCopy == options.ExperimentalCopy &&
 		o.RemoveRegions == options.RemoveRegions &&
-		o.Grants == options.Grants
+		o.Grants == options.Grants &&
+		o.RowFilter == options.RowFilter &&
+		cmp.Equal(o.Columns, options.Columns)
 }
 
 // BackupTargetList represents a list of targets.
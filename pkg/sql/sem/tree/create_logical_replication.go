@@ -37,6 +37,11 @@ type LogicalReplicationOptions struct {
 	Unidirectional   *DBool
 	BidirectionalURI Expr
 	ParentID         Expr
+	// DefaultConflictPolicy is the conflict policy of tables without an entry
+	// in ConflictPolicies.
+	DefaultConflictPolicy Expr
+	// Mapping of table name to conflict policy
+	ConflictPolicies map[UnresolvedName]Expr
 }
 
 var _ Statement = &CreateLogicalReplicationStream{}
@@ -172,6 +177,30 @@ func (lro *LogicalReplicationOptions) Format(ctx *FmtCtx) {
 		ctx.WriteString("PARENT = ")
 		ctx.FormatNode(lro.ParentID)
 	}
+	if lro.DefaultConflictPolicy != nil {
+		maybeAddSep()
+		ctx.WriteString("CONFLICT_POLICY = ")
+		ctx.FormatNode(lro.DefaultConflictPolicy)
+	}
+	if lro.ConflictPolicies != nil {
+		// In order to make tests deterministic, the ordering of map keys
+		// needs to be the same each time.
+		keys := make([]UnresolvedName, 0, len(lro.ConflictPolicies))
+		for k := range lro.ConflictPolicies {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+
+		for _, k := range keys {
+			maybeAddSep()
+			ctx.WriteString("CONFLICT_POLICY = ")
+			ctx.FormatNode(lro.ConflictPolicies[k])
+			ctx.WriteString(" FOR TABLE ")
+			ctx.FormatNode(&k)
+		}
+	}
 
 }
 
@@ -264,6 +293,25 @@ func (o *LogicalReplicationOptions) CombineWith(other *LogicalReplicationOptions
 		o.ParentID = other.ParentID
 	}
 
+	if o.DefaultConflictPolicy != nil {
+		if other.DefaultConflictPolicy != nil {
+			return errors.New("CONFLICT_POLICY option specified multiple times")
+		}
+	} else {
+		o.DefaultConflictPolicy = other.DefaultConflictPolicy
+	}
+	if other.ConflictPolicies != nil {
+		for tbl := range other.ConflictPolicies {
+			if _, ok := o.ConflictPolicies[tbl]; ok {
+				return errors.Newf("multiple conflict policies specified for table %s", tbl.String())
+			}
+			if o.ConflictPolicies == nil {
+				o.ConflictPolicies = make(map[UnresolvedName]Expr)
+			}
+			o.ConflictPolicies[tbl] = other.ConflictPolicies[tbl]
+		}
+	}
+
 	return nil
 }
 
@@ -280,5 +328,7 @@ func (o LogicalReplicationOptions) IsDefault() bool {
 		o.MetricsLabel == options.MetricsLabel &&
 		o.Unidirectional == options.Unidirectional &&
 		o.BidirectionalURI == options.BidirectionalURI &&
-		o.ParentID == options.ParentID
+		o.ParentID == options.ParentID &&
+		o.DefaultConflictPolicy == options.DefaultConflictPolicy &&
+		o.ConflictPolicies == nil
 }
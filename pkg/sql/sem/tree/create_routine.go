@@ -752,6 +752,7 @@ const (
 	PolicyUsingExpr                 SchemaExprContext = "POLICY USING"
 	PolicyWithCheckExpr             SchemaExprContext = "POLICY WITH CHECK"
 	RegionalByRowRegionDefaultExpr  SchemaExprContext = "REGIONAL BY ROW DEFAULT"
+	RestoreRowFilterExpr            SchemaExprContext = "RESTORE ROW FILTER"
 )
 
 func ComputedColumnExprContext(isVirtual bool) SchemaExprContext {
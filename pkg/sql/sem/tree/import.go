@@ -11,7 +11,10 @@ type Import struct {
 	IntoCols   NameList
 	FileFormat string
 	Files      Exprs
-	Options    KVOptions
+	// Bundle is set for IMPORT <format> <file>, which imports both the schema
+	// and the data of a database dump. Table is nil in that case.
+	Bundle  bool
+	Options KVOptions
 }
 
 var _ Statement = &Import{}
@@ -20,23 +23,29 @@ var _ Statement = &Import{}
 func (node *Import) Format(ctx *FmtCtx) {
 	ctx.WriteString("IMPORT ")
 
-	ctx.WriteString("INTO ")
-	ctx.FormatNode(node.Table)
-	if node.IntoCols != nil {
-		ctx.WriteByte('(')
-		ctx.FormatNode(&node.IntoCols)
-		ctx.WriteString(") ")
+	if node.Bundle {
+		ctx.WriteString(node.FileFormat)
+		ctx.WriteByte(' ')
+		ctx.FormatURIs(node.Files)
 	} else {
-		ctx.WriteString(" ")
-	}
-	ctx.WriteString(node.FileFormat)
-	ctx.WriteString(" DATA ")
-	if len(node.Files) == 1 {
-		ctx.WriteString("(")
-	}
-	ctx.FormatURIs(node.Files)
-	if len(node.Files) == 1 {
-		ctx.WriteString(")")
+		ctx.WriteString("INTO ")
+		ctx.FormatNode(node.Table)
+		if node.IntoCols != nil {
+			ctx.WriteByte('(')
+			ctx.FormatNode(&node.IntoCols)
+			ctx.WriteString(") ")
+		} else {
+			ctx.WriteString(" ")
+		}
+		ctx.WriteString(node.FileFormat)
+		ctx.WriteString(" DATA ")
+		if len(node.Files) == 1 {
+			ctx.WriteString("(")
+		}
+		ctx.FormatURIs(node.Files)
+		if len(node.Files) == 1 {
+			ctx.WriteString(")")
+		}
 	}
 
 	if node.Options != nil {
@@ -2233,7 +2233,11 @@ func (node *Restore) Doc(p *PrettyCfg) pretty.Doc {
 	items := make([]pretty.TableRow, 0, 6)
 
 	items = append(items, p.row("RESTORE", pretty.Nil))
-	if node.DescriptorCoverage == RequestedDescriptors {
+	if node.Index != nil {
+		items = append(items, p.row("INDEX", p.Doc(node.Index)))
+	} else if node.Column != nil {
+		items = append(items, p.row("COLUMN", p.Doc(node.Column)))
+	} else if node.DescriptorCoverage == RequestedDescriptors {
 		items = append(items, node.Targets.docRow(p))
 	}
 	from := p.Doc(&node.From)
@@ -2311,17 +2315,21 @@ func (node *Import) Doc(p *PrettyCfg) pretty.Doc {
 	items := make([]pretty.TableRow, 0, 5)
 	items = append(items, p.row("IMPORT", pretty.Nil))
 
-	into := p.Doc(node.Table)
-	if node.IntoCols != nil {
-		into = p.nestUnder(into, p.bracket("(", p.Doc(&node.IntoCols), ")"))
+	if node.Bundle {
+		items = append(items, p.row(node.FileFormat, p.Doc(&node.Files)))
+	} else {
+		into := p.Doc(node.Table)
+		if node.IntoCols != nil {
+			into = p.nestUnder(into, p.bracket("(", p.Doc(&node.IntoCols), ")"))
+		}
+		items = append(items, p.row("INTO", into))
+		data := p.bracketKeyword(
+			"DATA", " (",
+			p.Doc(&node.Files),
+			")", "",
+		)
+		items = append(items, p.row(node.FileFormat, data))
 	}
-	items = append(items, p.row("INTO", into))
-	data := p.bracketKeyword(
-		"DATA", " (",
-		p.Doc(&node.Files),
-		")", "",
-	)
-	items = append(items, p.row(node.FileFormat, data))
 
 	if node.Options != nil {
 		items = append(items, p.row("WITH", p.Doc(&node.Options)))
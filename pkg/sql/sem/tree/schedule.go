@@ -143,3 +143,39 @@ func (node *ScheduledChangefeed) Format(ctx *FmtCtx) {
 		ctx.FormatNode(&node.ScheduleOptions)
 	}
 }
+
+// ScheduledRestoreDrill represents a scheduled restore drill job, which
+// periodically restores the latest backup in a collection and verifies the
+// restored data.
+type ScheduledRestoreDrill struct {
+	ScheduleLabelSpec LabelSpec
+	From              StringOrPlaceholderOptList
+	Options           KVOptions
+	Recurrence        Expr
+	ScheduleOptions   KVOptions
+}
+
+var _ Statement = &ScheduledRestoreDrill{}
+
+// Format implements the NodeFormatter interface.
+func (node *ScheduledRestoreDrill) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE SCHEDULE")
+
+	ctx.FormatNode(&node.ScheduleLabelSpec)
+	ctx.WriteString(" FOR RESTORE DRILL FROM LATEST IN ")
+	ctx.FormatURIs(node.From)
+
+	if node.Options != nil {
+		ctx.WriteString(" WITH OPTIONS (")
+		ctx.FormatNode(&node.Options)
+		ctx.WriteString(")")
+	}
+
+	ctx.WriteString(" RECURRING ")
+	ctx.FormatNode(node.Recurrence)
+
+	if node.ScheduleOptions != nil {
+		ctx.WriteString(" WITH SCHEDULE OPTIONS ")
+		ctx.FormatNode(&node.ScheduleOptions)
+	}
+}
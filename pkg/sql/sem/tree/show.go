@@ -86,6 +86,8 @@ const (
 	// BackupValidateDetails identifies a SHOW BACKUP VALIDATION
 	// statement.
 	BackupValidateDetails
+	// BackupDiffDetails identifies a SHOW BACKUP DIFF statement.
+	BackupDiffDetails
 )
 
 // ShowBackup represents a SHOW BACKUP statement.
@@ -101,6 +103,22 @@ type ShowBackup struct {
 	Details      ShowBackupDetails
 	Options      ShowBackupOptions
 	TimeRange    ShowBackupTimeFilter
+	// Diff is set for a SHOW BACKUP DIFF statement, in which case Path is the
+	// backup the diff starts from.
+	Diff *ShowBackupDiff
+}
+
+// ShowBackupDiff represents the clauses specific to a SHOW BACKUP DIFF
+// statement.
+type ShowBackupDiff struct {
+	// To is the backup the diff ends at.
+	To Expr
+	// Table, if set, restricts the diff to a single table.
+	Table *TableName
+	// FileFormat and File are set if the diff is written to external storage
+	// rather than returned as rows.
+	FileFormat string
+	File       Expr
 }
 
 // Format implements the NodeFormatter interface.
@@ -121,6 +139,31 @@ func (node *ShowBackup) Format(ctx *FmtCtx) {
 	}
 	ctx.WriteString("SHOW BACKUP ")
 
+	if node.Details == BackupDiffDetails {
+		ctx.WriteString("DIFF FROM ")
+		ctx.FormatNode(node.Path)
+		ctx.WriteString(" TO ")
+		ctx.FormatNode(node.Diff.To)
+		ctx.WriteString(" IN ")
+		ctx.FormatURIs(node.InCollection)
+		if node.Diff.Table != nil {
+			ctx.WriteString(" FOR TABLE ")
+			ctx.FormatNode(node.Diff.Table)
+		}
+		if node.Diff.File != nil {
+			ctx.WriteString(" INTO ")
+			ctx.WriteString(node.Diff.FileFormat)
+			ctx.WriteString(" ")
+			ctx.FormatURI(node.Diff.File)
+		}
+		if !node.Options.IsDefault() {
+			ctx.WriteString(" WITH OPTIONS (")
+			ctx.FormatNode(&node.Options)
+			ctx.WriteString(")")
+		}
+		return
+	}
+
 	switch node.Details {
 	case BackupRangeDetails:
 		ctx.WriteString("RANGES ")
@@ -1529,6 +1572,10 @@ const (
 	// ScheduledChangefeedExecutor is an executor responsible for
 	// the execution of the scheduled changefeeds.
 	ScheduledChangefeedExecutor
+
+	// ScheduledRestoreDrillExecutor is an executor responsible for the
+	// execution of the scheduled restore drills.
+	ScheduledRestoreDrillExecutor
 )
 
 var scheduleExecutorInternalNames = map[ScheduledJobExecutorType]string{
@@ -1538,6 +1585,7 @@ var scheduleExecutorInternalNames = map[ScheduledJobExecutorType]string{
 	ScheduledRowLevelTTLExecutor:        "scheduled-row-level-ttl-executor",
 	ScheduledSchemaTelemetryExecutor:    "scheduled-schema-telemetry-executor",
 	ScheduledChangefeedExecutor:         "scheduled-changefeed-executor",
+	ScheduledRestoreDrillExecutor:       "scheduled-restore-drill-executor",
 }
 
 // InternalName returns an internal executor name.
@@ -1559,6 +1607,8 @@ func (t ScheduledJobExecutorType) UserName() string {
 		return "SCHEMA TELEMETRY"
 	case ScheduledChangefeedExecutor:
 		return "CHANGEFEED"
+	case ScheduledRestoreDrillExecutor:
+		return "RESTORE DRILL"
 	}
 	return "unsupported-executor"
 }
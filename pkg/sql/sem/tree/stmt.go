@@ -1020,6 +1020,21 @@ func (*ScheduledChangefeed) cclOnlyStatement() {}
 
 func (*ScheduledChangefeed) planHookStatement() {}
 
+// StatementReturnType implements the Statement interface.
+func (*ScheduledRestoreDrill) StatementReturnType() StatementReturnType { return Rows }
+
+// StatementType implements the Statement interface.
+func (*ScheduledRestoreDrill) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*ScheduledRestoreDrill) StatementTag() string { return "SCHEDULED RESTORE DRILL" }
+
+func (*ScheduledRestoreDrill) cclOnlyStatement() {}
+
+func (*ScheduledRestoreDrill) planHookStatement() {}
+
+func (*ScheduledRestoreDrill) hiddenFromShowQueries() {}
+
 // StatementReturnType implements the Statement interface.
 func (*CreateDatabase) StatementReturnType() StatementReturnType { return DDL }
 
@@ -2762,6 +2777,7 @@ func (n *RollbackTransaction) String() string                 { return AsString(
 func (n *Savepoint) String() string                           { return AsString(n) }
 func (n *Scatter) String() string                             { return AsString(n) }
 func (n *ScheduledBackup) String() string                     { return AsString(n) }
+func (n *ScheduledRestoreDrill) String() string               { return AsString(n) }
 func (n *Scrub) String() string                               { return AsString(n) }
 func (n *Select) String() string                              { return AsString(n) }
 func (n *SelectClause) String() string                        { return AsString(n) }
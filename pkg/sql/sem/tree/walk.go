@@ -1865,6 +1865,26 @@ func (stmt *Restore) walkStmt(v Visitor) Statement {
 		}
 	}
 
+	if stmt.Options.RowFilter != nil {
+		rowFilter, changed := WalkExpr(v, stmt.Options.RowFilter)
+		if changed {
+			if ret == stmt {
+				ret = stmt.copyNode()
+			}
+			ret.Options.RowFilter = rowFilter
+		}
+	}
+
+	for i, expr := range stmt.Options.Columns {
+		e, changed := WalkExpr(v, expr)
+		if changed {
+			if ret == stmt {
+				ret = stmt.copyNode()
+			}
+			ret.Options.Columns[i] = e
+		}
+	}
+
 	return ret
 }
 
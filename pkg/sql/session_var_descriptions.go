@@ -25,6 +25,7 @@ var sessionVarDescriptions = map[string]string{
 	"bypass_pcr_reader_catalog_aost":                                  "Disables the AOST used by all user queries on the PCR reader catalog.",
 	"bytea_output":                                                    "Controls how to encode byte arrays when converting to string.",
 	"catalog_digest_staleness_check_enabled":                          "Controls whether to use catalog digest information for fast memo staleness checks.",
+	"causality_token":                                                 "Sets the commit timestamp of an earlier write, as returned by SHOW COMMIT TIMESTAMP, which follower reads and bounded staleness reads in the session must observe. Follower reads wait for followers to catch up to the token instead of being served by the leaseholder.",
 	"check_function_bodies":                                           "Controls whether functions are validated during function creation.",
 	"client_encoding":                                                 "Controls the client-side character encoding. Only UTF8 is supported.",
 	"client_min_messages":                                             "Controls which message levels are sent to the client.",
@@ -1198,3 +1198,7 @@ func (m *SessionDataMutator) SetStatsAsOf(val hlc.Timestamp) {
 func (m *SessionDataMutator) SetBufferedWritesImplicitTxnsEnabled(val bool) {
 	m.Data.BufferedWritesImplicitTxnsEnabled = val
 }
+
+func (m *SessionDataMutator) SetCausalityToken(val hlc.Timestamp) {
+	m.Data.CausalityToken = val
+}
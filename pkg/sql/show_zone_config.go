@@ -218,6 +218,18 @@ func zoneConfigToSQL(zs *tree.ZoneSpecifier, zone *zonepb.ZoneConfig) (tree.Datu
 		maybeWriteComma(f)
 		f.Printf("\tnum_voters = %d", *zone.NumVoters)
 	}
+	if zone.NumWitnesses != nil && *zone.NumWitnesses > 0 {
+		maybeWriteComma(f)
+		f.Printf("\tnum_witnesses = %d", *zone.NumWitnesses)
+	}
+	if zone.StorageCompression != nil {
+		maybeWriteComma(f)
+		f.Printf("\tstorage.compression = %s", lexbase.EscapeSQLString(*zone.StorageCompression))
+	}
+	if zone.StorageValueSeparationMinSize != nil {
+		maybeWriteComma(f)
+		f.Printf("\tstorage.value_separation_min_size = %d", *zone.StorageValueSeparationMinSize)
+	}
 	if !zone.InheritedConstraints {
 		maybeWriteComma(f)
 		f.Printf("\tconstraints = %s", lexbase.EscapeSQLString(constraints))
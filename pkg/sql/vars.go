@@ -5000,6 +5000,43 @@ var varGen = map[string]sessionVar{
 		},
 	},
 
+	// CockroachDB extension.
+	`causality_token`: {
+		Description: sessionVarDescriptions["causality_token"],
+		SetWithPlanner: func(ctx context.Context, p *planner, scope setScope, s string) error {
+			var ts hlc.Timestamp
+			if s != "" {
+				var err error
+				if ts, err = hlc.ParseHLC(s); err != nil {
+					return pgerror.Wrap(err, pgcode.InvalidParameterValue, "invalid causality token")
+				}
+				// Make sure that the local clock is not behind the token, which may
+				// have been obtained from another node.
+				if err := p.ExecCfg().Clock.UpdateAndCheckMaxOffset(ctx, hlc.ClockTimestamp(ts)); err != nil {
+					return pgerror.Wrap(err, pgcode.InvalidParameterValue, "invalid causality token")
+				}
+			}
+			return p.applyOnSessionDataMutators(
+				ctx,
+				scope,
+				func(m sessionmutator.SessionDataMutator) error {
+					m.SetCausalityToken(ts)
+					return nil
+				},
+			)
+		},
+		Get: func(evalCtx *extendedEvalContext, _ *kv.Txn) (string, error) {
+			token := evalCtx.SessionData().CausalityToken
+			if token.IsEmpty() {
+				return "", nil
+			}
+			return token.AsOfSystemTime(), nil
+		},
+		GlobalDefault: func(sv *settings.Values) string {
+			return ""
+		},
+	},
+
 	// CockroachDB extension.
 	`buffered_writes_implicit_txns_enabled`: {
 		Description:  sessionVarDescriptions["buffered_writes_implicit_txns_enabled"],
@@ -1132,6 +1132,10 @@ type Engine interface {
 	// could be called repeatedly in multiple threads over a short period of time.
 	RegisterLowDiskSpaceCallback(cb func(info pebble.LowDiskSpaceInfo))
 
+	// SetUserKeySpanPolicyFunc sets the function used to look up the storage
+	// policies, e.g. the compression, of spans of the global keyspace.
+	SetUserKeySpanPolicyFunc(f UserKeySpanPolicyFunc)
+
 	// GetPebbleOptions returns the options used when creating the engine. The
 	// caller must not modify these.
 	GetPebbleOptions() *pebble.Options
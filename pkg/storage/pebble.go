@@ -812,6 +812,123 @@ func spanPolicyFuncFactory(sv *settings.Values) pebble.SpanPolicyFunc {
 	}
 }
 
+// UserKeySpanPolicy is the storage policy for a span of the global keyspace,
+// as configured through zone configs.
+type UserKeySpanPolicy struct {
+	// Compression is the compression requested for the span, or zero to use
+	// the store-wide compression settings (storage.sstable.compression_algorithm).
+	Compression StoreCompressionSetting
+	// ValueSeparationMinSize, if positive, overrides
+	// storage.value_separation.minimum_size for the span.
+	ValueSeparationMinSize int
+}
+
+// MakeUserKeySpanPolicy returns the storage policy configured by the
+// storage.compression and storage.value_separation_min_size fields of a span
+// config.
+func MakeUserKeySpanPolicy(conf *roachpb.SpanConfig) UserKeySpanPolicy {
+	policy := UserKeySpanPolicy{ValueSeparationMinSize: int(conf.StorageValueSeparationMinSize)}
+	switch conf.StorageCompression {
+	case "", roachpb.StorageCompressionDefault:
+	case roachpb.StorageCompressionFast:
+		// The 'fast' zone config value predates the other values, and has always
+		// meant the fastest compression.
+		policy.Compression = StoreCompressionFastest
+	default:
+		for c, name := range storeCompressionSettingToString {
+			if name == conf.StorageCompression {
+				policy.Compression = c
+			}
+		}
+	}
+	return policy
+}
+
+// preferFastCompression returns whether the span's sstables are compressed
+// with the fastest compression rather than the store-wide compression. Pebble's
+// span policies can only choose between the two, so this is how the
+// compression of a span is applied: compression settings which are at least
+// as fast as the fastest compression are honored, and the others fall back to
+// the store-wide compression.
+func (p UserKeySpanPolicy) preferFastCompression() bool {
+	switch p.Compression {
+	case StoreCompressionFastest, StoreCompressionSnappy, StoreCompressionMinLZ:
+		return true
+	default:
+		return false
+	}
+}
+
+// EffectiveCompression returns the compression that the engine applies to the
+// span's sstables.
+func (p UserKeySpanPolicy) EffectiveCompression(sv *settings.Values) StoreCompressionSetting {
+	if p.preferFastCompression() {
+		return StoreCompressionFastest
+	}
+	return CompressionAlgorithmStorage.Get(sv)
+}
+
+// EffectiveValueSeparationMinSize returns the minimum size of the span's values
+// that the engine separates into blob files, or zero if values are not
+// separated.
+func (p UserKeySpanPolicy) EffectiveValueSeparationMinSize(sv *settings.Values) int64 {
+	if !valueSeparationEnabled.Get(sv) {
+		return 0
+	}
+	if p.ValueSeparationMinSize > 0 {
+		return int64(p.ValueSeparationMinSize)
+	}
+	return valueSeparationMinimumSize.Get(sv)
+}
+
+// UserKeySpanPolicyFunc returns the storage policy for the specified global
+// key, along with the span that the policy applies to, which must contain the
+// key.
+type UserKeySpanPolicyFunc func(key roachpb.Key) (roachpb.Span, UserKeySpanPolicy)
+
+// wrapSpanPolicyFunc wraps the provided pebble.SpanPolicyFunc to apply the
+// policies returned by the function registered through
+// SetUserKeySpanPolicyFunc to the global keyspace.
+func (p *Pebble) wrapSpanPolicyFunc(f pebble.SpanPolicyFunc) pebble.SpanPolicyFunc {
+	return func(bounds pebble.UserKeyBounds) (pebble.SpanPolicy, error) {
+		policy, err := f(bounds)
+		if err != nil {
+			return policy, err
+		}
+		fn := p.userKeySpanPolicyFunc.Load()
+		if fn == nil {
+			return policy, nil
+		}
+		key, ok := DecodeEngineKey(bounds.Start)
+		if !ok || key.Key.Compare(keys.LocalMax) < 0 {
+			return policy, nil
+		}
+		span, userPolicy := (*fn)(key.Key)
+		if !span.ContainsKey(key.Key) {
+			return policy, nil
+		}
+		// Narrow the key range of the policy to the span.
+		if start := EncodeMVCCKey(MVCCKey{Key: span.Key}); cockroachkvs.Compare(start, policy.KeyRange.Start) > 0 {
+			policy.KeyRange.Start = start
+		}
+		if len(span.EndKey) > 0 {
+			end := EncodeMVCCKey(MVCCKey{Key: span.EndKey})
+			if policy.KeyRange.End == nil || cockroachkvs.Compare(end, policy.KeyRange.End) < 0 {
+				policy.KeyRange.End = end
+			}
+		}
+		if userPolicy.preferFastCompression() {
+			policy.PreferFastCompression = true
+		}
+		if userPolicy.ValueSeparationMinSize > 0 {
+			policy.ValueStoragePolicy = pebble.ValueStoragePolicyAdjustment{
+				OverrideBlobSeparationMinimumSize: userPolicy.ValueSeparationMinSize,
+			}
+		}
+		return policy, nil
+	}
+}
+
 func shortAttributeExtractorForValues(
 	key []byte, keyPrefixLen int, value []byte,
 ) (pebble.ShortAttribute, error) {
@@ -937,6 +1054,8 @@ type Pebble struct {
 	diskSlowFunc     atomic.Pointer[func(vfs.DiskSlowInfo)]
 	lowDiskSpaceFunc atomic.Pointer[func(pebble.LowDiskSpaceInfo)]
 
+	userKeySpanPolicyFunc atomic.Pointer[UserKeySpanPolicyFunc]
+
 	diskUnhealthyTracker diskUnhealthyTracker
 
 	singleDelLogEvery log.EveryN
@@ -976,6 +1095,13 @@ func (p *Pebble) RegisterLowDiskSpaceCallback(f func(info pebble.LowDiskSpaceInf
 	p.lowDiskSpaceFunc.Store(&f)
 }
 
+// SetUserKeySpanPolicyFunc sets the function used to look up the storage
+// policies of the global keyspace. The policies apply to sstables written by
+// subsequent flushes and compactions.
+func (p *Pebble) SetUserKeySpanPolicyFunc(f UserKeySpanPolicyFunc) {
+	p.userKeySpanPolicyFunc.Store(&f)
+}
+
 // SetStoreID adds the store id to pebble logs.
 func (p *Pebble) SetStoreID(ctx context.Context, storeID int32) error {
 	if p == nil {
@@ -1246,6 +1372,11 @@ func newPebble(ctx context.Context, cfg engineConfig) (p *Pebble, err error) {
 	// Wrap the CompactionConcurrencyRange function to allow overriding the lower
 	// and upper values at runtime through Engine.SetCompactionConcurrency.
 	cfg.opts.CompactionConcurrencyRange = p.cco.Wrap(cfg.opts.CompactionConcurrencyRange)
+	// Wrap the SpanPolicyFunc to allow applying the storage policies of the
+	// global keyspace through Engine.SetUserKeySpanPolicyFunc.
+	if cfg.opts.Experimental.SpanPolicyFunc != nil {
+		cfg.opts.Experimental.SpanPolicyFunc = p.wrapSpanPolicyFunc(cfg.opts.Experimental.SpanPolicyFunc)
+	}
 
 	p.diskUnhealthyTracker = diskUnhealthyTracker{
 		st:       cfg.settings,
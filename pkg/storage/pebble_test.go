@@ -1944,6 +1944,86 @@ func TestPebbleSpanPolicyFunc(t *testing.T) {
 	}
 }
 
+func TestPebbleUserKeySpanPolicy(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	tablePrefix := keys.SystemSQLCodec.TablePrefix(104)
+	tableSpan := roachpb.Span{Key: tablePrefix, EndKey: tablePrefix.PrefixEnd()}
+	var p Pebble
+	f := p.wrapSpanPolicyFunc(spanPolicyFuncFactory(nil /* sv */))
+	policyFor := func(key roachpb.Key) pebble.SpanPolicy {
+		var bounds pebble.UserKeyBounds
+		bounds.Start = EngineKey{Key: key}.Encode()
+		policy, err := f(bounds)
+		require.NoError(t, err)
+		return policy
+	}
+	localEndKey := EncodeMVCCKey(MVCCKey{Key: keys.LocalPrefix.PrefixEnd()})
+	defaultPolicy := pebble.SpanPolicy{KeyRange: pebble.KeyRange{Start: localEndKey}}
+
+	// Without a registered function, the global keyspace has no special policy.
+	require.Equal(t, defaultPolicy, policyFor(tablePrefix))
+
+	p.SetUserKeySpanPolicyFunc(func(key roachpb.Key) (roachpb.Span, UserKeySpanPolicy) {
+		if tableSpan.ContainsKey(key) {
+			return tableSpan, UserKeySpanPolicy{Compression: StoreCompressionSnappy, ValueSeparationMinSize: 1024}
+		}
+		return roachpb.Span{Key: key, EndKey: key.Next()}, UserKeySpanPolicy{}
+	})
+	require.Equal(t, pebble.SpanPolicy{
+		KeyRange: pebble.KeyRange{
+			Start: EncodeMVCCKey(MVCCKey{Key: tableSpan.Key}),
+			End:   EncodeMVCCKey(MVCCKey{Key: tableSpan.EndKey}),
+		},
+		PreferFastCompression: true,
+		ValueStoragePolicy: pebble.ValueStoragePolicyAdjustment{
+			OverrideBlobSeparationMinimumSize: 1024,
+		},
+	}, policyFor(tablePrefix))
+
+	// Local keys are unaffected.
+	require.Equal(t, pebble.ValueStorageLatencyTolerant, policyFor(keys.RaftLogKey(9, 2)).ValueStoragePolicy)
+	otherKey := keys.SystemSQLCodec.TablePrefix(105)
+	require.False(t, policyFor(otherKey).PreferFastCompression)
+}
+
+func TestUserKeySpanPolicyEffectiveSettings(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	CompressionAlgorithmStorage.Override(ctx, &st.SV, StoreCompressionBalanced)
+	valueSeparationEnabled.Override(ctx, &st.SV, true)
+	valueSeparationMinimumSize.Override(ctx, &st.SV, 256)
+
+	for _, tc := range []struct {
+		compression string
+		minSize     int64
+		expected    StoreCompressionSetting
+		expectedMin int64
+	}{
+		{compression: "", expected: StoreCompressionBalanced, expectedMin: 256},
+		{compression: roachpb.StorageCompressionDefault, minSize: 4096, expected: StoreCompressionBalanced, expectedMin: 4096},
+		{compression: roachpb.StorageCompressionFast, expected: StoreCompressionFastest, expectedMin: 256},
+		{compression: roachpb.StorageCompressionMinLZ, expected: StoreCompressionFastest, expectedMin: 256},
+		{compression: roachpb.StorageCompressionZstd, expected: StoreCompressionBalanced, expectedMin: 256},
+		{compression: roachpb.StorageCompressionGood, expected: StoreCompressionBalanced, expectedMin: 256},
+	} {
+		policy := MakeUserKeySpanPolicy(&roachpb.SpanConfig{
+			StorageCompression:            tc.compression,
+			StorageValueSeparationMinSize: tc.minSize,
+		})
+		require.Equal(t, tc.expected, policy.EffectiveCompression(&st.SV), "%q", tc.compression)
+		require.Equal(t, tc.expectedMin, policy.EffectiveValueSeparationMinSize(&st.SV), "%q", tc.compression)
+	}
+
+	valueSeparationEnabled.Override(ctx, &st.SV, false)
+	policy := MakeUserKeySpanPolicy(&roachpb.SpanConfig{StorageValueSeparationMinSize: 4096})
+	require.Zero(t, policy.EffectiveValueSeparationMinSize(&st.SV))
+}
+
 func TestDiskUnhealthyTracker(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -90,8 +90,6 @@ func mapToUnorderedUniqueInt(uniqueInt uint64) uint64 {
 // periodically to avoid the clock ever going backwards (e.g. due to NTP
 // adjustment)?
 func GenerateUniqueInt(instanceID ProcessUniqueID) int64 {
-	const precision = uint64(10 * time.Microsecond)
-
 	// TODO(andrei): For tenants we need to validate that the current time is
 	// within the validity of the sqlliveness session to which the instanceID is
 	// bound. Without this validation, two different nodes might be calling this
@@ -102,7 +100,7 @@ func GenerateUniqueInt(instanceID ProcessUniqueID) int64 {
 	if nowNanos < uniqueIntEpoch {
 		nowNanos = uniqueIntEpoch
 	}
-	timestamp := uint64(nowNanos-uniqueIntEpoch) / precision
+	timestamp := uint64(nowNanos-uniqueIntEpoch) / uniqueIntPrecision
 
 	uniqueIntState.Lock()
 	if timestamp <= uniqueIntState.timestamp {
@@ -121,6 +119,18 @@ var uniqueIntState struct {
 
 var uniqueIntEpoch = time.Date(2015, time.January, 1, 0, 0, 0, 0, time.UTC).UnixNano()
 
+// uniqueIntPrecision is the granularity of the timestamp in a unique int.
+const uniqueIntPrecision = uint64(10 * time.Microsecond)
+
+// UniqueIntTime returns the time at which a unique int generated by
+// GenerateUniqueInt was created, truncated to the 10-microsecond granularity
+// of its timestamp. The result is only approximate if the instance ID used to
+// generate the unique int did not fit in its 15 bits.
+func UniqueIntTime(uniqueInt int64) time.Time {
+	timestamp := (uint64(uniqueInt) & UniqueIntTimestampMask) >> UniqueIntNodeIDBits
+	return timeutil.Unix(0, uniqueIntEpoch+int64(timestamp*uniqueIntPrecision))
+}
+
 // generateUniqueID encapsulates the logic to generate a unique number from
 // a nodeID and timestamp.
 func generateUniqueID(instanceID int32, timestamp uint64) int64 {
@@ -13,9 +13,11 @@ import (
 	"sort"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -189,3 +191,22 @@ func TestMapToUnorderedUniqueInt(t *testing.T) {
 		}
 	})
 }
+
+// TestUniqueIntTime verifies that UniqueIntTime recovers the creation time of
+// a unique int.
+func TestUniqueIntTime(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	epoch := timeutil.Unix(0, uniqueIntEpoch)
+	require.Equal(t, epoch, UniqueIntTime(generateUniqueID(0, 0)))
+	require.Equal(t, epoch.Add(time.Millisecond), UniqueIntTime(generateUniqueID(1<<15-1, 100)))
+
+	before := timeutil.Now().Truncate(10 * time.Microsecond)
+	id := GenerateUniqueInt(7)
+	after := timeutil.Now()
+	ts := UniqueIntTime(id)
+	require.False(t, ts.Before(before), "%s before %s", ts, before)
+	// GenerateUniqueInt may advance the timestamp past the current time to
+	// maintain uniqueness, but never by much in a test.
+	require.False(t, ts.After(after.Add(time.Second)), "%s after %s", ts, after)
+}